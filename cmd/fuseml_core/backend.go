@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/fuseml/fuseml-core/pkg/core/argo"
+	"github.com/fuseml/fuseml-core/pkg/core/local"
+	"github.com/fuseml/fuseml-core/pkg/core/tekton"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+const (
+	tektonBackend          = "tekton"
+	argoBackend            = "argo"
+	localBackend           = "local"
+	localExecutorProcess   = "process"
+	localExecutorShell     = "sh"
+	localExecutorDocker    = "docker"
+	localExecutorPodman    = "podman"
+	defaultLocalWorkDir    = "./runs"
+	defaultWorkflowBackend = tektonBackend
+)
+
+// workflowBackendOptions holds the options used to select and configure the workflow backend
+type workflowBackendOptions struct {
+	// Backend is the name of the workflow backend (tekton, argo or local)
+	Backend string
+	// LocalExecutor is how the local backend executes the workflow steps: as processes (process) or as
+	// containers, using a container runtime (docker or podman)
+	LocalExecutor string
+	// LocalWorkDir is the directory where the local backend keeps the files used by the workflow runs
+	LocalWorkDir string
+}
+
+// newWorkflowBackend initializes the workflow backend selected by the options
+func newWorkflowBackend(logger *log.Logger, options workflowBackendOptions, fuseMLNamespace string) (domain.WorkflowBackend, error) {
+	switch options.Backend {
+	case tektonBackend:
+		return tekton.NewWorkflowBackend(logger, fuseMLNamespace)
+	case argoBackend:
+		return argo.NewWorkflowBackend(logger, fuseMLNamespace)
+	case localBackend:
+		var executor local.StepExecutor
+		switch options.LocalExecutor {
+		case localExecutorProcess:
+			executor = local.NewProcessExecutor(localExecutorShell)
+		case localExecutorDocker, localExecutorPodman:
+			executor = local.NewContainerExecutor(options.LocalExecutor)
+		default:
+			return nil, fmt.Errorf("invalid local executor: %q (valid executors: %s|%s|%s)", options.LocalExecutor,
+				localExecutorProcess, localExecutorDocker, localExecutorPodman)
+		}
+		logger.Printf("using the local workflow backend (executor: %s, work dir: %s)", options.LocalExecutor, options.LocalWorkDir)
+		return local.NewWorkflowBackend(logger, fuseMLNamespace, options.LocalWorkDir, executor)
+	default:
+		return nil, fmt.Errorf("invalid workflow backend: %q (valid backends: %s|%s|%s)", options.Backend, tektonBackend,
+			argoBackend, localBackend)
+	}
+}
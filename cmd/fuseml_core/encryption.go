@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/timshannon/badgerhold/v3"
+
+	"github.com/fuseml/fuseml-core/pkg/core/encryption"
+	"github.com/fuseml/fuseml-core/pkg/core/store/badger"
+)
+
+// encryptionOptions holds the options used to configure the encryption of the stored extension credentials
+type encryptionOptions struct {
+	// KeyFile is the file holding the encryption keys, the FUSEML_ENCRYPTION_KEYS environment variable
+	// is used when not set
+	KeyFile string
+}
+
+// newKeyProvider initializes the provider of the keys used to encrypt the stored extension credentials. It
+// returns nil if no keys are configured, in which case the credentials are stored unencrypted.
+func newKeyProvider(logger *log.Logger, options encryptionOptions) (encryption.KeyProvider, error) {
+	if options.KeyFile != "" {
+		return encryption.NewKeyProviderFromFile(options.KeyFile)
+	}
+	provider, err := encryption.NewKeyProviderFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		logger.Printf("no encryption keys configured, extension credentials are stored unencrypted")
+		return nil, nil
+	}
+	return provider, nil
+}
+
+// rotateEncryptionKey re-encrypts the extension credentials kept in the store with the current encryption key.
+// It opens the store directly, so FuseML must not be running.
+func rotateEncryptionKey(logger *log.Logger, storeOptions badgerhold.Options, options encryptionOptions) error {
+	keys, err := newKeyProvider(logger, options)
+	if err != nil {
+		return err
+	}
+	store, err := badgerhold.Open(storeOptions)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	rotated, err := badger.NewExtensionStore(store, keys).RotateCredentialsKey(context.Background())
+	if err != nil {
+		return err
+	}
+	logger.Printf("re-encrypted %d extension credentials with the current encryption key", rotated)
+	return nil
+}
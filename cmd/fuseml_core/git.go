@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/fuseml/fuseml-core/pkg/core/gitea"
+	"github.com/fuseml/fuseml-core/pkg/core/gitprovider"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+const (
+	giteaProvider      = "gitea"
+	githubProvider     = "github"
+	gitlabProvider     = "gitlab"
+	localProvider      = "local"
+	defaultLocalGitDir = "./git"
+	defaultGitProvider = giteaProvider
+)
+
+// gitProviderOptions holds the options used to select and configure the git provider hosting the codesets
+type gitProviderOptions struct {
+	// Provider is the name of the git provider (gitea, github, gitlab or local)
+	Provider string
+	// LocalDir is the directory where the local provider keeps the codeset repositories
+	LocalDir string
+}
+
+// newGitAdminClient initializes the client for the git provider selected by the options
+func newGitAdminClient(logger *log.Logger, options gitProviderOptions) (domain.GitAdminClient, error) {
+	switch options.Provider {
+	case giteaProvider:
+		return gitea.NewAdminClient(logger)
+	case githubProvider:
+		return gitprovider.NewGitHubAdminClient(logger)
+	case gitlabProvider:
+		return gitprovider.NewGitLabAdminClient(logger)
+	case localProvider:
+		return gitprovider.NewLocalAdminClient(logger, options.LocalDir)
+	default:
+		return nil, fmt.Errorf("invalid git provider: %q (valid providers: %s|%s|%s|%s)", options.Provider, giteaProvider,
+			githubProvider, gitlabProvider, localProvider)
+	}
+}
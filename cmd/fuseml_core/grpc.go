@@ -55,7 +55,7 @@ func handleGRPCServer(ctx context.Context, u *url.URL, endpoints *endpoints, wg
 		runnableServer = runnablesvr.New(endpoints.runnable, nil)
 		codesetServer = codesetsvr.New(endpoints.codeset, nil)
 		projectServer = projectsvr.New(endpoints.project, nil)
-		workflowServer = workflowsvr.New(endpoints.workflow, nil)
+		workflowServer = workflowsvr.New(endpoints.workflow, nil, nil)
 		extensionServer = extensionsvr.New(endpoints.extension, nil)
 	}
 
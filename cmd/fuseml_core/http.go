@@ -21,6 +21,7 @@ import (
 	workflowsvr "github.com/fuseml/fuseml-core/gen/http/workflow/server"
 
 	"github.com/goccy/go-yaml"
+	"github.com/gorilla/websocket"
 	goahttp "goa.design/goa/v3/http"
 	httpmdlwr "goa.design/goa/v3/http/middleware"
 	"goa.design/goa/v3/middleware"
@@ -74,7 +75,7 @@ func handleHTTPServer(ctx context.Context, u *url.URL, endpoints *endpoints, wg
 		runnableServer = runnablesvr.New(endpoints.runnable, mux, dec, enc, eh, nil)
 		codesetServer = codesetsvr.New(endpoints.codeset, mux, dec, enc, eh, nil)
 		projectServer = projectsvr.New(endpoints.project, mux, dec, enc, eh, nil)
-		workflowServer = workflowsvr.New(endpoints.workflow, mux, dec, enc, eh, nil)
+		workflowServer = workflowsvr.New(endpoints.workflow, mux, dec, enc, eh, nil, &websocket.Upgrader{}, nil)
 		extensionServer = extensionsvr.New(endpoints.extension, mux, dec, enc, eh, nil)
 		openapiServer = openapisvr.New(nil, mux, dec, enc, eh, nil, nil, nil, nil, nil)
 		if debug {
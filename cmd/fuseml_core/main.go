@@ -5,14 +5,18 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"math"
 	"net"
 	"net/url"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/timshannon/badgerhold/v3"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/fuseml/fuseml-core/gen/application"
 	"github.com/fuseml/fuseml-core/gen/codeset"
@@ -21,13 +25,22 @@ import (
 	"github.com/fuseml/fuseml-core/gen/runnable"
 	"github.com/fuseml/fuseml-core/gen/version"
 	"github.com/fuseml/fuseml-core/gen/workflow"
+	"github.com/fuseml/fuseml-core/pkg/core"
 	"github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/core/manager"
+	"github.com/fuseml/fuseml-core/pkg/domain"
 	ver "github.com/fuseml/fuseml-core/pkg/version"
 )
 
+// defaultReconcileTimeout is how long the workflow assignments reconciliation is retried at startup before
+// giving up
+const defaultReconcileTimeout = 5 * time.Minute
+
 type coreInit struct {
-	endpoints *endpoints
-	store     *badgerhold.Store
+	endpoints         *endpoints
+	store             *badgerhold.Store
+	workflowManager   domain.WorkflowManager
+	extensionRegistry *manager.ExtensionRegistry
 }
 
 type endpoints struct {
@@ -50,6 +63,18 @@ func main() {
 		grpcPortF = flag.String("grpc-port", "", "gRPC port (overrides host gRPC port specified in service design)")
 		secureF   = flag.Bool("secure", false, "Use secure scheme (https or grpcs)")
 		dbgF      = flag.Bool("debug", false, "Log request and response bodies")
+		backendF  = flag.String("workflow-backend", defaultWorkflowBackend, "Backend used to run workflows (valid values: tekton, argo, local)")
+		executorF = flag.String("local-executor", localExecutorProcess, "How the local backend runs workflow steps (valid values: process, docker, podman)")
+		workDirF  = flag.String("local-work-dir", defaultLocalWorkDir, "Directory where the local backend keeps the workflow runs files")
+		gitF      = flag.String("git-provider", defaultGitProvider, "Git provider hosting the codesets (valid values: gitea, github, gitlab, local)")
+		gitDirF   = flag.String("local-git-dir", defaultLocalGitDir, "Directory where the local git provider keeps the codeset repositories")
+		cleanupF  = flag.Bool("reconcile-cleanup", false, "Remove the stale workflow assignments, orphaned webhooks and orphaned workflow listeners found at startup")
+		rTimeoutF = flag.Duration("reconcile-timeout", defaultReconcileTimeout, "Maximum duration of the retries of the workflow assignments reconciliation at startup, after which fuseml-core exits")
+		keyFileF  = flag.String("encryption-key-file", "", "File holding the keys used to encrypt the stored extension credentials (overrides FUSEML_ENCRYPTION_KEYS)")
+		rotateF   = flag.Bool("rotate-encryption-key", false, "Re-encrypt the stored extension credentials with the current encryption key and exit")
+		healthF   = flag.Duration("extension-health-interval", manager.DefaultHealthCheckInterval, "Interval between the extension endpoint health checks (0 disables the health checks)")
+		hTimeoutF = flag.Duration("extension-health-timeout", manager.DefaultHealthCheckTimeout, "Default maximum duration of an extension endpoint health check")
+		importNF  = flag.String("import-allowed-networks", "", "Comma separated loopback, link-local or private networks (CIDR) from which remote git repositories can be imported into codesets")
 	)
 	flag.Parse()
 
@@ -67,12 +92,52 @@ func main() {
 	storeOptions.Dir = "./data"
 	storeOptions.ValueDir = storeOptions.Dir
 
-	coreInit, err := InitializeCore(logger, storeOptions, config.FuseMLNamespace)
+	backendOptions := workflowBackendOptions{
+		Backend:       *backendF,
+		LocalExecutor: *executorF,
+		LocalWorkDir:  *workDirF,
+	}
+
+	gitOptions := gitProviderOptions{
+		Provider: *gitF,
+		LocalDir: *gitDirF,
+	}
+
+	keyOptions := encryptionOptions{
+		KeyFile: *keyFileF,
+	}
+
+	if *importNF != "" {
+		if err := core.SetImportAllowedNetworks(strings.Split(*importNF, ",")); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to set the allowed import networks: ", err.Error())
+			os.Exit(1)
+		}
+	}
+
+	if *rotateF {
+		if err := rotateEncryptionKey(logger, storeOptions, keyOptions); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to rotate the encryption key: ", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	coreInit, err := InitializeCore(logger, storeOptions, backendOptions, gitOptions, keyOptions, config.FuseMLNamespace)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Failed to initialize fuseml-core: ", err.Error())
 		os.Exit(1)
 	}
 
+	// codeset subscriptions are not persisted, so they are restored from the workflow assignments. Serving
+	// requests without them would leave workflows assigned to codesets that can be deleted, so fuseml-core
+	// does not start until the reconciliation succeeds.
+	reconciliation, err := reconcileAssignments(logger, coreInit.workflowManager, *cleanupF, *rTimeoutF)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to reconcile workflow assignments: ", err.Error())
+		os.Exit(1)
+	}
+	logReconciliation(logger, reconciliation)
+
 	// Create channel used by both the signal handler and server goroutines
 	// to notify the main goroutine when to stop the server.
 	errc := make(chan error)
@@ -88,6 +153,15 @@ func main() {
 	var wg sync.WaitGroup
 	ctx, cancel := context.WithCancel(context.Background())
 
+	// Check the extension endpoints in the background, so that the unhealthy ones are not used by workflows.
+	if *healthF > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			manager.NewExtensionHealthProber(logger, coreInit.extensionRegistry, *healthF, *hTimeoutF).Run(ctx)
+		}()
+	}
+
 	// Start the servers and send errors (if any) to the error channel.
 	switch *hostF {
 	case "dev":
@@ -212,3 +286,43 @@ func main() {
 	wg.Wait()
 	logger.Println("exited")
 }
+
+// reconcileAssignments reconciles the workflow assignments, retrying with an exponential backoff when it
+// fails, e.g. because the git provider or the workflow backend are not reachable yet, until the timeout expires
+func reconcileAssignments(logger *log.Logger, mgr domain.WorkflowManager, cleanup bool,
+	timeout time.Duration) (*domain.WorkflowReconciliation, error) {
+	backoff := wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1, Steps: math.MaxInt32, Cap: time.Minute}
+	deadline := time.Now().Add(timeout)
+	for {
+		reconciliation, err := mgr.ReconcileAssignments(context.Background(), cleanup)
+		if err == nil {
+			return reconciliation, nil
+		}
+		delay := backoff.Step()
+		if time.Now().Add(delay).After(deadline) {
+			return nil, err
+		}
+		logger.Printf("Failed to reconcile workflow assignments, retrying in %s: %v", delay.Round(time.Millisecond), err)
+		time.Sleep(delay)
+	}
+}
+
+// logReconciliation logs the inconsistencies found when reconciling the workflow assignments
+func logReconciliation(logger *log.Logger, r *domain.WorkflowReconciliation) {
+	logger.Printf("Watching %d codesets assigned to workflows", r.Subscriptions)
+	action := "Found"
+	if r.Cleanup {
+		action = "Removed"
+	}
+	for _, a := range r.StaleAssignments {
+		logger.Printf("%s stale assignment of workflow %s to deleted codeset %s/%s", action, a.Workflow,
+			a.Codeset.Project, a.Codeset.Name)
+	}
+	for _, w := range r.OrphanedWebhooks {
+		logger.Printf("%s orphaned webhook %d of codeset %s/%s for workflow listener %s", action, w.Webhook.ID,
+			w.Codeset.Project, w.Codeset.Name, w.Listener)
+	}
+	for _, l := range r.OrphanedListeners {
+		logger.Printf("%s orphaned workflow listener %s", action, l)
+	}
+}
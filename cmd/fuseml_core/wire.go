@@ -17,10 +17,8 @@ import (
 	"github.com/fuseml/fuseml-core/gen/version"
 	"github.com/fuseml/fuseml-core/gen/workflow"
 	"github.com/fuseml/fuseml-core/pkg/core"
-	"github.com/fuseml/fuseml-core/pkg/core/gitea"
 	"github.com/fuseml/fuseml-core/pkg/core/manager"
 	"github.com/fuseml/fuseml-core/pkg/core/store/badger"
-	"github.com/fuseml/fuseml-core/pkg/core/tekton"
 	"github.com/fuseml/fuseml-core/pkg/domain"
 	"github.com/fuseml/fuseml-core/pkg/svc"
 )
@@ -29,14 +27,17 @@ var storeSet = wire.NewSet(
 	badgerhold.Open,
 	badger.NewApplicationStore,
 	wire.Bind(new(domain.ApplicationStore), new(*badger.ApplicationStore)),
-	gitea.NewAdminClient,
-	wire.Bind(new(domain.GitAdminClient), new(*gitea.AdminClient)),
+	newGitAdminClient,
+	badger.NewCodesetMetadataStore,
+	wire.Bind(new(domain.CodesetMetadataStore), new(*badger.CodesetMetadataStore)),
 	core.NewGitCodesetStore,
 	wire.Bind(new(domain.CodesetStore), new(*core.GitCodesetStore)),
+	badger.NewProjectMetadataStore,
+	wire.Bind(new(domain.ProjectMetadataStore), new(*badger.ProjectMetadataStore)),
 	core.NewGitProjectStore,
 	wire.Bind(new(domain.ProjectStore), new(*core.GitProjectStore)),
-	core.NewRunnableStore,
-	wire.Bind(new(domain.RunnableStore), new(*core.RunnableStore)),
+	badger.NewRunnableStore,
+	wire.Bind(new(domain.RunnableStore), new(*badger.RunnableStore)),
 	badger.NewWorkflowStore,
 	wire.Bind(new(domain.WorkflowStore), new(*badger.WorkflowStore)),
 	badger.NewExtensionStore,
@@ -48,11 +49,16 @@ var managerSet = wire.NewSet(
 	wire.Bind(new(domain.WorkflowManager), new(*manager.WorkflowManager)),
 	manager.NewExtensionRegistry,
 	wire.Bind(new(domain.ExtensionRegistry), new(*manager.ExtensionRegistry)),
+	manager.NewProjectManager,
+	wire.Bind(new(domain.ProjectManager), new(*manager.ProjectManager)),
+)
+
+var encryptionSet = wire.NewSet(
+	newKeyProvider,
 )
 
 var backendSet = wire.NewSet(
-	tekton.NewWorkflowBackend,
-	wire.Bind(new(domain.WorkflowBackend), new(*tekton.WorkflowBackend)),
+	newWorkflowBackend,
 )
 
 var endpointsSet = wire.NewSet(
@@ -72,8 +78,10 @@ var endpointsSet = wire.NewSet(
 	extension.NewEndpoints,
 )
 
-func InitializeCore(logger *log.Logger, storeOptions badgerhold.Options, fuseMLNamespace string) (*coreInit, error) {
+func InitializeCore(logger *log.Logger, storeOptions badgerhold.Options, backendOptions workflowBackendOptions,
+	gitOptions gitProviderOptions, keyOptions encryptionOptions, fuseMLNamespace string) (*coreInit, error) {
 	wire.Build(
+		encryptionSet,
 		storeSet,
 		managerSet,
 		backendSet,
@@ -14,10 +14,8 @@ import (
 	"github.com/fuseml/fuseml-core/gen/version"
 	"github.com/fuseml/fuseml-core/gen/workflow"
 	"github.com/fuseml/fuseml-core/pkg/core"
-	"github.com/fuseml/fuseml-core/pkg/core/gitea"
 	"github.com/fuseml/fuseml-core/pkg/core/manager"
 	"github.com/fuseml/fuseml-core/pkg/core/store/badger"
-	"github.com/fuseml/fuseml-core/pkg/core/tekton"
 	"github.com/fuseml/fuseml-core/pkg/domain"
 	"github.com/fuseml/fuseml-core/pkg/svc"
 	"github.com/google/wire"
@@ -27,7 +25,7 @@ import (
 
 // Injectors from wire.go:
 
-func InitializeCore(logger *log.Logger, storeOptions badgerhold.Options, fuseMLNamespace string) (*coreInit, error) {
+func InitializeCore(logger *log.Logger, storeOptions badgerhold.Options, backendOptions workflowBackendOptions, gitOptions gitProviderOptions, keyOptions encryptionOptions, fuseMLNamespace string) (*coreInit, error) {
 	store, err := badgerhold.Open(storeOptions)
 	if err != nil {
 		return nil, err
@@ -35,29 +33,36 @@ func InitializeCore(logger *log.Logger, storeOptions badgerhold.Options, fuseMLN
 	applicationStore := badger.NewApplicationStore(store)
 	service := svc.NewApplicationService(logger, applicationStore)
 	applicationEndpoints := application.NewEndpoints(service)
-	adminClient, err := gitea.NewAdminClient(logger)
+	gitAdminClient, err := newGitAdminClient(logger, gitOptions)
 	if err != nil {
 		return nil, err
 	}
-	gitCodesetStore := core.NewGitCodesetStore(adminClient)
-	codesetService := svc.NewCodesetService(logger, gitCodesetStore)
+	codesetMetadataStore := badger.NewCodesetMetadataStore(store)
+	gitCodesetStore := core.NewGitCodesetStore(gitAdminClient, codesetMetadataStore)
+	keyProvider, err := newKeyProvider(logger, keyOptions)
+	if err != nil {
+		return nil, err
+	}
+	extensionStore := badger.NewExtensionStore(store, keyProvider)
+	extensionRegistry := manager.NewExtensionRegistry(extensionStore)
+	codesetService := svc.NewCodesetService(logger, gitCodesetStore, extensionRegistry)
 	codesetEndpoints := codeset.NewEndpoints(codesetService)
-	gitProjectStore := core.NewGitProjectStore(adminClient)
-	projectService := svc.NewProjectService(logger, gitProjectStore)
+	projectMetadataStore := badger.NewProjectMetadataStore(store)
+	gitProjectStore := core.NewGitProjectStore(gitAdminClient, projectMetadataStore)
+	workflowBackend, err := newWorkflowBackend(logger, backendOptions, fuseMLNamespace)
+	if err != nil {
+		return nil, err
+	}
+	workflowStore := badger.NewWorkflowStore(store)
+	runnableStore := badger.NewRunnableStore(store)
+	workflowManager := manager.NewWorkflowManager(logger, workflowBackend, workflowStore, gitCodesetStore, extensionRegistry, runnableStore, projectMetadataStore)
+	projectManager := manager.NewProjectManager(logger, gitProjectStore, gitCodesetStore, workflowManager, applicationStore)
+	projectService := svc.NewProjectService(logger, gitProjectStore, projectManager)
 	projectEndpoints := project.NewEndpoints(projectService)
-	runnableStore := core.NewRunnableStore()
 	runnableService := svc.NewRunnableService(logger, runnableStore)
 	runnableEndpoints := runnable.NewEndpoints(runnableService)
 	versionService := svc.NewVersionService(logger)
 	versionEndpoints := version.NewEndpoints(versionService)
-	workflowBackend, err := tekton.NewWorkflowBackend(logger, fuseMLNamespace)
-	if err != nil {
-		return nil, err
-	}
-	workflowStore := badger.NewWorkflowStore(store)
-	extensionStore := badger.NewExtensionStore(store)
-	extensionRegistry := manager.NewExtensionRegistry(extensionStore)
-	workflowManager := manager.NewWorkflowManager(workflowBackend, workflowStore, gitCodesetStore, extensionRegistry)
 	workflowService := svc.NewWorkflowService(logger, workflowManager)
 	workflowEndpoints := workflow.NewEndpoints(workflowService)
 	extensionService := svc.NewExtensionRegistryService(logger, extensionRegistry)
@@ -72,18 +77,26 @@ func InitializeCore(logger *log.Logger, storeOptions badgerhold.Options, fuseMLN
 		extension:   extensionEndpoints,
 	}
 	mainCoreInit := &coreInit{
-		endpoints: mainEndpoints,
-		store:     store,
+		endpoints:         mainEndpoints,
+		store:             store,
+		workflowManager:   workflowManager,
+		extensionRegistry: extensionRegistry,
 	}
 	return mainCoreInit, nil
 }
 
 // wire.go:
 
-var storeSet = wire.NewSet(badgerhold.Open, badger.NewApplicationStore, wire.Bind(new(domain.ApplicationStore), new(*badger.ApplicationStore)), gitea.NewAdminClient, wire.Bind(new(domain.GitAdminClient), new(*gitea.AdminClient)), core.NewGitCodesetStore, wire.Bind(new(domain.CodesetStore), new(*core.GitCodesetStore)), core.NewGitProjectStore, wire.Bind(new(domain.ProjectStore), new(*core.GitProjectStore)), core.NewRunnableStore, wire.Bind(new(domain.RunnableStore), new(*core.RunnableStore)), badger.NewWorkflowStore, wire.Bind(new(domain.WorkflowStore), new(*badger.WorkflowStore)), badger.NewExtensionStore, wire.Bind(new(domain.ExtensionStore), new(*badger.ExtensionStore)))
+var storeSet = wire.NewSet(badgerhold.Open, badger.NewApplicationStore, wire.Bind(new(domain.ApplicationStore), new(*badger.ApplicationStore)), newGitAdminClient, badger.NewCodesetMetadataStore, wire.Bind(new(domain.CodesetMetadataStore), new(*badger.CodesetMetadataStore)), core.NewGitCodesetStore, wire.Bind(new(domain.CodesetStore), new(*core.GitCodesetStore)), badger.NewProjectMetadataStore, wire.Bind(new(domain.ProjectMetadataStore), new(*badger.ProjectMetadataStore)), core.NewGitProjectStore, wire.Bind(new(domain.ProjectStore), new(*core.GitProjectStore)), badger.NewRunnableStore, wire.Bind(new(domain.RunnableStore), new(*badger.RunnableStore)), badger.NewWorkflowStore, wire.Bind(new(domain.WorkflowStore), new(*badger.WorkflowStore)), badger.NewExtensionStore, wire.Bind(new(domain.ExtensionStore), new(*badger.ExtensionStore)))
+
+var managerSet = wire.NewSet(manager.NewWorkflowManager, wire.Bind(new(domain.WorkflowManager), new(*manager.WorkflowManager)), manager.NewExtensionRegistry, wire.Bind(new(domain.ExtensionRegistry), new(*manager.ExtensionRegistry)), manager.NewProjectManager, wire.Bind(new(domain.ProjectManager), new(*manager.ProjectManager)))
 
-var managerSet = wire.NewSet(manager.NewWorkflowManager, wire.Bind(new(domain.WorkflowManager), new(*manager.WorkflowManager)), manager.NewExtensionRegistry, wire.Bind(new(domain.ExtensionRegistry), new(*manager.ExtensionRegistry)))
+var encryptionSet = wire.NewSet(
+	newKeyProvider,
+)
 
-var backendSet = wire.NewSet(tekton.NewWorkflowBackend, wire.Bind(new(domain.WorkflowBackend), new(*tekton.WorkflowBackend)))
+var backendSet = wire.NewSet(
+	newWorkflowBackend,
+)
 
 var endpointsSet = wire.NewSet(svc.NewApplicationService, application.NewEndpoints, svc.NewCodesetService, codeset.NewEndpoints, svc.NewProjectService, project.NewEndpoints, svc.NewRunnableService, runnable.NewEndpoints, svc.NewVersionService, version.NewEndpoints, svc.NewWorkflowService, workflow.NewEndpoints, svc.NewExtensionRegistryService, extension.NewEndpoints)
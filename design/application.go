@@ -145,6 +145,12 @@ var Application = Type("Application", func() {
 	Field(7, "k8s_namespace", String, "Kubernetes namespace where the resources are located", func() {
 		Example("fuseml-workloads")
 	})
+	Field(8, "codeset_project", String, "Project of the codeset from which the Workflow created the Application", func() {
+		Example("mlflow-project-01")
+	})
+	Field(9, "codeset_name", String, "Name of the codeset from which the Workflow created the Application", func() {
+		Example("mlflow-app-01")
+	})
 
 	Required("name", "type", "url", "workflow", "k8s_namespace")
 })
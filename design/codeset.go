@@ -108,6 +108,69 @@ var _ = Service("codeset", func() {
 		})
 	})
 
+	Method("import", func() {
+		Description("Register a Codeset and populate it with the contents of a remote git repository or of an uploaded archive.")
+
+		Payload(func() {
+			Field(1, "name", String, "The name of the Codeset", func() {
+				Example("mlflow-app-01")
+				Pattern(`^[A-Za-z0-9_][A-Za-z0-9-_]*$`)
+			})
+			Field(2, "project", String, "The project this Codeset belongs to", func() {
+				Example("mlflow-project-01")
+				Pattern(`^[A-Za-z0-9_][A-Za-z0-9-_]*$`)
+			})
+			Field(3, "description", String, "Codeset description", func() {
+				Example("My first MLFlow application with FuseML")
+				Default("")
+			})
+			Field(4, "labels", ArrayOf(String), "Additional Codeset labels that helps with identifying the type", func() {
+				Elem(func() {
+					Pattern(`^[A-Za-z0-9_][A-Za-z0-9-_]*$`)
+				})
+				Example([]string{"mlflow", "playground"})
+			})
+			Field(5, "url", String, "URL of the remote git repository to import", func() {
+				Example("https://github.com/fuseml/examples.git")
+			})
+			Field(6, "ref", String, "Branch, tag or commit of the remote git repository to import. The default branch is imported if not set", func() {
+				Example("main")
+			})
+			Field(7, "extension_id", String, "Extension holding the credentials used to access the remote git repository", func() {
+				Example("github")
+			})
+			Field(8, "service_id", String, "Extension service holding the credentials used to access the remote git repository. One of its endpoints must be located on the git repository host", func() {
+				Example("git")
+			})
+			Field(9, "credentials_id", String, "Credentials used to access the remote git repository", func() {
+				Example("ci-token")
+			})
+			Field(10, "archive", Bytes, "Archive holding the contents to import, used instead of a remote git repository")
+			Field(11, "archive_format", String, "Format of the archive", func() {
+				Enum("tar.gz", "zip")
+				Default("tar.gz")
+			})
+			Required("name", "project")
+		})
+
+		Error("BadRequest", func() {
+			Description("If the Codeset does not have the required fields, the import source is not valid or the git provider does not support imports, should return 400 Bad Request.")
+		})
+
+		Result(func() {
+			Field(1, "codeset", Codeset, "Imported codeset")
+			Field(2, "username", String, "User for accessing new project")
+			Field(3, "password", String, "Password for new user")
+		})
+
+		// Only exposed over HTTP: "import" is a reserved word in the generated protocol buffers code.
+		HTTP(func() {
+			POST("/codesets/import")
+			Response(StatusCreated)
+			Response("BadRequest", StatusBadRequest)
+		})
+	})
+
 	Method("get", func() {
 		Description("Retrieve a Codeset from FuseML.")
 
@@ -144,6 +207,176 @@ var _ = Service("codeset", func() {
 		})
 	})
 
+	Method("revisions", func() {
+		Description("List the branches, tags and the most recent commits of a Codeset.")
+
+		Payload(func() {
+			Field(1, "project", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "name", String, "Codeset name", func() {
+				Example("mlflow-app-01")
+			})
+			Field(3, "commits", Int, "Maximum number of recent commits to list", func() {
+				Minimum(0)
+				Maximum(50)
+				Default(10)
+			})
+			Required("project", "name")
+		})
+
+		Error("BadRequest", func() {
+			Description("If neither name or project is not given, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no codeset with the given name and project, should return 404 Not Found.")
+		})
+
+		Result(CodesetRevisions)
+
+		HTTP(func() {
+			GET("/codesets/{project}/{name}/revisions")
+			Param("commits")
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("files", func() {
+		Description("List the files and directories found under a path of a Codeset, at a given revision.")
+
+		Payload(func() {
+			Field(1, "project", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "name", String, "Codeset name", func() {
+				Example("mlflow-app-01")
+			})
+			Field(3, "path", String, "Path of the directory to list, relative to the Codeset root", func() {
+				Example("model")
+				Default("")
+			})
+			Field(4, "ref", String, "Branch, tag or commit to list the files from. The default branch is used if not set", func() {
+				Example("main")
+				Default("")
+			})
+			Required("project", "name")
+		})
+
+		Error("BadRequest", func() {
+			Description("If neither name or project is not given, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If the codeset, the path or the revision is not found, should return 404 Not Found.")
+		})
+
+		Result(ArrayOf(CodesetFile))
+
+		HTTP(func() {
+			GET("/codesets/{project}/{name}/files")
+			Param("path")
+			Param("ref")
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("contents", func() {
+		Description("Retrieve the contents of a Codeset file, at a given revision.")
+
+		Payload(func() {
+			Field(1, "project", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "name", String, "Codeset name", func() {
+				Example("mlflow-app-01")
+			})
+			Field(3, "path", String, "Path of the file, relative to the Codeset root", func() {
+				Example("MLproject")
+			})
+			Field(4, "ref", String, "Branch, tag or commit to read the file from. The default branch is used if not set", func() {
+				Example("main")
+				Default("")
+			})
+			Required("project", "name", "path")
+		})
+
+		Error("BadRequest", func() {
+			Description("If the path is not given or it is not a regular file, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If the codeset, the file or the revision is not found, should return 404 Not Found.")
+		})
+
+		Result(CodesetFileContents)
+
+		HTTP(func() {
+			GET("/codesets/{project}/{name}/contents")
+			Param("path")
+			Param("ref")
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("inspect", func() {
+		Description(`Detect the metadata of a Codeset from the contents of its most recent commit and add the labels
+associated with the detected formats to the Codeset labels.`)
+
+		Payload(func() {
+			Field(1, "project", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "name", String, "Codeset name", func() {
+				Example("mlflow-app-01")
+			})
+			Required("project", "name")
+		})
+
+		Error("BadRequest", func() {
+			Description("If the Codeset contents cannot be inspected, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no codeset with the given name and project, should return 404 Not Found.")
+		})
+
+		Result(Codeset)
+
+		HTTP(func() {
+			POST("/codesets/{project}/{name}/inspect")
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
 	Method("delete", func() {
 		Description("Delete a Codeset registered by FuseML.")
 
@@ -196,5 +429,94 @@ var Codeset = Type("Codeset", func() {
 	Field(5, "url", String, "Full URL to the Codeset", func() {
 		Example("http://my-gitea.server/project/repository.git")
 	})
+	Field(6, "metadata", CodesetMetadata, "Metadata detected from the Codeset contents. Not set for empty Codesets")
 	Required("name", "project")
 })
+
+// CodesetMetadata describes the contents of a Codeset, as detected from the files found at the top of its most
+// recent commit
+var CodesetMetadata = Type("CodesetMetadata", func() {
+	Field(1, "commit", String, "The ID of the commit the metadata was detected from", func() {
+		Example("2bbd8ea8e1a2c1e8d2d0b0bc3a3f7a3de6b40ad4")
+	})
+	Field(2, "type", ArrayOf(String), "The type of information contained in the Codeset", func() {
+		Example([]string{"code", "configuration"})
+	})
+	Field(3, "format", ArrayOf(String), "The format(s) used for the Codeset contents", func() {
+		Example([]string{"MLProject", "conda"})
+	})
+	Field(4, "requirements", MapOf(String, String),
+		"Software packages found in the Codeset dependency files, with their optional version requirements", func() {
+			Example(map[string]string{
+				"mlflow":       "",
+				"scikit-learn": "0.22.3",
+			})
+		})
+	Field(5, "files", ArrayOf(String), "The files the metadata was detected from", func() {
+		Example([]string{"MLproject", "conda.yaml"})
+	})
+	Field(6, "labels", ArrayOf(String), "The labels associated with the detected formats", func() {
+		Example([]string{"mlflow", "conda"})
+	})
+	Required("commit", "type", "format", "requirements", "files", "labels")
+})
+
+// CodesetRevisions describes the revisions of a Codeset
+var CodesetRevisions = Type("CodesetRevisions", func() {
+	Field(1, "branches", ArrayOf(CodesetRef), "Codeset branches")
+	Field(2, "tags", ArrayOf(CodesetRef), "Codeset tags")
+	Field(3, "commits", ArrayOf(CodesetCommit), "Most recent Codeset commits, starting with the newest one")
+})
+
+// CodesetRef describes a Codeset branch or tag
+var CodesetRef = Type("CodesetRef", func() {
+	Field(1, "name", String, "The name of the branch or tag", func() {
+		Example("main")
+	})
+	Field(2, "commit", String, "The ID of the commit the branch or tag points to", func() {
+		Example("8e1d4ba6dd1cb4a6bbb0ee9bd1da7a5d2f5b8c8e")
+	})
+	Required("name", "commit")
+})
+
+// CodesetCommit describes a Codeset commit
+var CodesetCommit = Type("CodesetCommit", func() {
+	Field(1, "id", String, "The commit ID", func() {
+		Example("8e1d4ba6dd1cb4a6bbb0ee9bd1da7a5d2f5b8c8e")
+	})
+	Field(2, "author", String, "The name of the commit author", func() {
+		Example("John Doe")
+	})
+	Field(3, "message", String, "The commit message", func() {
+		Example("Tune the model hyperparameters")
+	})
+	Field(4, "time", String, "The time the commit was created", func() {
+		Format(FormatDateTime)
+		Example("2021-04-09T06:17:25Z")
+	})
+	Required("id")
+})
+
+// CodesetFile describes an entry in the file tree of a Codeset
+var CodesetFile = Type("CodesetFile", func() {
+	Field(1, "path", String, "The path of the entry, relative to the Codeset root", func() {
+		Example("model/train.py")
+	})
+	Field(2, "type", String, "The type of the entry", func() {
+		Enum("file", "dir", "symlink", "submodule")
+		Example("file")
+	})
+	Field(3, "size", Int64, "The size of the file, in bytes. Only set for regular files", func() {
+		Example(1024)
+	})
+	Required("path", "type")
+})
+
+// CodesetFileContents describes the contents of a Codeset file
+var CodesetFileContents = Type("CodesetFileContents", func() {
+	Field(1, "path", String, "The path of the file, relative to the Codeset root", func() {
+		Example("MLproject")
+	})
+	Field(2, "content", Bytes, "The contents of the file")
+	Required("path", "content")
+})
@@ -790,6 +790,41 @@ this service), expressed as set of key-value entries`, func() {
 	Field(tag, "endpoints", ArrayOf(ExtensionEndpoint), "List of endpoints through which this service can be accessed")
 	tag++
 	Field(tag, "credentials", ArrayOf(ExtensionCredentials), "List of credentials required to access this service")
+	tag++
+	Field(tag, "health_check", ExtensionServiceHealthCheck,
+		`Health check run periodically against the service endpoints. If not set, the endpoints are not checked
+and are always considered available`)
+})
+
+// Extension service health check descriptor
+var ExtensionServiceHealthCheck = Type("ExtensionServiceHealthCheck", func() {
+	tag := 1
+	Field(tag, "type", String,
+		`Health check type - http/tcp. An http health check sends a GET request to the endpoint, while a tcp
+health check only opens a connection to the endpoint host and port`, func() {
+			Enum("http", "tcp")
+			Default("http")
+			Example("http")
+			Example("tcp")
+		})
+	tag++
+	Field(tag, "path", String, "Path appended to the endpoint URL for http health checks", func() {
+		MaxLength(200)
+		Example("/health")
+	})
+	tag++
+	Field(tag, "expected_status", Int,
+		`HTTP status expected from the endpoint for http health checks. If not set, any 2xx or 3xx status
+is accepted`, func() {
+			Minimum(100)
+			Maximum(599)
+			Example(200)
+		})
+	tag++
+	Field(tag, "timeout_seconds", Int, "Maximum duration of a health check, in seconds", func() {
+		Minimum(1)
+		Example(5)
+	})
 })
 
 // Extension service status descriptor
@@ -855,8 +890,26 @@ this endpoint), expressed as set of key-value entries`, func() {
 
 // Extension endpoint status descriptor
 var ExtensionEndpointStatus = Type("ExtensionEndpointStatus", func() {
-	// empty for now, but can be extended to include future operational status attrs
-	// e.g. whether the endpoint is reachable or not, or number of consumers
+	tag := 1
+	Field(tag, "health", String,
+		`Endpoint health, as reported by the last health check. Not set if the service has no health check
+configured or if the endpoint was not checked yet`, func() {
+			Enum("healthy", "unhealthy")
+			Example("healthy")
+		})
+	tag++
+	Field(tag, "message", String, "The reason why the last health check failed", func() {
+		Example("unexpected HTTP status 503")
+	})
+	tag++
+	Field(tag, "latency_ms", Int64, "The duration of the last health check, in milliseconds", func() {
+		Example(12)
+	})
+	tag++
+	Field(tag, "checked", String, "The time when the endpoint was last checked", func() {
+		Format(FormatDateTime)
+		Example(time.Now().Format(time.RFC3339))
+	})
 })
 
 // Extension credentials descriptor
@@ -996,4 +1049,9 @@ categories of AI/ML services (e.g. model store, feature store, distributed train
 			Example("serving-platform")
 		})
 	tag++
+	Field(tag, "exclude_unhealthy", Boolean,
+		`Exclude the endpoints reported as unhealthy by their last health check. Extension services left
+without endpoints are excluded as well`, func() {
+			Default(false)
+		})
 })
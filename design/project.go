@@ -95,28 +95,155 @@ var _ = Service("project", func() {
 		})
 	})
 
+	Method("update", func() {
+		Description("Update the description, labels, default workflow or quota of a Project. Fields that are not set are left unchanged.")
+
+		Payload(func() {
+			Field(1, "name", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "description", String, "Project description", func() {
+				Example("Set of MLFlow applications")
+			})
+			Field(3, "labels", ArrayOf(String), "Labels associated with the Project, replacing the existing ones", func() {
+				Example([]string{"team-a"})
+			})
+			Field(4, "defaultWorkflow", String, "The workflow suggested for running the Project codesets", func() {
+				Example("mlflow-sklearn-e2e")
+			})
+			Field(5, "quota", ProjectQuota, "Resources that the workflow runs of the Project are allowed to use. An empty quota removes the limits")
+			Required("name")
+		})
+
+		Error("BadRequest", func() {
+			Description("If the project does not exist, the quota is not valid or the workflow backend cannot enforce it, should return 400 Bad Request.")
+		})
+
+		Result(Project)
+
+		HTTP(func() {
+			PUT("/projects/{name}")
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+		})
+	})
+
 	Method("delete", func() {
-		Description("Delete a FuseML Project.")
+		Description("Delete a FuseML Project, optionally with its codesets and the resources depending on them.")
 
 		Payload(func() {
 			Field(1, "name", String, "Project name", func() {
 				Example("mlflow-project-01")
 			})
+			Field(2, "cascade", Boolean, "Unassign the workflows from the Project codesets and delete the codesets and the applications created from them", func() {
+				Default(false)
+			})
+			Field(3, "dryRun", Boolean, "Only list the resources that would be removed, without deleting anything", func() {
+				Default(false)
+			})
 			Required("name")
 		})
 
 		Error("BadRequest", func() {
 			Description("If name is not given, should return 400 Bad Request.")
 		})
+		Error("Conflict", func() {
+			Description("If the project still contains codesets and the deletion is not cascading, should return 409 Conflict.")
+		})
+
+		Result(ProjectDeletion)
 
 		HTTP(func() {
 			DELETE("/projects/{name}")
+			Param("cascade")
+			Param("dryRun")
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("Conflict", StatusConflict)
+		})
+		GRPC(func() {
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("Conflict", CodeFailedPrecondition)
+		})
+	})
+
+	Method("addMember", func() {
+		Description("Add a user to a Project, or change the role of an existing Project member.")
+
+		Payload(func() {
+			Field(1, "name", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "user", String, "User name", func() {
+				Example("jane")
+				Pattern(`^[A-Za-z0-9_][A-Za-z0-9-_.]*$`)
+			})
+			Field(3, "email", String, "User email", func() {
+				Example("jane@example.com")
+				Default("")
+			})
+			Field(4, "role", String, "The role of the user in the Project", func() {
+				Enum("owner", "developer", "viewer")
+				Default("developer")
+			})
+			Required("name", "user")
+		})
+
+		Error("BadRequest", func() {
+			Description("If the member cannot be added to the project, should return 400 Bad Request.")
+		})
+
+		Result(ProjectMemberCredentials)
+
+		HTTP(func() {
+			PUT("/projects/{name}/members/{user}")
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+		})
+	})
+
+	Method("removeMember", func() {
+		Description("Remove a user from a Project.")
+
+		Payload(func() {
+			Field(1, "name", String, "Project name", func() {
+				Example("mlflow-project-01")
+			})
+			Field(2, "user", String, "User name", func() {
+				Example("jane")
+			})
+			Required("name", "user")
+		})
+
+		Error("BadRequest", func() {
+			Description("If the member cannot be removed from the project, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If the user is not a member of the project, should return 404 Not Found.")
+		})
+
+		HTTP(func() {
+			DELETE("/projects/{name}/members/{user}")
 			Response(StatusNoContent)
 			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
 		})
+
 		GRPC(func() {
 			Response(CodeOK)
 			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
 		})
 	})
 })
@@ -132,9 +259,66 @@ var Project = Type("Project", func() {
 		Example("Set of MLFlow applications")
 		Default("")
 	})
+	Field(4, "labels", ArrayOf(String), "Labels associated with the Project", func() {
+		Example([]string{"team-a"})
+	})
+	Field(5, "defaultWorkflow", String, "The workflow suggested for running the Project codesets", func() {
+		Example("mlflow-sklearn-e2e")
+	})
+	Field(6, "quota", ProjectQuota, "Resources that the workflow runs of the Project are allowed to use")
 	Required("name")
 })
 
+// ProjectQuota describes the resources that the workflow runs of a Project are allowed to use
+var ProjectQuota = Type("ProjectQuota", func() {
+	Field(1, "maxConcurrentRuns", Int, "Maximum number of workflow runs of the Project executing at the same time. Not limited if 0", func() {
+		Minimum(0)
+		Example(2)
+	})
+	Field(2, "cpu", String, "Maximum CPU requested by the running workflow steps of the Project, as a Kubernetes quantity. Not limited if empty", func() {
+		Example("4")
+	})
+	Field(3, "memory", String, "Maximum memory requested by the running workflow steps of the Project, as a Kubernetes quantity. Not limited if empty", func() {
+		Example("8Gi")
+	})
+})
+
+// ProjectDeletion lists the resources removed when deleting a Project
+var ProjectDeletion = Type("ProjectDeletion", func() {
+	Field(1, "project", String, "The name of the deleted Project", func() {
+		Example("mlflow-project-01")
+	})
+	Field(2, "dryRun", Boolean, "True if nothing was actually deleted", func() {
+		Example(false)
+	})
+	Field(3, "assignments", ArrayOf(ProjectDeletionAssignment), "Workflow assignments removed from the Project codesets")
+	Field(4, "codesets", ArrayOf(String), "Codesets of the Project", func() {
+		Example([]string{"mlflow-app-01"})
+	})
+	Field(5, "applications", ArrayOf(String), "Applications created by workflow runs of the Project codesets", func() {
+		Example([]string{"mlflow-project-01-mlflow-app-01"})
+	})
+	Field(6, "users", ArrayOf(String), "Project members that do not belong to any other Project", func() {
+		Example([]string{"fuseml-mlflow-project-01"})
+	})
+	Field(7, "unknownApplications", ArrayOf(String), "Applications that do not record the codeset Project they "+
+		"were created from, which are kept", func() {
+		Example([]string{"mlflow-app-02"})
+	})
+	Required("project", "dryRun", "assignments", "codesets", "applications", "users", "unknownApplications")
+})
+
+// ProjectDeletionAssignment describes a workflow assignment removed when deleting a Project
+var ProjectDeletionAssignment = Type("ProjectDeletionAssignment", func() {
+	Field(1, "workflow", String, "The name of the assigned Workflow", func() {
+		Example("mlflow-sklearn-e2e")
+	})
+	Field(2, "codeset", String, "The name of the Codeset", func() {
+		Example("mlflow-app-01")
+	})
+	Required("workflow", "codeset")
+})
+
 // User describes the user assigned to the project
 var User = Type("User", func() {
 	Field(1, "name", String, "User name", func() {
@@ -143,5 +327,20 @@ var User = Type("User", func() {
 	Field(2, "email", String, "User email", func() {
 		Example("fuseml-mlflow-project-01@fuseml.org")
 	})
+	Field(3, "role", String, "The role of the user in the Project", func() {
+		Enum("owner", "developer", "viewer")
+		Example("owner")
+	})
 	Required("name", "email")
 })
+
+// ProjectMemberCredentials describes the git credentials of a Project member
+var ProjectMemberCredentials = Type("ProjectMemberCredentials", func() {
+	Field(1, "username", String, "User name used to access the Project codesets", func() {
+		Example("jane")
+	})
+	Field(2, "password", String, "Password generated for a new user. Not set if the user already exists or if the git provider does not use FuseML managed credentials", func() {
+		Example("Jf8cmD2Fk0qXbLhw")
+	})
+	Required("username")
+})
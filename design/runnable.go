@@ -92,25 +92,86 @@ var _ = Service("runnable", func() {
 	})
 
 	Method("get", func() {
-		Description("Retrieve a Runnable from FuseML.")
+		Description("Retrieve a Runnable from FuseML. The latest version is returned unless a version is specified.")
 
 		Payload(func() {
 			Field(1, "id", String, "Unique runnable identifier", func() {
 				Pattern(identifierPattern)
 				Example("model-trainer-1234")
 			})
+			Field(2, "version", Int, "Runnable version", func() {
+				Minimum(1)
+				Example(2)
+			})
 			Required("id")
 		})
 
 		Error("NotFound", func() {
-			Description("If there is no runnable with the given ID, should return 404 Not Found.")
+			Description("If there is no runnable with the given ID or version, should return 404 Not Found.")
 		})
 
 		Result(Runnable)
 
 		HTTP(func() {
 			GET("/runnables/{id}")
+			Param("version")
+			Response(StatusOK)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("update", func() {
+		Description("Update a runnable registered in FuseML, creating a new version of the runnable. The previous versions are kept and can still be retrieved.")
+
+		Payload(Runnable, "Runnable descriptor")
+
+		Error("BadRequest", func() {
+			Description("If the runnable does not have the required fields, should return 400 Bad Request.")
+		})
+
+		Error("NotFound", func() {
+			Description("If there is no runnable with the given ID, should return 404 Not Found.")
+		})
+
+		Result(Runnable, "Return the new version of the runnable.")
+
+		HTTP(func() {
+			PUT("/runnables/{id}")
 			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("delete", func() {
+		Description("Delete a runnable and all its versions from FuseML.")
+
+		Payload(func() {
+			Field(1, "id", String, "Unique runnable identifier", func() {
+				Pattern(identifierPattern)
+				Example("model-trainer-1234")
+			})
+			Required("id")
+		})
+
+		Error("NotFound", func() {
+			Description("If there is no runnable with the given ID, should return 404 Not Found.")
+		})
+
+		HTTP(func() {
+			DELETE("/runnables/{id}")
+			Response(StatusNoContent)
 			Response("NotFound", StatusNotFound)
 		})
 
@@ -188,6 +249,15 @@ var Runnable = Type("Runnable", func() {
 			})
 		})
 	tag++
+	Field(tag, "version", Int, "The runnable version, incremented every time the runnable is updated", func() {
+		Example(1)
+	})
+	tag++
+	Field(tag, "updated", String, "The time when this version of the runnable was created", func() {
+		Format(FormatDateTime)
+		Example("2021-04-09T06:17:25Z")
+	})
+	tag++
 	Required("id", "container")
 })
 
@@ -56,6 +56,33 @@ var _ = Service("workflow", func() {
 		})
 	})
 
+	Method("validate", func() {
+		Description("Validate a Workflow definition, optionally rendering the objects created by the workflow backend for it.")
+		Payload(func() {
+			Extend(Workflow)
+			Field(20, "render", Boolean,
+				"Render the objects created by the workflow backend for the workflow, when the workflow is valid", func() {
+					Default(false)
+				})
+		})
+		Error("BadRequest", func() {
+			Description("If the workflow does not have the required fields, should return 400 Bad Request.")
+		})
+		Result(WorkflowValidation)
+
+		HTTP(func() {
+			POST("/workflows/validate")
+			Param("render")
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+		})
+	})
+
 	Method("get", func() {
 		Description("Get a Workflow.")
 
@@ -128,11 +155,23 @@ var _ = Service("workflow", func() {
 			Field(3, "codesetName", String, "Codeset to assign the workflow to", func() {
 				Example("mlflow-project-001")
 			})
+			Field(4, "branches", ArrayOf(String),
+				"Only trigger the workflow for changes pushed to the branches matching these glob patterns", func() {
+					Example([]string{"main", "release/*"})
+				})
+			Field(5, "tags", ArrayOf(String),
+				"Only trigger the workflow for the tags matching these glob patterns", func() {
+					Example([]string{"v*"})
+				})
+			Field(6, "paths", ArrayOf(String),
+				"Only trigger the workflow for changes to the files matching these glob patterns", func() {
+					Example([]string{"src/**", "MLproject"})
+				})
 			Required("name", "codesetProject", "codesetName")
 		})
 
 		Error("BadRequest", func() {
-			Description("If no workflowName or codeset is given, should return 400 Bad Request.")
+			Description("If no workflowName or codeset is given, the filters are not valid, or no extension credentials are available for the codeset project, should return 400 Bad Request.")
 		})
 		Error("NotFound", func() {
 			Description("If there is no workflow with the given name or codeset, should return 404 Not Found.")
@@ -143,6 +182,9 @@ var _ = Service("workflow", func() {
 			Param("name")
 			Param("codesetProject")
 			Param("codesetName")
+			Param("branches")
+			Param("tags")
+			Param("paths")
 			Response(StatusCreated)
 			Response("BadRequest", StatusBadRequest)
 			Response("NotFound", StatusNotFound)
@@ -217,6 +259,28 @@ var _ = Service("workflow", func() {
 		})
 	})
 
+	Method("reconcileAssignments", func() {
+		Description("Find the Workflow assignments to deleted Codesets, the Codeset webhooks and the Workflow listeners left behind by removed assignments, and optionally remove them.")
+
+		Payload(func() {
+			Field(1, "cleanup", Boolean, "Remove the inconsistencies found", func() {
+				Default(false)
+			})
+		})
+
+		Result(WorkflowReconciliation)
+
+		HTTP(func() {
+			POST("/workflows/assignments/reconcile")
+			Param("cleanup")
+			Response(StatusOK)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+		})
+	})
+
 	Method("listRuns", func() {
 		Description("List Workflow runs.")
 
@@ -234,6 +298,9 @@ var _ = Service("workflow", func() {
 				Example("Succeeded")
 
 			})
+			Field(5, "codesetVersion", String, "Version (git revision) of the codeset to list runs from", func() {
+				Example("main")
+			})
 		})
 
 		Error("NotFound", func() {
@@ -248,15 +315,265 @@ var _ = Service("workflow", func() {
 			Param("codesetProject")
 			Param("codesetName")
 			Param("status")
+			Param("codesetVersion")
+			Response(StatusOK)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("NotFound", CodeNotFound)
+		})
+
+	})
+
+	Method("getRun", func() {
+		Description("Get a Workflow run, including the status of each of its steps.")
+
+		Payload(func() {
+			Field(1, "name", String, "Name of the workflow run", func() {
+				Example("fuseml-workspace-mlflow-project-001-x7k2p")
+			})
+			Required("name")
+		})
+
+		Error("BadRequest", func() {
+			Description("If name is not given, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no workflow run with the given name, should return 404 Not Found.")
+		})
+
+		Result(WorkflowRun)
+
+		HTTP(func() {
+			GET("/workflows/runs/{name}")
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
+	})
+
+	Method("logs", func() {
+		Description("Stream the logs from the steps of a Workflow run.")
+
+		Payload(func() {
+			Field(1, "name", String, "Name of the workflow run", func() {
+				Example("fuseml-workspace-mlflow-project-001-x7k2p")
+			})
+			Field(2, "step", String, "Name of the step to stream the logs from, all steps are included when not set", func() {
+				Example("trainer")
+			})
+			Field(3, "follow", Boolean, "Keep streaming the logs until the steps complete", func() {
+				Default(false)
+			})
+			Required("name")
+		})
+
+		Error("BadRequest", func() {
+			Description("If name is not given, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no workflow run or step with the given name, should return 404 Not Found.")
+		})
+
+		StreamingResult(WorkflowRunLog)
+
+		HTTP(func() {
+			GET("/workflows/runs/{name}/logs")
+			Param("step")
+			Param("follow")
 			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
 			Response("NotFound", StatusNotFound)
 		})
 
 		GRPC(func() {
 			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
 			Response("NotFound", CodeNotFound)
 		})
+	})
+
+	Method("run", func() {
+		Description("Start a new Workflow run for a Codeset.")
+
+		Payload(func() {
+			Field(1, "name", String, "Name of the Workflow to run", func() {
+				Example("mlflow-sklearn-e2e")
+			})
+			Field(2, "codesetProject", String, "Project that hosts the codeset to run the workflow with", func() {
+				Example("workspace")
+			})
+			Field(3, "codesetName", String, "Codeset to run the workflow with", func() {
+				Example("mlflow-project-001")
+			})
+			Field(4, "codesetRevision", String, "Codeset revision (branch, tag or commit) to run the workflow with", func() {
+				Example("v1.0")
+			})
+			Field(5, "inputs", MapOf(String, String), "Values overriding the defaults of the workflow inputs", func() {
+				Example(map[string]string{"predictor": "kfserving"})
+			})
+			Field(6, "user", String, "User triggering the run, whose user scoped extension credentials are used by the workflow steps", func() {
+				Example("alice")
+			})
+			Required("name", "codesetProject", "codesetName")
+		})
 
+		Error("BadRequest", func() {
+			Description("If no workflowName or codeset is given, an unknown input is set, no extension credentials are available for the codeset project and user, or the workflow backend cannot enforce the project quota, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no workflow with the given name or codeset, should return 404 Not Found.")
+		})
+		Error("QuotaExceeded", func() {
+			Description("If the codeset project already has as many active runs as allowed by its quota, should return 429 Too Many Requests.")
+		})
+
+		Result(func() {
+			Field(1, "name", String, "Name of the created workflow run", func() {
+				Example("fuseml-workspace-mlflow-project-001-x7k2p")
+			})
+			Required("name")
+		})
+
+		HTTP(func() {
+			POST("/workflows/runs")
+			Response(StatusCreated)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+			Response("QuotaExceeded", StatusTooManyRequests)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+			Response("QuotaExceeded", CodeResourceExhausted)
+		})
+	})
+
+	Method("cancelRun", func() {
+		Description("Cancel a running Workflow run.")
+
+		Payload(func() {
+			Field(1, "name", String, "Name of the workflow run to cancel", func() {
+				Example("fuseml-workspace-mlflow-project-001-x7k2p")
+			})
+			Required("name")
+		})
+
+		Error("BadRequest", func() {
+			Description("If name is not given, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no workflow run with the given name, should return 404 Not Found.")
+		})
+		Error("Conflict", func() {
+			Description("If the workflow run has already completed, should return 409 Conflict.")
+		})
+
+		HTTP(func() {
+			POST("/workflows/runs/{name}/cancel")
+			Response(StatusOK)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+			Response("Conflict", StatusConflict)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+			Response("Conflict", CodeFailedPrecondition)
+		})
+	})
+
+	Method("retryRun", func() {
+		Description("Retry a completed Workflow run, creating a new run with the same inputs and codeset revision, bound to " +
+			"the extensions and credentials currently available to the workflow.")
+
+		Payload(func() {
+			Field(1, "name", String, "Name of the workflow run to retry", func() {
+				Example("fuseml-workspace-mlflow-project-001-x7k2p")
+			})
+			Required("name")
+		})
+
+		Error("BadRequest", func() {
+			Description("If name is not given, the workflow backend cannot enforce the project quota, or the workflow " +
+				"extensions can no longer be bound to credentials usable by the codeset project, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no workflow run with the given name, should return 404 Not Found.")
+		})
+		Error("Conflict", func() {
+			Description("If the workflow run has not completed yet, should return 409 Conflict.")
+		})
+		Error("QuotaExceeded", func() {
+			Description("If the codeset project already has as many active runs as allowed by its quota, should return 429 Too Many Requests.")
+		})
+
+		Result(func() {
+			Field(1, "name", String, "Name of the created workflow run", func() {
+				Example("fuseml-workspace-mlflow-project-001-b9q4m")
+			})
+			Required("name")
+		})
+
+		HTTP(func() {
+			POST("/workflows/runs/{name}/retry")
+			Response(StatusCreated)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+			Response("Conflict", StatusConflict)
+			Response("QuotaExceeded", StatusTooManyRequests)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+			Response("Conflict", CodeFailedPrecondition)
+			Response("QuotaExceeded", CodeResourceExhausted)
+		})
+	})
+
+	Method("deleteRun", func() {
+		Description("Delete a Workflow run.")
+
+		Payload(func() {
+			Field(1, "name", String, "Name of the workflow run to delete", func() {
+				Example("fuseml-workspace-mlflow-project-001-x7k2p")
+			})
+			Required("name")
+		})
+
+		Error("BadRequest", func() {
+			Description("If name is not given, should return 400 Bad Request.")
+		})
+		Error("NotFound", func() {
+			Description("If there is no workflow run with the given name, should return 404 Not Found.")
+		})
+
+		HTTP(func() {
+			DELETE("/workflows/runs/{name}")
+			Response(StatusNoContent)
+			Response("BadRequest", StatusBadRequest)
+			Response("NotFound", StatusNotFound)
+		})
+
+		GRPC(func() {
+			Response(CodeOK)
+			Response("BadRequest", CodeInvalidArgument)
+			Response("NotFound", CodeNotFound)
+		})
 	})
 })
 
@@ -279,6 +596,31 @@ var Workflow = Type("Workflow", func() {
 	Required("name", "steps")
 })
 
+// WorkflowValidation describes the result of validating a FuseML workflow
+var WorkflowValidation = Type("WorkflowValidation", func() {
+	Field(1, "valid", Boolean, "Whether the workflow is valid", func() {
+		Example(false)
+	})
+	Field(2, "errors", ArrayOf(WorkflowValidationError), "Problems found in the workflow definition")
+	Field(3, "rendered", String, "Objects created by the workflow backend for the workflow, in YAML format", func() {
+		Example("apiVersion: tekton.dev/v1beta1\nkind: Pipeline\n...")
+	})
+
+	Required("valid")
+})
+
+// WorkflowValidationError describes a problem found in a FuseML workflow definition
+var WorkflowValidationError = Type("WorkflowValidationError", func() {
+	Field(1, "field", String, "Path to the workflow field with the problem", func() {
+		Example("steps[1].inputs[0].value")
+	})
+	Field(2, "message", String, "Description of the problem", func() {
+		Example(`reference "steps.trainer.outputs.model" to a step that does not exist`)
+	})
+
+	Required("field", "message")
+})
+
 // WorkflowInput defines the input for a FuseML workflow
 var WorkflowInput = Type("WorkflowInput", func() {
 	Field(1, "name", String, "Name of the input", func() {
@@ -320,7 +662,7 @@ var WorkflowStep = Type("WorkflowStep", func() {
 	Field(1, "name", String, "The name of the step", func() {
 		Example("predictor")
 	})
-	Field(2, "image", String, "The image used to execute the step", func() {
+	Field(2, "image", String, "The image used to execute the step, required unless the step references a runnable", func() {
 		Example("ghcr.io/fuseml/kserve-predictor:1.0")
 	})
 	Field(3, "inputs", ArrayOf(WorkflowStepInput), "List of inputs for the step")
@@ -328,8 +670,34 @@ var WorkflowStep = Type("WorkflowStep", func() {
 	Field(5, "extensions", ArrayOf(WorkflowStepExtension), "List of extension requirements")
 	Field(6, "env", ArrayOf(WorkflowStepEnv), "List of environment variables available for the container running the step")
 	Field(7, "resources", WorkflowStepResources, "Set the resources requests and limits for the step.")
+	Field(8, "runnable", WorkflowStepRunnable,
+		`Registered runnable executed by the step. The step image, entrypoint, arguments and environment
+variables are taken from the runnable, and the step inputs and outputs are validated against it`)
+	Field(9, "entrypoint", String, "The entrypoint of the container running the step", func() {
+		Example("/usr/local/bin/predictor")
+	})
+	Field(10, "args", ArrayOf(String), "Arguments passed to the entrypoint of the container running the step", func() {
+		Example([]string{"--verbose"})
+	})
 
-	Required("name", "image")
+	Required("name")
+})
+
+// WorkflowStepRunnable references the runnable executed by a FuseML workflow step
+var WorkflowStepRunnable = Type("WorkflowStepRunnable", func() {
+	Field(1, "id", String, "Reference the runnable explicitly by ID", func() {
+		Example("kserve-predictor")
+	})
+	Field(2, "version", Int, "Use a specific version of the runnable referenced by ID, instead of the latest one", func() {
+		Minimum(1)
+		Example(1)
+	})
+	Field(3, "kind", String, "Find the runnable by kind, when it is not referenced by ID", func() {
+		Example("predictor")
+	})
+	Field(4, "labels", MapOf(String, String), "Find the runnable by labels, when it is not referenced by ID", func() {
+		Example(map[string]string{"serving": "kserve"})
+	})
 })
 
 // WorkflowStepInput defines the input for a FuseML workflow step
@@ -529,10 +897,80 @@ var WorkflowRun = Type("WorkflowRun", func() {
 		Example("Succeeded")
 	})
 	Field(8, "URL", String, "Dashboard URL to the workflow run")
+	Field(9, "steps", ArrayOf(WorkflowRunStep), "Status of the steps executed by the workflow run")
+	Field(10, "extensions", ArrayOf(WorkflowRunExtension), "Extensions the workflow steps were bound to when the run was created")
 
 	Required("name", "workflowRef", "startTime", "completionTime", "status")
 })
 
+// WorkflowRunExtension describes the extension a step extension requirement was bound to for a WorkflowRun
+var WorkflowRunExtension = Type("WorkflowRunExtension", func() {
+	Field(1, "step", String, "Name of the step", func() {
+		Example("trainer")
+	})
+	Field(2, "name", String, "Name of the step extension requirement", func() {
+		Example("mlflow-tracking")
+	})
+	Field(3, "extensionId", String, "ID of the extension the requirement was bound to", func() {
+		Example("mlflow-0001")
+	})
+	Field(4, "serviceId", String, "ID of the extension service", func() {
+		Example("mlflow-tracking")
+	})
+	Field(5, "endpointUrl", String, "URL of the extension service endpoint", func() {
+		Example("http://mlflow")
+	})
+	Field(6, "credentialsId", String, "ID of the extension service credentials, if any", func() {
+		Example("mlflow-creds")
+	})
+
+	Required("step", "name", "extensionId", "serviceId", "endpointUrl")
+})
+
+// WorkflowRunStep describes the status of a step executed by a WorkflowRun
+var WorkflowRunStep = Type("WorkflowRunStep", func() {
+	Field(1, "name", String, "Name of the step", func() {
+		Example("trainer")
+	})
+	Field(2, "status", String, "The current status of the step", func() {
+		Example("Succeeded")
+	})
+	Field(3, "startTime", String, "The time when the step started", func() {
+		Format(FormatDateTime)
+		Example("2021-04-09T06:17:25Z")
+	})
+	Field(4, "completionTime", String, "The time when the step completed", func() {
+		Format(FormatDateTime)
+		Example("2021-04-09T06:19:05Z")
+	})
+	Field(5, "retries", Int, "Number of times the step has been retried", func() {
+		Example(0)
+	})
+	Field(6, "image", String, "The image used to execute the step", func() {
+		Example("ghcr.io/fuseml/mlflow@sha256:2ba4c7a8dd1b3dd3ed9a1e1c9fb1d2a3b8d7c6a0e5bbc9e37f0de6b3a8c7e1f2")
+	})
+	Field(7, "results", MapOf(String, String), "Results produced by the step", func() {
+		Example(map[string]string{"mlflow-model-url": "s3://mlflow-artifacts/1/a7c5e3/artifacts/model"})
+	})
+
+	Required("name", "status", "retries")
+})
+
+// WorkflowRunLog describes a line logged by a step of a WorkflowRun
+var WorkflowRunLog = Type("WorkflowRunLog", func() {
+	Field(1, "step", String, "Name of the step", func() {
+		Example("trainer")
+	})
+	Field(2, "container", String, "Name of the step container that logged the line", func() {
+		Example("step-run")
+	})
+	Field(3, "line", String, "The logged line", func() {
+		Example("Successfully registered model 'mlflow-app-01'.")
+	})
+
+	Required("step", "container", "line")
+})
+
 // WorkflowRunInput describes a input from a WorkflowRun including its value
 var WorkflowRunInput = Type("WorkflowRunInput", func() {
 	Field(1, "input", WorkflowInput, "The workflow input")
@@ -554,10 +992,78 @@ var WorkflowAssignment = Type("WorkflowAssignment", func() {
 	Field(1, "workflow", String, "Workflow assigned to the codeset")
 	Field(2, "codesets", ArrayOf(Codeset), "Codesets assigned to the workflow")
 	Field(3, "status", WorkflowAssignmentStatus, "The status of the assignment")
+	Field(4, "codesetFilters", MapOf(String, CodesetAssignmentFilter),
+		"Filters applied to the codeset events that trigger the workflow, indexed by codeset (PROJECT/NAME)")
 
 	Required("workflow", "codesets")
 })
 
+// WorkflowReconciliation describes the inconsistencies found between the workflow assignments, the codeset
+// webhooks and the workflow listeners
+var WorkflowReconciliation = Type("WorkflowReconciliation", func() {
+	Field(1, "cleanup", Boolean, "Whether the inconsistencies found were removed")
+	Field(2, "subscriptions", Int, "Number of assigned codesets whose deletion is watched", func() {
+		Example(3)
+	})
+	Field(3, "staleAssignments", ArrayOf(StaleWorkflowAssignment), "Workflow assignments to codesets that no longer exist")
+	Field(4, "orphanedWebhooks", ArrayOf(OrphanedCodesetWebhook), "Codeset webhooks sending events to a workflow listener without a matching assignment")
+	Field(5, "orphanedListeners", ArrayOf(String), "Listeners of workflows that are not assigned to any codeset", func() {
+		Example([]string{"mlflow-sklearn-e2e"})
+	})
+
+	Required("cleanup", "subscriptions", "staleAssignments", "orphanedWebhooks", "orphanedListeners")
+})
+
+// StaleWorkflowAssignment describes a workflow assignment to a codeset that no longer exists
+var StaleWorkflowAssignment = Type("StaleWorkflowAssignment", func() {
+	Field(1, "workflow", String, "Name of the assigned workflow", func() {
+		Example("mlflow-sklearn-e2e")
+	})
+	Field(2, "codesetProject", String, "Project of the deleted codeset", func() {
+		Example("workspace")
+	})
+	Field(3, "codesetName", String, "Name of the deleted codeset", func() {
+		Example("mlflow-project-001")
+	})
+
+	Required("workflow", "codesetProject", "codesetName")
+})
+
+// OrphanedCodesetWebhook describes a codeset webhook sending events to a workflow listener without a matching
+// workflow assignment
+var OrphanedCodesetWebhook = Type("OrphanedCodesetWebhook", func() {
+	Field(1, "codesetProject", String, "Project of the codeset", func() {
+		Example("workspace")
+	})
+	Field(2, "codesetName", String, "Name of the codeset", func() {
+		Example("mlflow-project-001")
+	})
+	Field(3, "id", Int64, "ID of the webhook", func() {
+		Example(12)
+	})
+	Field(4, "url", String, "URL the webhook sends the events to", func() {
+		Example("http://el-mlflow-sklearn-e2e.fuseml-workloads.svc.cluster.local:8080")
+	})
+	Field(5, "listener", String, "Name of the workflow listener receiving the events", func() {
+		Example("mlflow-sklearn-e2e")
+	})
+
+	Required("codesetProject", "codesetName", "id", "url", "listener")
+})
+
+// CodesetAssignmentFilter restricts the codeset changes that trigger an assigned workflow
+var CodesetAssignmentFilter = Type("CodesetAssignmentFilter", func() {
+	Field(1, "branches", ArrayOf(String), "Glob patterns matching the branches that trigger the workflow", func() {
+		Example([]string{"main", "release/*"})
+	})
+	Field(2, "tags", ArrayOf(String), "Glob patterns matching the tags that trigger the workflow", func() {
+		Example([]string{"v*"})
+	})
+	Field(3, "paths", ArrayOf(String), "Glob patterns matching the changed files that trigger the workflow", func() {
+		Example([]string{"src/**", "MLproject"})
+	})
+})
+
 // WorkflowAssignmentStatus describes the status of the resource responsible for the
 // assignment between a workflow and codesets
 var WorkflowAssignmentStatus = Type("WorkflowAssignmentStatus", func() {
@@ -5,12 +5,15 @@ go 1.16
 require (
 	code.gitea.io/sdk/gitea v0.14.0
 	github.com/Masterminds/semver v1.5.0
+	github.com/dgraph-io/badger/v3 v3.2011.1
 	github.com/fatih/color v1.10.0
 	github.com/ghodss/yaml v1.0.0
+	github.com/go-git/go-billy/v5 v5.3.1
 	github.com/go-git/go-git/v5 v5.4.2
 	github.com/goccy/go-yaml v1.8.9
 	github.com/google/go-cmp v0.5.5
 	github.com/google/wire v0.5.0
+	github.com/gorilla/websocket v1.4.2
 	github.com/grpc-ecosystem/go-grpc-middleware v1.2.2
 	github.com/jedib0t/go-pretty/v6 v6.2.2
 	github.com/jinzhu/copier v0.2.9
@@ -29,6 +32,7 @@ require (
 	google.golang.org/grpc v1.38.0
 	google.golang.org/protobuf v1.27.1
 	k8s.io/api v0.20.7
+	k8s.io/apiextensions-apiserver v0.19.7
 	k8s.io/apimachinery v0.20.7
 	k8s.io/client-go v0.20.7
 	knative.dev/pkg v0.0.0-20210510175900-4564797bf3b7
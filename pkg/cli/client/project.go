@@ -37,15 +37,24 @@ func (pc *ProjectClient) Create(name, desc string) (*project.Project, error) {
 	return response.(*project.Project), nil
 }
 
-// Delete a Project and its assignments.
-func (pc *ProjectClient) Delete(name string) (err error) {
-	request, err := projectc.BuildDeletePayload(name)
+// Update the description, labels, default workflow or quota of a Project.
+func (pc *ProjectClient) Update(request *project.UpdatePayload) (*project.Project, error) {
+	response, err := pc.c.Update()(context.Background(), request)
 	if err != nil {
-		return
+		return nil, err
 	}
 
-	_, err = pc.c.Delete()(context.Background(), request)
-	return
+	return response.(*project.Project), nil
+}
+
+// Delete a Project, optionally with its codesets and the resources depending on them.
+func (pc *ProjectClient) Delete(name string, cascade, dryRun bool) (*project.ProjectDeletion, error) {
+	response, err := pc.c.Delete()(context.Background(), &project.DeletePayload{Name: name, Cascade: cascade, DryRun: dryRun})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*project.ProjectDeletion), nil
 }
 
 // Get a Project.
@@ -71,3 +80,25 @@ func (pc *ProjectClient) List() ([]*project.Project, error) {
 	}
 	return response.([]*project.Project), nil
 }
+
+// AddMember adds a user to a Project, or changes the role of an existing Project member.
+func (pc *ProjectClient) AddMember(name, user, email, role string) (*project.ProjectMemberCredentials, error) {
+	request := &project.AddMemberPayload{Name: name, User: user, Email: email, Role: role}
+	response, err := pc.c.AddMember()(context.Background(), request)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*project.ProjectMemberCredentials), nil
+}
+
+// RemoveMember removes a user from a Project.
+func (pc *ProjectClient) RemoveMember(name, user string) (err error) {
+	request, err := projectc.BuildRemoveMemberPayload(name, user)
+	if err != nil {
+		return
+	}
+
+	_, err = pc.c.RemoveMember()(context.Background(), request)
+	return
+}
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/gorilla/websocket"
 	goahttp "goa.design/goa/v3/http"
 
 	workflowc "github.com/fuseml/fuseml-core/gen/http/workflow/client"
@@ -20,13 +21,13 @@ type WorkflowClient struct {
 // NewWorkflowClient initializes a WorkflowClient
 func NewWorkflowClient(scheme string, host string, doer goahttp.Doer, encoder func(*http.Request) goahttp.Encoder,
 	decoder func(*http.Response) goahttp.Decoder, verbose bool) *WorkflowClient {
-	wc := &WorkflowClient{workflowc.NewClient(scheme, host, doer, encoder, decoder, verbose)}
+	wc := &WorkflowClient{workflowc.NewClient(scheme, host, doer, encoder, decoder, verbose, websocket.DefaultDialer, nil)}
 	return wc
 }
 
-// Assign a Workflow to a Codeset.
-func (wc *WorkflowClient) Assign(name, codesetProject, codesetName string) (err error) {
-	request, err := workflowc.BuildAssignPayload(name, codesetProject, codesetName)
+// Assign a Workflow to a Codeset, optionally filtering the codeset changes that trigger the workflow.
+func (wc *WorkflowClient) Assign(name, codesetProject, codesetName string, branches, tags, paths []string) (err error) {
+	request, err := workflowc.BuildAssignPayload(name, codesetProject, codesetName, branches, tags, paths)
 	if err != nil {
 		return
 	}
@@ -50,6 +51,21 @@ func (wc *WorkflowClient) Create(workflowDef string) (*workflow.Workflow, error)
 	return response.(*workflow.Workflow), nil
 }
 
+// Validate a Workflow definition, optionally rendering the objects created by the workflow backend for it.
+func (wc *WorkflowClient) Validate(workflowDef string, render bool) (*workflow.WorkflowValidation, error) {
+	request, err := workflowc.BuildValidatePayload(workflowDef, render)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := wc.c.Validate()(context.Background(), request)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*workflow.WorkflowValidation), nil
+}
+
 // Delete a Workflow and its assignments.
 func (wc *WorkflowClient) Delete(name string) (err error) {
 	request, err := workflowc.BuildDeletePayload(name)
@@ -76,6 +92,21 @@ func (wc *WorkflowClient) Get(name string) (*workflow.Workflow, error) {
 	return response.(*workflow.Workflow), nil
 }
 
+// GetRun gets a Workflow run.
+func (wc *WorkflowClient) GetRun(name string) (*workflow.WorkflowRun, error) {
+	request, err := workflowc.BuildGetRunPayload(name)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := wc.c.GetRun()(context.Background(), request)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*workflow.WorkflowRun), nil
+}
+
 // List Workflows.
 func (wc *WorkflowClient) List(name string) ([]*workflow.Workflow, error) {
 	request, err := workflowc.BuildListPayload(name)
@@ -106,9 +137,20 @@ func (wc *WorkflowClient) ListAssignments(name string) ([]*workflow.WorkflowAssi
 	return response.([]*workflow.WorkflowAssignment), nil
 }
 
+// ReconcileAssignments finds, and optionally removes, the stale Workflow assignments, orphaned webhooks and
+// orphaned Workflow listeners.
+func (wc *WorkflowClient) ReconcileAssignments(cleanup bool) (*workflow.WorkflowReconciliation, error) {
+	response, err := wc.c.ReconcileAssignments()(context.Background(), &workflow.ReconcileAssignmentsPayload{Cleanup: cleanup})
+	if err != nil {
+		return nil, err
+	}
+
+	return response.(*workflow.WorkflowReconciliation), nil
+}
+
 // ListRuns lists Workflow runs.
-func (wc *WorkflowClient) ListRuns(name, codesetProject, codesetName, status string) ([]*workflow.WorkflowRun, error) {
-	request, err := workflowc.BuildListRunsPayload(name, codesetProject, codesetName, status)
+func (wc *WorkflowClient) ListRuns(name, codesetProject, codesetName, codesetVersion, status string) ([]*workflow.WorkflowRun, error) {
+	request, err := workflowc.BuildListRunsPayload(name, codesetProject, codesetName, status, codesetVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -124,6 +166,91 @@ func (wc *WorkflowClient) ListRuns(name, codesetProject, codesetName, status str
 	return wrs, nil
 }
 
+// Logs streams the logs from the steps of a Workflow run, calling the handler for every logged line.
+func (wc *WorkflowClient) Logs(name, step string, follow bool, handler func(*workflow.WorkflowRunLog)) error {
+	request, err := workflowc.BuildLogsPayload(name, step, follow)
+	if err != nil {
+		return err
+	}
+
+	response, err := wc.c.Logs()(context.Background(), request)
+	if err != nil {
+		return err
+	}
+
+	stream := response.(*workflowc.LogsClientStream)
+	for {
+		log, err := stream.Recv()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return nil
+			}
+			return err
+		}
+		handler(log)
+	}
+}
+
+// Run starts a new Workflow run for a Codeset.
+func (wc *WorkflowClient) Run(name, codesetProject, codesetName, codesetRevision, user string, inputs map[string]string) (string, error) {
+	request := &workflow.RunPayload{
+		Name:           name,
+		CodesetProject: codesetProject,
+		CodesetName:    codesetName,
+		Inputs:         inputs,
+	}
+	if codesetRevision != "" {
+		request.CodesetRevision = &codesetRevision
+	}
+	if user != "" {
+		request.User = &user
+	}
+
+	response, err := wc.c.Run()(context.Background(), request)
+	if err != nil {
+		return "", err
+	}
+
+	return response.(*workflow.RunResult).Name, nil
+}
+
+// CancelRun cancels a running Workflow run.
+func (wc *WorkflowClient) CancelRun(name string) (err error) {
+	request, err := workflowc.BuildCancelRunPayload(name)
+	if err != nil {
+		return
+	}
+
+	_, err = wc.c.CancelRun()(context.Background(), request)
+	return
+}
+
+// RetryRun creates a new Workflow run with the same inputs and codeset revision as a completed one.
+func (wc *WorkflowClient) RetryRun(name string) (string, error) {
+	request, err := workflowc.BuildRetryRunPayload(name)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := wc.c.RetryRun()(context.Background(), request)
+	if err != nil {
+		return "", err
+	}
+
+	return response.(*workflow.RetryRunResult).Name, nil
+}
+
+// DeleteRun deletes a Workflow run.
+func (wc *WorkflowClient) DeleteRun(name string) (err error) {
+	request, err := workflowc.BuildDeleteRunPayload(name)
+	if err != nil {
+		return
+	}
+
+	_, err = wc.c.DeleteRun()(context.Background(), request)
+	return
+}
+
 // Unassign removes an assignment between a workflow and a codeset.
 func (wc *WorkflowClient) Unassign(name, codesetProject, codesetName string) (err error) {
 	request, err := workflowc.BuildUnassignPayload(name, codesetProject, codesetName)
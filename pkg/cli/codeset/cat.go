@@ -0,0 +1,74 @@
+package codeset
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/gen/codeset"
+	codesetc "github.com/fuseml/fuseml-core/gen/http/codeset/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+// CatOptions holds the options for 'codeset cat' sub command
+type CatOptions struct {
+	client.Clients
+	global  *common.GlobalOptions
+	Name    string
+	Project string
+	Ref     string
+	Path    string
+}
+
+// NewCatOptions initializes a CatOptions struct
+func NewCatOptions(o *common.GlobalOptions) *CatOptions {
+	return &CatOptions{global: o}
+}
+
+// NewSubCmdCodesetCat creates and returns the cobra command for the `codeset cat` CLI command
+func NewSubCmdCodesetCat(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewCatOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   "cat {-n|--name NAME} {-p|--project PROJECT} [--ref REF] PATH",
+		Short: "Print codeset files.",
+		Long: `Print the contents of the file found at PATH in a codeset, without cloning it. The default branch is used
+if a branch, tag or commit is not supplied with --ref`,
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Path = args[0]
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "codeset name")
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "the project to which the codeset belongs")
+	cmd.Flags().StringVar(&o.Ref, "ref", "", "branch, tag or commit to read the file from")
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("project")
+	return cmd
+}
+
+func (o *CatOptions) validate() error {
+	return nil
+}
+
+func (o *CatOptions) run() error {
+	request, err := codesetc.BuildContentsPayload(o.Project, o.Name, o.Path, o.Ref)
+	if err != nil {
+		return err
+	}
+
+	response, err := o.CodesetClient.Contents()(context.Background(), request)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(response.(*codeset.CodesetFileContents).Content)
+	return err
+}
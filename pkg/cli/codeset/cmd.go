@@ -14,10 +14,14 @@ func NewCmdCodeset(c *common.GlobalOptions) *cobra.Command {
 	}
 
 	cmd.AddCommand(NewSubCmdCodesetRegister(c))
+	cmd.AddCommand(NewSubCmdCodesetImport(c))
 	cmd.AddCommand(NewSubCmdCodesetGet(c))
 	cmd.AddCommand(NewSubCmdCodesetList(c))
 	cmd.AddCommand(NewSubCmdCodesetDelete(c))
 	cmd.AddCommand(NewSubCmdCodesetSet(c))
+	cmd.AddCommand(NewSubCmdCodesetLs(c))
+	cmd.AddCommand(NewSubCmdCodesetCat(c))
+	cmd.AddCommand(NewSubCmdCodesetInspect(c))
 
 	return cmd
 }
@@ -4,20 +4,95 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
 
+	"github.com/jonboulle/clockwork"
+	"github.com/spf13/cobra"
+	"github.com/tektoncd/cli/pkg/formatted"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fuseml/fuseml-core/gen/codeset"
 	codesetc "github.com/fuseml/fuseml-core/gen/http/codeset/client"
+	"github.com/fuseml/fuseml-core/gen/workflow"
 	"github.com/fuseml/fuseml-core/pkg/cli/client"
 	"github.com/fuseml/fuseml-core/pkg/cli/common"
-	"github.com/spf13/cobra"
+	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
+const getTemplate = `{{decorate "bold" "Name"}}:	{{ .Codeset.Name }}
+{{decorate "bold" "Project"}}:	{{ .Codeset.Project }}
+{{- if ne .Codeset.Description "" }}
+{{decorate "bold" "Description"}}:	{{ .Codeset.Description }}
+{{- end }}
+{{- if gt (len .Codeset.Labels) 0 }}
+{{decorate "bold" "Labels"}}:	{{ join .Codeset.Labels ", " }}
+{{- end }}
+{{decorate "bold" "URL"}}:	{{ deref .Codeset.URL }}
+{{- with .Codeset.Metadata }}
+
+{{decorate "underline bold" "Metadata\n"}}
+ {{decorate "bold" "Commit"}}:	{{ shortCommit .Commit }}
+{{- if gt (len .Type) 0 }}
+ {{decorate "bold" "Type"}}:	{{ join .Type ", " }}
+{{- end }}
+{{- if gt (len .Format) 0 }}
+ {{decorate "bold" "Format"}}:	{{ join .Format ", " }}
+{{- end }}
+{{- if gt (len .Files) 0 }}
+ {{decorate "bold" "Files"}}:	{{ join .Files ", " }}
+{{- end }}
+{{- if gt (len .Requirements) 0 }}
+ {{decorate "bold" "Requirements"}}:	{{ formatRequirements .Requirements }}
+{{- end }}
+{{- end }}
+
+{{decorate "underline bold" "Branches\n"}}
+{{- if eq (len .Revisions.Branches) 0 }}
+ No branches
+{{- else }}
+ NAME	COMMIT	WORKFLOW RUNS
+{{- range $b := .Revisions.Branches }}
+ {{decorate "bullet" $b.Name }}	{{ shortCommit $b.Commit }}	{{ formatRuns (index $.Runs $b.Name) }}
+{{- end }}
+{{- end }}
+
+{{decorate "underline bold" "Tags\n"}}
+{{- if eq (len .Revisions.Tags) 0 }}
+ No tags
+{{- else }}
+ NAME	COMMIT	WORKFLOW RUNS
+{{- range $t := .Revisions.Tags }}
+ {{decorate "bullet" $t.Name }}	{{ shortCommit $t.Commit }}	{{ formatRuns (index $.Runs $t.Name) }}
+{{- end }}
+{{- end }}
+
+{{decorate "underline bold" "Recent Commits\n"}}
+{{- if eq (len .Revisions.Commits) 0 }}
+ No commits
+{{- else }}
+ COMMIT	AUTHOR	AGE	MESSAGE	WORKFLOW RUNS
+{{- range $c := .Revisions.Commits }}
+ {{decorate "bullet" (shortCommit $c.ID) }}	{{ deref $c.Author }}	{{ formatAge $c.Time }}	{{ formatMessage $c.Message }}	{{ formatRuns (index $.Runs $c.ID) }}
+{{- end }}
+{{- end }}
+`
+
+// codesetInputType is the type of the workflow inputs holding the codeset processed by a workflow run
+const codesetInputType = "codeset"
+
 // GetOptions holds the options for 'codeset get' sub command
 type GetOptions struct {
 	client.Clients
-	global  *common.GlobalOptions
-	format  *common.FormattingOptions
-	Name    string
-	Project string
+	global    *common.GlobalOptions
+	format    *common.FormattingOptions
+	Name      string
+	Project   string
+	Revisions bool
+	Commits   int
 }
 
 // NewGetOptions creates a CodesetGetOptions struct
@@ -33,9 +108,10 @@ func NewSubCmdCodesetGet(gOpt *common.GlobalOptions) *cobra.Command {
 	o := NewGetOptions(gOpt)
 
 	cmd := &cobra.Command{
-		Use:   `get {-n|--name NAME} {-p|--project PROJECT}`,
+		Use:   `get {-n|--name NAME} {-p|--project PROJECT} [--revisions [--commits COMMITS]]`,
 		Short: "Get codesets.",
-		Long:  `Show details about a FuseML codeset`,
+		Long: `Show details about a FuseML codeset. With --revisions, also show its branches, tags and most recent
+commits, together with the workflow runs that were executed for each of them`,
 		Run: func(cmd *cobra.Command, args []string) {
 			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
 			common.CheckErr(o.validate())
@@ -46,6 +122,8 @@ func NewSubCmdCodesetGet(gOpt *common.GlobalOptions) *cobra.Command {
 
 	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "codeset name")
 	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "the project to which the codeset belongs")
+	cmd.Flags().BoolVar(&o.Revisions, "revisions", false, "show the codeset revisions and their workflow runs as text, instead of the formatted codeset")
+	cmd.Flags().IntVar(&o.Commits, "commits", 10, "maximum number of recent commits to show with --revisions")
 	o.format.AddSingleValueFormattingFlags(cmd, common.FormatYAML)
 	cmd.MarkFlagRequired("name")
 	cmd.MarkFlagRequired("project")
@@ -53,6 +131,9 @@ func NewSubCmdCodesetGet(gOpt *common.GlobalOptions) *cobra.Command {
 }
 
 func (o *GetOptions) validate() error {
+	if o.Commits < 0 || o.Commits > 50 {
+		return fmt.Errorf("the number of commits must be between 0 and 50")
+	}
 	return nil
 }
 
@@ -69,7 +150,125 @@ func (o *GetOptions) run() error {
 		return err
 	}
 
-	o.format.FormatValue(os.Stdout, response)
+	if !o.Revisions {
+		o.format.FormatValue(os.Stdout, response)
+		return nil
+	}
 
-	return nil
+	revRequest, err := codesetc.BuildRevisionsPayload(o.Project, o.Name, o.Commits)
+	if err != nil {
+		return err
+	}
+	revisions, err := o.CodesetClient.Revisions()(context.Background(), revRequest)
+	if err != nil {
+		return err
+	}
+
+	data := struct {
+		Codeset   *codeset.Codeset
+		Revisions *codeset.CodesetRevisions
+		Runs      map[string][]*workflow.WorkflowRun
+	}{
+		Codeset:   response.(*codeset.Codeset),
+		Revisions: revisions.(*codeset.CodesetRevisions),
+	}
+	data.Runs, err = o.getRevisionRuns()
+	if err != nil {
+		return err
+	}
+
+	funcMap := template.FuncMap{
+		"decorate":           formatted.DecorateAttr,
+		"join":               strings.Join,
+		"deref":              util.DerefString,
+		"shortCommit":        shortCommit,
+		"formatAge":          formatAge,
+		"formatMessage":      formatMessage,
+		"formatRuns":         formatRuns,
+		"formatRequirements": formatRequirements,
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 5, 3, ' ', tabwriter.TabIndent)
+	t := template.Must(template.New("Describe Codeset").Funcs(funcMap).Parse(getTemplate))
+	if err := t.Execute(w, data); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// getRevisionRuns returns the workflow runs executed for the codeset, indexed by the revision (branch name,
+// tag name or commit ID) recorded on the run. The runs are listed at once and grouped by their codeset version.
+func (o *GetOptions) getRevisionRuns() (map[string][]*workflow.WorkflowRun, error) {
+	wfRuns, err := o.WorkflowClient.ListRuns("", o.Project, o.Name, "", "")
+	if err != nil {
+		return nil, err
+	}
+	return groupRunsByVersion(wfRuns), nil
+}
+
+// groupRunsByVersion indexes workflow runs by the version of the codeset they processed. The value of a
+// codeset input is the codeset URL followed by the version, separated by a colon.
+func groupRunsByVersion(wfRuns []*workflow.WorkflowRun) map[string][]*workflow.WorkflowRun {
+	runs := map[string][]*workflow.WorkflowRun{}
+	for _, wr := range wfRuns {
+		for _, input := range wr.Inputs {
+			if input.Input == nil || util.DerefString(input.Input.Type) != codesetInputType {
+				continue
+			}
+			if i := strings.LastIndex(input.Value, ":"); i >= 0 {
+				version := input.Value[i+1:]
+				runs[version] = append(runs[version], wr)
+			}
+			break
+		}
+	}
+	return runs
+}
+
+func shortCommit(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+func formatAge(t *string) string {
+	if t != nil {
+		ct, _ := time.Parse(time.RFC3339, *t)
+		return formatted.Age(&v1.Time{Time: ct}, clockwork.NewRealClock())
+	}
+	return "---"
+}
+
+// formatMessage returns the first line of a commit message, truncated if too long
+func formatMessage(message *string) string {
+	msg := strings.SplitN(util.DerefString(message), "\n", 2)[0]
+	if len(msg) > 50 {
+		return msg[0:49] + "..."
+	}
+	return msg
+}
+
+func formatRuns(runs []*workflow.WorkflowRun) string {
+	if len(runs) == 0 {
+		return "---"
+	}
+	res := make([]string, 0, len(runs))
+	for _, wr := range runs {
+		res = append(res, fmt.Sprintf("%s (%s)", wr.Name, formatted.ColorStatus(wr.Status)))
+	}
+	return strings.Join(res, ", ")
+}
+
+// formatRequirements formats the software requirements detected in a codeset, sorted by package name
+func formatRequirements(requirements map[string]string) string {
+	res := make([]string, 0, len(requirements))
+	for pkg, version := range requirements {
+		if version != "" {
+			pkg = fmt.Sprintf("%s (%s)", pkg, version)
+		}
+		res = append(res, pkg)
+	}
+	sort.Strings(res)
+	return strings.Join(res, ", ")
 }
@@ -0,0 +1,131 @@
+package codeset
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/gen/codeset"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+// ImportOptions holds the options for 'codeset import' sub command
+type ImportOptions struct {
+	client.Clients
+	global        *common.GlobalOptions
+	Name          string
+	Project       string
+	Description   string
+	Labels        []string
+	Source        string
+	Ref           string
+	ExtensionID   string
+	ServiceID     string
+	CredentialsID string
+}
+
+// NewImportOptions creates a CodesetImportOptions struct
+func NewImportOptions(o *common.GlobalOptions) *ImportOptions {
+	return &ImportOptions{global: o}
+}
+
+// NewSubCmdCodesetImport creates and returns the cobra command for the `codeset import` CLI command
+func NewSubCmdCodesetImport(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewImportOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use: `import {-n|--name NAME} {-p|--project PROJECT} {-d|--desc DESCRIPTION} [--label LABEL] [--ref REF] [--extension-id EXTENSION --service-id SERVICE --credentials-id CREDENTIALS] SOURCE [flags]
+
+SOURCE can be the URL of a git repository or the path to a local tar.gz or zip archive`,
+		Short: "Import codesets.",
+		Long: `Register a codeset with FuseML and let FuseML populate it with the contents of SOURCE.
+
+Unlike 'codeset register', the code is not pushed from the client: FuseML fetches the remote git repository
+itself, or extracts the uploaded archive. The credentials needed to access a private git repository are taken
+from the extension registry and must be available to the codeset project.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Source = args[0]
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args:                  cobra.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "codeset name")
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "the project to which the codeset belongs")
+	cmd.Flags().StringVarP(&o.Description, "desc", "d", "", "codeset description")
+	cmd.Flags().StringSliceVar(&o.Labels, "label", []string{}, "one or more codeset labels associated with the codeset")
+	cmd.Flags().StringVar(&o.Ref, "ref", "", "branch, tag or commit of the git repository to import")
+	cmd.Flags().StringVar(&o.ExtensionID, "extension-id", "", "extension holding the credentials used to access the git repository")
+	cmd.Flags().StringVar(&o.ServiceID, "service-id", "", "extension service holding the credentials used to access the git repository")
+	cmd.Flags().StringVar(&o.CredentialsID, "credentials-id", "", "credentials used to access the git repository")
+
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("project")
+	return cmd
+}
+
+// isGitURL returns true if the import source is the URL of a remote git repository
+func (o *ImportOptions) isGitURL() bool {
+	u, err := url.Parse(o.Source)
+	return err == nil && u.IsAbs() && u.Host != ""
+}
+
+func (o *ImportOptions) validate() error {
+	credentials := []string{o.ExtensionID, o.ServiceID, o.CredentialsID}
+	if strings.Join(credentials, "") != "" && (o.ExtensionID == "" || o.ServiceID == "" || o.CredentialsID == "") {
+		return errors.New("--extension-id, --service-id and --credentials-id must be supplied together")
+	}
+	if !o.isGitURL() && (o.Ref != "" || o.CredentialsID != "") {
+		return errors.New("--ref and credentials can only be supplied when importing a git repository")
+	}
+	return nil
+}
+
+func (o *ImportOptions) run() error {
+	request := &codeset.ImportPayload{
+		Name:          o.Name,
+		Project:       o.Project,
+		Description:   o.Description,
+		Labels:        o.Labels,
+		ArchiveFormat: "tar.gz",
+	}
+	if o.isGitURL() {
+		request.URL = &o.Source
+		if o.Ref != "" {
+			request.Ref = &o.Ref
+		}
+		if o.CredentialsID != "" {
+			request.ExtensionID = &o.ExtensionID
+			request.ServiceID = &o.ServiceID
+			request.CredentialsID = &o.CredentialsID
+		}
+	} else {
+		archive, err := ioutil.ReadFile(o.Source)
+		if err != nil {
+			return errors.Wrap(err, "Error reading the archive")
+		}
+		request.Archive = archive
+		if strings.HasSuffix(o.Source, ".zip") {
+			request.ArchiveFormat = "zip"
+		}
+	}
+
+	response, err := o.CodesetClient.Import()(context.Background(), request)
+	if err != nil {
+		return err
+	}
+
+	result := response.(*codeset.ImportResult)
+	fmt.Printf("Codeset %s successfully imported\n", *result.Codeset.URL)
+	return saveCodesetConfig(o.Name, o.Project, result.Username, result.Password)
+}
@@ -0,0 +1,79 @@
+package codeset
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/gen/codeset"
+	codesetc "github.com/fuseml/fuseml-core/gen/http/codeset/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+// InspectOptions holds the options for 'codeset inspect' sub command
+type InspectOptions struct {
+	client.Clients
+	global  *common.GlobalOptions
+	Name    string
+	Project string
+}
+
+// NewInspectOptions creates a CodesetInspectOptions struct
+func NewInspectOptions(o *common.GlobalOptions) *InspectOptions {
+	return &InspectOptions{global: o}
+}
+
+// NewSubCmdCodesetInspect creates and returns the cobra command for the `codeset inspect` CLI command
+func NewSubCmdCodesetInspect(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewInspectOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   `inspect {-n|--name NAME} {-p|--project PROJECT}`,
+		Short: "Inspect codesets.",
+		Long: `Detect the metadata of a codeset from the contents of its most recent commit.
+
+The metadata is also detected again when retrieving a codeset after new commits were pushed to it. Run it
+after pushing changes to a codeset, so that the labels associated with the detected formats are added to the
+codeset labels right away.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "codeset name")
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "the project to which the codeset belongs")
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("project")
+	return cmd
+}
+
+func (o *InspectOptions) validate() error {
+	return nil
+}
+
+func (o *InspectOptions) run() error {
+	request, err := codesetc.BuildInspectPayload(o.Project, o.Name)
+	if err != nil {
+		return err
+	}
+
+	response, err := o.CodesetClient.Inspect()(context.Background(), request)
+	if err != nil {
+		return err
+	}
+
+	c := response.(*codeset.Codeset)
+	if c.Metadata == nil {
+		fmt.Printf("Codeset %s is empty\n", o.Name)
+		return nil
+	}
+	fmt.Printf("Codeset %s labels: %s\n", o.Name, strings.Join(c.Labels, ", "))
+	return nil
+}
@@ -0,0 +1,83 @@
+package codeset
+
+import (
+	"context"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	codesetc "github.com/fuseml/fuseml-core/gen/http/codeset/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+// LsOptions holds the options for 'codeset ls' sub command
+type LsOptions struct {
+	client.Clients
+	global  *common.GlobalOptions
+	format  *common.FormattingOptions
+	Name    string
+	Project string
+	Ref     string
+	Path    string
+}
+
+// NewLsOptions initializes a LsOptions struct
+func NewLsOptions(o *common.GlobalOptions) (res *LsOptions) {
+	res = &LsOptions{global: o}
+	res.format = common.NewFormattingOptions(
+		[]string{"Path", "Type", "Size"},
+		[]table.SortBy{{Name: "Path", Mode: table.Asc}},
+		nil,
+	)
+
+	return
+}
+
+// NewSubCmdCodesetLs creates and returns the cobra command for the `codeset ls` CLI command
+func NewSubCmdCodesetLs(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewLsOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   "ls {-n|--name NAME} {-p|--project PROJECT} [--ref REF] [PATH]",
+		Short: "List codeset files.",
+		Long: `List the files and directories found under PATH in a codeset, without cloning it. The codeset root is
+listed if PATH is not supplied and the default branch is used if a branch, tag or commit is not supplied with --ref`,
+		Run: func(cmd *cobra.Command, args []string) {
+			o.Path = cmd.Flags().Arg(0)
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "codeset name")
+	cmd.Flags().StringVarP(&o.Project, "project", "p", "", "the project to which the codeset belongs")
+	cmd.Flags().StringVar(&o.Ref, "ref", "", "branch, tag or commit to list the files from")
+	o.format.AddMultiValueFormattingFlags(cmd)
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("project")
+	return cmd
+}
+
+func (o *LsOptions) validate() error {
+	return nil
+}
+
+func (o *LsOptions) run() error {
+	request, err := codesetc.BuildFilesPayload(o.Project, o.Name, o.Path, o.Ref)
+	if err != nil {
+		return err
+	}
+
+	response, err := o.CodesetClient.Files()(context.Background(), request)
+	if err != nil {
+		return err
+	}
+
+	o.format.FormatValue(os.Stdout, response)
+	return nil
+}
@@ -39,18 +39,22 @@ func NewSubCmdCodesetRegister(gOpt *common.GlobalOptions) *cobra.Command {
 	o := NewRegisterOptions(gOpt)
 
 	cmd := &cobra.Command{
-		Use: `register {-n|--name NAME} {-p|--project PROJECT} {-d|--desc DESCRIPTION} [--label LABEL] LOCATION [flags]
+		Use: `register {-n|--name NAME} {-p|--project PROJECT} {-d|--desc DESCRIPTION} [--label LABEL] [LOCATION] [flags]
 
 LOCATION can be path to local directory or URL of a git repository`,
 		Short: "Register codesets.",
-		Long:  `Register a codeset with FuseML.`,
+		Long: `Register a codeset with FuseML.
+
+The code found at LOCATION is pushed to the codeset repository. When FuseML is configured with an external git
+provider (GitHub or GitLab), the codeset is a reference to an existing repository: LOCATION must be left out and
+the codeset name and project must match the repository name and its organization or group.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			o.Location = cmd.Flags().Arg(0)
 			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
-		Args:                  cobra.ExactArgs(1),
+		Args:                  cobra.MaximumNArgs(1),
 		DisableFlagsInUseLine: true,
 	}
 
@@ -98,34 +102,49 @@ func (o *RegisterOptions) run() error {
 		password = &o.Password
 	}
 
-	err = gitc.Push(o.Project, o.Name, o.Location, *codeset.URL, username, password, o.global.Verbose)
-	if err != nil {
-		return err
+	if o.Location != "" {
+		err = gitc.Push(o.Project, o.Name, o.Location, *codeset.URL, username, password, o.global.Verbose)
+		if err != nil {
+			return err
+		}
+		// detect the codeset metadata from the pushed contents
+		inspect, err := codesetc.BuildInspectPayload(o.Project, o.Name)
+		if err != nil {
+			return err
+		}
+		if _, err = o.CodesetClient.Inspect()(context.Background(), inspect); err != nil {
+			fmt.Printf("Failed to inspect the codeset contents: %v\n", err)
+		}
 	}
 
 	fmt.Printf("Codeset %s successfully registered\n", *codeset.URL)
+	return saveCodesetConfig(o.Name, o.Project, result.Username, result.Password)
+}
 
-	if result.Username != nil {
-		if viper.GetString("Username") != *result.Username {
+// saveCodesetConfig saves the credentials of a newly created project user in the config file and sets the
+// codeset as the current one
+func saveCodesetConfig(name, project string, username, password *string) error {
+	if username != nil {
+		if viper.GetString("Username") != *username {
 			fmt.Println("Saving new username into config file as current username.")
-			viper.Set("Username", *result.Username)
+			viper.Set("Username", *username)
 		}
 	}
-	if result.Password != nil {
-		if viper.GetString("Password") != *result.Password {
+	if password != nil {
+		if viper.GetString("Password") != *password {
 			fmt.Println("Saving new password into config file as current password.")
-			viper.Set("Password", *result.Password)
+			viper.Set("Password", *password)
 		}
 	}
 
-	if viper.GetString("CurrentCodeset") != o.Name {
-		fmt.Printf("Setting %s as current codeset.\n", o.Name)
-		viper.Set("CurrentCodeset", o.Name)
+	if viper.GetString("CurrentCodeset") != name {
+		fmt.Printf("Setting %s as current codeset.\n", name)
+		viper.Set("CurrentCodeset", name)
 	}
 
-	if viper.GetString("CurrentProject") != o.Project {
-		fmt.Printf("Setting %s as current project.\n", o.Project)
-		viper.Set("CurrentProject", o.Project)
+	if viper.GetString("CurrentProject") != project {
+		fmt.Printf("Setting %s as current project.\n", project)
+		viper.Set("CurrentProject", project)
 	}
 
 	if err := common.WriteConfigFile(); err != nil {
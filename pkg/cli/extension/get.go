@@ -48,6 +48,9 @@ const extensionGetTemplate = `{{decorate "bold" "ID"}}:	{{ .ID }}
    {{decorate "bold" "Category"}}:	{{ deref $s.Category }}
  {{- end }}
    {{decorate "bold" "Authentication required"}}:	{{ $s.AuthRequired }}
+ {{- with $s.HealthCheck }}
+   {{decorate "bold" "Health check"}}:	{{ .Type }}{{ if ne (deref .Path) "" }} {{ deref .Path }}{{ end }}{{ with .ExpectedStatus }} (expected status {{ . }}){{ end }}
+ {{- end }}
  
    {{- $l := len $s.Endpoints }}{{ if ne $l 0 }}
    {{decorate "pipelineruns" ""}}{{decorate "underline bold" "Endpoints\n"}}
@@ -56,6 +59,12 @@ const extensionGetTemplate = `{{decorate "bold" "ID"}}:	{{ .ID }}
     {{- if ne (deref $e.Type) "" }}
       {{decorate "bold" "Type"}}:	{{ deref $e.Type }}
     {{- end }}
+    {{- with $e.Status }}{{ if ne (deref .Health) "" }}
+      {{decorate "bold" "Health"}}:	{{ deref .Health }}{{ with .LatencyMs }} ({{ . }}ms){{ end }}, checked {{ deref .Checked }}
+      {{- if ne (deref .Message) "" }}
+      {{decorate "bold" "Health message"}}:	{{ deref .Message }}
+      {{- end }}
+    {{- end }}{{ end }}
 	{{- $l := len $e.Configuration }}{{ if ne $l 0 }}
       {{decorate "bold" "Configuration"}}:
 	  {{- range $k, $v := $e.Configuration }}
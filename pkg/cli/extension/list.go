@@ -54,6 +54,13 @@ func formatEndpoints(object interface{}, column string, field interface{}) (form
 			formated += fmt.Sprintf("[ %s ]\n", util.DerefString(svc.ID))
 			for _, ep := range svc.Endpoints {
 				formated += fmt.Sprintf("%s: %s\n", util.DerefString(ep.Type, "external"), util.DerefString(ep.URL))
+				if ep.Status != nil && ep.Status.Health != nil {
+					formated += fmt.Sprintf("  health: %s", *ep.Status.Health)
+					if ep.Status.LatencyMs != nil {
+						formated += fmt.Sprintf(" (%dms)", *ep.Status.LatencyMs)
+					}
+					formated += "\n"
+				}
 			}
 			formated += "\n"
 		}
@@ -91,7 +98,7 @@ func newSubCmdExtensionList(gOpt *common.GlobalOptions) *cobra.Command {
 	cmd := &cobra.Command{
 		Use: `list [--id EXTENSION_ID] [--product|-p PRODUCT] [--version VERSION] 
 [--zone|-z ZONE] [--service-id SERVICE_ID] [--service-resource|-r SERVICE_RESOURCE] 
-[--service-category|-r SERVICE_CATEGORY]`,
+[--service-category|-r SERVICE_CATEGORY] [--exclude-unhealthy]`,
 		Short: "Lists one or more extensions",
 		Long:  `Display information about registered extensions matching supplied criteria.`,
 		Run: func(cmd *cobra.Command, args []string) {
@@ -117,6 +124,8 @@ installations of the same product registered with the same or different FuseML s
 	cmd.Flags().StringVarP(&o.query.ServiceCategory, "service-category", "c", "",
 		`match only extensions providing one of the well-known categories of AI/ML services
 (e.g. model store, feature store, distributed training, serving)`)
+	cmd.Flags().BoolVar(&o.query.ExcludeUnhealthy, "exclude-unhealthy", false,
+		"exclude the endpoints reported as unhealthy by their last health check")
 	o.format.AddMultiValueFormattingFlags(cmd)
 
 	return cmd
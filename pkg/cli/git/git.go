@@ -15,7 +15,6 @@ import (
 	gitobject "github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/pkg/errors"
 
-	config "github.com/fuseml/fuseml-core/pkg/core/config"
 	dircopy "github.com/otiai10/copy"
 )
 
@@ -48,7 +47,7 @@ func fetchRemoteRepository(path, target string) error {
 }
 
 // Push the code from local dir to remote repo
-// If username or password is not provided, use default values
+// Username and password are required when pushing to a git server
 func Push(org, name, location, gitURL string, uname, pass *string, debug bool) error {
 	log.Printf("Pushing the code to the git repository...")
 
@@ -78,18 +77,13 @@ func Push(org, name, location, gitURL string, uname, pass *string, debug bool) e
 	if err != nil {
 		return errors.Wrap(err, "Failed to parse git url")
 	}
-	username := config.DefaultUserName(org)
-	password := config.DefaultUserPassword
-
-	if uname != nil {
-		username = *uname
-	}
-	if pass != nil {
-		password = *pass
+	if uname != nil && pass != nil {
+		u.User = url.UserPassword(*uname, *pass)
+	} else if u.Scheme == "http" || u.Scheme == "https" {
+		return errors.New(fmt.Sprintf("no credentials available to push to project %s: use --user and --password, "+
+			"or save the credentials of a project member with 'fuseml project members add --save'", org))
 	}
 
-	u.User = url.UserPassword(username, password)
-
 	// Clone new repository so we can push new content
 	cloneDir, err := ioutil.TempDir("", "codeset-clone")
 	if err != nil {
@@ -2,6 +2,7 @@ package project
 
 import (
 	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/fuseml/fuseml-core/pkg/cli/project/members"
 	"github.com/spf13/cobra"
 )
 
@@ -18,6 +19,8 @@ func NewCmdProject(c *common.GlobalOptions) *cobra.Command {
 	cmd.AddCommand(NewSubCmdProjectGet(c))
 	cmd.AddCommand(NewSubCmdProjectList(c))
 	cmd.AddCommand(NewSubCmdProjectSet(c))
+	cmd.AddCommand(NewSubCmdProjectUpdate(c))
+	cmd.AddCommand(members.NewSubCmdProjectMembers(c))
 
 	return cmd
 }
@@ -3,16 +3,21 @@ package project
 import (
 	"fmt"
 
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/gen/project"
 	"github.com/fuseml/fuseml-core/pkg/cli/client"
 	"github.com/fuseml/fuseml-core/pkg/cli/common"
-	"github.com/spf13/cobra"
 )
 
 // DeleteOptions holds the options for 'project delete' sub command
 type DeleteOptions struct {
 	client.Clients
-	global *common.GlobalOptions
-	Name   string
+	global  *common.GlobalOptions
+	Name    string
+	Cascade bool
+	DryRun  bool
 }
 
 // NewDeleteOptions creates a ProjectDeleteOptions struct
@@ -26,9 +31,13 @@ func NewSubCmdProjectDelete(gOpt *common.GlobalOptions) *cobra.Command {
 	o := NewDeleteOptions(gOpt)
 
 	cmd := &cobra.Command{
-		Use:   `delete {-n|--name NAME}`,
+		Use:   `delete {-n|--name NAME} [--cascade [--dry-run]]`,
 		Short: "Delete projects.",
-		Long:  `Delete a project from FuseML`,
+		Long: `Delete a project from FuseML.
+
+A project that still contains codesets can only be deleted with --cascade, which also unassigns the workflows
+from the project codesets and deletes the codesets and the applications created from them. Use --dry-run to
+list what would be removed without deleting anything.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
 			common.CheckErr(o.validate())
@@ -38,21 +47,57 @@ func NewSubCmdProjectDelete(gOpt *common.GlobalOptions) *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "project name")
+	cmd.Flags().BoolVar(&o.Cascade, "cascade", false, "also delete the project codesets and the resources depending on them")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "only list the resources that would be removed")
 	cmd.MarkFlagRequired("name")
 	return cmd
 }
 
 func (o *DeleteOptions) validate() error {
+	if o.DryRun && !o.Cascade {
+		return errors.New("--dry-run can only be supplied together with --cascade")
+	}
 	return nil
 }
 
 func (o *DeleteOptions) run() error {
-	err := o.ProjectClient.Delete(o.Name)
+	result, err := o.ProjectClient.Delete(o.Name, o.Cascade, o.DryRun)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Project %s successfully deleted\n", o.Name)
+	if o.Cascade {
+		printDeletion(result)
+	}
+	if !o.DryRun {
+		fmt.Printf("Project %s successfully deleted\n", o.Name)
+	}
 
 	return nil
 }
+
+// printDeletion prints the resources removed, or that would be removed, with a project
+func printDeletion(d *project.ProjectDeletion) {
+	action := "Removed"
+	if d.DryRun {
+		action = "Would remove"
+	}
+	for _, a := range d.Assignments {
+		fmt.Printf("%s workflow assignment: %s -> %s\n", action, a.Workflow, a.Codeset)
+	}
+	for _, c := range d.Codesets {
+		fmt.Printf("%s codeset: %s\n", action, c)
+	}
+	for _, a := range d.Applications {
+		fmt.Printf("%s application: %s\n", action, a)
+	}
+	for _, u := range d.Users {
+		fmt.Printf("%s user: %s\n", action, u)
+	}
+	for _, a := range d.UnknownApplications {
+		fmt.Printf("Kept application with unknown codeset project: %s\n", a)
+	}
+	if d.DryRun {
+		fmt.Printf("Would remove project: %s\n", d.Project)
+	}
+}
@@ -3,6 +3,7 @@ package project
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	project "github.com/fuseml/fuseml-core/gen/project"
 
@@ -27,18 +28,29 @@ func formatUsers(object interface{}, column string, field interface{}) (formated
 				formated += "\n"
 			}
 			formated += fmt.Sprintf("- name: %s\n  email: %s", user.Name, user.Email)
+			if user.Role != nil {
+				formated += fmt.Sprintf("\n  role: %s", *user.Role)
+			}
 		}
 	}
 	return
 }
 
+// custom formatting handler used to format project labels
+func formatLabels(object interface{}, column string, field interface{}) string {
+	if project, ok := object.(*project.Project); ok {
+		return strings.Join(project.Labels, "\n")
+	}
+	return ""
+}
+
 // NewListOptions initializes a ListOptions struct
 func NewListOptions(o *common.GlobalOptions) (res *ListOptions) {
 	res = &ListOptions{global: o}
 	res.format = common.NewFormattingOptions(
-		[]string{"Name", "Description", "Users"},
+		[]string{"Name", "Description", "Labels", "Users"},
 		[]table.SortBy{{Name: "Name", Mode: table.Asc}},
-		common.OutputFormatters{"Users": formatUsers},
+		common.OutputFormatters{"Labels": formatLabels, "Users": formatUsers},
 	)
 
 	return
@@ -0,0 +1,78 @@
+package members
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+type membersAddOptions struct {
+	client.Clients
+	global *common.GlobalOptions
+	Name   string
+	Email  string
+	Role   string
+	Save   bool
+}
+
+func newMembersAddOptions(o *common.GlobalOptions) *membersAddOptions {
+	return &membersAddOptions{global: o}
+}
+
+func newSubCmdMembersAdd(gOpt *common.GlobalOptions) *cobra.Command {
+	o := newMembersAddOptions(gOpt)
+	cmd := &cobra.Command{
+		Use:   `add {-n|--name NAME} [--email EMAIL] [--role owner|developer|viewer] [--save] USER`,
+		Short: "Adds a member to a project",
+		Long: `Add a user to a FuseML project, or change the role of an existing project member.
+
+A git user with a generated password is created for new users. The password is only displayed once: use --save
+to store the credentials in the config file, as the credentials used to push code to the project codesets.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run(cmd.Flags().Arg(0)))
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "project name")
+	cmd.Flags().StringVar(&o.Email, "email", "", "email of the user")
+	cmd.Flags().StringVar(&o.Role, "role", "developer", "role of the user in the project: owner, developer or viewer")
+	cmd.Flags().BoolVar(&o.Save, "save", false, "save the generated credentials in the config file")
+	cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+func (o *membersAddOptions) validate() error {
+	switch o.Role {
+	case "owner", "developer", "viewer":
+		return nil
+	}
+	return fmt.Errorf("invalid role %q: must be one of owner, developer or viewer", o.Role)
+}
+
+func (o *membersAddOptions) run(user string) error {
+	creds, err := o.ProjectClient.AddMember(o.Name, user, o.Email, o.Role)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("User %s successfully added to project %s as %s\n", user, o.Name, o.Role)
+	if creds.Password == nil {
+		return nil
+	}
+	fmt.Printf("Generated password: %s\n", *creds.Password)
+	if !o.Save {
+		return nil
+	}
+
+	fmt.Println("Saving the credentials into config file as current username and password.")
+	viper.Set("Username", creds.Username)
+	viper.Set("Password", *creds.Password)
+	return common.WriteConfigFile()
+}
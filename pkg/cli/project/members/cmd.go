@@ -0,0 +1,22 @@
+package members
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+// NewSubCmdProjectMembers creates and returns the cobra command that acts as a root for all other project members CLI sub-commands
+func NewSubCmdProjectMembers(c *common.GlobalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "members",
+		Short: "Project members management",
+		Long:  `Perform operations on the users assigned to a project`,
+	}
+
+	cmd.AddCommand(newSubCmdMembersAdd(c))
+	cmd.AddCommand(newSubCmdMembersRemove(c))
+	cmd.AddCommand(newSubCmdMembersList(c))
+
+	return cmd
+}
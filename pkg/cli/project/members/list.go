@@ -0,0 +1,64 @@
+package members
+
+import (
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+type membersListOptions struct {
+	client.Clients
+	global *common.GlobalOptions
+	format *common.FormattingOptions
+	Name   string
+}
+
+func newMembersListOptions(o *common.GlobalOptions) (res *membersListOptions) {
+	res = &membersListOptions{global: o}
+	res.format = common.NewFormattingOptions(
+		[]string{"Name", "Email", "Role"},
+		[]table.SortBy{{Name: "Name", Mode: table.Asc}},
+		nil,
+	)
+
+	return
+}
+
+func newSubCmdMembersList(gOpt *common.GlobalOptions) *cobra.Command {
+	o := newMembersListOptions(gOpt)
+	cmd := &cobra.Command{
+		Use:   `list {-n|--name NAME}`,
+		Short: "Lists project members",
+		Long:  `Display the users assigned to a FuseML project, together with their roles.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "project name")
+	o.format.AddMultiValueFormattingFlags(cmd)
+	cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+func (o *membersListOptions) validate() error {
+	return nil
+}
+
+func (o *membersListOptions) run() error {
+	project, err := o.ProjectClient.Get(o.Name)
+	if err != nil {
+		return err
+	}
+
+	o.format.FormatValue(os.Stdout, project.Users)
+
+	return nil
+}
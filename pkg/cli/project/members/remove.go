@@ -0,0 +1,54 @@
+package members
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+type membersRemoveOptions struct {
+	client.Clients
+	global *common.GlobalOptions
+	Name   string
+}
+
+func newMembersRemoveOptions(o *common.GlobalOptions) *membersRemoveOptions {
+	return &membersRemoveOptions{global: o}
+}
+
+func newSubCmdMembersRemove(gOpt *common.GlobalOptions) *cobra.Command {
+	o := newMembersRemoveOptions(gOpt)
+	cmd := &cobra.Command{
+		Use:   `remove {-n|--name NAME} USER`,
+		Short: "Removes a member from a project",
+		Long:  `Remove a user from a FuseML project. The git user is deleted if it is not a member of any other project.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run(cmd.Flags().Arg(0)))
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "project name")
+	cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+func (o *membersRemoveOptions) validate() error {
+	return nil
+}
+
+func (o *membersRemoveOptions) run(user string) error {
+	err := o.ProjectClient.RemoveMember(o.Name, user)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("User %s successfully removed from project %s\n", user, o.Name)
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/fuseml/fuseml-core/gen/project"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+// UpdateOptions holds the options for 'project update' sub command
+type UpdateOptions struct {
+	client.Clients
+	global            *common.GlobalOptions
+	Name              string
+	Description       string
+	Labels            []string
+	DefaultWorkflow   string
+	MaxConcurrentRuns int
+	CPU               string
+	Memory            string
+	NoQuota           bool
+}
+
+// NewUpdateOptions creates a ProjectUpdateOptions struct
+func NewUpdateOptions(o *common.GlobalOptions) *UpdateOptions {
+	return &UpdateOptions{global: o}
+}
+
+// NewSubCmdProjectUpdate creates and returns the cobra command for the `project update` CLI command
+func NewSubCmdProjectUpdate(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewUpdateOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   `update {-n|--name NAME} [-d|--desc DESCRIPTION] [--label LABEL]... [--default-workflow WORKFLOW] [--max-runs RUNS] [--cpu CPU] [--memory MEMORY] [--no-quota]`,
+		Short: "Update projects.",
+		Long: `Update the description, labels, default workflow or quota of a FuseML project.
+
+The quota limits the number of workflow runs of the project executing at the same time, and the CPU and memory
+requested by their steps. The quota fields that are not supplied keep their current values.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate(cmd.Flags()))
+			common.CheckErr(o.run(cmd.Flags()))
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.Name, "name", "n", "", "project name")
+	cmd.Flags().StringVarP(&o.Description, "desc", "d", "", "project description")
+	cmd.Flags().StringSliceVar(&o.Labels, "label", []string{}, "label associated with the project, replacing the existing ones. One or more may be supplied")
+	cmd.Flags().StringVar(&o.DefaultWorkflow, "default-workflow", "", "workflow suggested for running the project codesets")
+	cmd.Flags().IntVar(&o.MaxConcurrentRuns, "max-runs", 0, "maximum number of workflow runs of the project executing at the same time (0 for no limit)")
+	cmd.Flags().StringVar(&o.CPU, "cpu", "", "maximum CPU requested by the running workflow steps of the project (e.g. 4 or 2500m)")
+	cmd.Flags().StringVar(&o.Memory, "memory", "", "maximum memory requested by the running workflow steps of the project (e.g. 8Gi)")
+	cmd.Flags().BoolVar(&o.NoQuota, "no-quota", false, "remove the project quota")
+	cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+// quotaChanged returns true if any of the quota flags is supplied
+func quotaChanged(flags *pflag.FlagSet) bool {
+	return flags.Changed("max-runs") || flags.Changed("cpu") || flags.Changed("memory")
+}
+
+func (o *UpdateOptions) validate(flags *pflag.FlagSet) error {
+	if o.NoQuota && quotaChanged(flags) {
+		return errors.New("--no-quota cannot be supplied together with --max-runs, --cpu or --memory")
+	}
+	if o.MaxConcurrentRuns < 0 {
+		return errors.New("--max-runs cannot be negative")
+	}
+	return nil
+}
+
+func (o *UpdateOptions) run(flags *pflag.FlagSet) error {
+	request := &project.UpdatePayload{Name: o.Name}
+	if flags.Changed("desc") {
+		request.Description = &o.Description
+	}
+	if flags.Changed("label") {
+		request.Labels = o.Labels
+	}
+	if flags.Changed("default-workflow") {
+		request.DefaultWorkflow = &o.DefaultWorkflow
+	}
+	if o.NoQuota {
+		request.Quota = &project.ProjectQuota{}
+	}
+	if quotaChanged(flags) {
+		// the quota is replaced as a whole, keep the current values of the fields that are not supplied
+		current, err := o.ProjectClient.Get(o.Name)
+		if err != nil {
+			return err
+		}
+		request.Quota = &project.ProjectQuota{}
+		if current.Quota != nil {
+			*request.Quota = *current.Quota
+		}
+		if flags.Changed("max-runs") {
+			request.Quota.MaxConcurrentRuns = &o.MaxConcurrentRuns
+		}
+		if flags.Changed("cpu") {
+			request.Quota.CPU = &o.CPU
+		}
+		if flags.Changed("memory") {
+			request.Quota.Memory = &o.Memory
+		}
+	}
+
+	_, err := o.ProjectClient.Update(request)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Project %s successfully updated.\n", o.Name)
+
+	return nil
+}
@@ -16,6 +16,8 @@ func NewCmdRunnable(c *common.GlobalOptions) *cobra.Command {
 	cmd.AddCommand(NewSubCmdRunnableRegister(c))
 	cmd.AddCommand(NewSubCmdRunnableGet(c))
 	cmd.AddCommand(NewSubCmdRunnableList(c))
+	cmd.AddCommand(NewSubCmdRunnableUpdate(c))
+	cmd.AddCommand(NewSubCmdRunnableDelete(c))
 
 	return cmd
 }
@@ -0,0 +1,65 @@
+package runnable
+
+import (
+	"context"
+	"fmt"
+
+	runnablec "github.com/fuseml/fuseml-core/gen/http/runnable/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/spf13/cobra"
+)
+
+// DeleteOptions holds the options for 'runnable delete' sub command
+type DeleteOptions struct {
+	client.Clients
+	global *common.GlobalOptions
+	ID     string
+}
+
+// NewDeleteOptions initializes a DeleteOptions struct
+func NewDeleteOptions(o *common.GlobalOptions) *DeleteOptions {
+	return &DeleteOptions{global: o}
+}
+
+// NewSubCmdRunnableDelete creates and returns the cobra command for the `runnable delete` CLI command
+func NewSubCmdRunnableDelete(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewDeleteOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   `delete {-n|--id ID}`,
+		Short: "Delete runnables.",
+		Long:  `Delete a runnable registered with FuseML, including all its versions`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.ID, "id", "n", "", "runnable ID")
+	cmd.MarkFlagRequired("id")
+	return cmd
+}
+
+func (o *DeleteOptions) validate() error {
+	return nil
+}
+
+func (o *DeleteOptions) run() error {
+	request, err := runnablec.BuildDeletePayload(o.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = o.RunnableClient.Delete()(context.Background(), request)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Runnable %s successfully deleted\n", o.ID)
+
+	return nil
+}
@@ -2,9 +2,13 @@ package runnable
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
-	runnablec "github.com/fuseml/fuseml-core/gen/http/runnable/client"
+	"github.com/fuseml/fuseml-core/gen/runnable"
 	"github.com/fuseml/fuseml-core/pkg/cli/client"
 	"github.com/fuseml/fuseml-core/pkg/cli/common"
 	"github.com/spf13/cobra"
@@ -15,7 +19,9 @@ type GetOptions struct {
 	client.Clients
 	global *common.GlobalOptions
 	format *common.FormattingOptions
-	ID     string
+	// ID is the runnable ID, optionally followed by @VERSION to get a previous version of the runnable
+	ID      string
+	version *int
 }
 
 // NewGetOptions creates a RunnableGetOptions struct
@@ -31,32 +37,42 @@ func NewSubCmdRunnableGet(gOpt *common.GlobalOptions) *cobra.Command {
 	o := NewGetOptions(gOpt)
 
 	cmd := &cobra.Command{
-		Use:   `get {-n|--name NAME} {-p|--project PROJECT}`,
+		Use:   `get {ID[@VERSION]|-n|--id ID[@VERSION]}`,
 		Short: "Get runnables.",
-		Long:  `Show details about a FuseML runnable`,
+		Long:  `Show details about a FuseML runnable. The latest version of the runnable is shown, unless a version is specified with ID@VERSION`,
 		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) > 0 {
+				o.ID = args[0]
+			}
 			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
 			common.CheckErr(o.validate())
 			common.CheckErr(o.run())
 		},
-		Args: cobra.ExactArgs(0),
+		Args: cobra.MaximumNArgs(1),
 	}
 
-	cmd.Flags().StringVarP(&o.ID, "id", "n", "", "runnable ID")
+	cmd.Flags().StringVarP(&o.ID, "id", "n", "", "runnable ID, optionally followed by @VERSION")
 	o.format.AddSingleValueFormattingFlags(cmd, common.FormatYAML)
-	cmd.MarkFlagRequired("id")
 	return cmd
 }
 
 func (o *GetOptions) validate() error {
+	if o.ID == "" {
+		return errors.New("a runnable ID must be specified")
+	}
+	if i := strings.LastIndex(o.ID, "@"); i >= 0 {
+		version, err := strconv.Atoi(o.ID[i+1:])
+		if err != nil || version < 1 {
+			return fmt.Errorf("invalid runnable version %q: must be a positive number", o.ID[i+1:])
+		}
+		o.ID = o.ID[:i]
+		o.version = &version
+	}
 	return nil
 }
 
 func (o *GetOptions) run() error {
-	request, err := runnablec.BuildGetPayload(o.ID)
-	if err != nil {
-		return err
-	}
+	request := &runnable.GetPayload{ID: o.ID, Version: o.version}
 
 	response, err := o.RunnableClient.Get()(context.Background(), request)
 	if err != nil {
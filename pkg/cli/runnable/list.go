@@ -39,7 +39,7 @@ func formatLabels(object interface{}, column string, field interface{}) string {
 func NewListOptions(o *common.GlobalOptions) (res *ListOptions) {
 	res = &ListOptions{global: o}
 	res.format = common.NewFormattingOptions(
-		[]string{"ID", "Kind", "Description", "Labels"},
+		[]string{"ID", "Version", "Kind", "Description", "Labels"},
 		[]table.SortBy{{Name: "ID", Mode: table.Asc}},
 		common.OutputFormatters{"Labels": formatLabels},
 	)
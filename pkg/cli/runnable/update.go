@@ -0,0 +1,68 @@
+package runnable
+
+import (
+	"context"
+	"fmt"
+
+	runnablec "github.com/fuseml/fuseml-core/gen/http/runnable/client"
+	"github.com/fuseml/fuseml-core/gen/runnable"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/spf13/cobra"
+)
+
+// UpdateOptions holds the options for 'runnable update' sub command
+type UpdateOptions struct {
+	client.Clients
+	global       *common.GlobalOptions
+	RunnableDesc string
+}
+
+// NewUpdateOptions initializes a UpdateOptions struct
+func NewUpdateOptions(o *common.GlobalOptions) *UpdateOptions {
+	return &UpdateOptions{global: o}
+}
+
+// NewSubCmdRunnableUpdate creates and returns the cobra command for the `runnable update` CLI command
+func NewSubCmdRunnableUpdate(gOpt *common.GlobalOptions) *cobra.Command {
+
+	o := NewUpdateOptions(gOpt)
+
+	cmd := &cobra.Command{
+		Use:   `update RUNNABLE_FILE`,
+		Short: "Update runnables.",
+		Long:  `Update a runnable registered with FuseML, creating a new version of the runnable. Previous versions can still be retrieved with 'runnable get ID@VERSION'`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(common.LoadFileIntoVar(cmd.Flags().Arg(0), &o.RunnableDesc))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	return cmd
+}
+
+func (o *UpdateOptions) validate() error {
+	return nil
+}
+
+func (o *UpdateOptions) run() error {
+	// the runnable descriptor is the same used to register the runnable, including its ID
+	request, err := runnablec.BuildRegisterPayload(o.RunnableDesc)
+	if err != nil {
+		return err
+	}
+
+	response, err := o.RunnableClient.Update()(context.Background(), request)
+	if err != nil {
+		return err
+	}
+
+	runnable := response.(*runnable.Runnable)
+
+	fmt.Printf("Runnable %s successfully updated to version %d\n", runnable.ID, *runnable.Version)
+
+	return nil
+}
@@ -15,6 +15,9 @@ type assignOptions struct {
 	name           string
 	codesetName    string
 	codesetProject string
+	branches       []string
+	tags           []string
+	paths          []string
 }
 
 func newAssignOptions(o *common.GlobalOptions) *assignOptions {
@@ -24,10 +27,16 @@ func newAssignOptions(o *common.GlobalOptions) *assignOptions {
 func newSubCmdAssign(gOpt *common.GlobalOptions) *cobra.Command {
 	o := newAssignOptions(gOpt)
 	cmd := &cobra.Command{
-		Use:   "assign {-n|--name NAME} {-p|--codeset-project CODESET_PROJECT} {-c|--codeset-name CODESET_NAME} ",
+		Use:   "assign {-n|--name NAME} {-p|--codeset-project CODESET_PROJECT} {-c|--codeset-name CODESET_NAME} [--branch BRANCH]... [--tag TAG]... [--path PATH]...",
 		Short: "Assigns a workflow to a codeset",
 		Long: `Assigning a workflow to a codeset makes any change pushed to the codeset trigger the workflow(s) assigned to it.
-Upon successfully assignment a workflow run is created using the workflow's default inputs and the assigned codeset.`,
+Upon successfully assignment a workflow run is created using the workflow's default inputs and the assigned codeset.
+
+The changes that trigger the workflow can be restricted with glob patterns matching the branch or tag names
+(--branch, --tag) and the changed files (--path). In the patterns, '*' matches any sequence of characters
+except '/', '**' matches any sequence of characters and '?' matches any single character except '/'.
+When a branch filter is set that does not match the main branch, no workflow run is created upon assignment.
+Assigning a workflow to a codeset it is already assigned to replaces the assignment filters.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
 			common.CheckErr(o.validate())
@@ -39,6 +48,9 @@ Upon successfully assignment a workflow run is created using the workflow's defa
 	cmd.Flags().StringVarP(&o.name, "name", "n", "", "name of the workflow to be assigned")
 	cmd.Flags().StringVarP(&o.codesetProject, "codeset-project", "p", "", "name of the project to which the codeset belongs")
 	cmd.Flags().StringVarP(&o.codesetName, "codeset-name", "c", "", "name of the codeset to assign the workflow to")
+	cmd.Flags().StringSliceVar(&o.branches, "branch", []string{}, "pattern matching the branches that trigger the workflow. One or more may be supplied")
+	cmd.Flags().StringSliceVar(&o.tags, "tag", []string{}, "pattern matching the tags that trigger the workflow. One or more may be supplied")
+	cmd.Flags().StringSliceVar(&o.paths, "path", []string{}, "pattern matching the changed files that trigger the workflow. One or more may be supplied")
 	cmd.MarkFlagRequired("name")
 	cmd.MarkFlagRequired("codeset-name")
 	cmd.MarkFlagRequired("codeset-project")
@@ -51,7 +63,7 @@ func (o *assignOptions) validate() error {
 }
 
 func (o *assignOptions) run() error {
-	err := o.WorkflowClient.Assign(o.name, o.codesetProject, o.codesetName)
+	err := o.WorkflowClient.Assign(o.name, o.codesetProject, o.codesetName, o.branches, o.tags, o.paths)
 	if err != nil {
 		return err
 	}
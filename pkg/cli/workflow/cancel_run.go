@@ -0,0 +1,55 @@
+package workflow
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+type cancelRunOptions struct {
+	client.Clients
+	global *common.GlobalOptions
+	name   string
+}
+
+func newCancelRunOptions(o *common.GlobalOptions) *cancelRunOptions {
+	return &cancelRunOptions{global: o}
+}
+
+func newSubCmdCancelRun(gOpt *common.GlobalOptions) *cobra.Command {
+	o := newCancelRunOptions(gOpt)
+	cmd := &cobra.Command{
+		Use:   "cancel-run {-n|--name NAME}",
+		Short: "Cancels a workflow run",
+		Long:  `Cancel a running workflow run. Completed workflow runs cannot be cancelled.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.name, "name", "n", "", "name of the workflow run to be cancelled")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func (o *cancelRunOptions) validate() error {
+	return nil
+}
+
+func (o *cancelRunOptions) run() error {
+	err := o.WorkflowClient.CancelRun(o.name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Workflow run %s successfully cancelled\n", o.name)
+
+	return nil
+}
@@ -16,10 +16,18 @@ func NewCmdWorkflow(c *common.GlobalOptions) *cobra.Command {
 
 	cmd.AddCommand(newSubCmdList(c))
 	cmd.AddCommand(newSubCmdCreate(c))
+	cmd.AddCommand(newSubCmdValidate(c))
 	cmd.AddCommand(newSubCmdGet(c))
 	cmd.AddCommand(newSubCmdAssign(c))
 	cmd.AddCommand(newSubCmdListAssignments(c))
+	cmd.AddCommand(newSubCmdReconcile(c))
 	cmd.AddCommand(newSubCmdListRuns(c))
+	cmd.AddCommand(newSubCmdGetRun(c))
+	cmd.AddCommand(newSubCmdLogs(c))
+	cmd.AddCommand(newSubCmdRun(c))
+	cmd.AddCommand(newSubCmdCancelRun(c))
+	cmd.AddCommand(newSubCmdRetryRun(c))
+	cmd.AddCommand(newSubCmdDeleteRun(c))
 	cmd.AddCommand(newSubCmdUnassign(c))
 	cmd.AddCommand(newSubCmdDelete(c))
 
@@ -12,19 +12,22 @@ import (
 type createOptions struct {
 	client.Clients
 	global   *common.GlobalOptions
+	format   *common.FormattingOptions
 	workflow string
+	dryRun   bool
 }
 
 func newCreateOptions(o *common.GlobalOptions) *createOptions {
-	return &createOptions{global: o}
+	return &createOptions{global: o, format: newValidationErrorsFormattingOptions()}
 }
 
 func newSubCmdCreate(gOpt *common.GlobalOptions) *cobra.Command {
 	o := newCreateOptions(gOpt)
 	cmd := &cobra.Command{
-		Use:   `create WORKFLOW_FILE`,
+		Use:   `create [--dry-run] WORKFLOW_FILE`,
 		Short: "Creates a workflow",
-		Long:  `Creates a workflow from a file`,
+		Long: `Creates a workflow from a file. With --dry-run, the workflow is validated and the objects that the
+workflow backend would create for it are printed, without creating anything.`,
 		Run: func(cmd *cobra.Command, args []string) {
 			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
 			common.CheckErr(common.LoadFileIntoVar(cmd.Flags().Arg(0), &o.workflow))
@@ -34,6 +37,8 @@ func newSubCmdCreate(gOpt *common.GlobalOptions) *cobra.Command {
 		Args: cobra.ExactArgs(1),
 	}
 
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "print the objects created for the workflow, without creating it")
+
 	return cmd
 }
 
@@ -43,6 +48,18 @@ func (o *createOptions) validate() error {
 }
 
 func (o *createOptions) run() error {
+	if o.dryRun {
+		v, err := o.WorkflowClient.Validate(o.workflow, true)
+		if err != nil {
+			return err
+		}
+		if !v.Valid {
+			return printValidationErrors(o.format, v)
+		}
+		fmt.Print(*v.Rendered)
+		return nil
+	}
+
 	wf, err := o.WorkflowClient.Create(o.workflow)
 	if err != nil {
 		return err
@@ -0,0 +1,55 @@
+package workflow
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+type deleteRunOptions struct {
+	client.Clients
+	global *common.GlobalOptions
+	name   string
+}
+
+func newDeleteRunOptions(o *common.GlobalOptions) *deleteRunOptions {
+	return &deleteRunOptions{global: o}
+}
+
+func newSubCmdDeleteRun(gOpt *common.GlobalOptions) *cobra.Command {
+	o := newDeleteRunOptions(gOpt)
+	cmd := &cobra.Command{
+		Use:   "delete-run {-n|--name NAME}",
+		Short: "Deletes a workflow run",
+		Long:  `Delete a workflow run and the resources created for it.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.name, "name", "n", "", "name of the workflow run to be deleted")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func (o *deleteRunOptions) validate() error {
+	return nil
+}
+
+func (o *deleteRunOptions) run() error {
+	err := o.WorkflowClient.DeleteRun(o.name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Workflow run %s successfully deleted\n", o.name)
+
+	return nil
+}
@@ -110,7 +110,7 @@ func (o *getOptions) run() error {
 	}
 
 	if o.format.Format == common.FormatText {
-		wfRuns, err := o.WorkflowClient.ListRuns(o.name, "", "", "")
+		wfRuns, err := o.WorkflowClient.ListRuns(o.name, "", "", "", "")
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,148 @@
+package workflow
+
+import (
+	"os"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"github.com/tektoncd/cli/pkg/formatted"
+
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+const getRunTemplate = `{{decorate "bold" "Name"}}:	{{ .Name }}
+{{decorate "bold" "Workflow"}}:	{{ .WorkflowRef }}
+{{decorate "bold" "Status"}}:	{{ colorStatus .Status }}
+{{decorate "bold" "Started"}}:	{{ formatAge .StartTime }}
+{{decorate "bold" "Duration"}}:	{{ formatDuration .StartTime .CompletionTime }}
+{{- if ne (deref .URL) "" }}
+{{decorate "bold" "URL"}}:	{{ deref .URL }}
+{{- end }}
+
+{{decorate "params" ""}}{{decorate "underline bold" "Inputs\n"}}
+{{- if eq (len .Inputs) 0 }}
+ No inputs
+{{- else }}
+ NAME	VALUE
+{{- range $i := .Inputs }}
+ {{decorate "bullet" $i.Input.Name }}	{{ $i.Value }}
+{{- end }}
+{{- end }}
+
+{{decorate "results" ""}}{{decorate "underline bold" "Outputs\n"}}
+{{- if eq (len .Outputs) 0 }}
+ No outputs
+{{- else }}
+ NAME	VALUE
+{{- range $o := .Outputs }}
+{{- if eq $o.Value "" }}
+ {{decorate "bullet" $o.Output.Name }}	{{ "---" }}
+{{- else }}
+ {{decorate "bullet" $o.Output.Name }}	{{ $o.Value }}
+{{- end }}
+{{- end }}
+{{- end }}
+
+{{decorate "steps" ""}}{{decorate "underline bold" "Steps\n"}}
+{{- if eq (len .Steps) 0 }}
+ No steps
+{{- else }}
+ NAME	STARTED	DURATION	RETRIES	STATUS	IMAGE
+{{- range $s := .Steps }}
+ {{decorate "bullet" $s.Name }}	{{ formatAge $s.StartTime }}	{{ formatDuration (deref $s.StartTime) (deref $s.CompletionTime) }}	{{ $s.Retries }}	{{ colorStatus $s.Status }}	{{ deref $s.Image }}
+{{- end }}
+{{- end }}
+
+{{decorate "results" ""}}{{decorate "underline bold" "Step Results\n"}}
+{{- if not (hasResults .Steps) }}
+ No step results
+{{- else }}
+ STEP	NAME	VALUE
+{{- range $s := .Steps }}
+{{- range $name, $value := $s.Results }}
+ {{decorate "bullet" $s.Name }}	{{ $name }}	{{ $value }}
+{{- end }}
+{{- end }}
+{{- end }}
+
+{{decorate "params" ""}}{{decorate "underline bold" "Extensions\n"}}
+{{- if eq (len .Extensions) 0 }}
+ No extensions
+{{- else }}
+ STEP	NAME	EXTENSION	SERVICE	ENDPOINT	CREDENTIALS
+{{- range $e := .Extensions }}
+ {{decorate "bullet" $e.Step }}	{{ $e.Name }}	{{ $e.ExtensionID }}	{{ $e.ServiceID }}	{{ $e.EndpointURL }}	{{ deref $e.CredentialsID }}
+{{- end }}
+{{- end }}
+`
+
+type getRunOptions struct {
+	client.Clients
+	global *common.GlobalOptions
+	format *common.FormattingOptions
+	name   string
+}
+
+func newGetRunOptions(o *common.GlobalOptions) *getRunOptions {
+	res := &getRunOptions{global: o}
+	res.format = common.NewSingleValueFormattingOptions()
+	return res
+}
+
+func newSubCmdGetRun(gOpt *common.GlobalOptions) *cobra.Command {
+	o := newGetRunOptions(gOpt)
+	cmd := &cobra.Command{
+		Use:   `get-run {-n|--name NAME}`,
+		Short: "Get a workflow run",
+		Long:  `Show detailed information from a workflow run, including the status, timings and results of each of its steps`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.name, "name", "n", "", "workflow run name")
+	o.format.AddSingleValueFormattingFlags(cmd, common.FormatText)
+	cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+func (o *getRunOptions) validate() error {
+	return nil
+}
+
+func (o *getRunOptions) run() error {
+	wr, err := o.WorkflowClient.GetRun(o.name)
+	if err != nil {
+		return err
+	}
+
+	if o.format.Format == common.FormatText {
+		funcMap := template.FuncMap{
+			"decorate":       formatted.DecorateAttr,
+			"formatAge":      formatAge,
+			"formatDuration": formatDuration,
+			"colorStatus":    formatted.ColorStatus,
+			"deref":          util.DerefString,
+			"hasResults":     hasStepResults,
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 5, 3, ' ', tabwriter.TabIndent)
+		t := template.Must(template.New("Describe PipelineRun").Funcs(funcMap).Parse(getRunTemplate))
+		err = t.Execute(w, wr)
+		if err != nil {
+			return err
+		}
+
+		w.Flush()
+	} else {
+		o.format.FormatValue(os.Stdout, wr)
+	}
+
+	return nil
+}
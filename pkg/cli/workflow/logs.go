@@ -0,0 +1,57 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/gen/workflow"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+type logsOptions struct {
+	client.Clients
+	global *common.GlobalOptions
+	name   string
+	step   string
+	follow bool
+}
+
+func newLogsOptions(o *common.GlobalOptions) *logsOptions {
+	return &logsOptions{global: o}
+}
+
+func newSubCmdLogs(gOpt *common.GlobalOptions) *cobra.Command {
+	o := newLogsOptions(gOpt)
+	cmd := &cobra.Command{
+		Use:   "logs {-n|--name NAME} [-s|--step STEP] [-f|--follow]",
+		Short: "Shows the logs from a workflow run",
+		Long: `Prints the logs from the steps of a workflow run, prefixed by the step and container names. Use --step to only
+show the logs from a single step and --follow to keep streaming the logs until the workflow run completes.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.name, "name", "n", "", "name of the workflow run")
+	cmd.Flags().StringVarP(&o.step, "step", "s", "", "name of the step to show the logs from")
+	cmd.Flags().BoolVarP(&o.follow, "follow", "f", false, "keep streaming the logs until the workflow run completes")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func (o *logsOptions) validate() error {
+	return nil
+}
+
+func (o *logsOptions) run() error {
+	return o.WorkflowClient.Logs(o.name, o.step, o.follow, func(log *workflow.WorkflowRunLog) {
+		fmt.Printf("[%s : %s] %s\n", log.Step, strings.TrimPrefix(log.Container, "step-"), log.Line)
+	})
+}
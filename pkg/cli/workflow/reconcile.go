@@ -0,0 +1,73 @@
+package workflow
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+type reconcileOptions struct {
+	client.Clients
+	global  *common.GlobalOptions
+	cleanup bool
+}
+
+func newReconcileOptions(o *common.GlobalOptions) *reconcileOptions {
+	return &reconcileOptions{global: o}
+}
+
+func newSubCmdReconcile(gOpt *common.GlobalOptions) *cobra.Command {
+	o := newReconcileOptions(gOpt)
+	cmd := &cobra.Command{
+		Use:   "reconcile [--cleanup]",
+		Short: "Finds inconsistent workflow assignments",
+		Long: `Find the workflow assignments to codesets that no longer exist, the codeset webhooks sending events to a
+workflow listener without a matching assignment and the listeners of workflows not assigned to any codeset.
+With --cleanup, the inconsistencies found are removed.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().BoolVar(&o.cleanup, "cleanup", false, "remove the inconsistencies found")
+
+	return cmd
+}
+
+func (o *reconcileOptions) validate() error {
+	return nil
+}
+
+func (o *reconcileOptions) run() error {
+	r, err := o.WorkflowClient.ReconcileAssignments(o.cleanup)
+	if err != nil {
+		return err
+	}
+
+	action := "Found"
+	if r.Cleanup {
+		action = "Removed"
+	}
+	for _, a := range r.StaleAssignments {
+		fmt.Printf("%s stale assignment of workflow %s to deleted codeset %s/%s\n", action, a.Workflow,
+			a.CodesetProject, a.CodesetName)
+	}
+	for _, w := range r.OrphanedWebhooks {
+		fmt.Printf("%s orphaned webhook %d of codeset %s/%s for workflow listener %s\n", action, w.ID,
+			w.CodesetProject, w.CodesetName, w.Listener)
+	}
+	for _, l := range r.OrphanedListeners {
+		fmt.Printf("%s orphaned workflow listener %s\n", action, l)
+	}
+	if len(r.StaleAssignments)+len(r.OrphanedWebhooks)+len(r.OrphanedListeners) == 0 {
+		fmt.Println("Workflow assignments are consistent")
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package workflow
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+type retryRunOptions struct {
+	client.Clients
+	global *common.GlobalOptions
+	name   string
+}
+
+func newRetryRunOptions(o *common.GlobalOptions) *retryRunOptions {
+	return &retryRunOptions{global: o}
+}
+
+func newSubCmdRetryRun(gOpt *common.GlobalOptions) *cobra.Command {
+	o := newRetryRunOptions(gOpt)
+	cmd := &cobra.Command{
+		Use:   "retry-run {-n|--name NAME}",
+		Short: "Retries a workflow run",
+		Long: `Start a new run of a workflow using the same codeset revision and input values as a completed workflow run.
+The workflow run being retried is kept unchanged.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.name, "name", "n", "", "name of the workflow run to be retried")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+func (o *retryRunOptions) validate() error {
+	return nil
+}
+
+func (o *retryRunOptions) run() error {
+	runName, err := o.WorkflowClient.RetryRun(o.name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Workflow run %q created to retry workflow run %q\n", runName, o.name)
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package workflow
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+type runOptions struct {
+	client.Clients
+	global          *common.GlobalOptions
+	name            string
+	codesetName     string
+	codesetProject  string
+	codesetRevision string
+	user            string
+	inputs          map[string]string
+}
+
+func newRunOptions(o *common.GlobalOptions) *runOptions {
+	return &runOptions{global: o}
+}
+
+func newSubCmdRun(gOpt *common.GlobalOptions) *cobra.Command {
+	o := newRunOptions(gOpt)
+	cmd := &cobra.Command{
+		Use:   "run {-n|--name NAME} {-p|--codeset-project CODESET_PROJECT} {-c|--codeset-name CODESET_NAME} [-r|--codeset-revision REVISION] [-u|--user USER] [-i|--input NAME=VALUE]...",
+		Short: "Starts a workflow run",
+		Long: `Starts a new run of a workflow for a codeset. By default, the workflow run uses the codeset 'main' branch and the
+default values of the workflow inputs. Use --codeset-revision to run the workflow with a different codeset branch, tag
+or commit and --input to override the default value of one or more workflow inputs. Use --user to run the workflow
+with the extension credentials scoped to a user of the codeset project.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(0),
+	}
+
+	cmd.Flags().StringVarP(&o.name, "name", "n", "", "name of the workflow to run")
+	cmd.Flags().StringVarP(&o.codesetProject, "codeset-project", "p", "", "name of the project to which the codeset belongs")
+	cmd.Flags().StringVarP(&o.codesetName, "codeset-name", "c", "", "name of the codeset to run the workflow with")
+	cmd.Flags().StringVarP(&o.codesetRevision, "codeset-revision", "r", "", "codeset revision (branch, tag or commit) to run the workflow with")
+	cmd.Flags().StringVarP(&o.user, "user", "u", "", "user triggering the run, whose user scoped extension credentials are used by the workflow")
+	cmd.Flags().StringToStringVarP(&o.inputs, "input", "i", nil, "value overriding the default of a workflow input, in the NAME=VALUE format")
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("codeset-name")
+	cmd.MarkFlagRequired("codeset-project")
+
+	return cmd
+}
+
+func (o *runOptions) validate() error {
+	return nil
+}
+
+func (o *runOptions) run() error {
+	runName, err := o.WorkflowClient.Run(o.name, o.codesetProject, o.codesetName, o.codesetRevision, o.user, o.inputs)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Workflow run %q created for workflow %q and codeset \"%s/%s\"\n", runName, o.name, o.codesetProject, o.codesetName)
+
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/jonboulle/clockwork"
 	"github.com/tektoncd/cli/pkg/formatted"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fuseml/fuseml-core/gen/workflow"
 )
 
 func formatDesc(desc string) string {
@@ -29,3 +31,12 @@ func formatDuration(startTime, completionTime string) string {
 	ct, _ := time.Parse(layout, completionTime)
 	return formatted.Duration(&v1.Time{Time: st}, &v1.Time{Time: ct})
 }
+
+func hasStepResults(steps []*workflow.WorkflowRunStep) bool {
+	for _, s := range steps {
+		if len(s.Results) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/spf13/cobra"
+
+	"github.com/fuseml/fuseml-core/gen/workflow"
+	"github.com/fuseml/fuseml-core/pkg/cli/client"
+	"github.com/fuseml/fuseml-core/pkg/cli/common"
+)
+
+type validateOptions struct {
+	client.Clients
+	global   *common.GlobalOptions
+	format   *common.FormattingOptions
+	workflow string
+}
+
+func newValidationErrorsFormattingOptions() *common.FormattingOptions {
+	return common.NewFormattingOptions(
+		[]string{"Field", "Message"},
+		[]table.SortBy{},
+		nil,
+	)
+}
+
+func newValidateOptions(o *common.GlobalOptions) *validateOptions {
+	return &validateOptions{global: o, format: newValidationErrorsFormattingOptions()}
+}
+
+func newSubCmdValidate(gOpt *common.GlobalOptions) *cobra.Command {
+	o := newValidateOptions(gOpt)
+	cmd := &cobra.Command{
+		Use:   `validate WORKFLOW_FILE`,
+		Short: "Validates a workflow",
+		Long: `Validates a workflow definition from a file, without creating it. Prints a table of the problems found in the
+workflow definition, if any.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			common.CheckErr(o.InitializeClients(gOpt.URL, gOpt.Timeout, gOpt.Verbose))
+			common.CheckErr(common.LoadFileIntoVar(cmd.Flags().Arg(0), &o.workflow))
+			common.CheckErr(o.validate())
+			common.CheckErr(o.run())
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	o.format.AddMultiValueFormattingFlags(cmd)
+
+	return cmd
+}
+
+func (o *validateOptions) validate() error {
+	return nil
+}
+
+func (o *validateOptions) run() error {
+	v, err := o.WorkflowClient.Validate(o.workflow, false)
+	if err != nil {
+		return err
+	}
+
+	if !v.Valid {
+		return printValidationErrors(o.format, v)
+	}
+	fmt.Println("Workflow is valid")
+
+	return nil
+}
+
+// printValidationErrors prints the problems found when validating a workflow, returning an error
+// to signal that the workflow is not valid
+func printValidationErrors(format *common.FormattingOptions, v *workflow.WorkflowValidation) error {
+	format.FormatValue(os.Stdout, v.Errors)
+	return fmt.Errorf("workflow is not valid, %d problem(s) found", len(v.Errors))
+}
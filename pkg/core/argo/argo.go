@@ -0,0 +1,1254 @@
+package argo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/fuseml/fuseml-core/pkg/core/variables"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+const (
+	errServerURLMissing    = WorkflowBackendErr("value for Argo Server URL (ARGO_SERVER_URL) was not provided.")
+	errWaitListenerTimeout = WorkflowBackendErr("time out waiting for listener to become ready")
+)
+
+// WorkflowBackendErr are expected errors returned from the WorkflowBackend
+type WorkflowBackendErr string
+
+// EnvVarMap describes environment variables and their values that need to be passed to an argo template
+type EnvVarMap map[string]string
+
+// WorkflowBackend implements the FuseML WorkflowBackend interface for argo. FuseML workflows are
+// represented by argo WorkflowTemplates, their runs by argo Workflows and their listeners by argo events
+// EventSources (webhooks) and Sensors (triggering Workflows).
+type WorkflowBackend struct {
+	serverURL   string
+	namespace   string
+	logger      *log.Logger
+	argoClients *clients
+}
+
+// NewWorkflowBackend initializes Argo backend
+func NewWorkflowBackend(logger *log.Logger, namespace string) (*WorkflowBackend, error) {
+	serverURL, exists := os.LookupEnv("ARGO_SERVER_URL")
+	if !exists {
+		return nil, errServerURLMissing
+	}
+	clients, err := newClients(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing argo workflow backend: %w", err)
+	}
+	return &WorkflowBackend{strings.TrimSuffix(serverURL, "/"), namespace, logger, clients}, nil
+}
+
+// CreateWorkflow receives a FuseML workflow and creates an argo WorkflowTemplate from it
+func (w *WorkflowBackend) CreateWorkflow(ctx context.Context, workflow *domain.Workflow) error {
+	wt := generateWorkflowTemplate(*workflow, w.namespace)
+	w.logger.Printf("Creating argo workflow template for workflow: %s...", workflow.Name)
+	err := w.create(ctx, w.argoClients.WorkflowTemplateClient, wt)
+	if err != nil {
+		if k8serr.IsAlreadyExists(err) {
+			return domain.ErrWorkflowExists
+		}
+		return fmt.Errorf("error creating argo workflow template for workflow %q: %w", workflow.Name, err)
+	}
+	return nil
+}
+
+// UpdateWorkflow updates the argo WorkflowTemplate generated for the workflow and, if the workflow has a
+// listener, the sensor creating argo Workflows from it
+func (w *WorkflowBackend) UpdateWorkflow(ctx context.Context, workflow *domain.Workflow) error {
+	wt := generateWorkflowTemplate(*workflow, w.namespace)
+	w.logger.Printf("Updating argo workflow template for workflow: %s...", workflow.Name)
+	if err := w.update(ctx, w.argoClients.WorkflowTemplateClient, wt.Name, wt); err != nil {
+		if k8serr.IsNotFound(err) {
+			return domain.ErrWorkflowNotFound
+		}
+		return fmt.Errorf("error updating argo workflow template for workflow %q: %w", workflow.Name, err)
+	}
+
+	sensor, err := generateSensor(wt, generateEventSource(wt))
+	if err != nil {
+		return fmt.Errorf("error generating argo sensor %q: %w", workflow.Name, err)
+	}
+	if err = w.update(ctx, w.argoClients.SensorClient, sensor.Name, sensor); err != nil && !k8serr.IsNotFound(err) {
+		return fmt.Errorf("error updating argo sensor %q: %w", workflow.Name, err)
+	}
+	return nil
+}
+
+// RenderWorkflow returns the argo WorkflowTemplate generated for the workflow as YAML, without creating it
+func (w *WorkflowBackend) RenderWorkflow(ctx context.Context, workflow *domain.Workflow) (string, error) {
+	out, err := yaml.Marshal(generateWorkflowTemplate(*workflow, w.namespace))
+	if err != nil {
+		return "", fmt.Errorf("error rendering argo workflow template for workflow %q: %w", workflow.Name, err)
+	}
+	return string(out), nil
+}
+
+// DeleteWorkflow deletes the argo WorkflowTemplate with the specified name
+func (w *WorkflowBackend) DeleteWorkflow(ctx context.Context, name string) error {
+	w.logger.Printf("Deleting argo workflow template: %s...", name)
+	err := w.argoClients.WorkflowTemplateClient.Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return fmt.Errorf("error deleting argo workflow template %q: %w", name, err)
+		}
+		w.logger.Printf("Argo workflow template %q not found, skipping delete...", name)
+	}
+	return w.deleteCredentialsSecrets(ctx, name)
+}
+
+// CreateWorkflowRun creates an argo Workflow from the WorkflowTemplate of the specified workflow and codeset.
+// The Workflow uses the codeset revision and input values set on the run options, falling back to their
+// default values.
+func (w *WorkflowBackend) CreateWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset,
+	options *domain.WorkflowRunOptions) (string, error) {
+	wt := &WorkflowTemplate{}
+	if err := w.get(ctx, w.argoClients.WorkflowTemplateClient, workflowName, wt); err != nil {
+		return "", fmt.Errorf("error getting argo workflow template %q: %w", workflowName, err)
+	}
+
+	wf, err := generateWorkflow(wt, codeset, options)
+	if err != nil {
+		return "", fmt.Errorf("error generating argo workflow for workflow %q: %w", workflowName, err)
+	}
+
+	w.logger.Printf("Creating argo workflow for workflow: %s...", workflowName)
+	return w.createWorkflow(ctx, wf)
+}
+
+// GetWorkflowRuns returns the argo Workflows created from the workflow WorkflowTemplate, matching the filter
+func (w *WorkflowBackend) GetWorkflowRuns(ctx context.Context, wf *domain.Workflow, filter *domain.WorkflowRunFilter) ([]*domain.WorkflowRun, error) {
+	labelSelector := fmt.Sprintf("%s=%s", LabelWorkflowRef, wf.Name)
+	if filter.CodesetName != "" {
+		labelSelector = fmt.Sprintf("%s,%s=%s", labelSelector, LabelCodesetName, filter.CodesetName)
+	}
+	if filter.CodesetProject != "" {
+		labelSelector = fmt.Sprintf("%s,%s=%s", labelSelector, LabelCodesetProject, filter.CodesetProject)
+	}
+	if filter.CodesetVersion != "" {
+		labelSelector = fmt.Sprintf("%s,%s=%s", labelSelector, LabelCodesetVersion, filter.CodesetVersion)
+	}
+	list, err := w.argoClients.WorkflowClient.List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		return nil, fmt.Errorf("error getting argo workflows %q: %w", wf.Name, err)
+	}
+
+	workflowRuns := []*domain.WorkflowRun{}
+	for _, item := range list.Items {
+		run := &Workflow{}
+		if err := fromUnstructured(item.Object, run); err != nil {
+			return nil, fmt.Errorf("error decoding argo workflow %q: %w", item.GetName(), err)
+		}
+		if len(filter.Status) > 0 && !util.StringInSlice(workflowStatus(run), filter.Status) {
+			continue
+		}
+		workflowRuns = append(workflowRuns, w.toWorkflowRun(wf, run))
+	}
+	return workflowRuns, nil
+}
+
+// GetWorkflowRun returns the WorkflowRun for the argo Workflow with the specified name, including the
+// status of the tasks executed by the Workflow
+func (w *WorkflowBackend) GetWorkflowRun(ctx context.Context, runName string) (*domain.WorkflowRun, error) {
+	run, err := w.getWorkflow(ctx, runName)
+	if err != nil {
+		return nil, err
+	}
+
+	// build the workflow inputs and outputs from the argo workflow, the codeset input is represented
+	// by the codeset parameters, while the outputs are the results from the tasks
+	wf := &domain.Workflow{Name: run.Labels[LabelWorkflowRef]}
+	for _, param := range workflowParameters(run) {
+		switch {
+		case param.Name == codesetURLParam:
+			wf.Inputs = append(wf.Inputs, &domain.WorkflowInput{Type: domain.WorkflowIOTypeCodeset})
+		case !isCodesetParam(param.Name) && param.Name != credentialsSecretParam:
+			wf.Inputs = append(wf.Inputs, &domain.WorkflowInput{Name: param.Name, Type: domain.WorkflowIOTypeString})
+		}
+	}
+	for _, node := range taskNodes(run) {
+		if node.Outputs == nil {
+			continue
+		}
+		for _, output := range node.Outputs.Parameters {
+			wf.Outputs = append(wf.Outputs, &domain.WorkflowOutput{Name: output.Name, Type: domain.WorkflowIOTypeString})
+		}
+	}
+
+	wfr := w.toWorkflowRun(wf, run)
+	wfr.Steps = toWorkflowRunSteps(run)
+	return wfr, nil
+}
+
+// CancelWorkflowRun terminates a running argo Workflow
+func (w *WorkflowBackend) CancelWorkflowRun(ctx context.Context, runName string) error {
+	run, err := w.getWorkflow(ctx, runName)
+	if err != nil {
+		return err
+	}
+	if workflowDone(run) || run.Spec.Shutdown != "" {
+		return domain.ErrWorkflowRunCompleted
+	}
+
+	w.logger.Printf("Cancelling argo workflow: %s...", runName)
+	run.Spec.Shutdown = workflowShutdownTerminate
+	obj, err := toUnstructured(run)
+	if err != nil {
+		return err
+	}
+	_, err = w.argoClients.WorkflowClient.Update(ctx, &unstructured.Unstructured{Object: obj}, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("error cancelling argo workflow %q: %w", runName, err)
+	}
+	return nil
+}
+
+// RetryWorkflowRun creates a new argo Workflow with the same labels and arguments as a completed Workflow. The new
+// Workflow references the current workflow template, so it records the extensions the workflow steps are currently
+// bound to instead of those of the completed Workflow.
+func (w *WorkflowBackend) RetryWorkflowRun(ctx context.Context, runName string,
+	extensions []*domain.WorkflowRunExtension) (string, error) {
+	run, err := w.getWorkflow(ctx, runName)
+	if err != nil {
+		return "", err
+	}
+	if !workflowDone(run) {
+		return "", domain.ErrWorkflowRunNotCompleted
+	}
+
+	wf := &Workflow{
+		TypeMeta: run.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: run.GenerateName,
+			Namespace:    run.Namespace,
+			Labels:       run.Labels,
+		},
+		Spec: run.Spec,
+	}
+	annotations := map[string]string{}
+	if user, ok := run.Annotations[AnnotationUser]; ok {
+		annotations[AnnotationUser] = user
+	}
+	if len(extensions) > 0 {
+		annotations[AnnotationExtensions] = extensionsAnnotation(extensions)
+	}
+	if len(annotations) > 0 {
+		wf.Annotations = annotations
+	}
+	wf.Spec.Shutdown = ""
+
+	w.logger.Printf("Retrying argo workflow: %s...", runName)
+	name, err := w.createWorkflow(ctx, wf)
+	if err != nil {
+		return "", fmt.Errorf("error retrying argo workflow %q: %w", runName, err)
+	}
+	return name, nil
+}
+
+// DeleteWorkflowRun deletes the argo Workflow with the specified name
+func (w *WorkflowBackend) DeleteWorkflowRun(ctx context.Context, runName string) error {
+	if _, err := w.getWorkflow(ctx, runName); err != nil {
+		return err
+	}
+
+	w.logger.Printf("Deleting argo workflow: %s...", runName)
+	err := w.argoClients.WorkflowClient.Delete(ctx, runName, metav1.DeleteOptions{})
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return domain.ErrWorkflowRunNotFound
+		}
+		return fmt.Errorf("error deleting argo workflow %q: %w", runName, err)
+	}
+	return nil
+}
+
+// ApplyProjectQuota is only supported for quotas without resource caps, as the argo workflow backend does not
+// limit the resources used by the argo Workflows of a project
+func (w *WorkflowBackend) ApplyProjectQuota(ctx context.Context, project string, quota *domain.ProjectQuota) error {
+	if quota.HasResourceCaps() {
+		return domain.ErrProjectQuotaUnsupported
+	}
+	return nil
+}
+
+// CreateWorkflowListener creates the argo events EventSource and Sensor required to have a listener ready for
+// creating argo Workflows from the WorkflowTemplate
+func (w *WorkflowBackend) CreateWorkflowListener(ctx context.Context, workflowName string, timeout time.Duration) (*domain.WorkflowListener, error) {
+	wt := &WorkflowTemplate{}
+	if err := w.get(ctx, w.argoClients.WorkflowTemplateClient, workflowName, wt); err != nil {
+		return nil, fmt.Errorf("error getting argo workflow template %q: %w", workflowName, err)
+	}
+
+	eventSource := generateEventSource(wt)
+	err := w.get(ctx, w.argoClients.EventSourceClient, workflowName, &EventSource{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return nil, fmt.Errorf("error getting argo event source %q: %w", workflowName, err)
+		}
+		w.logger.Printf("Creating argo event source for workflow: %s...", workflowName)
+		err = w.create(ctx, w.argoClients.EventSourceClient, eventSource)
+		if err != nil {
+			return nil, fmt.Errorf("error creating argo event source %q: %w", workflowName, err)
+		}
+		defer w.deleteIfError(ctx, &err, w.argoClients.EventSourceClient, workflowName)
+	}
+
+	sensor, err := generateSensor(wt, eventSource)
+	if err != nil {
+		return nil, fmt.Errorf("error generating argo sensor %q: %w", workflowName, err)
+	}
+	err = w.get(ctx, w.argoClients.SensorClient, workflowName, &Sensor{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return nil, fmt.Errorf("error getting argo sensor %q: %w", workflowName, err)
+		}
+		w.logger.Printf("Creating argo sensor for workflow: %s...", workflowName)
+		err = w.create(ctx, w.argoClients.SensorClient, sensor)
+		if err != nil {
+			return nil, fmt.Errorf("error creating argo sensor %q: %w", workflowName, err)
+		}
+		defer w.deleteIfError(ctx, &err, w.argoClients.SensorClient, workflowName)
+	}
+
+	if timeout > 0 {
+		interval := 1 * time.Second
+		if err = wait.PollImmediate(interval, timeout, w.listenerReady(ctx, workflowName)); err != nil {
+			err = errWaitListenerTimeout
+			return nil, err
+		}
+	}
+	return w.GetWorkflowListener(ctx, workflowName)
+}
+
+// DeleteWorkflowListener deletes all argo events resources associated to the specified listener name
+func (w *WorkflowBackend) DeleteWorkflowListener(ctx context.Context, name string) error {
+	w.logger.Printf("Deleting argo sensor: %s...", name)
+	err := w.argoClients.SensorClient.Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return fmt.Errorf("error deleting argo sensor %q: %w", name, err)
+		}
+		w.logger.Printf("Argo sensor %q not found, skipping delete...", name)
+	}
+
+	w.logger.Printf("Deleting argo event source: %s...", name)
+	err = w.argoClients.EventSourceClient.Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		if !k8serr.IsNotFound(err) {
+			return fmt.Errorf("error deleting argo event source %q: %w", name, err)
+		}
+		w.logger.Printf("Argo event source %q not found, skipping delete...", name)
+	}
+	return nil
+}
+
+// UpdateWorkflowListener checks that the codeset assignments can be handled by the argo sensor. The sensor
+// triggers the workflow for every change pushed to the assigned codesets, so assignment filters are not supported.
+func (w *WorkflowBackend) UpdateWorkflowListener(ctx context.Context, workflowName string, assignments []*domain.CodesetAssignment) error {
+	for _, a := range assignments {
+		if !a.Filter.IsEmpty() {
+			return fmt.Errorf("%w: filters are not supported by the argo workflow backend", domain.ErrInvalidAssignmentFilter)
+		}
+	}
+	return nil
+}
+
+// GetWorkflowListener returns the listener for a given workflow
+func (w *WorkflowBackend) GetWorkflowListener(ctx context.Context, workflowName string) (*domain.WorkflowListener, error) {
+	available, err := w.listenerReady(ctx, workflowName)()
+	if err != nil {
+		return nil, err
+	}
+	wl := &domain.WorkflowListener{Name: workflowName, Available: available,
+		DashboardURL: fmt.Sprintf("%s/sensors/%s/%s", w.serverURL, w.namespace, workflowName)}
+	if available {
+		wl.URL = w.eventSourceURL(workflowName)
+	}
+	return wl, nil
+}
+
+// GetWorkflowListeners returns the listeners of all workflows, identified by their argo event sources
+func (w *WorkflowBackend) GetWorkflowListeners(ctx context.Context) ([]*domain.WorkflowListener, error) {
+	list, err := w.argoClients.EventSourceClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing argo event sources: %w", err)
+	}
+	listeners := make([]*domain.WorkflowListener, 0, len(list.Items))
+	for _, es := range list.Items {
+		// a listener whose sensor cannot be read is reported as not available
+		available, _ := w.listenerReady(ctx, es.GetName())()
+		listeners = append(listeners, &domain.WorkflowListener{Name: es.GetName(), Available: available,
+			URL:          w.eventSourceURL(es.GetName()),
+			DashboardURL: fmt.Sprintf("%s/sensors/%s/%s", w.serverURL, w.namespace, es.GetName())})
+	}
+	return listeners, nil
+}
+
+// eventSourceURL returns the address of the webhook served by the argo event source of a workflow
+func (w *WorkflowBackend) eventSourceURL(workflowName string) string {
+	return fmt.Sprintf("http://%s-eventsource-svc.%s.svc.cluster.local:%d%s", workflowName, w.namespace,
+		webhookPort, webhookEndpoint)
+}
+
+func (e WorkflowBackendErr) Error() string {
+	return string(e)
+}
+
+// listenerReady checks whether the event source and sensor for the workflow have been deployed
+func (w *WorkflowBackend) listenerReady(ctx context.Context, name string) wait.ConditionFunc {
+	return func() (bool, error) {
+		es := &EventSource{}
+		if err := w.get(ctx, w.argoClients.EventSourceClient, name, es); err != nil {
+			return false, fmt.Errorf("error getting argo event source %q: %w", name, err)
+		}
+		sensor := &Sensor{}
+		if err := w.get(ctx, w.argoClients.SensorClient, name, sensor); err != nil {
+			return false, fmt.Errorf("error getting argo sensor %q: %w", name, err)
+		}
+		return isDeployed(es.Status) && isDeployed(sensor.Status), nil
+	}
+}
+
+func isDeployed(status *Status) bool {
+	// No conditions have been set yet
+	if status == nil || len(status.Conditions) == 0 {
+		return false
+	}
+	for _, cond := range status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *WorkflowBackend) get(ctx context.Context, client dynamic.ResourceInterface, name string, obj interface{}) error {
+	u, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	return fromUnstructured(u.Object, obj)
+}
+
+func (w *WorkflowBackend) create(ctx context.Context, client dynamic.ResourceInterface, obj interface{}) error {
+	u, err := toUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	_, err = client.Create(ctx, &unstructured.Unstructured{Object: u}, metav1.CreateOptions{})
+	return err
+}
+
+// update replaces an existing object, keeping its resource version
+func (w *WorkflowBackend) update(ctx context.Context, client dynamic.ResourceInterface, name string, obj interface{}) error {
+	current, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	u, err := toUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	// keep the status set by the argo controllers
+	if status, ok := current.Object["status"]; ok {
+		u["status"] = status
+	}
+	updated := &unstructured.Unstructured{Object: u}
+	updated.SetResourceVersion(current.GetResourceVersion())
+	_, err = client.Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+func (w *WorkflowBackend) createWorkflow(ctx context.Context, wf *Workflow) (string, error) {
+	u, err := toUnstructured(wf)
+	if err != nil {
+		return "", err
+	}
+	created, err := w.argoClients.WorkflowClient.Create(ctx, &unstructured.Unstructured{Object: u}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("error creating argo workflow %q: %w", wf.GenerateName, err)
+	}
+	return created.GetName(), nil
+}
+
+func (w *WorkflowBackend) getWorkflow(ctx context.Context, name string) (*Workflow, error) {
+	wf := &Workflow{}
+	if err := w.get(ctx, w.argoClients.WorkflowClient, name, wf); err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil, domain.ErrWorkflowRunNotFound
+		}
+		return nil, fmt.Errorf("error getting argo workflow %q: %w", name, err)
+	}
+	if _, ok := wf.Labels[LabelWorkflowRef]; !ok {
+		return nil, domain.ErrWorkflowRunNotFound
+	}
+	return wf, nil
+}
+
+func (w *WorkflowBackend) deleteIfError(ctx context.Context, err *error, client dynamic.ResourceInterface, name string) {
+	if *err != nil {
+		w.logger.Printf("Deleting %s... (creating listener failed)", name)
+		client.Delete(ctx, name, metav1.DeleteOptions{})
+	}
+}
+
+func generateWorkflowTemplate(w domain.Workflow, namespace string) *WorkflowTemplate {
+	resolver := newVariablesResolver()
+	wt := &WorkflowTemplate{
+		TypeMeta: metav1.TypeMeta{APIVersion: apiVersion, Kind: workflowTemplateKind},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      w.Name,
+			Namespace: namespace,
+			// label the workflow template with a reference to the workflow name
+			Labels: map[string]string{LabelWorkflowRef: w.Name},
+		},
+		Spec: WorkflowSpec{
+			Entrypoint:         entrypointTemplateName,
+			ServiceAccountName: workflowServiceAccount,
+			Arguments:          &Arguments{},
+		},
+	}
+	if w.Description != "" {
+		wt.Annotations = map[string]string{"workflows.argoproj.io/description": w.Description}
+	}
+	// record the extensions the steps are bound to on the workflows created from the template, without the
+	// credentials as they depend on the codeset project of the workflows
+	if extensions := w.ExtensionReferences(); len(extensions) > 0 {
+		for _, extension := range extensions {
+			extension.CredentialsID = ""
+		}
+		wt.Spec.WorkflowMetadata = &metav1.ObjectMeta{
+			Annotations: map[string]string{AnnotationWorkflowExtensions: extensionsAnnotation(extensions)},
+		}
+	}
+
+	dag := &DAGTemplate{}
+	templates := []Template{}
+	// each task depends on the previous one, so that steps run in the order they are defined
+	previousTask := ""
+	addTask := func(task DAGTask) {
+		if previousTask != "" {
+			task.Dependencies = []string{previousTask}
+		}
+		dag.Tasks = append(dag.Tasks, task)
+		previousTask = task.Name
+	}
+
+	// process the FuseML workflow inputs
+	hasCodesetInput := false
+	for _, input := range w.Inputs {
+		// an input of the 'codeset' type means a git repository cloned into a volume shared by the
+		// workflow steps. Also add 'codeset-name', 'codeset-version', 'codeset-project' and
+		// 'codeset-url' parameters to the workflow template.
+		if input.Type == domain.WorkflowIOTypeCodeset {
+			hasCodesetInput = true
+			wt.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{codesetVolumeClaim()}
+			wt.Spec.Arguments.Parameters = append(wt.Spec.Arguments.Parameters,
+				Parameter{Name: codesetNameParam, Description: "Reference to the codeset (git project)"},
+				Parameter{Name: codesetVersionParam, Description: "Codeset version (git revision)",
+					Default: stringPtr(defaultCodesetVersion)},
+				Parameter{Name: codesetProjectParam, Description: "Reference to the codeset project (git organization)"},
+				Parameter{Name: codesetURLParam, Description: "The codeset URL (git repository URL)"})
+			resolver.AddReference(fmt.Sprintf("inputs.%s.name", input.Name), workflowParamRef(codesetNameParam))
+			resolver.AddReference(fmt.Sprintf("inputs.%s.version", input.Name), workflowParamRef(codesetVersionParam))
+			resolver.AddReference(fmt.Sprintf("inputs.%s.project", input.Name), workflowParamRef(codesetProjectParam))
+			templates = append(templates, cloneTemplate())
+			addTask(DAGTask{Name: cloneTemplateName, Template: cloneTemplateName})
+		} else {
+			wt.Spec.Arguments.Parameters = append(wt.Spec.Arguments.Parameters,
+				Parameter{Name: input.Name, Description: input.Description, Default: stringPtr(input.Default)})
+			resolver.AddReference(fmt.Sprintf("inputs.%s", input.Name), workflowParamRef(input.Name))
+		}
+	}
+
+	// process the FuseML workflow steps
+	hasCredentials := false
+STEPS:
+	for _, step := range w.Steps {
+		for _, output := range step.Outputs {
+			// image type outputs are built by two templates, the first one (prep) uses the image defined
+			// on the step to provide a Dockerfile that is used by the following one (kaniko) to build the
+			// image expected by the step output.
+			if output.Image != nil {
+				var dockerfile string
+				if output.Image.Dockerfile != "" {
+					dockerfile = resolver.Resolve(output.Image.Dockerfile)
+					// in FuseML workflow the dockerfile path is a full path including the codeset.path, however
+					// the builder mounts the codeset at its own path and expects the dockerfile path to be
+					// referenced from it. So, remove the codeset.path from image.dockerfile input
+					if codesetPath := getInputCodesetPath(step.Inputs); codesetPath != "" {
+						dockerfile = strings.Replace(dockerfile, fmt.Sprintf("%s/", codesetPath), "", 1)
+					}
+				}
+				prepName := fmt.Sprintf("%s-prep", step.Name)
+				templates = append(templates, builderPrepTemplate(prepName, resolver.Resolve(step.Image)),
+					builderTemplate(step.Name))
+				addTask(DAGTask{Name: prepName, Template: prepName,
+					Arguments: &Arguments{Parameters: []Parameter{{Name: dockerfileParamName, Value: stringPtr(dockerfile)}}}})
+				addTask(DAGTask{Name: step.Name, Template: step.Name,
+					Arguments: &Arguments{Parameters: []Parameter{
+						{Name: imageParamName, Value: stringPtr(resolver.Resolve(output.Image.Name))},
+						{Name: dockerfileParamName, Value: stringPtr(taskOutputRef(prepName, dockerfilePathResult))},
+					}}})
+				resolver.AddReference(fmt.Sprintf("steps.%s.outputs.%s", step.Name, output.Name), output.Image.Name)
+				continue STEPS
+			}
+		}
+
+		envVars := EnvVarMap{
+			envVarPrefix + "WORKFLOW_NAMESPACE": namespace,
+			envVarPrefix + "WORKFLOW_NAME":      w.Name,
+		}
+		// let the steps know the codeset being processed, e.g. to record it when registering applications
+		if hasCodesetInput {
+			envVars[envVarPrefix+"CODESET_PROJECT"] = workflowParamRef(codesetProjectParam)
+			envVars[envVarPrefix+"CODESET_NAME"] = workflowParamRef(codesetNameParam)
+		}
+		// maps environment variables to the keys of the credentials secret holding their values
+		secretEnvVars := map[string]string{}
+		stepResolver := resolver.Clone()
+		for _, extension := range step.Extensions {
+			addExtensionReferences(step.Name, extension, stepResolver, envVars, secretEnvVars)
+		}
+		hasCredentials = hasCredentials || len(secretEnvVars) > 0
+
+		templates = append(templates, toStepTemplate(step, stepResolver, envVars, secretEnvVars))
+		args := &Arguments{}
+		for _, input := range step.Inputs {
+			if input.Codeset == nil {
+				args.Parameters = append(args.Parameters, Parameter{Name: input.Name,
+					Value: stringPtr(resolver.Resolve(input.Value))})
+			}
+		}
+		// if image is parametrized add 'IMAGE' param, resolving it
+		if strings.Contains(step.Image, "{{") {
+			// The kubernetes nodes are unable to resolve the local FuseML registry
+			// (registry.fuseml-registry), in that way, when the step uses an image
+			// from the local FuseML registry, replace registry.fuseml-registry with
+			// 127.0.0.1:30500
+			image := resolver.Resolve(step.Image)
+			if strings.HasPrefix(image, domain.RegistryHostname) {
+				image = strings.Replace(image, domain.RegistryHostname, fuseMLRegistryLocal, 1)
+			}
+			args.Parameters = append(args.Parameters, Parameter{Name: imageParamName, Value: stringPtr(image)})
+		}
+		if len(args.Parameters) == 0 {
+			args = nil
+		}
+		addTask(DAGTask{Name: step.Name, Template: step.Name, Arguments: args})
+	}
+
+	// the workflows select the secret holding the credentials of the project and user they run for
+	if hasCredentials {
+		wt.Spec.Arguments.Parameters = append(wt.Spec.Arguments.Parameters, Parameter{Name: credentialsSecretParam,
+			Description: "Name of the secret holding the extension credentials",
+			Default:     stringPtr(credentialsSecretName(w.Name, "", ""))})
+	}
+	if len(w.Steps) > 0 {
+		wt.Spec.Volumes = []corev1.Volume{{Name: resultsVolumeName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+	}
+	if len(wt.Spec.Arguments.Parameters) == 0 {
+		wt.Spec.Arguments = nil
+	}
+	wt.Spec.Templates = append([]Template{{Name: entrypointTemplateName, DAG: dag}}, templates...)
+	return wt
+}
+
+func generateWorkflow(wt *WorkflowTemplate, codeset *domain.Codeset, options *domain.WorkflowRunOptions) (*Workflow, error) {
+	codesetVersion := defaultCodesetVersion
+	inputs := map[string]string{}
+	user := ""
+	var extensions []*domain.WorkflowRunExtension
+	if options != nil {
+		if options.CodesetRevision != "" {
+			codesetVersion = options.CodesetRevision
+		}
+		if options.Inputs != nil {
+			inputs = options.Inputs
+		}
+		user = options.User
+		extensions = options.Extensions
+	}
+
+	wf := &Workflow{
+		TypeMeta: metav1.TypeMeta{APIVersion: apiVersion, Kind: workflowKind},
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s%s-%s-", workflowRunPrefix, codeset.Project, codeset.Name),
+			Namespace:    wt.Namespace,
+			Labels: map[string]string{
+				LabelCodesetName:    codeset.Name,
+				LabelCodesetProject: codeset.Project,
+				LabelCodesetVersion: codesetVersion,
+				LabelWorkflowRef:    wt.Labels[LabelWorkflowRef],
+			},
+		},
+		Spec: WorkflowSpec{WorkflowTemplateRef: &WorkflowTemplateRef{Name: wt.Name}},
+	}
+	if len(extensions) > 0 || user != "" {
+		wf.Annotations = map[string]string{}
+	}
+	if len(extensions) > 0 {
+		wf.Annotations[AnnotationExtensions] = extensionsAnnotation(extensions)
+	}
+	if user != "" {
+		wf.Annotations[AnnotationUser] = user
+	}
+
+	if wt.Spec.Arguments == nil {
+		return wf, nil
+	}
+	wf.Spec.Arguments = &Arguments{}
+	for _, param := range wt.Spec.Arguments.Parameters {
+		var value string
+		switch param.Name {
+		case codesetNameParam:
+			value = codeset.Name
+		case codesetVersionParam:
+			value = codesetVersion
+		case codesetProjectParam:
+			value = codeset.Project
+		case codesetURLParam:
+			value = codeset.URL
+		case credentialsSecretParam:
+			value = credentialsSecretName(wt.Name, codeset.Project, user)
+		default:
+			if v, ok := inputs[param.Name]; ok {
+				value = v
+			} else if param.Default != nil {
+				value = *param.Default
+			} else {
+				return nil, fmt.Errorf("workflow run failed: could not set parameter value for %q", param.Name)
+			}
+		}
+		wf.Spec.Arguments.Parameters = append(wf.Spec.Arguments.Parameters, Parameter{Name: param.Name, Value: stringPtr(value)})
+	}
+	return wf, nil
+}
+
+func generateEventSource(wt *WorkflowTemplate) *EventSource {
+	return &EventSource{
+		TypeMeta:   metav1.TypeMeta{APIVersion: apiVersion, Kind: eventSourceKind},
+		ObjectMeta: metav1.ObjectMeta{Name: wt.Name, Namespace: wt.Namespace},
+		Spec: EventSourceSpec{
+			Service: &Service{Ports: []corev1.ServicePort{{Port: webhookPort, TargetPort: intstr.FromInt(webhookPort)}}},
+			Webhook: map[string]WebhookContext{
+				webhookEventName: {Endpoint: webhookEndpoint, Method: "POST", Port: fmt.Sprint(webhookPort)},
+			},
+		},
+	}
+}
+
+// generateSensor generates a sensor that creates an argo Workflow from the workflow template every time the
+// event source receives an event, setting the codeset parameters and labels from the event payload
+func generateSensor(wt *WorkflowTemplate, eventSource *EventSource) (*Sensor, error) {
+	webhookParamsMap := map[string]string{
+		codesetNameParam:    "body.repository.name",
+		codesetVersionParam: "body.after",
+		codesetProjectParam: "body.repository.owner.username",
+		codesetURLParam:     "body.repository.clone_url",
+	}
+	labelsMap := map[string]string{
+		codesetNameParam:    LabelCodesetName,
+		codesetVersionParam: LabelCodesetVersion,
+		codesetProjectParam: LabelCodesetProject,
+	}
+
+	wf := &Workflow{
+		TypeMeta: metav1.TypeMeta{APIVersion: apiVersion, Kind: workflowKind},
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s%s-", workflowRunPrefix, wt.Name),
+			Namespace:    wt.Namespace,
+			Labels:       map[string]string{LabelWorkflowRef: wt.Labels[LabelWorkflowRef]},
+		},
+		Spec: WorkflowSpec{WorkflowTemplateRef: &WorkflowTemplateRef{Name: wt.Name}},
+	}
+
+	parameters := []TriggerParameter{}
+	if wt.Spec.Arguments != nil {
+		wf.Spec.Arguments = &Arguments{}
+		for i, param := range wt.Spec.Arguments.Parameters {
+			value := ""
+			if param.Default != nil {
+				value = *param.Default
+			}
+			if param.Name == credentialsSecretParam {
+				// the workflow uses the credentials of the project of the codeset the event comes from
+				value = credentialsSecretName(wt.Name, "", "") + "."
+				parameters = append(parameters, TriggerParameter{
+					Src:       &TriggerParameterSource{DependencyName: webhookEventName, DataKey: webhookParamsMap[codesetProjectParam]},
+					Dest:      fmt.Sprintf("spec.arguments.parameters.%d.value", i),
+					Operation: "append",
+				})
+			}
+			wf.Spec.Arguments.Parameters = append(wf.Spec.Arguments.Parameters, Parameter{Name: param.Name, Value: stringPtr(value)})
+			dataKey, ok := webhookParamsMap[param.Name]
+			if !ok {
+				continue
+			}
+			src := &TriggerParameterSource{DependencyName: webhookEventName, DataKey: dataKey}
+			parameters = append(parameters, TriggerParameter{Src: src, Dest: fmt.Sprintf("spec.arguments.parameters.%d.value", i)})
+			if label, ok := labelsMap[param.Name]; ok {
+				parameters = append(parameters, TriggerParameter{Src: src, Dest: fmt.Sprintf("metadata.labels.%s", label)})
+			}
+		}
+	}
+
+	resource, err := toUnstructured(wf)
+	if err != nil {
+		return nil, err
+	}
+	// the workflow is created by the sensor, so it has no creation timestamp nor status
+	unstructured.RemoveNestedField(resource, "metadata", "creationTimestamp")
+	delete(resource, "status")
+
+	return &Sensor{
+		TypeMeta:   metav1.TypeMeta{APIVersion: apiVersion, Kind: sensorKind},
+		ObjectMeta: metav1.ObjectMeta{Name: wt.Name, Namespace: wt.Namespace},
+		Spec: SensorSpec{
+			Template: &SensorTemplate{ServiceAccountName: sensorServiceAccount},
+			Dependencies: []EventDependency{
+				{Name: webhookEventName, EventSourceName: eventSource.Name, EventName: webhookEventName},
+			},
+			Triggers: []Trigger{{Template: &TriggerTemplate{
+				Name: wt.Name,
+				K8s: &StandardK8STrigger{
+					Operation:  "create",
+					Source:     &ArtifactLocation{Resource: resource},
+					Parameters: parameters,
+				},
+			}}},
+		},
+	}, nil
+}
+
+// cloneTemplate clones the codeset revision into the volume shared by the workflow steps
+func cloneTemplate() Template {
+	return Template{
+		Name: cloneTemplateName,
+		Container: &corev1.Container{
+			Name:    stepContainerName,
+			Image:   gitImage,
+			Command: []string{"sh", "-c"},
+			Args: []string{fmt.Sprintf(`git clone "$CODESET_URL" /tmp/codeset && `+
+				`git -C /tmp/codeset checkout "$CODESET_VERSION" && cp -a /tmp/codeset/. %s`, codesetClonePath)},
+			Env: []corev1.EnvVar{
+				{Name: "CODESET_URL", Value: workflowParamRef(codesetURLParam)},
+				{Name: "CODESET_VERSION", Value: workflowParamRef(codesetVersionParam)},
+			},
+			VolumeMounts: []corev1.VolumeMount{{Name: codesetVolumeName, MountPath: codesetClonePath}},
+		},
+	}
+}
+
+// builderPrepTemplate runs the step image to provide the Dockerfile used to build the step output image
+func builderPrepTemplate(name, image string) Template {
+	return Template{
+		Name:    name,
+		Inputs:  &Inputs{Parameters: []Parameter{{Name: dockerfileParamName}}},
+		Outputs: &Outputs{Parameters: []Parameter{{Name: dockerfilePathResult, ValueFrom: &ValueFrom{Path: resultPath(dockerfilePathResult)}}}},
+		Container: &corev1.Container{
+			Name:       stepContainerName,
+			Image:      image,
+			Command:    []string{stepDefaultCmd},
+			WorkingDir: codesetClonePath,
+			Env: []corev1.EnvVar{
+				{Name: dockerfileParamName, Value: inputParamRef(dockerfileParamName)},
+				{Name: stepOutputVarName, Value: dockerfilePathResult},
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				{Name: codesetVolumeName, MountPath: codesetClonePath},
+				{Name: resultsVolumeName, MountPath: resultsPath},
+			},
+		},
+	}
+}
+
+// builderTemplate builds the step output image with kaniko and pushes it to the registry
+func builderTemplate(name string) Template {
+	return Template{
+		Name:   name,
+		Inputs: &Inputs{Parameters: []Parameter{{Name: imageParamName}, {Name: dockerfileParamName}}},
+		Container: &corev1.Container{
+			Name:  stepContainerName,
+			Image: builderImage,
+			Args: []string{
+				fmt.Sprintf("--dockerfile=%s", inputParamRef(dockerfileParamName)),
+				fmt.Sprintf("--context=dir://%s", codesetClonePath),
+				fmt.Sprintf("--destination=%s", inputParamRef(imageParamName)),
+				"--insecure",
+				"--skip-tls-verify",
+			},
+			VolumeMounts: []corev1.VolumeMount{{Name: codesetVolumeName, MountPath: codesetClonePath}},
+		},
+	}
+}
+
+func toStepTemplate(step *domain.WorkflowStep, resolver *variables.Resolver, envVars EnvVarMap,
+	secretEnvVars map[string]string) Template {
+	t := Template{Name: step.Name, Inputs: &Inputs{}, Outputs: &Outputs{}}
+	c := &corev1.Container{Name: stepContainerName, Image: step.Image, Command: []string{stepDefaultCmd}, Args: step.Args}
+	if step.Entrypoint != "" {
+		c.Command = []string{step.Entrypoint}
+	}
+	env := map[string]string{}
+
+	for _, input := range step.Inputs {
+		// if there is a codeset as input, mount the codeset volume and set the working directory to codeset.path
+		if input.Codeset != nil {
+			c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{Name: codesetVolumeName, MountPath: input.Codeset.Path})
+			c.WorkingDir = input.Codeset.Path
+		} else {
+			// else add it as a parameter to the template, making it also available as env variable
+			// (with the FUSEML_ prefix) so that the container can use it
+			t.Inputs.Parameters = append(t.Inputs.Parameters, Parameter{Name: input.Name})
+			env[fmt.Sprintf("%s%s", inputsVarPrefix, strings.ToUpper(input.Name))] = inputParamRef(input.Name)
+		}
+	}
+
+	// if image is parameterized reference it as a template parameter to be able
+	// to receive its value from a task output
+	if strings.Contains(step.Image, "{{") {
+		t.Inputs.Parameters = append(t.Inputs.Parameters, Parameter{Name: imageParamName})
+		c.Image = inputParamRef(imageParamName)
+	}
+
+	// a workflow output that is not an image represents an output parameter read from the results
+	// volume. Also adds an environment variable with the variable name so that the container can set
+	// the output
+	for _, output := range step.Outputs {
+		if output.Image == nil {
+			t.Outputs.Parameters = append(t.Outputs.Parameters, Parameter{Name: output.Name,
+				ValueFrom: &ValueFrom{Path: resultPath(output.Name)}})
+			env[stepOutputVarName] = output.Name
+		}
+	}
+	if len(t.Outputs.Parameters) > 0 {
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{Name: resultsVolumeName, MountPath: resultsPath})
+	}
+
+	for k, v := range envVars {
+		env[k] = v
+	}
+	for _, stepEnv := range step.Env {
+		// step environment variables override those set elsewhere
+		env[stepEnv.Name] = resolver.Resolve(stepEnv.Value)
+	}
+	// the env variables holding credentials are set first so that they can be referenced by the others.
+	// They are optional, as the credentials bound for a project or user may not set them all
+	secretEnv := []corev1.EnvVar{}
+	for k, key := range secretEnvVars {
+		if _, ok := env[k]; ok {
+			continue
+		}
+		secretEnv = append(secretEnv, corev1.EnvVar{Name: k, ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: workflowParamRef(credentialsSecretParam)},
+				Key:                  key,
+				Optional:             boolPtr(true),
+			}}})
+	}
+	for k, v := range env {
+		c.Env = append(c.Env, corev1.EnvVar{Name: k, Value: v})
+	}
+	sort.Slice(c.Env, func(i, j int) bool { return c.Env[i].Name < c.Env[j].Name })
+	sort.Slice(secretEnv, func(i, j int) bool { return secretEnv[i].Name < secretEnv[j].Name })
+	c.Env = append(secretEnv, c.Env...)
+
+	if len(step.Resources.Requests) > 0 {
+		c.Resources.Requests = toResourceList(step.Resources.Requests)
+	}
+	if len(step.Resources.Limits) > 0 {
+		c.Resources.Limits = toResourceList(step.Resources.Limits)
+	}
+
+	if len(t.Inputs.Parameters) == 0 {
+		t.Inputs = nil
+	}
+	if len(t.Outputs.Parameters) == 0 {
+		t.Outputs = nil
+	}
+	t.Container = c
+	return t
+}
+
+// addExtensionReferences adds references to the extension fields and sets its configuration as environment variables.
+// The credentials are not set in the workflow template, the environment variables take their values from the
+// credentials secret of the project and user the workflow runs for and references are expanded from them.
+func addExtensionReferences(stepName string, extension *domain.WorkflowStepExtension, resolver *variables.Resolver,
+	envVars EnvVarMap, secretEnvVars map[string]string) {
+	access := extension.ExtensionAccess
+	resolver.AddReference(fmt.Sprintf("extensions.%s.product", extension.Name), access.Extension.Product)
+	resolver.AddReference(fmt.Sprintf("extensions.%s.zone", extension.Name), access.Extension.Zone)
+	resolver.AddReference(fmt.Sprintf("extensions.%s.version", extension.Name), access.Extension.Version)
+	resolver.AddReference(fmt.Sprintf("extensions.%s.service_resource", extension.Name), access.Service.Resource)
+	resolver.AddReference(fmt.Sprintf("extensions.%s.service_category", extension.Name), access.Service.Category)
+	resolver.AddReference(fmt.Sprintf("extensions.%s.url", extension.Name), access.Endpoint.URL)
+	configurations := []map[string]string{access.Extension.Configuration, access.Service.Configuration,
+		access.Endpoint.Configuration}
+	for _, configuration := range configurations {
+		for k, v := range configuration {
+			envVars[k] = v
+			resolver.AddReference(fmt.Sprintf("extensions.%s.cfg.%s", extension.Name, k), v)
+		}
+	}
+	if access.Credentials != nil {
+		for k := range access.Credentials.Configuration {
+			delete(envVars, k)
+			secretEnvVars[k] = credentialsSecretKey(stepName, extension.Name, k)
+			resolver.AddReference(fmt.Sprintf("extensions.%s.cfg.%s", extension.Name, k), fmt.Sprintf("$(%s)", k))
+		}
+	}
+}
+
+func (w *WorkflowBackend) toWorkflowRun(wf *domain.Workflow, run *Workflow) *domain.WorkflowRun {
+	wfr := domain.WorkflowRun{
+		Name:           run.Name,
+		WorkflowRef:    wf.Name,
+		CodesetProject: run.Labels[LabelCodesetProject],
+		User:           run.Annotations[AnnotationUser],
+		Status:         workflowStatus(run),
+		URL:            fmt.Sprintf("%s/workflows/%s/%s", w.serverURL, w.namespace, run.Name),
+		Extensions:     runExtensions(run.Annotations),
+	}
+	if run.Status != nil && run.Status.StartedAt != nil {
+		wfr.StartTime = run.Status.StartedAt.Time
+	}
+	if run.Status != nil && run.Status.FinishedAt != nil {
+		wfr.CompletionTime = run.Status.FinishedAt.Time
+	}
+
+	params := map[string]string{}
+	for _, param := range workflowParameters(run) {
+		if param.Value != nil {
+			params[param.Name] = *param.Value
+		}
+	}
+	for _, input := range wf.Inputs {
+		value := params[input.Name]
+		if input.Type == domain.WorkflowIOTypeCodeset {
+			value = fmt.Sprintf("%s:%s", params[codesetURLParam], params[codesetVersionParam])
+		}
+		wfr.Inputs = append(wfr.Inputs, &domain.WorkflowRunInput{Input: input, Value: value})
+	}
+
+	// the workflow outputs are the outputs from the steps with the same name
+	results := map[string]string{}
+	for _, node := range taskNodes(run) {
+		if node.Outputs == nil {
+			continue
+		}
+		for _, output := range node.Outputs.Parameters {
+			if output.Value != nil {
+				results[output.Name] = *output.Value
+			}
+		}
+	}
+	for _, output := range wf.Outputs {
+		wfr.Outputs = append(wfr.Outputs, &domain.WorkflowRunOutput{Output: output, Value: results[output.Name]})
+	}
+	return &wfr
+}
+
+// extensionsAnnotation returns the value of the annotation recording the extensions the workflow steps are bound to
+func extensionsAnnotation(extensions []*domain.WorkflowRunExtension) string {
+	value, err := json.Marshal(extensions)
+	if err != nil {
+		log.Fatalf("Error marshalling extensions: %s", err)
+	}
+	return string(value)
+}
+
+// runExtensions returns the extensions the steps of an argo workflow are bound to. The workflows created by
+// the sensor only have the annotation set from the workflow template, without the credentials.
+func runExtensions(annotations map[string]string) []*domain.WorkflowRunExtension {
+	value, ok := annotations[AnnotationExtensions]
+	if !ok {
+		value, ok = annotations[AnnotationWorkflowExtensions]
+	}
+	if !ok {
+		return nil
+	}
+	extensions := []*domain.WorkflowRunExtension{}
+	if err := json.Unmarshal([]byte(value), &extensions); err != nil {
+		return nil
+	}
+	return extensions
+}
+
+// toWorkflowRunSteps returns the status of the tasks executed by an argo workflow, sorted by their start time
+func toWorkflowRunSteps(run *Workflow) []*domain.WorkflowRunStep {
+	steps := []*domain.WorkflowRunStep{}
+	for _, node := range taskNodes(run) {
+		step := &domain.WorkflowRunStep{
+			Name:   node.DisplayName,
+			Status: phaseToStatus(node.Phase),
+		}
+		if node.StartedAt != nil {
+			step.StartTime = node.StartedAt.Time
+		}
+		if node.FinishedAt != nil {
+			step.CompletionTime = node.FinishedAt.Time
+		}
+		if node.Outputs != nil && len(node.Outputs.Parameters) > 0 {
+			step.Results = map[string]string{}
+			for _, output := range node.Outputs.Parameters {
+				if output.Value != nil {
+					step.Results[output.Name] = strings.TrimSpace(*output.Value)
+				}
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// taskNodes returns the nodes of an argo workflow that executed a DAG task, sorted by their start time
+func taskNodes(run *Workflow) []NodeStatus {
+	nodes := []NodeStatus{}
+	if run.Status == nil {
+		return nodes
+	}
+	for _, node := range run.Status.Nodes {
+		if node.Type == "Pod" {
+			nodes = append(nodes, node)
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		si, sj := nodeStartTime(nodes[i]), nodeStartTime(nodes[j])
+		if si.Equal(sj) {
+			return nodes[i].DisplayName < nodes[j].DisplayName
+		}
+		return si.Before(sj)
+	})
+	return nodes
+}
+
+func nodeStartTime(node NodeStatus) time.Time {
+	if node.StartedAt == nil {
+		return time.Time{}
+	}
+	return node.StartedAt.Time
+}
+
+func workflowParameters(run *Workflow) []Parameter {
+	if run.Spec.Arguments == nil {
+		return nil
+	}
+	return run.Spec.Arguments.Parameters
+}
+
+func workflowDone(run *Workflow) bool {
+	return run.Status != nil && run.Status.FinishedAt != nil && !run.Status.FinishedAt.IsZero()
+}
+
+// workflowStatus returns the status of an argo workflow, a terminated workflow is reported as cancelled
+func workflowStatus(run *Workflow) string {
+	if run.Status == nil || run.Status.Phase == "" {
+		return "Unknown"
+	}
+	if run.Spec.Shutdown != "" && workflowDone(run) && run.Status.Phase != "Succeeded" {
+		return "Cancelled"
+	}
+	return phaseToStatus(run.Status.Phase)
+}
+
+func phaseToStatus(phase string) string {
+	expectedStatus := []string{"Succeeded", "Running", "Pending", "Failed"}
+	// If it is not an expected phase it means that the workflow failed and the status is the phase
+	// describing how it failed (e.g. Error, Omitted)
+	if !util.StringInSlice(phase, expectedStatus) {
+		return fmt.Sprintf("Failed (%s)", phase)
+	}
+	return phase
+}
+
+// newVariablesResolver initializes a resolver that translates references to step outputs to the argo way of
+// getting a task output: "{{tasks.TASK.outputs.parameters.VARIABLE}}"
+func newVariablesResolver() *variables.Resolver {
+	return variables.NewResolver(func(reference string) string {
+		parts := strings.SplitN(reference, ".", 4)
+		if len(parts) != 4 {
+			return reference
+		}
+		return taskOutputRef(parts[1], parts[3])
+	})
+}
+
+func workflowParamRef(name string) string {
+	return fmt.Sprintf("{{workflow.parameters.%s}}", name)
+}
+
+func inputParamRef(name string) string {
+	return fmt.Sprintf("{{inputs.parameters.%s}}", name)
+}
+
+func taskOutputRef(task, output string) string {
+	return fmt.Sprintf("{{tasks.%s.outputs.parameters.%s}}", task, output)
+}
+
+func resultPath(name string) string {
+	return fmt.Sprintf("%s/%s", resultsPath, name)
+}
+
+func isCodesetParam(name string) bool {
+	return name == codesetNameParam || name == codesetVersionParam || name == codesetProjectParam ||
+		name == codesetURLParam
+}
+
+func getInputCodesetPath(inputs []*domain.WorkflowStepInput) string {
+	for _, input := range inputs {
+		if input.Codeset != nil {
+			return input.Codeset.Path
+		}
+	}
+	return ""
+}
+
+func codesetVolumeClaim() corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: codesetVolumeName},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(codesetVolumeSize)},
+			},
+		},
+	}
+}
+
+func toResourceList(resources map[string]string) corev1.ResourceList {
+	res := corev1.ResourceList{}
+	for k, v := range resources {
+		res[corev1.ResourceName(k)] = resource.MustParse(v)
+	}
+	return res
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,958 @@
+package argo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+const (
+	fuseMLWorkflow           = "testdata/workflow.yaml"
+	wantArgoWorkflowTemplate = "testdata/argo-workflow-template.yaml"
+	wantArgoWorkflow         = "testdata/argo-workflow.yaml"
+	wantArgoEventSource      = "testdata/argo-event-source.yaml"
+	wantArgoSensor           = "testdata/argo-sensor.yaml"
+	testNamespace            = "test-namespace"
+	testServerURL            = "http://argo.test"
+)
+
+func TestCreateWorkflow(t *testing.T) {
+	t.Run("new workflow", func(t *testing.T) {
+		ctx, b, logsOutput := initBackend(t)
+
+		w := domain.Workflow{}
+		readYaml(t, fuseMLWorkflow, &w)
+
+		err := b.CreateWorkflow(ctx, &w)
+
+		assertError(t, err, nil)
+		assertStrings(t, strings.TrimSuffix(logsOutput.String(), "\n"), "Creating argo workflow template for workflow: mlflow-sklearn-e2e...")
+
+		got := WorkflowTemplate{}
+		b.getObject(ctx, t, b.argoClients.WorkflowTemplateClient, w.Name, &got)
+
+		want := WorkflowTemplate{}
+		readYaml(t, wantArgoWorkflowTemplate, &want)
+
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected WorkflowTemplate (-want +got): %s", d)
+		}
+	})
+
+	t.Run("existing workflow", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+
+		w := domain.Workflow{}
+		readYaml(t, fuseMLWorkflow, &w)
+
+		err := b.CreateWorkflow(ctx, &w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := b.CreateWorkflow(ctx, &w)
+		assertError(t, got, domain.ErrWorkflowExists)
+	})
+}
+
+func TestUpdateWorkflow(t *testing.T) {
+	t.Run("existing workflow", func(t *testing.T) {
+		ctx, b, logsOutput := initBackend(t)
+		w := b.createTestWorkflow(ctx, t)
+		b.createTestListener(ctx, t, w.Name, true)
+		logsOutput.Reset()
+
+		access := *w.Steps[1].Extensions[0].ExtensionAccess
+		access.Endpoint.URL = "http://mlflow-tracking:5000"
+		w.Steps[1].Extensions[0].ExtensionAccess = &access
+		err := b.UpdateWorkflow(ctx, w)
+		assertError(t, err, nil)
+
+		got := WorkflowTemplate{}
+		b.getObject(ctx, t, b.argoClients.WorkflowTemplateClient, w.Name, &got)
+		want := generateWorkflowTemplate(*w, testNamespace)
+		if d := cmp.Diff(want.Spec, got.Spec); d != "" {
+			t.Errorf("Unexpected WorkflowTemplate (-want +got): %s", d)
+		}
+		extensions := runExtensions(got.Spec.WorkflowMetadata.Annotations)
+		if len(extensions) == 0 || extensions[0].EndpointURL != access.Endpoint.URL {
+			t.Errorf("Expected the workflow template to record the updated extension endpoint, got %v",
+				got.Spec.WorkflowMetadata.Annotations)
+		}
+
+		// the sensor is regenerated, keeping its status
+		listener, err := b.GetWorkflowListener(ctx, w.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !listener.Available {
+			t.Errorf("Expected WorkflowListener to remain available")
+		}
+
+		expectedLog := fmt.Sprintf("Updating argo workflow template for workflow: %s...\n", w.Name)
+		assertStrings(t, logsOutput.String(), expectedLog)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+
+		w := domain.Workflow{}
+		readYaml(t, fuseMLWorkflow, &w)
+
+		err := b.UpdateWorkflow(ctx, &w)
+		assertError(t, err, domain.ErrWorkflowNotFound)
+	})
+}
+
+func TestDeleteWorkflow(t *testing.T) {
+	t.Run("delete", func(t *testing.T) {
+		ctx, b, logsOutput := initBackend(t)
+
+		w := domain.Workflow{}
+		readYaml(t, fuseMLWorkflow, &w)
+
+		err := b.CreateWorkflow(ctx, &w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = b.SetWorkflowCredentials(ctx, &w, "workspace", ""); err != nil {
+			t.Fatal(err)
+		}
+		logsOutput.Reset()
+
+		err = b.DeleteWorkflow(ctx, w.Name)
+
+		assertError(t, err, nil)
+
+		templates, err := b.argoClients.WorkflowTemplateClient.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(templates.Items) > 0 {
+			t.Errorf("Expected 0 WorkflowTemplate, got %d", len(templates.Items))
+		}
+		secrets, err := b.argoClients.SecretClient.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(secrets.Items) > 0 {
+			t.Errorf("Expected 0 Secret, got %d", len(secrets.Items))
+		}
+
+		expectedLog := fmt.Sprintf("Deleting argo workflow template: %s...\n", w.Name)
+		assertStrings(t, logsOutput.String(), expectedLog)
+	})
+
+	t.Run("skip not found", func(t *testing.T) {
+		ctx, b, logsOutput := initBackend(t)
+
+		name := "TestWorkflow"
+		err := b.DeleteWorkflow(ctx, name)
+
+		assertError(t, err, nil)
+
+		expectedLog := fmt.Sprintf(`Deleting argo workflow template: %s...
+Argo workflow template %q not found, skipping delete...
+`, name, name)
+		assertStrings(t, logsOutput.String(), expectedLog)
+	})
+}
+
+func TestSetWorkflowCredentials(t *testing.T) {
+	ctx, b, _ := initBackend(t)
+
+	w := domain.Workflow{}
+	readYaml(t, fuseMLWorkflow, &w)
+	if err := b.CreateWorkflow(ctx, &w); err != nil {
+		t.Fatal(err)
+	}
+
+	err := b.SetWorkflowCredentials(ctx, &w, "workspace", "alice")
+	assertError(t, err, nil)
+
+	secret, err := b.argoClients.SecretClient.Get(ctx, "fuseml-mlflow-sklearn-e2e-credentials.workspace.alice", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get credentials Secret: %s", err)
+	}
+	want := map[string][]byte{}
+	for _, prefix := range []string{"trainer.mlflow-store.", "predictor.s3-storage."} {
+		want[prefix+"AWS_ACCESS_KEY_ID"] = []byte("gABTE5DmmLgjJypJzGFs")
+		want[prefix+"AWS_SECRET_ACCESS_KEY"] = []byte("uW1qiFS8DTFuACXCDrM7i5zLJXbbfXd6pReyntjn")
+	}
+	if d := cmp.Diff(want, secret.Data); d != "" {
+		t.Errorf("Unexpected Secret data (-want +got): %s", d)
+	}
+	assertStrings(t, secret.Labels[LabelCodesetProject], "workspace")
+}
+
+func TestCreateWorkflowRun(t *testing.T) {
+	cs := &domain.Codeset{
+		Name:    "mlflow-app-01",
+		Project: "workspace",
+		URL:     "http://gitea.10.160.5.140.nip.io/workspace/mlflow-app-01.git",
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		ctx, b, logsOutput := initBackend(t)
+		w := b.createTestWorkflow(ctx, t)
+		logsOutput.Reset()
+
+		_, err := b.CreateWorkflowRun(ctx, w.Name, cs, nil)
+		if err != nil {
+			t.Fatalf("Failed to create workflow run %q: %s", w.Name, err)
+		}
+
+		got := b.listWorkflows(ctx, t)
+		if len(got) != 1 {
+			t.Fatalf("Expected 1 Workflow, got %d", len(got))
+		}
+
+		want := Workflow{}
+		readYaml(t, wantArgoWorkflow, &want)
+		if d := cmp.Diff(want, got[0]); d != "" {
+			t.Errorf("Unexpected Workflow (-want +got): %s", d)
+		}
+
+		expectedLog := fmt.Sprintf("Creating argo workflow for workflow: %s...\n", w.Name)
+		assertStrings(t, logsOutput.String(), expectedLog)
+	})
+
+	t.Run("with options", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+		w := b.createTestWorkflow(ctx, t)
+
+		options := &domain.WorkflowRunOptions{
+			CodesetRevision: "v1.0",
+			Inputs:          map[string]string{"predictor": "kfserving", codesetVersionParam: "ignored"},
+			Extensions: []*domain.WorkflowRunExtension{{StepName: "trainer", Name: "mlflow-tracking", ExtensionID: "mlflow-0001",
+				ServiceID: "mlflow-tracking", EndpointURL: "http://mlflow"}},
+		}
+		_, err := b.CreateWorkflowRun(ctx, w.Name, cs, options)
+		if err != nil {
+			t.Fatalf("Failed to create workflow run %q: %s", w.Name, err)
+		}
+
+		got := b.listWorkflows(ctx, t)
+		if len(got) != 1 {
+			t.Fatalf("Expected 1 Workflow, got %d", len(got))
+		}
+
+		want := Workflow{}
+		readYaml(t, wantArgoWorkflow, &want)
+		want.Labels[LabelCodesetVersion] = "v1.0"
+		want.Annotations = map[string]string{AnnotationExtensions: extensionsAnnotation(options.Extensions)}
+		for i, param := range want.Spec.Arguments.Parameters {
+			switch param.Name {
+			case codesetVersionParam:
+				want.Spec.Arguments.Parameters[i].Value = stringPtr("v1.0")
+			case "predictor":
+				want.Spec.Arguments.Parameters[i].Value = stringPtr("kfserving")
+			}
+		}
+		if d := cmp.Diff(want, got[0]); d != "" {
+			t.Errorf("Unexpected Workflow (-want +got): %s", d)
+		}
+		if d := cmp.Diff(options.Extensions, b.toWorkflowRun(w, &got[0]).Extensions); d != "" {
+			t.Errorf("Unexpected WorkflowRun extensions (-want +got): %s", d)
+		}
+	})
+
+	t.Run("workflow not found", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+
+		_, err := b.CreateWorkflowRun(ctx, "unknown", cs, nil)
+		if err == nil {
+			t.Fatal("Expected an error creating a run for an unknown workflow")
+		}
+	})
+}
+
+func TestGetWorkflowRun(t *testing.T) {
+	t.Run("existing run", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+		w := b.createTestWorkflow(ctx, t)
+
+		cs := createCodeset(t, 1, 1)
+		startTime := time.Now().Truncate(time.Second)
+		completionTime := startTime.Add(time.Minute)
+		run := b.createTestWorkflowRun(ctx, t, w.Name, cs, "run", "Failed", startTime, completionTime)
+
+		cloneStart := metav1.NewTime(startTime)
+		cloneEnd := metav1.NewTime(startTime.Add(10 * time.Second))
+		trainerStart := metav1.NewTime(startTime.Add(20 * time.Second))
+		trainerEnd := metav1.NewTime(completionTime)
+		run.Status.Nodes = map[string]NodeStatus{
+			"run": {ID: "run", Name: "run", DisplayName: "run", Type: "DAG", Phase: "Failed"},
+			"run-2": {ID: "run-2", Name: "run.trainer", DisplayName: "trainer", Type: "Pod", Phase: "Error",
+				StartedAt: &trainerStart, FinishedAt: &trainerEnd,
+				Outputs: &Outputs{Parameters: []Parameter{{Name: "mlflow-model-url", Value: stringPtr("s3://model\n")}}}},
+			"run-1": {ID: "run-1", Name: "run.clone", DisplayName: "clone", Type: "Pod", Phase: "Succeeded",
+				StartedAt: &cloneStart, FinishedAt: &cloneEnd},
+		}
+		b.updateWorkflow(ctx, t, run)
+
+		got, err := b.GetWorkflowRun(ctx, "run")
+		if err != nil {
+			t.Fatalf("Failed to get workflow run: %s", err)
+		}
+
+		codesetInput := &domain.WorkflowInput{Type: domain.WorkflowIOTypeCodeset}
+		predictorInput := &domain.WorkflowInput{Name: "predictor", Type: domain.WorkflowIOTypeString}
+		modelOutput := &domain.WorkflowOutput{Name: "mlflow-model-url", Type: domain.WorkflowIOTypeString}
+		want := &domain.WorkflowRun{
+			Name:           "run",
+			WorkflowRef:    w.Name,
+			CodesetProject: cs.Project,
+			Status:         "Failed",
+			URL:            fmt.Sprintf("%s/workflows/%s/run", testServerURL, testNamespace),
+			StartTime:      startTime,
+			CompletionTime: completionTime,
+			Inputs: []*domain.WorkflowRunInput{
+				{Input: codesetInput, Value: fmt.Sprintf("%s:%s", cs.URL, defaultCodesetVersion)},
+				{Input: predictorInput, Value: "auto"},
+			},
+			Outputs: []*domain.WorkflowRunOutput{{Output: modelOutput, Value: "s3://model\n"}},
+			Steps: []*domain.WorkflowRunStep{
+				{Name: "clone", Status: "Succeeded", StartTime: cloneStart.Time, CompletionTime: cloneEnd.Time},
+				{Name: "trainer", Status: "Failed (Error)", StartTime: trainerStart.Time, CompletionTime: trainerEnd.Time,
+					Results: map[string]string{"mlflow-model-url": "s3://model"}},
+			},
+		}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected WorkflowRun (-want +got): %s", d)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+
+		_, err := b.GetWorkflowRun(ctx, "unknown")
+		assertError(t, err, domain.ErrWorkflowRunNotFound)
+	})
+}
+
+func TestGetWorkflowRuns(t *testing.T) {
+	ctx, b, _ := initBackend(t)
+	w := b.createTestWorkflow(ctx, t)
+
+	startTime := time.Now().Truncate(time.Second)
+	b.createTestWorkflowRun(ctx, t, w.Name, createCodeset(t, 1, 1), "run-1", "Succeeded", startTime, startTime.Add(time.Minute))
+	b.createTestWorkflowRun(ctx, t, w.Name, createCodeset(t, 2, 1), "run-2", "Running", startTime, startTime)
+	b.createTestWorkflowRun(ctx, t, w.Name, createCodeset(t, 1, 2), "run-3", "Failed", startTime, startTime.Add(time.Minute))
+
+	tests := []struct {
+		name   string
+		filter *domain.WorkflowRunFilter
+		want   []string
+	}{
+		{"all", &domain.WorkflowRunFilter{}, []string{"run-1", "run-2", "run-3"}},
+		{"by codeset name", &domain.WorkflowRunFilter{CodesetName: "mlflow-app-1"}, []string{"run-1", "run-3"}},
+		{"by codeset project", &domain.WorkflowRunFilter{CodesetProject: "workspace-1"}, []string{"run-1", "run-2"}},
+		{"by status", &domain.WorkflowRunFilter{Status: []string{"Running", "Failed"}}, []string{"run-2", "run-3"}},
+		{"no match", &domain.WorkflowRunFilter{CodesetName: "mlflow-app-1", Status: []string{"Running"}}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs, err := b.GetWorkflowRuns(ctx, w, tt.filter)
+			if err != nil {
+				t.Fatalf("Failed to get workflow runs: %s", err)
+			}
+			got := []string{}
+			for _, run := range runs {
+				got = append(got, run.Name)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("Unexpected WorkflowRuns (-want +got): %s", d)
+			}
+		})
+	}
+
+	t.Run("run fields", func(t *testing.T) {
+		runs, err := b.GetWorkflowRuns(ctx, w, &domain.WorkflowRunFilter{Status: []string{"Succeeded"}})
+		if err != nil {
+			t.Fatalf("Failed to get workflow runs: %s", err)
+		}
+		if len(runs) != 1 {
+			t.Fatalf("Expected 1 WorkflowRun, got %d", len(runs))
+		}
+		got := runs[0]
+		assertStrings(t, got.WorkflowRef, w.Name)
+		assertStrings(t, got.URL, fmt.Sprintf("%s/workflows/%s/run-1", testServerURL, testNamespace))
+		if !got.StartTime.Equal(startTime) || !got.CompletionTime.Equal(startTime.Add(time.Minute)) {
+			t.Errorf("Unexpected WorkflowRun times: %s - %s", got.StartTime, got.CompletionTime)
+		}
+		if len(got.Inputs) != 2 {
+			t.Fatalf("Expected 2 WorkflowRun inputs, got %d", len(got.Inputs))
+		}
+		assertStrings(t, got.Inputs[0].Value, "http://gitea.10.160.5.140.nip.io/mlflow-app-1/workspace-1.git:main")
+		assertStrings(t, got.Inputs[1].Value, "auto")
+	})
+}
+
+func TestStreamWorkflowRunLogs(t *testing.T) {
+	ctx, b, _ := initBackend(t)
+	w := b.createTestWorkflow(ctx, t)
+
+	cs := createCodeset(t, 1, 1)
+	startTime := time.Now()
+	run := b.createTestWorkflowRun(ctx, t, w.Name, cs, "run", "Failed", startTime, startTime.Add(time.Minute))
+	b.createTestWorkflowRun(ctx, t, w.Name, cs, "pending", "Running", startTime, startTime)
+
+	cloneStart := metav1.NewTime(startTime)
+	trainerStart := metav1.NewTime(startTime.Add(time.Second))
+	predictorStart := metav1.NewTime(startTime.Add(2 * time.Second))
+	run.Status.Nodes = map[string]NodeStatus{
+		"run-trainer-pod":   {ID: "run-trainer-pod", DisplayName: "trainer", Type: "Pod", StartedAt: &trainerStart},
+		"run-clone-pod":     {ID: "run-clone-pod", DisplayName: "clone", Type: "Pod", StartedAt: &cloneStart},
+		"run-predictor-pod": {ID: "run-predictor-pod", DisplayName: "predictor", Type: "Pod", StartedAt: &predictorStart},
+	}
+	b.updateWorkflow(ctx, t, run)
+
+	terminated := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}
+	waiting := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}
+	pods := []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "run-clone-pod"},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "wait", State: terminated}, {Name: "main", State: terminated}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "run-trainer-pod"},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "wait", State: terminated}, {Name: "main", State: terminated}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "run-predictor-pod"},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "wait", State: terminated}, {Name: "main", State: waiting}}},
+		},
+	}
+	for _, pod := range pods {
+		_, err := b.argoClients.PodClient.Create(ctx, pod, metav1.CreateOptions{})
+		if err != nil {
+			t.Fatalf("Failed to create pod: %s", err)
+		}
+	}
+
+	// the fake pod client always returns "fake logs" as the container logs
+	t.Run("all steps", func(t *testing.T) {
+		got := []*domain.WorkflowRunLog{}
+		err := b.StreamWorkflowRunLogs(ctx, "run", nil, func(log *domain.WorkflowRunLog) error {
+			got = append(got, log)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Failed to stream workflow run logs: %s", err)
+		}
+
+		want := []*domain.WorkflowRunLog{
+			{Step: "clone", Container: "main", Line: "fake logs"},
+			{Step: "trainer", Container: "main", Line: "fake logs"},
+		}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected WorkflowRunLog (-want +got): %s", d)
+		}
+	})
+
+	t.Run("single step", func(t *testing.T) {
+		got := []*domain.WorkflowRunLog{}
+		err := b.StreamWorkflowRunLogs(ctx, "run", &domain.WorkflowRunLogOptions{Step: "trainer"}, func(log *domain.WorkflowRunLog) error {
+			got = append(got, log)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Failed to stream workflow run logs: %s", err)
+		}
+
+		want := []*domain.WorkflowRunLog{{Step: "trainer", Container: "main", Line: "fake logs"}}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected WorkflowRunLog (-want +got): %s", d)
+		}
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		handlerErr := fmt.Errorf("connection closed")
+		err := b.StreamWorkflowRunLogs(ctx, "run", nil, func(log *domain.WorkflowRunLog) error {
+			return handlerErr
+		})
+		assertError(t, err, handlerErr)
+	})
+
+	t.Run("step not found", func(t *testing.T) {
+		err := b.StreamWorkflowRunLogs(ctx, "run", &domain.WorkflowRunLogOptions{Step: "unknown"}, nil)
+		assertError(t, err, domain.ErrWorkflowRunStepNotFound)
+	})
+
+	t.Run("no logs", func(t *testing.T) {
+		err := b.StreamWorkflowRunLogs(ctx, "pending", nil, nil)
+		assertError(t, err, domain.ErrWorkflowRunLogsNotFound)
+	})
+
+	t.Run("run not found", func(t *testing.T) {
+		err := b.StreamWorkflowRunLogs(ctx, "unknown", nil, nil)
+		assertError(t, err, domain.ErrWorkflowRunNotFound)
+	})
+}
+
+func TestCancelWorkflowRun(t *testing.T) {
+	ctx, b, _ := initBackend(t)
+	w := b.createTestWorkflow(ctx, t)
+
+	cs := createCodeset(t, 1, 1)
+	b.createTestWorkflowRun(ctx, t, w.Name, cs, "running", "Running", time.Now(), time.Now())
+	b.createTestWorkflowRun(ctx, t, w.Name, cs, "succeeded", "Succeeded", time.Now(), time.Now())
+
+	t.Run("running", func(t *testing.T) {
+		err := b.CancelWorkflowRun(ctx, "running")
+		if err != nil {
+			t.Fatalf("Failed to cancel workflow run: %s", err)
+		}
+
+		wf := Workflow{}
+		b.getObject(ctx, t, b.argoClients.WorkflowClient, "running", &wf)
+		assertStrings(t, wf.Spec.Shutdown, workflowShutdownTerminate)
+
+		err = b.CancelWorkflowRun(ctx, "running")
+		assertError(t, err, domain.ErrWorkflowRunCompleted)
+	})
+
+	t.Run("completed", func(t *testing.T) {
+		err := b.CancelWorkflowRun(ctx, "succeeded")
+		assertError(t, err, domain.ErrWorkflowRunCompleted)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		err := b.CancelWorkflowRun(ctx, "unknown")
+		assertError(t, err, domain.ErrWorkflowRunNotFound)
+	})
+}
+
+func TestRetryWorkflowRun(t *testing.T) {
+	ctx, b, _ := initBackend(t)
+	w := b.createTestWorkflow(ctx, t)
+
+	cs := createCodeset(t, 1, 1)
+	b.createTestWorkflowRun(ctx, t, w.Name, cs, "running", "Running", time.Now(), time.Now())
+	want := b.createTestWorkflowRun(ctx, t, w.Name, cs, "failed", "Failed", time.Now(), time.Now())
+	want.Annotations = map[string]string{AnnotationUser: "alice", AnnotationExtensions: "[]"}
+	b.updateWorkflow(ctx, t, want)
+
+	t.Run("completed", func(t *testing.T) {
+		// the retried workflow records the extensions the workflow steps are currently bound to
+		extensions := []*domain.WorkflowRunExtension{{StepName: "trainer", Name: "mlflow-tracking", ExtensionID: "mlflow",
+			ServiceID: "mlflow-tracking", EndpointURL: "http://mlflow", CredentialsID: "alice"}}
+		_, err := b.RetryWorkflowRun(ctx, "failed", extensions)
+		if err != nil {
+			t.Fatalf("Failed to retry workflow run: %s", err)
+		}
+
+		// the fake dynamic client does not generate a name for the workflow
+		got := Workflow{}
+		b.getObject(ctx, t, b.argoClients.WorkflowClient, "", &got)
+
+		assertStrings(t, got.GenerateName, want.GenerateName)
+		if d := cmp.Diff(want.Labels, got.Labels); d != "" {
+			t.Errorf("Unexpected Workflow labels (-want +got): %s", d)
+		}
+		wantAnnotations := map[string]string{AnnotationUser: "alice", AnnotationExtensions: extensionsAnnotation(extensions)}
+		if d := cmp.Diff(wantAnnotations, got.Annotations); d != "" {
+			t.Errorf("Unexpected Workflow annotations (-want +got): %s", d)
+		}
+		if d := cmp.Diff(want.Spec, got.Spec); d != "" {
+			t.Errorf("Unexpected Workflow spec (-want +got): %s", d)
+		}
+		if got.Status != nil {
+			t.Errorf("Expected retried Workflow to have no status, got %v", got.Status)
+		}
+	})
+
+	t.Run("running", func(t *testing.T) {
+		_, err := b.RetryWorkflowRun(ctx, "running", nil)
+		assertError(t, err, domain.ErrWorkflowRunNotCompleted)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := b.RetryWorkflowRun(ctx, "unknown", nil)
+		assertError(t, err, domain.ErrWorkflowRunNotFound)
+	})
+}
+
+func TestDeleteWorkflowRun(t *testing.T) {
+	ctx, b, logsOutput := initBackend(t)
+	w := b.createTestWorkflow(ctx, t)
+
+	cs := createCodeset(t, 1, 1)
+	b.createTestWorkflowRun(ctx, t, w.Name, cs, "succeeded", "Succeeded", time.Now(), time.Now())
+	logsOutput.Reset()
+
+	err := b.DeleteWorkflowRun(ctx, "succeeded")
+	if err != nil {
+		t.Fatalf("Failed to delete workflow run: %s", err)
+	}
+
+	if runs := b.listWorkflows(ctx, t); len(runs) != 0 {
+		t.Errorf("Expected 0 Workflow, got %d", len(runs))
+	}
+	assertStrings(t, logsOutput.String(), "Deleting argo workflow: succeeded...\n")
+
+	err = b.DeleteWorkflowRun(ctx, "succeeded")
+	assertError(t, err, domain.ErrWorkflowRunNotFound)
+}
+
+func TestCreateWorkflowListener(t *testing.T) {
+	t.Run("new listener", func(t *testing.T) {
+		ctx, b, logsOutput := initBackend(t)
+		w := b.createTestWorkflow(ctx, t)
+		logsOutput.Reset()
+
+		got, err := b.CreateWorkflowListener(ctx, w.Name, 0)
+		if err != nil {
+			t.Fatalf("Failed to create workflow listener: %s", err)
+		}
+
+		want := &domain.WorkflowListener{Name: w.Name, Available: false,
+			DashboardURL: fmt.Sprintf("%s/sensors/%s/%s", testServerURL, testNamespace, w.Name)}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected WorkflowListener (-want +got): %s", d)
+		}
+
+		gotEventSource := EventSource{}
+		b.getObject(ctx, t, b.argoClients.EventSourceClient, w.Name, &gotEventSource)
+		wantEventSource := EventSource{}
+		readYaml(t, wantArgoEventSource, &wantEventSource)
+		if d := cmp.Diff(wantEventSource, gotEventSource); d != "" {
+			t.Errorf("Unexpected EventSource (-want +got): %s", d)
+		}
+
+		gotSensor := Sensor{}
+		b.getObject(ctx, t, b.argoClients.SensorClient, w.Name, &gotSensor)
+		wantSensor := Sensor{}
+		readYaml(t, wantArgoSensor, &wantSensor)
+		if d := cmp.Diff(wantSensor, gotSensor); d != "" {
+			t.Errorf("Unexpected Sensor (-want +got): %s", d)
+		}
+
+		expectedLog := fmt.Sprintf("Creating argo event source for workflow: %s...\nCreating argo sensor for workflow: %s...\n",
+			w.Name, w.Name)
+		assertStrings(t, logsOutput.String(), expectedLog)
+	})
+
+	t.Run("existing listener", func(t *testing.T) {
+		ctx, b, logsOutput := initBackend(t)
+		w := b.createTestWorkflow(ctx, t)
+		b.createTestListener(ctx, t, w.Name, true)
+		logsOutput.Reset()
+
+		got, err := b.CreateWorkflowListener(ctx, w.Name, time.Second)
+		if err != nil {
+			t.Fatalf("Failed to create workflow listener: %s", err)
+		}
+		if !got.Available {
+			t.Errorf("Expected WorkflowListener to be available")
+		}
+		assertStrings(t, logsOutput.String(), "")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+		w := b.createTestWorkflow(ctx, t)
+
+		_, err := b.CreateWorkflowListener(ctx, w.Name, time.Second)
+		assertError(t, err, errWaitListenerTimeout)
+
+		// the resources created for the listener are deleted when it fails to become ready
+		list, err := b.argoClients.SensorClient.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list.Items) != 0 {
+			t.Errorf("Expected 0 Sensor, got %d", len(list.Items))
+		}
+	})
+}
+
+func TestDeleteWorkflowListener(t *testing.T) {
+	t.Run("delete", func(t *testing.T) {
+		ctx, b, logsOutput := initBackend(t)
+		w := b.createTestWorkflow(ctx, t)
+		b.createTestListener(ctx, t, w.Name, true)
+		logsOutput.Reset()
+
+		err := b.DeleteWorkflowListener(ctx, w.Name)
+		assertError(t, err, nil)
+
+		for _, client := range []dynamic.ResourceInterface{b.argoClients.EventSourceClient, b.argoClients.SensorClient} {
+			list, err := client.List(ctx, metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(list.Items) != 0 {
+				t.Errorf("Expected 0 items, got %d", len(list.Items))
+			}
+		}
+
+		expectedLog := fmt.Sprintf("Deleting argo sensor: %s...\nDeleting argo event source: %s...\n", w.Name, w.Name)
+		assertStrings(t, logsOutput.String(), expectedLog)
+	})
+
+	t.Run("skip not found", func(t *testing.T) {
+		ctx, b, logsOutput := initBackend(t)
+
+		name := "TestWorkflow"
+		err := b.DeleteWorkflowListener(ctx, name)
+		assertError(t, err, nil)
+
+		expectedLog := fmt.Sprintf(`Deleting argo sensor: %s...
+Argo sensor %q not found, skipping delete...
+Deleting argo event source: %s...
+Argo event source %q not found, skipping delete...
+`, name, name, name, name)
+		assertStrings(t, logsOutput.String(), expectedLog)
+	})
+}
+
+func TestGetWorkflowListener(t *testing.T) {
+	t.Run("available", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+		w := b.createTestWorkflow(ctx, t)
+		b.createTestListener(ctx, t, w.Name, true)
+
+		got, err := b.GetWorkflowListener(ctx, w.Name)
+		if err != nil {
+			t.Fatalf("Failed to get workflow listener: %s", err)
+		}
+
+		want := &domain.WorkflowListener{
+			Name:         w.Name,
+			Available:    true,
+			URL:          fmt.Sprintf("http://%s-eventsource-svc.%s.svc.cluster.local:12000/", w.Name, testNamespace),
+			DashboardURL: fmt.Sprintf("%s/sensors/%s/%s", testServerURL, testNamespace, w.Name),
+		}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected WorkflowListener (-want +got): %s", d)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+
+		_, err := b.GetWorkflowListener(ctx, "unknown")
+		if err == nil {
+			t.Fatal("Expected an error getting an unknown workflow listener")
+		}
+	})
+}
+
+func assertError(t testing.TB, got, want error) {
+	t.Helper()
+
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func assertStrings(t testing.TB, got, want string) {
+	t.Helper()
+
+	if got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func initBackend(t *testing.T) (context context.Context, backend *WorkflowBackend, logsOutput *bytes.Buffer) {
+	t.Helper()
+
+	context = contextWithCancel(t)
+	logsOutput = &bytes.Buffer{}
+	logger := log.New(logsOutput, "", 0)
+	backend = &WorkflowBackend{testServerURL, testNamespace, logger, newFakeClients(t, testNamespace)}
+	return
+}
+
+func contextWithCancel(t *testing.T) context.Context {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func readYaml(t *testing.T, path string, obj interface{}) {
+	t.Helper()
+
+	wfFile, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read workflow file %s: %s", path, err)
+	}
+	err = yaml.Unmarshal(wfFile, &obj)
+	if err != nil {
+		t.Fatalf("Error unmarshiling workflow: %s", err)
+	}
+}
+
+func newFakeClients(t *testing.T, namespace string) *clients {
+	t.Helper()
+
+	listKinds := map[schema.GroupVersionResource]string{
+		workflowTemplateResource: "WorkflowTemplateList",
+		workflowResource:         "WorkflowList",
+		eventSourceResource:      "EventSourceList",
+		sensorResource:           "SensorList",
+	}
+	dc := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	return newClientsFor(dc, kubefake.NewSimpleClientset().CoreV1(), namespace)
+}
+
+func createCodeset(t *testing.T, nameID, projectID int) *domain.Codeset {
+	t.Helper()
+
+	name := fmt.Sprintf("mlflow-app-%d", nameID)
+	project := fmt.Sprintf("workspace-%d", projectID)
+	url := fmt.Sprintf("http://gitea.10.160.5.140.nip.io/%s/%s.git", name, project)
+	return &domain.Codeset{Name: name, Project: project, URL: url}
+}
+
+func (b *WorkflowBackend) getObject(ctx context.Context, t *testing.T, client dynamic.ResourceInterface, name string, obj interface{}) {
+	t.Helper()
+
+	u, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get %q: %s", name, err)
+	}
+	if err := fromUnstructured(u.Object, obj); err != nil {
+		t.Fatalf("Failed to decode %q: %s", name, err)
+	}
+}
+
+func (b *WorkflowBackend) listWorkflows(ctx context.Context, t *testing.T) []Workflow {
+	t.Helper()
+
+	list, err := b.argoClients.WorkflowClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list Workflows: %s", err)
+	}
+	workflows := []Workflow{}
+	for _, item := range list.Items {
+		wf := Workflow{}
+		if err := fromUnstructured(item.Object, &wf); err != nil {
+			t.Fatalf("Failed to decode Workflow: %s", err)
+		}
+		workflows = append(workflows, wf)
+	}
+	return workflows
+}
+
+func (b *WorkflowBackend) updateWorkflow(ctx context.Context, t *testing.T, wf *Workflow) {
+	t.Helper()
+
+	obj, err := toUnstructured(wf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = b.argoClients.WorkflowClient.Update(ctx, &unstructured.Unstructured{Object: obj}, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to update Workflow: %s", err)
+	}
+}
+
+func (b *WorkflowBackend) createTestWorkflow(ctx context.Context, t *testing.T) *domain.Workflow {
+	t.Helper()
+
+	w := &domain.Workflow{}
+	readYaml(t, fuseMLWorkflow, w)
+	if err := b.CreateWorkflow(ctx, w); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func (b *WorkflowBackend) createTestWorkflowRun(ctx context.Context, t *testing.T, workflow string,
+	cs *domain.Codeset, runName string, phase string, startTime time.Time, completionTime time.Time) *Workflow {
+	t.Helper()
+
+	_, err := b.CreateWorkflowRun(ctx, workflow, cs, nil)
+	if err != nil {
+		t.Fatalf("Failed to create workflow run %q: %s", workflow, err)
+	}
+
+	// the fake dynamic client does not generate a name for the workflow, in that way it is not
+	// possible to create multiple workflows as they conflict on their name (""). To get around
+	// that, create the workflow run then recreate it with another name and the expected status
+	wf := &Workflow{}
+	b.getObject(ctx, t, b.argoClients.WorkflowClient, "", wf)
+	err = b.argoClients.WorkflowClient.Delete(ctx, "", metav1.DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Failed to delete Workflow: %s", err)
+	}
+
+	wf.Name = runName
+	st := metav1.NewTime(startTime)
+	wf.Status = &WorkflowStatus{Phase: phase, StartedAt: &st}
+	if phase != "Running" {
+		ct := metav1.NewTime(completionTime)
+		wf.Status.FinishedAt = &ct
+	}
+	obj, err := toUnstructured(wf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = b.argoClients.WorkflowClient.Create(ctx, &unstructured.Unstructured{Object: obj}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create Workflow: %s", err)
+	}
+	return wf
+}
+
+func (b *WorkflowBackend) createTestListener(ctx context.Context, t *testing.T, workflow string, available bool) {
+	t.Helper()
+
+	_, err := b.CreateWorkflowListener(ctx, workflow, 0)
+	if err != nil {
+		t.Fatalf("Failed to create listener %q: %s", workflow, err)
+	}
+	if available {
+		// argo events is not running, so there is no controller setting the status of the event source
+		// and sensor. To get around that, set their status to deployed
+		deployed := &Status{Conditions: []Condition{{Type: "Deployed", Status: corev1.ConditionTrue}}}
+
+		es := &EventSource{}
+		b.getObject(ctx, t, b.argoClients.EventSourceClient, workflow, es)
+		es.Status = deployed
+		b.updateObject(ctx, t, b.argoClients.EventSourceClient, es)
+
+		sensor := &Sensor{}
+		b.getObject(ctx, t, b.argoClients.SensorClient, workflow, sensor)
+		sensor.Status = deployed
+		b.updateObject(ctx, t, b.argoClients.SensorClient, sensor)
+	}
+}
+
+func (b *WorkflowBackend) updateObject(ctx context.Context, t *testing.T, client dynamic.ResourceInterface, obj interface{}) {
+	t.Helper()
+
+	u, err := toUnstructured(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Update(ctx, &unstructured.Unstructured{Object: u}, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to update object: %s", err)
+	}
+}
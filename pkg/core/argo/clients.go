@@ -0,0 +1,52 @@
+package argo
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/dynamic"
+	k8sclient "k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/fuseml/fuseml-core/pkg/kubernetes"
+)
+
+// Clients holds instances of interfaces for making requests to the argo controllers.
+type clients struct {
+	WorkflowTemplateClient dynamic.ResourceInterface
+	WorkflowClient         dynamic.ResourceInterface
+	EventSourceClient      dynamic.ResourceInterface
+	SensorClient           dynamic.ResourceInterface
+	PodClient              corev1.PodInterface
+	SecretClient           corev1.SecretInterface
+}
+
+// NewClients instantiates and returns the clients required for making requests to argo. Clients can
+// make requests within namespace.
+func newClients(namespace string) (*clients, error) {
+	cfg, err := kubernetes.GetClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error getting kubernetes client config: %w", err)
+	}
+
+	dc, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating kubernetes dynamic client: %w", err)
+	}
+
+	kcs, err := k8sclient.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating kubernetes client set: %w", err)
+	}
+	return newClientsFor(dc, kcs.CoreV1(), namespace), nil
+}
+
+func newClientsFor(dc dynamic.Interface, coreClient corev1.CoreV1Interface, namespace string) *clients {
+	return &clients{
+		WorkflowTemplateClient: dc.Resource(workflowTemplateResource).Namespace(namespace),
+		WorkflowClient:         dc.Resource(workflowResource).Namespace(namespace),
+		EventSourceClient:      dc.Resource(eventSourceResource).Namespace(namespace),
+		SensorClient:           dc.Resource(sensorResource).Namespace(namespace),
+		PodClient:              coreClient.Pods(namespace),
+		SecretClient:           coreClient.Secrets(namespace),
+	}
+}
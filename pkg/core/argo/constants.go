@@ -0,0 +1,55 @@
+package argo
+
+import "time"
+
+const (
+	workflowRunPrefix         = "fuseml-"
+	workflowServiceAccount    = "fuseml-workloads"
+	sensorServiceAccount      = "argo-events-sa"
+	entrypointTemplateName    = "main"
+	cloneTemplateName         = "clone"
+	codesetVolumeName         = "source"
+	codesetVolumeSize         = "2Gi"
+	codesetClonePath          = "/workspace/source"
+	resultsVolumeName         = "results"
+	resultsPath               = "/tekton/results"
+	gitImage                  = "alpine/git:v2.30.2"
+	builderImage              = "gcr.io/kaniko-project/executor:v1.6.0"
+	dockerfileParamName       = "DOCKERFILE"
+	dockerfilePathResult      = "DOCKERFILE-PATH"
+	codesetNameParam          = "codeset-name"
+	codesetVersionParam       = "codeset-version"
+	codesetProjectParam       = "codeset-project"
+	codesetURLParam           = "codeset-url"
+	credentialsSecretParam    = "credentials-secret"
+	defaultCodesetVersion     = "main"
+	fuseMLRegistryLocal       = "127.0.0.1:30500"
+	imageParamName            = "IMAGE"
+	stepOutputVarName         = "TASK_RESULT"
+	inputsVarPrefix           = "FUSEML_"
+	envVarPrefix              = "FUSEML_ENV_"
+	stepDefaultCmd            = "run"
+	stepContainerName         = "main"
+	webhookEventName          = "codeset"
+	webhookPort               = 12000
+	webhookEndpoint           = "/"
+	workflowShutdownTerminate = "Terminate"
+	logsPollInterval          = 2 * time.Second
+	maxLogLineSize            = 1024 * 1024
+
+	// LabelCodesetName is the label key for the codeset name
+	LabelCodesetName = "fuseml/codeset-name"
+	// LabelCodesetProject is the label key for the codeset project
+	LabelCodesetProject = "fuseml/codeset-project"
+	// LabelCodesetVersion is the label key for the codeset version
+	LabelCodesetVersion = "fuseml/codeset-version"
+	// LabelWorkflowRef is the label key for the reference of the workflow
+	LabelWorkflowRef = "fuseml/workflow-ref"
+	// AnnotationUser is the annotation key for the user triggering a workflow run
+	AnnotationUser = "fuseml/user"
+	// AnnotationExtensions is the annotation key for the extensions the steps of a workflow run are bound to
+	AnnotationExtensions = "fuseml/extensions"
+	// AnnotationWorkflowExtensions is the annotation key for the extensions the steps of a workflow are bound to,
+	// which argo sets on the workflows created from the workflow template
+	AnnotationWorkflowExtensions = "fuseml/workflow-extensions"
+)
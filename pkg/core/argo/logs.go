@@ -0,0 +1,171 @@
+package argo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// StreamWorkflowRunLogs streams the logs from the main container of the pods running the tasks of an argo
+// workflow. When following the logs, the workflow is polled for new tasks until it completes.
+func (w *WorkflowBackend) StreamWorkflowRunLogs(ctx context.Context, runName string, options *domain.WorkflowRunLogOptions,
+	handler domain.WorkflowRunLogHandler) error {
+	if options == nil {
+		options = &domain.WorkflowRunLogOptions{}
+	}
+
+	run, err := w.getWorkflow(ctx, runName)
+	if err != nil {
+		return err
+	}
+
+	if options.Step != "" {
+		found, err := w.workflowHasTask(ctx, run, options.Step)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return domain.ErrWorkflowRunStepNotFound
+		}
+	}
+
+	// count the logged lines, so that it is possible to tell when there are no logs for the workflow
+	lines := 0
+	countingHandler := func(log *domain.WorkflowRunLog) error {
+		lines++
+		return handler(log)
+	}
+
+	// keep track of the pods that had their logs streamed, a task may run on multiple pods when retried
+	streamed := map[string]bool{}
+	for {
+		for _, node := range taskNodes(run) {
+			if options.Step != "" && node.DisplayName != options.Step {
+				continue
+			}
+			// the pod running the task is named after the node ID
+			if streamed[node.ID] {
+				continue
+			}
+			err := w.streamPodLogs(ctx, node.DisplayName, node.ID, options.Follow, countingHandler)
+			if err != nil {
+				return err
+			}
+			streamed[node.ID] = true
+		}
+
+		if !options.Follow || workflowDone(run) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(logsPollInterval):
+		}
+
+		run, err = w.getWorkflow(ctx, runName)
+		if err != nil {
+			return err
+		}
+	}
+
+	if lines == 0 {
+		return domain.ErrWorkflowRunLogsNotFound
+	}
+	return nil
+}
+
+// streamPodLogs streams the logs from the main container of a pod, where the task template is executed
+func (w *WorkflowBackend) streamPodLogs(ctx context.Context, step, podName string, follow bool,
+	handler domain.WorkflowRunLogHandler) error {
+	pod, err := w.argoClients.PodClient.Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		// the pod may not have been created yet or deleted after the task completed
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error getting pod %q: %w", podName, err)
+	}
+
+	if follow {
+		pod, err = w.waitForContainer(ctx, podName, stepContainerName)
+		if err != nil {
+			return err
+		}
+	}
+	// there are no logs for containers that did not start
+	if !containerStarted(pod, stepContainerName) {
+		return nil
+	}
+
+	req := w.argoClients.PodClient.GetLogs(podName, &corev1.PodLogOptions{Container: stepContainerName, Follow: follow})
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("error streaming logs from container %q of pod %q: %w", stepContainerName, podName, err)
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLogLineSize)
+	for scanner.Scan() {
+		err = handler(&domain.WorkflowRunLog{Step: step, Container: stepContainerName, Line: scanner.Text()})
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// waitForContainer waits until a pod container starts or the pod completes, returning the updated pod
+func (w *WorkflowBackend) waitForContainer(ctx context.Context, podName, container string) (pod *corev1.Pod, err error) {
+	err = wait.PollImmediateUntil(logsPollInterval, func() (bool, error) {
+		pod, err = w.argoClients.PodClient.Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("error getting pod %q: %w", podName, err)
+		}
+		return containerStarted(pod, container) || pod.Status.Phase == corev1.PodSucceeded ||
+			pod.Status.Phase == corev1.PodFailed, nil
+	}, ctx.Done())
+	return
+}
+
+// workflowHasTask checks whether the workflow template executed by an argo workflow has a task with the
+// specified name
+func (w *WorkflowBackend) workflowHasTask(ctx context.Context, run *Workflow, name string) (bool, error) {
+	if run.Spec.WorkflowTemplateRef == nil {
+		return false, nil
+	}
+	wt := &WorkflowTemplate{}
+	if err := w.get(ctx, w.argoClients.WorkflowTemplateClient, run.Spec.WorkflowTemplateRef.Name, wt); err != nil {
+		return false, fmt.Errorf("error getting argo workflow template %q: %w", run.Spec.WorkflowTemplateRef.Name, err)
+	}
+	for _, t := range wt.Spec.Templates {
+		if t.DAG == nil {
+			continue
+		}
+		for _, task := range t.DAG.Tasks {
+			if task.Name == name {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func containerStarted(pod *corev1.Pod, container string) bool {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == container {
+			return cs.State.Running != nil || cs.State.Terminated != nil
+		}
+	}
+	return false
+}
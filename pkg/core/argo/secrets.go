@@ -0,0 +1,103 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+// credentialsSecretName returns the name of the secret holding the extension credentials used by the workflows
+// created for the codesets of a project and, if set, triggered by a user. Project and user names are not
+// necessarily valid in a secret name, so they are converted to DNS labels.
+func credentialsSecretName(workflowName, project, user string) string {
+	name := fmt.Sprintf("%s%s-credentials", workflowRunPrefix, workflowName)
+	for _, scope := range []string{project, user} {
+		if scope != "" {
+			name = fmt.Sprintf("%s.%s", name, util.DNSLabel(scope))
+		}
+	}
+	return name
+}
+
+// credentialsSecretKey returns the key of the secret entry holding a credentials configuration value for
+// a workflow step extension
+func credentialsSecretKey(stepName, extensionName, name string) string {
+	return fmt.Sprintf("%s.%s.%s", stepName, extensionName, name)
+}
+
+// SetWorkflowCredentials creates or updates the secret holding the extension credentials bound to the workflow
+// steps for a project and user, from which they are passed to the step containers as environment variables. The
+// argo workflows select the secret through the credentials-secret parameter.
+func (w *WorkflowBackend) SetWorkflowCredentials(ctx context.Context, workflow *domain.Workflow, project, user string) error {
+	name := credentialsSecretName(workflow.Name, project, user)
+	data := map[string][]byte{}
+	for _, step := range workflow.Steps {
+		for _, extension := range step.Extensions {
+			if extension.ExtensionAccess == nil || extension.ExtensionAccess.Credentials == nil {
+				continue
+			}
+			for k, v := range extension.ExtensionAccess.Credentials.Configuration {
+				data[credentialsSecretKey(step.Name, extension.Name, k)] = []byte(v)
+			}
+		}
+	}
+	if len(data) == 0 {
+		return w.deleteSecret(ctx, name)
+	}
+
+	secret, err := w.argoClients.SecretClient.Get(ctx, name, metav1.GetOptions{})
+	if k8serr.IsNotFound(err) {
+		w.logger.Printf("Creating credentials secret for workflow: %s, project: %s...", workflow.Name, project)
+		labels := map[string]string{LabelWorkflowRef: workflow.Name}
+		if project != "" {
+			labels[LabelCodesetProject] = project
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: w.namespace, Labels: labels},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       data,
+		}
+		if _, err = w.argoClients.SecretClient.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error creating secret %q: %w", name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error getting secret %q: %w", name, err)
+	}
+	secret.Data = data
+	if _, err = w.argoClients.SecretClient.Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating secret %q: %w", name, err)
+	}
+	return nil
+}
+
+// deleteCredentialsSecrets deletes the secrets holding the extension credentials used by the runs of a workflow
+func (w *WorkflowBackend) deleteCredentialsSecrets(ctx context.Context, workflowName string) error {
+	secrets, err := w.argoClients.SecretClient.List(ctx,
+		metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", LabelWorkflowRef, workflowName)})
+	if err != nil {
+		return fmt.Errorf("error listing credentials secrets of workflow %q: %w", workflowName, err)
+	}
+	for _, secret := range secrets.Items {
+		if err := w.deleteSecret(ctx, secret.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteSecret deletes a secret, if it exists
+func (w *WorkflowBackend) deleteSecret(ctx context.Context, name string) error {
+	err := w.argoClients.SecretClient.Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !k8serr.IsNotFound(err) {
+		return fmt.Errorf("error deleting secret %q: %w", name, err)
+	}
+	return nil
+}
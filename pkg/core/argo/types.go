@@ -0,0 +1,243 @@
+package argo
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// The types below are the subset of the Argo Workflows and Argo Events (argoproj.io/v1alpha1) APIs
+// used by FuseML. They are converted to unstructured objects, which are managed by a dynamic client.
+
+var (
+	workflowTemplateResource = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "workflowtemplates"}
+	workflowResource         = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "workflows"}
+	eventSourceResource      = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "eventsources"}
+	sensorResource           = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "sensors"}
+)
+
+const (
+	apiVersion           = "argoproj.io/v1alpha1"
+	workflowTemplateKind = "WorkflowTemplate"
+	workflowKind         = "Workflow"
+	eventSourceKind      = "EventSource"
+	sensorKind           = "Sensor"
+)
+
+// WorkflowTemplate is a reusable definition of an Argo workflow.
+type WorkflowTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              WorkflowSpec `json:"spec"`
+}
+
+// Workflow is an Argo workflow, representing a run of a WorkflowTemplate.
+type Workflow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              WorkflowSpec    `json:"spec"`
+	Status            *WorkflowStatus `json:"status,omitempty"`
+}
+
+// WorkflowSpec is the specification of a Workflow or WorkflowTemplate.
+type WorkflowSpec struct {
+	Entrypoint           string                         `json:"entrypoint,omitempty"`
+	Arguments            *Arguments                     `json:"arguments,omitempty"`
+	Templates            []Template                     `json:"templates,omitempty"`
+	ServiceAccountName   string                         `json:"serviceAccountName,omitempty"`
+	Volumes              []corev1.Volume                `json:"volumes,omitempty"`
+	VolumeClaimTemplates []corev1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
+	WorkflowTemplateRef  *WorkflowTemplateRef           `json:"workflowTemplateRef,omitempty"`
+	WorkflowMetadata     *metav1.ObjectMeta             `json:"workflowMetadata,omitempty"`
+	Shutdown             string                         `json:"shutdown,omitempty"`
+}
+
+// WorkflowTemplateRef references the WorkflowTemplate executed by a Workflow.
+type WorkflowTemplateRef struct {
+	Name string `json:"name"`
+}
+
+// Arguments are the values passed to a workflow or a DAG task.
+type Arguments struct {
+	Parameters []Parameter `json:"parameters,omitempty"`
+}
+
+// Parameter is a workflow or template parameter.
+type Parameter struct {
+	Name        string     `json:"name"`
+	Default     *string    `json:"default,omitempty"`
+	Value       *string    `json:"value,omitempty"`
+	Description string     `json:"description,omitempty"`
+	ValueFrom   *ValueFrom `json:"valueFrom,omitempty"`
+}
+
+// ValueFrom describes where a parameter value comes from.
+type ValueFrom struct {
+	Path string `json:"path,omitempty"`
+}
+
+// Template is a unit of execution in a workflow, either a container or a DAG of other templates.
+type Template struct {
+	Name      string            `json:"name"`
+	Inputs    *Inputs           `json:"inputs,omitempty"`
+	Outputs   *Outputs          `json:"outputs,omitempty"`
+	Container *corev1.Container `json:"container,omitempty"`
+	DAG       *DAGTemplate      `json:"dag,omitempty"`
+}
+
+// Inputs are the parameters received by a template.
+type Inputs struct {
+	Parameters []Parameter `json:"parameters,omitempty"`
+}
+
+// Outputs are the parameters produced by a template.
+type Outputs struct {
+	Parameters []Parameter `json:"parameters,omitempty"`
+}
+
+// DAGTemplate is a template made of tasks executed according to their dependencies.
+type DAGTemplate struct {
+	Tasks []DAGTask `json:"tasks"`
+}
+
+// DAGTask is a task of a DAG template.
+type DAGTask struct {
+	Name         string     `json:"name"`
+	Template     string     `json:"template"`
+	Arguments    *Arguments `json:"arguments,omitempty"`
+	Dependencies []string   `json:"dependencies,omitempty"`
+}
+
+// WorkflowStatus is the status of a Workflow.
+type WorkflowStatus struct {
+	Phase      string                `json:"phase,omitempty"`
+	StartedAt  *metav1.Time          `json:"startedAt,omitempty"`
+	FinishedAt *metav1.Time          `json:"finishedAt,omitempty"`
+	Message    string                `json:"message,omitempty"`
+	Nodes      map[string]NodeStatus `json:"nodes,omitempty"`
+}
+
+// NodeStatus is the status of a node (e.g. a pod executing a DAG task) of a Workflow.
+type NodeStatus struct {
+	ID           string       `json:"id"`
+	Name         string       `json:"name"`
+	DisplayName  string       `json:"displayName,omitempty"`
+	Type         string       `json:"type"`
+	TemplateName string       `json:"templateName,omitempty"`
+	Phase        string       `json:"phase,omitempty"`
+	Message      string       `json:"message,omitempty"`
+	StartedAt    *metav1.Time `json:"startedAt,omitempty"`
+	FinishedAt   *metav1.Time `json:"finishedAt,omitempty"`
+	Outputs      *Outputs     `json:"outputs,omitempty"`
+}
+
+// EventSource describes the events consumed by Argo Events, a webhook for FuseML.
+type EventSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              EventSourceSpec `json:"spec"`
+	Status            *Status         `json:"status,omitempty"`
+}
+
+// EventSourceSpec is the specification of an EventSource.
+type EventSourceSpec struct {
+	Service *Service                  `json:"service,omitempty"`
+	Webhook map[string]WebhookContext `json:"webhook,omitempty"`
+}
+
+// Service is the kubernetes service exposing an EventSource.
+type Service struct {
+	Ports []corev1.ServicePort `json:"ports,omitempty"`
+}
+
+// WebhookContext is the configuration of a webhook EventSource.
+type WebhookContext struct {
+	Endpoint string `json:"endpoint"`
+	Method   string `json:"method"`
+	Port     string `json:"port"`
+}
+
+// Sensor defines the actions triggered by the events from an EventSource.
+type Sensor struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              SensorSpec `json:"spec"`
+	Status            *Status    `json:"status,omitempty"`
+}
+
+// SensorSpec is the specification of a Sensor.
+type SensorSpec struct {
+	Template     *SensorTemplate   `json:"template,omitempty"`
+	Dependencies []EventDependency `json:"dependencies"`
+	Triggers     []Trigger         `json:"triggers"`
+}
+
+// SensorTemplate customizes the deployment running a Sensor.
+type SensorTemplate struct {
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+}
+
+// EventDependency is an event a Sensor depends on.
+type EventDependency struct {
+	Name            string `json:"name"`
+	EventSourceName string `json:"eventSourceName"`
+	EventName       string `json:"eventName"`
+}
+
+// Trigger is an action executed by a Sensor.
+type Trigger struct {
+	Template *TriggerTemplate `json:"template"`
+}
+
+// TriggerTemplate describes a Trigger.
+type TriggerTemplate struct {
+	Name string              `json:"name"`
+	K8s  *StandardK8STrigger `json:"k8s"`
+}
+
+// StandardK8STrigger is a Trigger that creates a kubernetes resource.
+type StandardK8STrigger struct {
+	Operation  string             `json:"operation"`
+	Source     *ArtifactLocation  `json:"source"`
+	Parameters []TriggerParameter `json:"parameters,omitempty"`
+}
+
+// ArtifactLocation holds the resource created by a StandardK8STrigger.
+type ArtifactLocation struct {
+	Resource map[string]interface{} `json:"resource"`
+}
+
+// TriggerParameter sets a field of the resource created by a Trigger from the event data.
+type TriggerParameter struct {
+	Src       *TriggerParameterSource `json:"src"`
+	Dest      string                  `json:"dest"`
+	Operation string                  `json:"operation,omitempty"`
+}
+
+// TriggerParameterSource is the event data used by a TriggerParameter.
+type TriggerParameterSource struct {
+	DependencyName string `json:"dependencyName"`
+	DataKey        string `json:"dataKey"`
+}
+
+// Status is the status of an EventSource or Sensor.
+type Status struct {
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// Condition is a condition of an EventSource or Sensor.
+type Condition struct {
+	Type    string                 `json:"type"`
+	Status  corev1.ConditionStatus `json:"status"`
+	Reason  string                 `json:"reason,omitempty"`
+	Message string                 `json:"message,omitempty"`
+}
+
+func toUnstructured(obj interface{}) (map[string]interface{}, error) {
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
+
+func fromUnstructured(u map[string]interface{}, obj interface{}) error {
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u, obj)
+}
@@ -0,0 +1,581 @@
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	git "github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	gitobject "github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitclient "github.com/go-git/go-git/v5/plumbing/transport/client"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/storage/memory"
+	dircopy "github.com/otiai10/copy"
+	"github.com/pkg/errors"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+const (
+	// maxArchiveSize is the maximum total size of the files extracted from an imported archive
+	maxArchiveSize = 512 * 1024 * 1024
+	// importFetchTimeout is the maximum duration of fetching a remote git repository for an import
+	importFetchTimeout = 5 * time.Minute
+	// importAuthorName and importAuthorEmail identify the author of the commits pushed by codeset imports
+	importAuthorName  = "FuseML core user"
+	importAuthorEmail = "fuseml-core@fuseml"
+	// defaultImportUsername is the username used to access a remote git repository when the credentials
+	// supplied for the import only have a token
+	defaultImportUsername = "git"
+)
+
+// pushCredentialsProvider is implemented by the git admin clients that require credentials to push contents to
+// the codeset repositories
+type pushCredentialsProvider interface {
+	PushCredentials() (string, string)
+}
+
+// importURLSchemes are the URL schemes accepted for the remote git repositories imported into codesets.
+// Local paths are deliberately left out, as they would expose the files accessible to FuseML core.
+var importURLSchemes = []string{"http", "https"}
+
+// maxRepositorySize is the maximum number of bytes written when fetching a remote git repository for an import,
+// including the git objects
+var maxRepositorySize int64 = 1024 * 1024 * 1024
+
+// restrictedNetworks are the loopback, link-local and private networks that the remote git repositories imported
+// into codesets cannot be fetched from, unless allowed by importAllowedNetworks. Otherwise, imports could be used
+// to reach the cluster internal services and the cloud metadata endpoints.
+var restrictedNetworks = parseNetworks(
+	"0.0.0.0/8", "127.0.0.0/8", "169.254.0.0/16", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16",
+	"::/128", "::1/128", "fe80::/10", "fc00::/7",
+)
+
+// importAllowedNetworks are the restricted networks from which remote git repositories can still be imported
+var importAllowedNetworks []*net.IPNet
+
+// importFetchKey marks the contexts of the remote git repository fetches, whose connections are restricted
+type importFetchKey struct{}
+
+func init() {
+	// go-git only supports replacing the HTTP client used for all the git operations, so the restrictions only
+	// apply to the requests made with a context marked with importFetchKey. Pushing to the codeset repositories,
+	// which are usually hosted inside the cluster, is not affected.
+	restricted := http.DefaultTransport.(*http.Transport).Clone()
+	// connect directly, so that the destinations can be checked
+	restricted.Proxy = nil
+	restricted.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   checkImportConnection,
+	}).DialContext
+	client := githttp.NewClient(&http.Client{Transport: &importRoundTripper{base: http.DefaultTransport, restricted: restricted}})
+	gitclient.InstallProtocol("http", client)
+	gitclient.InstallProtocol("https", client)
+}
+
+// SetImportAllowedNetworks sets the loopback, link-local and private networks, in CIDR notation, from which remote
+// git repositories can still be imported into codesets
+func SetImportAllowedNetworks(cidrs []string) error {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return errors.Wrapf(err, "Invalid import network %q", cidr)
+		}
+		networks = append(networks, network)
+	}
+	importAllowedNetworks = networks
+	return nil
+}
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+func networksContain(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkImportConnection refuses the connections to the restricted networks that are not explicitly allowed. It
+// is called with the resolved address, right before connecting, which also covers the redirects and the host
+// names resolving to different addresses over time.
+func checkImportConnection(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("invalid address %q", host)
+	}
+	if networksContain(restrictedNetworks, ip) && !networksContain(importAllowedNetworks, ip) {
+		return fmt.Errorf("importing from %s is not allowed", ip)
+	}
+	return nil
+}
+
+// importRoundTripper sends the requests made for fetching the remote git repositories imported into codesets
+// through the restricted transport
+type importRoundTripper struct {
+	base       http.RoundTripper
+	restricted http.RoundTripper
+}
+
+func (t *importRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Context().Value(importFetchKey{}) != nil {
+		return t.restricted.RoundTrip(req)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// Import creates a new codeset and populates it with the contents of a remote git repository or of an archive
+func (cs *GitCodesetStore) Import(ctx context.Context, c *domain.Codeset, source *domain.CodesetImportSource) (*domain.Codeset, *string, *string, error) {
+	// push as FuseML itself, as the project members have their own credentials which are not known to FuseML
+	provider, ok := cs.gitAdmin.(pushCredentialsProvider)
+	if !ok {
+		return nil, nil, nil, domain.ErrCodesetImportUnsupported
+	}
+	if err := validateImportSource(source); err != nil {
+		return nil, nil, nil, err
+	}
+
+	sourceDir, err := ioutil.TempDir("", "codeset-import")
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "Failed to create temp directory")
+	}
+	defer os.RemoveAll(sourceDir)
+
+	var message string
+	contentsDir := sourceDir
+	if source.URL != "" {
+		message = fmt.Sprintf("Import codeset from %s", source.URL)
+		if source.Ref != "" {
+			message += fmt.Sprintf(" (%s)", source.Ref)
+		}
+		err = fetchGitSource(ctx, source, sourceDir)
+	} else {
+		message = fmt.Sprintf("Import codeset from %s archive", source.ArchiveFormat)
+		contentsDir, err = extractArchive(source, sourceDir)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	c, username, password, err := cs.Add(ctx, c)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	pushUser, pushPass := provider.PushCredentials()
+	if err = pushContents(ctx, c.URL, contentsDir, message, basicAuth(c.URL, pushUser, pushPass)); err != nil {
+		// do not leave an empty codeset behind, so that the import can be retried
+		if delErr := cs.gitAdmin.DeleteRepository(c.Project, c.Name); delErr != nil {
+			return nil, nil, nil, errors.Wrapf(err, "Pushing Codeset contents failed (removing the codeset also failed: %v)", delErr)
+		}
+		return nil, nil, nil, errors.Wrap(err, "Pushing Codeset contents failed")
+	}
+	// the contents are imported even if they cannot be inspected, which can be retried with Inspect
+	_ = cs.updateMetadata(ctx, c)
+	return c, username, password, nil
+}
+
+func validateImportSource(source *domain.CodesetImportSource) error {
+	if (source.URL == "") == (len(source.Archive) == 0) {
+		return errors.Wrap(domain.ErrInvalidCodesetImport, "either a git repository URL or an archive is required")
+	}
+	if source.URL != "" {
+		u, err := url.Parse(source.URL)
+		if err != nil {
+			return errors.Wrapf(domain.ErrInvalidCodesetImport, "malformed git repository URL %q", source.URL)
+		}
+		for _, scheme := range importURLSchemes {
+			if u.Scheme == scheme {
+				return nil
+			}
+		}
+		return errors.Wrapf(domain.ErrInvalidCodesetImport, "unsupported git repository URL scheme %q", u.Scheme)
+	}
+	if source.ArchiveFormat != domain.CodesetArchiveTarGz && source.ArchiveFormat != domain.CodesetArchiveZip {
+		return errors.Wrapf(domain.ErrInvalidCodesetImport, "unsupported archive format %q", source.ArchiveFormat)
+	}
+	return nil
+}
+
+// basicAuth returns the HTTP basic authentication for a git repository URL, or nil if the URL does not
+// use HTTP
+func basicAuth(repoURL, username, password string) transport.AuthMethod {
+	if !strings.HasPrefix(repoURL, "http://") && !strings.HasPrefix(repoURL, "https://") {
+		return nil
+	}
+	return &githttp.BasicAuth{Username: username, Password: password}
+}
+
+// fetchGitSource checks out the requested revision of a remote git repository into dir, without the git metadata.
+// Only the requested branch or tag is fetched, without its history. Other revisions, like commit IDs, require
+// fetching the whole repository.
+func fetchGitSource(ctx context.Context, source *domain.CodesetImportSource, dir string) error {
+	var auth transport.AuthMethod
+	if len(source.Credentials) > 0 {
+		username, password := source.Credentials["username"], source.Credentials["password"]
+		if password == "" {
+			password = source.Credentials["token"]
+		}
+		if username == "" {
+			username = defaultImportUsername
+		}
+		auth = basicAuth(source.URL, username, password)
+	}
+	ctx, cancel := context.WithTimeout(context.WithValue(ctx, importFetchKey{}, true), importFetchTimeout)
+	defer cancel()
+
+	opts := &git.CloneOptions{URL: source.URL, Auth: auth, Depth: 1, SingleBranch: true}
+	// whether the requested revision has to be resolved in the whole repository
+	resolve := false
+	if source.Ref != "" {
+		ref, err := remoteReference(ctx, source.URL, auth, source.Ref)
+		if err != nil {
+			return errors.Wrapf(domain.ErrInvalidCodesetImport, "failed fetching git repository %s: %s", source.URL, err)
+		}
+		resolve = ref == ""
+		if resolve {
+			opts.Depth, opts.SingleBranch = 0, false
+		}
+		opts.ReferenceName = ref
+	}
+
+	fs := &limitedFilesystem{Filesystem: osfs.New(dir), limit: &writeLimit{max: maxRepositorySize}}
+	dotGit, err := fs.Chroot(git.GitDirName)
+	if err != nil {
+		return errors.Wrap(err, "Failed to create the git directory")
+	}
+	repo, err := git.CloneContext(ctx, filesystem.NewStorage(dotGit, cache.NewObjectLRUDefault()), fs, opts)
+	if err != nil {
+		return errors.Wrapf(domain.ErrInvalidCodesetImport, "failed fetching git repository %s: %s", source.URL, err)
+	}
+
+	if resolve {
+		hash, err := repo.ResolveRevision(plumbing.Revision(source.Ref))
+		if err != nil {
+			return errors.Wrapf(domain.ErrInvalidCodesetImport, "revision %q not found in git repository %s", source.Ref, source.URL)
+		}
+		wt, err := repo.Worktree()
+		if err != nil {
+			return errors.Wrap(err, "Failed to get the git repository worktree")
+		}
+		if err = wt.Checkout(&git.CheckoutOptions{Hash: *hash, Force: true}); err != nil {
+			if errors.Is(err, errWriteLimitExceeded) {
+				return errors.Wrapf(domain.ErrInvalidCodesetImport, "failed fetching git repository %s: %s", source.URL, err)
+			}
+			return errors.Wrapf(err, "Failed to check out revision %q", source.Ref)
+		}
+	}
+	return os.RemoveAll(filepath.Join(dir, git.GitDirName))
+}
+
+// remoteReference returns the name of the branch or of the tag called ref in a remote git repository, or an
+// empty name if there is none
+func remoteReference(ctx context.Context, repoURL string, auth transport.AuthMethod, ref string) (plumbing.ReferenceName, error) {
+	remote := git.NewRemote(memory.NewStorage(), &gitconfig.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{repoURL}})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+	if err != nil {
+		return "", err
+	}
+	for _, name := range []plumbing.ReferenceName{plumbing.NewBranchReferenceName(ref), plumbing.NewTagReferenceName(ref)} {
+		for _, r := range refs {
+			if r.Name() == name {
+				return name, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// errWriteLimitExceeded is returned when writing more than allowed to a limitedFilesystem
+var errWriteLimitExceeded = errors.New("repository size limit exceeded")
+
+// writeLimit is the number of bytes that can be written to a limitedFilesystem and to its sub-directories
+type writeLimit struct {
+	max     int64
+	written int64
+}
+
+func (l *writeLimit) add(n int) error {
+	if atomic.AddInt64(&l.written, int64(n)) > l.max {
+		return errWriteLimitExceeded
+	}
+	return nil
+}
+
+// limitedFilesystem is a filesystem that refuses writing more than its limit, to bound the size of the remote git
+// repositories fetched for imports
+type limitedFilesystem struct {
+	billy.Filesystem
+	limit *writeLimit
+}
+
+func (fs *limitedFilesystem) wrap(f billy.File, err error) (billy.File, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &limitedFile{File: f, limit: fs.limit}, nil
+}
+
+func (fs *limitedFilesystem) Create(filename string) (billy.File, error) {
+	return fs.wrap(fs.Filesystem.Create(filename))
+}
+
+func (fs *limitedFilesystem) OpenFile(filename string, flag int, perm os.FileMode) (billy.File, error) {
+	return fs.wrap(fs.Filesystem.OpenFile(filename, flag, perm))
+}
+
+func (fs *limitedFilesystem) TempFile(dir, prefix string) (billy.File, error) {
+	return fs.wrap(fs.Filesystem.TempFile(dir, prefix))
+}
+
+func (fs *limitedFilesystem) Chroot(path string) (billy.Filesystem, error) {
+	chroot, err := fs.Filesystem.Chroot(path)
+	if err != nil {
+		return nil, err
+	}
+	return &limitedFilesystem{Filesystem: chroot, limit: fs.limit}, nil
+}
+
+// limitedFile is a file opened from a limitedFilesystem
+type limitedFile struct {
+	billy.File
+	limit *writeLimit
+}
+
+func (f *limitedFile) Write(p []byte) (int, error) {
+	if err := f.limit.add(len(p)); err != nil {
+		return 0, err
+	}
+	return f.File.Write(p)
+}
+
+// extractArchive extracts the archive supplied for an import into dir and returns the directory holding the
+// codeset files. When all the archive contents are located in a single top directory (e.g. archives downloaded
+// from git hosting services), that directory is returned.
+func extractArchive(source *domain.CodesetImportSource, dir string) (string, error) {
+	w := &archiveWriter{dir: dir}
+	var err error
+	switch source.ArchiveFormat {
+	case domain.CodesetArchiveTarGz:
+		err = w.extractTarGz(source.Archive)
+	case domain.CodesetArchiveZip:
+		err = w.extractZip(source.Archive)
+	}
+	if err != nil {
+		return "", errors.Wrapf(domain.ErrInvalidCodesetImport, "failed extracting %s archive: %s", source.ArchiveFormat, err)
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to read the extracted archive")
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		dir = filepath.Join(dir, entries[0].Name())
+	}
+	return dir, os.RemoveAll(filepath.Join(dir, ".git"))
+}
+
+// archiveWriter writes the directories and regular files found in an archive under a directory
+type archiveWriter struct {
+	dir  string
+	size int64
+}
+
+func (w *archiveWriter) extractTarGz(archive []byte) error {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = w.mkdir(header.Name); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err = w.writeFile(header.Name, os.FileMode(header.Mode), tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (w *archiveWriter) extractZip(archive []byte) error {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		mode := f.Mode()
+		if mode.IsDir() {
+			if err = w.mkdir(f.Name); err != nil {
+				return err
+			}
+			continue
+		}
+		if !mode.IsRegular() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = w.writeFile(f.Name, mode, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// path returns the location where an archive entry is extracted
+func (w *archiveWriter) path(name string) (string, error) {
+	path := filepath.Join(w.dir, filepath.FromSlash(name))
+	if path != w.dir && !strings.HasPrefix(path, w.dir+string(os.PathSeparator)) {
+		return "", fmt.Errorf("illegal path %q", name)
+	}
+	return path, nil
+}
+
+func (w *archiveWriter) mkdir(name string) error {
+	path, err := w.path(name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(path, 0755)
+}
+
+func (w *archiveWriter) writeFile(name string, mode os.FileMode, r io.Reader) error {
+	path, err := w.path(name)
+	if err != nil {
+		return err
+	}
+	if path == w.dir {
+		return fmt.Errorf("illegal file name %q", name)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	n, err := io.Copy(f, io.LimitReader(r, maxArchiveSize-w.size+1))
+	w.size += n
+	if err != nil {
+		return err
+	}
+	if w.size > maxArchiveSize {
+		return fmt.Errorf("archive contents exceed %d bytes", maxArchiveSize)
+	}
+	return nil
+}
+
+// pushContents replaces the contents of the default branch of the git repository at repoURL with the
+// files found in sourceDir
+func pushContents(ctx context.Context, repoURL, sourceDir, message string, auth transport.AuthMethod) error {
+	cloneDir, err := ioutil.TempDir("", "codeset-clone")
+	if err != nil {
+		return errors.Wrap(err, "Failed to create temp directory")
+	}
+	defer os.RemoveAll(cloneDir)
+
+	repo, err := git.PlainCloneContext(ctx, cloneDir, false, &git.CloneOptions{URL: repoURL, Auth: auth})
+	if err == transport.ErrEmptyRemoteRepository {
+		repo, err = initRepository(cloneDir, repoURL)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed to clone the codeset repository")
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return errors.Wrap(err, "Failed to get the codeset repository worktree")
+	}
+	if err = wt.RemoveGlob("*"); err != nil && err != git.ErrGlobNoMatches {
+		return errors.Wrap(err, "Failed to remove the existing codeset files")
+	}
+	if err = dircopy.Copy(sourceDir, cloneDir); err != nil {
+		return errors.Wrap(err, "Failed to copy the codeset files")
+	}
+	if err = wt.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+		return errors.Wrap(err, "Failed to add the codeset files")
+	}
+	_, err = wt.Commit(message, &git.CommitOptions{
+		Author: &gitobject.Signature{Name: importAuthorName, Email: importAuthorEmail, When: time.Now()},
+	})
+	if err != nil {
+		return errors.Wrap(err, "Failed to commit the codeset files")
+	}
+	if err = repo.PushContext(ctx, &git.PushOptions{Auth: auth}); err != nil {
+		return errors.Wrap(err, "Failed to push the codeset files")
+	}
+	return nil
+}
+
+// initRepository initializes a working repository for a remote repository without any commits, with the
+// default branch checked out
+func initRepository(dir, repoURL string) (*git.Repository, error) {
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		return nil, err
+	}
+	head := plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main"))
+	if err = repo.Storer.SetReference(head); err != nil {
+		return nil, err
+	}
+	_, err = repo.CreateRemote(&gitconfig.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{repoURL}})
+	return repo, err
+}
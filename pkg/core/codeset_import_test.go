@@ -0,0 +1,317 @@
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/fuseml/fuseml-core/pkg/core/gitprovider"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func newLocalCodesetStore(t *testing.T) *GitCodesetStore {
+	t.Helper()
+	gitAdmin, err := gitprovider.NewLocalAdminClient(log.New(io.Discard, "", 0), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewGitCodesetStore(gitAdmin, NewCodesetMetadataStore())
+}
+
+// readCodesetFile clones the codeset repository and returns the contents of a file from its default branch
+func readCodesetFile(t *testing.T, c *domain.Codeset, name string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if _, err := git.PlainClone(dir, false, &git.CloneOptions{URL: c.URL}); err != nil {
+		t.Fatalf("Failed to clone the codeset repository: %v", err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatalf("Failed to read %s from the codeset repository: %v", name, err)
+	}
+	return string(data)
+}
+
+// commitFile commits a file to the git repository in dir, tags the commit and returns its ID
+func commitFile(t *testing.T, repo *git.Repository, dir, name, content, tag string) string {
+	t.Helper()
+	if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = wt.Add(name); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := wt.Commit("Update "+name, &git.CommitOptions{Author: &object.Signature{Name: "Jane", When: time.Now()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag != "" {
+		if _, err = repo.CreateTag(tag, hash, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return hash.String()
+}
+
+func tarGzArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImportArchive(t *testing.T) {
+	store := newLocalCodesetStore(t)
+
+	t.Run("tar.gz", func(t *testing.T) {
+		archive := tarGzArchive(t, map[string]string{
+			"mlflow-app-main/MLproject":              "name: mlflow-app\n",
+			"mlflow-app-main/model/train.py":         "print('training')\n",
+			"mlflow-app-main/.git/config":            "[core]\n",
+			"mlflow-app-main/model/requirements.txt": "mlflow\n",
+		})
+		source := &domain.CodesetImportSource{Archive: archive, ArchiveFormat: domain.CodesetArchiveTarGz}
+		c, _, _, err := store.Import(context.TODO(), &domain.Codeset{Name: "mlflow-app", Project: "workspace"}, source)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := readCodesetFile(t, c, "MLproject"); got != "name: mlflow-app\n" {
+			t.Errorf("Unexpected MLproject contents: %q", got)
+		}
+		if got := readCodesetFile(t, c, "model/train.py"); got != "print('training')\n" {
+			t.Errorf("Unexpected model/train.py contents: %q", got)
+		}
+	})
+
+	t.Run("zip replaces existing files", func(t *testing.T) {
+		archive := zipArchive(t, map[string]string{"MLproject": "name: mlflow-app-v2\n", "conda.yaml": "name: env\n"})
+		source := &domain.CodesetImportSource{Archive: archive, ArchiveFormat: domain.CodesetArchiveZip}
+		c, _, _, err := store.Import(context.TODO(), &domain.Codeset{Name: "mlflow-app", Project: "workspace"}, source)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := readCodesetFile(t, c, "MLproject"); got != "name: mlflow-app-v2\n" {
+			t.Errorf("Unexpected MLproject contents: %q", got)
+		}
+		revisions, err := store.GetRevisions(context.TODO(), "workspace", "mlflow-app", 10)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(revisions.Commits) != 2 || revisions.Commits[0].Author != importAuthorName {
+			t.Errorf("Unexpected codeset commits: %v", revisions.Commits)
+		}
+		dir := t.TempDir()
+		if _, err = git.PlainClone(dir, false, &git.CloneOptions{URL: c.URL}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = ioutil.ReadFile(filepath.Join(dir, "model", "train.py")); err == nil {
+			t.Errorf("Expected the files missing from the archive to be removed")
+		}
+	})
+}
+
+func TestImportGitRepository(t *testing.T) {
+	store := newLocalCodesetStore(t)
+	importURLSchemes = append(importURLSchemes, "file")
+	defer func() { importURLSchemes = importURLSchemes[:len(importURLSchemes)-1] }()
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1 := commitFile(t, repo, dir, "MLproject", "name: v1\n", "v1.0")
+	commitFile(t, repo, dir, "MLproject", "name: v2\n", "")
+
+	for _, tc := range []struct {
+		ref  string
+		want string
+	}{
+		{"", "name: v2\n"},
+		{"v1.0", "name: v1\n"},
+		{"master", "name: v2\n"},
+		{v1, "name: v1\n"},
+	} {
+		source := &domain.CodesetImportSource{URL: "file://" + dir, Ref: tc.ref}
+		c, _, _, err := store.Import(context.TODO(), &domain.Codeset{Name: "mlflow-app", Project: "workspace"}, source)
+		if err != nil {
+			t.Fatalf("Unexpected error importing ref %q: %v", tc.ref, err)
+		}
+		if got := readCodesetFile(t, c, "MLproject"); got != tc.want {
+			t.Errorf("Unexpected MLproject contents for ref %q: %q", tc.ref, got)
+		}
+	}
+
+	source := &domain.CodesetImportSource{URL: "file://" + dir, Ref: "unknown"}
+	_, _, _, err = store.Import(context.TODO(), &domain.Codeset{Name: "mlflow-app", Project: "workspace"}, source)
+	if !errors.Is(err, domain.ErrInvalidCodesetImport) {
+		t.Errorf("Expected %q error, got %v", domain.ErrInvalidCodesetImport, err)
+	}
+
+	maxRepositorySize = 1024
+	defer func() { maxRepositorySize = 1024 * 1024 * 1024 }()
+	for _, ref := range []string{"", v1} {
+		source = &domain.CodesetImportSource{URL: "file://" + dir, Ref: ref}
+		_, _, _, err = store.Import(context.TODO(), &domain.Codeset{Name: "mlflow-app", Project: "workspace"}, source)
+		if !errors.Is(err, domain.ErrInvalidCodesetImport) {
+			t.Errorf("Expected %q error importing ref %q over the size limit, got %v", domain.ErrInvalidCodesetImport, ref, err)
+		}
+	}
+}
+
+func TestImportRestrictedNetworks(t *testing.T) {
+	store := newLocalCodesetStore(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	for _, url := range []string{server.URL + "/mlflow-app.git", "http://169.254.169.254/latest/meta-data", "http://10.0.0.1/mlflow-app.git"} {
+		source := &domain.CodesetImportSource{URL: url}
+		_, _, _, err := store.Import(context.TODO(), &domain.Codeset{Name: "mlflow-app", Project: "workspace"}, source)
+		if !errors.Is(err, domain.ErrInvalidCodesetImport) || !strings.Contains(err.Error(), "not allowed") {
+			t.Errorf("Expected importing from %s not to be allowed, got %v", url, err)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests to the loopback address, got %d", requests)
+	}
+
+	if err := SetImportAllowedNetworks([]string{"127.0.0.0/8"}); err != nil {
+		t.Fatal(err)
+	}
+	defer SetImportAllowedNetworks(nil)
+	source := &domain.CodesetImportSource{URL: server.URL + "/mlflow-app.git"}
+	if _, _, _, err := store.Import(context.TODO(), &domain.Codeset{Name: "mlflow-app", Project: "workspace"}, source); err == nil {
+		t.Error("Expected the import of a missing repository to fail")
+	}
+	if requests == 0 {
+		t.Error("Expected importing from an allowed network to reach the server")
+	}
+}
+
+func TestImportInvalidSource(t *testing.T) {
+	store := newLocalCodesetStore(t)
+	archive := zipArchive(t, map[string]string{"MLproject": "name: mlflow-app\n"})
+
+	for name, source := range map[string]*domain.CodesetImportSource{
+		"no source":        {},
+		"both sources":     {URL: "https://github.com/fuseml/examples", Archive: archive, ArchiveFormat: domain.CodesetArchiveZip},
+		"local path":       {URL: "/etc"},
+		"file URL":         {URL: "file:///etc"},
+		"unknown format":   {Archive: archive, ArchiveFormat: "rar"},
+		"wrong format":     {Archive: archive, ArchiveFormat: domain.CodesetArchiveTarGz},
+		"path traversal":   {Archive: zipArchive(t, map[string]string{"../MLproject": ""}), ArchiveFormat: domain.CodesetArchiveZip},
+		"nested traversal": {Archive: zipArchive(t, map[string]string{"a/../../b": ""}), ArchiveFormat: domain.CodesetArchiveZip},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, _, err := store.Import(context.TODO(), &domain.Codeset{Name: "mlflow-app", Project: "workspace"}, source)
+			if !errors.Is(err, domain.ErrInvalidCodesetImport) {
+				t.Errorf("Expected %q error, got %v", domain.ErrInvalidCodesetImport, err)
+			}
+		})
+	}
+	if codesets, err := store.GetAll(context.TODO(), nil, nil); err != nil || len(codesets) != 0 {
+		t.Errorf("Expected no codesets to be created, got %v (error: %v)", codesets, err)
+	}
+}
+
+// brokenPushAdminClient prepares the codeset repositories with an URL to which nothing can be pushed
+type brokenPushAdminClient struct {
+	domain.GitAdminClient
+}
+
+func (c brokenPushAdminClient) PrepareRepository(codeset *domain.Codeset, citoken *string) (*string, *string, error) {
+	username, password, err := c.GitAdminClient.PrepareRepository(codeset, citoken)
+	codeset.URL = "file:///nonexistent/" + codeset.Name
+	return username, password, err
+}
+
+func (c brokenPushAdminClient) PushCredentials() (string, string) {
+	return c.GitAdminClient.(pushCredentialsProvider).PushCredentials()
+}
+
+func TestImportPushFailure(t *testing.T) {
+	store := newLocalCodesetStore(t)
+	store.gitAdmin = brokenPushAdminClient{store.gitAdmin}
+	archive := zipArchive(t, map[string]string{"MLproject": "name: mlflow-app\n"})
+
+	source := &domain.CodesetImportSource{Archive: archive, ArchiveFormat: domain.CodesetArchiveZip}
+	if _, _, _, err := store.Import(context.TODO(), &domain.Codeset{Name: "mlflow-app", Project: "workspace"}, source); err == nil {
+		t.Fatal("Expected the import to fail")
+	}
+	if codesets, err := store.GetAll(context.TODO(), nil, nil); err != nil || len(codesets) != 0 {
+		t.Errorf("Expected the codeset to be removed, got %v (error: %v)", codesets, err)
+	}
+}
+
+func TestImportUnsupportedProvider(t *testing.T) {
+	store := newLocalCodesetStore(t)
+	// hide the push credentials of the local git provider
+	store.gitAdmin = struct{ domain.GitAdminClient }{store.gitAdmin}
+	archive := zipArchive(t, map[string]string{"MLproject": "name: mlflow-app\n"})
+
+	source := &domain.CodesetImportSource{Archive: archive, ArchiveFormat: domain.CodesetArchiveZip}
+	_, _, _, err := store.Import(context.TODO(), &domain.Codeset{Name: "mlflow-app", Project: "workspace"}, source)
+	if !errors.Is(err, domain.ErrCodesetImportUnsupported) {
+		t.Errorf("Expected %q error, got %v", domain.ErrCodesetImportUnsupported, err)
+	}
+	if codesets, err := store.GetAll(context.TODO(), nil, nil); err != nil || len(codesets) != 0 {
+		t.Errorf("Expected no codesets to be created, got %v (error: %v)", codesets, err)
+	}
+}
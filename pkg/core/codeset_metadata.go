@@ -0,0 +1,179 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+// codesetFormat describes a format that can be detected from the files found at the top of a codeset
+type codesetFormat struct {
+	// the name of the format, as used in the runnable codeset artifact descriptions
+	format string
+	// the type of information contained in the files of this format
+	types []string
+	// the label added to the codesets containing files of this format
+	label string
+	// the file names, or the file extensions if starting with a dot, of this format
+	files []string
+	// optional parser returning the software requirements listed in a file of this format
+	requirements func(data []byte) (map[string]string, error)
+}
+
+// codesetFormats are the formats detected when inspecting codesets
+var codesetFormats = []codesetFormat{
+	{format: "MLProject", types: []string{"code"}, label: "mlflow", files: []string{"MLproject"}},
+	{format: "conda", types: []string{"configuration"}, label: "conda",
+		files: []string{"conda.yaml", "conda.yml", "environment.yaml", "environment.yml"}, requirements: condaRequirements},
+	{format: "pip", types: []string{"configuration"}, label: "pip", files: []string{"requirements.txt"},
+		requirements: pipRequirements},
+	{format: "Dockerfile", types: []string{"container"}, label: "docker", files: []string{"Dockerfile"}},
+	{format: "Jupyter", types: []string{"code"}, label: "jupyter", files: []string{".ipynb"}},
+	{format: "python", types: []string{"code"}, label: "python", files: []string{".py"}},
+}
+
+func (f *codesetFormat) match(name string) bool {
+	for _, file := range f.files {
+		if name == file || (strings.HasPrefix(file, ".") && path.Ext(name) == file) {
+			return true
+		}
+	}
+	return false
+}
+
+// inspectCodeset detects the metadata of a codeset from the files found at the top of the given commit
+func (cs *GitCodesetStore) inspectCodeset(c *domain.Codeset, commit string) (*domain.CodesetMetadata, error) {
+	files, err := cs.gitAdmin.GetRepositoryFiles(c.Project, c.Name, commit, "")
+	if err != nil {
+		return nil, err
+	}
+	metadata := &domain.CodesetMetadata{
+		Commit:       commit,
+		Type:         []string{},
+		Format:       []string{},
+		Requirements: map[string]string{},
+		Files:        []string{},
+		Labels:       []string{},
+	}
+	for _, file := range files {
+		if file.Type != domain.CodesetFileTypeFile {
+			continue
+		}
+		for i := range codesetFormats {
+			format := &codesetFormats[i]
+			if !format.match(path.Base(file.Path)) {
+				continue
+			}
+			metadata.Files = append(metadata.Files, file.Path)
+			metadata.Type = appendMissing(metadata.Type, format.types...)
+			metadata.Format = appendMissing(metadata.Format, format.format)
+			metadata.Labels = appendMissing(metadata.Labels, format.label)
+			if format.requirements == nil {
+				continue
+			}
+			data, err := cs.gitAdmin.GetRepositoryFileContents(c.Project, c.Name, commit, file.Path)
+			if err != nil {
+				return nil, err
+			}
+			requirements, err := format.requirements(data)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to parse %s", file.Path)
+			}
+			for pkg, version := range requirements {
+				if _, ok := metadata.Requirements[pkg]; !ok {
+					metadata.Requirements[pkg] = version
+				}
+			}
+		}
+	}
+	return metadata, nil
+}
+
+// pipRequirements returns the packages listed in a pip requirements file. Options, URLs and local paths are skipped
+func pipRequirements(data []byte) (map[string]string, error) {
+	requirements := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		addPipRequirement(requirements, scanner.Text())
+	}
+	return requirements, scanner.Err()
+}
+
+func addPipRequirement(requirements map[string]string, line string) {
+	if i := strings.Index(line, "#"); i >= 0 {
+		line = line[:i]
+	}
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "-") || strings.HasPrefix(line, ".") || strings.Contains(line, "://") {
+		return
+	}
+	// drop the environment markers
+	if i := strings.Index(line, ";"); i >= 0 {
+		line = strings.TrimSpace(line[:i])
+	}
+	name, version := line, ""
+	if i := strings.IndexAny(line, "<>=!~ "); i >= 0 {
+		name, version = line[:i], strings.TrimSpace(line[i:])
+	}
+	// drop the package extras
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	requirements[strings.ToLower(name)] = strings.TrimPrefix(version, "==")
+}
+
+// condaEnvironment is the part of a conda environment file listing the environment dependencies
+type condaEnvironment struct {
+	Dependencies []interface{} `json:"dependencies"`
+}
+
+// condaRequirements returns the packages listed in a conda environment file, including the pip dependencies
+func condaRequirements(data []byte) (map[string]string, error) {
+	env := condaEnvironment{}
+	if err := yaml.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	requirements := map[string]string{}
+	for _, dep := range env.Dependencies {
+		switch dep := dep.(type) {
+		case string:
+			// strip the channel, if any
+			if i := strings.LastIndex(dep, "::"); i >= 0 {
+				dep = dep[i+2:]
+			}
+			name, version := strings.TrimSpace(dep), ""
+			if i := strings.IndexAny(name, "<>=!~ "); i >= 0 {
+				name, version = name[:i], strings.TrimSpace(name[i:])
+			}
+			if version != "" && !strings.ContainsAny(version[:1], "<>!~") {
+				version = strings.TrimLeft(version, "=")
+			}
+			requirements[strings.ToLower(name)] = version
+		case map[string]interface{}:
+			pipDeps, _ := dep["pip"].([]interface{})
+			for _, pipDep := range pipDeps {
+				if line, ok := pipDep.(string); ok {
+					addPipRequirement(requirements, line)
+				}
+			}
+		}
+	}
+	return requirements, nil
+}
+
+// appendMissing appends to a slice the values that it does not contain yet
+func appendMissing(slice []string, values ...string) []string {
+	for _, v := range values {
+		if !util.StringInSlice(v, slice) {
+			slice = append(slice, v)
+		}
+	}
+	return slice
+}
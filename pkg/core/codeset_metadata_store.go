@@ -0,0 +1,44 @@
+package core
+
+import (
+	"context"
+	"sync"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// CodesetMetadataStore describes in memory store for codeset metadata
+type CodesetMetadataStore struct {
+	sync.RWMutex
+	items map[codesetID]*domain.CodesetMetadata
+}
+
+// NewCodesetMetadataStore returns an in-memory codeset metadata store instance
+func NewCodesetMetadataStore() *CodesetMetadataStore {
+	return &CodesetMetadataStore{
+		items: make(map[codesetID]*domain.CodesetMetadata),
+	}
+}
+
+// Find returns the metadata of a codeset, or nil if the codeset has none
+func (ms *CodesetMetadataStore) Find(ctx context.Context, project, name string) *domain.CodesetMetadata {
+	ms.RLock()
+	defer ms.RUnlock()
+	return ms.items[codesetID{name, project}]
+}
+
+// Save adds or replaces the metadata of a codeset
+func (ms *CodesetMetadataStore) Save(ctx context.Context, project, name string, metadata *domain.CodesetMetadata) error {
+	ms.Lock()
+	defer ms.Unlock()
+	ms.items[codesetID{name, project}] = metadata
+	return nil
+}
+
+// Delete removes the metadata of a codeset
+func (ms *CodesetMetadataStore) Delete(ctx context.Context, project, name string) error {
+	ms.Lock()
+	defer ms.Unlock()
+	delete(ms.items, codesetID{name, project})
+	return nil
+}
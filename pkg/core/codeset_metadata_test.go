@@ -0,0 +1,169 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+const testCondaEnv = `name: mlflow-env
+channels:
+  - defaults
+dependencies:
+  - python=3.8
+  - conda-forge::numpy>=1.19
+  - pip
+  - pip:
+    - mlflow
+    - scikit-learn==0.22.3
+`
+
+const testPipRequirements = `# training requirements
+--index-url https://pypi.org/simple
+pandas >= 1.1, < 2.0
+Requests[security]==2.25.1 ; python_version > "3.6"
+./local-package
+git+https://github.com/fuseml/examples.git
+`
+
+func TestCodesetMetadata(t *testing.T) {
+	store := newLocalCodesetStore(t)
+
+	c, _, _, err := store.Add(context.TODO(), &domain.Codeset{Name: "empty", Project: "workspace", Labels: []string{"demo"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c, err = store.Find(context.TODO(), "workspace", "empty"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.Metadata != nil {
+		t.Errorf("Expected no metadata for an empty codeset, got %v", c.Metadata)
+	}
+
+	archive := zipArchive(t, map[string]string{
+		"MLproject":        "name: mlflow-app\n",
+		"conda.yaml":       testCondaEnv,
+		"requirements.txt": testPipRequirements,
+		"train.ipynb":      "{}",
+		"data/Dockerfile":  "FROM python:3.8\n",
+	})
+	source := &domain.CodesetImportSource{Archive: archive, ArchiveFormat: domain.CodesetArchiveZip}
+	c, _, _, err = store.Import(context.TODO(), &domain.Codeset{Name: "mlflow-app", Project: "workspace", Labels: []string{"demo"}}, source)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := &domain.CodesetMetadata{
+		Type:   []string{"code", "configuration"},
+		Format: []string{"MLProject", "conda", "pip", "Jupyter"},
+		Requirements: map[string]string{
+			"python":       "3.8",
+			"numpy":        ">=1.19",
+			"pip":          "",
+			"mlflow":       "",
+			"scikit-learn": "0.22.3",
+			"pandas":       ">= 1.1, < 2.0",
+			"requests":     "2.25.1",
+		},
+		Files:  []string{"MLproject", "conda.yaml", "requirements.txt", "train.ipynb"},
+		Labels: []string{"mlflow", "conda", "pip", "jupyter"},
+	}
+	if c.Metadata == nil {
+		t.Fatalf("Expected the metadata of the imported codeset to be set")
+	}
+	want.Commit = c.Metadata.Commit
+	if d := cmp.Diff(want, c.Metadata); d != "" {
+		t.Errorf("Unexpected codeset metadata (-want +got): %s", d)
+	}
+
+	// the metadata is kept by the metadata store, and the detected labels are added to the codeset labels, so that
+	// they can be matched by the git provider
+	store = NewGitCodesetStore(store.gitAdmin, store.metadataStore)
+	codesets, err := store.GetAll(context.TODO(), nil, util.RefString("mlflow"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(codesets) != 1 || codesets[0].Name != "mlflow-app" {
+		t.Fatalf("Expected only the mlflow-app codeset to be labeled mlflow, got %v", codesets)
+	}
+	if d := cmp.Diff([]string{"demo", "mlflow", "conda", "pip", "jupyter"}, codesets[0].Labels); d != "" {
+		t.Errorf("Unexpected codeset labels (-want +got): %s", d)
+	}
+	if d := cmp.Diff(want, codesets[0].Metadata); d != "" {
+		t.Errorf("Unexpected codeset metadata (-want +got): %s", d)
+	}
+
+	// pushing new contents outside of FuseML
+	dir := t.TempDir()
+	repo, err := git.PlainClone(dir, false, &git.CloneOptions{URL: c.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit := commitFile(t, repo, dir, "Dockerfile", "FROM python:3.8\n", "")
+	if err = repo.Push(&git.PushOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// listing codesets does not inspect their contents
+	if codesets, err = store.GetAll(context.TODO(), util.RefString("workspace"), nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, c := range codesets {
+		if c.Name == "mlflow-app" && (c.Metadata == nil || c.Metadata.Commit != want.Commit) {
+			t.Errorf("Expected the metadata of the previous commit, got %v", c.Metadata)
+		}
+	}
+	// reading a codeset inspects the new commit
+	want.Commit = commit
+	want.Type = append([]string{"container"}, want.Type...)
+	want.Format = append([]string{"Dockerfile"}, want.Format...)
+	want.Files = append([]string{"Dockerfile"}, want.Files...)
+	want.Labels = append([]string{"docker"}, want.Labels...)
+	if c, err = store.Find(context.TODO(), "workspace", "mlflow-app"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff(want, c.Metadata); d != "" {
+		t.Errorf("Unexpected codeset metadata (-want +got): %s", d)
+	}
+	if d := cmp.Diff(want, store.metadataStore.Find(context.TODO(), "workspace", "mlflow-app")); d != "" {
+		t.Errorf("Unexpected stored codeset metadata (-want +got): %s", d)
+	}
+	if c, err = store.Inspect(context.TODO(), "workspace", "mlflow-app"); err != nil || c.Metadata == nil || c.Metadata.Commit != commit {
+		t.Errorf("Unexpected codeset metadata: %v (error: %v)", c, err)
+	}
+
+	if err = store.Delete(context.TODO(), "workspace", "mlflow-app"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if metadata := store.metadataStore.Find(context.TODO(), "workspace", "mlflow-app"); metadata != nil {
+		t.Errorf("Expected the metadata of the deleted codeset to be removed, got %v", metadata)
+	}
+}
+
+func TestCodesetMetadataMatches(t *testing.T) {
+	metadata := &domain.CodesetMetadata{
+		Type:         []string{"code", "configuration"},
+		Format:       []string{"MLProject", "conda"},
+		Requirements: map[string]string{"mlflow": "", "scikit-learn": "0.22.3"},
+	}
+	for _, tc := range []struct {
+		artifact domain.RunnableCodesetArtifact
+		want     bool
+	}{
+		{domain.RunnableCodesetArtifact{}, true},
+		{domain.RunnableCodesetArtifact{Type: []string{"code"}, Format: []string{"mlproject"}}, true},
+		{domain.RunnableCodesetArtifact{Requirements: map[string]string{"MLflow": ">=1.15"}}, true},
+		{domain.RunnableCodesetArtifact{Type: []string{"container"}}, false},
+		{domain.RunnableCodesetArtifact{Format: []string{"MLProject", "pip"}}, false},
+		{domain.RunnableCodesetArtifact{Requirements: map[string]string{"tensorflow": ""}}, false},
+	} {
+		if got := metadata.Matches(&tc.artifact); got != tc.want {
+			t.Errorf("Expected match %v for %+v, got %v", tc.want, tc.artifact, got)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
 type codesetID struct {
@@ -17,26 +18,33 @@ type codesetID struct {
 type GitCodesetStore struct {
 	gitAdmin    domain.GitAdminClient
 	subscribers map[codesetID][]domain.CodesetSubscriber
+	// metadata detected from the most recent commit of each codeset
+	metadataStore domain.CodesetMetadataStore
 }
 
 // NewGitCodesetStore returns codeset store instance
-func NewGitCodesetStore(gitAdmin domain.GitAdminClient) *GitCodesetStore {
+func NewGitCodesetStore(gitAdmin domain.GitAdminClient, metadataStore domain.CodesetMetadataStore) *GitCodesetStore {
 	subscribers := make(map[codesetID][]domain.CodesetSubscriber)
-	return &GitCodesetStore{gitAdmin, subscribers}
+	return &GitCodesetStore{gitAdmin: gitAdmin, subscribers: subscribers, metadataStore: metadataStore}
 }
 
-// Find returns a codeset identified by project and name
+// Find returns a codeset identified by project and name. The codeset contents are inspected again when
+// new commits were pushed since they were last inspected.
 func (cs *GitCodesetStore) Find(ctx context.Context, project, name string) (*domain.Codeset, error) {
 	result, err := cs.gitAdmin.GetRepository(project, name)
 	if err != nil {
 		return nil, errors.Wrap(err, "Fetching Codeset failed")
 	}
+	// the codeset is still returned if it cannot be inspected, with the metadata detected previously
+	if err = cs.updateMetadata(ctx, result); err != nil {
+		cs.setMetadata(ctx, result)
+	}
 	return result, nil
 }
 
 // Delete removes a codeset identified by project and name
 func (cs *GitCodesetStore) Delete(ctx context.Context, project, name string) error {
-	codeset, err := cs.Find(ctx, project, name)
+	codeset, err := cs.gitAdmin.GetRepository(project, name)
 	if err != nil {
 		return nil
 	}
@@ -51,15 +59,113 @@ func (cs *GitCodesetStore) Delete(ctx context.Context, project, name string) err
 	}
 	// upon a codeset deletion all subscribers associated to that codeset also needs to be removed
 	cs.deleteSubscribers(codeset)
+	if err = cs.metadataStore.Delete(ctx, project, name); err != nil {
+		return errors.Wrap(err, "Deleting Codeset metadata failed")
+	}
 	return nil
 }
 
-// GetAll returns all codesets matching given project and label
+// GetAll returns all codesets matching given project and label, with the metadata detected when they were
+// last inspected
 func (cs *GitCodesetStore) GetAll(ctx context.Context, project, label *string) ([]*domain.Codeset, error) {
-	result, err := cs.gitAdmin.GetRepositories(project, label)
+	codesets, err := cs.gitAdmin.GetRepositories(project, label)
 	if err != nil {
 		return nil, errors.Wrap(err, "Fetching Codesets failed")
 	}
+	for _, c := range codesets {
+		cs.setMetadata(ctx, c)
+	}
+	return codesets, nil
+}
+
+// Inspect detects the metadata of a codeset from the contents of its most recent commit and adds the labels
+// associated with the detected formats to the codeset labels. The codeset contents are only inspected when
+// a new commit is found. The metadata is left unset if the codeset is empty.
+func (cs *GitCodesetStore) Inspect(ctx context.Context, project, name string) (*domain.Codeset, error) {
+	c, err := cs.gitAdmin.GetRepository(project, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fetching Codeset failed")
+	}
+	if err = cs.updateMetadata(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// updateMetadata sets the metadata of a codeset to the metadata detected from its most recent commit
+func (cs *GitCodesetStore) updateMetadata(ctx context.Context, c *domain.Codeset) error {
+	revisions, err := cs.gitAdmin.GetRepositoryRevisions(c.Project, c.Name, 1)
+	if err != nil {
+		return errors.Wrap(err, "Fetching Codeset revisions failed")
+	}
+	if len(revisions.Commits) == 0 {
+		return nil
+	}
+	commit := revisions.Commits[0].ID
+
+	metadata := cs.metadataStore.Find(ctx, c.Project, c.Name)
+	if metadata == nil || metadata.Commit != commit {
+		if metadata, err = cs.inspectCodeset(c, commit); err != nil {
+			return errors.Wrap(err, "Inspecting Codeset failed")
+		}
+		if labels := missingLabels(c.Labels, metadata.Labels); len(labels) > 0 {
+			if err = cs.gitAdmin.AddRepositoryLabels(c.Project, c.Name, labels); err != nil {
+				return errors.Wrap(err, "Adding Codeset labels failed")
+			}
+		}
+		if err = cs.metadataStore.Save(ctx, c.Project, c.Name, metadata); err != nil {
+			return errors.Wrap(err, "Saving Codeset metadata failed")
+		}
+	}
+	c.Labels = appendMissing(c.Labels, metadata.Labels...)
+	c.Metadata = metadata
+	return nil
+}
+
+// setMetadata sets the metadata of a codeset to the metadata detected when it was last inspected, if any
+func (cs *GitCodesetStore) setMetadata(ctx context.Context, c *domain.Codeset) {
+	if metadata := cs.metadataStore.Find(ctx, c.Project, c.Name); metadata != nil {
+		c.Labels = appendMissing(c.Labels, metadata.Labels...)
+		c.Metadata = metadata
+	}
+}
+
+// missingLabels returns the labels that are not found in the codeset labels
+func missingLabels(codesetLabels, labels []string) []string {
+	missing := []string{}
+	for _, label := range labels {
+		if !util.StringInSlice(label, codesetLabels) {
+			missing = append(missing, label)
+		}
+	}
+	return missing
+}
+
+// GetRevisions returns the branches, tags and the most recent commits of a codeset
+func (cs *GitCodesetStore) GetRevisions(ctx context.Context, project, name string, commits int) (*domain.CodesetRevisions, error) {
+	result, err := cs.gitAdmin.GetRepositoryRevisions(project, name, commits)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fetching Codeset revisions failed")
+	}
+	return result, nil
+}
+
+// GetFiles returns the entries of a codeset directory at the given revision. A single entry is returned
+// if the path points to a file
+func (cs *GitCodesetStore) GetFiles(ctx context.Context, project, name, ref, path string) ([]*domain.CodesetFile, error) {
+	result, err := cs.gitAdmin.GetRepositoryFiles(project, name, ref, path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fetching Codeset files failed")
+	}
+	return result, nil
+}
+
+// GetFileContents returns the contents of a codeset file at the given revision
+func (cs *GitCodesetStore) GetFileContents(ctx context.Context, project, name, ref, path string) ([]byte, error) {
+	result, err := cs.gitAdmin.GetRepositoryFileContents(project, name, ref, path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fetching Codeset file contents failed")
+	}
 	return result, nil
 }
 
@@ -81,6 +187,15 @@ func (cs *GitCodesetStore) DeleteWebhook(ctx context.Context, c *domain.Codeset,
 	return nil
 }
 
+// GetWebhooks returns the webhooks of a codeset
+func (cs *GitCodesetStore) GetWebhooks(ctx context.Context, c *domain.Codeset) ([]*domain.CodesetWebhook, error) {
+	hooks, err := cs.gitAdmin.GetRepoWebhooks(c.Project, c.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fetching webhooks failed")
+	}
+	return hooks, nil
+}
+
 // Add creates new codeset
 func (cs *GitCodesetStore) Add(ctx context.Context, c *domain.Codeset) (*domain.Codeset, *string, *string, error) {
 	username, password, err := cs.gitAdmin.PrepareRepository(c, nil)
@@ -91,12 +206,19 @@ func (cs *GitCodesetStore) Add(ctx context.Context, c *domain.Codeset) (*domain.
 	return c, username, password, nil
 }
 
-// Subscribe adds a subscriber interested on operations performed on a specific codeset
+// Subscribe adds a subscriber interested on operations performed on a specific codeset. Subscribing again
+// to the same codeset has no effect
 func (cs *GitCodesetStore) Subscribe(ctx context.Context, subscriber domain.CodesetSubscriber, codeset *domain.Codeset) error {
-	if _, err := cs.Find(ctx, codeset.Project, codeset.Name); err != nil {
-		return err
+	if _, err := cs.gitAdmin.GetRepository(codeset.Project, codeset.Name); err != nil {
+		return errors.Wrap(err, "Fetching Codeset failed")
+	}
+	id := codesetID{codeset.Name, codeset.Project}
+	for _, s := range cs.subscribers[id] {
+		if s == subscriber {
+			return nil
+		}
 	}
-	cs.subscribers[codesetID{codeset.Name, codeset.Project}] = append(cs.subscribers[codesetID{codeset.Name, codeset.Project}], subscriber)
+	cs.subscribers[id] = append(cs.subscribers[id], subscriber)
 	return nil
 }
 
@@ -6,10 +6,8 @@ const (
 )
 
 var (
-	// DefaultUserNamePrefix is the default prefix for user names created for each project
+	// DefaultUserNamePrefix is the default prefix for the names of the default project owners
 	DefaultUserNamePrefix = "fuseml"
-	// DefaultUserPassword is the default password used when creating new per-project users
-	DefaultUserPassword = "changeme"
 	// DefaultUserEmailDomain is the default domain for user email
 	DefaultUserEmailDomain = "@fuseml.org"
 
@@ -17,12 +15,12 @@ var (
 	HookSecret = "generatedsecret"
 )
 
-// DefaultUserName returns default user name for new per-project user
+// DefaultUserName returns the name of the default owner created for a new project
 func DefaultUserName(org string) string {
 	return DefaultUserNamePrefix + "-" + org
 }
 
-// DefaultUserEmail returns default email for new per-project user
+// DefaultUserEmail returns the email of the default owner created for a new project
 func DefaultUserEmail(org string) string {
 	return DefaultUserName(org) + DefaultUserEmailDomain
 }
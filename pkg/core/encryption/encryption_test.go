@@ -0,0 +1,106 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncrypt(t *testing.T) {
+	key1 := &Key{ID: "key1", Secret: []byte(strings.Repeat("1", KeySize))}
+	key2 := &Key{ID: "key2", Secret: []byte(strings.Repeat("2", KeySize))}
+	provider, err := NewStaticKeyProvider(key1, key2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	envelope, err := Encrypt(provider, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(envelope) || strings.Contains(envelope, "secret") {
+		t.Errorf("Unexpected encrypted data %q", envelope)
+	}
+	if keyID, _ := KeyID(envelope); keyID != "key1" {
+		t.Errorf("Expected the data to be encrypted with the current key, got %q", keyID)
+	}
+
+	t.Run("decrypt", func(t *testing.T) {
+		got, err := Decrypt(provider, envelope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "secret" {
+			t.Errorf("got %q want %q", got, "secret")
+		}
+	})
+
+	t.Run("previous key", func(t *testing.T) {
+		rotated, _ := NewStaticKeyProvider(key2, key1)
+		got, err := Decrypt(rotated, envelope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "secret" {
+			t.Errorf("got %q want %q", got, "secret")
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		other, _ := NewStaticKeyProvider(key2)
+		_, err := Decrypt(other, envelope)
+		if !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("got error %v want %v", err, ErrKeyNotFound)
+		}
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		wrong, _ := NewStaticKeyProvider(&Key{ID: "key1", Secret: key2.Secret})
+		if _, err := Decrypt(wrong, envelope); err == nil {
+			t.Errorf("Expected decrypting with the wrong key to fail")
+		}
+	})
+
+	t.Run("invalid data", func(t *testing.T) {
+		if _, err := Decrypt(provider, "secret"); !errors.Is(err, errInvalidEnvelope) {
+			t.Errorf("got error %v want %v", err, errInvalidEnvelope)
+		}
+	})
+}
+
+func TestParseKeys(t *testing.T) {
+	secret1 := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("1", KeySize)))
+	secret2 := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("2", KeySize)))
+
+	keys, err := ParseKeys(strings.NewReader("# current key\nkey2:" + secret2 + "\n\nkey1:" + secret1 + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider, err := NewStaticKeyProvider(keys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current, _ := provider.CurrentKey(); current.ID != "key2" {
+		t.Errorf("Expected the first key to be the current key, got %q", current.ID)
+	}
+	if _, err := provider.Key("key1"); err != nil {
+		t.Errorf("Expected the previous key to be found, got %v", err)
+	}
+
+	keys, err = ParseKeys(strings.NewReader("key2:" + secret2 + ",key1:" + secret1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("Expected 2 comma separated keys, got %d", len(keys))
+	}
+
+	if _, err := ParseKeys(strings.NewReader("key1")); err == nil {
+		t.Errorf("Expected a key without secret to be refused")
+	}
+	short := base64.StdEncoding.EncodeToString([]byte("short"))
+	if _, err := NewStaticKeyProvider(&Key{ID: "key1", Secret: []byte(short)}); err == nil {
+		t.Errorf("Expected a key of the wrong size to be refused")
+	}
+}
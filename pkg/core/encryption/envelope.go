@@ -0,0 +1,121 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// envelopePrefix marks the version of the format of the encrypted data
+	envelopePrefix = "fuseml-enc-v1"
+
+	errInvalidEnvelope = Err("invalid encrypted data")
+)
+
+// Encrypt encrypts data using envelope encryption: the data is encrypted with a new random data key, which is
+// in turn encrypted with the current key of the key provider. The result holds the ID of the key, the encrypted
+// data key and the encrypted data, so that rotating the key only requires the data key to be re-encrypted.
+func Encrypt(provider KeyProvider, plaintext []byte) (string, error) {
+	key, err := provider.CurrentKey()
+	if err != nil {
+		return "", err
+	}
+	dataKey := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", fmt.Errorf("error generating data key: %w", err)
+	}
+	wrappedKey, err := seal(key.Secret, dataKey)
+	if err != nil {
+		return "", err
+	}
+	ciphertext, err := seal(dataKey, plaintext)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{envelopePrefix, key.ID, base64.StdEncoding.EncodeToString(wrappedKey),
+		base64.StdEncoding.EncodeToString(ciphertext)}, ":"), nil
+}
+
+// Decrypt decrypts data encrypted by Encrypt, using the key of the key provider it was encrypted with
+func Decrypt(provider KeyProvider, envelope string) ([]byte, error) {
+	keyID, wrappedKey, ciphertext, err := parseEnvelope(envelope)
+	if err != nil {
+		return nil, err
+	}
+	key, err := provider.Key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := open(key.Secret, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting data key with encryption key %q: %w", keyID, err)
+	}
+	return open(dataKey, ciphertext)
+}
+
+// IsEncrypted returns true if the value holds data encrypted by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, envelopePrefix+":")
+}
+
+// KeyID returns the ID of the key the data was encrypted with
+func KeyID(envelope string) (string, error) {
+	keyID, _, _, err := parseEnvelope(envelope)
+	return keyID, err
+}
+
+func parseEnvelope(envelope string) (keyID string, wrappedKey, ciphertext []byte, err error) {
+	parts := strings.Split(envelope, ":")
+	if len(parts) != 4 || parts[0] != envelopePrefix {
+		return "", nil, nil, errInvalidEnvelope
+	}
+	if wrappedKey, err = base64.StdEncoding.DecodeString(parts[2]); err != nil {
+		return "", nil, nil, errInvalidEnvelope
+	}
+	if ciphertext, err = base64.StdEncoding.DecodeString(parts[3]); err != nil {
+		return "", nil, nil, errInvalidEnvelope
+	}
+	return parts[1], wrappedKey, ciphertext, nil
+}
+
+// seal encrypts the data with AES-GCM, prepending the random nonce to the result
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts data encrypted by seal
+func open(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errInvalidEnvelope
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errInvalidEnvelope
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error initializing cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
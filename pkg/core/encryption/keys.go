@@ -0,0 +1,148 @@
+package encryption
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// KeySize is the size in bytes of the keys used to encrypt data (AES-256)
+	KeySize = 32
+
+	// KeysEnvVar is the environment variable from which the encryption keys can be loaded
+	KeysEnvVar = "FUSEML_ENCRYPTION_KEYS"
+
+	// ErrKeyNotFound is returned when the key used to encrypt data is not available
+	ErrKeyNotFound = Err("encryption key not found")
+
+	errNoKeys = Err("no encryption keys supplied")
+)
+
+// Err are expected errors returned when encrypting and decrypting data
+type Err string
+
+// Error returns the error message
+func (e Err) Error() string {
+	return string(e)
+}
+
+// Key is a named key used to encrypt data. The ID is stored along with the encrypted data, so that it can be
+// decrypted after the key used to encrypt new data changes.
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// KeyProvider provides the keys used to encrypt and decrypt data. New data is always encrypted with the current
+// key, while the previous keys are only used to decrypt the data encrypted before the current key was rotated in.
+type KeyProvider interface {
+	// CurrentKey returns the key used to encrypt new data
+	CurrentKey() (*Key, error)
+	// Key returns the key with the given ID, or ErrKeyNotFound if the provider does not know it
+	Key(id string) (*Key, error)
+}
+
+// StaticKeyProvider is a KeyProvider holding a fixed list of keys, the first of which is the current key
+type StaticKeyProvider struct {
+	keys []*Key
+}
+
+// NewStaticKeyProvider initializes a StaticKeyProvider. The first key is used to encrypt new data.
+func NewStaticKeyProvider(keys ...*Key) (*StaticKeyProvider, error) {
+	if len(keys) == 0 {
+		return nil, errNoKeys
+	}
+	ids := map[string]bool{}
+	for _, k := range keys {
+		if k.ID == "" || strings.ContainsAny(k.ID, ":, \t") {
+			return nil, fmt.Errorf("invalid encryption key ID %q", k.ID)
+		}
+		if ids[k.ID] {
+			return nil, fmt.Errorf("duplicate encryption key ID %q", k.ID)
+		}
+		if len(k.Secret) != KeySize {
+			return nil, fmt.Errorf("encryption key %q must be %d bytes long, got %d", k.ID, KeySize, len(k.Secret))
+		}
+		ids[k.ID] = true
+	}
+	return &StaticKeyProvider{keys}, nil
+}
+
+// CurrentKey returns the key used to encrypt new data
+func (p *StaticKeyProvider) CurrentKey() (*Key, error) {
+	return p.keys[0], nil
+}
+
+// Key returns the key with the given ID
+func (p *StaticKeyProvider) Key(id string) (*Key, error) {
+	for _, k := range p.keys {
+		if k.ID == id {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrKeyNotFound, id)
+}
+
+// ParseKeys parses a list of keys, one per line or separated by commas, each key in the `ID:BASE64-SECRET`
+// format. Empty lines and lines starting with '#' are ignored.
+func ParseKeys(r io.Reader) ([]*Key, error) {
+	keys := []*Key{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, entry := range strings.Split(line, ",") {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			parts := strings.SplitN(entry, ":", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid encryption key entry, expected ID:BASE64-SECRET")
+			}
+			secret, err := base64.StdEncoding.DecodeString(parts[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid encryption key %q: %w", parts[0], err)
+			}
+			keys = append(keys, &Key{ID: parts[0], Secret: secret})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// NewKeyProviderFromFile initializes a StaticKeyProvider with the keys found in a file
+func NewKeyProviderFromFile(path string) (*StaticKeyProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading encryption keys: %w", err)
+	}
+	defer f.Close()
+	keys, err := ParseKeys(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading encryption keys from %s: %w", path, err)
+	}
+	return NewStaticKeyProvider(keys...)
+}
+
+// NewKeyProviderFromEnv initializes a StaticKeyProvider with the keys set in the FUSEML_ENCRYPTION_KEYS
+// environment variable. It returns nil if the variable is not set.
+func NewKeyProviderFromEnv() (*StaticKeyProvider, error) {
+	value, exists := os.LookupEnv(KeysEnvVar)
+	if !exists {
+		return nil, nil
+	}
+	keys, err := ParseKeys(strings.NewReader(value))
+	if err != nil {
+		return nil, fmt.Errorf("error reading encryption keys from %s: %w", KeysEnvVar, err)
+	}
+	return NewStaticKeyProvider(keys...)
+}
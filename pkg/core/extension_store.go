@@ -184,6 +184,16 @@ func (store *ExtensionStore) UpdateExtensionServiceEndpoint(ctx context.Context,
 	return extension.UpdateServiceEndpoint(serviceID, endpoint)
 }
 
+// SetExtensionServiceEndpointStatus records the result of a health check run against an extension endpoint.
+func (store *ExtensionStore) SetExtensionServiceEndpointStatus(ctx context.Context, extensionID string, serviceID string, endpointID string,
+	status *domain.ExtensionServiceEndpointStatus) error {
+	extension, err := store.GetExtension(ctx, extensionID)
+	if err != nil {
+		return err
+	}
+	return extension.SetServiceEndpointStatus(serviceID, endpointID, status)
+}
+
 // DeleteExtensionServiceEndpoint deletes an extension endpoint from an extension service.
 func (store *ExtensionStore) DeleteExtensionServiceEndpoint(ctx context.Context, extensionID, serviceID, endpointID string) error {
 	extension, err := store.GetExtension(ctx, extensionID)
@@ -1,9 +1,15 @@
 package gitea
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
 	"log"
-	"math/rand"
+	"math/big"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	"code.gitea.io/sdk/gitea"
 	"github.com/pkg/errors"
@@ -17,11 +23,14 @@ import (
 type Client interface {
 	GetOrg(orgname string) (*gitea.Organization, *gitea.Response, error)
 	CreateOrg(gitea.CreateOrgOption) (*gitea.Organization, *gitea.Response, error)
+	EditOrg(string, gitea.EditOrgOption) (*gitea.Response, error)
 	GetUserInfo(string) (*gitea.User, *gitea.Response, error)
 	AdminCreateUser(gitea.CreateUserOption) (*gitea.User, *gitea.Response, error)
 	AdminDeleteUser(string) (*gitea.Response, error)
 	ListOrgTeams(string, gitea.ListTeamsOptions) ([]*gitea.Team, *gitea.Response, error)
+	CreateTeam(string, gitea.CreateTeamOption) (*gitea.Team, *gitea.Response, error)
 	AddTeamMember(int64, string) (*gitea.Response, error)
+	RemoveTeamMember(int64, string) (*gitea.Response, error)
 	ListTeamMembers(int64, gitea.ListTeamMembersOptions) ([]*gitea.User, *gitea.Response, error)
 	GetRepo(string, string) (*gitea.Repository, *gitea.Response, error)
 	CreateOrgRepo(string, gitea.CreateRepoOption) (*gitea.Repository, *gitea.Response, error)
@@ -36,6 +45,11 @@ type Client interface {
 	DeleteRepo(string, string) (*gitea.Response, error)
 	DeleteOrg(string) (*gitea.Response, error)
 	DeleteOrgMembership(org, user string) (*gitea.Response, error)
+	ListRepoBranches(string, string, gitea.ListRepoBranchesOptions) ([]*gitea.Branch, *gitea.Response, error)
+	ListRepoTags(string, string, gitea.ListRepoTagsOptions) ([]*gitea.Tag, *gitea.Response, error)
+	ListRepoCommits(string, string, gitea.ListCommitOptions) ([]*gitea.Commit, *gitea.Response, error)
+	ListContents(string, string, string, string) ([]*gitea.ContentsResponse, *gitea.Response, error)
+	GetContents(string, string, string, string) (*gitea.ContentsResponse, *gitea.Response, error)
 }
 
 // AdminClient is the struct holding information about gitea client
@@ -43,6 +57,8 @@ type Client interface {
 type AdminClient struct {
 	giteaClient Client
 	url         string
+	username    string
+	password    string
 	logger      *log.Logger
 }
 
@@ -60,12 +76,26 @@ func (e giteaErr) Error() string {
 	return string(e)
 }
 
+// maxPageSize is the maximum number of items that gitea returns in a page
+const maxPageSize = 50
+
 var lettersForPassword = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
 var generatedPasswordLength = 16
 
-// For now, turn off the password generation
-// But we need to fine a way how user can alter this value (env variable, config file, client param...)
-var generateUserPassword = false
+// projectRoleTeams are the organization teams holding the project members with each role,
+// ordered by decreasing permissions. The Owners team is created by gitea together with the organization.
+var projectRoleTeams = []struct {
+	role       string
+	team       string
+	permission gitea.AccessMode
+}{
+	{domain.ProjectRoleOwner, "Owners", gitea.AccessModeOwner},
+	{domain.ProjectRoleDeveloper, "Developers", gitea.AccessModeWrite},
+	{domain.ProjectRoleViewer, "Viewers", gitea.AccessModeRead},
+}
+
+// teamUnits are the repository units that the members of the teams created by FuseML can access
+var teamUnits = []string{"repo.code", "repo.issues", "repo.pulls", "repo.releases", "repo.wiki"}
 
 // NewAdminClient creates a new gitea client and performs authentication
 // from the credentials provided as env variables
@@ -96,6 +126,8 @@ func NewAdminClient(logger *log.Logger) (*AdminClient, error) {
 	return &AdminClient{
 		giteaClient: client,
 		url:         url,
+		username:    username,
+		password:    password,
 		logger:      logger,
 	}, nil
 }
@@ -104,15 +136,21 @@ func generateUserName(org string) string {
 	return config.DefaultUserName(org)
 }
 
-func getUserPassword() string {
-	if !generateUserPassword {
-		return config.DefaultUserPassword
-	}
+func getUserPassword() (string, error) {
 	p := make([]rune, generatedPasswordLength)
 	for i := range p {
-		p[i] = lettersForPassword[rand.Intn(len(lettersForPassword))]
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(lettersForPassword))))
+		if err != nil {
+			return "", errors.Wrap(err, "Failed to generate password")
+		}
+		p[i] = lettersForPassword[n.Int64()]
 	}
-	return string(p)
+	return string(p), nil
+}
+
+// PushCredentials returns the credentials used by FuseML to push contents to the codeset repositories
+func (gac *AdminClient) PushCredentials() (string, string) {
+	return gac.username, gac.password
 }
 
 // GetGiteaURL returns the gitea url
@@ -153,6 +191,16 @@ func (gac *AdminClient) CreateProject(name, desc string, ignoreExisting bool) (*
 	}, nil
 }
 
+// UpdateProject changes the description of a Project
+func (gac *AdminClient) UpdateProject(org, desc string) error {
+	gac.logger.Printf("Updating project %s....", org)
+	_, err := gac.giteaClient.EditOrg(org, gitea.EditOrgOption{Description: desc})
+	if err != nil {
+		return errors.Wrap(err, "Failed to update project")
+	}
+	return nil
+}
+
 // CreateOrg creates an Org in gitea. Does not return an error if it already exists
 func (gac *AdminClient) createOrganizationIfNotPresent(org string) error {
 
@@ -160,50 +208,173 @@ func (gac *AdminClient) createOrganizationIfNotPresent(org string) error {
 	return err
 }
 
-// CreateUser creates user assigned to current project
-func (gac *AdminClient) CreateUser(org string) (*string, *string, error) {
-	username := generateUserName(org)
-	password := getUserPassword()
-	user, resp, err := gac.giteaClient.GetUserInfo(username)
+// createUser creates the git user of a project member, with a generated password, if it does not exist yet.
+// The password is only returned for new users.
+func (gac *AdminClient) createUser(user *domain.User) (*string, error) {
+	u, resp, err := gac.giteaClient.GetUserInfo(user.Name)
 	if resp == nil && err != nil {
-		return nil, nil, errors.Wrap(err, "Failed to make get user request")
+		return nil, errors.Wrap(err, "Failed to make get user request")
 	}
-	if user != nil && user.ID != 0 {
-		gac.logger.Println("User already exists")
-		return nil, nil, nil
+	if u != nil && u.ID != 0 {
+		gac.logger.Printf("User '%s' already exists", user.Name)
+		return nil, nil
 	}
 
-	gac.logger.Printf("Creating user '%s'", username)
+	password, err := getUserPassword()
+	if err != nil {
+		return nil, err
+	}
+	email := user.Email
+	if email == "" {
+		email = user.Name + config.DefaultUserEmailDomain
+	}
+	gac.logger.Printf("Creating user '%s'", user.Name)
 	_, _, err = gac.giteaClient.AdminCreateUser(gitea.CreateUserOption{
-		Username:           username,
-		Email:              config.DefaultUserEmail(org),
+		Username:           user.Name,
+		Email:              email,
 		Password:           password,
 		MustChangePassword: gitea.OptionalBool(false),
 		SendNotify:         false,
 	})
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "Failed to create user")
+		return nil, errors.Wrap(err, "Failed to create user")
 	}
+	return &password, nil
+}
 
+// getRoleTeams returns the organization teams holding the project members, indexed by role. If create is true,
+// the missing teams are created.
+func (gac *AdminClient) getRoleTeams(org string, create bool) (map[string]*gitea.Team, error) {
 	teams, _, err := gac.giteaClient.ListOrgTeams(org, gitea.ListTeamsOptions{})
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "Failed to list org teams")
+		return nil, errors.Wrap(err, "Failed to list org teams")
 	}
-	for _, team := range teams {
-		if team.Name == "Owners" {
-			_, err = gac.giteaClient.AddTeamMember(team.ID, username)
-			if err != nil {
-				return nil, nil, errors.Wrap(err, "Failed adding user to Owners")
+	roleTeams := make(map[string]*gitea.Team)
+	for _, rt := range projectRoleTeams {
+		for _, team := range teams {
+			if team.Name == rt.team {
+				roleTeams[rt.role] = team
+				break
 			}
-			break
 		}
+		if roleTeams[rt.role] != nil || !create || rt.permission == gitea.AccessModeOwner {
+			continue
+		}
+		gac.logger.Printf("Creating team '%s' for project %s", rt.team, org)
+		team, _, err := gac.giteaClient.CreateTeam(org, gitea.CreateTeamOption{
+			Name:                    rt.team,
+			Description:             fmt.Sprintf("FuseML project members with the %s role", rt.role),
+			Permission:              rt.permission,
+			IncludesAllRepositories: true,
+			Units:                   teamUnits,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to create the %s team", rt.team)
+		}
+		roleTeams[rt.role] = team
 	}
+	return roleTeams, nil
+}
 
+// getTeamMembers returns the names of the non-admin members of a team
+func (gac *AdminClient) getTeamMembers(team *gitea.Team) ([]*gitea.User, error) {
+	var members []*gitea.User
+	users, _, err := gac.giteaClient.ListTeamMembers(team.ID, gitea.ListTeamMembersOptions{})
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "Failed to create application")
+		return nil, errors.Wrapf(err, "Failed listing members of %s team", team.Name)
+	}
+	for _, u := range users {
+		if !u.IsAdmin {
+			members = append(members, u)
+		}
 	}
+	return members, nil
+}
 
-	return &username, &password, nil
+// AddProjectMember adds a user to a project, with the given role. The git user is created with a generated
+// password if it does not exist yet, in which case the password is returned. Adding a user that is already
+// a member of the project changes its role.
+func (gac *AdminClient) AddProjectMember(org string, user *domain.User) (*string, error) {
+	gac.logger.Printf("Adding user %s to project %s as %s....", user.Name, org, user.Role)
+	if _, err := gac.GetProject(org); err != nil {
+		return nil, err
+	}
+	teams, err := gac.getRoleTeams(org, true)
+	if err != nil {
+		return nil, err
+	}
+	team, ok := teams[user.Role]
+	if !ok {
+		return nil, errors.Errorf("No team found for the %s role", user.Role)
+	}
+	password, err := gac.createUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	added := false
+	for _, t := range teams {
+		members, err := gac.getTeamMembers(t)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			if m.UserName != user.Name {
+				continue
+			}
+			if t.ID == team.ID {
+				added = true
+			} else if _, err = gac.giteaClient.RemoveTeamMember(t.ID, user.Name); err != nil {
+				return nil, errors.Wrapf(err, "Failed removing user from %s team", t.Name)
+			}
+		}
+	}
+	if !added {
+		if _, err = gac.giteaClient.AddTeamMember(team.ID, user.Name); err != nil {
+			return nil, errors.Wrapf(err, "Failed adding user to %s team", team.Name)
+		}
+	}
+	return password, nil
+}
+
+// RemoveProjectMember removes a user from a project. The git user is deleted if it is not a member of any
+// other project.
+func (gac *AdminClient) RemoveProjectMember(org, name string) error {
+	gac.logger.Printf("Removing user %s from project %s....", name, org)
+	teams, err := gac.getRoleTeams(org, false)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, t := range teams {
+		members, err := gac.getTeamMembers(t)
+		if err != nil {
+			return err
+		}
+		for _, m := range members {
+			if m.UserName == name {
+				found = true
+				if _, err = gac.giteaClient.RemoveTeamMember(t.ID, name); err != nil {
+					return errors.Wrapf(err, "Failed removing user from %s team", t.Name)
+				}
+			}
+		}
+	}
+	if !found {
+		return domain.ErrProjectMemberNotFound
+	}
+
+	orgs, _, err := gac.giteaClient.ListUserOrgs(name, gitea.ListOrgsOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Failed to list orgs for user")
+	}
+	if len(orgs) == 0 {
+		gac.logger.Printf("Deleting user %s....", name)
+		if _, err := gac.giteaClient.AdminDeleteUser(name); err != nil {
+			return errors.Wrap(err, "Failed to delete user")
+		}
+	}
+	return nil
 }
 
 // CreateRepo creates a git repository with given name under given org
@@ -296,7 +467,20 @@ func (gac *AdminClient) DeleteRepoWebhook(org, name string, hookID *int64) error
 	return nil
 }
 
-// PrepareRepository prepares the org, repository, and creates a user
+// GetRepoWebhooks returns the webhooks of the given repository
+func (gac *AdminClient) GetRepoWebhooks(org, name string) ([]*domain.CodesetWebhook, error) {
+	hooks, _, err := gac.giteaClient.ListRepoHooks(org, name, gitea.ListHooksOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list webhooks")
+	}
+	result := make([]*domain.CodesetWebhook, 0, len(hooks))
+	for _, hook := range hooks {
+		result = append(result, &domain.CodesetWebhook{ID: hook.ID, URL: hook.Config["url"]})
+	}
+	return result, nil
+}
+
+// PrepareRepository prepares the org and repository, and creates a default project owner for new projects
 func (gac *AdminClient) PrepareRepository(code *domain.Codeset, listenerURL *string) (*string, *string, error) {
 
 	err := gac.createOrganizationIfNotPresent(code.Project)
@@ -304,9 +488,22 @@ func (gac *AdminClient) PrepareRepository(code *domain.Codeset, listenerURL *str
 		return nil, nil, errors.Wrap(err, "Create org failed")
 	}
 
-	user, pass, err := gac.CreateUser(code.Project)
+	// the first codeset of a project also creates a default project owner
+	var user, pass *string
+	members, err := gac.getProjectMembers(code.Project)
 	if err != nil {
-		return nil, nil, errors.Wrap(err, "Create FuseML user failed")
+		return nil, nil, err
+	}
+	if len(members) == 0 {
+		owner := &domain.User{
+			Name:  generateUserName(code.Project),
+			Email: config.DefaultUserEmail(code.Project),
+			Role:  domain.ProjectRoleOwner,
+		}
+		if pass, err = gac.AddProjectMember(code.Project, owner); err != nil {
+			return nil, nil, errors.Wrap(err, "Create FuseML user failed")
+		}
+		user = &owner.Name
 	}
 
 	err = gac.CreateRepo(code)
@@ -426,32 +623,175 @@ func (gac *AdminClient) DeleteRepository(org, name string) error {
 	return nil
 }
 
-// return all non-admin users that are Owners for given organization
-func (gac *AdminClient) getProjectOwners(name string) ([]*domain.User, error) {
+// AddRepositoryLabels adds labels to the topics of a repository
+func (gac *AdminClient) AddRepositoryLabels(org, name string, labels []string) error {
+	if err := gac.AddRepoTopics(org, name, labels); err != nil {
+		return errors.Wrap(err, "Failed to add repository topics")
+	}
+	return nil
+}
+
+// GetRepositoryRevisions retrieves the branches, tags and the most recent commits of a repository
+func (gac *AdminClient) GetRepositoryRevisions(org, name string, commits int) (*domain.CodesetRevisions, error) {
+	gac.logger.Printf("Listing revisions of repo %s for org '%s'...", name, org)
+	revisions := domain.CodesetRevisions{}
+
+	for page := 1; ; page++ {
+		branches, _, err := gac.giteaClient.ListRepoBranches(org, name, gitea.ListRepoBranchesOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: maxPageSize}})
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to list repository branches")
+		}
+		for _, b := range branches {
+			ref := domain.CodesetRef{Name: b.Name}
+			if b.Commit != nil {
+				ref.Commit = b.Commit.ID
+			}
+			revisions.Branches = append(revisions.Branches, &ref)
+		}
+		if len(branches) < maxPageSize {
+			break
+		}
+	}
+
+	for page := 1; ; page++ {
+		tags, _, err := gac.giteaClient.ListRepoTags(org, name, gitea.ListRepoTagsOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: maxPageSize}})
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to list repository tags")
+		}
+		for _, t := range tags {
+			ref := domain.CodesetRef{Name: t.Name}
+			if t.Commit != nil {
+				ref.Commit = t.Commit.SHA
+			}
+			revisions.Tags = append(revisions.Tags, &ref)
+		}
+		if len(tags) < maxPageSize {
+			break
+		}
+	}
 
+	if commits <= 0 {
+		return &revisions, nil
+	}
+	if commits > maxPageSize {
+		commits = maxPageSize
+	}
+	repoCommits, _, err := gac.giteaClient.ListRepoCommits(org, name, gitea.ListCommitOptions{
+		ListOptions: gitea.ListOptions{Page: 1, PageSize: commits}})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list repository commits")
+	}
+	for _, c := range repoCommits {
+		revisions.Commits = append(revisions.Commits, toCodesetCommit(c))
+	}
+	return &revisions, nil
+}
+
+// GetRepositoryFiles lists the entries of a repository directory at the given revision (the default branch
+// if ref is empty). If path points to a file, only the file entry is returned
+func (gac *AdminClient) GetRepositoryFiles(org, name, ref, path string) ([]*domain.CodesetFile, error) {
+	gac.logger.Printf("Listing files of repo %s for org '%s'...", name, org)
+	path = strings.Trim(path, "/")
+	contents, resp, err := gac.giteaClient.ListContents(org, name, ref, path)
+	if err != nil && resp != nil && resp.StatusCode == http.StatusOK {
+		// gitea returns a single entry instead of a list when the path points to a file
+		var file *gitea.ContentsResponse
+		file, resp, err = gac.giteaClient.GetContents(org, name, ref, path)
+		contents = []*gitea.ContentsResponse{file}
+	}
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrCodesetFileNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list repository files")
+	}
+
+	files := make([]*domain.CodesetFile, 0, len(contents))
+	for _, c := range contents {
+		file := domain.CodesetFile{Path: c.Path, Type: c.Type}
+		if c.Type == domain.CodesetFileTypeFile {
+			file.Size = c.Size
+		}
+		files = append(files, &file)
+	}
+	return files, nil
+}
+
+// GetRepositoryFileContents returns the contents of a repository file at the given revision (the default
+// branch if ref is empty)
+func (gac *AdminClient) GetRepositoryFileContents(org, name, ref, path string) ([]byte, error) {
+	gac.logger.Printf("Reading file %s of repo %s for org '%s'...", path, name, org)
+	file, resp, err := gac.giteaClient.GetContents(org, name, ref, strings.Trim(path, "/"))
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrCodesetFileNotFound
+	}
+	if err != nil && resp != nil && resp.StatusCode == http.StatusOK {
+		// gitea returns a list of entries when the path points to a directory
+		return nil, domain.ErrCodesetNotAFile
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get repository file")
+	}
+	if file.Type != domain.CodesetFileTypeFile || file.Content == nil {
+		return nil, domain.ErrCodesetNotAFile
+	}
+	contents, err := base64.StdEncoding.DecodeString(*file.Content)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode repository file")
+	}
+	return contents, nil
+}
+
+// toCodesetCommit converts a gitea commit into a codeset commit
+func toCodesetCommit(c *gitea.Commit) *domain.CodesetCommit {
+	commit := domain.CodesetCommit{}
+	if c.CommitMeta != nil {
+		commit.ID = c.SHA
+		commit.Time = c.Created
+	}
+	if c.RepoCommit != nil {
+		commit.Message = c.RepoCommit.Message
+		if c.RepoCommit.Author != nil {
+			commit.Author = c.RepoCommit.Author.Name
+			if commit.Time.IsZero() {
+				commit.Time, _ = time.Parse(time.RFC3339, c.RepoCommit.Author.Date)
+			}
+		}
+	}
+	return &commit
+}
+
+// getProjectMembers returns all non-admin users that are members of the project teams, together with their roles.
+// Users that are members of several teams get the role with the most permissions.
+func (gac *AdminClient) getProjectMembers(name string) ([]*domain.User, error) {
 	var ret []*domain.User
-	teams, _, err := gac.giteaClient.ListOrgTeams(name, gitea.ListTeamsOptions{})
+	seen := make(map[string]bool)
+	teams, err := gac.getRoleTeams(name, false)
 	if err != nil {
-		return nil, errors.Wrap(err, "Failed to list org teams")
+		return nil, err
 	}
-	for _, team := range teams {
-		if team.Name != "Owners" {
+	for _, rt := range projectRoleTeams {
+		team, ok := teams[rt.role]
+		if !ok {
 			continue
 		}
-		users, _, err := gac.giteaClient.ListTeamMembers(team.ID, gitea.ListTeamMembersOptions{})
+		users, err := gac.getTeamMembers(team)
 		if err != nil {
-			return nil, errors.Wrap(err, "Failed listing members of Owners team")
+			return nil, err
 		}
 		for _, u := range users {
-			if u.IsAdmin {
+			if seen[u.UserName] {
 				continue
 			}
+			seen[u.UserName] = true
 			ret = append(ret, &domain.User{
 				Name:  u.UserName,
 				Email: u.Email,
+				Role:  rt.role,
 			})
 		}
-		break // no need to continue after checking Owners
 	}
 	return ret, nil
 }
@@ -467,7 +807,7 @@ func (gac *AdminClient) GetProjects() ([]*domain.Project, error) {
 
 	var ret []*domain.Project
 	for _, o := range orgs {
-		users, err := gac.getProjectOwners(o.UserName)
+		users, err := gac.getProjectMembers(o.UserName)
 		if err != nil {
 			return nil, err
 		}
@@ -488,7 +828,7 @@ func (gac *AdminClient) GetProject(name string) (*domain.Project, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to make get org request")
 	}
-	users, err := gac.getProjectOwners(name)
+	users, err := gac.getProjectMembers(name)
 	if err != nil {
 		return nil, err
 	}
@@ -513,19 +853,19 @@ func (gac *AdminClient) DeleteProject(org string) error {
 		return errProjectNotEmpty
 	}
 
-	// 2. delete all members of the project, if they are not owning any other project
-	owners, err := gac.getProjectOwners(org)
+	// 2. delete all members of the project, if they are not members of any other project
+	members, err := gac.getProjectMembers(org)
 	if err != nil {
-		return errors.Wrap(err, "Failed to list project owners")
+		return errors.Wrap(err, "Failed to list project members")
 	}
 	usersOrgs := make(map[string]int)
-	for _, owner := range owners {
-		orgsForUser, _, err := gac.giteaClient.ListUserOrgs(owner.Name, gitea.ListOrgsOptions{})
+	for _, member := range members {
+		orgsForUser, _, err := gac.giteaClient.ListUserOrgs(member.Name, gitea.ListOrgsOptions{})
 		if err != nil {
 			return errors.Wrap(err, "Failed to list orgs for user")
 		}
 		for range orgsForUser {
-			usersOrgs[owner.Name]++
+			usersOrgs[member.Name]++
 		}
 	}
 	for userName, orgNumber := range usersOrgs {
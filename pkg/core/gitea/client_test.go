@@ -1,13 +1,20 @@
 package gitea
 
 import (
+	"encoding/base64"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"code.gitea.io/sdk/gitea"
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/fuseml/fuseml-core/pkg/domain"
 	"github.com/fuseml/fuseml-core/pkg/util"
 )
@@ -16,6 +23,11 @@ type TestStore struct {
 	projects       map[string]gitea.Organization
 	projects2repos map[string]map[string]gitea.Repository
 	teams          map[int64][]string
+	orgTeams       map[string][]*gitea.Team
+	users          map[string]*gitea.User
+	repos2commits  map[string][]*gitea.Commit
+	repos2tags     map[string][]*gitea.Tag
+	repos2files    map[string]map[string]string
 }
 
 // Replace all methods that are caled from actual gitea client with the ones operating
@@ -30,6 +42,11 @@ func NewTestStore() *TestStore {
 		projects:       make(map[string]gitea.Organization),
 		projects2repos: make(map[string]map[string]gitea.Repository),
 		teams:          make(map[int64][]string),
+		orgTeams:       make(map[string][]*gitea.Team),
+		users:          make(map[string]*gitea.User),
+		repos2commits:  make(map[string][]*gitea.Commit),
+		repos2tags:     make(map[string][]*gitea.Tag),
+		repos2files:    make(map[string]map[string]string),
 	}
 }
 
@@ -65,30 +82,64 @@ func (tc *testGiteaClient) CreateOrg(opt gitea.CreateOrgOption) (*gitea.Organiza
 	tc.testStore.projects2repos[opt.Name] = make(map[string]gitea.Repository)
 	return &org, nil, nil
 }
-func (tc *testGiteaClient) GetUserInfo(string) (*gitea.User, *gitea.Response, error) {
+func (tc *testGiteaClient) EditOrg(orgname string, opt gitea.EditOrgOption) (*gitea.Response, error) {
+	org := tc.testStore.projects[orgname]
+	org.Description = opt.Description
+	tc.testStore.projects[orgname] = org
+	return nil, nil
+}
+func (tc *testGiteaClient) GetUserInfo(user string) (*gitea.User, *gitea.Response, error) {
+	if u, ok := tc.testStore.users[user]; ok {
+		return u, &gitea.Response{Response: &httpResp200}, nil
+	}
 	return &gitea.User{ID: 0}, nil, nil
 }
-func (tc *testGiteaClient) AdminCreateUser(gitea.CreateUserOption) (*gitea.User, *gitea.Response, error) {
-	return nil, nil, nil
+func (tc *testGiteaClient) AdminCreateUser(opt gitea.CreateUserOption) (*gitea.User, *gitea.Response, error) {
+	u := &gitea.User{ID: int64(len(tc.testStore.users) + 1), UserName: opt.Username, Email: opt.Email}
+	tc.testStore.users[opt.Username] = u
+	return u, nil, nil
 }
 func (tc *testGiteaClient) AdminDeleteUser(user string) (*gitea.Response, error) {
+	delete(tc.testStore.users, user)
 	return nil, nil
 }
-func (tc *testGiteaClient) ListOrgTeams(string, gitea.ListTeamsOptions) ([]*gitea.Team, *gitea.Response, error) {
-	// return default team for any org
+func (tc *testGiteaClient) ListOrgTeams(org string, opt gitea.ListTeamsOptions) ([]*gitea.Team, *gitea.Response, error) {
+	// return default team for any org, followed by the teams created for the org
 	teams := []*gitea.Team{{Name: "Owners", ID: 42}}
-	return teams, nil, nil
+	return append(teams, tc.testStore.orgTeams[org]...), nil, nil
+}
+func (tc *testGiteaClient) CreateTeam(org string, opt gitea.CreateTeamOption) (*gitea.Team, *gitea.Response, error) {
+	team := &gitea.Team{Name: opt.Name, ID: int64(100 + len(tc.testStore.orgTeams[org])), Permission: opt.Permission}
+	tc.testStore.orgTeams[org] = append(tc.testStore.orgTeams[org], team)
+	return team, nil, nil
 }
 func (tc *testGiteaClient) AddTeamMember(id int64, username string) (*gitea.Response, error) {
 	tc.testStore.teams[id] = append(tc.testStore.teams[id], username)
 	return nil, nil
 }
+func (tc *testGiteaClient) RemoveTeamMember(id int64, username string) (*gitea.Response, error) {
+	members := []string{}
+	for _, m := range tc.testStore.teams[id] {
+		if m != username {
+			members = append(members, m)
+		}
+	}
+	tc.testStore.teams[id] = members
+	return nil, nil
+}
 func (tc *testGiteaClient) DeleteOrgMembership(org, user string) (*gitea.Response, error) {
 	return &gitea.Response{Response: &httpResp200}, nil
 }
 
 func (tc *testGiteaClient) ListTeamMembers(id int64, opts gitea.ListTeamMembersOptions) ([]*gitea.User, *gitea.Response, error) {
 	users := make([]*gitea.User, 0)
+	for _, m := range tc.testStore.teams[id] {
+		user := &gitea.User{UserName: m}
+		if u, ok := tc.testStore.users[m]; ok {
+			user = u
+		}
+		users = append(users, user)
+	}
 	return users, &gitea.Response{Response: &httpResp200}, nil
 }
 
@@ -138,6 +189,80 @@ func (tc *testGiteaClient) ListMyOrgs(gitea.ListOrgsOptions) ([]*gitea.Organizat
 	return allOrgs, nil, nil
 }
 
+func (tc *testGiteaClient) ListRepoBranches(owner, repo string, opt gitea.ListRepoBranchesOptions) ([]*gitea.Branch, *gitea.Response, error) {
+	commits := tc.testStore.repos2commits[owner+"/"+repo]
+	if opt.Page > 1 || len(commits) == 0 {
+		return []*gitea.Branch{}, nil, nil
+	}
+	return []*gitea.Branch{{Name: "main", Commit: &gitea.PayloadCommit{ID: commits[0].SHA}}}, nil, nil
+}
+
+func (tc *testGiteaClient) ListRepoTags(owner, repo string, opt gitea.ListRepoTagsOptions) ([]*gitea.Tag, *gitea.Response, error) {
+	tags := tc.testStore.repos2tags[owner+"/"+repo]
+	start := (opt.Page - 1) * opt.PageSize
+	if start >= len(tags) {
+		return []*gitea.Tag{}, nil, nil
+	}
+	end := start + opt.PageSize
+	if end > len(tags) {
+		end = len(tags)
+	}
+	return tags[start:end], nil, nil
+}
+
+func (tc *testGiteaClient) ListRepoCommits(owner, repo string, opt gitea.ListCommitOptions) ([]*gitea.Commit, *gitea.Response, error) {
+	commits := tc.testStore.repos2commits[owner+"/"+repo]
+	if len(commits) > opt.PageSize {
+		commits = commits[:opt.PageSize]
+	}
+	return commits, nil, nil
+}
+
+// ListContents lists the files of a repository, keeping only the first path element below the directory
+func (tc *testGiteaClient) ListContents(owner, repo, ref, dir string) ([]*gitea.ContentsResponse, *gitea.Response, error) {
+	files := tc.testStore.repos2files[owner+"/"+repo]
+	if _, ok := files[dir]; ok {
+		return nil, &gitea.Response{Response: &httpResp200}, fmt.Errorf("expect directory, got file")
+	}
+	prefix := dir + "/"
+	if dir == "" {
+		prefix = ""
+	}
+	entries := map[string]*gitea.ContentsResponse{}
+	for path, content := range files {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		entry := &gitea.ContentsResponse{Path: path, Type: "file", Size: int64(len(content))}
+		if i := strings.Index(strings.TrimPrefix(path, prefix), "/"); i >= 0 {
+			entry = &gitea.ContentsResponse{Path: prefix + path[len(prefix):len(prefix)+i], Type: "dir"}
+		}
+		entries[entry.Path] = entry
+	}
+	if len(entries) == 0 {
+		return nil, &gitea.Response{Response: &httpResp404}, fmt.Errorf("not found")
+	}
+	res := []*gitea.ContentsResponse{}
+	for _, entry := range entries {
+		res = append(res, entry)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Path < res[j].Path })
+	return res, &gitea.Response{Response: &httpResp200}, nil
+}
+
+func (tc *testGiteaClient) GetContents(owner, repo, ref, path string) (*gitea.ContentsResponse, *gitea.Response, error) {
+	content, ok := tc.testStore.repos2files[owner+"/"+repo][path]
+	if !ok {
+		if _, resp, err := tc.ListContents(owner, repo, ref, path); err != nil {
+			return nil, resp, err
+		}
+		return nil, &gitea.Response{Response: &httpResp200}, fmt.Errorf("expect file, got directory")
+	}
+	encoded := base64.StdEncoding.EncodeToString([]byte(content))
+	return &gitea.ContentsResponse{Path: path, Type: "file", Size: int64(len(content)), Content: &encoded},
+		&gitea.Response{Response: &httpResp200}, nil
+}
+
 func (tc *testGiteaClient) DeleteRepo(owner, repo string) (*gitea.Response, error) {
 	delete(tc.testStore.projects2repos[owner], repo)
 	return nil, nil
@@ -297,6 +422,105 @@ func TestGetRepositories(t *testing.T) {
 	}
 }
 
+func TestGetRepositoryRevisions(t *testing.T) {
+
+	testStore := NewTestStore()
+	testGiteaAdminClient := newTestGiteaAdminClient(testStore)
+	testGiteaAdminClient.PrepareRepository(getTestCodeset(), testListenerURL)
+
+	// newest commits first, as returned by gitea
+	created := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	repoKey := project1 + "/" + name
+	for i := 60; i > 0; i-- {
+		testStore.repos2commits[repoKey] = append(testStore.repos2commits[repoKey], &gitea.Commit{
+			CommitMeta: &gitea.CommitMeta{SHA: fmt.Sprintf("%040d", i), Created: created.Add(time.Duration(i) * time.Hour)},
+			RepoCommit: &gitea.RepoCommit{Message: fmt.Sprintf("commit %d", i), Author: &gitea.CommitUser{
+				Identity: gitea.Identity{Name: "John Doe"}}},
+		})
+	}
+	for i := 1; i <= 55; i++ {
+		testStore.repos2tags[repoKey] = append(testStore.repos2tags[repoKey], &gitea.Tag{
+			Name: fmt.Sprintf("v%d", i), Commit: &gitea.CommitMeta{SHA: fmt.Sprintf("%040d", i)}})
+	}
+
+	revisions, err := testGiteaAdminClient.GetRepositoryRevisions(project1, name, 5)
+	assertError(t, err, nil)
+
+	if len(revisions.Branches) != 1 || revisions.Branches[0].Name != "main" || revisions.Branches[0].Commit != fmt.Sprintf("%040d", 60) {
+		t.Errorf("Unexpected branches: %v", revisions.Branches)
+	}
+	if len(revisions.Tags) != 55 || revisions.Tags[54].Name != "v55" {
+		t.Errorf("Expected all the 55 tags to be listed, got %d", len(revisions.Tags))
+	}
+	if len(revisions.Commits) != 5 {
+		t.Fatalf("Expected 5 commits, got %d", len(revisions.Commits))
+	}
+	want := domain.CodesetCommit{ID: fmt.Sprintf("%040d", 60), Author: "John Doe", Message: "commit 60",
+		Time: created.Add(60 * time.Hour)}
+	if *revisions.Commits[0] != want {
+		t.Errorf("got commit %v, want %v", *revisions.Commits[0], want)
+	}
+
+	// the number of commits is capped to the maximum gitea page size
+	revisions, err = testGiteaAdminClient.GetRepositoryRevisions(project1, name, 100)
+	assertError(t, err, nil)
+	if len(revisions.Commits) != maxPageSize {
+		t.Errorf("Expected %d commits, got %d", maxPageSize, len(revisions.Commits))
+	}
+
+	revisions, err = testGiteaAdminClient.GetRepositoryRevisions(project1, name, 0)
+	assertError(t, err, nil)
+	if len(revisions.Commits) != 0 {
+		t.Errorf("Expected no commits, got %d", len(revisions.Commits))
+	}
+}
+
+func TestGetRepositoryFiles(t *testing.T) {
+
+	testStore := NewTestStore()
+	testGiteaAdminClient := newTestGiteaAdminClient(testStore)
+	testStore.repos2files[project1+"/"+name] = map[string]string{
+		"MLproject":         "name: mlflow-app\n",
+		"conda.yaml":        "name: env\n",
+		"model/train.py":    "print('training')\n",
+		"model/data/x.csv":  "1,2\n",
+		"model/predict.py":  "print('predicting')\n",
+		"docs/README.md":    "# README\n",
+		"docs/img/logo.png": "",
+	}
+
+	files, err := testGiteaAdminClient.GetRepositoryFiles(project1, name, "", "/")
+	assertError(t, err, nil)
+	want := []*domain.CodesetFile{
+		{Path: "MLproject", Type: "file", Size: 17},
+		{Path: "conda.yaml", Type: "file", Size: 10},
+		{Path: "docs", Type: "dir"},
+		{Path: "model", Type: "dir"},
+	}
+	if d := cmp.Diff(want, files); d != "" {
+		t.Errorf("Unexpected files (-want +got): %s", d)
+	}
+
+	files, err = testGiteaAdminClient.GetRepositoryFiles(project1, name, "main", "model/train.py")
+	assertError(t, err, nil)
+	if d := cmp.Diff([]*domain.CodesetFile{{Path: "model/train.py", Type: "file", Size: 18}}, files); d != "" {
+		t.Errorf("Unexpected files (-want +got): %s", d)
+	}
+
+	_, err = testGiteaAdminClient.GetRepositoryFiles(project1, name, "", "unknown")
+	assertError(t, err, domain.ErrCodesetFileNotFound)
+
+	contents, err := testGiteaAdminClient.GetRepositoryFileContents(project1, name, "", "MLproject")
+	assertError(t, err, nil)
+	if string(contents) != "name: mlflow-app\n" {
+		t.Errorf("Unexpected file contents: %q", contents)
+	}
+	_, err = testGiteaAdminClient.GetRepositoryFileContents(project1, name, "", "model")
+	assertError(t, err, domain.ErrCodesetNotAFile)
+	_, err = testGiteaAdminClient.GetRepositoryFileContents(project1, name, "", "model/unknown.py")
+	assertError(t, err, domain.ErrCodesetFileNotFound)
+}
+
 func TestAddDeleteOrgs(t *testing.T) {
 
 	testGiteaAdminClient := newTestGiteaAdminClient(NewTestStore())
@@ -350,6 +574,58 @@ func TestAddDeleteOrgs(t *testing.T) {
 	}
 }
 
+func TestProjectMembers(t *testing.T) {
+
+	testStore := NewTestStore()
+	testGiteaAdminClient := newTestGiteaAdminClient(testStore)
+
+	user, pass, err := testGiteaAdminClient.PrepareRepository(getTestCodeset(), testListenerURL)
+	assertError(t, err, nil)
+	if user == nil || *user != generateUserName(project1) || pass == nil || len(*pass) != generatedPasswordLength {
+		t.Fatalf("Expected credentials for the default project owner, got %v/%v", user, pass)
+	}
+
+	// the default owner is only created for the first codeset
+	code := getTestCodeset()
+	code.Name = "other"
+	if user, pass, err = testGiteaAdminClient.PrepareRepository(code, testListenerURL); user != nil || pass != nil {
+		t.Errorf("Expected no credentials for the second codeset, got %v/%v (error: %v)", user, pass, err)
+	}
+
+	alice := &domain.User{Name: "alice", Email: "alice@example.com", Role: domain.ProjectRoleDeveloper}
+	pass, err = testGiteaAdminClient.AddProjectMember(project1, alice)
+	assertError(t, err, nil)
+	if pass == nil || len(*pass) != generatedPasswordLength {
+		t.Errorf("Expected a generated password for a new user, got %v", pass)
+	}
+
+	// changing the role does not generate new credentials
+	alice.Role = domain.ProjectRoleViewer
+	pass, err = testGiteaAdminClient.AddProjectMember(project1, alice)
+	assertError(t, err, nil)
+	if pass != nil {
+		t.Errorf("Expected no password for an existing user, got %q", *pass)
+	}
+
+	p, err := testGiteaAdminClient.GetProject(project1)
+	assertError(t, err, nil)
+	want := []*domain.User{
+		{Name: generateUserName(project1), Email: generateUserName(project1) + "@fuseml.org", Role: domain.ProjectRoleOwner},
+		{Name: "alice", Email: "alice@example.com", Role: domain.ProjectRoleViewer},
+	}
+	if d := cmp.Diff(want, p.Users); d != "" {
+		t.Errorf("Unexpected project members (-want +got): %s", d)
+	}
+
+	err = testGiteaAdminClient.RemoveProjectMember(project1, "alice")
+	assertError(t, err, nil)
+	if _, ok := testStore.users["alice"]; ok {
+		t.Errorf("Expected the user that is not a member of any project to be deleted")
+	}
+	err = testGiteaAdminClient.RemoveProjectMember(project1, "alice")
+	assertError(t, err, domain.ErrProjectMemberNotFound)
+}
+
 func TestNewGiteaAdminClient(t *testing.T) {
 
 	os.Unsetenv("GITEA_URL")
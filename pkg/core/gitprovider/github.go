@@ -0,0 +1,468 @@
+package gitprovider
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	config "github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+const (
+	// defaultGitHubAPIURL is the URL of the GitHub REST API, used when GITHUB_API_URL is not set
+	defaultGitHubAPIURL = "https://api.github.com"
+
+	errGITHUBTOKENMissing = gitProviderErr("Value for GitHub access token (GITHUB_TOKEN) was not provided.")
+)
+
+type githubOrg struct {
+	Login       string `json:"login"`
+	Description string `json:"description"`
+}
+
+type githubRepo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	CloneURL    string   `json:"clone_url"`
+	Topics      []string `json:"topics"`
+}
+
+type githubHook struct {
+	ID     int64             `json:"id"`
+	Name   string            `json:"name,omitempty"`
+	Active bool              `json:"active"`
+	Events []string          `json:"events,omitempty"`
+	Config map[string]string `json:"config"`
+}
+
+type githubRef struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+}
+
+type githubCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name string    `json:"name"`
+			Date time.Time `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+type githubContent struct {
+	Path    string `json:"path"`
+	Type    string `json:"type"`
+	Size    int64  `json:"size"`
+	Content string `json:"content"`
+}
+
+// GitHubAdminClient implements the GitAdminClient interface for GitHub. Projects are GitHub organizations and
+// codesets are references to existing repositories: registering a codeset adds the codeset topic and the codeset
+// labels to the repository topics, and deleting it only removes the codeset topic.
+type GitHubAdminClient struct {
+	api    *restClient
+	logger *log.Logger
+}
+
+// NewGitHubAdminClient creates a new GitHub client, authenticating with the access token provided as the
+// GITHUB_TOKEN env variable. The GitHub API URL can be changed, e.g. for GitHub Enterprise, with the GITHUB_API_URL
+// env variable.
+func NewGitHubAdminClient(logger *log.Logger) (*GitHubAdminClient, error) {
+	token, exists := os.LookupEnv("GITHUB_TOKEN")
+	if !exists {
+		return nil, errGITHUBTOKENMissing
+	}
+	apiURL, exists := os.LookupEnv("GITHUB_API_URL")
+	if !exists {
+		apiURL = defaultGitHubAPIURL
+	}
+
+	logger.Printf("Using GitHub from: %s", apiURL)
+	return newGitHubAdminClient(logger, apiURL, token), nil
+}
+
+func newGitHubAdminClient(logger *log.Logger, apiURL, token string) *GitHubAdminClient {
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github.v3+json")
+	header.Set("Authorization", "token "+token)
+	return &GitHubAdminClient{api: newRestClient(apiURL, header), logger: logger}
+}
+
+func githubRepoPath(org, name string) string {
+	return fmt.Sprintf("/repos/%s/%s", url.PathEscape(org), url.PathEscape(name))
+}
+
+func (c *GitHubAdminClient) getRepo(org, name string) (*githubRepo, error) {
+	repo := githubRepo{}
+	if err := c.api.do(http.MethodGet, githubRepoPath(org, name), nil, &repo); err != nil {
+		if isNotFound(err) {
+			return nil, errRepoNotFound
+		}
+		return nil, errors.Wrap(err, "Failed to read repository")
+	}
+	return &repo, nil
+}
+
+func (c *GitHubAdminClient) setRepoTopics(org, name string, topics []string) error {
+	body := map[string][]string{"names": topics}
+	if err := c.api.do(http.MethodPut, githubRepoPath(org, name)+"/topics", body, nil); err != nil {
+		return errors.Wrap(err, "Failed to set repository topics")
+	}
+	return nil
+}
+
+func githubRepoToCodeset(org string, repo *githubRepo) *domain.Codeset {
+	return &domain.Codeset{
+		Name:        repo.Name,
+		Project:     org,
+		Description: repo.Description,
+		Labels:      codesetLabels(repo.Topics),
+		URL:         repo.CloneURL,
+	}
+}
+
+// PrepareRepository registers an existing GitHub repository as a codeset. GitHub users are not managed by FuseML,
+// so no credentials are returned.
+func (c *GitHubAdminClient) PrepareRepository(code *domain.Codeset, listenerURL *string) (*string, *string, error) {
+	repo, err := c.getRepo(code.Project, code.Name)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Registering GitHub repository failed")
+	}
+
+	c.logger.Printf("Registering GitHub repository '%s' under '%s'...", code.Name, code.Project)
+	if err = c.setRepoTopics(code.Project, code.Name, registeredTopics(repo.Topics, code.Labels)); err != nil {
+		return nil, nil, err
+	}
+	code.URL = repo.CloneURL
+	if code.Description == "" {
+		code.Description = repo.Description
+	}
+
+	_, err = c.CreateRepoWebhook(code.Project, code.Name, listenerURL)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Creating webhook failed")
+	}
+	return nil, nil, nil
+}
+
+// CreateRepoWebhook creates a webhook for the push events of the repository, sent to the listenerURL
+func (c *GitHubAdminClient) CreateRepoWebhook(org, name string, listenerURL *string) (*int64, error) {
+	if listenerURL == nil {
+		c.logger.Printf("Webhook listener URL not provided, skipping creation")
+		return nil, nil
+	}
+
+	hooks := []githubHook{}
+	if err := c.api.do(http.MethodGet, githubRepoPath(org, name)+"/hooks", nil, &hooks); err != nil {
+		return nil, errors.Wrap(err, "Failed to list webhooks")
+	}
+	for _, hook := range hooks {
+		if hook.Config["url"] == *listenerURL {
+			c.logger.Printf("Webhook for '%s' already exists", name)
+			return &hook.ID, nil
+		}
+	}
+
+	c.logger.Printf("Creating Webhook for '%s' under '%s'...", name, org)
+	hook := githubHook{}
+	err := c.api.do(http.MethodPost, githubRepoPath(org, name)+"/hooks", githubHook{
+		Name:   "web",
+		Active: true,
+		Events: []string{"push"},
+		Config: map[string]string{
+			"url":          *listenerURL,
+			"content_type": "json",
+			"secret":       config.HookSecret,
+		},
+	}, &hook)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create webhook")
+	}
+	return &hook.ID, nil
+}
+
+// DeleteRepoWebhook deletes a webhook of the repository
+func (c *GitHubAdminClient) DeleteRepoWebhook(org, name string, hookID *int64) error {
+	c.logger.Printf("Deleting Webhook for %q under %q...", name, org)
+	err := c.api.do(http.MethodDelete, fmt.Sprintf("%s/hooks/%d", githubRepoPath(org, name), *hookID), nil, nil)
+	if err != nil {
+		if isNotFound(err) {
+			c.logger.Printf("Webhook not found, skipping deletion")
+			return nil
+		}
+		return errors.Wrap(err, "Failed to delete webhook")
+	}
+	return nil
+}
+
+// GetRepoWebhooks returns the webhooks of the repository
+func (c *GitHubAdminClient) GetRepoWebhooks(org, name string) ([]*domain.CodesetWebhook, error) {
+	hooks := []githubHook{}
+	if err := c.api.do(http.MethodGet, githubRepoPath(org, name)+"/hooks", nil, &hooks); err != nil {
+		return nil, errors.Wrap(err, "Failed to list webhooks")
+	}
+	result := make([]*domain.CodesetWebhook, 0, len(hooks))
+	for _, hook := range hooks {
+		result = append(result, &domain.CodesetWebhook{ID: hook.ID, URL: hook.Config["url"]})
+	}
+	return result, nil
+}
+
+// GetRepositories retrieves the repositories registered as codesets, can be filtered by project (organization)
+// and label
+func (c *GitHubAdminClient) GetRepositories(org, label *string) ([]*domain.Codeset, error) {
+	var orgs []string
+	if org == nil {
+		projects, err := c.GetProjects()
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			orgs = append(orgs, p.Name)
+		}
+	} else {
+		orgs = append(orgs, *org)
+	}
+
+	codesets := []*domain.Codeset{}
+	for _, o := range orgs {
+		c.logger.Printf("Listing repos for org '%s'...", o)
+		for page := 1; ; page++ {
+			repos := []*githubRepo{}
+			err := c.api.do(http.MethodGet, pagePath(fmt.Sprintf("/orgs/%s/repos", url.PathEscape(o)), page, maxPageSize), nil, &repos)
+			if err != nil {
+				return nil, errors.Wrap(err, "Failed to list project repos")
+			}
+			for _, repo := range repos {
+				if !util.StringInSlice(codesetTopic, repo.Topics) || (label != nil && !util.StringInSlice(*label, repo.Topics)) {
+					continue
+				}
+				codesets = append(codesets, githubRepoToCodeset(o, repo))
+			}
+			if len(repos) < maxPageSize {
+				break
+			}
+		}
+	}
+	return codesets, nil
+}
+
+// GetRepository retrieves a repository registered as a codeset
+func (c *GitHubAdminClient) GetRepository(org, name string) (*domain.Codeset, error) {
+	c.logger.Printf("Get repo %s for org '%s'...", name, org)
+	repo, err := c.getRepo(org, name)
+	if err != nil {
+		return nil, err
+	}
+	if !util.StringInSlice(codesetTopic, repo.Topics) {
+		return nil, errRepoNotRegistered
+	}
+	return githubRepoToCodeset(org, repo), nil
+}
+
+// DeleteRepository unregisters a repository as a codeset, the repository itself is not deleted
+func (c *GitHubAdminClient) DeleteRepository(org, name string) error {
+	c.logger.Printf("Going to unregister repo %s for org '%s'...", name, org)
+	repo, err := c.getRepo(org, name)
+	if err != nil {
+		if err == errRepoNotFound {
+			c.logger.Printf("Repo does not exist, no need to unregister")
+			return nil
+		}
+		return err
+	}
+	return c.setRepoTopics(org, name, codesetLabels(repo.Topics))
+}
+
+// AddRepositoryLabels adds labels to the topics of a repository registered as a codeset
+func (c *GitHubAdminClient) AddRepositoryLabels(org, name string, labels []string) error {
+	repo, err := c.getRepo(org, name)
+	if err != nil {
+		return err
+	}
+	return c.setRepoTopics(org, name, registeredTopics(repo.Topics, labels))
+}
+
+// GetRepositoryRevisions retrieves the branches, tags and the most recent commits of a repository
+func (c *GitHubAdminClient) GetRepositoryRevisions(org, name string, commits int) (*domain.CodesetRevisions, error) {
+	c.logger.Printf("Listing revisions of repo %s for org '%s'...", name, org)
+	revisions := domain.CodesetRevisions{}
+
+	for _, kind := range []string{"branches", "tags"} {
+		for page := 1; ; page++ {
+			refs := []*githubRef{}
+			err := c.api.do(http.MethodGet, pagePath(githubRepoPath(org, name)+"/"+kind, page, maxPageSize), nil, &refs)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to list repository %s", kind)
+			}
+			for _, r := range refs {
+				ref := &domain.CodesetRef{Name: r.Name, Commit: r.Commit.SHA}
+				if kind == "branches" {
+					revisions.Branches = append(revisions.Branches, ref)
+				} else {
+					revisions.Tags = append(revisions.Tags, ref)
+				}
+			}
+			if len(refs) < maxPageSize {
+				break
+			}
+		}
+	}
+
+	if commits <= 0 {
+		return &revisions, nil
+	}
+	if commits > maxPageSize {
+		commits = maxPageSize
+	}
+	repoCommits := []*githubCommit{}
+	err := c.api.do(http.MethodGet, pagePath(githubRepoPath(org, name)+"/commits", 1, commits), nil, &repoCommits)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list repository commits")
+	}
+	for _, rc := range repoCommits {
+		revisions.Commits = append(revisions.Commits, &domain.CodesetCommit{
+			ID:      rc.SHA,
+			Author:  rc.Commit.Author.Name,
+			Message: rc.Commit.Message,
+			Time:    rc.Commit.Author.Date,
+		})
+	}
+	return &revisions, nil
+}
+
+// getContents returns the contents of a repository path: a list of entries for a directory or a single entry,
+// including the file contents, for a file
+func (c *GitHubAdminClient) getContents(org, name, ref, path string) ([]*githubContent, bool, error) {
+	data := json.RawMessage{}
+	err := c.api.do(http.MethodGet, githubRepoPath(org, name)+"/contents/"+escapePath(path)+refQuery(ref), nil, &data)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, false, domain.ErrCodesetFileNotFound
+		}
+		return nil, false, errors.Wrap(err, "Failed to read repository contents")
+	}
+	if len(data) > 0 && data[0] == '[' {
+		contents := []*githubContent{}
+		return contents, true, json.Unmarshal(data, &contents)
+	}
+	content := githubContent{}
+	return []*githubContent{&content}, false, json.Unmarshal(data, &content)
+}
+
+// GetRepositoryFiles lists the entries of a repository directory at the given revision (the default branch
+// if ref is empty). If path points to a file, only the file entry is returned
+func (c *GitHubAdminClient) GetRepositoryFiles(org, name, ref, path string) ([]*domain.CodesetFile, error) {
+	c.logger.Printf("Listing files of repo %s for org '%s'...", name, org)
+	contents, _, err := c.getContents(org, name, ref, path)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]*domain.CodesetFile, 0, len(contents))
+	for _, content := range contents {
+		file := domain.CodesetFile{Path: content.Path, Type: content.Type}
+		if content.Type == domain.CodesetFileTypeFile {
+			file.Size = content.Size
+		}
+		files = append(files, &file)
+	}
+	return files, nil
+}
+
+// GetRepositoryFileContents returns the contents of a repository file at the given revision (the default
+// branch if ref is empty)
+func (c *GitHubAdminClient) GetRepositoryFileContents(org, name, ref, path string) ([]byte, error) {
+	c.logger.Printf("Reading file %s of repo %s for org '%s'...", path, name, org)
+	contents, isDir, err := c.getContents(org, name, ref, path)
+	if err != nil {
+		return nil, err
+	}
+	if isDir || contents[0].Type != domain.CodesetFileTypeFile {
+		return nil, domain.ErrCodesetNotAFile
+	}
+	// GitHub splits the base64 encoded contents into lines
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(contents[0].Content, "\n", ""))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode repository file")
+	}
+	return data, nil
+}
+
+// GetProjects retrieves the organizations the authenticated user belongs to
+func (c *GitHubAdminClient) GetProjects() ([]*domain.Project, error) {
+	c.logger.Printf("listing GitHub orgs....")
+	var projects []*domain.Project
+	for page := 1; ; page++ {
+		orgs := []*githubOrg{}
+		if err := c.api.do(http.MethodGet, pagePath("/user/orgs", page, maxPageSize), nil, &orgs); err != nil {
+			return nil, errors.Wrap(err, "Failed to list orgs")
+		}
+		for _, o := range orgs {
+			projects = append(projects, &domain.Project{Name: o.Login, Description: o.Description})
+		}
+		if len(orgs) < maxPageSize {
+			break
+		}
+	}
+	return projects, nil
+}
+
+// GetProject retrieves an organization by its name
+func (c *GitHubAdminClient) GetProject(name string) (*domain.Project, error) {
+	c.logger.Printf("Fetching GitHub org %s....", name)
+	org := githubOrg{}
+	if err := c.api.do(http.MethodGet, "/orgs/"+url.PathEscape(name), nil, &org); err != nil {
+		if isNotFound(err) {
+			return nil, errProjectNotFound
+		}
+		return nil, errors.Wrap(err, "Failed to make get org request")
+	}
+	return &domain.Project{Name: org.Login, Description: org.Description}, nil
+}
+
+// CreateProject checks that the organization exists, as GitHub organizations cannot be created through the API.
+// If ignoreExisting argument is true, the call does not return the project.
+func (c *GitHubAdminClient) CreateProject(name, desc string, ignoreExisting bool) (*domain.Project, error) {
+	project, err := c.GetProject(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Projects must exist as GitHub organizations")
+	}
+	if ignoreExisting {
+		return nil, nil
+	}
+	return project, nil
+}
+
+// DeleteProject is not supported, as the GitHub organizations are not managed by FuseML
+func (c *GitHubAdminClient) DeleteProject(org string) error {
+	return errProjectDeleteUnsupported
+}
+
+// UpdateProject is not supported, as the GitHub organizations are not managed by FuseML
+func (c *GitHubAdminClient) UpdateProject(org, desc string) error {
+	return errProjectUpdateUnsupported
+}
+
+// AddProjectMember is not supported, as the members of the GitHub organizations are not managed by FuseML
+func (c *GitHubAdminClient) AddProjectMember(org string, user *domain.User) (*string, error) {
+	return nil, errMembersUnsupported
+}
+
+// RemoveProjectMember is not supported, as the members of the GitHub organizations are not managed by FuseML
+func (c *GitHubAdminClient) RemoveProjectMember(org, name string) error {
+	return errMembersUnsupported
+}
@@ -0,0 +1,295 @@
+package gitprovider
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+const testToken = "test-token"
+
+func testLogger() *log.Logger {
+	logger := log.New(os.Stderr, "[test] ", log.Ltime)
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+// fakeGitHub is a fake GitHub API server, with the "workspace" organization holding the "mlflow-app" repository
+type fakeGitHub struct {
+	*httptest.Server
+	repos map[string]*githubRepo
+	hooks []githubHook
+}
+
+func newFakeGitHub(t *testing.T) *fakeGitHub {
+	f := &fakeGitHub{repos: map[string]*githubRepo{
+		"mlflow-app": {Name: "mlflow-app", Description: "MLflow app", CloneURL: "https://github.com/workspace/mlflow-app.git",
+			Topics: []string{"python"}},
+		"docs": {Name: "docs", CloneURL: "https://github.com/workspace/docs.git"},
+	}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user/orgs", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]githubOrg{{Login: "workspace", Description: "ML workspace"}})
+	})
+	mux.HandleFunc("/orgs/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/workspace":
+			json.NewEncoder(w).Encode(githubOrg{Login: "workspace", Description: "ML workspace"})
+		case "/orgs/workspace/repos":
+			repos := []*githubRepo{}
+			for _, name := range []string{"docs", "mlflow-app"} {
+				repos = append(repos, f.repos[name])
+			}
+			json.NewEncoder(w).Encode(repos)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mux.HandleFunc("/repos/workspace/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token "+testToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/repos/workspace/"), "/", 2)
+		repo, ok := f.repos[parts[0]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		resource := ""
+		if len(parts) > 1 {
+			resource = parts[1]
+		}
+		switch {
+		case resource == "":
+			json.NewEncoder(w).Encode(repo)
+		case resource == "topics" && r.Method == http.MethodPut:
+			body := map[string][]string{}
+			json.NewDecoder(r.Body).Decode(&body)
+			repo.Topics = body["names"]
+		case resource == "hooks" && r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(f.hooks)
+		case resource == "hooks" && r.Method == http.MethodPost:
+			hook := githubHook{}
+			json.NewDecoder(r.Body).Decode(&hook)
+			hook.ID = int64(len(f.hooks) + 1)
+			f.hooks = append(f.hooks, hook)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(hook)
+		case strings.HasPrefix(resource, "hooks/") && r.Method == http.MethodDelete:
+			if resource != "hooks/1" || len(f.hooks) == 0 {
+				http.NotFound(w, r)
+				return
+			}
+			f.hooks = nil
+			w.WriteHeader(http.StatusNoContent)
+		case resource == "contents/" && r.URL.Query().Get("ref") == "v1.0":
+			io.WriteString(w, `[{"path": "MLproject", "type": "file", "size": 17}, {"path": "model", "type": "dir", "size": 0}]`)
+		case resource == "contents/MLproject":
+			// GitHub splits the base64 encoded contents into lines
+			io.WriteString(w, `{"path": "MLproject", "type": "file", "size": 17, "content": "bmFtZTog\nbWxmbG93LWFwcAo=\n"}`)
+		case resource == "branches":
+			io.WriteString(w, `[{"name": "main", "commit": {"sha": "a1b2c3"}}, {"name": "dev", "commit": {"sha": "d4e5f6"}}]`)
+		case resource == "tags":
+			io.WriteString(w, `[{"name": "v1.0", "commit": {"sha": "a1b2c3"}}]`)
+		case resource == "commits":
+			if r.URL.Query().Get("per_page") != "1" {
+				t.Errorf("Unexpected number of commits requested: %s", r.URL.Query().Get("per_page"))
+			}
+			io.WriteString(w, `[{"sha": "a1b2c3", "commit": {"message": "Initial commit",
+				"author": {"name": "Jane", "date": "2021-06-01T10:00:00Z"}}}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func TestGitHubPrepareRepository(t *testing.T) {
+	f := newFakeGitHub(t)
+	client := newGitHubAdminClient(testLogger(), f.URL, testToken)
+
+	t.Run("existing repository", func(t *testing.T) {
+		codeset := &domain.Codeset{Name: "mlflow-app", Project: "workspace", Labels: []string{"mlflow"}}
+		listenerURL := "http://el-workflow.test:8080"
+		user, pass, err := client.PrepareRepository(codeset, &listenerURL)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if user != nil || pass != nil {
+			t.Errorf("Expected no credentials for a GitHub repository")
+		}
+
+		want := &domain.Codeset{Name: "mlflow-app", Project: "workspace", Description: "MLflow app",
+			Labels: []string{"mlflow"}, URL: "https://github.com/workspace/mlflow-app.git"}
+		if d := cmp.Diff(want, codeset); d != "" {
+			t.Errorf("Unexpected codeset (-want +got): %s", d)
+		}
+		if d := cmp.Diff([]string{"python", codesetTopic, "mlflow"}, f.repos["mlflow-app"].Topics); d != "" {
+			t.Errorf("Unexpected repository topics (-want +got): %s", d)
+		}
+		if len(f.hooks) != 1 || f.hooks[0].Config["url"] != listenerURL || f.hooks[0].Events[0] != "push" {
+			t.Errorf("Unexpected webhooks: %v", f.hooks)
+		}
+
+		// the webhook is not created twice
+		hookID, err := client.CreateRepoWebhook("workspace", "mlflow-app", &listenerURL)
+		if err != nil || *hookID != 1 || len(f.hooks) != 1 {
+			t.Errorf("Expected the existing webhook to be returned, got %v (error: %v)", hookID, err)
+		}
+	})
+
+	t.Run("repository not found", func(t *testing.T) {
+		_, _, err := client.PrepareRepository(&domain.Codeset{Name: "unknown", Project: "workspace"}, nil)
+		if err == nil || !strings.Contains(err.Error(), errRepoNotFound.Error()) {
+			t.Errorf("Expected %q error, got %v", errRepoNotFound, err)
+		}
+	})
+}
+
+func TestGitHubGetRepositories(t *testing.T) {
+	f := newFakeGitHub(t)
+	client := newGitHubAdminClient(testLogger(), f.URL, testToken)
+
+	_, err := client.GetRepository("workspace", "mlflow-app")
+	if err != errRepoNotRegistered {
+		t.Errorf("Expected %q error, got %v", errRepoNotRegistered, err)
+	}
+
+	if _, _, err = client.PrepareRepository(&domain.Codeset{Name: "mlflow-app", Project: "workspace"}, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	codeset, err := client.GetRepository("workspace", "mlflow-app")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff([]string{"python"}, codeset.Labels); d != "" {
+		t.Errorf("Unexpected codeset labels (-want +got): %s", d)
+	}
+
+	// only the registered repositories are listed
+	for _, label := range []*string{nil, util.RefString("python")} {
+		codesets, err := client.GetRepositories(nil, label)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(codesets) != 1 || codesets[0].Name != "mlflow-app" || codesets[0].Project != "workspace" {
+			t.Errorf("Unexpected codesets: %v", codesets)
+		}
+	}
+	codesets, err := client.GetRepositories(util.RefString("workspace"), util.RefString("mlflow"))
+	if err != nil || len(codesets) != 0 {
+		t.Errorf("Expected no codesets with the label, got %v (error: %v)", codesets, err)
+	}
+
+	// deleting the codeset only unregisters the repository
+	if err = client.DeleteRepository("workspace", "mlflow-app"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff([]string{"python"}, f.repos["mlflow-app"].Topics); d != "" {
+		t.Errorf("Unexpected repository topics (-want +got): %s", d)
+	}
+	if err = client.DeleteRepository("workspace", "unknown"); err != nil {
+		t.Errorf("Unexpected error deleting a repository that does not exist: %v", err)
+	}
+}
+
+func TestGitHubGetRepositoryRevisions(t *testing.T) {
+	f := newFakeGitHub(t)
+	client := newGitHubAdminClient(testLogger(), f.URL, testToken)
+
+	got, err := client.GetRepositoryRevisions("workspace", "mlflow-app", 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := &domain.CodesetRevisions{
+		Branches: []*domain.CodesetRef{{Name: "main", Commit: "a1b2c3"}, {Name: "dev", Commit: "d4e5f6"}},
+		Tags:     []*domain.CodesetRef{{Name: "v1.0", Commit: "a1b2c3"}},
+		Commits: []*domain.CodesetCommit{{ID: "a1b2c3", Author: "Jane", Message: "Initial commit",
+			Time: time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)}},
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Unexpected revisions (-want +got): %s", d)
+	}
+}
+
+func TestGitHubRepositoryFiles(t *testing.T) {
+	f := newFakeGitHub(t)
+	client := newGitHubAdminClient(testLogger(), f.URL, testToken)
+
+	files, err := client.GetRepositoryFiles("workspace", "mlflow-app", "v1.0", "/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []*domain.CodesetFile{{Path: "MLproject", Type: "file", Size: 17}, {Path: "model", Type: "dir"}}
+	if d := cmp.Diff(want, files); d != "" {
+		t.Errorf("Unexpected files (-want +got): %s", d)
+	}
+	files, err = client.GetRepositoryFiles("workspace", "mlflow-app", "", "MLproject")
+	if err != nil || len(files) != 1 || files[0].Path != "MLproject" {
+		t.Errorf("Expected the file entry, got %v (error: %v)", files, err)
+	}
+
+	contents, err := client.GetRepositoryFileContents("workspace", "mlflow-app", "", "MLproject")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(contents) != "name: mlflow-app\n" {
+		t.Errorf("Unexpected file contents: %q", contents)
+	}
+	if _, err = client.GetRepositoryFileContents("workspace", "mlflow-app", "v1.0", ""); err != domain.ErrCodesetNotAFile {
+		t.Errorf("Expected %q error, got %v", domain.ErrCodesetNotAFile, err)
+	}
+	if _, err = client.GetRepositoryFileContents("workspace", "mlflow-app", "", "conda.yaml"); err != domain.ErrCodesetFileNotFound {
+		t.Errorf("Expected %q error, got %v", domain.ErrCodesetFileNotFound, err)
+	}
+}
+
+func TestGitHubWebhooksAndProjects(t *testing.T) {
+	f := newFakeGitHub(t)
+	client := newGitHubAdminClient(testLogger(), f.URL, testToken)
+
+	listenerURL := "http://el-workflow.test:8080"
+	hookID, err := client.CreateRepoWebhook("workspace", "mlflow-app", &listenerURL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err = client.DeleteRepoWebhook("workspace", "mlflow-app", hookID); err != nil || len(f.hooks) != 0 {
+		t.Errorf("Expected the webhook to be deleted, got %v (error: %v)", f.hooks, err)
+	}
+	if err = client.DeleteRepoWebhook("workspace", "mlflow-app", hookID); err != nil {
+		t.Errorf("Unexpected error deleting a webhook that does not exist: %v", err)
+	}
+
+	projects, err := client.GetProjects()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff([]*domain.Project{{Name: "workspace", Description: "ML workspace"}}, projects); d != "" {
+		t.Errorf("Unexpected projects (-want +got): %s", d)
+	}
+	if _, err = client.CreateProject("workspace", "", false); err != nil {
+		t.Errorf("Unexpected error creating a project for an existing organization: %v", err)
+	}
+	if _, err = client.CreateProject("unknown", "", false); err == nil {
+		t.Errorf("Expected an error creating a project for an organization that does not exist")
+	}
+	if err = client.DeleteProject("workspace"); err != errProjectDeleteUnsupported {
+		t.Errorf("Expected %q error, got %v", errProjectDeleteUnsupported, err)
+	}
+}
@@ -0,0 +1,514 @@
+package gitprovider
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	config "github.com/fuseml/fuseml-core/pkg/core/config"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+const (
+	// defaultGitLabURL is the URL of the GitLab server, used when GITLAB_URL is not set
+	defaultGitLabURL = "https://gitlab.com"
+	// gitlabMinAccessLevel is the minimum access level (developer) to the GitLab groups listed as projects
+	gitlabMinAccessLevel = 30
+
+	errGITLABTOKENMissing = gitProviderErr("Value for GitLab access token (GITLAB_TOKEN) was not provided.")
+)
+
+type gitlabGroup struct {
+	FullPath    string `json:"full_path"`
+	Description string `json:"description"`
+}
+
+type gitlabProject struct {
+	Path          string   `json:"path"`
+	Description   string   `json:"description"`
+	HTTPURLToRepo string   `json:"http_url_to_repo"`
+	Topics        []string `json:"topics"`
+}
+
+type gitlabHook struct {
+	ID            int64  `json:"id,omitempty"`
+	URL           string `json:"url"`
+	PushEvents    bool   `json:"push_events"`
+	TagPushEvents bool   `json:"tag_push_events"`
+	Token         string `json:"token,omitempty"`
+}
+
+type gitlabRef struct {
+	Name   string `json:"name"`
+	Commit struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+}
+
+type gitlabCommit struct {
+	ID         string    `json:"id"`
+	Message    string    `json:"message"`
+	AuthorName string    `json:"author_name"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type gitlabTreeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Mode string `json:"mode"`
+}
+
+type gitlabFile struct {
+	FilePath string `json:"file_path"`
+	Size     int64  `json:"size"`
+	Content  string `json:"content"`
+}
+
+// gitlabFileTypes maps the types of the GitLab repository tree entries to codeset file types
+var gitlabFileTypes = map[string]string{
+	"blob":   domain.CodesetFileTypeFile,
+	"tree":   domain.CodesetFileTypeDir,
+	"commit": domain.CodesetFileTypeSubmodule,
+}
+
+// gitlabSymlinkMode is the git file mode of symbolic links
+const gitlabSymlinkMode = "120000"
+
+// GitLabAdminClient implements the GitAdminClient interface for GitLab. Projects are GitLab groups and codesets
+// are references to existing GitLab projects: registering a codeset adds the codeset topic and the codeset labels
+// to the GitLab project topics, and deleting it only removes the codeset topic.
+type GitLabAdminClient struct {
+	api    *restClient
+	logger *log.Logger
+}
+
+// NewGitLabAdminClient creates a new GitLab client, authenticating with the access token provided as the
+// GITLAB_TOKEN env variable. The GitLab server defaults to gitlab.com and can be changed with the GITLAB_URL
+// env variable.
+func NewGitLabAdminClient(logger *log.Logger) (*GitLabAdminClient, error) {
+	token, exists := os.LookupEnv("GITLAB_TOKEN")
+	if !exists {
+		return nil, errGITLABTOKENMissing
+	}
+	serverURL, exists := os.LookupEnv("GITLAB_URL")
+	if !exists {
+		serverURL = defaultGitLabURL
+	}
+
+	logger.Printf("Using GitLab from: %s", serverURL)
+	return newGitLabAdminClient(logger, serverURL, token), nil
+}
+
+func newGitLabAdminClient(logger *log.Logger, serverURL, token string) *GitLabAdminClient {
+	header := http.Header{}
+	header.Set("PRIVATE-TOKEN", token)
+	api := newRestClient(strings.TrimSuffix(serverURL, "/")+"/api/v4", header)
+	return &GitLabAdminClient{api: api, logger: logger}
+}
+
+// gitlabProjectPath returns the API path of a GitLab project, identified by its URL encoded full path
+func gitlabProjectPath(group, name string) string {
+	return "/projects/" + url.QueryEscape(group+"/"+name)
+}
+
+func (c *GitLabAdminClient) getProject(group, name string) (*gitlabProject, error) {
+	project := gitlabProject{}
+	if err := c.api.do(http.MethodGet, gitlabProjectPath(group, name), nil, &project); err != nil {
+		if isNotFound(err) {
+			return nil, errRepoNotFound
+		}
+		return nil, errors.Wrap(err, "Failed to read repository")
+	}
+	return &project, nil
+}
+
+func (c *GitLabAdminClient) setProjectTopics(group, name string, topics []string) error {
+	body := map[string][]string{"topics": topics}
+	if err := c.api.do(http.MethodPut, gitlabProjectPath(group, name), body, nil); err != nil {
+		return errors.Wrap(err, "Failed to set repository topics")
+	}
+	return nil
+}
+
+func gitlabProjectToCodeset(group string, project *gitlabProject) *domain.Codeset {
+	return &domain.Codeset{
+		Name:        project.Path,
+		Project:     group,
+		Description: project.Description,
+		Labels:      codesetLabels(project.Topics),
+		URL:         project.HTTPURLToRepo,
+	}
+}
+
+// PrepareRepository registers an existing GitLab project as a codeset. GitLab users are not managed by FuseML,
+// so no credentials are returned.
+func (c *GitLabAdminClient) PrepareRepository(code *domain.Codeset, listenerURL *string) (*string, *string, error) {
+	project, err := c.getProject(code.Project, code.Name)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Registering GitLab repository failed")
+	}
+
+	c.logger.Printf("Registering GitLab repository '%s' under '%s'...", code.Name, code.Project)
+	if err = c.setProjectTopics(code.Project, code.Name, registeredTopics(project.Topics, code.Labels)); err != nil {
+		return nil, nil, err
+	}
+	code.URL = project.HTTPURLToRepo
+	if code.Description == "" {
+		code.Description = project.Description
+	}
+
+	_, err = c.CreateRepoWebhook(code.Project, code.Name, listenerURL)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Creating webhook failed")
+	}
+	return nil, nil, nil
+}
+
+// CreateRepoWebhook creates a webhook for the push and tag push events of the repository, sent to the listenerURL
+func (c *GitLabAdminClient) CreateRepoWebhook(group, name string, listenerURL *string) (*int64, error) {
+	if listenerURL == nil {
+		c.logger.Printf("Webhook listener URL not provided, skipping creation")
+		return nil, nil
+	}
+
+	hooks := []gitlabHook{}
+	if err := c.api.do(http.MethodGet, gitlabProjectPath(group, name)+"/hooks", nil, &hooks); err != nil {
+		return nil, errors.Wrap(err, "Failed to list webhooks")
+	}
+	for _, hook := range hooks {
+		if hook.URL == *listenerURL {
+			c.logger.Printf("Webhook for '%s' already exists", name)
+			return &hook.ID, nil
+		}
+	}
+
+	c.logger.Printf("Creating Webhook for '%s' under '%s'...", name, group)
+	hook := gitlabHook{}
+	err := c.api.do(http.MethodPost, gitlabProjectPath(group, name)+"/hooks", gitlabHook{
+		URL:           *listenerURL,
+		PushEvents:    true,
+		TagPushEvents: true,
+		Token:         config.HookSecret,
+	}, &hook)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create webhook")
+	}
+	return &hook.ID, nil
+}
+
+// DeleteRepoWebhook deletes a webhook of the repository
+func (c *GitLabAdminClient) DeleteRepoWebhook(group, name string, hookID *int64) error {
+	c.logger.Printf("Deleting Webhook for %q under %q...", name, group)
+	err := c.api.do(http.MethodDelete, fmt.Sprintf("%s/hooks/%d", gitlabProjectPath(group, name), *hookID), nil, nil)
+	if err != nil {
+		if isNotFound(err) {
+			c.logger.Printf("Webhook not found, skipping deletion")
+			return nil
+		}
+		return errors.Wrap(err, "Failed to delete webhook")
+	}
+	return nil
+}
+
+// GetRepoWebhooks returns the webhooks of the repository
+func (c *GitLabAdminClient) GetRepoWebhooks(group, name string) ([]*domain.CodesetWebhook, error) {
+	hooks := []gitlabHook{}
+	if err := c.api.do(http.MethodGet, gitlabProjectPath(group, name)+"/hooks", nil, &hooks); err != nil {
+		return nil, errors.Wrap(err, "Failed to list webhooks")
+	}
+	result := make([]*domain.CodesetWebhook, 0, len(hooks))
+	for _, hook := range hooks {
+		result = append(result, &domain.CodesetWebhook{ID: hook.ID, URL: hook.URL})
+	}
+	return result, nil
+}
+
+// GetRepositories retrieves the repositories registered as codesets, can be filtered by project (group) and label
+func (c *GitLabAdminClient) GetRepositories(group, label *string) ([]*domain.Codeset, error) {
+	var groups []string
+	if group == nil {
+		projects, err := c.GetProjects()
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			groups = append(groups, p.Name)
+		}
+	} else {
+		groups = append(groups, *group)
+	}
+
+	codesets := []*domain.Codeset{}
+	for _, g := range groups {
+		c.logger.Printf("Listing repos for group '%s'...", g)
+		for page := 1; ; page++ {
+			projects := []*gitlabProject{}
+			err := c.api.do(http.MethodGet, pagePath("/groups/"+url.QueryEscape(g)+"/projects", page, maxPageSize), nil, &projects)
+			if err != nil {
+				return nil, errors.Wrap(err, "Failed to list project repos")
+			}
+			for _, p := range projects {
+				if !util.StringInSlice(codesetTopic, p.Topics) || (label != nil && !util.StringInSlice(*label, p.Topics)) {
+					continue
+				}
+				codesets = append(codesets, gitlabProjectToCodeset(g, p))
+			}
+			if len(projects) < maxPageSize {
+				break
+			}
+		}
+	}
+	return codesets, nil
+}
+
+// GetRepository retrieves a repository registered as a codeset
+func (c *GitLabAdminClient) GetRepository(group, name string) (*domain.Codeset, error) {
+	c.logger.Printf("Get repo %s for group '%s'...", name, group)
+	project, err := c.getProject(group, name)
+	if err != nil {
+		return nil, err
+	}
+	if !util.StringInSlice(codesetTopic, project.Topics) {
+		return nil, errRepoNotRegistered
+	}
+	return gitlabProjectToCodeset(group, project), nil
+}
+
+// DeleteRepository unregisters a repository as a codeset, the repository itself is not deleted
+func (c *GitLabAdminClient) DeleteRepository(group, name string) error {
+	c.logger.Printf("Going to unregister repo %s for group '%s'...", name, group)
+	project, err := c.getProject(group, name)
+	if err != nil {
+		if err == errRepoNotFound {
+			c.logger.Printf("Repo does not exist, no need to unregister")
+			return nil
+		}
+		return err
+	}
+	return c.setProjectTopics(group, name, codesetLabels(project.Topics))
+}
+
+// AddRepositoryLabels adds labels to the topics of a GitLab project registered as a codeset
+func (c *GitLabAdminClient) AddRepositoryLabels(group, name string, labels []string) error {
+	project, err := c.getProject(group, name)
+	if err != nil {
+		return err
+	}
+	return c.setProjectTopics(group, name, registeredTopics(project.Topics, labels))
+}
+
+// GetRepositoryRevisions retrieves the branches, tags and the most recent commits of a repository
+func (c *GitLabAdminClient) GetRepositoryRevisions(group, name string, commits int) (*domain.CodesetRevisions, error) {
+	c.logger.Printf("Listing revisions of repo %s for group '%s'...", name, group)
+	revisions := domain.CodesetRevisions{}
+
+	for _, kind := range []string{"branches", "tags"} {
+		for page := 1; ; page++ {
+			refs := []*gitlabRef{}
+			err := c.api.do(http.MethodGet, pagePath(gitlabProjectPath(group, name)+"/repository/"+kind, page, maxPageSize), nil, &refs)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to list repository %s", kind)
+			}
+			for _, r := range refs {
+				ref := &domain.CodesetRef{Name: r.Name, Commit: r.Commit.ID}
+				if kind == "branches" {
+					revisions.Branches = append(revisions.Branches, ref)
+				} else {
+					revisions.Tags = append(revisions.Tags, ref)
+				}
+			}
+			if len(refs) < maxPageSize {
+				break
+			}
+		}
+	}
+
+	if commits <= 0 {
+		return &revisions, nil
+	}
+	if commits > maxPageSize {
+		commits = maxPageSize
+	}
+	projectCommits := []*gitlabCommit{}
+	err := c.api.do(http.MethodGet, pagePath(gitlabProjectPath(group, name)+"/repository/commits", 1, commits), nil, &projectCommits)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list repository commits")
+	}
+	for _, pc := range projectCommits {
+		revisions.Commits = append(revisions.Commits, &domain.CodesetCommit{
+			ID:      pc.ID,
+			Author:  pc.AuthorName,
+			Message: pc.Message,
+			Time:    pc.CreatedAt,
+		})
+	}
+	return &revisions, nil
+}
+
+// gitlabRevision returns the revision to request from GitLab, which requires one for accessing files
+func gitlabRevision(ref string) string {
+	if ref == "" {
+		return "HEAD"
+	}
+	return ref
+}
+
+// getTree lists the entries of a repository directory
+func (c *GitLabAdminClient) getTree(group, name, ref, path string) ([]*gitlabTreeEntry, error) {
+	treePath := fmt.Sprintf("%s/repository/tree?path=%s&ref=%s", gitlabProjectPath(group, name),
+		url.QueryEscape(strings.Trim(path, "/")), url.QueryEscape(gitlabRevision(ref)))
+	var entries []*gitlabTreeEntry
+	for page := 1; ; page++ {
+		pageEntries := []*gitlabTreeEntry{}
+		if err := c.api.do(http.MethodGet, pagePath(treePath, page, maxPageSize), nil, &pageEntries); err != nil {
+			if isNotFound(err) {
+				return nil, domain.ErrCodesetFileNotFound
+			}
+			return nil, errors.Wrap(err, "Failed to list repository files")
+		}
+		entries = append(entries, pageEntries...)
+		if len(pageEntries) < maxPageSize {
+			return entries, nil
+		}
+	}
+}
+
+// getFile returns a repository file, including its contents
+func (c *GitLabAdminClient) getFile(group, name, ref, path string) (*gitlabFile, error) {
+	filePath := fmt.Sprintf("%s/repository/files/%s?ref=%s", gitlabProjectPath(group, name),
+		url.PathEscape(strings.Trim(path, "/")), url.QueryEscape(gitlabRevision(ref)))
+	file := gitlabFile{}
+	if err := c.api.do(http.MethodGet, filePath, nil, &file); err != nil {
+		if isNotFound(err) {
+			return nil, domain.ErrCodesetFileNotFound
+		}
+		return nil, errors.Wrap(err, "Failed to read repository file")
+	}
+	return &file, nil
+}
+
+// GetRepositoryFiles lists the entries of a repository directory at the given revision (the default branch
+// if ref is empty). If path points to a file, only the file entry is returned
+func (c *GitLabAdminClient) GetRepositoryFiles(group, name, ref, path string) ([]*domain.CodesetFile, error) {
+	c.logger.Printf("Listing files of repo %s for group '%s'...", name, group)
+	entries, err := c.getTree(group, name, ref, path)
+	if err != nil && err != domain.ErrCodesetFileNotFound {
+		return nil, err
+	}
+	// GitLab does not list anything for a path pointing to a file
+	if len(entries) == 0 && strings.Trim(path, "/") != "" {
+		file, err := c.getFile(group, name, ref, path)
+		if err != nil {
+			return nil, err
+		}
+		return []*domain.CodesetFile{{Path: file.FilePath, Type: domain.CodesetFileTypeFile, Size: file.Size}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*domain.CodesetFile, 0, len(entries))
+	for _, entry := range entries {
+		file := domain.CodesetFile{Path: entry.Path, Type: gitlabFileTypes[entry.Type]}
+		if entry.Mode == gitlabSymlinkMode {
+			file.Type = domain.CodesetFileTypeSymlink
+		}
+		files = append(files, &file)
+	}
+	return files, nil
+}
+
+// GetRepositoryFileContents returns the contents of a repository file at the given revision (the default
+// branch if ref is empty)
+func (c *GitLabAdminClient) GetRepositoryFileContents(group, name, ref, path string) ([]byte, error) {
+	c.logger.Printf("Reading file %s of repo %s for group '%s'...", path, name, group)
+	file, err := c.getFile(group, name, ref, path)
+	if err == domain.ErrCodesetFileNotFound {
+		// check whether the path points to a directory
+		if entries, _ := c.getTree(group, name, ref, path); len(entries) > 0 {
+			return nil, domain.ErrCodesetNotAFile
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(file.Content)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode repository file")
+	}
+	return data, nil
+}
+
+// GetProjects retrieves the groups the authenticated user can push to
+func (c *GitLabAdminClient) GetProjects() ([]*domain.Project, error) {
+	c.logger.Printf("listing GitLab groups....")
+	var projects []*domain.Project
+	for page := 1; ; page++ {
+		groups := []*gitlabGroup{}
+		path := pagePath(fmt.Sprintf("/groups?min_access_level=%d", gitlabMinAccessLevel), page, maxPageSize)
+		if err := c.api.do(http.MethodGet, path, nil, &groups); err != nil {
+			return nil, errors.Wrap(err, "Failed to list groups")
+		}
+		for _, g := range groups {
+			projects = append(projects, &domain.Project{Name: g.FullPath, Description: g.Description})
+		}
+		if len(groups) < maxPageSize {
+			break
+		}
+	}
+	return projects, nil
+}
+
+// GetProject retrieves a group by its full path
+func (c *GitLabAdminClient) GetProject(name string) (*domain.Project, error) {
+	c.logger.Printf("Fetching GitLab group %s....", name)
+	group := gitlabGroup{}
+	if err := c.api.do(http.MethodGet, "/groups/"+url.QueryEscape(name), nil, &group); err != nil {
+		if isNotFound(err) {
+			return nil, errProjectNotFound
+		}
+		return nil, errors.Wrap(err, "Failed to make get group request")
+	}
+	return &domain.Project{Name: group.FullPath, Description: group.Description}, nil
+}
+
+// CreateProject checks that the group exists, FuseML does not create GitLab groups.
+// If ignoreExisting argument is true, the call does not return the project.
+func (c *GitLabAdminClient) CreateProject(name, desc string, ignoreExisting bool) (*domain.Project, error) {
+	project, err := c.GetProject(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Projects must exist as GitLab groups")
+	}
+	if ignoreExisting {
+		return nil, nil
+	}
+	return project, nil
+}
+
+// DeleteProject is not supported, as the GitLab groups are not managed by FuseML
+func (c *GitLabAdminClient) DeleteProject(group string) error {
+	return errProjectDeleteUnsupported
+}
+
+// UpdateProject is not supported, as the GitLab groups are not managed by FuseML
+func (c *GitLabAdminClient) UpdateProject(group, desc string) error {
+	return errProjectUpdateUnsupported
+}
+
+// AddProjectMember is not supported, as the members of the GitLab groups are not managed by FuseML
+func (c *GitLabAdminClient) AddProjectMember(group string, user *domain.User) (*string, error) {
+	return nil, errMembersUnsupported
+}
+
+// RemoveProjectMember is not supported, as the members of the GitLab groups are not managed by FuseML
+func (c *GitLabAdminClient) RemoveProjectMember(group, name string) error {
+	return errMembersUnsupported
+}
@@ -0,0 +1,168 @@
+package gitprovider
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// fakeGitLab is a fake GitLab API server, with the "ml/team" group holding the "mlflow-app" project
+type fakeGitLab struct {
+	*httptest.Server
+	project *gitlabProject
+	hooks   []gitlabHook
+}
+
+func newFakeGitLab(t *testing.T) *fakeGitLab {
+	f := &fakeGitLab{project: &gitlabProject{Path: "mlflow-app", Description: "MLflow app",
+		HTTPURLToRepo: "https://gitlab.test/ml/team/mlflow-app.git", Topics: []string{}}}
+	projectPath := "/api/v4/projects/ml%2Fteam%2Fmlflow-app"
+
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != testToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		path := r.URL.EscapedPath()
+		switch {
+		case path == "/api/v4/groups":
+			if r.URL.Query().Get("min_access_level") != "30" {
+				t.Errorf("Unexpected min access level: %s", r.URL.Query().Get("min_access_level"))
+			}
+			json.NewEncoder(w).Encode([]gitlabGroup{{FullPath: "ml/team"}})
+		case path == "/api/v4/groups/ml%2Fteam":
+			json.NewEncoder(w).Encode(gitlabGroup{FullPath: "ml/team"})
+		case path == "/api/v4/groups/ml%2Fteam/projects":
+			json.NewEncoder(w).Encode([]*gitlabProject{f.project})
+		case path == projectPath && r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(f.project)
+		case path == projectPath && r.Method == http.MethodPut:
+			body := map[string][]string{}
+			json.NewDecoder(r.Body).Decode(&body)
+			f.project.Topics = body["topics"]
+			json.NewEncoder(w).Encode(f.project)
+		case path == projectPath+"/hooks" && r.Method == http.MethodGet:
+			json.NewEncoder(w).Encode(f.hooks)
+		case path == projectPath+"/hooks" && r.Method == http.MethodPost:
+			hook := gitlabHook{}
+			json.NewDecoder(r.Body).Decode(&hook)
+			hook.ID = 7
+			f.hooks = append(f.hooks, hook)
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(hook)
+		case path == projectPath+"/hooks/7" && r.Method == http.MethodDelete && len(f.hooks) > 0:
+			f.hooks = nil
+			w.WriteHeader(http.StatusNoContent)
+		case path == projectPath+"/repository/tree":
+			switch r.URL.Query().Get("path") {
+			case "":
+				io.WriteString(w, `[{"path": "MLproject", "type": "blob", "mode": "100644"},
+					{"path": "model", "type": "tree", "mode": "040000"}, {"path": "data", "type": "blob", "mode": "120000"}]`)
+			case "MLproject":
+				io.WriteString(w, `[]`)
+			default:
+				http.NotFound(w, r)
+			}
+		case path == projectPath+"/repository/files/MLproject" && r.URL.Query().Get("ref") == "HEAD":
+			io.WriteString(w, `{"file_path": "MLproject", "size": 17, "content": "bmFtZTogbWxmbG93LWFwcAo="}`)
+		case path == projectPath+"/repository/branches":
+			io.WriteString(w, `[{"name": "main", "commit": {"id": "a1b2c3"}}]`)
+		case path == projectPath+"/repository/tags":
+			io.WriteString(w, `[]`)
+		case path == projectPath+"/repository/commits":
+			io.WriteString(w, `[{"id": "a1b2c3", "message": "Initial commit", "author_name": "Jane",
+				"created_at": "2021-06-01T10:00:00Z"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func TestGitLabRepositories(t *testing.T) {
+	f := newFakeGitLab(t)
+	client := newGitLabAdminClient(testLogger(), f.URL+"/", testToken)
+
+	listenerURL := "http://el-workflow.test:8080"
+	codeset := &domain.Codeset{Name: "mlflow-app", Project: "ml/team", Labels: []string{"mlflow"}}
+	if _, _, err := client.PrepareRepository(codeset, &listenerURL); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff([]string{codesetTopic, "mlflow"}, f.project.Topics); d != "" {
+		t.Errorf("Unexpected project topics (-want +got): %s", d)
+	}
+	if len(f.hooks) != 1 || f.hooks[0].URL != listenerURL || !f.hooks[0].PushEvents || !f.hooks[0].TagPushEvents {
+		t.Errorf("Unexpected webhooks: %v", f.hooks)
+	}
+
+	want := []*domain.Codeset{{Name: "mlflow-app", Project: "ml/team", Description: "MLflow app",
+		Labels: []string{"mlflow"}, URL: "https://gitlab.test/ml/team/mlflow-app.git"}}
+	got, err := client.GetRepositories(nil, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Unexpected codesets (-want +got): %s", d)
+	}
+
+	revisions, err := client.GetRepositoryRevisions("ml/team", "mlflow-app", 5)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	wantRevisions := &domain.CodesetRevisions{
+		Branches: []*domain.CodesetRef{{Name: "main", Commit: "a1b2c3"}},
+		Commits: []*domain.CodesetCommit{{ID: "a1b2c3", Author: "Jane", Message: "Initial commit",
+			Time: time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)}},
+	}
+	if d := cmp.Diff(wantRevisions, revisions); d != "" {
+		t.Errorf("Unexpected revisions (-want +got): %s", d)
+	}
+
+	files, err := client.GetRepositoryFiles("ml/team", "mlflow-app", "", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	wantFiles := []*domain.CodesetFile{{Path: "MLproject", Type: "file"}, {Path: "model", Type: "dir"}, {Path: "data", Type: "symlink"}}
+	if d := cmp.Diff(wantFiles, files); d != "" {
+		t.Errorf("Unexpected files (-want +got): %s", d)
+	}
+	files, err = client.GetRepositoryFiles("ml/team", "mlflow-app", "", "MLproject")
+	if d := cmp.Diff([]*domain.CodesetFile{{Path: "MLproject", Type: "file", Size: 17}}, files); err != nil || d != "" {
+		t.Errorf("Unexpected file entry (-want +got): %s (error: %v)", d, err)
+	}
+	contents, err := client.GetRepositoryFileContents("ml/team", "mlflow-app", "", "MLproject")
+	if err != nil || string(contents) != "name: mlflow-app\n" {
+		t.Errorf("Unexpected file contents %q (error: %v)", contents, err)
+	}
+	if _, err = client.GetRepositoryFileContents("ml/team", "mlflow-app", "", "/"); err != domain.ErrCodesetNotAFile {
+		t.Errorf("Expected %q error, got %v", domain.ErrCodesetNotAFile, err)
+	}
+	if _, err = client.GetRepositoryFiles("ml/team", "mlflow-app", "", "unknown"); err != domain.ErrCodesetFileNotFound {
+		t.Errorf("Expected %q error, got %v", domain.ErrCodesetFileNotFound, err)
+	}
+
+	hookID := int64(7)
+	if err = client.DeleteRepoWebhook("ml/team", "mlflow-app", &hookID); err != nil || len(f.hooks) != 0 {
+		t.Errorf("Expected the webhook to be deleted, got %v (error: %v)", f.hooks, err)
+	}
+
+	if err = client.DeleteRepository("ml/team", "mlflow-app"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err = client.GetRepository("ml/team", "mlflow-app"); err != errRepoNotRegistered {
+		t.Errorf("Expected %q error, got %v", errRepoNotRegistered, err)
+	}
+	_, _, err = client.PrepareRepository(&domain.Codeset{Name: "unknown", Project: "ml/team"}, nil)
+	if err == nil || !strings.Contains(err.Error(), errRepoNotFound.Error()) {
+		t.Errorf("Expected %q error, got %v", errRepoNotFound, err)
+	}
+}
@@ -0,0 +1,675 @@
+package gitprovider
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/pkg/errors"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+const (
+	// localRepoSuffix is the suffix of the bare repository directories
+	localRepoSuffix = ".git"
+	// localDescriptionFile holds the description of a project or repository
+	localDescriptionFile = "description"
+	// localLabelsFile holds the labels of a repository, one per line
+	localLabelsFile = "fuseml-labels"
+	// localWebhooksFile holds the webhooks of a repository
+	localWebhooksFile = "fuseml-webhooks.json"
+	// localMembersFile holds the members of a project
+	localMembersFile = "fuseml-members.json"
+	// defaultRepoDescription is the description that git sets for new repositories
+	defaultRepoDescription = "Unnamed repository; edit this file 'description' to name the repository."
+)
+
+// localWebhook is a webhook recorded for a local repository
+type localWebhook struct {
+	ID  int64  `json:"id"`
+	URL string `json:"url"`
+}
+
+// localMember is a member recorded for a local project
+type localMember struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+// LocalAdminClient implements the GitAdminClient interface with bare git repositories kept in a local directory,
+// with a sub-directory for each project. It does not need a git server, which makes it suitable for tests and for
+// running FuseML locally. The webhooks are recorded with the repositories, but the push events are not delivered.
+type LocalAdminClient struct {
+	sync.Mutex
+	dir    string
+	logger *log.Logger
+}
+
+// NewLocalAdminClient creates a new local git client, keeping the repositories in dir
+func NewLocalAdminClient(logger *log.Logger, dir string) (*LocalAdminClient, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to resolve the local git directory")
+	}
+	if err = os.MkdirAll(absDir, 0755); err != nil {
+		return nil, errors.Wrap(err, "Failed to create the local git directory")
+	}
+	logger.Printf("Using local git repositories from: %s", absDir)
+	return &LocalAdminClient{dir: absDir, logger: logger}, nil
+}
+
+func (c *LocalAdminClient) projectDir(org string) string {
+	return filepath.Join(c.dir, org)
+}
+
+func (c *LocalAdminClient) repoDir(org, name string) string {
+	return filepath.Join(c.dir, org, name+localRepoSuffix)
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// readFile returns the trimmed content of a file, or an empty string if the file cannot be read
+func readFile(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func (c *LocalAdminClient) readCodeset(org, name string) *domain.Codeset {
+	dir := c.repoDir(org, name)
+	description := readFile(filepath.Join(dir, localDescriptionFile))
+	if description == defaultRepoDescription {
+		description = ""
+	}
+	labels := []string{}
+	if content := readFile(filepath.Join(dir, localLabelsFile)); content != "" {
+		labels = strings.Split(content, "\n")
+	}
+	return &domain.Codeset{
+		Name:        name,
+		Project:     org,
+		Description: description,
+		Labels:      labels,
+		URL:         "file://" + dir,
+	}
+}
+
+func (c *LocalAdminClient) readWebhooks(org, name string) ([]*localWebhook, error) {
+	hooks := []*localWebhook{}
+	data, err := ioutil.ReadFile(filepath.Join(c.repoDir(org, name), localWebhooksFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return hooks, nil
+		}
+		return nil, err
+	}
+	return hooks, json.Unmarshal(data, &hooks)
+}
+
+func (c *LocalAdminClient) readMembers(org string) ([]*domain.User, error) {
+	members := []*localMember{}
+	data, err := ioutil.ReadFile(filepath.Join(c.projectDir(org), localMembersFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	users := make([]*domain.User, 0, len(members))
+	for _, m := range members {
+		users = append(users, &domain.User{Name: m.Name, Email: m.Email, Role: m.Role})
+	}
+	return users, nil
+}
+
+func (c *LocalAdminClient) writeMembers(org string, users []*domain.User) error {
+	members := make([]*localMember, 0, len(users))
+	for _, u := range users {
+		members = append(members, &localMember{Name: u.Name, Email: u.Email, Role: u.Role})
+	}
+	data, err := json.Marshal(members)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(c.projectDir(org), localMembersFile), data, 0644)
+}
+
+func (c *LocalAdminClient) writeWebhooks(org, name string, hooks []*localWebhook) error {
+	data, err := json.Marshal(hooks)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(c.repoDir(org, name), localWebhooksFile), data, 0644)
+}
+
+// PrepareRepository creates the project and the bare repository, if they do not exist yet, and sets the repository
+// description and labels. Local repositories are accessed directly, so no credentials are returned.
+func (c *LocalAdminClient) PrepareRepository(code *domain.Codeset, listenerURL *string) (*string, *string, error) {
+	if _, err := c.CreateProject(code.Project, "", true); err != nil {
+		return nil, nil, errors.Wrap(err, "Create project failed")
+	}
+
+	dir := c.repoDir(code.Project, code.Name)
+	if !isDir(dir) {
+		c.logger.Printf("Creating repository '%s' under '%s'...", code.Name, code.Project)
+		repo, err := git.PlainInit(dir, true)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Failed to create repository")
+		}
+		head := plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName(defaultBranch))
+		if err = repo.Storer.SetReference(head); err != nil {
+			return nil, nil, errors.Wrap(err, "Failed to set repository default branch")
+		}
+	} else {
+		c.logger.Printf("Repository '%s' already exists under '%s'", code.Name, code.Project)
+	}
+
+	err := ioutil.WriteFile(filepath.Join(dir, localDescriptionFile), []byte(code.Description+"\n"), 0644)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to set repository description")
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, localLabelsFile), []byte(strings.Join(code.Labels, "\n")), 0644)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to set repository labels")
+	}
+	code.URL = "file://" + dir
+
+	_, err = c.CreateRepoWebhook(code.Project, code.Name, listenerURL)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Creating webhook failed")
+	}
+	return nil, nil, nil
+}
+
+// CreateRepoWebhook records a webhook for the repository, wired to the listenerURL
+func (c *LocalAdminClient) CreateRepoWebhook(org, name string, listenerURL *string) (*int64, error) {
+	if listenerURL == nil {
+		c.logger.Printf("Webhook listener URL not provided, skipping creation")
+		return nil, nil
+	}
+	if !isDir(c.repoDir(org, name)) {
+		return nil, errRepoNotFound
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	hooks, err := c.readWebhooks(org, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list webhooks")
+	}
+	var id int64
+	for _, hook := range hooks {
+		if hook.URL == *listenerURL {
+			c.logger.Printf("Webhook for '%s' already exists", name)
+			return &hook.ID, nil
+		}
+		if hook.ID > id {
+			id = hook.ID
+		}
+	}
+
+	c.logger.Printf("Creating Webhook for '%s' under '%s'...", name, org)
+	hook := &localWebhook{ID: id + 1, URL: *listenerURL}
+	if err = c.writeWebhooks(org, name, append(hooks, hook)); err != nil {
+		return nil, errors.Wrap(err, "Failed to create webhook")
+	}
+	return &hook.ID, nil
+}
+
+// DeleteRepoWebhook deletes a webhook recorded for the repository
+func (c *LocalAdminClient) DeleteRepoWebhook(org, name string, hookID *int64) error {
+	c.logger.Printf("Deleting Webhook for %q under %q...", name, org)
+	c.Lock()
+	defer c.Unlock()
+	hooks, err := c.readWebhooks(org, name)
+	if err != nil {
+		return errors.Wrap(err, "Failed to list webhooks")
+	}
+	for i, hook := range hooks {
+		if hook.ID == *hookID {
+			if err = c.writeWebhooks(org, name, append(hooks[:i], hooks[i+1:]...)); err != nil {
+				return errors.Wrap(err, "Failed to delete webhook")
+			}
+			return nil
+		}
+	}
+	c.logger.Printf("Webhook not found, skipping deletion")
+	return nil
+}
+
+// GetRepoWebhooks returns the webhooks recorded for the repository
+func (c *LocalAdminClient) GetRepoWebhooks(org, name string) ([]*domain.CodesetWebhook, error) {
+	if !isDir(c.repoDir(org, name)) {
+		return nil, errRepoNotFound
+	}
+	c.Lock()
+	defer c.Unlock()
+	hooks, err := c.readWebhooks(org, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list webhooks")
+	}
+	result := make([]*domain.CodesetWebhook, 0, len(hooks))
+	for _, hook := range hooks {
+		result = append(result, &domain.CodesetWebhook{ID: hook.ID, URL: hook.URL})
+	}
+	return result, nil
+}
+
+// GetRepositories retrieves all repositories, can be filtered by project and label
+func (c *LocalAdminClient) GetRepositories(org, label *string) ([]*domain.Codeset, error) {
+	var orgs []string
+	if org == nil {
+		projects, err := c.GetProjects()
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			orgs = append(orgs, p.Name)
+		}
+	} else {
+		orgs = append(orgs, *org)
+	}
+
+	codesets := []*domain.Codeset{}
+	for _, o := range orgs {
+		entries, err := ioutil.ReadDir(c.projectDir(o))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, errors.Wrap(err, "Failed to list project repos")
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() || !strings.HasSuffix(entry.Name(), localRepoSuffix) {
+				continue
+			}
+			codeset := c.readCodeset(o, strings.TrimSuffix(entry.Name(), localRepoSuffix))
+			if label != nil && !util.StringInSlice(*label, codeset.Labels) {
+				continue
+			}
+			codesets = append(codesets, codeset)
+		}
+	}
+	return codesets, nil
+}
+
+// GetRepository retrieves information about the repository
+func (c *LocalAdminClient) GetRepository(org, name string) (*domain.Codeset, error) {
+	if !isDir(c.repoDir(org, name)) {
+		return nil, errRepoNotFound
+	}
+	return c.readCodeset(org, name), nil
+}
+
+// DeleteRepository deletes a repository
+func (c *LocalAdminClient) DeleteRepository(org, name string) error {
+	c.logger.Printf("Going to delete repo %s for org '%s'...", name, org)
+	dir := c.repoDir(org, name)
+	if !isDir(dir) {
+		c.logger.Printf("Repo does not exist, no need to delete")
+		return nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return errors.Wrap(err, "Failed to delete repository")
+	}
+	return nil
+}
+
+// PushCredentials returns the credentials used by FuseML to push contents to the codeset repositories. The local
+// repositories are accessed directly, without any credentials.
+func (c *LocalAdminClient) PushCredentials() (string, string) {
+	return "", ""
+}
+
+// AddRepositoryLabels adds labels to the labels of a repository
+func (c *LocalAdminClient) AddRepositoryLabels(org, name string, labels []string) error {
+	if !isDir(c.repoDir(org, name)) {
+		return errRepoNotFound
+	}
+	codeset := c.readCodeset(org, name)
+	for _, label := range labels {
+		if !util.StringInSlice(label, codeset.Labels) {
+			codeset.Labels = append(codeset.Labels, label)
+		}
+	}
+	err := ioutil.WriteFile(filepath.Join(c.repoDir(org, name), localLabelsFile), []byte(strings.Join(codeset.Labels, "\n")), 0644)
+	if err != nil {
+		return errors.Wrap(err, "Failed to set repository labels")
+	}
+	return nil
+}
+
+// GetRepositoryRevisions retrieves the branches, tags and the most recent commits of a repository
+func (c *LocalAdminClient) GetRepositoryRevisions(org, name string, commits int) (*domain.CodesetRevisions, error) {
+	if !isDir(c.repoDir(org, name)) {
+		return nil, errRepoNotFound
+	}
+	repo, err := git.PlainOpen(c.repoDir(org, name))
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open repository")
+	}
+	revisions := domain.CodesetRevisions{}
+
+	branches, err := repo.Branches()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list repository branches")
+	}
+	err = branches.ForEach(func(ref *plumbing.Reference) error {
+		revisions.Branches = append(revisions.Branches, &domain.CodesetRef{Name: ref.Name().Short(), Commit: ref.Hash().String()})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list repository branches")
+	}
+
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list repository tags")
+	}
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		hash := ref.Hash()
+		// annotated tags point to a tag object, instead of the commit
+		if tag, err := repo.TagObject(hash); err == nil {
+			if commit, err := tag.Commit(); err == nil {
+				hash = commit.Hash
+			}
+		}
+		revisions.Tags = append(revisions.Tags, &domain.CodesetRef{Name: ref.Name().Short(), Commit: hash.String()})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list repository tags")
+	}
+	sort.Slice(revisions.Branches, func(i, j int) bool { return revisions.Branches[i].Name < revisions.Branches[j].Name })
+	sort.Slice(revisions.Tags, func(i, j int) bool { return revisions.Tags[i].Name < revisions.Tags[j].Name })
+
+	if commits <= 0 {
+		return &revisions, nil
+	}
+	head, err := repo.Head()
+	if err != nil {
+		// nothing was pushed to the repository yet
+		if err == plumbing.ErrReferenceNotFound {
+			return &revisions, nil
+		}
+		return nil, errors.Wrap(err, "Failed to read repository head")
+	}
+	commitIter, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list repository commits")
+	}
+	err = commitIter.ForEach(func(commit *object.Commit) error {
+		if len(revisions.Commits) >= commits {
+			return storer.ErrStop
+		}
+		revisions.Commits = append(revisions.Commits, &domain.CodesetCommit{
+			ID:      commit.Hash.String(),
+			Author:  commit.Author.Name,
+			Message: commit.Message,
+			Time:    commit.Author.When,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list repository commits")
+	}
+	return &revisions, nil
+}
+
+// repositoryTree opens a repository and returns its file tree at the given revision (HEAD if ref is empty)
+func (c *LocalAdminClient) repositoryTree(org, name, ref string) (*git.Repository, *object.Tree, error) {
+	if !isDir(c.repoDir(org, name)) {
+		return nil, nil, errRepoNotFound
+	}
+	repo, err := git.PlainOpen(c.repoDir(org, name))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to open repository")
+	}
+	if ref == "" {
+		ref = string(plumbing.HEAD)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, nil, domain.ErrCodesetFileNotFound
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to read repository commit")
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to read repository tree")
+	}
+	return repo, tree, nil
+}
+
+// localFile converts a repository tree entry into a codeset file
+func localFile(repo *git.Repository, filePath string, entry *object.TreeEntry) *domain.CodesetFile {
+	file := domain.CodesetFile{Path: filePath, Type: domain.CodesetFileTypeFile}
+	switch entry.Mode {
+	case filemode.Dir:
+		file.Type = domain.CodesetFileTypeDir
+	case filemode.Submodule:
+		file.Type = domain.CodesetFileTypeSubmodule
+	case filemode.Symlink:
+		file.Type = domain.CodesetFileTypeSymlink
+	default:
+		if blob, err := repo.BlobObject(entry.Hash); err == nil {
+			file.Size = blob.Size
+		}
+	}
+	return &file
+}
+
+// GetRepositoryFiles lists the entries of a repository directory at the given revision (HEAD if ref is empty).
+// If path points to a file, only the file entry is returned
+func (c *LocalAdminClient) GetRepositoryFiles(org, name, ref, filePath string) ([]*domain.CodesetFile, error) {
+	repo, tree, err := c.repositoryTree(org, name, ref)
+	if err != nil {
+		return nil, err
+	}
+	filePath = strings.Trim(filePath, "/")
+	if filePath != "" {
+		entry, err := tree.FindEntry(filePath)
+		if err != nil {
+			return nil, domain.ErrCodesetFileNotFound
+		}
+		if entry.Mode != filemode.Dir {
+			return []*domain.CodesetFile{localFile(repo, filePath, entry)}, nil
+		}
+		if tree, err = tree.Tree(filePath); err != nil {
+			return nil, errors.Wrap(err, "Failed to read repository tree")
+		}
+	}
+
+	files := make([]*domain.CodesetFile, 0, len(tree.Entries))
+	for i := range tree.Entries {
+		files = append(files, localFile(repo, path.Join(filePath, tree.Entries[i].Name), &tree.Entries[i]))
+	}
+	return files, nil
+}
+
+// GetRepositoryFileContents returns the contents of a repository file at the given revision (HEAD if ref
+// is empty)
+func (c *LocalAdminClient) GetRepositoryFileContents(org, name, ref, filePath string) ([]byte, error) {
+	repo, tree, err := c.repositoryTree(org, name, ref)
+	if err != nil {
+		return nil, err
+	}
+	filePath = strings.Trim(filePath, "/")
+	if filePath == "" {
+		return nil, domain.ErrCodesetNotAFile
+	}
+	entry, err := tree.FindEntry(filePath)
+	if err != nil {
+		return nil, domain.ErrCodesetFileNotFound
+	}
+	if !entry.Mode.IsFile() || entry.Mode == filemode.Symlink {
+		return nil, domain.ErrCodesetNotAFile
+	}
+	blob, err := repo.BlobObject(entry.Hash)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read repository file")
+	}
+	reader, err := blob.Reader()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read repository file")
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+// GetProjects retrieves all projects
+func (c *LocalAdminClient) GetProjects() ([]*domain.Project, error) {
+	entries, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list projects")
+	}
+	var projects []*domain.Project
+	for _, entry := range entries {
+		if entry.IsDir() {
+			project, err := c.GetProject(entry.Name())
+			if err != nil {
+				return nil, err
+			}
+			projects = append(projects, project)
+		}
+	}
+	return projects, nil
+}
+
+// GetProject retrieves a project by its name
+func (c *LocalAdminClient) GetProject(name string) (*domain.Project, error) {
+	dir := c.projectDir(name)
+	if !isDir(dir) {
+		return nil, errProjectNotFound
+	}
+	users, err := c.readMembers(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list project members")
+	}
+	return &domain.Project{Name: name, Description: readFile(filepath.Join(dir, localDescriptionFile)), Users: users}, nil
+}
+
+// CreateProject creates a project directory.
+// If ignoreExisting argument is true, the call will not fail when a project with same name already exists.
+func (c *LocalAdminClient) CreateProject(name, desc string, ignoreExisting bool) (*domain.Project, error) {
+	dir := c.projectDir(name)
+	if isDir(dir) {
+		if ignoreExisting {
+			return nil, nil
+		}
+		return nil, domain.ErrProjectExists
+	}
+
+	c.logger.Printf("Creating project %s....", name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "Failed to create project")
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, localDescriptionFile), []byte(desc+"\n"), 0644); err != nil {
+		return nil, errors.Wrap(err, "Failed to set project description")
+	}
+	return &domain.Project{Name: name, Description: desc}, nil
+}
+
+// UpdateProject changes the description of a project
+func (c *LocalAdminClient) UpdateProject(org, desc string) error {
+	dir := c.projectDir(org)
+	if !isDir(dir) {
+		return errProjectNotFound
+	}
+	c.logger.Printf("Updating project %s....", org)
+	if err := ioutil.WriteFile(filepath.Join(dir, localDescriptionFile), []byte(desc+"\n"), 0644); err != nil {
+		return errors.Wrap(err, "Failed to set project description")
+	}
+	return nil
+}
+
+// DeleteProject deletes a project, which must not have any repositories
+func (c *LocalAdminClient) DeleteProject(org string) error {
+	c.logger.Printf("Deleting project %s....", org)
+	repos, err := c.GetRepositories(&org, nil)
+	if err != nil {
+		return err
+	}
+	if len(repos) > 0 {
+		return errProjectNotEmpty
+	}
+	if err = os.RemoveAll(c.projectDir(org)); err != nil {
+		return errors.Wrap(err, "Failed deleting project")
+	}
+	return nil
+}
+
+// AddProjectMember records a user as a member of a project, with the given role. Local repositories are accessed
+// directly, so no credentials are generated.
+func (c *LocalAdminClient) AddProjectMember(org string, user *domain.User) (*string, error) {
+	if !isDir(c.projectDir(org)) {
+		return nil, errProjectNotFound
+	}
+	c.Lock()
+	defer c.Unlock()
+	members, err := c.readMembers(org)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to list project members")
+	}
+	found := false
+	for i, m := range members {
+		if m.Name == user.Name {
+			members[i], found = user, true
+		}
+	}
+	if !found {
+		members = append(members, user)
+	}
+	c.logger.Printf("Adding user %s to project %s as %s....", user.Name, org, user.Role)
+	if err = c.writeMembers(org, members); err != nil {
+		return nil, errors.Wrap(err, "Failed to add project member")
+	}
+	return nil, nil
+}
+
+// RemoveProjectMember removes a user from the members of a project
+func (c *LocalAdminClient) RemoveProjectMember(org, name string) error {
+	if !isDir(c.projectDir(org)) {
+		return errProjectNotFound
+	}
+	c.Lock()
+	defer c.Unlock()
+	members, err := c.readMembers(org)
+	if err != nil {
+		return errors.Wrap(err, "Failed to list project members")
+	}
+	for i, m := range members {
+		if m.Name == name {
+			c.logger.Printf("Removing user %s from project %s....", name, org)
+			if err = c.writeMembers(org, append(members[:i], members[i+1:]...)); err != nil {
+				return errors.Wrap(err, "Failed to remove project member")
+			}
+			return nil
+		}
+	}
+	return domain.ErrProjectMemberNotFound
+}
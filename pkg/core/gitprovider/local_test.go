@@ -0,0 +1,259 @@
+package gitprovider
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+// pushCommit commits a file to a new working repository, tags the commit and pushes it to the repository at url
+func pushCommit(t *testing.T, url, message, tag string) string {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, "MLproject"), []byte("name: mlflow-app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = wt.Add("MLproject"); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := wt.Commit(message, &git.CommitOptions{Author: &object.Signature{Name: "Jane", When: time.Now()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = repo.CreateTag(tag, hash, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{url}}); err != nil {
+		t.Fatal(err)
+	}
+	err = repo.Push(&git.PushOptions{RefSpecs: []config.RefSpec{"refs/heads/master:refs/heads/main", "refs/tags/*:refs/tags/*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash.String()
+}
+
+func TestLocalRepositories(t *testing.T) {
+	client, err := NewLocalAdminClient(testLogger(), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codeset := &domain.Codeset{Name: "mlflow-app", Project: "workspace", Description: "MLflow app", Labels: []string{"mlflow", "demo"}}
+	user, pass, err := client.PrepareRepository(codeset, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if user != nil || pass != nil {
+		t.Errorf("Expected no credentials for a local repository")
+	}
+	if _, _, err = client.PrepareRepository(&domain.Codeset{Name: "docs", Project: "workspace"}, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got, err := client.GetRepository("workspace", "mlflow-app")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff(codeset, got); d != "" {
+		t.Errorf("Unexpected codeset (-want +got): %s", d)
+	}
+	if _, err = client.GetRepository("workspace", "unknown"); err != errRepoNotFound {
+		t.Errorf("Expected %q error, got %v", errRepoNotFound, err)
+	}
+
+	codesets, err := client.GetRepositories(nil, util.RefString("demo"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff([]*domain.Codeset{codeset}, codesets); d != "" {
+		t.Errorf("Unexpected codesets (-want +got): %s", d)
+	}
+
+	revisions, err := client.GetRepositoryRevisions("workspace", "mlflow-app", 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff(&domain.CodesetRevisions{}, revisions); d != "" {
+		t.Errorf("Expected no revisions for an empty repository (-want +got): %s", d)
+	}
+
+	commit := pushCommit(t, codeset.URL, "Initial commit", "v1.0")
+	revisions, err = client.GetRepositoryRevisions("workspace", "mlflow-app", 10)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff([]*domain.CodesetRef{{Name: "main", Commit: commit}}, revisions.Branches); d != "" {
+		t.Errorf("Unexpected branches (-want +got): %s", d)
+	}
+	if d := cmp.Diff([]*domain.CodesetRef{{Name: "v1.0", Commit: commit}}, revisions.Tags); d != "" {
+		t.Errorf("Unexpected tags (-want +got): %s", d)
+	}
+	if len(revisions.Commits) != 1 || revisions.Commits[0].ID != commit || revisions.Commits[0].Author != "Jane" {
+		t.Errorf("Unexpected commits: %v", revisions.Commits)
+	}
+
+	if err = client.DeleteProject("workspace"); err != errProjectNotEmpty {
+		t.Errorf("Expected %q error, got %v", errProjectNotEmpty, err)
+	}
+	for _, name := range []string{"mlflow-app", "docs", "unknown"} {
+		if err = client.DeleteRepository("workspace", name); err != nil {
+			t.Fatalf("Unexpected error deleting repository %q: %v", name, err)
+		}
+	}
+	if err = client.DeleteProject("workspace"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err = client.GetProject("workspace"); err != errProjectNotFound {
+		t.Errorf("Expected %q error, got %v", errProjectNotFound, err)
+	}
+}
+
+func TestLocalRepositoryFiles(t *testing.T) {
+	client, err := NewLocalAdminClient(testLogger(), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	codeset := &domain.Codeset{Name: "mlflow-app", Project: "workspace"}
+	if _, _, err = client.PrepareRepository(codeset, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err = client.GetRepositoryFiles("workspace", "mlflow-app", "", ""); err != domain.ErrCodesetFileNotFound {
+		t.Errorf("Expected %q error for an empty repository, got %v", domain.ErrCodesetFileNotFound, err)
+	}
+	pushCommit(t, codeset.URL, "Initial commit", "v1.0")
+
+	want := []*domain.CodesetFile{{Path: "MLproject", Type: domain.CodesetFileTypeFile, Size: 17}}
+	for _, tc := range []struct{ ref, path string }{{"", "/"}, {"v1.0", ""}, {"main", "MLproject"}} {
+		files, err := client.GetRepositoryFiles("workspace", "mlflow-app", tc.ref, tc.path)
+		if err != nil {
+			t.Fatalf("Unexpected error listing %q at %q: %v", tc.path, tc.ref, err)
+		}
+		if d := cmp.Diff(want, files); d != "" {
+			t.Errorf("Unexpected files for %q at %q (-want +got): %s", tc.path, tc.ref, d)
+		}
+	}
+	for _, tc := range []struct{ ref, path string }{{"", "unknown"}, {"unknown", ""}} {
+		if _, err = client.GetRepositoryFiles("workspace", "mlflow-app", tc.ref, tc.path); err != domain.ErrCodesetFileNotFound {
+			t.Errorf("Expected %q error for %q at %q, got %v", domain.ErrCodesetFileNotFound, tc.path, tc.ref, err)
+		}
+	}
+
+	contents, err := client.GetRepositoryFileContents("workspace", "mlflow-app", "v1.0", "MLproject")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(contents) != "name: mlflow-app\n" {
+		t.Errorf("Unexpected file contents: %q", contents)
+	}
+	if _, err = client.GetRepositoryFileContents("workspace", "mlflow-app", "", "/"); err != domain.ErrCodesetNotAFile {
+		t.Errorf("Expected %q error, got %v", domain.ErrCodesetNotAFile, err)
+	}
+	if _, err = client.GetRepositoryFileContents("workspace", "mlflow-app", "", "conda.yaml"); err != domain.ErrCodesetFileNotFound {
+		t.Errorf("Expected %q error, got %v", domain.ErrCodesetFileNotFound, err)
+	}
+}
+
+func TestLocalWebhooks(t *testing.T) {
+	client, err := NewLocalAdminClient(testLogger(), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = client.PrepareRepository(&domain.Codeset{Name: "mlflow-app", Project: "workspace"}, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	first, second := "http://el-first.test:8080", "http://el-second.test:8080"
+	for i, url := range []string{first, second, first} {
+		hookID, err := client.CreateRepoWebhook("workspace", "mlflow-app", &url)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if want := []int64{1, 2, 1}[i]; *hookID != want {
+			t.Errorf("Expected webhook %d for %q, got %d", want, url, *hookID)
+		}
+	}
+
+	hookID := int64(1)
+	if err = client.DeleteRepoWebhook("workspace", "mlflow-app", &hookID); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	hooks, err := client.readWebhooks("workspace", "mlflow-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]*localWebhook{{ID: 2, URL: second}}, hooks); d != "" {
+		t.Errorf("Unexpected webhooks (-want +got): %s", d)
+	}
+
+	if _, err = client.CreateRepoWebhook("workspace", "unknown", &first); err != errRepoNotFound {
+		t.Errorf("Expected %q error, got %v", errRepoNotFound, err)
+	}
+	if _, err = client.CreateProject("workspace", "", false); err != domain.ErrProjectExists {
+		t.Errorf("Expected %q error, got %v", domain.ErrProjectExists, err)
+	}
+}
+
+func TestLocalProjectMembers(t *testing.T) {
+	client, err := NewLocalAdminClient(testLogger(), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := &domain.User{Name: "alice", Email: "alice@example.com", Role: domain.ProjectRoleDeveloper}
+	if _, err = client.AddProjectMember("workspace", alice); err != errProjectNotFound {
+		t.Errorf("Expected %q error, got %v", errProjectNotFound, err)
+	}
+	if _, err = client.CreateProject("workspace", "", false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	bob := &domain.User{Name: "bob", Email: "bob@example.com", Role: domain.ProjectRoleOwner}
+	for _, user := range []*domain.User{alice, bob, {Name: "alice", Email: "alice@example.com", Role: domain.ProjectRoleViewer}} {
+		password, err := client.AddProjectMember("workspace", user)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if password != nil {
+			t.Errorf("Expected no credentials for a local project member")
+		}
+	}
+	project, err := client.GetProject("workspace")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []*domain.User{{Name: "alice", Email: "alice@example.com", Role: domain.ProjectRoleViewer}, bob}
+	if d := cmp.Diff(want, project.Users); d != "" {
+		t.Errorf("Unexpected project members (-want +got): %s", d)
+	}
+
+	if err = client.RemoveProjectMember("workspace", "alice"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err = client.RemoveProjectMember("workspace", "alice"); err != domain.ErrProjectMemberNotFound {
+		t.Errorf("Expected %q error, got %v", domain.ErrProjectMemberNotFound, err)
+	}
+	projects, err := client.GetProjects()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff([]*domain.Project{{Name: "workspace", Users: []*domain.User{bob}}}, projects); d != "" {
+		t.Errorf("Unexpected projects (-want +got): %s", d)
+	}
+}
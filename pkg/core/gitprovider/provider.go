@@ -0,0 +1,168 @@
+// Package gitprovider implements the GitAdminClient interface for git providers other than the FuseML
+// built-in gitea: external providers (GitHub and GitLab), where codesets are references to existing
+// repositories, and a local provider that keeps the codesets as bare git repositories on disk.
+package gitprovider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+const (
+	// codesetTopic is the topic added to the external repositories registered as codesets. Only the repositories
+	// with this topic are listed as codesets.
+	codesetTopic = "fuseml-codeset"
+	// defaultBranch is the default branch of the repositories created by the local provider
+	defaultBranch = "main"
+	// maxPageSize is the maximum number of items requested in a page from the external providers
+	maxPageSize = 100
+	// requestTimeout is the timeout of the requests sent to the external providers
+	requestTimeout = 30 * time.Second
+)
+
+const (
+	errRepoNotFound             = gitProviderErr("Repository by that name not found")
+	errRepoNotRegistered        = gitProviderErr("Repository is not registered as a codeset")
+	errProjectNotFound          = gitProviderErr("Project by that name not found")
+	errProjectNotEmpty          = gitProviderErr("Project has still codesets assigned. Delete them first")
+	errProjectDeleteUnsupported = gitProviderErr("Projects are not managed by FuseML for this git provider, delete them from the git provider")
+	errMembersUnsupported       = gitProviderErr("Project members are not managed by FuseML for this git provider, manage them with the git provider")
+	errProjectUpdateUnsupported = gitProviderErr("Project descriptions are not managed by FuseML for this git provider, update them with the git provider")
+)
+
+type gitProviderErr string
+
+func (e gitProviderErr) Error() string {
+	return string(e)
+}
+
+// apiError is the error returned when an external provider API responds with an error status
+type apiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// isNotFound checks whether the error is an API error for a resource that does not exist
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*apiError)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// restClient is a minimal client for the REST APIs of the external git providers
+type restClient struct {
+	httpClient *http.Client
+	baseURL    string
+	header     http.Header
+}
+
+func newRestClient(baseURL string, header http.Header) *restClient {
+	return &restClient{
+		httpClient: &http.Client{Timeout: requestTimeout},
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		header:     header,
+	}
+}
+
+// do sends a request to the API path. The body, when set, is encoded as JSON and the response is decoded as
+// JSON into result, when set.
+func (c *restClient) do(method, path string, body, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &apiError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, result)
+}
+
+// pagePath returns the API path for a page of a list of items
+func pagePath(path string, page, pageSize int) string {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%sper_page=%d&page=%d", path, sep, pageSize, page)
+}
+
+// escapePath escapes the segments of a repository file path, to be used as part of an API path
+func escapePath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// refQuery returns the query string selecting a repository revision, or an empty string for the default branch
+func refQuery(ref string) string {
+	if ref == "" {
+		return ""
+	}
+	return "?ref=" + url.QueryEscape(ref)
+}
+
+// codesetLabels returns the codeset labels from the topics of a repository, which are all the repository
+// topics except the codeset topic
+func codesetLabels(topics []string) []string {
+	labels := []string{}
+	for _, topic := range topics {
+		if topic != codesetTopic {
+			labels = append(labels, topic)
+		}
+	}
+	return labels
+}
+
+// registeredTopics returns the repository topics with the codeset topic and labels added
+func registeredTopics(topics, labels []string) []string {
+	result := append([]string{}, topics...)
+	for _, topic := range append([]string{codesetTopic}, labels...) {
+		if !util.StringInSlice(topic, result) {
+			result = append(result, topic)
+		}
+	}
+	return result
+}
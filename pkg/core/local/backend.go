@@ -0,0 +1,552 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ghodss/yaml"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/fuseml/fuseml-core/pkg/core/variables"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+// WorkflowBackend implements the FuseML WorkflowBackend interface executing the workflows on the local
+// machine, without requiring a kubernetes cluster. Workflows and their runs are kept in memory, and the
+// workflow steps are executed by a StepExecutor, one at a time, in the order they are defined.
+// As there is nothing listening for codeset events, workflow runs are only created when a workflow is
+// assigned to a codeset or when explicitly requested.
+type WorkflowBackend struct {
+	sync.RWMutex
+	namespace string
+	workDir   string
+	logger    *log.Logger
+	executor  StepExecutor
+	workflows map[string]*domain.Workflow
+	// credentials holds the workflows with the extension credentials bound for a project and user
+	credentials map[credentialsScope]*domain.Workflow
+	runs        map[string]*workflowRun
+	runCount    int
+}
+
+// credentialsScope identifies the workflow runs that use the same extension credentials
+type credentialsScope struct {
+	workflow string
+	project  string
+	user     string
+}
+
+// NewWorkflowBackend initializes the local backend, using workDir to hold the files used by the workflow runs
+func NewWorkflowBackend(logger *log.Logger, namespace, workDir string, executor StepExecutor) (*WorkflowBackend, error) {
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return nil, fmt.Errorf("error initializing local workflow backend: %w", err)
+	}
+	return &WorkflowBackend{
+		namespace:   namespace,
+		workDir:     workDir,
+		logger:      logger,
+		executor:    executor,
+		workflows:   make(map[string]*domain.Workflow),
+		credentials: make(map[credentialsScope]*domain.Workflow),
+		runs:        make(map[string]*workflowRun),
+	}, nil
+}
+
+// CreateWorkflow keeps the FuseML workflow so that it can be executed
+func (b *WorkflowBackend) CreateWorkflow(ctx context.Context, workflow *domain.Workflow) error {
+	b.Lock()
+	defer b.Unlock()
+	b.workflows[workflow.Name] = workflow
+	return nil
+}
+
+// UpdateWorkflow replaces the FuseML workflow executed by the workflow runs created from now on
+func (b *WorkflowBackend) UpdateWorkflow(ctx context.Context, workflow *domain.Workflow) error {
+	b.Lock()
+	defer b.Unlock()
+	if _, ok := b.workflows[workflow.Name]; !ok {
+		return domain.ErrWorkflowNotFound
+	}
+	b.workflows[workflow.Name] = workflow
+	return nil
+}
+
+// RenderWorkflow returns the workflow as YAML. The local backend does not generate any objects for a workflow,
+// it keeps the workflow definition, which is executed as it is
+func (b *WorkflowBackend) RenderWorkflow(ctx context.Context, workflow *domain.Workflow) (string, error) {
+	out, err := yaml.Marshal(workflow)
+	if err != nil {
+		return "", fmt.Errorf("error rendering workflow %q: %w", workflow.Name, err)
+	}
+	return string(out), nil
+}
+
+// DeleteWorkflow deletes the FuseML workflow from the backend
+func (b *WorkflowBackend) DeleteWorkflow(ctx context.Context, workflowName string) error {
+	b.Lock()
+	defer b.Unlock()
+	delete(b.workflows, workflowName)
+	for scope := range b.credentials {
+		if scope.workflow == workflowName {
+			delete(b.credentials, scope)
+		}
+	}
+	return nil
+}
+
+// CreateWorkflowRun starts executing the workflow steps with the codeset as input, returning the name
+// of the workflow run. The steps use the extension credentials set for the codeset project and run user.
+func (b *WorkflowBackend) CreateWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset,
+	options *domain.WorkflowRunOptions) (string, error) {
+	b.Lock()
+	defer b.Unlock()
+	workflow, ok := b.workflows[workflowName]
+	if !ok {
+		return "", domain.ErrWorkflowNotFound
+	}
+	if options == nil {
+		options = &domain.WorkflowRunOptions{}
+	}
+	if bound, ok := b.credentials[credentialsScope{workflowName, codeset.Project, options.User}]; ok {
+		workflow = bound
+	}
+
+	b.runCount++
+	name := fmt.Sprintf("%s%s-%s-%d", runNamePrefix, codeset.Project, codeset.Name, b.runCount)
+	run := newWorkflowRun(name, workflow, codeset, options)
+	runCtx, cancel := context.WithCancel(context.Background())
+	run.cancel = cancel
+	b.runs[name] = run
+
+	go b.execute(runCtx, run)
+	return name, nil
+}
+
+// SetWorkflowCredentials keeps the workflow with the extension credentials bound for a project and user, to be
+// executed by the workflow runs created in their context
+func (b *WorkflowBackend) SetWorkflowCredentials(ctx context.Context, workflow *domain.Workflow, project, user string) error {
+	b.Lock()
+	defer b.Unlock()
+	if _, ok := b.workflows[workflow.Name]; !ok {
+		return domain.ErrWorkflowNotFound
+	}
+	b.credentials[credentialsScope{workflow.Name, project, user}] = workflow
+	return nil
+}
+
+// GetWorkflowRuns returns the runs of the workflow matching the filter
+func (b *WorkflowBackend) GetWorkflowRuns(ctx context.Context, workflow *domain.Workflow,
+	filter *domain.WorkflowRunFilter) ([]*domain.WorkflowRun, error) {
+	b.RLock()
+	defer b.RUnlock()
+	workflowRuns := []*domain.WorkflowRun{}
+	for _, run := range b.runs {
+		if run.workflow.Name != workflow.Name ||
+			(filter.CodesetName != "" && run.codeset.Name != filter.CodesetName) ||
+			(filter.CodesetProject != "" && run.codeset.Project != filter.CodesetProject) ||
+			(filter.CodesetVersion != "" && run.codesetVersion() != filter.CodesetVersion) {
+			continue
+		}
+		wfr := run.snapshot()
+		if len(filter.Status) > 0 && !util.StringInSlice(wfr.Status, filter.Status) {
+			continue
+		}
+		workflowRuns = append(workflowRuns, wfr)
+	}
+	sort.Slice(workflowRuns, func(i, j int) bool {
+		return workflowRuns[i].StartTime.Before(workflowRuns[j].StartTime)
+	})
+	return workflowRuns, nil
+}
+
+// GetWorkflowRun returns the workflow run with the specified name, including the status of its steps
+func (b *WorkflowBackend) GetWorkflowRun(ctx context.Context, runName string) (*domain.WorkflowRun, error) {
+	run, err := b.getRun(runName)
+	if err != nil {
+		return nil, err
+	}
+	return run.snapshot(), nil
+}
+
+// StreamWorkflowRunLogs calls handler for every line logged by the steps of the workflow run
+func (b *WorkflowBackend) StreamWorkflowRunLogs(ctx context.Context, runName string, options *domain.WorkflowRunLogOptions,
+	handler domain.WorkflowRunLogHandler) error {
+	if options == nil {
+		options = &domain.WorkflowRunLogOptions{}
+	}
+
+	run, err := b.getRun(runName)
+	if err != nil {
+		return err
+	}
+	if options.Step != "" && !workflowHasStep(run.workflow, options.Step) {
+		return domain.ErrWorkflowRunStepNotFound
+	}
+
+	lines, err := run.streamLogs(ctx, options.Step, options.Follow, handler)
+	if err != nil {
+		return err
+	}
+	if lines == 0 {
+		return domain.ErrWorkflowRunLogsNotFound
+	}
+	return nil
+}
+
+// CancelWorkflowRun stops the execution of the workflow run
+func (b *WorkflowBackend) CancelWorkflowRun(ctx context.Context, runName string) error {
+	run, err := b.getRun(runName)
+	if err != nil {
+		return err
+	}
+	if run.isDone() {
+		return domain.ErrWorkflowRunCompleted
+	}
+	run.cancel()
+	return nil
+}
+
+// RetryWorkflowRun creates a new workflow run with the same workflow, codeset and options as a completed
+// workflow run, except for the extensions the workflow steps are bound to, returning the name of the new
+// workflow run
+func (b *WorkflowBackend) RetryWorkflowRun(ctx context.Context, runName string,
+	extensions []*domain.WorkflowRunExtension) (string, error) {
+	run, err := b.getRun(runName)
+	if err != nil {
+		return "", err
+	}
+	if !run.isDone() {
+		return "", domain.ErrWorkflowRunNotCompleted
+	}
+	options := *run.options
+	options.Extensions = extensions
+	return b.CreateWorkflowRun(ctx, run.workflow.Name, run.codeset, &options)
+}
+
+// DeleteWorkflowRun stops the workflow run if it is still running and deletes it, together with its files
+func (b *WorkflowBackend) DeleteWorkflowRun(ctx context.Context, runName string) error {
+	b.Lock()
+	run, ok := b.runs[runName]
+	delete(b.runs, runName)
+	b.Unlock()
+	if !ok {
+		return domain.ErrWorkflowRunNotFound
+	}
+	run.cancel()
+	run.waitDone()
+	return os.RemoveAll(filepath.Join(b.workDir, runName))
+}
+
+// ApplyProjectQuota is only supported for quotas without resource caps, as the local backend does not limit the
+// resources used by the steps it executes
+func (b *WorkflowBackend) ApplyProjectQuota(ctx context.Context, project string, quota *domain.ProjectQuota) error {
+	if quota.HasResourceCaps() {
+		return domain.ErrProjectQuotaUnsupported
+	}
+	return nil
+}
+
+// CreateWorkflowListener returns a listener for the workflow. The local backend does not listen for codeset
+// events, so the listener has no URL.
+func (b *WorkflowBackend) CreateWorkflowListener(ctx context.Context, workflowName string, timeout time.Duration) (*domain.WorkflowListener, error) {
+	return b.GetWorkflowListener(ctx, workflowName)
+}
+
+// DeleteWorkflowListener does nothing as the local backend does not listen for codeset events
+func (b *WorkflowBackend) DeleteWorkflowListener(ctx context.Context, workflowName string) error {
+	return nil
+}
+
+// UpdateWorkflowListener does nothing as the local backend does not listen for codeset events
+func (b *WorkflowBackend) UpdateWorkflowListener(ctx context.Context, workflowName string, assignments []*domain.CodesetAssignment) error {
+	return nil
+}
+
+// GetWorkflowListener returns the listener for the workflow
+func (b *WorkflowBackend) GetWorkflowListener(ctx context.Context, workflowName string) (*domain.WorkflowListener, error) {
+	b.RLock()
+	defer b.RUnlock()
+	if _, ok := b.workflows[workflowName]; !ok {
+		return nil, domain.ErrWorkflowNotFound
+	}
+	return &domain.WorkflowListener{Name: workflowName, Available: true}, nil
+}
+
+// GetWorkflowListeners returns no listeners, as the local backend does not listen for codeset events
+func (b *WorkflowBackend) GetWorkflowListeners(ctx context.Context) ([]*domain.WorkflowListener, error) {
+	return []*domain.WorkflowListener{}, nil
+}
+
+func (b *WorkflowBackend) getRun(runName string) (*workflowRun, error) {
+	b.RLock()
+	defer b.RUnlock()
+	run, ok := b.runs[runName]
+	if !ok {
+		return nil, domain.ErrWorkflowRunNotFound
+	}
+	return run, nil
+}
+
+// execute runs the workflow steps, setting the workflow run status when they complete
+func (b *WorkflowBackend) execute(ctx context.Context, run *workflowRun) {
+	status := statusSucceeded
+	if err := b.executeSteps(ctx, run); err != nil {
+		if ctx.Err() != nil {
+			status = statusCancelled
+		} else {
+			b.logger.Printf("workflow run %q failed: %s", run.run.Name, err)
+			status = statusFailed
+		}
+	}
+	run.complete(status)
+}
+
+func (b *WorkflowBackend) executeSteps(ctx context.Context, run *workflowRun) error {
+	workspace := filepath.Join(b.workDir, run.run.Name)
+	if err := os.RemoveAll(workspace); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(workspace, 0755); err != nil {
+		return err
+	}
+
+	codesetVersion := run.codesetVersion()
+	codesetDir := filepath.Join(workspace, codesetDirName)
+
+	// process the FuseML workflow inputs, cloning the codeset and adding references to the
+	// values used by the run
+	resolver := variables.NewResolver(nil)
+	inputs := []*domain.WorkflowRunInput{}
+	for _, input := range run.workflow.Inputs {
+		if input.Type == domain.WorkflowIOTypeCodeset {
+			resolver.AddReference(fmt.Sprintf("inputs.%s.name", input.Name), run.codeset.Name)
+			resolver.AddReference(fmt.Sprintf("inputs.%s.version", input.Name), codesetVersion)
+			resolver.AddReference(fmt.Sprintf("inputs.%s.project", input.Name), run.codeset.Project)
+			inputs = append(inputs, &domain.WorkflowRunInput{Input: input,
+				Value: fmt.Sprintf("%s:%s", run.codeset.URL, codesetVersion)})
+			continue
+		}
+		value, ok := run.options.Inputs[input.Name]
+		if !ok {
+			value = input.Default
+		}
+		resolver.AddReference(fmt.Sprintf("inputs.%s", input.Name), value)
+		inputs = append(inputs, &domain.WorkflowRunInput{Input: input, Value: value})
+	}
+	run.setInputs(inputs)
+
+	if workflowHasCodesetInput(run.workflow) {
+		err := run.runStep(ctx, cloneStepName, "", func(out io.Writer) (map[string]string, error) {
+			return nil, cloneCodeset(ctx, run.codeset.URL, codesetVersion, codesetDir, out)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, step := range run.workflow.Steps {
+		if err := b.executeStep(ctx, run, step, resolver, workspace, codesetDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// executeStep runs a workflow step, adding references to its outputs to the resolver
+func (b *WorkflowBackend) executeStep(ctx context.Context, run *workflowRun, step *domain.WorkflowStep,
+	resolver *variables.Resolver, workspace, codesetDir string) error {
+	env := map[string]string{
+		envVarPrefix + "WORKFLOW_NAMESPACE": b.namespace,
+		envVarPrefix + "WORKFLOW_NAME":      run.workflow.Name,
+	}
+	// let the steps know the codeset being processed, e.g. to record it when registering applications
+	if workflowHasCodesetInput(run.workflow) {
+		env[envVarPrefix+"CODESET_PROJECT"] = run.codeset.Project
+		env[envVarPrefix+"CODESET_NAME"] = run.codeset.Name
+	}
+	stepResolver := resolver.Clone()
+	for _, extension := range step.Extensions {
+		addExtensionReferences(extension, stepResolver, env)
+	}
+
+	workDir := workspace
+	codesetPath := ""
+	for _, input := range step.Inputs {
+		if input.Codeset != nil {
+			workDir = codesetDir
+			codesetPath = input.Codeset.Path
+		} else {
+			env[fmt.Sprintf("%s%s", inputsVarPrefix, strings.ToUpper(input.Name))] = resolver.Resolve(input.Value)
+		}
+	}
+
+	// make the name of each step output available for the step so it knows where to write its result. Image
+	// outputs are built from the codeset once the step completes, the same way the builder does on tekton after
+	// the step image prepared the dockerfile
+	results := []*domain.WorkflowStepOutput{}
+	images := []*domain.WorkflowStepOutput{}
+	for _, output := range step.Outputs {
+		if output.Image != nil {
+			images = append(images, output)
+			continue
+		}
+		results = append(results, output)
+		env[stepOutputVarName] = output.Name
+		env[fmt.Sprintf("%s_%s", stepOutputVarName, strings.ToUpper(output.Name))] = output.Name
+	}
+	for _, stepEnv := range step.Env {
+		// step environment variables override those set elsewhere
+		env[stepEnv.Name] = stepResolver.Resolve(stepEnv.Value)
+	}
+
+	resultsDir := filepath.Join(workspace, resultsDirName, step.Name)
+	if err := os.MkdirAll(resultsDir, 0755); err != nil {
+		return err
+	}
+	image := stepResolver.Resolve(step.Image)
+	var values map[string]string
+	err := run.runStep(ctx, step.Name, image, func(out io.Writer) (map[string]string, error) {
+		err := b.executor.Run(ctx, &Step{
+			ID:          fmt.Sprintf("%s-%s", run.run.Name, step.Name),
+			Name:        step.Name,
+			Image:       image,
+			Entrypoint:  step.Entrypoint,
+			Args:        step.Args,
+			Env:         env,
+			WorkDir:     workDir,
+			CodesetPath: codesetPath,
+			ResultsDir:  resultsDir,
+		}, out)
+		if err != nil {
+			return nil, err
+		}
+		if values, err = readResults(resultsDir, results); err != nil {
+			return values, err
+		}
+		// the result of an image output is the name of the built image
+		for _, output := range images {
+			built := resolver.Resolve(output.Image.Name)
+			dockerfile := defaultDockerfile
+			if output.Image.Dockerfile != "" {
+				// the dockerfile path includes the codeset path, which is only valid from inside the step
+				dockerfile = strings.TrimPrefix(resolver.Resolve(output.Image.Dockerfile), codesetPath+"/")
+			}
+			err = b.executor.BuildImage(ctx, built, filepath.Join(workDir, dockerfile), workDir, out)
+			if err != nil {
+				return values, fmt.Errorf("error building image %q: %w", built, err)
+			}
+			values[output.Name] = built
+		}
+		return values, nil
+	})
+	if err != nil {
+		return fmt.Errorf("error running step %q: %w", step.Name, err)
+	}
+
+	for name, value := range values {
+		resolver.AddReference(fmt.Sprintf("steps.%s.outputs.%s", step.Name, name), value)
+		for _, output := range run.workflow.Outputs {
+			if output.Name == name {
+				run.setOutput(output, value)
+			}
+		}
+	}
+	return nil
+}
+
+// addExtensionReferences adds references to the extension fields and sets its configuration as environment variables
+func addExtensionReferences(extension *domain.WorkflowStepExtension, resolver *variables.Resolver, env map[string]string) {
+	access := extension.ExtensionAccess
+	resolver.AddReference(fmt.Sprintf("extensions.%s.product", extension.Name), access.Extension.Product)
+	resolver.AddReference(fmt.Sprintf("extensions.%s.zone", extension.Name), access.Extension.Zone)
+	resolver.AddReference(fmt.Sprintf("extensions.%s.version", extension.Name), access.Extension.Version)
+	resolver.AddReference(fmt.Sprintf("extensions.%s.service_resource", extension.Name), access.Service.Resource)
+	resolver.AddReference(fmt.Sprintf("extensions.%s.service_category", extension.Name), access.Service.Category)
+	resolver.AddReference(fmt.Sprintf("extensions.%s.url", extension.Name), access.Endpoint.URL)
+	configurations := []map[string]string{access.Extension.Configuration, access.Service.Configuration,
+		access.Endpoint.Configuration}
+	if access.Credentials != nil {
+		configurations = append(configurations, access.Credentials.Configuration)
+	}
+	for _, configuration := range configurations {
+		for k, v := range configuration {
+			env[k] = v
+			resolver.AddReference(fmt.Sprintf("extensions.%s.cfg.%s", extension.Name, k), v)
+		}
+	}
+}
+
+// readResults reads the results written by a step, one file per step output
+func readResults(resultsDir string, outputs []*domain.WorkflowStepOutput) (map[string]string, error) {
+	results := map[string]string{}
+	for _, output := range outputs {
+		value, err := ioutil.ReadFile(filepath.Join(resultsDir, output.Name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		results[output.Name] = strings.TrimSpace(string(value))
+	}
+	return results, nil
+}
+
+// cloneCodeset clones the codeset repository into dir, checking out the specified revision
+// (branch, tag or commit)
+func cloneCodeset(ctx context.Context, url, revision, dir string, out io.Writer) error {
+	fmt.Fprintf(out, "cloning %s\n", url)
+	repo, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{URL: url, Progress: out})
+	if err != nil {
+		return fmt.Errorf("error cloning codeset: %w", err)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		// branches other than the default one are only available as remote branches
+		hash, err = repo.ResolveRevision(plumbing.Revision(fmt.Sprintf("%s/%s", git.DefaultRemoteName, revision)))
+		if err != nil {
+			return fmt.Errorf("error resolving codeset revision %q: %w", revision, err)
+		}
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{Hash: *hash}); err != nil {
+		return fmt.Errorf("error checking out codeset revision %q: %w", revision, err)
+	}
+	fmt.Fprintf(out, "checked out %s (%s)\n", revision, hash)
+	return nil
+}
+
+func workflowHasCodesetInput(workflow *domain.Workflow) bool {
+	for _, input := range workflow.Inputs {
+		if input.Type == domain.WorkflowIOTypeCodeset {
+			return true
+		}
+	}
+	return false
+}
+
+func workflowHasStep(workflow *domain.Workflow, name string) bool {
+	if name == cloneStepName && workflowHasCodesetInput(workflow) {
+		return true
+	}
+	for _, step := range workflow.Steps {
+		if step.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package local
+
+const (
+	runNamePrefix         = "fuseml-"
+	codesetDirName        = "codeset"
+	resultsDirName        = "results"
+	cloneStepName         = "clone"
+	defaultCodesetVersion = "main"
+	defaultDockerfile     = "Dockerfile"
+	containerResultsDir   = "/tekton/results"
+	stepOutputVarName     = "TASK_RESULT"
+	inputsVarPrefix       = "FUSEML_"
+	envVarPrefix          = "FUSEML_ENV_"
+	resultsDirVarName     = envVarPrefix + "RESULTS_DIR"
+	stepDefaultCmd        = "run"
+
+	statusRunning   = "Running"
+	statusSucceeded = "Succeeded"
+	statusFailed    = "Failed"
+	statusCancelled = "Cancelled"
+)
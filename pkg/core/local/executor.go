@@ -0,0 +1,153 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+)
+
+// Step describes a workflow step, with all its variables resolved, to be executed by a StepExecutor.
+type Step struct {
+	// ID uniquely identifies the execution of the step.
+	ID string
+	// Name is the name of the step.
+	Name string
+	// Image is the image used to run the step.
+	Image string
+	// Entrypoint is the entrypoint used to run the step, the default command is used when not set.
+	Entrypoint string
+	// Args is the list of arguments passed to the entrypoint.
+	Args []string
+	// Env is the map of environment variables set for the step.
+	Env map[string]string
+	// WorkDir is the local directory the step runs from, holding the codeset when the step has one as input.
+	WorkDir string
+	// CodesetPath is the path where the step expects the codeset to be available, empty when the step has
+	// no codeset as input.
+	CodesetPath string
+	// ResultsDir is the local directory where the step writes its results, one file per result.
+	ResultsDir string
+}
+
+// StepExecutor executes FuseML workflow steps on the local machine.
+type StepExecutor interface {
+	// Run executes a workflow step, writing everything it logs to out.
+	Run(ctx context.Context, step *Step, out io.Writer) error
+	// BuildImage builds the image from the dockerfile, using contextDir as the build context.
+	BuildImage(ctx context.Context, image, dockerfile, contextDir string, out io.Writer) error
+}
+
+// ContainerExecutor executes workflow steps as containers using a container runtime CLI
+// compatible with docker (e.g. docker or podman).
+type ContainerExecutor struct {
+	runtime string
+}
+
+// NewContainerExecutor initializes a ContainerExecutor that uses the specified container runtime.
+func NewContainerExecutor(runtime string) *ContainerExecutor {
+	return &ContainerExecutor{runtime}
+}
+
+// Run executes the workflow step in a container from the step image, running the step entrypoint or,
+// when the step has none, the step default command. The codeset is mounted at the path expected by the
+// step and the results directory is mounted where the FuseML images write their results to.
+func (e *ContainerExecutor) Run(ctx context.Context, step *Step, out io.Writer) error {
+	args := []string{"run", "--rm", "--name", step.ID,
+		"-v", fmt.Sprintf("%s:%s", step.ResultsDir, containerResultsDir),
+		"-e", fmt.Sprintf("%s=%s", resultsDirVarName, containerResultsDir)}
+	if step.CodesetPath != "" {
+		args = append(args, "-v", fmt.Sprintf("%s:%s", step.WorkDir, step.CodesetPath), "-w", step.CodesetPath)
+	}
+	for _, env := range toEnvList(step.Env) {
+		args = append(args, "-e", env)
+	}
+	if step.Entrypoint != "" {
+		args = append(args, "--entrypoint", step.Entrypoint, step.Image)
+	} else {
+		args = append(args, step.Image, stepDefaultCmd)
+	}
+	args = append(args, step.Args...)
+	return e.run(ctx, step.ID, out, args...)
+}
+
+// BuildImage builds the image using the container runtime.
+func (e *ContainerExecutor) BuildImage(ctx context.Context, image, dockerfile, contextDir string, out io.Writer) error {
+	return e.run(ctx, "", out, "build", "-t", image, "-f", dockerfile, contextDir)
+}
+
+// run executes the container runtime CLI with the specified arguments. When the context is cancelled
+// the container (if any) is removed, as killing the CLI process does not stop it.
+func (e *ContainerExecutor) run(ctx context.Context, container string, out io.Writer, args ...string) error {
+	cmd := exec.Command(e.runtime, args...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("error starting %s: %w", e.runtime, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if container != "" {
+			exec.Command(e.runtime, "rm", "-f", container).Run()
+		}
+		cmd.Process.Kill()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// ProcessExecutor executes workflow steps as processes on the local machine. As a process is not able
+// to run a container image, the step image is interpreted as the shell command executed by the step.
+// The step writes its results to the directory set by the FUSEML_ENV_RESULTS_DIR environment variable.
+type ProcessExecutor struct {
+	shell string
+}
+
+// NewProcessExecutor initializes a ProcessExecutor that runs the steps using the specified shell.
+func NewProcessExecutor(shell string) *ProcessExecutor {
+	return &ProcessExecutor{shell}
+}
+
+// Run executes the workflow step command as a process. When the step has an entrypoint, the entrypoint
+// is executed with the step arguments instead.
+func (e *ProcessExecutor) Run(ctx context.Context, step *Step, out io.Writer) error {
+	cmd := exec.CommandContext(ctx, e.shell, "-c", step.Image)
+	if step.Entrypoint != "" {
+		cmd = exec.CommandContext(ctx, step.Entrypoint, step.Args...)
+	}
+	cmd.Dir = step.WorkDir
+	cmd.Env = append(os.Environ(), toEnvList(step.Env)...)
+	cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", resultsDirVarName, step.ResultsDir))
+	cmd.Stdout = out
+	cmd.Stderr = out
+	err := cmd.Run()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// BuildImage does not build images, it only logs that the build was skipped.
+func (e *ProcessExecutor) BuildImage(ctx context.Context, image, dockerfile, contextDir string, out io.Writer) error {
+	_, err := fmt.Fprintf(out, "skipping build of image %q: images are not built when running steps as processes\n", image)
+	return err
+}
+
+func toEnvList(env map[string]string) []string {
+	envList := make([]string, 0, len(env))
+	for k, v := range env {
+		envList = append(envList, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(envList)
+	return envList
+}
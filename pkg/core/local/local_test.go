@@ -0,0 +1,438 @@
+package local
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+const runTimeout = 10 * time.Second
+
+func newTestBackend(t *testing.T) *WorkflowBackend {
+	logger := log.New(os.Stdout, "[local-test] ", log.Ltime)
+	b, err := NewWorkflowBackend(logger, "fuseml-workloads", t.TempDir(), NewProcessExecutor("sh"))
+	if err != nil {
+		t.Fatalf("failed to initialize local backend: %s", err)
+	}
+	return b
+}
+
+// createCodeset creates a git repository with a 'main' branch, holding a greeting file, and a 'dev'
+// branch that changes its content
+func createCodeset(t *testing.T) *domain.Codeset {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("failed to create git repository: %s", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get git worktree: %s", err)
+	}
+	commit := func(content string) plumbing.Hash {
+		if err := ioutil.WriteFile(filepath.Join(dir, "greeting"), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write file: %s", err)
+		}
+		if _, err := wt.Add("greeting"); err != nil {
+			t.Fatalf("failed to add file: %s", err)
+		}
+		hash, err := wt.Commit(content, &git.CommitOptions{Author: &object.Signature{Name: "fuseml", When: time.Now()}})
+		if err != nil {
+			t.Fatalf("failed to commit: %s", err)
+		}
+		return hash
+	}
+	main := commit("hello")
+	dev := commit("hi")
+	for branch, hash := range map[string]plumbing.Hash{"main": main, "dev": dev} {
+		err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(branch), hash))
+		if err != nil {
+			t.Fatalf("failed to create branch %q: %s", branch, err)
+		}
+	}
+	return &domain.Codeset{Name: "greeter", Project: "test", URL: dir}
+}
+
+func testWorkflow() *domain.Workflow {
+	return &domain.Workflow{
+		Name: "greet",
+		Inputs: []*domain.WorkflowInput{
+			{Name: "codeset", Type: domain.WorkflowIOTypeCodeset},
+			{Name: "name", Type: domain.WorkflowIOTypeString, Default: "world"},
+		},
+		Outputs: []*domain.WorkflowOutput{{Name: "message", Type: domain.WorkflowIOTypeString}},
+		Steps: []*domain.WorkflowStep{
+			{
+				Name:  "builder",
+				Image: `echo "preparing $FUSEML_ENV_WORKFLOW_NAME"`,
+				Inputs: []*domain.WorkflowStepInput{
+					{Name: "source", Codeset: &domain.WorkflowStepInputCodeset{Name: "{{ inputs.codeset }}", Path: "/project"}},
+				},
+				Outputs: []*domain.WorkflowStepOutput{
+					{Name: "image", Image: &domain.WorkflowStepOutputImage{
+						Dockerfile: "/project/Dockerfile",
+						Name:       "registry/{{ inputs.codeset.project }}/{{ inputs.codeset.name }}:{{ inputs.codeset.version }}",
+					}},
+				},
+			},
+			{
+				Name:  "greeting",
+				Image: `echo "using $FUSEML_IMAGE"; printf "$(cat greeting), $FUSEML_NAME" > $FUSEML_ENV_RESULTS_DIR/$TASK_RESULT`,
+				Inputs: []*domain.WorkflowStepInput{
+					{Name: "source", Codeset: &domain.WorkflowStepInputCodeset{Name: "{{ inputs.codeset }}", Path: "/project"}},
+					{Name: "name", Value: "{{ inputs.name }}"},
+					{Name: "image", Value: "{{ steps.builder.outputs.image }}"},
+				},
+				Outputs: []*domain.WorkflowStepOutput{{Name: "greeting"}},
+			},
+			{
+				Name:    "message",
+				Image:   `echo "$GREETING"; echo "$GREETING!" > $FUSEML_ENV_RESULTS_DIR/$TASK_RESULT`,
+				Env:     []*domain.WorkflowStepEnv{{Name: "GREETING", Value: "{{ steps.greeting.outputs.greeting }}"}},
+				Outputs: []*domain.WorkflowStepOutput{{Name: "message"}},
+			},
+		},
+	}
+}
+
+func waitForRun(t *testing.T, b *WorkflowBackend, name string) *domain.WorkflowRun {
+	deadline := time.Now().Add(runTimeout)
+	for time.Now().Before(deadline) {
+		run, err := b.GetWorkflowRun(context.Background(), name)
+		if err != nil {
+			t.Fatalf("failed to get workflow run %q: %s", name, err)
+		}
+		if !run.CompletionTime.IsZero() {
+			return run
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for workflow run %q to complete", name)
+	return nil
+}
+
+func runWorkflow(t *testing.T, b *WorkflowBackend, wf *domain.Workflow, codeset *domain.Codeset,
+	options *domain.WorkflowRunOptions) string {
+	ctx := context.Background()
+	if err := b.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("failed to create workflow: %s", err)
+	}
+	name, err := b.CreateWorkflowRun(ctx, wf.Name, codeset, options)
+	if err != nil {
+		t.Fatalf("failed to create workflow run: %s", err)
+	}
+	return name
+}
+
+func TestCreateWorkflowRun(t *testing.T) {
+	b := newTestBackend(t)
+	codeset := createCodeset(t)
+
+	t.Run("defaults", func(t *testing.T) {
+		run := waitForRun(t, b, runWorkflow(t, b, testWorkflow(), codeset, nil))
+		if run.Status != statusSucceeded {
+			t.Fatalf("expected status %q, got %q", statusSucceeded, run.Status)
+		}
+		wantSteps := []string{cloneStepName, "builder", "greeting", "message"}
+		if len(run.Steps) != len(wantSteps) {
+			t.Fatalf("expected %d steps, got %d", len(wantSteps), len(run.Steps))
+		}
+		for i, step := range run.Steps {
+			if step.Name != wantSteps[i] || step.Status != statusSucceeded {
+				t.Errorf("expected step %q to be %q, got %q: %q", wantSteps[i], statusSucceeded, step.Name, step.Status)
+			}
+		}
+		if image := run.Steps[1].Results["image"]; image != "registry/test/greeter:main" {
+			t.Errorf("unexpected builder image: %q", image)
+		}
+		if greeting := run.Steps[2].Results["greeting"]; greeting != "hello, world" {
+			t.Errorf("unexpected greeting result: %q", greeting)
+		}
+		if len(run.Inputs) != 2 || run.Inputs[0].Value != codeset.URL+":main" || run.Inputs[1].Value != "world" {
+			t.Errorf("unexpected run inputs: %+v, %+v", run.Inputs[0], run.Inputs[1])
+		}
+		if len(run.Outputs) != 1 || run.Outputs[0].Value != "hello, world!" {
+			t.Errorf("unexpected run outputs: %+v", run.Outputs)
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		extensions := []*domain.WorkflowRunExtension{{StepName: "greeting", Name: "store", ExtensionID: "store-01",
+			ServiceID: "s3", EndpointURL: "http://store"}}
+		options := &domain.WorkflowRunOptions{CodesetRevision: "dev", Inputs: map[string]string{"name": "fuseml"},
+			Extensions: extensions}
+		run := waitForRun(t, b, runWorkflow(t, b, testWorkflow(), codeset, options))
+		if run.Status != statusSucceeded {
+			t.Fatalf("expected status %q, got %q", statusSucceeded, run.Status)
+		}
+		if image := run.Steps[1].Results["image"]; image != "registry/test/greeter:dev" {
+			t.Errorf("unexpected builder image: %q", image)
+		}
+		if len(run.Outputs) != 1 || run.Outputs[0].Value != "hi, fuseml!" {
+			t.Errorf("unexpected run outputs: %+v", run.Outputs)
+		}
+		if len(run.Extensions) != 1 || *run.Extensions[0] != *extensions[0] {
+			t.Errorf("unexpected run extensions: %+v", run.Extensions)
+		}
+	})
+
+	t.Run("multiple outputs", func(t *testing.T) {
+		wf := testWorkflow()
+		wf.Name = "greet-outputs"
+		wf.Steps[0].Outputs = append(wf.Steps[0].Outputs, &domain.WorkflowStepOutput{Name: "tag"})
+		wf.Steps[0].Image = `echo "$TASK_RESULT_TAG" > $FUSEML_ENV_RESULTS_DIR/$TASK_RESULT_TAG`
+		wf.Steps[2].Image = `echo "$GREETING!" > $FUSEML_ENV_RESULTS_DIR/$TASK_RESULT_MESSAGE; ` +
+			`echo "$GREETING?" > $FUSEML_ENV_RESULTS_DIR/$TASK_RESULT_QUESTION`
+		wf.Steps[2].Outputs = append(wf.Steps[2].Outputs, &domain.WorkflowStepOutput{Name: "question"})
+		run := waitForRun(t, b, runWorkflow(t, b, wf, codeset, nil))
+		if run.Status != statusSucceeded {
+			t.Fatalf("expected status %q, got %q", statusSucceeded, run.Status)
+		}
+		want := map[string]string{"image": "registry/test/greeter:main", "tag": "tag"}
+		if !reflect.DeepEqual(run.Steps[1].Results, want) {
+			t.Errorf("expected builder results %v, got %v", want, run.Steps[1].Results)
+		}
+		want = map[string]string{"message": "hello, world!", "question": "hello, world?"}
+		if !reflect.DeepEqual(run.Steps[3].Results, want) {
+			t.Errorf("expected message results %v, got %v", want, run.Steps[3].Results)
+		}
+	})
+
+	t.Run("codeset environment", func(t *testing.T) {
+		wf := testWorkflow()
+		wf.Name = "greet-codeset"
+		wf.Steps[2].Image = `echo "$FUSEML_ENV_CODESET_PROJECT/$FUSEML_ENV_CODESET_NAME" > $FUSEML_ENV_RESULTS_DIR/$TASK_RESULT`
+		run := waitForRun(t, b, runWorkflow(t, b, wf, codeset, nil))
+		if len(run.Outputs) != 1 || run.Outputs[0].Value != "test/greeter" {
+			t.Errorf("unexpected run outputs: %+v", run.Outputs)
+		}
+	})
+
+	t.Run("failed step", func(t *testing.T) {
+		wf := testWorkflow()
+		wf.Steps[2].Image = "exit 1"
+		run := waitForRun(t, b, runWorkflow(t, b, wf, codeset, nil))
+		if run.Status != statusFailed {
+			t.Fatalf("expected status %q, got %q", statusFailed, run.Status)
+		}
+		if len(run.Steps) != 4 || run.Steps[3].Status != statusFailed {
+			t.Errorf("expected step %q to have failed", wf.Steps[2].Name)
+		}
+	})
+
+	t.Run("credentials", func(t *testing.T) {
+		ctx := context.Background()
+		wf := testWorkflow()
+		wf.Name = "greet-credentials"
+		wf.Steps = wf.Steps[:1]
+		wf.Steps = append(wf.Steps, &domain.WorkflowStep{
+			Name:       "message",
+			Image:      `printf "$TOKEN" > $FUSEML_ENV_RESULTS_DIR/$TASK_RESULT`,
+			Outputs:    []*domain.WorkflowStepOutput{{Name: "message"}},
+			Extensions: []*domain.WorkflowStepExtension{{Name: "store", ExtensionAccess: &domain.ExtensionAccessDescriptor{}}},
+		})
+		if err := b.CreateWorkflow(ctx, wf); err != nil {
+			t.Fatalf("failed to create workflow: %s", err)
+		}
+		withToken := func(token string) *domain.Workflow {
+			bound := wf.WithoutCredentials()
+			bound.Steps[1].Extensions[0].ExtensionAccess = &domain.ExtensionAccessDescriptor{
+				Credentials: &domain.ExtensionServiceCredentials{ID: token, Configuration: map[string]string{"TOKEN": token}}}
+			return bound
+		}
+		for user, token := range map[string]string{"": "project", "alice": "alice"} {
+			if err := b.SetWorkflowCredentials(ctx, withToken(token), codeset.Project, user); err != nil {
+				t.Fatalf("failed to set workflow credentials: %s", err)
+			}
+		}
+
+		for user, want := range map[string]string{"": "project", "alice": "alice", "bob": ""} {
+			name, err := b.CreateWorkflowRun(ctx, wf.Name, codeset, &domain.WorkflowRunOptions{User: user})
+			if err != nil {
+				t.Fatalf("failed to create workflow run: %s", err)
+			}
+			run := waitForRun(t, b, name)
+			if len(run.Outputs) != 1 || run.Outputs[0].Value != want {
+				t.Errorf("unexpected run outputs for user %q: %+v", user, run.Outputs)
+			}
+		}
+	})
+
+	t.Run("workflow not found", func(t *testing.T) {
+		_, err := b.CreateWorkflowRun(context.Background(), "missing", codeset, nil)
+		if err != domain.ErrWorkflowNotFound {
+			t.Errorf("expected error %q, got %v", domain.ErrWorkflowNotFound, err)
+		}
+	})
+}
+
+func TestUpdateWorkflow(t *testing.T) {
+	b := newTestBackend(t)
+	codeset := createCodeset(t)
+	ctx := context.Background()
+
+	wf := testWorkflow()
+	if err := b.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("failed to create workflow: %s", err)
+	}
+	updated := testWorkflow()
+	updated.Steps[2].Env[0].Value = "bye"
+	if err := b.UpdateWorkflow(ctx, updated); err != nil {
+		t.Fatalf("failed to update workflow: %s", err)
+	}
+	name, err := b.CreateWorkflowRun(ctx, wf.Name, codeset, nil)
+	if err != nil {
+		t.Fatalf("failed to create workflow run: %s", err)
+	}
+	run := waitForRun(t, b, name)
+	if len(run.Outputs) != 1 || run.Outputs[0].Value != "bye!" {
+		t.Errorf("unexpected run outputs: %+v", run.Outputs)
+	}
+
+	missing := testWorkflow()
+	missing.Name = "missing"
+	if err := b.UpdateWorkflow(ctx, missing); err != domain.ErrWorkflowNotFound {
+		t.Errorf("expected error %q, got %v", domain.ErrWorkflowNotFound, err)
+	}
+}
+
+func TestGetWorkflowRuns(t *testing.T) {
+	b := newTestBackend(t)
+	codeset := createCodeset(t)
+	wf := testWorkflow()
+	waitForRun(t, b, runWorkflow(t, b, wf, codeset, nil))
+	wf.Steps[2].Image = "exit 1"
+	waitForRun(t, b, runWorkflow(t, b, wf, codeset, nil))
+
+	tests := []struct {
+		name   string
+		filter *domain.WorkflowRunFilter
+		want   int
+	}{
+		{"all", &domain.WorkflowRunFilter{}, 2},
+		{"status", &domain.WorkflowRunFilter{Status: []string{statusFailed}}, 1},
+		{"codeset", &domain.WorkflowRunFilter{CodesetName: codeset.Name, CodesetProject: codeset.Project}, 2},
+		{"other codeset", &domain.WorkflowRunFilter{CodesetName: "other"}, 0},
+		{"codeset version", &domain.WorkflowRunFilter{CodesetVersion: defaultCodesetVersion}, 2},
+		{"other codeset version", &domain.WorkflowRunFilter{CodesetVersion: "v1.0"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs, err := b.GetWorkflowRuns(context.Background(), wf, tt.filter)
+			if err != nil {
+				t.Fatalf("failed to get workflow runs: %s", err)
+			}
+			if len(runs) != tt.want {
+				t.Errorf("expected %d runs, got %d", tt.want, len(runs))
+			}
+		})
+	}
+}
+
+func TestStreamWorkflowRunLogs(t *testing.T) {
+	b := newTestBackend(t)
+	codeset := createCodeset(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		options *domain.WorkflowRunLogOptions
+		want    []string
+		wantErr error
+	}{
+		{"step", &domain.WorkflowRunLogOptions{Step: "message"}, []string{"hello, world"}, nil},
+		{"follow", &domain.WorkflowRunLogOptions{Step: "greeting", Follow: true},
+			[]string{"using registry/test/greeter:main"}, nil},
+		{"step not found", &domain.WorkflowRunLogOptions{Step: "missing"}, nil, domain.ErrWorkflowRunStepNotFound},
+		{"image build", &domain.WorkflowRunLogOptions{Step: "builder"}, []string{"preparing greet",
+			`skipping build of image "registry/test/greeter:main": images are not built when running steps as processes`}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := runWorkflow(t, b, testWorkflow(), codeset, nil)
+			if !tt.options.Follow {
+				waitForRun(t, b, name)
+			}
+			got := []string{}
+			err := b.StreamWorkflowRunLogs(ctx, name, tt.options, func(l *domain.WorkflowRunLog) error {
+				if l.Step != tt.options.Step {
+					t.Errorf("expected logs from step %q, got %q", tt.options.Step, l.Step)
+				}
+				got = append(got, l.Line)
+				return nil
+			})
+			if err != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.want != nil && (len(got) != len(tt.want) || got[0] != tt.want[0]) {
+				t.Errorf("expected logs %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("no options", func(t *testing.T) {
+		name := runWorkflow(t, b, testWorkflow(), codeset, nil)
+		waitForRun(t, b, name)
+		lines := 0
+		err := b.StreamWorkflowRunLogs(ctx, name, nil, func(l *domain.WorkflowRunLog) error {
+			lines++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if lines == 0 {
+			t.Errorf("expected logs from all the steps, got none")
+		}
+	})
+}
+
+func TestWorkflowRunOperations(t *testing.T) {
+	b := newTestBackend(t)
+	codeset := createCodeset(t)
+	ctx := context.Background()
+	wf := testWorkflow()
+	wf.Steps[2].Image = "sleep 30"
+	name := runWorkflow(t, b, wf, codeset, nil)
+
+	if _, err := b.RetryWorkflowRun(ctx, name, nil); err != domain.ErrWorkflowRunNotCompleted {
+		t.Errorf("expected error %q, got %v", domain.ErrWorkflowRunNotCompleted, err)
+	}
+	if err := b.CancelWorkflowRun(ctx, name); err != nil {
+		t.Fatalf("failed to cancel workflow run: %s", err)
+	}
+	if run := waitForRun(t, b, name); run.Status != statusCancelled {
+		t.Errorf("expected status %q, got %q", statusCancelled, run.Status)
+	}
+	if err := b.CancelWorkflowRun(ctx, name); err != domain.ErrWorkflowRunCompleted {
+		t.Errorf("expected error %q, got %v", domain.ErrWorkflowRunCompleted, err)
+	}
+
+	retried, err := b.RetryWorkflowRun(ctx, name, nil)
+	if err != nil {
+		t.Fatalf("failed to retry workflow run: %s", err)
+	}
+	if retried == name {
+		t.Errorf("expected retry to create a new workflow run")
+	}
+	if err := b.DeleteWorkflowRun(ctx, retried); err != nil {
+		t.Fatalf("failed to delete workflow run: %s", err)
+	}
+	if _, err := b.GetWorkflowRun(ctx, retried); err != domain.ErrWorkflowRunNotFound {
+		t.Errorf("expected error %q, got %v", domain.ErrWorkflowRunNotFound, err)
+	}
+	if _, err := os.Stat(filepath.Join(b.workDir, retried)); !os.IsNotExist(err) {
+		t.Errorf("expected workflow run files to be deleted")
+	}
+}
@@ -0,0 +1,262 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// workflowRun holds the state of a workflow run executed by the local backend
+type workflowRun struct {
+	mu sync.Mutex
+	// updated is closed (and replaced) every time the run state changes, notifying the
+	// routines following the run logs
+	updated  chan struct{}
+	cancel   context.CancelFunc
+	run      *domain.WorkflowRun
+	workflow *domain.Workflow
+	codeset  *domain.Codeset
+	options  *domain.WorkflowRunOptions
+	logs     map[string][]string
+}
+
+func newWorkflowRun(name string, workflow *domain.Workflow, codeset *domain.Codeset, options *domain.WorkflowRunOptions) *workflowRun {
+	return &workflowRun{
+		updated:  make(chan struct{}),
+		workflow: workflow,
+		codeset:  codeset,
+		options:  options,
+		logs:     make(map[string][]string),
+		run: &domain.WorkflowRun{
+			Name:           name,
+			WorkflowRef:    workflow.Name,
+			CodesetProject: codeset.Project,
+			User:           options.User,
+			StartTime:      time.Now(),
+			Status:         statusRunning,
+			Extensions:     options.Extensions,
+		},
+	}
+}
+
+// notify wakes up the routines waiting for the run state to change, must be called with the lock held
+func (r *workflowRun) notify() {
+	close(r.updated)
+	r.updated = make(chan struct{})
+}
+
+// codesetVersion returns the codeset revision used by the run
+func (r *workflowRun) codesetVersion() string {
+	if r.options.CodesetRevision != "" {
+		return r.options.CodesetRevision
+	}
+	return defaultCodesetVersion
+}
+
+func (r *workflowRun) isDone() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.run.CompletionTime.IsZero()
+}
+
+// waitDone waits for the run to complete
+func (r *workflowRun) waitDone() {
+	for {
+		r.mu.Lock()
+		done := !r.run.CompletionTime.IsZero()
+		updated := r.updated
+		r.mu.Unlock()
+		if done {
+			return
+		}
+		<-updated
+	}
+}
+
+func (r *workflowRun) setInputs(inputs []*domain.WorkflowRunInput) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run.Inputs = inputs
+}
+
+func (r *workflowRun) setOutput(output *domain.WorkflowOutput, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, o := range r.run.Outputs {
+		if o.Output.Name == output.Name {
+			o.Value = value
+			return
+		}
+	}
+	r.run.Outputs = append(r.run.Outputs, &domain.WorkflowRunOutput{Output: output, Value: value})
+}
+
+func (r *workflowRun) complete(status string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run.Status = status
+	r.run.CompletionTime = time.Now()
+	r.notify()
+}
+
+// runStep records the execution of a step, running fn with a writer that collects the step logs
+func (r *workflowRun) runStep(ctx context.Context, name, image string, fn func(out io.Writer) (map[string]string, error)) error {
+	step := &domain.WorkflowRunStep{Name: name, Image: image, Status: statusRunning, StartTime: time.Now()}
+	r.mu.Lock()
+	r.run.Steps = append(r.run.Steps, step)
+	r.notify()
+	r.mu.Unlock()
+
+	out := &logWriter{run: r, step: name}
+	results, err := fn(out)
+	out.flush()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case ctx.Err() != nil:
+		step.Status = statusCancelled
+	case err != nil:
+		step.Status = statusFailed
+	default:
+		step.Status = statusSucceeded
+	}
+	step.Results = results
+	step.CompletionTime = time.Now()
+	r.notify()
+	return err
+}
+
+// snapshot returns a copy of the run that is safe to be used while the run executes
+func (r *workflowRun) snapshot() *domain.WorkflowRun {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	run := *r.run
+	run.Inputs = append([]*domain.WorkflowRunInput{}, r.run.Inputs...)
+	run.Outputs = []*domain.WorkflowRunOutput{}
+	for _, output := range r.run.Outputs {
+		o := *output
+		run.Outputs = append(run.Outputs, &o)
+	}
+	run.Steps = []*domain.WorkflowRunStep{}
+	for _, step := range r.run.Steps {
+		s := *step
+		run.Steps = append(run.Steps, &s)
+	}
+	return &run
+}
+
+// streamLogs calls handler for every line logged by the run steps. When follow is set, it waits for
+// new lines until the run completes.
+func (r *workflowRun) streamLogs(ctx context.Context, step string, follow bool, handler domain.WorkflowRunLogHandler) (int, error) {
+	lines := 0
+	for i := 0; ; i++ {
+		r.mu.Lock()
+		if i >= len(r.run.Steps) {
+			done := !r.run.CompletionTime.IsZero()
+			updated := r.updated
+			r.mu.Unlock()
+			if !follow || done {
+				return lines, nil
+			}
+			if err := wait(ctx, updated); err != nil {
+				return lines, err
+			}
+			i--
+			continue
+		}
+		name := r.run.Steps[i].Name
+		r.mu.Unlock()
+		if step != "" && step != name {
+			continue
+		}
+		n, err := r.streamStepLogs(ctx, name, follow, handler)
+		lines += n
+		if err != nil {
+			return lines, err
+		}
+	}
+}
+
+func (r *workflowRun) streamStepLogs(ctx context.Context, step string, follow bool, handler domain.WorkflowRunLogHandler) (int, error) {
+	for line := 0; ; {
+		r.mu.Lock()
+		logs := r.logs[step][line:]
+		done := r.stepDone(step)
+		updated := r.updated
+		r.mu.Unlock()
+
+		for _, l := range logs {
+			if err := handler(&domain.WorkflowRunLog{Step: step, Container: step, Line: l}); err != nil {
+				return line, err
+			}
+			line++
+		}
+		if !follow || done {
+			return line, nil
+		}
+		if len(logs) == 0 {
+			if err := wait(ctx, updated); err != nil {
+				return line, err
+			}
+		}
+	}
+}
+
+// stepDone returns whether the step completed, must be called with the lock held
+func (r *workflowRun) stepDone(name string) bool {
+	for _, step := range r.run.Steps {
+		if step.Name == name {
+			return !step.CompletionTime.IsZero()
+		}
+	}
+	return false
+}
+
+func wait(ctx context.Context, updated chan struct{}) error {
+	select {
+	case <-updated:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// logWriter collects the lines written by a step into the run logs
+type logWriter struct {
+	run  *workflowRun
+	step string
+	buf  []byte
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.addLine(string(bytes.TrimSuffix(w.buf[:i], []byte("\r"))))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush adds what was written after the last new line to the logs
+func (w *logWriter) flush() {
+	if len(w.buf) > 0 {
+		w.addLine(string(w.buf))
+		w.buf = nil
+	}
+}
+
+func (w *logWriter) addLine(line string) {
+	w.run.mu.Lock()
+	defer w.run.mu.Unlock()
+	w.run.logs[w.step] = append(w.run.logs[w.step], line)
+	w.run.notify()
+}
@@ -10,11 +10,29 @@ import (
 // ExtensionRegistry implements the domain.ExtensionRegistry interface
 type ExtensionRegistry struct {
 	extensionStore domain.ExtensionStore
+	subscribers    []domain.ExtensionSubscriber
 }
 
 // NewExtensionRegistry initializes an extension registry
 func NewExtensionRegistry(extensionStore domain.ExtensionStore) *ExtensionRegistry {
-	return &ExtensionRegistry{extensionStore}
+	return &ExtensionRegistry{extensionStore: extensionStore}
+}
+
+// Subscribe - subscribe to the changes made to the registered extensions. Subscribers are notified after an
+// extension, or one of its services, endpoints or credentials, is added, updated or removed.
+func (registry *ExtensionRegistry) Subscribe(subscriber domain.ExtensionSubscriber) {
+	registry.subscribers = append(registry.subscribers, subscriber)
+}
+
+// notify calls the subscribers when an operation changing an extension succeeds
+func (registry *ExtensionRegistry) notify(ctx context.Context, extensionID string, err error) error {
+	if err != nil {
+		return err
+	}
+	for _, subscriber := range registry.subscribers {
+		subscriber.OnExtensionChanged(ctx, extensionID)
+	}
+	return nil
 }
 
 // RegisterExtension - register a new extension, with all participating services, endpoints and credentials
@@ -24,7 +42,8 @@ func (registry *ExtensionRegistry) RegisterExtension(ctx context.Context, extens
 
 // AddService - add a service to an existing extension
 func (registry *ExtensionRegistry) AddService(ctx context.Context, extensionID string, service *domain.ExtensionService) (*domain.ExtensionService, error) {
-	return registry.extensionStore.AddExtensionService(ctx, extensionID, service)
+	result, err := registry.extensionStore.AddExtensionService(ctx, extensionID, service)
+	return result, registry.notify(ctx, extensionID, err)
 }
 
 // AddEndpoint - add an endpoint to an existing extension service
@@ -33,13 +52,15 @@ func (registry *ExtensionRegistry) AddEndpoint(ctx context.Context, extensionID
 	if endpoint.URL == "" {
 		return nil, domain.NewErrMissingField("endpoint", "URL")
 	}
-	return registry.extensionStore.AddExtensionServiceEndpoint(ctx, extensionID, serviceID, endpoint)
+	result, err := registry.extensionStore.AddExtensionServiceEndpoint(ctx, extensionID, serviceID, endpoint)
+	return result, registry.notify(ctx, extensionID, err)
 }
 
 // AddCredentials - add a set of credentials to an existing extension service
 func (registry *ExtensionRegistry) AddCredentials(ctx context.Context, extensionID string, serviceID string,
 	credentials *domain.ExtensionServiceCredentials) (*domain.ExtensionServiceCredentials, error) {
-	return registry.extensionStore.AddExtensionServiceCredentials(ctx, extensionID, serviceID, credentials)
+	result, err := registry.extensionStore.AddExtensionServiceCredentials(ctx, extensionID, serviceID, credentials)
+	return result, registry.notify(ctx, extensionID, err)
 }
 
 // ListExtensions - list all registered extensions that match the supplied query parameters
@@ -72,7 +93,7 @@ func (registry *ExtensionRegistry) UpdateExtension(ctx context.Context, extensio
 	if extension.ID == "" {
 		return domain.NewErrMissingField("extension", "extension ID")
 	}
-	return registry.extensionStore.UpdateExtension(ctx, extension)
+	return registry.notify(ctx, extension.ID, registry.extensionStore.UpdateExtension(ctx, extension))
 }
 
 // UpdateService - update a service belonging to an extension
@@ -80,7 +101,7 @@ func (registry *ExtensionRegistry) UpdateService(ctx context.Context, extensionI
 	if service.ID == "" {
 		return domain.NewErrMissingField("service", "service ID")
 	}
-	return registry.extensionStore.UpdateExtensionService(ctx, extensionID, service)
+	return registry.notify(ctx, extensionID, registry.extensionStore.UpdateExtensionService(ctx, extensionID, service))
 }
 
 // UpdateEndpoint - update an endpoint belonging to a service
@@ -88,7 +109,7 @@ func (registry *ExtensionRegistry) UpdateEndpoint(ctx context.Context, extension
 	if endpoint.URL == "" {
 		return domain.NewErrMissingField("endpoint", "URL")
 	}
-	return registry.extensionStore.UpdateExtensionServiceEndpoint(ctx, extensionID, serviceID, endpoint)
+	return registry.notify(ctx, extensionID, registry.extensionStore.UpdateExtensionServiceEndpoint(ctx, extensionID, serviceID, endpoint))
 }
 
 // UpdateCredentials - update a set of credentials belonging to a service
@@ -96,27 +117,27 @@ func (registry *ExtensionRegistry) UpdateCredentials(ctx context.Context, extens
 	if credentials.ID == "" {
 		return domain.NewErrMissingField("credentials", "credentials ID")
 	}
-	return registry.extensionStore.UpdateExtensionServiceCredentials(ctx, extensionID, serviceID, credentials)
+	return registry.notify(ctx, extensionID, registry.extensionStore.UpdateExtensionServiceCredentials(ctx, extensionID, serviceID, credentials))
 }
 
 // RemoveExtension - remove an extension from the registry
 func (registry *ExtensionRegistry) RemoveExtension(ctx context.Context, extensionID string) error {
-	return registry.extensionStore.DeleteExtension(ctx, extensionID)
+	return registry.notify(ctx, extensionID, registry.extensionStore.DeleteExtension(ctx, extensionID))
 }
 
 // RemoveService - remove an extension service from the registry
 func (registry *ExtensionRegistry) RemoveService(ctx context.Context, extensionID, serviceID string) error {
-	return registry.extensionStore.DeleteExtensionService(ctx, extensionID, serviceID)
+	return registry.notify(ctx, extensionID, registry.extensionStore.DeleteExtensionService(ctx, extensionID, serviceID))
 }
 
 // RemoveEndpoint - remove an extension endpoint from the registry
 func (registry *ExtensionRegistry) RemoveEndpoint(ctx context.Context, extensionID, serviceID, endpointID string) error {
-	return registry.extensionStore.DeleteExtensionServiceEndpoint(ctx, extensionID, serviceID, endpointID)
+	return registry.notify(ctx, extensionID, registry.extensionStore.DeleteExtensionServiceEndpoint(ctx, extensionID, serviceID, endpointID))
 }
 
 // RemoveCredentials - remove a set of extension credentials from the registry
 func (registry *ExtensionRegistry) RemoveCredentials(ctx context.Context, extensionID, serviceID, credentialsID string) error {
-	return registry.extensionStore.DeleteExtensionServiceCredentials(ctx, extensionID, serviceID, credentialsID)
+	return registry.notify(ctx, extensionID, registry.extensionStore.DeleteExtensionServiceCredentials(ctx, extensionID, serviceID, credentialsID))
 }
 
 type queryResults []*domain.ExtensionAccessDescriptor
@@ -0,0 +1,178 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+const (
+	// DefaultHealthCheckInterval is the default interval between two rounds of extension health checks
+	DefaultHealthCheckInterval = time.Minute
+	// DefaultHealthCheckTimeout is the default maximum duration of an extension endpoint health check
+	DefaultHealthCheckTimeout = 5 * time.Second
+)
+
+// ExtensionHealthProber periodically runs the health checks configured for the extension services against their
+// endpoints, recording the result on the endpoints. The subscribers of the extension registry are notified
+// when an endpoint becomes unhealthy or recovers, so that the workflows using it can be bound again.
+type ExtensionHealthProber struct {
+	logger   *log.Logger
+	registry *ExtensionRegistry
+	interval time.Duration
+	timeout  time.Duration
+	client   *http.Client
+}
+
+// NewExtensionHealthProber initializes an extension health prober running the health checks every interval,
+// each check taking at most timeout, unless the service health check sets its own timeout
+func NewExtensionHealthProber(logger *log.Logger, registry *ExtensionRegistry, interval, timeout time.Duration) *ExtensionHealthProber {
+	return &ExtensionHealthProber{
+		logger:   logger,
+		registry: registry,
+		interval: interval,
+		timeout:  timeout,
+		// the status of the redirect responses is checked as it is
+		client: &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}},
+	}
+}
+
+// Run checks the extension endpoints every interval, until the context is cancelled
+func (p *ExtensionHealthProber) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		p.CheckEndpoints(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckEndpoints runs the health checks once against the endpoints of all the extension services that have
+// a health check configured
+func (p *ExtensionHealthProber) CheckEndpoints(ctx context.Context) {
+	for _, extension := range p.registry.extensionStore.ListExtensions(ctx, nil) {
+		changed := false
+		for _, service := range extension.ListServices() {
+			if service.HealthCheck == nil {
+				continue
+			}
+			for _, endpoint := range service.ListEndpoints() {
+				if ctx.Err() != nil {
+					return
+				}
+				wasUnhealthy := endpoint.IsUnhealthy()
+				status := p.checkEndpoint(ctx, service.HealthCheck, endpoint.URL)
+				err := p.registry.extensionStore.SetExtensionServiceEndpointStatus(ctx, extension.ID, service.ID, endpoint.URL, status)
+				if err != nil {
+					p.logger.Printf("Failed to record the health of extension endpoint %s/%s %s: %v", extension.ID, service.ID, endpoint.URL, err)
+					continue
+				}
+				if wasUnhealthy == (status.Health == domain.EEHUnhealthy) {
+					continue
+				}
+				changed = true
+				if status.Health == domain.EEHUnhealthy {
+					p.logger.Printf("Extension endpoint %s/%s %s is unhealthy: %s", extension.ID, service.ID, endpoint.URL, status.Message)
+				} else {
+					p.logger.Printf("Extension endpoint %s/%s %s is healthy", extension.ID, service.ID, endpoint.URL)
+				}
+			}
+		}
+		if changed {
+			p.registry.notify(ctx, extension.ID, nil)
+		}
+	}
+}
+
+// checkEndpoint runs a health check against an endpoint, returning its status
+func (p *ExtensionHealthProber) checkEndpoint(ctx context.Context, check *domain.ExtensionServiceHealthCheck,
+	endpointURL string) *domain.ExtensionServiceEndpointStatus {
+	timeout := p.timeout
+	if check.Timeout > 0 {
+		timeout = check.Timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var err error
+	switch check.Type {
+	case domain.EHCTCP:
+		err = checkTCP(ctx, endpointURL)
+	default:
+		err = p.checkHTTP(ctx, check, endpointURL)
+	}
+	status := &domain.ExtensionServiceEndpointStatus{Health: domain.EEHHealthy, Latency: time.Since(start), Checked: start}
+	if err != nil {
+		status.Health = domain.EEHUnhealthy
+		status.Message = err.Error()
+	}
+	return status
+}
+
+// checkHTTP sends a GET request to the health check path of the endpoint and checks the response status
+func (p *ExtensionHealthProber) checkHTTP(ctx context.Context, check *domain.ExtensionServiceHealthCheck, endpointURL string) error {
+	checkURL := endpointURL
+	if check.Path != "" {
+		checkURL = strings.TrimSuffix(endpointURL, "/") + "/" + strings.TrimPrefix(check.Path, "/")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checkURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if check.ExpectedStatus != 0 {
+		if resp.StatusCode != check.ExpectedStatus {
+			return fmt.Errorf("unexpected HTTP status %d, expected %d", resp.StatusCode, check.ExpectedStatus)
+		}
+		return nil
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// checkTCP opens a TCP connection to the host and port of the endpoint. The port defaults to the one of the
+// URL scheme (http or https) when not set.
+func checkTCP(ctx context.Context, endpointURL string) error {
+	u, err := url.Parse(endpointURL)
+	if err != nil {
+		return err
+	}
+	address := u.Host
+	if u.Port() == "" {
+		switch u.Scheme {
+		case "http":
+			address = net.JoinHostPort(u.Hostname(), "80")
+		case "https":
+			address = net.JoinHostPort(u.Hostname(), "443")
+		default:
+			return fmt.Errorf("no port set for endpoint %s", endpointURL)
+		}
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
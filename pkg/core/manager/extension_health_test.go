@@ -0,0 +1,133 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+type fakeExtensionSubscriber struct {
+	changed []string
+}
+
+func (s *fakeExtensionSubscriber) OnExtensionChanged(ctx context.Context, extensionID string) {
+	s.changed = append(s.changed, extensionID)
+}
+
+func TestExtensionHealthProber(t *testing.T) {
+	ctx := context.Background()
+	registry := newExtensionRegistry()
+	subscriber := &fakeExtensionSubscriber{}
+	registry.Subscribe(subscriber)
+
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" || !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+
+	e := &domain.Extension{ID: "testextension"}
+	services := []struct {
+		service   *domain.ExtensionService
+		endpoints []string
+	}{
+		{&domain.ExtensionService{ID: "http", HealthCheck: &domain.ExtensionServiceHealthCheck{Type: domain.EHCHTTP, Path: "/health"}},
+			[]string{server.URL}},
+		{&domain.ExtensionService{ID: "http-status", HealthCheck: &domain.ExtensionServiceHealthCheck{Type: domain.EHCHTTP,
+			Path: "/health", ExpectedStatus: http.StatusNoContent}}, []string{server.URL}},
+		{&domain.ExtensionService{ID: "tcp", HealthCheck: &domain.ExtensionServiceHealthCheck{Type: domain.EHCTCP}},
+			[]string{fmt.Sprintf("tcp://%s", listener.Addr()), fmt.Sprintf("tcp://%s", closed.Addr())}},
+		{&domain.ExtensionService{ID: "unchecked"}, []string{fmt.Sprintf("http://%s", closed.Addr())}},
+	}
+	for _, s := range services {
+		for _, url := range s.endpoints {
+			s.service.AddEndpoint(&domain.ExtensionServiceEndpoint{URL: url, Type: domain.EETInternal})
+		}
+		e.AddService(s.service)
+	}
+	_, err = registry.RegisterExtension(ctx, e)
+	assertError(t, err, nil)
+
+	prober := NewExtensionHealthProber(log.New(ioutil.Discard, "", 0), registry, time.Minute, time.Second)
+	assertHealth := func(serviceID, url string, want domain.ExtensionServiceEndpointHealth) {
+		t.Helper()
+		endpoint, err := registry.GetEndpoint(ctx, e.ID, serviceID, url)
+		assertError(t, err, nil)
+		if want == "" {
+			if endpoint.Status != nil {
+				t.Errorf("Expected endpoint %s/%s not to be checked, got %+v", serviceID, url, endpoint.Status)
+			}
+			return
+		}
+		if endpoint.Status == nil || endpoint.Status.Health != want {
+			t.Errorf("Expected endpoint %s/%s to be %s, got %+v", serviceID, url, want, endpoint.Status)
+			return
+		}
+		if endpoint.Status.Checked.IsZero() || (want == domain.EEHUnhealthy) == (endpoint.Status.Message == "") {
+			t.Errorf("Unexpected status for endpoint %s/%s: %+v", serviceID, url, endpoint.Status)
+		}
+	}
+
+	prober.CheckEndpoints(ctx)
+	assertHealth("http", server.URL, domain.EEHHealthy)
+	assertHealth("http-status", server.URL, domain.EEHUnhealthy)
+	assertHealth("tcp", fmt.Sprintf("tcp://%s", listener.Addr()), domain.EEHHealthy)
+	assertHealth("tcp", fmt.Sprintf("tcp://%s", closed.Addr()), domain.EEHUnhealthy)
+	assertHealth("unchecked", fmt.Sprintf("http://%s", closed.Addr()), "")
+	if len(subscriber.changed) != 1 {
+		t.Errorf("Expected the subscribers to be notified once, got %v", subscriber.changed)
+	}
+
+	// the subscribers are only notified when the health of an endpoint changes
+	prober.CheckEndpoints(ctx)
+	if len(subscriber.changed) != 1 {
+		t.Errorf("Expected the subscribers not to be notified, got %v", subscriber.changed)
+	}
+	healthy = false
+	prober.CheckEndpoints(ctx)
+	assertHealth("http", server.URL, domain.EEHUnhealthy)
+	if len(subscriber.changed) != 2 {
+		t.Errorf("Expected the subscribers to be notified, got %v", subscriber.changed)
+	}
+
+	// unhealthy endpoints can be excluded from queries
+	descriptors, err := registry.GetExtensionAccessDescriptors(ctx, &domain.ExtensionQuery{ExcludeUnhealthy: true})
+	assertError(t, err, nil)
+	got := map[string]bool{}
+	for _, d := range descriptors {
+		got[d.Service.ID+" "+d.Endpoint.URL] = true
+	}
+	want := []string{
+		"tcp tcp://" + listener.Addr().String(),
+		"unchecked http://" + closed.Addr().String(),
+	}
+	if len(got) != len(want) {
+		t.Errorf("Unexpected access descriptors: %v", got)
+	}
+	for _, k := range want {
+		if !got[k] {
+			t.Errorf("Expected access descriptor %q, got %v", k, got)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package manager
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/kubernetes"
+)
+
+// resourceDeleter deletes the Kubernetes resources that form an application
+type resourceDeleter interface {
+	DeleteResource(ctx context.Context, name, namespace, kind string) error
+}
+
+// ProjectManager implements the domain.ProjectManager interface
+type ProjectManager struct {
+	logger           *log.Logger
+	projectStore     domain.ProjectStore
+	codesetStore     domain.CodesetStore
+	workflowManager  domain.WorkflowManager
+	applicationStore domain.ApplicationStore
+	// cluster is initialized the first time application resources need to be deleted, so that FuseML can
+	// run without access to a Kubernetes cluster when using other workflow backends
+	cluster resourceDeleter
+}
+
+// NewProjectManager initializes a Project Manager
+func NewProjectManager(
+	logger *log.Logger,
+	projectStore domain.ProjectStore,
+	codesetStore domain.CodesetStore,
+	workflowManager domain.WorkflowManager,
+	applicationStore domain.ApplicationStore) *ProjectManager {
+	return &ProjectManager{logger: logger, projectStore: projectStore, codesetStore: codesetStore,
+		workflowManager: workflowManager, applicationStore: applicationStore}
+}
+
+// UpdateProject updates a project. The project quota is applied before the project is stored, so that a quota
+// the workflow backend cannot enforce is refused and the runs triggered by changes pushed to the project codesets
+// are limited right away.
+func (mgr *ProjectManager) UpdateProject(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	current, err := mgr.projectStore.Find(ctx, project.Name)
+	if err != nil {
+		return nil, err
+	}
+	previous := current.Quota
+	if err = mgr.workflowManager.ApplyProjectQuota(ctx, project.Name, project.Quota); err != nil {
+		return nil, err
+	}
+	updated, err := mgr.projectStore.Update(ctx, project)
+	if err != nil {
+		if qerr := mgr.workflowManager.ApplyProjectQuota(ctx, project.Name, previous); qerr != nil {
+			mgr.logger.Printf("Failed to restore the quota of project %s: %v", project.Name, qerr)
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+// DeleteProject deletes a project. Without cascading, the project must not contain any codeset. When cascading,
+// the workflows are unassigned from the project codesets, the applications created from the codesets and the
+// codesets are deleted before the project, along with the project members that do not belong to other projects.
+// In dry-run mode nothing is deleted and the returned result lists what would be removed.
+func (mgr *ProjectManager) DeleteProject(ctx context.Context, name string, cascade, dryRun bool) (*domain.ProjectDeletion, error) {
+	project, err := mgr.projectStore.Find(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	codesets, err := mgr.codesetStore.GetAll(ctx, &name, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(codesets) > 0 && !cascade {
+		return nil, domain.ErrProjectNotEmpty
+	}
+	applications, unknown, err := mgr.projectApplications(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	users, err := mgr.exclusiveMembers(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.ProjectDeletion{Project: name, DryRun: dryRun, Users: users,
+		Assignments: []*domain.ProjectDeletionAssignment{}, Codesets: []string{}, Applications: []string{},
+		UnknownApplications: []string{}}
+	assignments := mgr.workflowManager.GetAllCodesetAssignments(ctx, nil)
+	for _, c := range codesets {
+		result.Codesets = append(result.Codesets, c.Name)
+		for workflow, workflowAssignments := range assignments {
+			for _, a := range workflowAssignments {
+				if a.Codeset.Project == name && a.Codeset.Name == c.Name {
+					result.Assignments = append(result.Assignments,
+						&domain.ProjectDeletionAssignment{Workflow: workflow, Codeset: c.Name})
+				}
+			}
+		}
+	}
+	sort.Slice(result.Assignments, func(i, j int) bool {
+		if result.Assignments[i].Codeset != result.Assignments[j].Codeset {
+			return result.Assignments[i].Codeset < result.Assignments[j].Codeset
+		}
+		return result.Assignments[i].Workflow < result.Assignments[j].Workflow
+	})
+	sort.Strings(result.Codesets)
+	for _, app := range applications {
+		result.Applications = append(result.Applications, app.Name)
+	}
+	sort.Strings(result.Applications)
+	for _, app := range unknown {
+		result.UnknownApplications = append(result.UnknownApplications, app.Name)
+	}
+	sort.Strings(result.UnknownApplications)
+	if dryRun {
+		return result, nil
+	}
+
+	for _, a := range result.Assignments {
+		err := mgr.workflowManager.UnassignFromCodeset(ctx, a.Workflow, name, a.Codeset)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Unassigning workflow %s from codeset %s failed", a.Workflow, a.Codeset)
+		}
+	}
+	for _, app := range applications {
+		if err := mgr.deleteApplication(ctx, app); err != nil {
+			return nil, errors.Wrapf(err, "Deleting application %s failed", app.Name)
+		}
+	}
+	// deleting the codesets through the codeset store notifies the codeset subscribers
+	for _, c := range codesets {
+		if err := mgr.codesetStore.Delete(ctx, name, c.Name); err != nil {
+			return nil, err
+		}
+	}
+	if err := mgr.projectStore.Delete(ctx, name); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// projectApplications returns the applications created by workflow runs of the project codesets, together with
+// the applications that do not record the codeset project they were created from, e.g. because they were
+// registered by workflow steps that do not set it
+func (mgr *ProjectManager) projectApplications(ctx context.Context, project string) ([]*domain.Application,
+	[]*domain.Application, error) {
+	apps, err := mgr.applicationStore.GetAll(ctx, nil, nil)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Fetching Applications failed")
+	}
+	result := []*domain.Application{}
+	unknown := []*domain.Application{}
+	for _, app := range apps {
+		switch app.CodesetProject {
+		case project:
+			result = append(result, app)
+		case "":
+			unknown = append(unknown, app)
+		}
+	}
+	return result, unknown, nil
+}
+
+// exclusiveMembers returns the names of the project members that do not belong to any other project
+func (mgr *ProjectManager) exclusiveMembers(ctx context.Context, project *domain.Project) ([]string, error) {
+	projects, err := mgr.projectStore.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	shared := map[string]bool{}
+	for _, p := range projects {
+		if p.Name == project.Name {
+			continue
+		}
+		for _, u := range p.Users {
+			shared[u.Name] = true
+		}
+	}
+	users := []string{}
+	for _, u := range project.Users {
+		if !shared[u.Name] {
+			users = append(users, u.Name)
+		}
+	}
+	sort.Strings(users)
+	return users, nil
+}
+
+// deleteApplication deletes the Kubernetes resources of an application and removes it from the application store
+func (mgr *ProjectManager) deleteApplication(ctx context.Context, app *domain.Application) error {
+	if len(app.K8sResources) > 0 && mgr.cluster == nil {
+		cluster, err := kubernetes.NewCluster(mgr.logger)
+		if err != nil {
+			return errors.Wrap(err, "Failed initializing kubernetes cluster")
+		}
+		mgr.cluster = cluster
+	}
+	for _, r := range app.K8sResources {
+		if err := mgr.cluster.DeleteResource(ctx, r.Name, app.K8sNamespace, r.Kind); err != nil {
+			return errors.Wrap(err, "Failed deleting kubernetes resource "+r.Name)
+		}
+	}
+	return mgr.applicationStore.Delete(ctx, app.Name)
+}
@@ -0,0 +1,211 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+
+	"github.com/fuseml/fuseml-core/pkg/core"
+	"github.com/fuseml/fuseml-core/pkg/core/local"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+const errProjectNotFound = codesetErr("project not found")
+
+func TestDeleteProject(t *testing.T) {
+	newProjectManager := func(t *testing.T) (*ProjectManager, domain.ApplicationStore) {
+		wfm := newFakeWorkflowManager(t)
+		projects := &fakeProjectStore{map[string]*domain.Project{
+			"csproject0": {Name: "csproject0", Users: []*domain.User{{Name: "alice"}, {Name: "bob"}}},
+			"csproject1": {Name: "csproject1", Users: []*domain.User{{Name: "bob"}}},
+		}}
+		apps := core.NewApplicationStore()
+		for _, app := range []*domain.Application{
+			{Name: "cs0-predictor", Workflow: "wf", CodesetProject: "csproject0", CodesetName: "cs0"},
+			{Name: "cs1-predictor", Workflow: "wf", CodesetProject: "csproject1", CodesetName: "cs1"},
+			{Name: "unknown-predictor", Workflow: "wf"},
+		} {
+			if _, err := apps.Add(context.TODO(), app); err != nil {
+				t.Fatal(err)
+			}
+		}
+		_, err := wfm.CreateWorkflow(context.TODO(), &domain.Workflow{Name: "wf"})
+		assertError(t, err, nil)
+		_, _, err = wfm.AssignToCodeset(context.TODO(), "wf", "csproject0", "cs0", nil)
+		assertError(t, err, nil)
+		return NewProjectManager(log.New(os.Stdout, "", 0), projects, codesetStore, wfm, apps), apps
+	}
+	want := &domain.ProjectDeletion{
+		Project:             "csproject0",
+		Assignments:         []*domain.ProjectDeletionAssignment{{Workflow: "wf", Codeset: "cs0"}},
+		Codesets:            []string{"cs0"},
+		Applications:        []string{"cs0-predictor"},
+		Users:               []string{"alice"},
+		UnknownApplications: []string{"unknown-predictor"},
+	}
+
+	t.Run("not empty", func(t *testing.T) {
+		mgr, _ := newProjectManager(t)
+		_, err := mgr.DeleteProject(context.TODO(), "csproject0", false, false)
+		assertError(t, err, domain.ErrProjectNotEmpty)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		mgr, apps := newProjectManager(t)
+		got, err := mgr.DeleteProject(context.TODO(), "csproject0", true, true)
+		assertError(t, err, nil)
+		want := *want
+		want.DryRun = true
+		if d := cmp.Diff(&want, got); d != "" {
+			t.Errorf("Unexpected project deletion: %s", diff.PrintWantGot(d))
+		}
+		if _, err = mgr.projectStore.Find(context.TODO(), "csproject0"); err != nil {
+			t.Errorf("Expected the project to be kept, got %v", err)
+		}
+		if _, err = codesetStore.Find(context.TODO(), "csproject0", "cs0"); err != nil {
+			t.Errorf("Expected the codeset to be kept, got %v", err)
+		}
+		if apps.Find(context.TODO(), "cs0-predictor") == nil {
+			t.Errorf("Expected the application to be kept")
+		}
+		if len(mgr.workflowManager.GetAllCodesetAssignments(context.TODO(), nil)["wf"]) != 1 {
+			t.Errorf("Expected the workflow assignment to be kept")
+		}
+	})
+
+	t.Run("cascade", func(t *testing.T) {
+		mgr, apps := newProjectManager(t)
+		got, err := mgr.DeleteProject(context.TODO(), "csproject0", true, false)
+		assertError(t, err, nil)
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected project deletion: %s", diff.PrintWantGot(d))
+		}
+		_, err = mgr.projectStore.Find(context.TODO(), "csproject0")
+		assertError(t, err, errProjectNotFound)
+		_, err = codesetStore.Find(context.TODO(), "csproject0", "cs0")
+		assertError(t, err, errCodesetNotFound)
+		if apps.Find(context.TODO(), "cs0-predictor") != nil {
+			t.Errorf("Expected the application to be deleted")
+		}
+		for _, name := range []string{"cs1-predictor", "unknown-predictor"} {
+			if apps.Find(context.TODO(), name) == nil {
+				t.Errorf("Expected application %q of another project to be kept", name)
+			}
+		}
+		if assignments := mgr.workflowManager.GetAllCodesetAssignments(context.TODO(), nil)["wf"]; len(assignments) != 0 {
+			t.Errorf("Expected the workflow assignment to be removed, got %v", assignments)
+		}
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		mgr, _ := newProjectManager(t)
+		_, err := mgr.DeleteProject(context.TODO(), "unknown", true, true)
+		assertError(t, err, errProjectNotFound)
+	})
+}
+
+func TestUpdateProject(t *testing.T) {
+	newProjectManager := func(t *testing.T, quota *domain.ProjectQuota) (*ProjectManager, *WorkflowManager) {
+		wfm := newFakeWorkflowManager(t)
+		projects := &fakeProjectStore{map[string]*domain.Project{"csproject0": {Name: "csproject0", Quota: quota}}}
+		return NewProjectManager(log.New(os.Stdout, "", 0), projects, codesetStore, wfm, core.NewApplicationStore()), wfm
+	}
+
+	t.Run("quota", func(t *testing.T) {
+		mgr, _ := newProjectManager(t, nil)
+		quota := &domain.ProjectQuota{MaxConcurrentRuns: 2, CPU: "2"}
+		got, err := mgr.UpdateProject(context.TODO(), &domain.Project{Name: "csproject0", Quota: quota})
+		assertError(t, err, nil)
+		if d := cmp.Diff(quota, got.Quota); d != "" {
+			t.Errorf("Unexpected project quota: %s", diff.PrintWantGot(d))
+		}
+		if d := cmp.Diff(quota, workflowBackend.(*fakeWorkflowBackend).quotas["csproject0"]); d != "" {
+			t.Errorf("Unexpected applied quota: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("unsupported quota", func(t *testing.T) {
+		mgr, wfm := newProjectManager(t, nil)
+		backend, err := local.NewWorkflowBackend(log.New(os.Stdout, "", 0), "fuseml-workloads", t.TempDir(),
+			local.NewProcessExecutor("sh"))
+		assertError(t, err, nil)
+		wfm.workflowBackend = backend
+		_, err = mgr.UpdateProject(context.TODO(), &domain.Project{Name: "csproject0", Quota: &domain.ProjectQuota{CPU: "2"}})
+		if !errors.Is(err, domain.ErrProjectQuotaUnsupported) {
+			t.Errorf("got error %q want %q", err, domain.ErrProjectQuotaUnsupported)
+		}
+		project, _ := mgr.projectStore.Find(context.TODO(), "csproject0")
+		if project.Quota != nil {
+			t.Errorf("Expected the project quota not to be stored, got %+v", project.Quota)
+		}
+	})
+
+	t.Run("invalid quota", func(t *testing.T) {
+		previous := &domain.ProjectQuota{CPU: "1"}
+		mgr, _ := newProjectManager(t, previous)
+		_, err := mgr.UpdateProject(context.TODO(), &domain.Project{Name: "csproject0", Quota: &domain.ProjectQuota{MaxConcurrentRuns: -1}})
+		if !errors.Is(err, domain.ErrInvalidProjectQuota) {
+			t.Errorf("got error %q want %q", err, domain.ErrInvalidProjectQuota)
+		}
+		// the quota of the stored project is applied again
+		if d := cmp.Diff(previous, workflowBackend.(*fakeWorkflowBackend).quotas["csproject0"]); d != "" {
+			t.Errorf("Unexpected applied quota: %s", diff.PrintWantGot(d))
+		}
+	})
+}
+
+type fakeProjectStore struct {
+	projects map[string]*domain.Project
+}
+
+func (ps *fakeProjectStore) Find(ctx context.Context, name string) (*domain.Project, error) {
+	if p, ok := ps.projects[name]; ok {
+		return p, nil
+	}
+	return nil, errProjectNotFound
+}
+
+func (ps *fakeProjectStore) GetAll(ctx context.Context) ([]*domain.Project, error) {
+	result := []*domain.Project{}
+	for _, p := range ps.projects {
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+func (ps *fakeProjectStore) Delete(ctx context.Context, name string) error {
+	if _, ok := ps.projects[name]; !ok {
+		return errProjectNotFound
+	}
+	delete(ps.projects, name)
+	return nil
+}
+
+func (ps *fakeProjectStore) Create(ctx context.Context, name, desc string) (*domain.Project, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (ps *fakeProjectStore) Update(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	if _, ok := ps.projects[project.Name]; !ok {
+		return nil, errProjectNotFound
+	}
+	if project.Quota != nil && project.Quota.MaxConcurrentRuns < 0 {
+		return nil, domain.ErrInvalidProjectQuota
+	}
+	updated := *project
+	ps.projects[project.Name] = &updated
+	return &updated, nil
+}
+
+func (ps *fakeProjectStore) AddMember(ctx context.Context, project string, user *domain.User) (*string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (ps *fakeProjectStore) RemoveMember(ctx context.Context, project, user string) error {
+	return errors.New("not implemented")
+}
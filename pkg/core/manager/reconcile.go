@@ -0,0 +1,176 @@
+package manager
+
+import (
+	"context"
+	"sort"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// codesetID identifies a codeset by name and project
+type codesetID struct {
+	name    string
+	project string
+}
+
+// ReconcileAssignments makes the codeset subscriptions, webhooks and workflow listeners consistent with the
+// workflow assignments kept in the workflow store. The codeset subscriptions are only kept in memory, so they are
+// restored from the workflow assignments, e.g. when FuseML starts. The following inconsistencies are reported and,
+// if cleanup is true, removed:
+// - assignments to codesets that were deleted while FuseML was not watching them
+// - codeset webhooks sending events to a workflow listener, without the workflow being assigned to the codeset
+// - workflow listeners of workflows that are not assigned to any codeset
+// Webhooks that do not send events to a known workflow listener are never considered orphaned, as they may have
+// been created by users.
+func (mgr *WorkflowManager) ReconcileAssignments(ctx context.Context, cleanup bool) (*domain.WorkflowReconciliation, error) {
+	result := &domain.WorkflowReconciliation{
+		Cleanup:           cleanup,
+		StaleAssignments:  []*domain.StaleWorkflowAssignment{},
+		OrphanedWebhooks:  []*domain.OrphanedCodesetWebhook{},
+		OrphanedListeners: []string{},
+	}
+
+	// the codesets are listed at once, so that failing to reach the git provider is not mistaken for codesets
+	// being deleted
+	codesets, err := mgr.codesetStore.GetAll(ctx, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	existing := map[codesetID]*domain.Codeset{}
+	for _, c := range codesets {
+		existing[codesetID{c.Name, c.Project}] = c
+	}
+
+	// assigned maps each workflow to the existing codesets it is assigned to
+	assigned := map[string]map[codesetID]bool{}
+	for workflow, assignments := range mgr.workflowStore.GetAllCodesetAssignments(ctx, nil) {
+		assigned[workflow] = map[codesetID]bool{}
+		for _, a := range assignments {
+			id := codesetID{a.Codeset.Name, a.Codeset.Project}
+			if existing[id] == nil {
+				result.StaleAssignments = append(result.StaleAssignments,
+					&domain.StaleWorkflowAssignment{Workflow: workflow, Codeset: a.Codeset})
+				continue
+			}
+			assigned[workflow][id] = true
+		}
+	}
+	subscribed := map[codesetID]bool{}
+	for _, codesets := range assigned {
+		for id := range codesets {
+			if subscribed[id] {
+				continue
+			}
+			if err := mgr.codesetStore.Subscribe(ctx, mgr, existing[id]); err != nil {
+				return nil, err
+			}
+			subscribed[id] = true
+		}
+	}
+	result.Subscriptions = len(subscribed)
+
+	listeners, err := mgr.workflowBackend.GetWorkflowListeners(ctx)
+	if err != nil {
+		return nil, err
+	}
+	listenerByURL := map[string]string{}
+	for _, l := range listeners {
+		if l.URL != "" {
+			listenerByURL[l.URL] = l.Name
+		}
+		if len(assigned[l.Name]) == 0 {
+			result.OrphanedListeners = append(result.OrphanedListeners, l.Name)
+		}
+	}
+
+	for _, c := range codesets {
+		hooks, err := mgr.codesetStore.GetWebhooks(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		for _, hook := range hooks {
+			listener, ok := listenerByURL[hook.URL]
+			if !ok || assigned[listener][codesetID{c.Name, c.Project}] {
+				continue
+			}
+			result.OrphanedWebhooks = append(result.OrphanedWebhooks,
+				&domain.OrphanedCodesetWebhook{Codeset: c, Webhook: hook, Listener: listener})
+		}
+	}
+
+	sortReconciliation(result)
+	if cleanup {
+		if err := mgr.cleanupReconciliation(ctx, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// cleanupReconciliation removes the stale assignments, orphaned webhooks and orphaned listeners
+func (mgr *WorkflowManager) cleanupReconciliation(ctx context.Context, r *domain.WorkflowReconciliation) error {
+	orphaned := map[string]bool{}
+	for _, name := range r.OrphanedListeners {
+		orphaned[name] = true
+	}
+	for _, a := range r.StaleAssignments {
+		assignments, err := mgr.workflowStore.DeleteCodesetAssignment(ctx, a.Workflow, a.Codeset)
+		if err != nil {
+			return err
+		}
+		mgr.codesetStore.Unsubscribe(ctx, mgr, a.Codeset)
+		if len(assignments) > 0 && !orphaned[a.Workflow] {
+			if err = mgr.workflowBackend.UpdateWorkflowListener(ctx, a.Workflow, assignments); err != nil {
+				return err
+			}
+		}
+	}
+	for _, w := range r.OrphanedWebhooks {
+		if err := mgr.codesetStore.DeleteWebhook(ctx, w.Codeset, &w.Webhook.ID); err != nil {
+			return err
+		}
+	}
+	for _, name := range r.OrphanedListeners {
+		if err := mgr.workflowBackend.DeleteWorkflowListener(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// codesetAssigned returns true if any workflow is assigned to the codeset
+func (mgr *WorkflowManager) codesetAssigned(ctx context.Context, codeset *domain.Codeset) bool {
+	for _, assignments := range mgr.workflowStore.GetAllCodesetAssignments(ctx, nil) {
+		for _, a := range assignments {
+			if a.Codeset.Project == codeset.Project && a.Codeset.Name == codeset.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sortReconciliation(r *domain.WorkflowReconciliation) {
+	sort.Slice(r.StaleAssignments, func(i, j int) bool {
+		a, b := r.StaleAssignments[i], r.StaleAssignments[j]
+		if a.Workflow != b.Workflow {
+			return a.Workflow < b.Workflow
+		}
+		return codesetLess(a.Codeset, b.Codeset)
+	})
+	sort.Slice(r.OrphanedWebhooks, func(i, j int) bool {
+		a, b := r.OrphanedWebhooks[i], r.OrphanedWebhooks[j]
+		if a.Codeset.Project != b.Codeset.Project || a.Codeset.Name != b.Codeset.Name {
+			return codesetLess(a.Codeset, b.Codeset)
+		}
+		return a.Webhook.ID < b.Webhook.ID
+	})
+	sort.Strings(r.OrphanedListeners)
+}
+
+func codesetLess(a, b *domain.Codeset) bool {
+	if a.Project != b.Project {
+		return a.Project < b.Project
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,94 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestReconcileAssignments(t *testing.T) {
+	mgr := newFakeWorkflowManager(t)
+	cs0, _ := codesetStore.Find(context.TODO(), "csproject0", "cs0")
+	cs1, _ := codesetStore.Find(context.TODO(), "csproject1", "cs1")
+	cs2, _ := codesetStore.Find(context.TODO(), "csproject1", "cs2")
+
+	for _, name := range []string{"wf", "wf2", "wf3"} {
+		_, err := mgr.CreateWorkflow(context.TODO(), &domain.Workflow{Name: name})
+		assertError(t, err, nil)
+	}
+	for _, a := range []struct {
+		workflow string
+		codeset  *domain.Codeset
+	}{{"wf", cs0}, {"wf", cs1}, {"wf2", cs2}} {
+		_, _, err := mgr.AssignToCodeset(context.TODO(), a.workflow, a.codeset.Project, a.codeset.Name, nil)
+		assertError(t, err, nil)
+	}
+	// wf3 has a listener and a webhook left behind, while a user webhook is not related to any listener
+	listener, err := workflowBackend.CreateWorkflowListener(context.TODO(), "wf3", 0)
+	assertError(t, err, nil)
+	hookID, _ := codesetStore.CreateWebhook(context.TODO(), cs0, listener.URL)
+	codesetStore.CreateWebhook(context.TODO(), cs0, "http://ci.test/hook")
+
+	// simulate a restart, losing the subscriptions, during which cs2 was deleted
+	for id, sc := range codesetStore.store {
+		sc.subscribers = nil
+		codesetStore.store[id] = sc
+	}
+	delete(codesetStore.store, codesetID{cs2.Name, cs2.Project})
+
+	want := &domain.WorkflowReconciliation{
+		Subscriptions:    2,
+		StaleAssignments: []*domain.StaleWorkflowAssignment{{Workflow: "wf2", Codeset: cs2}},
+		OrphanedWebhooks: []*domain.OrphanedCodesetWebhook{{Codeset: cs0,
+			Webhook: &domain.CodesetWebhook{ID: *hookID, URL: listener.URL}, Listener: "wf3"}},
+		OrphanedListeners: []string{"wf2", "wf3"},
+	}
+	got, err := mgr.ReconcileAssignments(context.TODO(), false)
+	assertError(t, err, nil)
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Unexpected reconciliation: %s", diff.PrintWantGot(d))
+	}
+	for _, c := range []*domain.Codeset{cs0, cs1} {
+		if subscribers := codesetStore.getSubscribers(context.TODO(), c); len(subscribers) != 1 {
+			t.Errorf("Expected the workflow manager to be subscribed to %s, got %v", c.Name, subscribers)
+		}
+	}
+	if _, err = workflowBackend.GetWorkflowListener(context.TODO(), "wf3"); err != nil {
+		t.Errorf("Expected the orphaned listener to be kept without cleanup, got %v", err)
+	}
+
+	// reconciling again does not subscribe twice
+	want.Cleanup = true
+	got, err = mgr.ReconcileAssignments(context.TODO(), true)
+	assertError(t, err, nil)
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Unexpected reconciliation: %s", diff.PrintWantGot(d))
+	}
+	if subscribers := codesetStore.getSubscribers(context.TODO(), cs0); len(subscribers) != 1 {
+		t.Errorf("Expected a single subscription to cs0, got %v", subscribers)
+	}
+
+	got, err = mgr.ReconcileAssignments(context.TODO(), false)
+	assertError(t, err, nil)
+	want = &domain.WorkflowReconciliation{Subscriptions: 2, StaleAssignments: []*domain.StaleWorkflowAssignment{},
+		OrphanedWebhooks: []*domain.OrphanedCodesetWebhook{}, OrphanedListeners: []string{}}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Unexpected reconciliation after cleanup: %s", diff.PrintWantGot(d))
+	}
+	if hooks, _ := codesetStore.GetWebhooks(context.TODO(), cs0); len(hooks) != 2 {
+		t.Errorf("Expected the webhooks of the wf assignment and the user to be kept, got %v", hooks)
+	}
+	if assignments := mgr.GetAllCodesetAssignments(context.TODO(), nil); len(assignments["wf2"]) != 0 || len(assignments["wf"]) != 2 {
+		t.Errorf("Unexpected assignments after cleanup: %v", assignments)
+	}
+
+	// deleting a codeset after the subscriptions are restored unassigns its workflows
+	assertError(t, codesetStore.Delete(context.TODO(), cs1.Project, cs1.Name), nil)
+	if assignments := mgr.GetAllCodesetAssignments(context.TODO(), nil); len(assignments["wf"]) != 1 {
+		t.Errorf("Expected wf to be unassigned from the deleted codeset, got %v", assignments["wf"])
+	}
+}
@@ -0,0 +1,150 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// findStepRunnable returns the runnable referenced by a workflow step, either by ID or by a kind and labels
+// query. A query must match exactly one runnable.
+func (mgr *WorkflowManager) findStepRunnable(ctx context.Context, ref *domain.WorkflowStepRunnable) (*domain.Runnable, error) {
+	if ref.ID != "" {
+		if ref.Version > 0 {
+			return mgr.runnableStore.GetVersion(ctx, ref.ID, ref.Version)
+		}
+		return mgr.runnableStore.Get(ctx, ref.ID)
+	}
+
+	if ref.Kind == "" && len(ref.Labels) == 0 {
+		return nil, fmt.Errorf("the runnable must be referenced by ID or by kind and labels")
+	}
+	runnables, err := mgr.runnableStore.Find(ctx, "", ref.Kind, ref.Labels)
+	if err != nil {
+		return nil, err
+	}
+	switch len(runnables) {
+	case 0:
+		return nil, fmt.Errorf("no runnable matches the kind and labels query")
+	case 1:
+		return runnables[0], nil
+	}
+	ids := make([]string, 0, len(runnables))
+	for _, r := range runnables {
+		ids = append(ids, r.ID)
+	}
+	sort.Strings(ids)
+	return nil, fmt.Errorf("the kind and labels query matches more than one runnable: %s", strings.Join(ids, ", "))
+}
+
+// expandStepRunnable sets the step image, entrypoint, arguments and environment variables from the runnable,
+// values already set in the step take precedence. The step inputs and outputs must be defined by the runnable,
+// optional runnable input parameters not set by the step are added to the step with their default values.
+func expandStepRunnable(step *domain.WorkflowStep, r *domain.Runnable) error {
+	if step.Image == "" {
+		step.Image = r.Container.Image
+		if r.Container.LocalImage {
+			step.Image = domain.RegistryHostname + "/" + r.Container.Image
+		}
+	}
+	if step.Entrypoint == "" {
+		step.Entrypoint = r.Container.Entrypoint
+	}
+	if len(step.Args) == 0 {
+		step.Args = r.Container.Args
+	}
+
+	stepEnv := map[string]bool{}
+	for _, env := range step.Env {
+		stepEnv[env.Name] = true
+	}
+	for _, name := range sortedKeys(r.Container.Env) {
+		if !stepEnv[name] {
+			step.Env = append(step.Env, &domain.WorkflowStepEnv{Name: name, Value: r.Container.Env[name]})
+		}
+	}
+
+	stepInputs := map[string]bool{}
+	for _, input := range step.Inputs {
+		stepInputs[input.Name] = true
+		def, ok := r.Inputs[input.Name]
+		if !ok {
+			return fmt.Errorf("runnable %q does not have an input named %q", r.ID, input.Name)
+		}
+		codesetDef, isCodeset := def.(*domain.RunnableInputCodeset)
+		if input.Codeset == nil {
+			if isCodeset {
+				return fmt.Errorf("runnable %q input %q requires a codeset", r.ID, input.Name)
+			}
+			continue
+		}
+		if !isCodeset {
+			return fmt.Errorf("runnable %q input %q does not accept a codeset", r.ID, input.Name)
+		}
+		if input.Codeset.Path == "" {
+			input.Codeset.Path = codesetDef.Path
+			if input.Codeset.Path == "" {
+				input.Codeset.Path = r.DefaultInputPath
+			}
+		}
+	}
+	for _, name := range sortedKeys(r.Inputs) {
+		if stepInputs[name] {
+			continue
+		}
+		if param, ok := r.Inputs[name].(*domain.RunnableInputParameter); ok && param.Optional {
+			step.Inputs = append(step.Inputs, &domain.WorkflowStepInput{Name: name, Value: param.DefaultValue})
+			continue
+		}
+		if !runnableInputIsOptional(r.Inputs[name]) {
+			return fmt.Errorf("runnable %q requires a value for the input %q", r.ID, name)
+		}
+	}
+
+	for _, output := range step.Outputs {
+		if _, ok := r.Outputs[output.Name]; !ok {
+			return fmt.Errorf("runnable %q does not have an output named %q", r.ID, output.Name)
+		}
+	}
+
+	step.Runnable.ID = r.ID
+	step.Runnable.Version = r.Version
+	return nil
+}
+
+// runnableInputIsOptional checks whether a runnable input is marked as optional
+func runnableInputIsOptional(input interface{}) bool {
+	switch in := input.(type) {
+	case *domain.RunnableInputParameter:
+		return in.Optional
+	case *domain.RunnableInputCodeset:
+		return in.Optional
+	case *domain.RunnableInputModel:
+		return in.Optional
+	case *domain.RunnableInputDataset:
+		return in.Optional
+	case *domain.RunnableInputRunnable:
+		return in.Optional
+	}
+	return false
+}
+
+// sortedKeys returns the keys of a map sorted, so that the map can be iterated in a deterministic order
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch v := m.(type) {
+	case map[string]string:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]interface{}:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,285 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
+)
+
+// referenceRegex matches the variables referenced by a workflow (e.g. "{{ inputs.mlflow-codeset.name }}")
+var referenceRegex = regexp.MustCompile(`{{\s*([^{}]*?)\s*}}`)
+
+// codesetInputFields are the fields of a codeset workflow input that can be referenced by the workflow steps
+var codesetInputFields = []string{"name", "version", "project"}
+
+// extensionFields are the fields of a step extension that can be referenced by the step
+var extensionFields = []string{"product", "zone", "version", "service_resource", "service_category", "url"}
+
+// workflowValidator collects the problems found in a workflow definition
+type workflowValidator struct {
+	wf       *domain.Workflow
+	problems domain.WorkflowValidationErrors
+}
+
+func (v *workflowValidator) addProblem(field, format string, a ...interface{}) {
+	v.problems = append(v.problems, &domain.WorkflowValidationError{Field: field, Message: fmt.Sprintf(format, a...)})
+}
+
+// validateWorkflow validates a workflow definition, expanding the steps that reference a runnable. It does not
+// validate the workflow extension requirements, as they are validated when they are resolved.
+func (mgr *WorkflowManager) validateWorkflow(ctx context.Context, wf *domain.Workflow) domain.WorkflowValidationErrors {
+	v := &workflowValidator{wf: wf}
+	if wf.Name == "" {
+		v.addProblem("name", "the workflow name is required")
+	}
+
+	for i, step := range wf.Steps {
+		field := fmt.Sprintf("steps[%d]", i)
+		if step.Runnable == nil {
+			if step.Image == "" {
+				v.addProblem(field+".image", "the step must have an image or reference a runnable")
+			}
+			continue
+		}
+		r, err := mgr.findStepRunnable(ctx, step.Runnable)
+		if err == nil {
+			err = expandStepRunnable(step, r)
+		}
+		if err != nil {
+			v.addProblem(field+".runnable", "%s", err)
+		}
+	}
+
+	v.validateInputs()
+	v.validateOutputs()
+	v.validateSteps()
+	return v.problems
+}
+
+// validateExtensionReferences checks whether the extension requirements of the workflow steps can be resolved
+func (mgr *WorkflowManager) validateExtensionReferences(ctx context.Context, wf *domain.Workflow) domain.WorkflowValidationErrors {
+	v := &workflowValidator{wf: wf}
+	for i, step := range wf.Steps {
+		for j, extReq := range step.Extensions {
+			field := fmt.Sprintf("steps[%d].extensions[%d]", i, j)
+			accessDescList, err := mgr.extensionRegistry.GetExtensionAccessDescriptors(ctx, extensionQuery(extReq))
+			if err != nil {
+				v.addProblem(field, "error resolving extension requirements: %s", err)
+			} else if len(accessDescList) == 0 {
+				v.addProblem(field, "could not resolve extension requirements")
+			}
+		}
+	}
+	return v.problems
+}
+
+func (v *workflowValidator) validateInputs() {
+	names := map[string]bool{}
+	codesetInputs := 0
+	for i, input := range v.wf.Inputs {
+		field := fmt.Sprintf("inputs[%d]", i)
+		v.validateName(field+".name", input.Name, names)
+		switch input.Type {
+		case domain.WorkflowIOTypeCodeset:
+			codesetInputs++
+			if codesetInputs > 1 {
+				v.addProblem(field+".type", "only one input of type %q is supported", domain.WorkflowIOTypeCodeset)
+			}
+		case "", domain.WorkflowIOTypeString:
+		default:
+			v.addProblem(field+".type", "unknown input type %q, expected %q or %q", input.Type,
+				domain.WorkflowIOTypeString, domain.WorkflowIOTypeCodeset)
+		}
+	}
+}
+
+func (v *workflowValidator) validateOutputs() {
+	names := map[string]bool{}
+	for i, output := range v.wf.Outputs {
+		field := fmt.Sprintf("outputs[%d]", i)
+		v.validateName(field+".name", output.Name, names)
+		if output.Type != "" && output.Type != domain.WorkflowIOTypeString {
+			v.addProblem(field+".type", "unknown output type %q, expected %q", output.Type, domain.WorkflowIOTypeString)
+		}
+		if !workflowHasStepOutput(v.wf, output.Name) {
+			v.addProblem(field+".name", "the output %q is not set by any of the workflow steps", output.Name)
+		}
+	}
+}
+
+func (v *workflowValidator) validateSteps() {
+	names := map[string]bool{}
+	for i, step := range v.wf.Steps {
+		field := fmt.Sprintf("steps[%d]", i)
+		v.validateName(field+".name", step.Name, names)
+		v.validateReferences(field+".image", step.Image, i)
+
+		for j, input := range step.Inputs {
+			inputField := fmt.Sprintf("%s.inputs[%d]", field, j)
+			if input.Codeset == nil {
+				if input.Name == "" {
+					v.addProblem(inputField+".name", "the input name is required")
+				}
+				v.validateReferences(inputField+".value", input.Value, i)
+				continue
+			}
+			if !workflowHasCodesetInput(v.wf) {
+				v.addProblem(inputField+".codeset", "the step requires a codeset, but the workflow does not have an input of type %q",
+					domain.WorkflowIOTypeCodeset)
+			}
+			v.validateReferences(inputField+".codeset.name", input.Codeset.Name, i)
+		}
+
+		outputNames := map[string]bool{}
+		for j, output := range step.Outputs {
+			outputField := fmt.Sprintf("%s.outputs[%d]", field, j)
+			v.validateName(outputField+".name", output.Name, outputNames)
+			if output.Image != nil {
+				v.validateReferences(outputField+".image.name", output.Image.Name, i)
+				v.validateReferences(outputField+".image.dockerfile", output.Image.Dockerfile, i)
+			}
+		}
+
+		extensionNames := map[string]bool{}
+		for j, extension := range step.Extensions {
+			v.validateName(fmt.Sprintf("%s.extensions[%d].name", field, j), extension.Name, extensionNames)
+		}
+
+		for j, env := range step.Env {
+			envField := fmt.Sprintf("%s.env[%d]", field, j)
+			if env.Name == "" {
+				v.addProblem(envField+".name", "the environment variable name is required")
+			}
+			v.validateReferences(envField+".value", env.Value, i)
+		}
+
+		for _, name := range sortedKeys(step.Resources.Requests) {
+			v.validateQuantity(fmt.Sprintf("%s.resources.requests.%s", field, name), step.Resources.Requests[name])
+		}
+		for _, name := range sortedKeys(step.Resources.Limits) {
+			v.validateQuantity(fmt.Sprintf("%s.resources.limits.%s", field, name), step.Resources.Limits[name])
+		}
+	}
+}
+
+// validateName checks that a name is set and is not already in use
+func (v *workflowValidator) validateName(field, name string, names map[string]bool) {
+	if name == "" {
+		v.addProblem(field, "the name is required")
+		return
+	}
+	if names[name] {
+		v.addProblem(field, "the name %q is already in use", name)
+	}
+	names[name] = true
+}
+
+func (v *workflowValidator) validateQuantity(field, quantity string) {
+	if _, err := resource.ParseQuantity(quantity); err != nil {
+		v.addProblem(field, "invalid quantity %q: %s", quantity, err)
+	}
+}
+
+// validateReferences checks that the variables referenced by value, set on the step at stepIndex, can be
+// resolved. Step outputs can only be referenced from the steps that run after them.
+func (v *workflowValidator) validateReferences(field, value string, stepIndex int) {
+	for _, match := range referenceRegex.FindAllStringSubmatch(value, -1) {
+		if problem := v.checkReference(match[1], stepIndex); problem != "" {
+			v.addProblem(field, "%s", problem)
+		}
+	}
+}
+
+// checkReference returns the reason why a reference cannot be resolved, or an empty string when it can
+func (v *workflowValidator) checkReference(ref string, stepIndex int) string {
+	parts := strings.Split(ref, ".")
+	switch parts[0] {
+	case "inputs":
+		if len(parts) < 2 {
+			break
+		}
+		input := findWorkflowInput(v.wf, parts[1])
+		if input == nil {
+			return fmt.Sprintf("reference %q to an input that does not exist", ref)
+		}
+		if len(parts) == 2 {
+			return ""
+		}
+		if input.Type != domain.WorkflowIOTypeCodeset || len(parts) != 3 || !util.StringInSlice(parts[2], codesetInputFields) {
+			return fmt.Sprintf("reference %q to an unknown input field", ref)
+		}
+		return ""
+	case "steps":
+		if len(parts) != 4 || parts[2] != "outputs" {
+			break
+		}
+		for i, step := range v.wf.Steps {
+			if step.Name != parts[1] {
+				continue
+			}
+			if i >= stepIndex {
+				return fmt.Sprintf("reference %q to a step that does not run before this one", ref)
+			}
+			for _, output := range step.Outputs {
+				if output.Name == parts[3] {
+					return ""
+				}
+			}
+			return fmt.Sprintf("reference %q to a step output that does not exist", ref)
+		}
+		return fmt.Sprintf("reference %q to a step that does not exist", ref)
+	case "extensions":
+		if len(parts) < 3 || stepIndex >= len(v.wf.Steps) {
+			break
+		}
+		for _, extension := range v.wf.Steps[stepIndex].Extensions {
+			if extension.Name != parts[1] {
+				continue
+			}
+			if (len(parts) == 3 && util.StringInSlice(parts[2], extensionFields)) || (len(parts) > 3 && parts[2] == "cfg") {
+				return ""
+			}
+			return fmt.Sprintf("reference %q to an unknown extension field", ref)
+		}
+		return fmt.Sprintf("reference %q to an extension that is not required by the step", ref)
+	}
+	return fmt.Sprintf("unknown reference %q", ref)
+}
+
+// findWorkflowInput returns the workflow input with the specified name, or nil if there is none
+func findWorkflowInput(wf *domain.Workflow, name string) *domain.WorkflowInput {
+	for _, input := range wf.Inputs {
+		if input.Name == name {
+			return input
+		}
+	}
+	return nil
+}
+
+// workflowHasCodesetInput checks whether a workflow has an input of the codeset type
+func workflowHasCodesetInput(wf *domain.Workflow) bool {
+	for _, input := range wf.Inputs {
+		if input.Type == domain.WorkflowIOTypeCodeset {
+			return true
+		}
+	}
+	return false
+}
+
+// workflowHasStepOutput checks whether any of the workflow steps has an output with the specified name
+func workflowHasStepOutput(wf *domain.Workflow, name string) bool {
+	for _, step := range wf.Steps {
+		for _, output := range step.Outputs {
+			if output.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
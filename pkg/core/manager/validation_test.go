@@ -0,0 +1,222 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestValidateWorkflow(t *testing.T) {
+	t.Run("valid workflow", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+		ext := createFakeExtension(t, mgr, "test-")
+		_, err := mgr.extensionRegistry.RegisterExtension(context.Background(), ext)
+		assertError(t, err, nil)
+
+		got, err := mgr.ValidateWorkflow(context.Background(), newValidWorkflow(ext))
+		assertError(t, err, nil)
+		if len(got) != 0 {
+			t.Errorf("Unexpected validation errors: %s", got)
+		}
+	})
+
+	for _, tc := range []struct {
+		name   string
+		modify func(wf *domain.Workflow)
+		want   domain.WorkflowValidationErrors
+	}{
+		{
+			name: "duplicate names",
+			modify: func(wf *domain.Workflow) {
+				wf.Inputs = append(wf.Inputs, &domain.WorkflowInput{Name: "predictor"})
+				wf.Steps[1].Name = "builder"
+				wf.Steps[1].Extensions = append(wf.Steps[1].Extensions, &domain.WorkflowStepExtension{Name: "tracking",
+					Product: "test-product"})
+			},
+			want: domain.WorkflowValidationErrors{
+				{Field: "inputs[2].name", Message: `the name "predictor" is already in use`},
+				{Field: "steps[1].name", Message: `the name "builder" is already in use`},
+				{Field: "steps[1].extensions[1].name", Message: `the name "tracking" is already in use`},
+				{Field: "steps[2].inputs[0].value", Message: `reference "steps.trainer.outputs.mlflow-model-url" to a step that does not exist`},
+			},
+		},
+		{
+			name: "input and output types",
+			modify: func(wf *domain.Workflow) {
+				wf.Inputs = append(wf.Inputs,
+					&domain.WorkflowInput{Name: "dataset", Type: "dataset"},
+					&domain.WorkflowInput{Name: "other-codeset", Type: domain.WorkflowIOTypeCodeset})
+				wf.Outputs[0].Type = domain.WorkflowIOTypeCodeset
+				wf.Outputs = append(wf.Outputs, &domain.WorkflowOutput{Name: "metrics"})
+			},
+			want: domain.WorkflowValidationErrors{
+				{Field: "inputs[2].type", Message: `unknown input type "dataset", expected "string" or "codeset"`},
+				{Field: "inputs[3].type", Message: `only one input of type "codeset" is supported`},
+				{Field: "outputs[0].type", Message: `unknown output type "codeset", expected "string"`},
+				{Field: "outputs[1].name", Message: `the output "metrics" is not set by any of the workflow steps`},
+			},
+		},
+		{
+			name: "unresolvable references",
+			modify: func(wf *domain.Workflow) {
+				wf.Steps[0].Outputs[0].Image.Name = "registry.fuseml-registry/{{ inputs.mlflow-codeset.url }}:{{ inputs.version }}"
+				wf.Steps[1].Env = []*domain.WorkflowStepEnv{
+					{Name: "PREDICTION_URL", Value: "{{ steps.predictor.outputs.prediction-url }}"},
+					{Name: "ENDPOINT", Value: "{{ extensions.store.url }}"},
+					{Name: "TRACKING_ZONE", Value: "{{ extensions.tracking.region }}"},
+					{Name: "TRACKING_URI", Value: "{{ extensions.tracking.cfg.MLFLOW_TRACKING_URI }}"},
+				}
+				wf.Steps[2].Inputs[0].Value = "{{ steps.trainer.outputs.model }}"
+				wf.Steps[2].Inputs[1].Value = "{{ workflow.name }}"
+			},
+			want: domain.WorkflowValidationErrors{
+				{Field: "steps[0].outputs[0].image.name", Message: `reference "inputs.mlflow-codeset.url" to an unknown input field`},
+				{Field: "steps[0].outputs[0].image.name", Message: `reference "inputs.version" to an input that does not exist`},
+				{Field: "steps[1].env[0].value", Message: `reference "steps.predictor.outputs.prediction-url" to a step that does not run before this one`},
+				{Field: "steps[1].env[1].value", Message: `reference "extensions.store.url" to an extension that is not required by the step`},
+				{Field: "steps[1].env[2].value", Message: `reference "extensions.tracking.region" to an unknown extension field`},
+				{Field: "steps[2].inputs[0].value", Message: `reference "steps.trainer.outputs.model" to a step output that does not exist`},
+				{Field: "steps[2].inputs[1].value", Message: `unknown reference "workflow.name"`},
+			},
+		},
+		{
+			name: "codeset input",
+			modify: func(wf *domain.Workflow) {
+				wf.Inputs = wf.Inputs[1:]
+			},
+			want: domain.WorkflowValidationErrors{
+				{Field: "steps[0].inputs[0].codeset", Message: `the step requires a codeset, but the workflow does not have an input of type "codeset"`},
+				{Field: "steps[0].inputs[0].codeset.name", Message: `reference "inputs.mlflow-codeset" to an input that does not exist`},
+				{Field: "steps[0].outputs[0].image.name", Message: `reference "inputs.mlflow-codeset.name" to an input that does not exist`},
+				{Field: "steps[0].outputs[0].image.name", Message: `reference "inputs.mlflow-codeset.version" to an input that does not exist`},
+				{Field: "steps[1].inputs[0].codeset", Message: `the step requires a codeset, but the workflow does not have an input of type "codeset"`},
+				{Field: "steps[1].inputs[0].codeset.name", Message: `reference "inputs.mlflow-codeset" to an input that does not exist`},
+			},
+		},
+		{
+			name: "resources and missing fields",
+			modify: func(wf *domain.Workflow) {
+				wf.Name = ""
+				wf.Steps[1].Resources.Limits["memory"] = "1 GB"
+				wf.Steps[1].Resources.Requests = map[string]string{"cpu": "a lot"}
+				wf.Steps[2].Image = ""
+				wf.Steps[2].Env = []*domain.WorkflowStepEnv{{Value: "value"}}
+			},
+			want: domain.WorkflowValidationErrors{
+				{Field: "name", Message: "the workflow name is required"},
+				{Field: "steps[2].image", Message: "the step must have an image or reference a runnable"},
+				{Field: "steps[1].resources.requests.cpu", Message: `invalid quantity "a lot": quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'`},
+				{Field: "steps[1].resources.limits.memory", Message: `invalid quantity "1 GB": quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'`},
+				{Field: "steps[2].env[0].name", Message: "the environment variable name is required"},
+			},
+		},
+		{
+			name: "unresolvable extension",
+			modify: func(wf *domain.Workflow) {
+				wf.Steps[1].Extensions[0].VersionConstraints = ">2.0"
+			},
+			want: domain.WorkflowValidationErrors{
+				{Field: "steps[1].extensions[0]", Message: "could not resolve extension requirements"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mgr := newFakeWorkflowManager(t)
+			ext := createFakeExtension(t, mgr, "test-")
+			_, err := mgr.extensionRegistry.RegisterExtension(context.Background(), ext)
+			assertError(t, err, nil)
+
+			wf := newValidWorkflow(ext)
+			tc.modify(wf)
+			got, err := mgr.ValidateWorkflow(context.Background(), wf)
+			assertError(t, err, nil)
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("Unexpected validation errors: %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+}
+
+func TestRenderWorkflow(t *testing.T) {
+	t.Run("valid workflow", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+		ext := createFakeExtension(t, mgr, "test-")
+		_, err := mgr.extensionRegistry.RegisterExtension(context.Background(), ext)
+		assertError(t, err, nil)
+
+		wf := newValidWorkflow(ext)
+		got, err := mgr.RenderWorkflow(context.Background(), wf)
+		assertError(t, err, nil)
+		assertStrings(t, got, "name: mlflow-sklearn-e2e\nsteps: 3\n")
+
+		if wf.Steps[1].Extensions[0].ExtensionAccess == nil {
+			t.Errorf("Expected the extension requirements to be resolved when rendering the workflow")
+		}
+		if len(mgr.GetWorkflows(context.Background(), nil)) != 0 {
+			t.Errorf("Expected the workflow not to be created when rendering it")
+		}
+	})
+
+	t.Run("invalid workflow", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+
+		wf := newValidWorkflow(&domain.Extension{})
+		wf.Steps[2].Name = ""
+		_, err := mgr.RenderWorkflow(context.Background(), wf)
+		if !errors.Is(err, domain.ErrInvalidWorkflow) {
+			t.Fatalf("got error %v want %q", err, domain.ErrInvalidWorkflow)
+		}
+		assertStrings(t, err.Error(), "invalid workflow: steps[2].name: the name is required")
+	})
+}
+
+// newValidWorkflow returns a valid workflow, with a step requiring the extension
+func newValidWorkflow(ext *domain.Extension) *domain.Workflow {
+	return &domain.Workflow{
+		Name: "mlflow-sklearn-e2e",
+		Inputs: []*domain.WorkflowInput{
+			{Name: "mlflow-codeset", Type: domain.WorkflowIOTypeCodeset},
+			{Name: "predictor", Type: domain.WorkflowIOTypeString, Default: "auto"},
+		},
+		Outputs: []*domain.WorkflowOutput{{Name: "prediction-url", Type: domain.WorkflowIOTypeString}},
+		Steps: []*domain.WorkflowStep{
+			{
+				Name:   "builder",
+				Image:  "ghcr.io/fuseml/mlflow-dockerfile:0.1",
+				Inputs: []*domain.WorkflowStepInput{{Codeset: &domain.WorkflowStepInputCodeset{Name: "{{ inputs.mlflow-codeset }}", Path: "/project"}}},
+				Outputs: []*domain.WorkflowStepOutput{{Name: "mlflow-env", Image: &domain.WorkflowStepOutputImage{
+					Name: "registry.fuseml-registry/mlflow-builder/{{ inputs.mlflow-codeset.name }}:{{ inputs.mlflow-codeset.version }}",
+				}}},
+			},
+			{
+				Name:    "trainer",
+				Image:   "{{ steps.builder.outputs.mlflow-env }}",
+				Inputs:  []*domain.WorkflowStepInput{{Codeset: &domain.WorkflowStepInputCodeset{Name: "{{ inputs.mlflow-codeset }}", Path: "/project"}}},
+				Outputs: []*domain.WorkflowStepOutput{{Name: "mlflow-model-url"}},
+				Extensions: []*domain.WorkflowStepExtension{{
+					Name:               "tracking",
+					Product:            ext.Product,
+					VersionConstraints: ext.Version,
+				}},
+				Env: []*domain.WorkflowStepEnv{{Name: "TRACKING_URL", Value: "{{ extensions.tracking.url }}"}},
+				Resources: domain.WorkflowStepResources{
+					Limits: map[string]string{"cpu": "1", "memory": "1Gi"},
+				},
+			},
+			{
+				Name:  "predictor",
+				Image: "ghcr.io/fuseml/kserve-predictor:0.1",
+				Inputs: []*domain.WorkflowStepInput{
+					{Name: "model", Value: "{{ steps.trainer.outputs.mlflow-model-url }}"},
+					{Name: "predictor", Value: "{{ inputs.predictor }}"},
+				},
+				Outputs: []*domain.WorkflowStepOutput{{Name: "prediction-url"}},
+			},
+		},
+	}
+}
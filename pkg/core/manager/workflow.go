@@ -3,6 +3,8 @@ package manager
 import (
 	"context"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/fuseml/fuseml-core/pkg/domain"
@@ -12,22 +14,45 @@ import (
 // to be available
 const createWorkflowListenerTimeout = 1
 
+// defaultCodesetBranch is the codeset branch used by the workflow runs created without a codeset revision
+const defaultCodesetBranch = "main"
+
+// activeWorkflowRunStatuses are the statuses of the workflow runs counted against the maximum number of
+// concurrent runs of a project
+var activeWorkflowRunStatuses = []string{"Pending", "Started", "Running"}
+
+// credentialsScopeRank orders the extension credentials scopes from the least to the most specific
+var credentialsScopeRank = map[domain.ExtensionServiceCredentialsScope]int{
+	domain.ECSGlobal:  0,
+	domain.ECSProject: 1,
+	domain.ECSUser:    2,
+}
+
 // WorkflowManager implements the domain.WorkflowManager interface
 type WorkflowManager struct {
+	logger            *log.Logger
 	workflowBackend   domain.WorkflowBackend
 	workflowStore     domain.WorkflowStore
 	codesetStore      domain.CodesetStore
 	extensionRegistry domain.ExtensionRegistry
+	runnableStore     domain.RunnableStore
+	projectMetadata   domain.ProjectMetadataStore
 }
 
 // NewWorkflowManager initializes a Workflow Manager
 // FIXME: instead of CodesetStore, receive a CodesetManager
 func NewWorkflowManager(
+	logger *log.Logger,
 	workflowBackend domain.WorkflowBackend,
 	workflowStore domain.WorkflowStore,
 	codesetStore domain.CodesetStore,
-	extensionRegistry domain.ExtensionRegistry) *WorkflowManager {
-	return &WorkflowManager{workflowBackend, workflowStore, codesetStore, extensionRegistry}
+	extensionRegistry domain.ExtensionRegistry,
+	runnableStore domain.RunnableStore,
+	projectMetadata domain.ProjectMetadataStore) *WorkflowManager {
+	mgr := &WorkflowManager{logger, workflowBackend, workflowStore, codesetStore, extensionRegistry, runnableStore, projectMetadata}
+	// keep the workflows bound to the current extension endpoints and configuration
+	extensionRegistry.Subscribe(mgr)
+	return mgr
 }
 
 // GetWorkflows returns a list of Workflows.
@@ -38,6 +63,9 @@ func (mgr *WorkflowManager) GetWorkflows(ctx context.Context, name *string) []*d
 // CreateWorkflow creates a new Workflow.
 func (mgr *WorkflowManager) CreateWorkflow(ctx context.Context, wf *domain.Workflow) (*domain.Workflow, error) {
 	wf.Created = time.Now()
+	if problems := mgr.validateWorkflow(ctx, wf); len(problems) > 0 {
+		return nil, problems
+	}
 	err := mgr.resolveExtensionReferences(ctx, wf)
 	if err != nil {
 		return nil, err
@@ -46,7 +74,28 @@ func (mgr *WorkflowManager) CreateWorkflow(ctx context.Context, wf *domain.Workf
 	if err != nil {
 		return nil, err
 	}
-	return mgr.workflowStore.AddWorkflow(ctx, wf)
+	// the workflow backend takes care of passing the credentials to the workflow steps, the stored
+	// workflow only references them
+	return mgr.workflowStore.AddWorkflow(ctx, wf.WithoutCredentials())
+}
+
+// ValidateWorkflow validates a Workflow definition, including whether its extension requirements can be
+// resolved, returning the list of problems found.
+func (mgr *WorkflowManager) ValidateWorkflow(ctx context.Context, wf *domain.Workflow) (domain.WorkflowValidationErrors, error) {
+	problems := mgr.validateWorkflow(ctx, wf)
+	return append(problems, mgr.validateExtensionReferences(ctx, wf)...), nil
+}
+
+// RenderWorkflow returns the objects that the workflow backend creates for a Workflow, without creating them.
+func (mgr *WorkflowManager) RenderWorkflow(ctx context.Context, wf *domain.Workflow) (string, error) {
+	if problems := mgr.validateWorkflow(ctx, wf); len(problems) > 0 {
+		return "", problems
+	}
+	err := mgr.resolveExtensionReferences(ctx, wf)
+	if err != nil {
+		return "", err
+	}
+	return mgr.workflowBackend.RenderWorkflow(ctx, wf)
 }
 
 // GetWorkflow retrieves a Workflow.
@@ -79,25 +128,56 @@ func (mgr *WorkflowManager) DeleteWorkflow(ctx context.Context, name string) err
 	return nil
 }
 
-// AssignToCodeset assigns a Workflow to a Codeset.
-func (mgr *WorkflowManager) AssignToCodeset(ctx context.Context, name, codesetProject, codesetName string) (wfListener *domain.WorkflowListener, webhookID *int64, err error) {
-	_, err = mgr.workflowStore.GetWorkflow(ctx, name)
+// AssignToCodeset assigns a Workflow to a Codeset. The filter restricts the changes pushed to the codeset
+// that trigger the workflow, assigning the workflow to a codeset it is already assigned to updates the filter.
+func (mgr *WorkflowManager) AssignToCodeset(ctx context.Context, name, codesetProject, codesetName string,
+	filter *domain.CodesetAssignmentFilter) (wfListener *domain.WorkflowListener, webhookID *int64, err error) {
+	wf, err := mgr.workflowStore.GetWorkflow(ctx, name)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	if err = validateAssignmentFilter(filter); err != nil {
+		return nil, nil, err
+	}
+	if filter.IsEmpty() {
+		filter = nil
+	}
+
 	codeset, err := mgr.codesetStore.Find(ctx, codesetProject, codesetName)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	// the runs triggered by changes pushed to the codeset use the credentials of the codeset project
+	bound, err := mgr.setWorkflowCredentials(ctx, wf, codeset.Project, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
 	wfListener, err = mgr.workflowBackend.CreateWorkflowListener(ctx, name, createWorkflowListenerTimeout*time.Minute)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	assignments := []*domain.CodesetAssignment{}
+	for _, a := range mgr.workflowStore.GetCodesetAssignments(ctx, name) {
+		if a.Codeset.Project != codeset.Project || a.Codeset.Name != codeset.Name {
+			assignments = append(assignments, a)
+		}
+	}
+	assignments = append(assignments, &domain.CodesetAssignment{Codeset: codeset, Filter: filter})
+	err = mgr.workflowBackend.UpdateWorkflowListener(ctx, name, assignments)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	assignment, err := mgr.workflowStore.GetCodesetAssignment(ctx, name, codeset)
 	if err == nil {
+		_, err = mgr.workflowStore.AddCodesetAssignment(ctx, name, codeset, assignment.WebhookID, filter)
+		if err != nil {
+			return nil, nil, err
+		}
 		return wfListener, assignment.WebhookID, nil
 	}
 
@@ -106,9 +186,14 @@ func (mgr *WorkflowManager) AssignToCodeset(ctx context.Context, name, codesetPr
 		return nil, nil, err
 	}
 
-	mgr.workflowStore.AddCodesetAssignment(ctx, name, codeset, webhookID)
+	mgr.workflowStore.AddCodesetAssignment(ctx, name, codeset, webhookID, filter)
 	mgr.codesetStore.Subscribe(ctx, mgr, codeset)
-	mgr.workflowBackend.CreateWorkflowRun(ctx, name, codeset)
+	// only run the workflow for the codeset default branch when it would be triggered by a push to it, and
+	// when the project quota allows it, as the workflow is assigned regardless
+	if filter.MatchesBranch(defaultCodesetBranch) && mgr.applyProjectQuota(ctx, codeset.Project) == nil {
+		mgr.workflowBackend.CreateWorkflowRun(ctx, name, codeset,
+			&domain.WorkflowRunOptions{Extensions: bound.ExtensionReferences()})
+	}
 	return
 }
 
@@ -138,8 +223,14 @@ func (mgr *WorkflowManager) UnassignFromCodeset(ctx context.Context, name, codes
 		}
 	}
 
-	mgr.workflowStore.DeleteCodesetAssignment(ctx, name, codeset)
-	mgr.codesetStore.Unsubscribe(ctx, mgr, codeset)
+	assignments, _ := mgr.workflowStore.DeleteCodesetAssignment(ctx, name, codeset)
+	// the manager stays subscribed to the codeset while other workflows are assigned to it
+	if !mgr.codesetAssigned(ctx, codeset) {
+		mgr.codesetStore.Unsubscribe(ctx, mgr, codeset)
+	}
+	if len(assignments) > 0 {
+		return mgr.workflowBackend.UpdateWorkflowListener(ctx, name, assignments)
+	}
 	return
 }
 
@@ -181,6 +272,178 @@ func (mgr *WorkflowManager) GetWorkflowRuns(ctx context.Context, filter *domain.
 	return workflowRuns, nil
 }
 
+// CreateWorkflowRun creates a new run of a Workflow for a Codeset, optionally using a specific codeset revision
+// and values overriding the defaults of the workflow inputs. The run is refused if the codeset project already
+// has as many active runs as allowed by its quota. The workflow steps use the extension credentials allowed
+// for the codeset project and, if set in the options, the user triggering the run. The extensions the steps
+// are bound to are recorded on the workflow run.
+func (mgr *WorkflowManager) CreateWorkflowRun(ctx context.Context, name, codesetProject, codesetName string,
+	options *domain.WorkflowRunOptions) (string, error) {
+	wf, err := mgr.workflowStore.GetWorkflow(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	codeset, err := mgr.codesetStore.Find(ctx, codesetProject, codesetName)
+	if err != nil {
+		return "", err
+	}
+
+	runOptions := domain.WorkflowRunOptions{}
+	if options != nil {
+		for inputName := range options.Inputs {
+			if !workflowHasInput(wf, inputName) {
+				return "", fmt.Errorf("%w: %q", domain.ErrWorkflowInputNotFound, inputName)
+			}
+		}
+		runOptions = *options
+	}
+
+	if err = mgr.applyProjectQuota(ctx, codeset.Project); err != nil {
+		return "", err
+	}
+	bound, err := mgr.setWorkflowCredentials(ctx, wf, codeset.Project, runOptions.User)
+	if err != nil {
+		return "", err
+	}
+	runOptions.Extensions = bound.ExtensionReferences()
+	return mgr.workflowBackend.CreateWorkflowRun(ctx, name, codeset, &runOptions)
+}
+
+// applyProjectQuota checks that a new workflow run does not exceed the maximum number of concurrent runs of a
+// project and has the workflow backend apply the resource caps of the project quota
+func (mgr *WorkflowManager) applyProjectQuota(ctx context.Context, project string) error {
+	var quota *domain.ProjectQuota
+	if metadata := mgr.projectMetadata.Find(ctx, project); metadata != nil {
+		quota = metadata.Quota
+	}
+	if quota != nil && quota.MaxConcurrentRuns > 0 {
+		runs, err := mgr.GetWorkflowRuns(ctx, &domain.WorkflowRunFilter{CodesetProject: project, Status: activeWorkflowRunStatuses})
+		if err != nil {
+			return err
+		}
+		if len(runs) >= quota.MaxConcurrentRuns {
+			return fmt.Errorf("%w: project %q already has %d active workflow runs, the maximum is %d",
+				domain.ErrProjectQuotaExceeded, project, len(runs), quota.MaxConcurrentRuns)
+		}
+	}
+	return mgr.workflowBackend.ApplyProjectQuota(ctx, project, quota)
+}
+
+// ApplyProjectQuota limits the resources used by the workflow runs of a project, including the runs triggered
+// by changes pushed to the project codesets, failing if the workflow backend cannot enforce the quota.
+func (mgr *WorkflowManager) ApplyProjectQuota(ctx context.Context, project string, quota *domain.ProjectQuota) error {
+	return mgr.workflowBackend.ApplyProjectQuota(ctx, project, quota)
+}
+
+// GetWorkflowRun returns a workflow run, including the state of each of its steps.
+func (mgr *WorkflowManager) GetWorkflowRun(ctx context.Context, runName string) (*domain.WorkflowRun, error) {
+	run, err := mgr.workflowBackend.GetWorkflowRun(ctx, runName)
+	if err != nil {
+		return nil, err
+	}
+
+	// the workflow may have been deleted while its runs are still kept by the backend, in that
+	// case, return the workflow run inputs and outputs as they are known by the backend
+	wf, err := mgr.workflowStore.GetWorkflow(ctx, run.WorkflowRef)
+	if err == nil {
+		setWorkflowRunIO(run, wf)
+	}
+	return run, nil
+}
+
+// StreamWorkflowRunLogs calls the handler for every line logged by the steps of a workflow run.
+func (mgr *WorkflowManager) StreamWorkflowRunLogs(ctx context.Context, runName string, options *domain.WorkflowRunLogOptions,
+	handler domain.WorkflowRunLogHandler) error {
+	return mgr.workflowBackend.StreamWorkflowRunLogs(ctx, runName, options, handler)
+}
+
+// CancelWorkflowRun cancels a running workflow run.
+func (mgr *WorkflowManager) CancelWorkflowRun(ctx context.Context, runName string) error {
+	return mgr.workflowBackend.CancelWorkflowRun(ctx, runName)
+}
+
+// RetryWorkflowRun creates a new workflow run using the same codeset revision and inputs as a completed
+// workflow run, returning the name of the new workflow run. As for new workflow runs, the retry is refused if
+// the codeset project already has as many active runs as allowed by its quota, and the workflow steps use the
+// extensions and credentials they are currently bound to for the codeset project and the user of the run.
+func (mgr *WorkflowManager) RetryWorkflowRun(ctx context.Context, runName string) (string, error) {
+	run, err := mgr.workflowBackend.GetWorkflowRun(ctx, runName)
+	if err != nil {
+		return "", err
+	}
+	wf, err := mgr.workflowStore.GetWorkflow(ctx, run.WorkflowRef)
+	if err != nil {
+		return "", err
+	}
+	if err = mgr.applyProjectQuota(ctx, run.CodesetProject); err != nil {
+		return "", err
+	}
+	bound, err := mgr.setWorkflowCredentials(ctx, wf, run.CodesetProject, run.User)
+	if err != nil {
+		return "", err
+	}
+	return mgr.workflowBackend.RetryWorkflowRun(ctx, runName, bound.ExtensionReferences())
+}
+
+// DeleteWorkflowRun deletes a workflow run.
+func (mgr *WorkflowManager) DeleteWorkflowRun(ctx context.Context, runName string) error {
+	return mgr.workflowBackend.DeleteWorkflowRun(ctx, runName)
+}
+
+// OnExtensionChanged binds the workflows with steps using an extension again when the extension changes, so
+// that the runs created afterwards use its current endpoints, configuration and credentials. The workflows
+// whose extension requirements cannot be resolved anymore are kept bound to the previous extensions. The
+// credentials bound for the projects of the assigned codesets are revoked when they cannot be bound anymore,
+// e.g. when the credentials or the extension are removed.
+func (mgr *WorkflowManager) OnExtensionChanged(ctx context.Context, extensionID string) {
+	for _, wf := range mgr.GetWorkflows(ctx, nil) {
+		if !workflowUsesExtension(wf, extensionID) {
+			continue
+		}
+		resolved, err := mgr.rebindExtensionReferences(ctx, wf)
+		if err != nil {
+			mgr.logger.Printf("Failed to bind workflow %s to the current extensions: %v", wf.Name, err)
+			resolved = wf
+		}
+		projects := map[string]bool{}
+		for _, assignment := range resolved.GetCodesetAssignments(ctx) {
+			if projects[assignment.Codeset.Project] {
+				continue
+			}
+			projects[assignment.Codeset.Project] = true
+			if _, err = mgr.setWorkflowCredentials(ctx, resolved, assignment.Codeset.Project, ""); err == nil {
+				continue
+			}
+			mgr.logger.Printf("Failed to bind the extension credentials of workflow %s for project %s, revoking them: %v",
+				wf.Name, assignment.Codeset.Project, err)
+			err = mgr.workflowBackend.SetWorkflowCredentials(ctx, resolved.WithoutCredentials(), assignment.Codeset.Project, "")
+			if err != nil {
+				mgr.logger.Printf("Failed to revoke the extension credentials of workflow %s for project %s: %v",
+					wf.Name, assignment.Codeset.Project, err)
+			}
+		}
+	}
+}
+
+// rebindExtensionReferences resolves the extension references of a stored workflow again, updating the
+// workflow in the workflow backend and in the store
+func (mgr *WorkflowManager) rebindExtensionReferences(ctx context.Context, wf *domain.Workflow) (*domain.Workflow, error) {
+	resolved := wf.WithoutCredentials()
+	for _, step := range resolved.Steps {
+		for _, extension := range step.Extensions {
+			extension.ExtensionAccess = nil
+		}
+	}
+	if err := mgr.resolveExtensionReferences(ctx, resolved); err != nil {
+		return nil, err
+	}
+	if err := mgr.workflowBackend.UpdateWorkflow(ctx, resolved); err != nil {
+		return nil, err
+	}
+	return mgr.workflowStore.UpdateWorkflow(ctx, resolved.WithoutCredentials())
+}
+
 // OnDeletingCodeset perform operations on workflows when a codeset is deleted
 func (mgr *WorkflowManager) OnDeletingCodeset(ctx context.Context, codeset *domain.Codeset) {
 	for _, wf := range mgr.GetWorkflows(ctx, nil) {
@@ -188,26 +451,28 @@ func (mgr *WorkflowManager) OnDeletingCodeset(ctx context.Context, codeset *doma
 	}
 }
 
+// validateAssignmentFilter checks that the patterns of a codeset assignment filter are not empty
+func validateAssignmentFilter(filter *domain.CodesetAssignmentFilter) error {
+	if filter == nil {
+		return nil
+	}
+	for _, patterns := range [][]string{filter.Branches, filter.Tags, filter.Paths} {
+		for _, pattern := range patterns {
+			if strings.TrimSpace(pattern) == "" {
+				return fmt.Errorf("%w: patterns cannot be empty", domain.ErrInvalidAssignmentFilter)
+			}
+		}
+	}
+	return nil
+}
+
 // Resolve all the extension references in the workflow steps and update them with actual
-// extension endpoints and credentials
+// extension endpoints. The credentials are only bound to the workflow steps by setWorkflowCredentials, when
+// the workflow runs, as they depend on the codeset project and user.
 func (mgr *WorkflowManager) resolveExtensionReferences(ctx context.Context, wf *domain.Workflow) error {
 	for _, step := range wf.Steps {
 		for _, extReq := range step.Extensions {
-			accessDescList, err := mgr.extensionRegistry.GetExtensionAccessDescriptors(ctx, &domain.ExtensionQuery{
-				ExtensionID:        extReq.ExtensionID,
-				Product:            extReq.Product,
-				VersionConstraints: extReq.VersionConstraints,
-				Zone:               extReq.Zone,
-				// allow extensions outside of the zone for now
-				StrictZoneMatch: false,
-				ServiceID:       extReq.ServiceID,
-				ServiceResource: extReq.ServiceResource,
-				ServiceCategory: extReq.ServiceCategory,
-				// determine endpoint type automatically based on zone
-				Type: nil,
-				// only global credentials supported for now
-				CredentialsScope: domain.ECSGlobal,
-			})
+			accessDescList, err := mgr.extensionRegistry.GetExtensionAccessDescriptors(ctx, extensionQuery(extReq))
 			if err != nil {
 				return fmt.Errorf("error resolving extension requirements for step %q extension %q: %w", step.Name, extReq.Name, err)
 			}
@@ -228,3 +493,136 @@ func (mgr *WorkflowManager) resolveExtensionReferences(ctx context.Context, wf *
 
 	return nil
 }
+
+// extensionQuery returns the query used to find the extensions matching a step extension requirement
+func extensionQuery(extReq *domain.WorkflowStepExtension) *domain.ExtensionQuery {
+	return &domain.ExtensionQuery{
+		ExtensionID:        extReq.ExtensionID,
+		Product:            extReq.Product,
+		VersionConstraints: extReq.VersionConstraints,
+		Zone:               extReq.Zone,
+		// allow extensions outside of the zone for now
+		StrictZoneMatch: false,
+		ServiceID:       extReq.ServiceID,
+		ServiceResource: extReq.ServiceResource,
+		ServiceCategory: extReq.ServiceCategory,
+		// determine endpoint type automatically based on zone
+		Type: nil,
+		// do not bind the workflow steps to endpoints known to be unreachable
+		ExcludeUnhealthy: true,
+	}
+}
+
+// setWorkflowCredentials binds to the workflow steps the extension credentials allowed for a project and, if set,
+// a user, and has the workflow backend make them available to the workflow runs created in their context. The
+// most specific credentials are preferred: user scoped credentials over project scoped ones, and these over
+// global credentials. It returns the workflow with the bound credentials.
+func (mgr *WorkflowManager) setWorkflowCredentials(ctx context.Context, wf *domain.Workflow, project, user string) (*domain.Workflow, error) {
+	bound := wf.WithoutCredentials()
+	for _, step := range bound.Steps {
+		for _, extension := range step.Extensions {
+			if extension.ExtensionAccess == nil {
+				continue
+			}
+			access := *extension.ExtensionAccess
+			credentials, err := mgr.findCredentials(ctx, &access, project, user)
+			if err != nil {
+				return nil, fmt.Errorf("error resolving credentials for step %q extension %q: %w", step.Name, extension.Name, err)
+			}
+			if credentials == nil && access.Service.AuthRequired {
+				return nil, fmt.Errorf("%w: step %q extension %q, project %q", domain.ErrWorkflowCredentialsNotFound,
+					step.Name, extension.Name, project)
+			}
+			access.Credentials = credentials
+			extension.ExtensionAccess = &access
+		}
+	}
+	if err := mgr.workflowBackend.SetWorkflowCredentials(ctx, bound, project, user); err != nil {
+		return nil, err
+	}
+	return bound, nil
+}
+
+// findCredentials returns the most specific credentials of the extension service that can be used for a
+// project and user, nil if there are none
+func (mgr *WorkflowManager) findCredentials(ctx context.Context, access *domain.ExtensionAccessDescriptor,
+	project, user string) (*domain.ExtensionServiceCredentials, error) {
+	query := &domain.ExtensionQuery{
+		ExtensionID:      access.Extension.ID,
+		ServiceID:        access.Service.ID,
+		EndpointURL:      access.Endpoint.URL,
+		CredentialsScope: domain.ECSProject,
+		Project:          project,
+	}
+	if user != "" {
+		query.CredentialsScope = domain.ECSUser
+		query.User = user
+	}
+	accessDescList, err := mgr.extensionRegistry.GetExtensionAccessDescriptors(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	var result *domain.ExtensionServiceCredentials
+	for _, accessDesc := range accessDescList {
+		credentials := accessDesc.Credentials
+		if credentials == nil {
+			continue
+		}
+		if result == nil || credentialsScopeRank[credentials.Scope] > credentialsScopeRank[result.Scope] ||
+			(credentials.Scope == result.Scope && credentials.ID < result.ID) {
+			result = credentials
+		}
+	}
+	return result, nil
+}
+
+// workflowUsesExtension checks whether any of the workflow steps is bound to an extension
+func workflowUsesExtension(wf *domain.Workflow, extensionID string) bool {
+	for _, reference := range wf.ExtensionReferences() {
+		if reference.ExtensionID == extensionID {
+			return true
+		}
+	}
+	return false
+}
+
+// workflowHasInput checks whether a workflow has a non-codeset input with the specified name
+func workflowHasInput(wf *domain.Workflow, name string) bool {
+	for _, input := range wf.Inputs {
+		if input.Name == name && input.Type != domain.WorkflowIOTypeCodeset {
+			return true
+		}
+	}
+	return false
+}
+
+// setWorkflowRunIO sets the workflow run inputs and outputs according to the workflow definition, using the
+// values from the inputs and outputs returned by the workflow backend
+func setWorkflowRunIO(run *domain.WorkflowRun, wf *domain.Workflow) {
+	inputs := make([]*domain.WorkflowRunInput, 0, len(wf.Inputs))
+	for _, input := range wf.Inputs {
+		runInput := &domain.WorkflowRunInput{Input: input}
+		for _, ri := range run.Inputs {
+			if ri.Input.Name == input.Name ||
+				(input.Type == domain.WorkflowIOTypeCodeset && ri.Input.Type == domain.WorkflowIOTypeCodeset) {
+				runInput.Value = ri.Value
+				break
+			}
+		}
+		inputs = append(inputs, runInput)
+	}
+	run.Inputs = inputs
+
+	outputs := make([]*domain.WorkflowRunOutput, 0, len(wf.Outputs))
+	for _, output := range wf.Outputs {
+		runOutput := &domain.WorkflowRunOutput{Output: output}
+		for _, ro := range run.Outputs {
+			if ro.Output.Name == output.Name {
+				runOutput.Value = ro.Value
+				break
+			}
+		}
+		outputs = append(outputs, runOutput)
+	}
+	run.Outputs = outputs
+}
@@ -2,8 +2,12 @@ package manager
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"log"
 	"math/rand"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -13,6 +17,7 @@ import (
 	"github.com/tektoncd/pipeline/test/diff"
 
 	"github.com/fuseml/fuseml-core/pkg/core"
+	"github.com/fuseml/fuseml-core/pkg/core/local"
 	"github.com/fuseml/fuseml-core/pkg/domain"
 	"github.com/fuseml/fuseml-core/pkg/util"
 )
@@ -36,6 +41,12 @@ var (
 	// extensionRegistry stores extensions
 	extensionRegistry *ExtensionRegistry
 
+	// runnableStore stores runnables referenced by workflow steps
+	runnableStore domain.RunnableStore
+
+	// projectMetadataStore stores the quotas of the codeset projects
+	projectMetadataStore domain.ProjectMetadataStore
+
 	// workflowRunStatuses are the possible Status for a WorkflowRun. The status of a WorkflowRun is set
 	// accordingly to its order, cycling between the workflowRunStatuses. E.g. run0: Succeeded, run1: Failed,
 	// run2: Succeeded, ...
@@ -61,21 +72,21 @@ func TestCreateWorkflow(t *testing.T) {
 		}
 
 		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
-		err = workflowBackend.CreateWorkflowRun(context.TODO(), wf.Name, codesets[0])
+		_, err = workflowBackend.CreateWorkflowRun(context.TODO(), wf.Name, codesets[0], nil)
 		assertError(t, err, nil)
 	})
 
 	t.Run("existing workflow", func(t *testing.T) {
 		mgr := newFakeWorkflowManager(t)
 		wf := domain.Workflow{Name: "test"}
-		_, err := mgr.CreateWorkflow(context.Background(), &wf)
+		created, err := mgr.CreateWorkflow(context.Background(), &wf)
 		assertError(t, err, nil)
 
 		_, err = mgr.CreateWorkflow(context.Background(), &wf)
 		assertError(t, err, domain.ErrWorkflowExists)
 
 		got := workflowStore.GetWorkflows(context.TODO(), nil)
-		want := []*domain.Workflow{&wf}
+		want := []*domain.Workflow{created}
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Workflow: %s", diff.PrintWantGot(d))
 		}
@@ -86,16 +97,21 @@ func TestCreateWorkflow(t *testing.T) {
 		ext := createFakeExtension(t, mgr, "test-")
 		ext, err := mgr.extensionRegistry.RegisterExtension(context.Background(), ext)
 		assertError(t, err, nil)
+		_, err = mgr.extensionRegistry.AddCredentials(context.Background(), ext.ID, "test-service-001",
+			&domain.ExtensionServiceCredentials{ID: "test-credentials", Scope: domain.ECSGlobal,
+				Configuration: map[string]string{"TOKEN": "test-token"}})
+		assertError(t, err, nil)
 
 		wf := domain.Workflow{
 			Name: "test",
 			Steps: []*domain.WorkflowStep{{
-				Name: "test-step",
+				Name:  "test-step",
+				Image: "test-image",
 				Extensions: []*domain.WorkflowStepExtension{{
 					Name:               "test-extension",
 					Product:            ext.Product,
 					VersionConstraints: ">=" + ext.Version,
-					ServiceResource:    ext.ListServices()[0].Resource,
+					ServiceID:          "test-service-001",
 				}},
 			}},
 		}
@@ -107,8 +123,17 @@ func TestCreateWorkflow(t *testing.T) {
 			t.Errorf("Unexpected Workflow: %s", diff.PrintWantGot(d))
 		}
 
+		// the stored workflow only references the credentials passed to the workflow backend
+		stored := got.Steps[0].Extensions[0].ExtensionAccess.Credentials
+		if stored.ID != "test-credentials" || stored.Configuration != nil {
+			t.Errorf("Expected the stored workflow to only reference the credentials, got %+v", stored)
+		}
+		if token := wf.Steps[0].Extensions[0].ExtensionAccess.Credentials.Configuration["TOKEN"]; token != "test-token" {
+			t.Errorf("Expected the credentials to be resolved for the workflow backend, got %q", token)
+		}
+
 		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
-		err = workflowBackend.CreateWorkflowRun(context.TODO(), wf.Name, codesets[0])
+		_, err = workflowBackend.CreateWorkflowRun(context.TODO(), wf.Name, codesets[0], nil)
 		assertError(t, err, nil)
 	})
 
@@ -121,7 +146,8 @@ func TestCreateWorkflow(t *testing.T) {
 		wf := domain.Workflow{
 			Name: "test",
 			Steps: []*domain.WorkflowStep{{
-				Name: "test-step",
+				Name:  "test-step",
+				Image: "test-image",
 				Extensions: []*domain.WorkflowStepExtension{{
 					Name:               "test-extension",
 					Product:            ext.Product,
@@ -137,6 +163,196 @@ func TestCreateWorkflow(t *testing.T) {
 	})
 }
 
+func TestCreateWorkflowWithRunnables(t *testing.T) {
+	registerRunnables := func(t *testing.T) {
+		t.Helper()
+
+		trainer := &domain.Runnable{
+			ID:   "trainer",
+			Kind: domain.RKTrainer,
+			Container: domain.RunnableContainer{
+				Image:      "mlflow-trainer:1.0",
+				LocalImage: true,
+				Entrypoint: "/usr/bin/train",
+				Args:       []string{"--verbose"},
+				Env:        map[string]string{"TRAINER_LOG": "info", "TRAINER_MODE": "fast"},
+			},
+			Inputs: map[string]interface{}{
+				"codeset": &domain.RunnableInputCodeset{},
+				"epochs": &domain.RunnableInputParameter{
+					RunnableArgDesc: domain.RunnableArgDesc{Name: "epochs"},
+					Optional:        true,
+					DefaultValue:    "10",
+				},
+			},
+			Outputs: map[string]interface{}{
+				"model-uri": &domain.RunnableOutputParameter{},
+			},
+			DefaultInputPath: "/project",
+			Labels:           map[string]string{"framework": "mlflow"},
+		}
+		predictors := []*domain.Runnable{
+			{ID: "kserve", Kind: domain.RKPredictor, Container: domain.RunnableContainer{Image: "kserve:1.0"},
+				Inputs: map[string]interface{}{"model": &domain.RunnableInputModel{}}, Labels: map[string]string{"serving": "kserve"}},
+			{ID: "seldon", Kind: domain.RKPredictor, Container: domain.RunnableContainer{Image: "seldon:1.0"},
+				Labels: map[string]string{"serving": "seldon"}},
+		}
+		for _, r := range append(predictors, trainer) {
+			_, err := runnableStore.Register(context.TODO(), r)
+			assertError(t, err, nil)
+		}
+		trainerV2 := *trainer
+		trainerV2.Container.Image = "mlflow-trainer:2.0"
+		_, err := runnableStore.Update(context.TODO(), &trainerV2)
+		assertError(t, err, nil)
+	}
+
+	t.Run("expand runnable", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+		registerRunnables(t)
+
+		wf := domain.Workflow{
+			Name:   "test",
+			Inputs: []*domain.WorkflowInput{{Name: "codeset", Type: domain.WorkflowIOTypeCodeset}},
+			Steps: []*domain.WorkflowStep{{
+				Name:     "train",
+				Runnable: &domain.WorkflowStepRunnable{ID: "trainer", Version: 1},
+				Inputs:   []*domain.WorkflowStepInput{{Name: "codeset", Codeset: &domain.WorkflowStepInputCodeset{Name: "{{ inputs.codeset }}"}}},
+				Outputs:  []*domain.WorkflowStepOutput{{Name: "model-uri"}},
+				Env:      []*domain.WorkflowStepEnv{{Name: "TRAINER_MODE", Value: "slow"}},
+			}},
+		}
+		got, err := mgr.CreateWorkflow(context.Background(), &wf)
+		assertError(t, err, nil)
+
+		want := &domain.WorkflowStep{
+			Name:       "train",
+			Image:      "registry.fuseml-registry/mlflow-trainer:1.0",
+			Entrypoint: "/usr/bin/train",
+			Args:       []string{"--verbose"},
+			Runnable:   &domain.WorkflowStepRunnable{ID: "trainer", Version: 1},
+			Inputs: []*domain.WorkflowStepInput{
+				{Name: "codeset", Codeset: &domain.WorkflowStepInputCodeset{Name: "{{ inputs.codeset }}", Path: "/project"}},
+				{Name: "epochs", Value: "10"},
+			},
+			Outputs: []*domain.WorkflowStepOutput{{Name: "model-uri"}},
+			Env: []*domain.WorkflowStepEnv{
+				{Name: "TRAINER_MODE", Value: "slow"},
+				{Name: "TRAINER_LOG", Value: "info"},
+			},
+		}
+		if d := cmp.Diff(want, got.Steps[0]); d != "" {
+			t.Errorf("Unexpected WorkflowStep: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("find runnable by kind and labels", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+		registerRunnables(t)
+
+		wf := domain.Workflow{
+			Name: "test",
+			Steps: []*domain.WorkflowStep{{
+				Name:     "predict",
+				Runnable: &domain.WorkflowStepRunnable{Kind: domain.RKPredictor, Labels: map[string]string{"serving": "kserve"}},
+				Inputs:   []*domain.WorkflowStepInput{{Name: "model", Value: "s3://mlflow-artifacts/model"}},
+			}},
+		}
+		got, err := mgr.CreateWorkflow(context.Background(), &wf)
+		assertError(t, err, nil)
+
+		assertStrings(t, got.Steps[0].Image, "kserve:1.0")
+		want := &domain.WorkflowStepRunnable{ID: "kserve", Version: 1, Kind: domain.RKPredictor,
+			Labels: map[string]string{"serving": "kserve"}}
+		if d := cmp.Diff(want, got.Steps[0].Runnable); d != "" {
+			t.Errorf("Unexpected WorkflowStepRunnable: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	for _, tc := range []struct {
+		name    string
+		step    *domain.WorkflowStep
+		wantErr string
+	}{
+		{
+			name:    "no image",
+			step:    &domain.WorkflowStep{Name: "s"},
+			wantErr: `invalid workflow: steps[0].image: the step must have an image or reference a runnable`,
+		},
+		{
+			name:    "runnable not found",
+			step:    &domain.WorkflowStep{Name: "s", Runnable: &domain.WorkflowStepRunnable{ID: "builder"}},
+			wantErr: `invalid workflow: steps[0].runnable: could not find a runnable with the specified ID`,
+		},
+		{
+			name:    "runnable version not found",
+			step:    &domain.WorkflowStep{Name: "s", Runnable: &domain.WorkflowStepRunnable{ID: "trainer", Version: 3}},
+			wantErr: `invalid workflow: steps[0].runnable: could not find a runnable with the specified version`,
+		},
+		{
+			name:    "no runnable matches query",
+			step:    &domain.WorkflowStep{Name: "s", Runnable: &domain.WorkflowStepRunnable{Kind: domain.RKBuilder}},
+			wantErr: `invalid workflow: steps[0].runnable: no runnable matches the kind and labels query`,
+		},
+		{
+			name:    "query matches multiple runnables",
+			step:    &domain.WorkflowStep{Name: "s", Runnable: &domain.WorkflowStepRunnable{Kind: domain.RKPredictor}},
+			wantErr: `invalid workflow: steps[0].runnable: the kind and labels query matches more than one runnable: kserve, seldon`,
+		},
+		{
+			name:    "empty runnable reference",
+			step:    &domain.WorkflowStep{Name: "s", Runnable: &domain.WorkflowStepRunnable{}},
+			wantErr: `invalid workflow: steps[0].runnable: the runnable must be referenced by ID or by kind and labels`,
+		},
+		{
+			name: "unknown input",
+			step: &domain.WorkflowStep{Name: "s", Runnable: &domain.WorkflowStepRunnable{ID: "seldon"},
+				Inputs: []*domain.WorkflowStepInput{{Name: "model", Value: "s3://model"}}},
+			wantErr: `invalid workflow: steps[0].runnable: runnable "seldon" does not have an input named "model"`,
+		},
+		{
+			name:    "missing required input",
+			step:    &domain.WorkflowStep{Name: "s", Runnable: &domain.WorkflowStepRunnable{ID: "kserve"}},
+			wantErr: `invalid workflow: steps[0].runnable: runnable "kserve" requires a value for the input "model"`,
+		},
+		{
+			name: "codeset input without codeset",
+			step: &domain.WorkflowStep{Name: "s", Runnable: &domain.WorkflowStepRunnable{ID: "trainer"},
+				Inputs: []*domain.WorkflowStepInput{{Name: "codeset", Value: "cs"}}},
+			wantErr: `invalid workflow: steps[0].runnable: runnable "trainer" input "codeset" requires a codeset`,
+		},
+		{
+			name: "codeset for a parameter input",
+			step: &domain.WorkflowStep{Name: "s", Runnable: &domain.WorkflowStepRunnable{ID: "trainer"},
+				Inputs: []*domain.WorkflowStepInput{{Name: "epochs", Codeset: &domain.WorkflowStepInputCodeset{Name: "cs"}}}},
+			wantErr: `invalid workflow: steps[0].runnable: runnable "trainer" input "epochs" does not accept a codeset`,
+		},
+		{
+			name: "unknown output",
+			step: &domain.WorkflowStep{Name: "s", Runnable: &domain.WorkflowStepRunnable{ID: "seldon"},
+				Outputs: []*domain.WorkflowStepOutput{{Name: "prediction-url"}}},
+			wantErr: `invalid workflow: steps[0].runnable: runnable "seldon" does not have an output named "prediction-url"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mgr := newFakeWorkflowManager(t)
+			registerRunnables(t)
+
+			_, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{
+				Name:   "test",
+				Inputs: []*domain.WorkflowInput{{Name: "codeset", Type: domain.WorkflowIOTypeCodeset}},
+				Steps:  []*domain.WorkflowStep{tc.step},
+			})
+			if err == nil || err.Error() != tc.wantErr {
+				t.Errorf("got error %v want %q", err, tc.wantErr)
+			}
+			if !errors.Is(err, domain.ErrInvalidWorkflow) {
+				t.Errorf("expected error %v to be a %q error", err, domain.ErrInvalidWorkflow)
+			}
+		})
+	}
+}
+
 func TestGetWorkflows(t *testing.T) {
 	t.Run("all", func(t *testing.T) {
 		mgr := newFakeWorkflowManager(t)
@@ -224,7 +440,7 @@ func TestDeleteWorkflow(t *testing.T) {
 			t.Errorf("Unexpected Workflow: %s", diff.PrintWantGot(d))
 		}
 
-		err = workflowBackend.CreateWorkflowRun(context.TODO(), wf.Name, nil)
+		_, err = workflowBackend.CreateWorkflowRun(context.TODO(), wf.Name, nil, nil)
 		assertStrings(t, err.Error(), "workflow not found")
 
 	})
@@ -236,7 +452,7 @@ func TestDeleteWorkflow(t *testing.T) {
 		assertError(t, err, nil)
 
 		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
-		_, _, got := mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name)
+		_, _, got := mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, nil)
 		assertError(t, got, nil)
 
 		err = mgr.DeleteWorkflow(context.Background(), wf.Name)
@@ -259,7 +475,7 @@ func TestAssignToCodeset(t *testing.T) {
 
 		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
 		codeset := codesets[0]
-		wantListener, webhookID, err := mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name)
+		wantListener, webhookID, err := mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name, nil)
 		assertError(t, err, nil)
 
 		ignoreUnexported := cmpopts.IgnoreUnexported(WorkflowManager{})
@@ -298,7 +514,7 @@ func TestAssignToCodeset(t *testing.T) {
 		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
 
 		for i := 0; i < 2; i++ {
-			_, _, err := mgr.AssignToCodeset(context.TODO(), wf.Name, codesets[0].Project, codesets[0].Name)
+			_, _, err := mgr.AssignToCodeset(context.TODO(), wf.Name, codesets[0].Project, codesets[0].Name, nil)
 			assertError(t, err, nil)
 		}
 
@@ -320,7 +536,7 @@ func TestAssignToCodeset(t *testing.T) {
 
 		wfName := "unknownWf"
 		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
-		_, _, got := mgr.AssignToCodeset(context.Background(), wfName, codesets[0].Project, codesets[0].Name)
+		_, _, got := mgr.AssignToCodeset(context.Background(), wfName, codesets[0].Project, codesets[0].Name, nil)
 		assertError(t, got, domain.ErrWorkflowNotFound)
 
 		gotAss := workflowStore.GetAllCodesetAssignments(context.TODO(), nil)
@@ -340,7 +556,7 @@ func TestAssignToCodeset(t *testing.T) {
 		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
 		assertError(t, err, nil)
 
-		_, _, got := mgr.AssignToCodeset(context.Background(), wf.Name, "unknownProj", "unknownCs")
+		_, _, got := mgr.AssignToCodeset(context.Background(), wf.Name, "unknownProj", "unknownCs", nil)
 		assertError(t, got, errCodesetNotFound)
 
 		gotAss := workflowStore.GetAllCodesetAssignments(context.TODO(), nil)
@@ -352,6 +568,68 @@ func TestAssignToCodeset(t *testing.T) {
 		_, err = workflowBackend.GetWorkflowListener(context.TODO(), wf.Name)
 		assertStrings(t, err.Error(), "listener not found")
 	})
+
+	t.Run("filtered assignment", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+
+		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
+		assertError(t, err, nil)
+
+		// the listener is updated before the codeset webhook is created
+		ignoreWebhook := cmpopts.IgnoreFields(domain.CodesetAssignment{}, "WebhookID")
+		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		filter := &domain.CodesetAssignmentFilter{Branches: []string{"release/*"}, Paths: []string{"src/**"}}
+		_, webhookID, err := mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, filter)
+		assertError(t, err, nil)
+
+		want := []*domain.CodesetAssignment{{Codeset: codesets[0], WebhookID: webhookID, Filter: filter}}
+		got := workflowStore.GetAllCodesetAssignments(context.TODO(), &wf.Name)
+		if d := cmp.Diff(want, got[wf.Name]); d != "" {
+			t.Errorf("Unexpected Assignment: %s", diff.PrintWantGot(d))
+		}
+		if d := cmp.Diff(want, workflowBackend.(*fakeWorkflowBackend).workflows[wf.Name].assignments, ignoreWebhook); d != "" {
+			t.Errorf("Unexpected listener assignments: %s", diff.PrintWantGot(d))
+		}
+
+		// the branch filter does not match the default branch, so no workflow run is created
+		workflowRuns, err := workflowBackend.GetWorkflowRuns(context.TODO(), wf, nil)
+		assertError(t, err, nil)
+		if len(workflowRuns) != 0 {
+			t.Errorf("Expected no WorkflowRun got %d", len(workflowRuns))
+		}
+
+		// assigning again replaces the filter
+		_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, nil)
+		assertError(t, err, nil)
+		want[0].Filter = nil
+		got = workflowStore.GetAllCodesetAssignments(context.TODO(), &wf.Name)
+		if d := cmp.Diff(want, got[wf.Name]); d != "" {
+			t.Errorf("Unexpected Assignment: %s", diff.PrintWantGot(d))
+		}
+		if d := cmp.Diff(want, workflowBackend.(*fakeWorkflowBackend).workflows[wf.Name].assignments, ignoreWebhook); d != "" {
+			t.Errorf("Unexpected listener assignments: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+
+		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
+		assertError(t, err, nil)
+
+		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		filter := &domain.CodesetAssignmentFilter{Tags: []string{" "}}
+		_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, filter)
+		if !errors.Is(err, domain.ErrInvalidAssignmentFilter) {
+			t.Fatalf("got error %v want %q", err, domain.ErrInvalidAssignmentFilter)
+		}
+
+		gotAss := workflowStore.GetAllCodesetAssignments(context.TODO(), nil)
+		wantAss := map[string][]*domain.CodesetAssignment{}
+		if d := cmp.Diff(wantAss, gotAss); d != "" {
+			t.Errorf("Unexpected Assignment: %s", diff.PrintWantGot(d))
+		}
+	})
 }
 
 func TestUnassignFromCodeset(t *testing.T) {
@@ -367,7 +645,7 @@ func TestUnassignFromCodeset(t *testing.T) {
 		webhooks := map[*domain.Codeset][]*int64{}
 		for i := 0; i < 2; i++ {
 			codeset := codesets[i]
-			listener, webhookID, err = mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name)
+			listener, webhookID, err = mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name, nil)
 			assertError(t, err, nil)
 
 			if webhook, exists := webhooks[codeset]; exists {
@@ -477,7 +755,7 @@ func TestUnassignFromCodeset(t *testing.T) {
 
 		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
 		codeset := codesets[0]
-		_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name)
+		_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codeset.Project, codeset.Name, nil)
 		assertError(t, err, nil)
 
 		codesetStore.Delete(context.TODO(), codeset.Project, codeset.Name)
@@ -516,11 +794,11 @@ func TestGetAllCodesetAssignments(t *testing.T) {
 			if i != 0 {
 				if i == 2 {
 					cs := codesets[i-2]
-					_, webhookID, err := mgr.AssignToCodeset(context.Background(), wf.Name, cs.Project, cs.Name)
+					_, webhookID, err := mgr.AssignToCodeset(context.Background(), wf.Name, cs.Project, cs.Name, nil)
 					assertError(t, err, nil)
 					addToWantAssignment(wf.Name, cs, webhookID)
 				}
-				_, webhookID, err := mgr.AssignToCodeset(context.Background(), wf.Name, codesets[i].Project, codesets[i].Name)
+				_, webhookID, err := mgr.AssignToCodeset(context.Background(), wf.Name, codesets[i].Project, codesets[i].Name, nil)
 				assertError(t, err, nil)
 				addToWantAssignment(wf.Name, codesets[i], webhookID)
 			}
@@ -572,7 +850,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 		// create 3 runs with (cs0, csproject0, "Succeeded", "Failed", "Succeeded") and list
 		for i := 0; i < 3; i++ {
 			// currently, assigning a workflow to a codeset is the only function that creates a workflow run
-			_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name)
+			_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, nil)
 			assertError(t, err, nil)
 
 			got, err = mgr.GetWorkflowRuns(context.Background(), &filter)
@@ -621,7 +899,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 			assertError(t, err, nil)
 
 			for j := 0; j < i; j++ {
-				_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name)
+				_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, nil)
 				assertError(t, err, nil)
 			}
 
@@ -672,7 +950,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 		// 2. (cs1, csproject1, Failed)
 		// 3. (cs2, csproject1, Succeeded)
 		for i := 0; i < len(codesets); i++ {
-			_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[i].Project, codesets[i].Name)
+			_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[i].Project, codesets[i].Name, nil)
 			assertError(t, err, nil)
 		}
 
@@ -751,7 +1029,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 		// 3. (cs0, csproject0, Succeeded)
 		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
 		for i := 0; i < len(codesets); i++ {
-			_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name)
+			_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, nil)
 			assertError(t, err, nil)
 		}
 
@@ -786,7 +1064,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 				if i == 2 {
 					csIndex = j + 1
 				}
-				_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[csIndex].Project, codesets[csIndex].Name)
+				_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, codesets[csIndex].Project, codesets[csIndex].Name, nil)
 				assertError(t, err, nil)
 			}
 		}
@@ -814,6 +1092,514 @@ func TestGetWorkflowRuns(t *testing.T) {
 	})
 }
 
+func TestCreateWorkflowRun(t *testing.T) {
+	t.Run("default values", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+
+		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
+		assertError(t, err, nil)
+
+		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		runName, err := mgr.CreateWorkflowRun(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, nil)
+		assertError(t, err, nil)
+
+		runs, _ := workflowBackend.GetWorkflowRuns(context.TODO(), wf, nil)
+		if len(runs) != 1 {
+			t.Fatalf("Expected 1 WorkflowRun got %d", len(runs))
+		}
+		assertStrings(t, runName, runs[0].Name)
+		assertStrings(t, runs[0].Inputs[1].Value, "sklearn")
+	})
+
+	t.Run("input override", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+
+		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{
+			Name:   "wf",
+			Inputs: []*domain.WorkflowInput{{Name: "predictor", Type: domain.WorkflowIOTypeString, Default: "sklearn"}},
+		})
+		assertError(t, err, nil)
+
+		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		options := domain.WorkflowRunOptions{CodesetRevision: "v1.0", Inputs: map[string]string{"predictor": "kfserving"}}
+		runName, err := mgr.CreateWorkflowRun(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, &options)
+		assertError(t, err, nil)
+
+		runs, _ := workflowBackend.GetWorkflowRuns(context.TODO(), wf, nil)
+		if len(runs) != 1 {
+			t.Fatalf("Expected 1 WorkflowRun got %d", len(runs))
+		}
+		assertStrings(t, runName, runs[0].Name)
+		assertStrings(t, runs[0].Inputs[1].Value, "kfserving")
+	})
+
+	t.Run("unknown input", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+
+		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
+		assertError(t, err, nil)
+
+		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		options := domain.WorkflowRunOptions{Inputs: map[string]string{"predictor": "kfserving"}}
+		_, err = mgr.CreateWorkflowRun(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, &options)
+		if !errors.Is(err, domain.ErrWorkflowInputNotFound) {
+			t.Errorf("got error %q want %q", err, domain.ErrWorkflowInputNotFound)
+		}
+
+		runs, _ := workflowBackend.GetWorkflowRuns(context.TODO(), wf, nil)
+		if len(runs) != 0 {
+			t.Errorf("Expected 0 WorkflowRun got %d", len(runs))
+		}
+	})
+
+	t.Run("project quota", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+
+		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
+		assertError(t, err, nil)
+
+		cs0, _ := codesetStore.Find(context.TODO(), "csproject0", "cs0")
+		cs1, _ := codesetStore.Find(context.TODO(), "csproject1", "cs1")
+		quota := &domain.ProjectQuota{MaxConcurrentRuns: 1, CPU: "2", Memory: "4Gi"}
+		err = projectMetadataStore.Save(context.TODO(), &domain.ProjectMetadata{Project: cs0.Project, Quota: quota})
+		assertError(t, err, nil)
+
+		runName, err := mgr.CreateWorkflowRun(context.Background(), wf.Name, cs0.Project, cs0.Name, nil)
+		assertError(t, err, nil)
+		backend := workflowBackend.(*fakeWorkflowBackend)
+		if d := cmp.Diff(quota, backend.quotas[cs0.Project]); d != "" {
+			t.Errorf("Unexpected ProjectQuota: %s", diff.PrintWantGot(d))
+		}
+
+		// the fake backend creates completed runs, mark the new run as running to reach the quota
+		run, _, _ := backend.findWorkflowRun(runName)
+		run.Status = "Running"
+		_, err = mgr.CreateWorkflowRun(context.Background(), wf.Name, cs0.Project, cs0.Name, nil)
+		if !errors.Is(err, domain.ErrProjectQuotaExceeded) {
+			t.Errorf("got error %q want %q", err, domain.ErrProjectQuotaExceeded)
+		}
+
+		// retrying a run and assigning a workflow to a codeset do not create runs over the quota either
+		run.Status = "Succeeded"
+		_, err = mgr.RetryWorkflowRun(context.Background(), runName)
+		assertError(t, err, nil)
+		_, err = mgr.RetryWorkflowRun(context.Background(), runName)
+		if !errors.Is(err, domain.ErrProjectQuotaExceeded) {
+			t.Errorf("got error %q want %q", err, domain.ErrProjectQuotaExceeded)
+		}
+		_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, cs0.Project, cs0.Name, nil)
+		assertError(t, err, nil)
+		runs, err := workflowBackend.GetWorkflowRuns(context.TODO(), wf, nil)
+		assertError(t, err, nil)
+		if len(runs) != 2 {
+			t.Errorf("Expected 2 WorkflowRun got %d", len(runs))
+		}
+
+		// the runs of other projects are not limited
+		_, err = mgr.CreateWorkflowRun(context.Background(), wf.Name, cs1.Project, cs1.Name, nil)
+		assertError(t, err, nil)
+
+		err = projectMetadataStore.Save(context.TODO(), &domain.ProjectMetadata{Project: cs0.Project})
+		assertError(t, err, nil)
+		_, err = mgr.CreateWorkflowRun(context.Background(), wf.Name, cs0.Project, cs0.Name, nil)
+		assertError(t, err, nil)
+		if _, ok := backend.quotas[cs0.Project]; ok {
+			t.Errorf("Expected the project quota to be removed")
+		}
+	})
+
+	t.Run("scoped credentials", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+		ext := createFakeExtension(t, mgr, "test-")
+		ext.Services["test-service-001"].AuthRequired = true
+		ext, err := mgr.extensionRegistry.RegisterExtension(context.Background(), ext)
+		assertError(t, err, nil)
+		err = mgr.extensionRegistry.RemoveCredentials(context.Background(), ext.ID, "test-service-001", "test-credentials-001")
+		assertError(t, err, nil)
+		addCredentials := func(credentials *domain.ExtensionServiceCredentials) {
+			t.Helper()
+			_, err := mgr.extensionRegistry.AddCredentials(context.Background(), ext.ID, "test-service-001", credentials)
+			assertError(t, err, nil)
+		}
+		addCredentials(&domain.ExtensionServiceCredentials{ID: "project", Scope: domain.ECSProject,
+			Projects: []string{"csproject0"}, Configuration: map[string]string{"TOKEN": "project-token"}})
+		addCredentials(&domain.ExtensionServiceCredentials{ID: "alice", Scope: domain.ECSUser,
+			Projects: []string{"csproject0"}, Users: []string{"alice"}, Configuration: map[string]string{"TOKEN": "alice-token"}})
+
+		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{
+			Name: "wf",
+			Steps: []*domain.WorkflowStep{{
+				Name:  "test-step",
+				Image: "test-image",
+				Extensions: []*domain.WorkflowStepExtension{{
+					Name:      "test-extension",
+					Product:   ext.Product,
+					ServiceID: "test-service-001",
+				}},
+			}},
+		})
+		assertError(t, err, nil)
+
+		backend := workflowBackend.(*fakeWorkflowBackend)
+		runNames := map[string]string{}
+		for _, tc := range []struct{ project, codeset, user, want string }{
+			{"csproject0", "cs0", "", "project-token"},
+			{"csproject0", "cs0", "alice", "alice-token"},
+			{"csproject0", "cs0", "bob", "project-token"},
+		} {
+			runNames[tc.user], err = mgr.CreateWorkflowRun(context.Background(), wf.Name, tc.project, tc.codeset,
+				&domain.WorkflowRunOptions{User: tc.user})
+			assertError(t, err, nil)
+			assertStrings(t, backend.credentialsValue(wf.Name, tc.project, tc.user, "TOKEN"), tc.want)
+		}
+
+		// retrying a run refreshes the credentials of the user that started it
+		err = mgr.extensionRegistry.UpdateCredentials(context.Background(), ext.ID, "test-service-001",
+			&domain.ExtensionServiceCredentials{ID: "alice", Scope: domain.ECSUser, Projects: []string{"csproject0"},
+				Users: []string{"alice"}, Configuration: map[string]string{"TOKEN": "alice-new-token"}})
+		assertError(t, err, nil)
+		retryName, err := mgr.RetryWorkflowRun(context.Background(), runNames["alice"])
+		assertError(t, err, nil)
+		assertStrings(t, backend.credentialsValue(wf.Name, "csproject0", "alice", "TOKEN"), "alice-new-token")
+		retried, _, err := backend.findWorkflowRun(retryName)
+		assertError(t, err, nil)
+		if len(retried.Extensions) != 1 || retried.Extensions[0].CredentialsID != "alice" {
+			t.Errorf("Expected the retried run to be bound to the current credentials, got %+v", retried.Extensions)
+		}
+
+		// the service requires credentials and none can be used by the other projects
+		_, err = mgr.CreateWorkflowRun(context.Background(), wf.Name, "csproject1", "cs1", nil)
+		if !errors.Is(err, domain.ErrWorkflowCredentialsNotFound) {
+			t.Errorf("got error %q want %q", err, domain.ErrWorkflowCredentialsNotFound)
+		}
+		_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, "csproject1", "cs1", nil)
+		if !errors.Is(err, domain.ErrWorkflowCredentialsNotFound) {
+			t.Errorf("got error %q want %q", err, domain.ErrWorkflowCredentialsNotFound)
+		}
+
+		addCredentials(&domain.ExtensionServiceCredentials{ID: "global", Scope: domain.ECSGlobal,
+			Configuration: map[string]string{"TOKEN": "global-token"}})
+		_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, "csproject1", "cs1", nil)
+		assertError(t, err, nil)
+		assertStrings(t, backend.credentialsValue(wf.Name, "csproject1", "", "TOKEN"), "global-token")
+	})
+
+	t.Run("bound extensions", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+		ext, err := mgr.extensionRegistry.RegisterExtension(context.Background(), createFakeExtension(t, mgr, "test-"))
+		assertError(t, err, nil)
+
+		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{
+			Name: "wf",
+			Steps: []*domain.WorkflowStep{{
+				Name:       "test-step",
+				Image:      "test-image",
+				Extensions: []*domain.WorkflowStepExtension{{Name: "test-extension", Product: ext.Product, ServiceID: "test-service-001"}},
+			}},
+		})
+		assertError(t, err, nil)
+
+		runName, err := mgr.CreateWorkflowRun(context.Background(), wf.Name, "csproject0", "cs0", nil)
+		assertError(t, err, nil)
+		run, _, err := workflowBackend.(*fakeWorkflowBackend).findWorkflowRun(runName)
+		assertError(t, err, nil)
+		want := []*domain.WorkflowRunExtension{{StepName: "test-step", Name: "test-extension", ExtensionID: ext.ID,
+			ServiceID: "test-service-001", EndpointURL: "https://test-endpoint-001.com", CredentialsID: "test-credentials-001"}}
+		if d := cmp.Diff(want, run.Extensions); d != "" {
+			t.Errorf("Unexpected WorkflowRun extensions: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("workflow not found", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+
+		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		_, err := mgr.CreateWorkflowRun(context.Background(), "unknownWf", codesets[0].Project, codesets[0].Name, nil)
+		assertError(t, err, domain.ErrWorkflowNotFound)
+	})
+
+	t.Run("codeset not found", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+
+		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
+		assertError(t, err, nil)
+
+		_, err = mgr.CreateWorkflowRun(context.Background(), wf.Name, "unknownProj", "unknownCs", nil)
+		assertError(t, err, errCodesetNotFound)
+	})
+}
+
+func TestOnExtensionChanged(t *testing.T) {
+	mgr := newFakeWorkflowManager(t)
+	ext, err := mgr.extensionRegistry.RegisterExtension(context.Background(), createFakeExtension(t, mgr, "test-"))
+	assertError(t, err, nil)
+	_, err = mgr.extensionRegistry.RegisterExtension(context.Background(), createFakeExtension(t, mgr, "other-"))
+	assertError(t, err, nil)
+
+	newWorkflow := func(name, prefix string) *domain.Workflow {
+		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{
+			Name: name,
+			Steps: []*domain.WorkflowStep{{
+				Name:  "test-step",
+				Image: "test-image",
+				Extensions: []*domain.WorkflowStepExtension{{Name: "test-extension", Product: prefix + "product",
+					ServiceID: prefix + "service-001"}},
+			}},
+		})
+		assertError(t, err, nil)
+		return wf
+	}
+	wf := newWorkflow("wf", "test-")
+	otherWf := newWorkflow("other-wf", "other-")
+	_, _, err = mgr.AssignToCodeset(context.Background(), wf.Name, "csproject0", "cs0", nil)
+	assertError(t, err, nil)
+
+	endpoint := &domain.ExtensionServiceEndpoint{URL: "https://test-endpoint-001.com", Type: domain.EETInternal}
+	err = mgr.extensionRegistry.RemoveEndpoint(context.Background(), ext.ID, "test-service-001", endpoint.URL)
+	assertError(t, err, nil)
+	endpoint.URL = "https://test-endpoint-003.com"
+	_, err = mgr.extensionRegistry.AddEndpoint(context.Background(), ext.ID, "test-service-001", endpoint)
+	assertError(t, err, nil)
+
+	// the workflow is bound to the new endpoint in the store, in the workflow backend and in the credentials
+	// bound for the assigned codeset project
+	backend := workflowBackend.(*fakeWorkflowBackend)
+	stored, err := mgr.GetWorkflow(context.Background(), wf.Name)
+	assertError(t, err, nil)
+	for _, w := range []*domain.Workflow{stored, backend.workflows[wf.Name].workflow, backend.credentials["wf/csproject0/"]} {
+		assertStrings(t, w.Steps[0].Extensions[0].ExtensionAccess.Endpoint.URL, endpoint.URL)
+	}
+
+	// the workflows bound to other extensions are not changed
+	stored, err = mgr.GetWorkflow(context.Background(), otherWf.Name)
+	assertError(t, err, nil)
+	assertStrings(t, stored.Steps[0].Extensions[0].ExtensionAccess.Endpoint.URL, "https://other-endpoint-001.com")
+}
+
+func TestOnExtensionChangedRevokesCredentials(t *testing.T) {
+	ctx := context.Background()
+	newAssignedWorkflow := func(t *testing.T) (*WorkflowManager, *domain.Extension) {
+		mgr := newFakeWorkflowManager(t)
+		ext := createFakeExtension(t, mgr, "test-")
+		ext.Services["test-service-001"].AuthRequired = true
+		ext.Services["test-service-001"].Credentials["test-credentials-001"].Configuration = map[string]string{"TOKEN": "test-token"}
+		ext, err := mgr.extensionRegistry.RegisterExtension(ctx, ext)
+		assertError(t, err, nil)
+		_, err = mgr.CreateWorkflow(ctx, &domain.Workflow{
+			Name: "wf",
+			Steps: []*domain.WorkflowStep{{
+				Name:       "test-step",
+				Image:      "test-image",
+				Extensions: []*domain.WorkflowStepExtension{{Name: "test-extension", Product: ext.Product, ServiceID: "test-service-001"}},
+			}},
+		})
+		assertError(t, err, nil)
+		_, _, err = mgr.AssignToCodeset(ctx, "wf", "csproject0", "cs0", nil)
+		assertError(t, err, nil)
+		assertStrings(t, workflowBackend.(*fakeWorkflowBackend).credentialsValue("wf", "csproject0", "", "TOKEN"), "test-token")
+		return mgr, ext
+	}
+
+	for _, tc := range []struct {
+		name   string
+		change func(mgr *WorkflowManager, ext *domain.Extension) error
+	}{
+		{"credentials removed", func(mgr *WorkflowManager, ext *domain.Extension) error {
+			return mgr.extensionRegistry.RemoveCredentials(ctx, ext.ID, "test-service-001", "test-credentials-001")
+		}},
+		{"project removed from credentials", func(mgr *WorkflowManager, ext *domain.Extension) error {
+			return mgr.extensionRegistry.UpdateCredentials(ctx, ext.ID, "test-service-001", &domain.ExtensionServiceCredentials{
+				ID: "test-credentials-001", Scope: domain.ECSProject, Projects: []string{"csproject1"},
+				Configuration: map[string]string{"TOKEN": "test-token"}})
+		}},
+		{"extension removed", func(mgr *WorkflowManager, ext *domain.Extension) error {
+			return mgr.extensionRegistry.RemoveExtension(ctx, ext.ID)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mgr, ext := newAssignedWorkflow(t)
+			assertError(t, tc.change(mgr, ext), nil)
+			backend := workflowBackend.(*fakeWorkflowBackend)
+			if _, exists := backend.credentials["wf/csproject0/"]; !exists {
+				t.Fatalf("Expected the credentials of the project to be set")
+			}
+			assertStrings(t, backend.credentialsValue("wf", "csproject0", "", "TOKEN"), "")
+		})
+	}
+}
+
+func TestGetWorkflowRun(t *testing.T) {
+	t.Run("existing run", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+
+		wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{
+			Name: "wf",
+			Inputs: []*domain.WorkflowInput{
+				{Name: "mlflow-codeset", Type: domain.WorkflowIOTypeCodeset, Description: "MLflow project"},
+				{Name: "predictor", Type: domain.WorkflowIOTypeString, Default: "sklearn"},
+			},
+			Outputs: []*domain.WorkflowOutput{{Name: "prediction-url", Type: domain.WorkflowIOTypeString}},
+			Steps: []*domain.WorkflowStep{{
+				Name:    "predictor",
+				Image:   "ghcr.io/fuseml/kserve-predictor:0.1",
+				Outputs: []*domain.WorkflowStepOutput{{Name: "prediction-url"}},
+			}},
+		})
+		assertError(t, err, nil)
+
+		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+		runName, err := mgr.CreateWorkflowRun(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, nil)
+		assertError(t, err, nil)
+
+		got, err := mgr.GetWorkflowRun(context.Background(), runName)
+		assertError(t, err, nil)
+
+		want := &domain.WorkflowRun{
+			Name:           runName,
+			WorkflowRef:    wf.Name,
+			CodesetProject: codesets[0].Project,
+			Inputs: []*domain.WorkflowRunInput{
+				{Input: wf.Inputs[0], Value: fmt.Sprintf("%s/%s", codesets[0].Project, codesets[0].Name)},
+				{Input: wf.Inputs[1], Value: "sklearn"},
+			},
+			Outputs: []*domain.WorkflowRunOutput{{Output: wf.Outputs[0]}},
+			Status:  workflowRunStatuses[0],
+			Steps:   []*domain.WorkflowRunStep{{Name: "clone", Status: "Succeeded"}, {Name: "trainer", Status: workflowRunStatuses[0]}},
+		}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected WorkflowRun: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mgr := newFakeWorkflowManager(t)
+
+		_, err := mgr.GetWorkflowRun(context.Background(), "unknown")
+		assertError(t, err, domain.ErrWorkflowRunNotFound)
+	})
+}
+
+func TestWorkflowRunOperations(t *testing.T) {
+	mgr := newFakeWorkflowManager(t)
+
+	wf, err := mgr.CreateWorkflow(context.Background(), &domain.Workflow{Name: "wf"})
+	assertError(t, err, nil)
+
+	codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
+	runName, err := mgr.CreateWorkflowRun(context.Background(), wf.Name, codesets[0].Project, codesets[0].Name, nil)
+	assertError(t, err, nil)
+
+	t.Run("cancel completed run", func(t *testing.T) {
+		err := mgr.CancelWorkflowRun(context.Background(), runName)
+		assertError(t, err, domain.ErrWorkflowRunCompleted)
+	})
+
+	t.Run("retry run", func(t *testing.T) {
+		newRunName, err := mgr.RetryWorkflowRun(context.Background(), runName)
+		assertError(t, err, nil)
+
+		runs, _ := workflowBackend.GetWorkflowRuns(context.TODO(), wf, nil)
+		if len(runs) != 2 {
+			t.Fatalf("Expected 2 WorkflowRun got %d", len(runs))
+		}
+		assertStrings(t, runs[1].Name, newRunName)
+		if d := cmp.Diff(runs[0].Inputs, runs[1].Inputs); d != "" {
+			t.Errorf("Unexpected WorkflowRun inputs: %s", diff.PrintWantGot(d))
+		}
+
+		_, err = mgr.RetryWorkflowRun(context.Background(), newRunName)
+		assertError(t, err, domain.ErrWorkflowRunNotCompleted)
+
+		err = mgr.CancelWorkflowRun(context.Background(), newRunName)
+		assertError(t, err, nil)
+	})
+
+	t.Run("delete run", func(t *testing.T) {
+		err := mgr.DeleteWorkflowRun(context.Background(), runName)
+		assertError(t, err, nil)
+
+		runs, _ := workflowBackend.GetWorkflowRuns(context.TODO(), wf, nil)
+		if len(runs) != 1 {
+			t.Fatalf("Expected 1 WorkflowRun got %d", len(runs))
+		}
+		assertStrings(t, runs[0].Status, "Cancelled")
+	})
+
+	t.Run("run not found", func(t *testing.T) {
+		err := mgr.CancelWorkflowRun(context.Background(), "unknown")
+		assertError(t, err, domain.ErrWorkflowRunNotFound)
+		_, err = mgr.RetryWorkflowRun(context.Background(), "unknown")
+		assertError(t, err, domain.ErrWorkflowRunNotFound)
+		err = mgr.DeleteWorkflowRun(context.Background(), "unknown")
+		assertError(t, err, domain.ErrWorkflowRunNotFound)
+	})
+}
+
+func TestLocalWorkflowBackend(t *testing.T) {
+	mgr := newFakeWorkflowManager(t)
+	logger := log.New(os.Stdout, "[manager-test] ", log.Ltime)
+	backend, err := local.NewWorkflowBackend(logger, "fuseml-workloads", t.TempDir(), local.NewProcessExecutor("sh"))
+	assertError(t, err, nil)
+	mgr.workflowBackend = backend
+	ctx := context.Background()
+
+	wf, err := mgr.CreateWorkflow(ctx, &domain.Workflow{
+		Name:    "greet",
+		Inputs:  []*domain.WorkflowInput{{Name: "name", Type: domain.WorkflowIOTypeString, Default: "world"}},
+		Outputs: []*domain.WorkflowOutput{{Name: "greeting", Type: domain.WorkflowIOTypeString}},
+		Steps: []*domain.WorkflowStep{
+			{
+				Name:    "hello",
+				Image:   `echo "greeting $FUSEML_NAME"; printf "hello" > $FUSEML_ENV_RESULTS_DIR/$TASK_RESULT`,
+				Inputs:  []*domain.WorkflowStepInput{{Name: "name", Value: "{{ inputs.name }}"}},
+				Outputs: []*domain.WorkflowStepOutput{{Name: "hello"}},
+			},
+			{
+				Name:    "greeting",
+				Image:   `printf "$HELLO, $FUSEML_NAME" > $FUSEML_ENV_RESULTS_DIR/$TASK_RESULT`,
+				Inputs:  []*domain.WorkflowStepInput{{Name: "name", Value: "{{ inputs.name }}"}},
+				Outputs: []*domain.WorkflowStepOutput{{Name: "greeting"}},
+				Env:     []*domain.WorkflowStepEnv{{Name: "HELLO", Value: "{{ steps.hello.outputs.hello }}"}},
+			},
+		},
+	})
+	assertError(t, err, nil)
+
+	codesets, _ := codesetStore.GetAll(ctx, nil, nil)
+	runName, err := mgr.CreateWorkflowRun(ctx, wf.Name, codesets[0].Project, codesets[0].Name,
+		&domain.WorkflowRunOptions{Inputs: map[string]string{"name": "fuseml"}})
+	assertError(t, err, nil)
+
+	logs := []string{}
+	err = mgr.StreamWorkflowRunLogs(ctx, runName, &domain.WorkflowRunLogOptions{Follow: true},
+		func(l *domain.WorkflowRunLog) error {
+			logs = append(logs, l.Line)
+			return nil
+		})
+	assertError(t, err, nil)
+	if d := cmp.Diff([]string{"greeting fuseml"}, logs); d != "" {
+		t.Errorf("Unexpected WorkflowRun logs: %s", diff.PrintWantGot(d))
+	}
+
+	run, err := mgr.GetWorkflowRun(ctx, runName)
+	assertError(t, err, nil)
+	assertStrings(t, run.Status, "Succeeded")
+	if len(run.Steps) != 2 {
+		t.Fatalf("Expected 2 WorkflowRun steps got %d", len(run.Steps))
+	}
+	if len(run.Outputs) != 1 {
+		t.Fatalf("Expected 1 WorkflowRun output got %d", len(run.Outputs))
+	}
+	assertStrings(t, run.Outputs[0].Value, "hello, fuseml")
+
+	runs, err := mgr.GetWorkflowRuns(ctx, &domain.WorkflowRunFilter{WorkflowName: &wf.Name})
+	assertError(t, err, nil)
+	if len(runs) != 1 {
+		t.Fatalf("Expected 1 WorkflowRun got %d", len(runs))
+	}
+	assertStrings(t, runs[0].Name, runName)
+}
+
 func TestGetAssignmentStatus(t *testing.T) {
 	t.Run("not assigned", func(t *testing.T) {
 		mgr := newFakeWorkflowManager(t)
@@ -837,7 +1623,7 @@ func TestGetAssignmentStatus(t *testing.T) {
 		codesets, _ := codesetStore.GetAll(context.TODO(), nil, nil)
 		codeset := codesets[0]
 
-		listener, _, err := mgr.AssignToCodeset(context.TODO(), wf.Name, codeset.Project, codeset.Name)
+		listener, _, err := mgr.AssignToCodeset(context.TODO(), wf.Name, codeset.Project, codeset.Name, nil)
 		assertError(t, err, nil)
 
 		got := mgr.GetAssignmentStatus(context.TODO(), wf.Name)
@@ -868,9 +1654,12 @@ func newFakeWorkflowManager(t *testing.T) *WorkflowManager {
 	t.Helper()
 
 	workflowStore = core.NewWorkflowStore()
-	workflowBackend = &fakeWorkflowBackend{t, make(map[string]*fakeStorableWorkflow)}
+	workflowBackend = &fakeWorkflowBackend{t, make(map[string]*fakeStorableWorkflow), make(map[string]*domain.ProjectQuota),
+		make(map[string]*domain.Workflow)}
 	codesetStore = &fakeCodesetStore{t, make(map[codesetID]fakeStorableCodeset)}
 	extensionRegistry = NewExtensionRegistry(core.NewExtensionStore())
+	runnableStore = core.NewRunnableStore()
+	projectMetadataStore = core.NewProjectMetadataStore()
 
 	// add codesets to the codeset store for the tests to use it:
 	// 1. name: cs0, project: csproject0
@@ -891,7 +1680,8 @@ func newFakeWorkflowManager(t *testing.T) *WorkflowManager {
 		}
 	}
 
-	return NewWorkflowManager(workflowBackend, workflowStore, codesetStore, extensionRegistry)
+	return NewWorkflowManager(log.New(ioutil.Discard, "", 0), workflowBackend, workflowStore, codesetStore, extensionRegistry,
+		runnableStore, projectMetadataStore)
 }
 
 func createFakeExtension(t *testing.T, wfm *WorkflowManager, prefix string) *domain.Extension {
@@ -936,13 +1726,17 @@ func createFakeExtension(t *testing.T, wfm *WorkflowManager, prefix string) *dom
 }
 
 type fakeStorableWorkflow struct {
-	listener *domain.WorkflowListener
-	runs     []*domain.WorkflowRun
+	workflow    *domain.Workflow
+	listener    *domain.WorkflowListener
+	assignments []*domain.CodesetAssignment
+	runs        []*domain.WorkflowRun
 }
 
 type fakeWorkflowBackend struct {
-	t         *testing.T
-	workflows map[string]*fakeStorableWorkflow
+	t           *testing.T
+	workflows   map[string]*fakeStorableWorkflow
+	quotas      map[string]*domain.ProjectQuota
+	credentials map[string]*domain.Workflow
 }
 
 func (b *fakeWorkflowBackend) CreateWorkflow(ctx context.Context, w *domain.Workflow) error {
@@ -951,10 +1745,26 @@ func (b *fakeWorkflowBackend) CreateWorkflow(ctx context.Context, w *domain.Work
 	if _, exists := b.workflows[w.Name]; exists {
 		return domain.ErrWorkflowExists
 	}
-	b.workflows[w.Name] = &fakeStorableWorkflow{nil, []*domain.WorkflowRun{}}
+	b.workflows[w.Name] = &fakeStorableWorkflow{workflow: w, listener: nil, runs: []*domain.WorkflowRun{}}
 	return nil
 }
 
+func (b *fakeWorkflowBackend) UpdateWorkflow(ctx context.Context, w *domain.Workflow) error {
+	b.t.Helper()
+
+	if _, exists := b.workflows[w.Name]; !exists {
+		return domain.ErrWorkflowNotFound
+	}
+	b.workflows[w.Name].workflow = w
+	return nil
+}
+
+func (b *fakeWorkflowBackend) RenderWorkflow(ctx context.Context, w *domain.Workflow) (string, error) {
+	b.t.Helper()
+
+	return fmt.Sprintf("name: %s\nsteps: %d\n", w.Name, len(w.Steps)), nil
+}
+
 func (b *fakeWorkflowBackend) DeleteWorkflow(ctx context.Context, workflowName string) error {
 	b.t.Helper()
 
@@ -962,26 +1772,65 @@ func (b *fakeWorkflowBackend) DeleteWorkflow(ctx context.Context, workflowName s
 	return nil
 }
 
-func (b *fakeWorkflowBackend) CreateWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset) error {
+func (b *fakeWorkflowBackend) CreateWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset,
+	options *domain.WorkflowRunOptions) (string, error) {
 	b.t.Helper()
 
 	if _, exists := b.workflows[workflowName]; !exists {
-		return fmt.Errorf("workflow not found")
+		return "", fmt.Errorf("workflow not found")
+	}
+
+	predictor := "sklearn"
+	user := ""
+	var extensions []*domain.WorkflowRunExtension
+	if options != nil {
+		if v, ok := options.Inputs["predictor"]; ok {
+			predictor = v
+		}
+		user = options.User
+		extensions = options.Extensions
 	}
 
 	runs := b.workflows[workflowName].runs
 	run := &domain.WorkflowRun{
-		Name:        fmt.Sprintf("%s-run%d", workflowName, len(runs)),
-		WorkflowRef: workflowName,
+		Name:           fmt.Sprintf("%s-run%d", workflowName, len(runs)),
+		WorkflowRef:    workflowName,
+		CodesetProject: codeset.Project,
 		Inputs: []*domain.WorkflowRunInput{
 			{Input: &domain.WorkflowInput{Name: "codeset-name", Type: "codeset"}, Value: fmt.Sprintf("%s/%s", codeset.Project, codeset.Name)},
-			{Input: &domain.WorkflowInput{Name: "predictor", Type: "string"}, Value: "sklearn"}},
-		Status: workflowRunStatuses[len(runs)%len(workflowRunStatuses)]}
+			{Input: &domain.WorkflowInput{Name: "predictor", Type: "string"}, Value: predictor}},
+		Status:     workflowRunStatuses[len(runs)%len(workflowRunStatuses)],
+		User:       user,
+		Extensions: extensions}
 
 	b.workflows[workflowName].runs = append(b.workflows[workflowName].runs, run)
+	return run.Name, nil
+}
+
+func (b *fakeWorkflowBackend) SetWorkflowCredentials(ctx context.Context, workflow *domain.Workflow, project, user string) error {
+	b.t.Helper()
+
+	if _, exists := b.workflows[workflow.Name]; !exists {
+		return domain.ErrWorkflowNotFound
+	}
+	b.credentials[fmt.Sprintf("%s/%s/%s", workflow.Name, project, user)] = workflow
 	return nil
 }
 
+// credentialsValue returns the value of a credentials configuration entry bound to the first extension of
+// the first step of a workflow for a project and user
+func (b *fakeWorkflowBackend) credentialsValue(workflowName, project, user, name string) string {
+	wf, exists := b.credentials[fmt.Sprintf("%s/%s/%s", workflowName, project, user)]
+	if !exists {
+		return ""
+	}
+	credentials := wf.Steps[0].Extensions[0].ExtensionAccess.Credentials
+	if credentials == nil {
+		return ""
+	}
+	return credentials.Configuration[name]
+}
+
 func (b *fakeWorkflowBackend) GetWorkflowRuns(ctx context.Context, wf *domain.Workflow, filter *domain.WorkflowRunFilter) ([]*domain.WorkflowRun, error) {
 	b.t.Helper()
 
@@ -1031,6 +1880,113 @@ func (b *fakeWorkflowBackend) GetWorkflowRuns(ctx context.Context, wf *domain.Wo
 	return res, nil
 }
 
+func (b *fakeWorkflowBackend) GetWorkflowRun(ctx context.Context, runName string) (*domain.WorkflowRun, error) {
+	b.t.Helper()
+
+	run, _, err := b.findWorkflowRun(runName)
+	if err != nil {
+		return nil, err
+	}
+
+	// as the backend does not keep the workflow definition, only return the inputs name and type
+	res := *run
+	res.Inputs = []*domain.WorkflowRunInput{}
+	for _, input := range run.Inputs {
+		res.Inputs = append(res.Inputs, &domain.WorkflowRunInput{
+			Input: &domain.WorkflowInput{Name: input.Input.Name, Type: input.Input.Type},
+			Value: input.Value,
+		})
+	}
+	res.Steps = []*domain.WorkflowRunStep{{Name: "clone", Status: "Succeeded"}, {Name: "trainer", Status: run.Status}}
+	return &res, nil
+}
+
+func (b *fakeWorkflowBackend) StreamWorkflowRunLogs(ctx context.Context, runName string, options *domain.WorkflowRunLogOptions,
+	handler domain.WorkflowRunLogHandler) error {
+	b.t.Helper()
+
+	if _, _, err := b.findWorkflowRun(runName); err != nil {
+		return err
+	}
+	for _, step := range []string{"clone", "trainer"} {
+		if options.Step == "" || options.Step == step {
+			if err := handler(&domain.WorkflowRunLog{Step: step, Container: "step-run", Line: runName}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *fakeWorkflowBackend) CancelWorkflowRun(ctx context.Context, runName string) error {
+	b.t.Helper()
+
+	run, _, err := b.findWorkflowRun(runName)
+	if err != nil {
+		return err
+	}
+	if run.Status != "Running" {
+		return domain.ErrWorkflowRunCompleted
+	}
+	run.Status = "Cancelled"
+	return nil
+}
+
+func (b *fakeWorkflowBackend) RetryWorkflowRun(ctx context.Context, runName string,
+	extensions []*domain.WorkflowRunExtension) (string, error) {
+	b.t.Helper()
+
+	run, _, err := b.findWorkflowRun(runName)
+	if err != nil {
+		return "", err
+	}
+	if run.Status == "Running" {
+		return "", domain.ErrWorkflowRunNotCompleted
+	}
+
+	runs := b.workflows[run.WorkflowRef].runs
+	newRun := *run
+	newRun.Name = fmt.Sprintf("%s-run%d", run.WorkflowRef, len(runs))
+	newRun.Status = "Running"
+	newRun.Extensions = extensions
+	b.workflows[run.WorkflowRef].runs = append(runs, &newRun)
+	return newRun.Name, nil
+}
+
+func (b *fakeWorkflowBackend) DeleteWorkflowRun(ctx context.Context, runName string) error {
+	b.t.Helper()
+
+	run, i, err := b.findWorkflowRun(runName)
+	if err != nil {
+		return err
+	}
+	runs := b.workflows[run.WorkflowRef].runs
+	b.workflows[run.WorkflowRef].runs = append(runs[:i], runs[i+1:]...)
+	return nil
+}
+
+func (b *fakeWorkflowBackend) findWorkflowRun(runName string) (*domain.WorkflowRun, int, error) {
+	for _, sw := range b.workflows {
+		for i, run := range sw.runs {
+			if run.Name == runName {
+				return run, i, nil
+			}
+		}
+	}
+	return nil, 0, domain.ErrWorkflowRunNotFound
+}
+
+func (b *fakeWorkflowBackend) ApplyProjectQuota(ctx context.Context, project string, quota *domain.ProjectQuota) error {
+	b.t.Helper()
+
+	if quota.HasResourceCaps() {
+		b.quotas[project] = quota
+	} else {
+		delete(b.quotas, project)
+	}
+	return nil
+}
+
 func (b *fakeWorkflowBackend) CreateWorkflowListener(ctx context.Context, workflowName string, timeout time.Duration) (*domain.WorkflowListener, error) {
 	b.t.Helper()
 
@@ -1055,6 +2011,17 @@ func (b *fakeWorkflowBackend) DeleteWorkflowListener(ctx context.Context, workfl
 	return nil
 }
 
+func (b *fakeWorkflowBackend) UpdateWorkflowListener(ctx context.Context, workflowName string, assignments []*domain.CodesetAssignment) error {
+	b.t.Helper()
+
+	wf, exists := b.workflows[workflowName]
+	if !exists || wf.listener == nil {
+		return fmt.Errorf("listener not found")
+	}
+	wf.assignments = assignments
+	return nil
+}
+
 func (b *fakeWorkflowBackend) GetWorkflowListener(ctx context.Context, workflowName string) (*domain.WorkflowListener, error) {
 	b.t.Helper()
 
@@ -1066,9 +2033,16 @@ func (b *fakeWorkflowBackend) GetWorkflowListener(ctx context.Context, workflowN
 	return nil, fmt.Errorf("listener not found")
 }
 
-type codesetID struct {
-	name    string
-	project string
+func (b *fakeWorkflowBackend) GetWorkflowListeners(ctx context.Context) ([]*domain.WorkflowListener, error) {
+	b.t.Helper()
+
+	listeners := []*domain.WorkflowListener{}
+	for _, wf := range b.workflows {
+		if wf.listener != nil {
+			listeners = append(listeners, wf.listener)
+		}
+	}
+	return listeners, nil
 }
 
 type fakeStorableCodeset struct {
@@ -1089,6 +2063,12 @@ func (fcs *fakeCodesetStore) Add(ctx context.Context, c *domain.Codeset) (*domai
 	return c, nil, nil, nil
 }
 
+func (fcs *fakeCodesetStore) Import(ctx context.Context, c *domain.Codeset, source *domain.CodesetImportSource) (*domain.Codeset, *string, *string, error) {
+	fcs.t.Helper()
+
+	return fcs.Add(ctx, c)
+}
+
 func (fcs *fakeCodesetStore) CreateWebhook(ctx context.Context, c *domain.Codeset, url string) (*int64, error) {
 	fcs.t.Helper()
 
@@ -1104,6 +2084,20 @@ func (fcs *fakeCodesetStore) DeleteWebhook(ctx context.Context, c *domain.Codese
 	return nil
 }
 
+func (fcs *fakeCodesetStore) GetWebhooks(ctx context.Context, c *domain.Codeset) ([]*domain.CodesetWebhook, error) {
+	fcs.t.Helper()
+
+	sc, ok := fcs.store[codesetID{c.Name, c.Project}]
+	if !ok {
+		return nil, errCodesetNotFound
+	}
+	hooks := []*domain.CodesetWebhook{}
+	for id, url := range sc.webhooks {
+		hooks = append(hooks, &domain.CodesetWebhook{ID: id, URL: url})
+	}
+	return hooks, nil
+}
+
 func (fcs *fakeCodesetStore) Delete(ctx context.Context, project, name string) error {
 	fcs.t.Helper()
 
@@ -1132,11 +2126,41 @@ func (fcs *fakeCodesetStore) GetAll(ctx context.Context, project, label *string)
 	fcs.t.Helper()
 
 	for _, c := range fcs.store {
+		if project != nil && c.codeset.Project != *project {
+			continue
+		}
 		res = append(res, c.codeset)
 	}
 	return res, nil
 }
 
+func (fcs *fakeCodesetStore) Inspect(ctx context.Context, project, name string) (*domain.Codeset, error) {
+	fcs.t.Helper()
+
+	return fcs.Find(ctx, project, name)
+}
+
+func (fcs *fakeCodesetStore) GetRevisions(ctx context.Context, project, name string, commits int) (*domain.CodesetRevisions, error) {
+	fcs.t.Helper()
+
+	if _, exists := fcs.store[codesetID{name, project}]; !exists {
+		return nil, errCodesetNotFound
+	}
+	return &domain.CodesetRevisions{Branches: []*domain.CodesetRef{{Name: "main"}}}, nil
+}
+
+func (fcs *fakeCodesetStore) GetFiles(ctx context.Context, project, name, ref, path string) ([]*domain.CodesetFile, error) {
+	fcs.t.Helper()
+
+	return nil, nil
+}
+
+func (fcs *fakeCodesetStore) GetFileContents(ctx context.Context, project, name, ref, path string) ([]byte, error) {
+	fcs.t.Helper()
+
+	return nil, nil
+}
+
 func (fcs *fakeCodesetStore) Subscribe(ctx context.Context, subscriber domain.CodesetSubscriber, codeset *domain.Codeset) error {
 	fcs.t.Helper()
 
@@ -1144,6 +2168,11 @@ func (fcs *fakeCodesetStore) Subscribe(ctx context.Context, subscriber domain.Co
 	if !ok {
 		return fmt.Errorf("codeset not found")
 	}
+	for _, s := range sc.subscribers {
+		if s == subscriber {
+			return nil
+		}
+	}
 	sc.subscribers = append(sc.subscribers, subscriber)
 	fcs.store[codesetID{codeset.Name, codeset.Project}] = sc
 	return nil
@@ -1152,7 +2181,10 @@ func (fcs *fakeCodesetStore) Subscribe(ctx context.Context, subscriber domain.Co
 func (fcs *fakeCodesetStore) Unsubscribe(ctx context.Context, subscriber domain.CodesetSubscriber, codeset *domain.Codeset) error {
 	fcs.t.Helper()
 
-	sc := fcs.store[codesetID{codeset.Name, codeset.Project}]
+	sc, ok := fcs.store[codesetID{codeset.Name, codeset.Project}]
+	if !ok {
+		return nil
+	}
 	sc.subscribers = removeSubscriber(sc.subscribers, subscriber)
 	fcs.store[codesetID{codeset.Name, codeset.Project}] = sc
 	return nil
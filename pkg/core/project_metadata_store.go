@@ -0,0 +1,36 @@
+package core
+
+import (
+	"context"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// ProjectMetadataStore describes in memory store for project metadata
+type ProjectMetadataStore struct {
+	items map[string]*domain.ProjectMetadata
+}
+
+// NewProjectMetadataStore returns an in-memory project metadata store instance
+func NewProjectMetadataStore() *ProjectMetadataStore {
+	return &ProjectMetadataStore{
+		items: make(map[string]*domain.ProjectMetadata),
+	}
+}
+
+// Find returns the metadata of a project, or nil if the project has none
+func (ps *ProjectMetadataStore) Find(ctx context.Context, project string) *domain.ProjectMetadata {
+	return ps.items[project]
+}
+
+// Save adds or replaces the metadata of a project
+func (ps *ProjectMetadataStore) Save(ctx context.Context, metadata *domain.ProjectMetadata) error {
+	ps.items[metadata.Project] = metadata
+	return nil
+}
+
+// Delete removes the metadata of a project
+func (ps *ProjectMetadataStore) Delete(ctx context.Context, project string) error {
+	delete(ps.items, project)
+	return nil
+}
@@ -2,21 +2,26 @@ package core
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/fuseml/fuseml-core/pkg/domain"
 )
 
-// GitProjectStore describes a structure that accesses project store implemented in git
+// GitProjectStore describes a structure that accesses project store implemented in git. The project
+// metadata that git providers cannot keep, such as labels and quotas, is kept in a separate store.
 type GitProjectStore struct {
-	gitAdmin domain.GitAdminClient
+	gitAdmin      domain.GitAdminClient
+	metadataStore domain.ProjectMetadataStore
 }
 
 // NewGitProjectStore returns project store instance
-func NewGitProjectStore(gitAdmin domain.GitAdminClient) *GitProjectStore {
+func NewGitProjectStore(gitAdmin domain.GitAdminClient, metadataStore domain.ProjectMetadataStore) *GitProjectStore {
 	return &GitProjectStore{
-		gitAdmin: gitAdmin,
+		gitAdmin:      gitAdmin,
+		metadataStore: metadataStore,
 	}
 }
 
@@ -26,6 +31,7 @@ func (cs *GitProjectStore) Find(ctx context.Context, project string) (*domain.Pr
 	if err != nil {
 		return nil, errors.Wrap(err, "Fetching Project failed")
 	}
+	cs.addMetadata(ctx, result)
 	return result, nil
 }
 
@@ -44,14 +50,93 @@ func (cs *GitProjectStore) GetAll(ctx context.Context) ([]*domain.Project, error
 	if err != nil {
 		return nil, errors.Wrap(err, "Fetching Projects failed")
 	}
+	for _, p := range result {
+		cs.addMetadata(ctx, p)
+	}
 	return result, nil
 }
 
+// Update changes the description, labels, default workflow and quota of a project
+func (cs *GitProjectStore) Update(ctx context.Context, project *domain.Project) (*domain.Project, error) {
+	if err := validateProjectQuota(project.Quota); err != nil {
+		return nil, err
+	}
+	current, err := cs.gitAdmin.GetProject(project.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "Fetching Project failed")
+	}
+	if project.Description != current.Description {
+		if err = cs.gitAdmin.UpdateProject(project.Name, project.Description); err != nil {
+			return nil, errors.Wrap(err, "Updating Project failed")
+		}
+	}
+	err = cs.metadataStore.Save(ctx, &domain.ProjectMetadata{
+		Project:         project.Name,
+		Labels:          project.Labels,
+		DefaultWorkflow: project.DefaultWorkflow,
+		Quota:           project.Quota,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Updating Project metadata failed")
+	}
+	return cs.Find(ctx, project.Name)
+}
+
 // Delete removes a project identified by project and name
 func (cs *GitProjectStore) Delete(ctx context.Context, project string) error {
 	err := cs.gitAdmin.DeleteProject(project)
 	if err != nil {
 		return errors.Wrap(err, "Deleting Project failed")
 	}
+	if err = cs.metadataStore.Delete(ctx, project); err != nil {
+		return errors.Wrap(err, "Deleting Project metadata failed")
+	}
+	return nil
+}
+
+// AddMember adds a user to a project with the given role, or changes the role of an existing member. The password
+// generated for the user is returned, if the git provider manages the user credentials and the user is new
+func (cs *GitProjectStore) AddMember(ctx context.Context, project string, user *domain.User) (*string, error) {
+	password, err := cs.gitAdmin.AddProjectMember(project, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "Adding Project member failed")
+	}
+	return password, nil
+}
+
+// RemoveMember removes a user from a project
+func (cs *GitProjectStore) RemoveMember(ctx context.Context, project, user string) error {
+	err := cs.gitAdmin.RemoveProjectMember(project, user)
+	if err != nil {
+		return errors.Wrap(err, "Removing Project member failed")
+	}
+	return nil
+}
+
+// addMetadata sets the project attributes kept by the metadata store
+func (cs *GitProjectStore) addMetadata(ctx context.Context, project *domain.Project) {
+	if metadata := cs.metadataStore.Find(ctx, project.Name); metadata != nil {
+		project.Labels = metadata.Labels
+		project.DefaultWorkflow = metadata.DefaultWorkflow
+		project.Quota = metadata.Quota
+	}
+}
+
+// validateProjectQuota checks that the resource caps of a project quota are valid Kubernetes quantities
+func validateProjectQuota(quota *domain.ProjectQuota) error {
+	if quota == nil {
+		return nil
+	}
+	if quota.MaxConcurrentRuns < 0 {
+		return fmt.Errorf("%w: the maximum number of concurrent runs cannot be negative", domain.ErrInvalidProjectQuota)
+	}
+	for name, value := range map[string]string{"CPU": quota.CPU, "memory": quota.Memory} {
+		if value == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(value); err != nil {
+			return fmt.Errorf("%w: %s %q is not a valid quantity", domain.ErrInvalidProjectQuota, name, value)
+		}
+	}
 	return nil
 }
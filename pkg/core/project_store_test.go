@@ -0,0 +1,61 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/fuseml/fuseml-core/pkg/core/gitprovider"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestProjectUpdate(t *testing.T) {
+	gitAdmin, err := gitprovider.NewLocalAdminClient(log.New(io.Discard, "", 0), t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := NewGitProjectStore(gitAdmin, NewProjectMetadataStore())
+	if _, err = store.Create(context.TODO(), "workspace", "Demo project"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := &domain.Project{
+		Name:            "workspace",
+		Description:     "Team A experiments",
+		Labels:          []string{"team-a"},
+		DefaultWorkflow: "mlflow-e2e",
+		Quota:           &domain.ProjectQuota{MaxConcurrentRuns: 2, CPU: "4", Memory: "8Gi"},
+	}
+	got, err := store.Update(context.TODO(), want)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Unexpected project (-want +got): %s", d)
+	}
+	projects, err := store.GetAll(context.TODO())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := cmp.Diff([]*domain.Project{want}, projects); d != "" {
+		t.Errorf("Unexpected projects (-want +got): %s", d)
+	}
+
+	for _, quota := range []*domain.ProjectQuota{{MaxConcurrentRuns: -1}, {CPU: "four"}, {Memory: "8GB!"}} {
+		_, err = store.Update(context.TODO(), &domain.Project{Name: "workspace", Quota: quota})
+		if !errors.Is(err, domain.ErrInvalidProjectQuota) {
+			t.Errorf("Expected %q error for %+v, got %v", domain.ErrInvalidProjectQuota, quota, err)
+		}
+	}
+
+	if err = store.Delete(context.TODO(), "workspace"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if metadata := store.metadataStore.Find(context.TODO(), "workspace"); metadata != nil {
+		t.Errorf("Expected the project metadata to be deleted, got %v", metadata)
+	}
+}
@@ -2,8 +2,6 @@ package core
 
 import (
 	"context"
-	"errors"
-	"regexp"
 	"time"
 
 	"github.com/fuseml/fuseml-core/pkg/domain"
@@ -13,57 +11,27 @@ import (
 // RunnableStore describes in memory store for runnables
 type RunnableStore struct {
 	items map[string]*domain.Runnable
+	// versions holds all the versions of each runnable, the last one being the current version
+	versions map[string][]*domain.Runnable
 }
 
 // NewRunnableStore creates and returns an in-memory runnable store instance
 func NewRunnableStore() *RunnableStore {
 	return &RunnableStore{
-		items: make(map[string]*domain.Runnable),
+		items:    make(map[string]*domain.Runnable),
+		versions: make(map[string][]*domain.Runnable),
 	}
 }
 
-const (
-	errRunnableExists = "a runnable with that ID already exists"
-)
-
 // Find returns a list of runnables matching the input query.
 // Runnables may be matched by id, kind or labels. Only runnables that match all the
 // supplied criteria will be returned.
 func (s *RunnableStore) Find(ctx context.Context, id string, kind string, labels map[string]string) (res []*domain.Runnable, err error) {
 	res = make([]*domain.Runnable, 0)
 
-RUNNABLES:
 	for _, r := range s.items {
-		// match ID query
-		if id != "" && r.ID != id {
-			// try matching as a regexp
-			if match, _ := regexp.Match(id, []byte(r.ID)); !match {
-				continue RUNNABLES
-			}
-		}
-		// match kind query
-		if kind != "" || r.Kind != kind {
-			// try matching as a regexp
-			if match, _ := regexp.Match(kind, []byte(r.Kind)); !match {
-				continue RUNNABLES
-			}
-		}
-		// match label query
-		for qLabelKey, qLabelValue := range labels {
-			rLabelValue, hasLabel := r.Labels[qLabelKey]
-			if !hasLabel {
-				// runnable label key doesn't match query
-				continue RUNNABLES
-			}
-			if qLabelValue == "" || qLabelValue == rLabelValue {
-				// empty query label value or exact match
-				continue
-			}
-			// try matching as a regexp
-			if match, _ := regexp.Match(qLabelValue, []byte(rLabelValue)); !match {
-				// runnable label value doesn't match query label regexp
-				continue RUNNABLES
-			}
+		if !r.Matches(id, kind, labels) {
+			continue
 		}
 
 		rMatch := &domain.Runnable{}
@@ -77,17 +45,63 @@ RUNNABLES:
 // Register adds a new runnable, based on the Runnable structure provided as argument
 func (s *RunnableStore) Register(ctx context.Context, r *domain.Runnable) (res *domain.Runnable, err error) {
 	if _, found := s.items[r.ID]; found {
-		return nil, errors.New(errRunnableExists)
+		return nil, domain.ErrRunnableExists
 	}
 	res = &domain.Runnable{}
 	// return a deep copy of the internal runnable
 	copier.Copy(&res, r)
 	res.Created = time.Now()
+	res.Updated = res.Created
+	res.Version = 1
 	s.items[res.ID] = res
+	s.versions[res.ID] = []*domain.Runnable{res}
 	return res, nil
 }
 
 // Get returns a runnable identified by id
 func (s *RunnableStore) Get(ctx context.Context, id string) (res *domain.Runnable, err error) {
-	return s.items[id], nil
+	r, found := s.items[id]
+	if !found {
+		return nil, domain.ErrRunnableNotFound
+	}
+	return r, nil
+}
+
+// GetVersion returns a version of the runnable identified by id
+func (s *RunnableStore) GetVersion(ctx context.Context, id string, version int) (res *domain.Runnable, err error) {
+	versions, found := s.versions[id]
+	if !found {
+		return nil, domain.ErrRunnableNotFound
+	}
+	if version < 1 || version > len(versions) {
+		return nil, domain.ErrRunnableVersionNotFound
+	}
+	return versions[version-1], nil
+}
+
+// Update replaces a runnable with a new version, based on the Runnable structure provided as argument
+func (s *RunnableStore) Update(ctx context.Context, r *domain.Runnable) (res *domain.Runnable, err error) {
+	current, found := s.items[r.ID]
+	if !found {
+		return nil, domain.ErrRunnableNotFound
+	}
+	res = &domain.Runnable{}
+	// return a deep copy of the internal runnable
+	copier.Copy(&res, r)
+	res.Created = current.Created
+	res.Updated = time.Now()
+	res.Version = current.Version + 1
+	s.items[res.ID] = res
+	s.versions[res.ID] = append(s.versions[res.ID], res)
+	return res, nil
+}
+
+// Delete removes a runnable identified by id, including all its versions
+func (s *RunnableStore) Delete(ctx context.Context, id string) error {
+	if _, found := s.items[id]; !found {
+		return domain.ErrRunnableNotFound
+	}
+	delete(s.items, id)
+	delete(s.versions, id)
+	return nil
 }
@@ -0,0 +1,49 @@
+package badger
+
+import (
+	"context"
+
+	"github.com/timshannon/badgerhold/v3"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// CodesetMetadataStore is a wrapper around a badgerhold.Store that implements the domain.CodesetMetadataStore
+// interface.
+type CodesetMetadataStore struct {
+	store *badgerhold.Store
+}
+
+// NewCodesetMetadataStore creates a new CodesetMetadataStore.
+func NewCodesetMetadataStore(store *badgerhold.Store) *CodesetMetadataStore {
+	return &CodesetMetadataStore{store: store}
+}
+
+// codesetMetadataKey returns the key under which the metadata of a codeset is stored
+func codesetMetadataKey(project, name string) string {
+	return project + "/" + name
+}
+
+// Find returns the metadata of a codeset, or nil if the codeset has none
+func (ms *CodesetMetadataStore) Find(ctx context.Context, project, name string) *domain.CodesetMetadata {
+	metadata := domain.CodesetMetadata{}
+	err := ms.store.Get(codesetMetadataKey(project, name), &metadata)
+	if err != nil {
+		return nil
+	}
+	return &metadata
+}
+
+// Save adds or replaces the metadata of a codeset
+func (ms *CodesetMetadataStore) Save(ctx context.Context, project, name string, metadata *domain.CodesetMetadata) error {
+	return ms.store.Upsert(codesetMetadataKey(project, name), metadata)
+}
+
+// Delete removes the metadata of a codeset
+func (ms *CodesetMetadataStore) Delete(ctx context.Context, project, name string) error {
+	err := ms.store.Delete(codesetMetadataKey(project, name), domain.CodesetMetadata{})
+	if err != nil && err != badgerhold.ErrNotFound {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package badger
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+	"github.com/timshannon/badgerhold/v3"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestCodesetMetadata(t *testing.T) {
+	store, done := newCodesetMetadataStore(t)
+	defer done()
+
+	if got := store.Find(context.TODO(), "workspace", "mlflow-app"); got != nil {
+		t.Errorf("Expected nil, got %v", got)
+	}
+
+	metadata := &domain.CodesetMetadata{
+		Commit:       "0b5e235",
+		Type:         []string{"code", "configuration"},
+		Format:       []string{"MLProject", "conda"},
+		Requirements: map[string]string{"mlflow": "", "scikit-learn": "0.22.3"},
+		Files:        []string{"MLproject", "conda.yaml"},
+		Labels:       []string{"mlflow", "conda"},
+	}
+	assertNoError(t, store.Save(context.TODO(), "workspace", "mlflow-app", metadata))
+	assertNoError(t, store.Save(context.TODO(), "workspace", "other-app", &domain.CodesetMetadata{Commit: "31ea04e"}))
+	metadata.Commit = "ca4724a"
+	assertNoError(t, store.Save(context.TODO(), "workspace", "mlflow-app", metadata))
+
+	got := store.Find(context.TODO(), "workspace", "mlflow-app")
+	if d := cmp.Diff(metadata, got); d != "" {
+		t.Errorf("Unexpected CodesetMetadata: %s", diff.PrintWantGot(d))
+	}
+
+	assertNoError(t, store.Delete(context.TODO(), "workspace", "mlflow-app"))
+	assertNoError(t, store.Delete(context.TODO(), "workspace", "mlflow-app"))
+	if got := store.Find(context.TODO(), "workspace", "mlflow-app"); got != nil {
+		t.Errorf("Expected nil, got %v", got)
+	}
+	if got := store.Find(context.TODO(), "workspace", "other-app"); got == nil || got.Commit != "31ea04e" {
+		t.Errorf("Expected the metadata of other codesets to be kept, got %v", got)
+	}
+}
+
+func newCodesetMetadataStore(t *testing.T) (*CodesetMetadataStore, func()) {
+	t.Helper()
+
+	dir := tmpDir(t)
+	opt := badgerhold.DefaultOptions
+	opt.Logger = nil
+	opt.Dir = dir
+	opt.ValueDir = dir
+
+	store, err := badgerhold.Open(opt)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+
+	return NewCodesetMetadataStore(store), func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+}
@@ -2,20 +2,43 @@ package badger
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
+	"github.com/dgraph-io/badger/v3"
+	"github.com/fuseml/fuseml-core/pkg/core/encryption"
 	"github.com/fuseml/fuseml-core/pkg/domain"
 	"github.com/timshannon/badgerhold/v3"
 )
 
+// encryptedConfigurationKey is the key of the only configuration entry kept for credentials stored encrypted,
+// holding the encrypted configuration
+const encryptedConfigurationKey = "fuseml/encrypted-configuration"
+
+const errNoEncryptionKeys = encryption.Err("no encryption keys configured")
+
 // ExtensionStore is a wrapper around a badgerhold.Store that implements the domain.ExtensionStore interface.
+// When a key provider is supplied, the configuration of the extension credentials is stored encrypted.
+// The health status of the extension endpoints is kept as endpointStatus records, so that recording the result
+// of a health check does not rewrite the whole extension, together with its credentials.
 type ExtensionStore struct {
 	store *badgerhold.Store
+	keys  encryption.KeyProvider
 }
 
-// NewExtensionStore creates a new ExtensionStore.
-func NewExtensionStore(store *badgerhold.Store) *ExtensionStore {
-	return &ExtensionStore{store: store}
+// endpointStatus is the health status of an extension endpoint
+type endpointStatus struct {
+	ExtensionID string
+	ServiceID   string
+	EndpointID  string
+	Status      *domain.ExtensionServiceEndpointStatus
+}
+
+// NewExtensionStore creates a new ExtensionStore. The key provider may be nil, in which case the extension
+// credentials are stored unencrypted.
+func NewExtensionStore(store *badgerhold.Store, keys encryption.KeyProvider) *ExtensionStore {
+	return &ExtensionStore{store: store, keys: keys}
 }
 
 // AddExtension adds a new extension to the store.
@@ -23,7 +46,11 @@ func (es *ExtensionStore) AddExtension(ctx context.Context, extension *domain.Ex
 	extension.EnsureID(ctx, es)
 	extension.SetCreated(ctx)
 
-	err := es.store.Insert(extension.ID, extension)
+	stored, err := es.encryptCredentials(extension)
+	if err != nil {
+		return nil, err
+	}
+	err = es.store.Insert(extension.ID, stored)
 	if err != nil {
 		return nil, domain.NewErrExtensionExists(extension.ID)
 	}
@@ -37,6 +64,14 @@ func (es *ExtensionStore) GetExtension(ctx context.Context, extensionID string)
 	if err != nil {
 		return nil, domain.NewErrExtensionNotFound(extensionID)
 	}
+	statuses := []*endpointStatus{}
+	if err = es.store.Find(&statuses, badgerhold.Where("ExtensionID").Eq(extensionID)); err != nil {
+		return nil, err
+	}
+	setEndpointStatuses(extension, statuses)
+	if err = es.decryptCredentials(extension); err != nil {
+		return nil, err
+	}
 	return extension, nil
 }
 
@@ -57,10 +92,11 @@ func (es *ExtensionStore) ListExtensions(ctx context.Context, query *domain.Exte
 			return
 		}
 
-		allExtensions := []*domain.Extension{}
-		es.store.Find(&allExtensions, nil)
+		allExtensions := es.findExtensions()
 
 		for _, extension := range allExtensions {
+			// credentials that cannot be decrypted are listed without their configuration
+			es.decryptCredentials(extension)
 			matchingExtension := extension.GetExtensionIfMatch(query)
 			if matchingExtension != nil {
 				result = append(result, matchingExtension)
@@ -69,10 +105,31 @@ func (es *ExtensionStore) ListExtensions(ctx context.Context, query *domain.Exte
 		return
 	}
 
-	es.store.Find(&result, nil)
+	result = es.findExtensions()
+	for _, extension := range result {
+		es.decryptCredentials(extension)
+	}
 	return
 }
 
+// findExtensions retrieves all stored extensions together with the health status of their endpoints, without
+// decrypting their credentials
+func (es *ExtensionStore) findExtensions() []*domain.Extension {
+	extensions := []*domain.Extension{}
+	es.store.Find(&extensions, nil)
+
+	statuses := []*endpointStatus{}
+	es.store.Find(&statuses, nil)
+	byExtension := map[string][]*endpointStatus{}
+	for _, s := range statuses {
+		byExtension[s.ExtensionID] = append(byExtension[s.ExtensionID], s)
+	}
+	for _, extension := range extensions {
+		setEndpointStatuses(extension, byExtension[extension.ID])
+	}
+	return extensions
+}
+
 // UpdateExtension updates an existing extension.
 func (es *ExtensionStore) UpdateExtension(ctx context.Context, newExtension *domain.Extension) error {
 	extension, err := es.GetExtension(ctx, newExtension.ID)
@@ -90,20 +147,42 @@ func (es *ExtensionStore) UpdateExtension(ctx context.Context, newExtension *dom
 		}
 	}
 
-	err = es.store.Update(newExtension.ID, newExtension)
+	stored, err := es.encryptCredentials(newExtension)
 	if err != nil {
-		return domain.NewErrExtensionNotFound(newExtension.ID)
+		return err
 	}
-	return nil
+	return es.store.Badger().Update(func(tx *badger.Txn) error {
+		if err := es.store.TxUpdate(tx, newExtension.ID, stored); err != nil {
+			return domain.NewErrExtensionNotFound(newExtension.ID)
+		}
+		// the status of the endpoints that were removed is removed as well
+		statuses := []*endpointStatus{}
+		if err := es.store.TxFind(tx, &statuses, badgerhold.Where("ExtensionID").Eq(newExtension.ID)); err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			if _, err := newExtension.GetServiceEndpoint(s.ServiceID, s.EndpointID); err != nil {
+				if err := es.store.TxDelete(tx, endpointStatusKey(s.ExtensionID, s.ServiceID, s.EndpointID), s); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
 }
 
-// DeleteExtension deletes an extension from the store.
+// DeleteExtension deletes an extension from the store, together with the health status of its endpoints.
 func (es *ExtensionStore) DeleteExtension(ctx context.Context, extensionID string) error {
 	extension, err := es.GetExtension(ctx, extensionID)
 	if err != nil {
 		return err
 	}
-	return es.store.Delete(extension.ID, extension)
+	return es.store.Badger().Update(func(tx *badger.Txn) error {
+		if err := es.store.TxDelete(tx, extension.ID, extension); err != nil {
+			return err
+		}
+		return es.store.TxDeleteMatching(tx, endpointStatus{}, badgerhold.Where("ExtensionID").Eq(extension.ID))
+	})
 }
 
 // AddExtensionService adds a new extension service to an extension.
@@ -231,6 +310,24 @@ func (es *ExtensionStore) UpdateExtensionServiceEndpoint(ctx context.Context, ex
 	return es.UpdateExtension(ctx, extension)
 }
 
+// SetExtensionServiceEndpointStatus records the result of a health check run against an extension endpoint.
+// Only the endpoint status is written, the extension itself, including the time when it was last updated and
+// its credentials, is left untouched.
+func (es *ExtensionStore) SetExtensionServiceEndpointStatus(ctx context.Context, extensionID string, serviceID string, endpointID string,
+	status *domain.ExtensionServiceEndpointStatus) error {
+	return es.store.Badger().Update(func(tx *badger.Txn) error {
+		extension := &domain.Extension{}
+		if err := es.store.TxGet(tx, extensionID, extension); err != nil {
+			return domain.NewErrExtensionNotFound(extensionID)
+		}
+		if err := extension.SetServiceEndpointStatus(serviceID, endpointID, status); err != nil {
+			return err
+		}
+		return es.store.TxUpsert(tx, endpointStatusKey(extensionID, serviceID, endpointID),
+			&endpointStatus{ExtensionID: extensionID, ServiceID: serviceID, EndpointID: endpointID, Status: status})
+	})
+}
+
 // DeleteExtensionServiceEndpoint deletes an extension endpoint from an extension service.
 func (es *ExtensionStore) DeleteExtensionServiceEndpoint(ctx context.Context, extensionID string, serviceID string, endpointID string) error {
 	extension, err := es.GetExtension(ctx, extensionID)
@@ -338,3 +435,123 @@ func (es *ExtensionStore) GetExtensionAccessDescriptors(ctx context.Context, que
 	}
 	return result, nil
 }
+
+// RotateCredentialsKey re-encrypts the configuration of all the stored extension credentials with the current
+// key of the key provider, returning the number of credentials that were not encrypted with it yet. Credentials
+// stored unencrypted, e.g. before a key provider was configured, are encrypted as well. After the rotation, the
+// previous keys are no longer needed and can be removed from the key provider.
+func (es *ExtensionStore) RotateCredentialsKey(ctx context.Context) (int, error) {
+	if es.keys == nil {
+		return 0, errNoEncryptionKeys
+	}
+	current, err := es.keys.CurrentKey()
+	if err != nil {
+		return 0, err
+	}
+	extensions := []*domain.Extension{}
+	if err := es.store.Find(&extensions, nil); err != nil {
+		return 0, err
+	}
+
+	rotated := 0
+	for _, extension := range extensions {
+		for _, service := range extension.Services {
+			for _, credentials := range service.Credentials {
+				if keyID, err := encryption.KeyID(credentials.Configuration[encryptedConfigurationKey]); err != nil || keyID != current.ID {
+					rotated++
+				}
+			}
+		}
+		if err := es.decryptCredentials(extension); err != nil {
+			return rotated, fmt.Errorf("error decrypting the credentials of extension %q: %w", extension.ID, err)
+		}
+		stored, err := es.encryptCredentials(extension)
+		if err != nil {
+			return rotated, err
+		}
+		if err := es.store.Update(extension.ID, stored); err != nil {
+			return rotated, fmt.Errorf("error storing extension %q: %w", extension.ID, err)
+		}
+	}
+	return rotated, nil
+}
+
+func endpointStatusKey(extensionID, serviceID, endpointID string) string {
+	return fmt.Sprintf("%s/%s/%s", extensionID, serviceID, endpointID)
+}
+
+// setEndpointStatuses sets the health status of the extension endpoints from their stored status. Statuses
+// recorded for endpoints that no longer exist are ignored.
+func setEndpointStatuses(extension *domain.Extension, statuses []*endpointStatus) {
+	for _, s := range statuses {
+		extension.SetServiceEndpointStatus(s.ServiceID, s.EndpointID, s.Status)
+	}
+}
+
+// encryptCredentials returns a copy of the extension where the configuration of the credentials is encrypted,
+// ready to be stored. The extension is returned as it is if no key provider is configured.
+func (es *ExtensionStore) encryptCredentials(extension *domain.Extension) (*domain.Extension, error) {
+	if es.keys == nil || extension.Services == nil {
+		return extension, nil
+	}
+	result := *extension
+	result.Services = make(map[string]*domain.ExtensionService, len(extension.Services))
+	for id, service := range extension.Services {
+		s := *service
+		result.Services[id] = &s
+		if service.Credentials == nil {
+			continue
+		}
+		s.Credentials = make(map[string]*domain.ExtensionServiceCredentials, len(service.Credentials))
+		for credentialsID, credentials := range service.Credentials {
+			c := *credentials
+			if len(c.Configuration) > 0 {
+				plaintext, err := json.Marshal(c.Configuration)
+				if err != nil {
+					return nil, err
+				}
+				encrypted, err := encryption.Encrypt(es.keys, plaintext)
+				if err != nil {
+					return nil, fmt.Errorf("error encrypting credentials %q: %w", c.ID, err)
+				}
+				c.Configuration = map[string]string{encryptedConfigurationKey: encrypted}
+			}
+			s.Credentials[credentialsID] = &c
+		}
+	}
+	return &result, nil
+}
+
+// decryptCredentials decrypts the configuration of the extension credentials read from the store. The
+// configuration of credentials that cannot be decrypted is removed and the first error is returned.
+func (es *ExtensionStore) decryptCredentials(extension *domain.Extension) (err error) {
+	for _, service := range extension.Services {
+		for _, credentials := range service.Credentials {
+			encrypted, ok := credentials.Configuration[encryptedConfigurationKey]
+			if !ok {
+				continue
+			}
+			configuration, cerr := es.decryptConfiguration(encrypted)
+			if cerr != nil && err == nil {
+				err = fmt.Errorf("error decrypting credentials %q: %w", credentials.ID, cerr)
+			}
+			credentials.Configuration = configuration
+		}
+	}
+	return
+}
+
+func (es *ExtensionStore) decryptConfiguration(encrypted string) (map[string]string, error) {
+	if es.keys == nil {
+		return nil, errNoEncryptionKeys
+	}
+	plaintext, err := encryption.Decrypt(es.keys, encrypted)
+	if err != nil {
+		return nil, err
+	}
+	configuration := map[string]string{}
+	if err = json.Unmarshal(plaintext, &configuration); err != nil {
+		return nil, err
+	}
+	return configuration, nil
+}
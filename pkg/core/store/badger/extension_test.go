@@ -2,9 +2,13 @@ package badger
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/fuseml/fuseml-core/pkg/core/encryption"
 	"github.com/fuseml/fuseml-core/pkg/domain"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -549,6 +553,123 @@ func TestUpdateExtensionServiceEndpoint(t *testing.T) {
 	})
 }
 
+func TestSetExtensionServiceEndpointStatus(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		store, done := newExtensionStore(t)
+		defer done()
+		ctx := context.Background()
+
+		ext, err := store.AddExtension(ctx, &domain.Extension{
+			Services: map[string]*domain.ExtensionService{"test-svc": {ID: "test-svc"}}})
+		assertNoError(t, err)
+		endpoint, err := store.AddExtensionServiceEndpoint(ctx, ext.ID, "test-svc", &domain.ExtensionServiceEndpoint{URL: "http://test"})
+		assertNoError(t, err)
+		_, err = store.AddExtensionServiceEndpoint(ctx, ext.ID, "test-svc", &domain.ExtensionServiceEndpoint{URL: "http://test2"})
+		assertNoError(t, err)
+		before, err := store.GetExtension(ctx, ext.ID)
+		assertNoError(t, err)
+
+		status := &domain.ExtensionServiceEndpointStatus{Health: domain.EEHUnhealthy, Message: "connection refused",
+			Latency: time.Millisecond, Checked: time.Now().Round(0)}
+		err = store.SetExtensionServiceEndpointStatus(ctx, ext.ID, "test-svc", endpoint.URL, status)
+		assertNoError(t, err)
+
+		got, err := store.GetExtensionServiceEndpoint(ctx, ext.ID, "test-svc", endpoint.URL)
+		assertNoError(t, err)
+		if d := cmp.Diff(status, got.Status); d != "" {
+			t.Errorf("Unexpected ExtensionServiceEndpointStatus: %s", diff.PrintWantGot(d))
+		}
+		after, err := store.GetExtension(ctx, ext.ID)
+		assertNoError(t, err)
+		if !after.Updated.Equal(before.Updated) {
+			t.Errorf("Expected the extension update time to be unchanged")
+		}
+
+		// updating the endpoint keeps its status
+		err = store.UpdateExtensionServiceEndpoint(ctx, ext.ID, "test-svc", &domain.ExtensionServiceEndpoint{URL: "http://test", Type: "external"})
+		assertNoError(t, err)
+		got, err = store.GetExtensionServiceEndpoint(ctx, ext.ID, "test-svc", endpoint.URL)
+		assertNoError(t, err)
+		if d := cmp.Diff(status, got.Status); d != "" {
+			t.Errorf("Unexpected ExtensionServiceEndpointStatus: %s", diff.PrintWantGot(d))
+		}
+
+		// unhealthy endpoints can be excluded from queries
+		descriptors, err := store.GetExtensionAccessDescriptors(ctx, &domain.ExtensionQuery{ExcludeUnhealthy: true})
+		assertNoError(t, err)
+		if len(descriptors) != 1 || descriptors[0].Endpoint.URL != "http://test2" {
+			t.Errorf("Expected only the healthy endpoint to be returned, got %v", descriptors)
+		}
+	})
+
+	t.Run("stored apart from the extension", func(t *testing.T) {
+		store, done := newExtensionStore(t)
+		defer done()
+		ctx := context.Background()
+
+		ext, err := store.AddExtension(ctx, &domain.Extension{
+			Services: map[string]*domain.ExtensionService{"test-svc": {ID: "test-svc",
+				Endpoints: map[string]*domain.ExtensionServiceEndpoint{"http://test": {URL: "http://test"}}}}})
+		assertNoError(t, err)
+		cred, err := store.AddExtensionServiceCredentials(ctx, ext.ID, "test-svc",
+			&domain.ExtensionServiceCredentials{Scope: domain.ECSGlobal, Configuration: map[string]string{"TOKEN": "secret-token"}})
+		assertNoError(t, err)
+		stored := func() *domain.Extension {
+			t.Helper()
+			e := &domain.Extension{}
+			if err := store.store.Get(ext.ID, e); err != nil {
+				t.Fatal(err)
+			}
+			return e
+		}
+		before := stored()
+
+		// the status is recorded without re-encrypting the credentials, even if they cannot be decrypted
+		store.keys = testKeyProvider(t, "key2")
+		status := &domain.ExtensionServiceEndpointStatus{Health: domain.EEHHealthy, Checked: time.Now().Round(0)}
+		err = store.SetExtensionServiceEndpointStatus(ctx, ext.ID, "test-svc", "http://test", status)
+		assertNoError(t, err)
+		if d := cmp.Diff(before, stored()); d != "" {
+			t.Errorf("Expected the stored extension to be unchanged: %s", diff.PrintWantGot(d))
+		}
+		store.keys = testKeyProvider(t, "key1")
+		got, err := store.GetExtensionServiceEndpoint(ctx, ext.ID, "test-svc", "http://test")
+		assertNoError(t, err)
+		if d := cmp.Diff(status, got.Status); d != "" {
+			t.Errorf("Unexpected ExtensionServiceEndpointStatus: %s", diff.PrintWantGot(d))
+		}
+		credentials, err := store.GetExtensionServiceCredentials(ctx, ext.ID, "test-svc", cred.ID)
+		assertNoError(t, err)
+		if token := credentials.Configuration["TOKEN"]; token != "secret-token" {
+			t.Errorf("Expected the credentials to be decrypted, got %q", token)
+		}
+
+		// the status is removed together with the endpoint
+		err = store.DeleteExtensionServiceEndpoint(ctx, ext.ID, "test-svc", "http://test")
+		assertNoError(t, err)
+		_, err = store.AddExtensionServiceEndpoint(ctx, ext.ID, "test-svc", &domain.ExtensionServiceEndpoint{URL: "http://test"})
+		assertNoError(t, err)
+		got, err = store.GetExtensionServiceEndpoint(ctx, ext.ID, "test-svc", "http://test")
+		assertNoError(t, err)
+		if got.Status != nil {
+			t.Errorf("Expected the status of the removed endpoint not to be kept, got %+v", got.Status)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		store, done := newExtensionStore(t)
+		defer done()
+		ctx := context.Background()
+
+		ext, err := store.AddExtension(ctx, &domain.Extension{
+			Services: map[string]*domain.ExtensionService{"test-svc": {ID: "test-svc"}}})
+		assertNoError(t, err)
+
+		err = store.SetExtensionServiceEndpointStatus(ctx, ext.ID, "test-svc", "http://test", &domain.ExtensionServiceEndpointStatus{})
+		assertErrorMessage(t, domain.NewErrExtensionServiceEndpointNotFound("", "test-svc", "http://test"), err)
+	})
+}
+
 func TestDeleteExtensionServiceEndpoint(t *testing.T) {
 	t.Run("found", func(t *testing.T) {
 		store, done := newExtensionStore(t)
@@ -846,6 +967,153 @@ func TestGetExtensionAccessDescriptors(t *testing.T) {
 	})
 }
 
+func TestExtensionCredentialsEncryption(t *testing.T) {
+	addCredentials := func(t *testing.T, store *ExtensionStore) (*domain.Extension, *domain.ExtensionServiceCredentials) {
+		t.Helper()
+		ext, err := store.AddExtension(context.Background(), &domain.Extension{
+			Services: map[string]*domain.ExtensionService{"test-svc": {ID: "test-svc",
+				Endpoints: map[string]*domain.ExtensionServiceEndpoint{"http://test": {URL: "http://test"}}}}})
+		assertNoError(t, err)
+		cred, err := store.AddExtensionServiceCredentials(context.Background(), ext.ID, "test-svc",
+			&domain.ExtensionServiceCredentials{Scope: domain.ECSGlobal, Configuration: map[string]string{"TOKEN": "secret-token"}})
+		assertNoError(t, err)
+		return ext, cred
+	}
+	// storedConfiguration returns the credentials configuration as it is stored
+	storedConfiguration := func(t *testing.T, store *ExtensionStore, extensionID, credentialsID string) map[string]string {
+		t.Helper()
+		ext := &domain.Extension{}
+		if err := store.store.Get(extensionID, ext); err != nil {
+			t.Fatal(err)
+		}
+		return ext.Services["test-svc"].Credentials[credentialsID].Configuration
+	}
+	assertStoredKey := func(t *testing.T, configuration map[string]string, keyID string) {
+		t.Helper()
+		if len(configuration) != 1 {
+			t.Fatalf("Expected the stored credentials configuration to be encrypted, got %v", configuration)
+		}
+		got, err := encryption.KeyID(configuration[encryptedConfigurationKey])
+		assertNoError(t, err)
+		if got != keyID {
+			t.Errorf("Expected the credentials to be encrypted with key %q, got %q", keyID, got)
+		}
+	}
+	wantConfiguration := map[string]string{"TOKEN": "secret-token"}
+
+	t.Run("encrypted at rest", func(t *testing.T) {
+		store, done := newExtensionStore(t)
+		defer done()
+		ctx := context.Background()
+		ext, cred := addCredentials(t, store)
+
+		assertStoredKey(t, storedConfiguration(t, store, ext.ID, cred.ID), "key1")
+
+		got, err := store.GetExtensionServiceCredentials(ctx, ext.ID, "test-svc", cred.ID)
+		assertNoError(t, err)
+		if d := cmp.Diff(wantConfiguration, got.Configuration); d != "" {
+			t.Errorf("Unexpected credentials configuration: %s", diff.PrintWantGot(d))
+		}
+		descriptors, err := store.GetExtensionAccessDescriptors(ctx, &domain.ExtensionQuery{ServiceID: "test-svc"})
+		assertNoError(t, err)
+		if len(descriptors) != 1 {
+			t.Fatalf("Expected 1 access descriptor, got %d", len(descriptors))
+		}
+		if d := cmp.Diff(wantConfiguration, descriptors[0].Credentials.Configuration); d != "" {
+			t.Errorf("Unexpected access descriptor credentials configuration: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("rotate key", func(t *testing.T) {
+		store, done := newExtensionStore(t)
+		defer done()
+		ctx := context.Background()
+		ext, cred := addCredentials(t, store)
+
+		store.keys = testKeyProvider(t, "key2", "key1")
+		rotated, err := store.RotateCredentialsKey(ctx)
+		assertNoError(t, err)
+		if rotated != 1 {
+			t.Errorf("Expected 1 rotated credentials, got %d", rotated)
+		}
+		assertStoredKey(t, storedConfiguration(t, store, ext.ID, cred.ID), "key2")
+
+		// the previous key is no longer needed
+		store.keys = testKeyProvider(t, "key2")
+		got, err := store.GetExtensionServiceCredentials(ctx, ext.ID, "test-svc", cred.ID)
+		assertNoError(t, err)
+		if d := cmp.Diff(wantConfiguration, got.Configuration); d != "" {
+			t.Errorf("Unexpected credentials configuration: %s", diff.PrintWantGot(d))
+		}
+		rotated, err = store.RotateCredentialsKey(ctx)
+		assertNoError(t, err)
+		if rotated != 0 {
+			t.Errorf("Expected no rotated credentials, got %d", rotated)
+		}
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		store, done := newExtensionStore(t)
+		defer done()
+		ctx := context.Background()
+		ext, cred := addCredentials(t, store)
+
+		store.keys = testKeyProvider(t, "key2")
+		_, err := store.GetExtension(ctx, ext.ID)
+		if !errors.Is(err, encryption.ErrKeyNotFound) {
+			t.Errorf("Expected error %q, got %v", encryption.ErrKeyNotFound, err)
+		}
+		_, err = store.RotateCredentialsKey(ctx)
+		if !errors.Is(err, encryption.ErrKeyNotFound) {
+			t.Errorf("Expected error %q, got %v", encryption.ErrKeyNotFound, err)
+		}
+		for _, e := range store.ListExtensions(ctx, nil) {
+			if c := e.Services["test-svc"].Credentials[cred.ID]; c.Configuration != nil {
+				t.Errorf("Expected credentials that cannot be decrypted to be listed without configuration, got %v", c.Configuration)
+			}
+		}
+	})
+
+	t.Run("encrypt unencrypted credentials", func(t *testing.T) {
+		store, done := newExtensionStore(t)
+		defer done()
+		ctx := context.Background()
+		store.keys = nil
+		ext, cred := addCredentials(t, store)
+		if d := cmp.Diff(wantConfiguration, storedConfiguration(t, store, ext.ID, cred.ID)); d != "" {
+			t.Errorf("Unexpected stored credentials configuration: %s", diff.PrintWantGot(d))
+		}
+
+		store.keys = testKeyProvider(t, "key1")
+		got, err := store.GetExtensionServiceCredentials(ctx, ext.ID, "test-svc", cred.ID)
+		assertNoError(t, err)
+		if d := cmp.Diff(wantConfiguration, got.Configuration); d != "" {
+			t.Errorf("Unexpected credentials configuration: %s", diff.PrintWantGot(d))
+		}
+		rotated, err := store.RotateCredentialsKey(ctx)
+		assertNoError(t, err)
+		if rotated != 1 {
+			t.Errorf("Expected 1 rotated credentials, got %d", rotated)
+		}
+		assertStoredKey(t, storedConfiguration(t, store, ext.ID, cred.ID), "key1")
+	})
+}
+
+// testKeyProvider returns a key provider with keys generated from their IDs, the first one being the current key
+func testKeyProvider(t *testing.T, ids ...string) encryption.KeyProvider {
+	t.Helper()
+
+	keys := []*encryption.Key{}
+	for _, id := range ids {
+		keys = append(keys, &encryption.Key{ID: id, Secret: []byte(fmt.Sprintf("%-32s", id))})
+	}
+	provider, err := encryption.NewStaticKeyProvider(keys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return provider
+}
+
 func newExtensionStore(t *testing.T) (*ExtensionStore, func()) {
 	t.Helper()
 
@@ -860,7 +1128,7 @@ func newExtensionStore(t *testing.T) (*ExtensionStore, func()) {
 		t.Fatalf("failed to open store: %v", err)
 	}
 
-	workflowStore := NewExtensionStore(store)
+	workflowStore := NewExtensionStore(store, testKeyProvider(t, "key1"))
 
 	return workflowStore, func() {
 		store.Close()
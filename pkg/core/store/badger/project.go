@@ -0,0 +1,43 @@
+package badger
+
+import (
+	"context"
+
+	"github.com/timshannon/badgerhold/v3"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// ProjectMetadataStore is a wrapper around a badgerhold.Store that implements the domain.ProjectMetadataStore interface.
+type ProjectMetadataStore struct {
+	store *badgerhold.Store
+}
+
+// NewProjectMetadataStore creates a new ProjectMetadataStore.
+func NewProjectMetadataStore(store *badgerhold.Store) *ProjectMetadataStore {
+	return &ProjectMetadataStore{store: store}
+}
+
+// Find returns the metadata of a project, or nil if the project has none
+func (ps *ProjectMetadataStore) Find(ctx context.Context, project string) *domain.ProjectMetadata {
+	metadata := domain.ProjectMetadata{}
+	err := ps.store.Get(project, &metadata)
+	if err != nil {
+		return nil
+	}
+	return &metadata
+}
+
+// Save adds or replaces the metadata of a project
+func (ps *ProjectMetadataStore) Save(ctx context.Context, metadata *domain.ProjectMetadata) error {
+	return ps.store.Upsert(metadata.Project, metadata)
+}
+
+// Delete removes the metadata of a project
+func (ps *ProjectMetadataStore) Delete(ctx context.Context, project string) error {
+	err := ps.store.Delete(project, domain.ProjectMetadata{})
+	if err != nil && err != badgerhold.ErrNotFound {
+		return err
+	}
+	return nil
+}
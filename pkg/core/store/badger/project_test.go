@@ -0,0 +1,63 @@
+package badger
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+	"github.com/timshannon/badgerhold/v3"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestProjectMetadata(t *testing.T) {
+	store, done := newProjectMetadataStore(t)
+	defer done()
+
+	if got := store.Find(context.TODO(), "workspace"); got != nil {
+		t.Errorf("Expected nil, got %v", got)
+	}
+
+	metadata := &domain.ProjectMetadata{
+		Project:         "workspace",
+		Labels:          []string{"team-a"},
+		DefaultWorkflow: "mlflow-e2e",
+		Quota:           &domain.ProjectQuota{MaxConcurrentRuns: 2, CPU: "4", Memory: "8Gi"},
+	}
+	assertNoError(t, store.Save(context.TODO(), metadata))
+	metadata.Quota = nil
+	assertNoError(t, store.Save(context.TODO(), metadata))
+
+	got := store.Find(context.TODO(), "workspace")
+	if d := cmp.Diff(metadata, got); d != "" {
+		t.Errorf("Unexpected ProjectMetadata: %s", diff.PrintWantGot(d))
+	}
+
+	assertNoError(t, store.Delete(context.TODO(), "workspace"))
+	assertNoError(t, store.Delete(context.TODO(), "workspace"))
+	if got := store.Find(context.TODO(), "workspace"); got != nil {
+		t.Errorf("Expected nil, got %v", got)
+	}
+}
+
+func newProjectMetadataStore(t *testing.T) (*ProjectMetadataStore, func()) {
+	t.Helper()
+
+	dir := tmpDir(t)
+	opt := badgerhold.DefaultOptions
+	opt.Logger = nil
+	opt.Dir = dir
+	opt.ValueDir = dir
+
+	store, err := badgerhold.Open(opt)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+
+	return NewProjectMetadataStore(store), func() {
+		store.Close()
+		os.RemoveAll(dir)
+	}
+}
@@ -0,0 +1,160 @@
+package badger
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/badger/v3"
+	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/timshannon/badgerhold/v3"
+)
+
+func init() {
+	// Runnable inputs and outputs are stored as interface values, their concrete types need to be
+	// registered so that they are encoded and decoded without losing information.
+	for _, arg := range []interface{}{
+		&domain.RunnableInputParameter{},
+		&domain.RunnableOutputParameter{},
+		&domain.RunnableInputArtifact{},
+		&domain.RunnableOutputArtifact{},
+		&domain.RunnableInputCodeset{},
+		&domain.RunnableOutputCodeset{},
+		&domain.RunnableInputModel{},
+		&domain.RunnableOutputModel{},
+		&domain.RunnableInputDataset{},
+		&domain.RunnableOutputDataset{},
+		&domain.RunnableInputRunnable{},
+		&domain.RunnableOutputRunnable{},
+	} {
+		gob.Register(arg)
+	}
+}
+
+// RunnableStore is a wrapper around a badgerhold.Store that implements the domain.RunnableStore interface.
+// The current version of each runnable is stored by its ID, while all its versions are kept as runnableVersion
+// records, so that previous versions can still be retrieved after the runnable is updated.
+type RunnableStore struct {
+	store *badgerhold.Store
+}
+
+// runnableVersion is an immutable version of a runnable
+type runnableVersion struct {
+	ID       string
+	Version  int
+	Runnable *domain.Runnable
+}
+
+// NewRunnableStore creates a new RunnableStore.
+func NewRunnableStore(store *badgerhold.Store) *RunnableStore {
+	return &RunnableStore{store: store}
+}
+
+// Find returns a list of runnables matching the input query.
+// Runnables may be matched by id, kind or labels. Only runnables that match all the
+// supplied criteria will be returned.
+func (rs *RunnableStore) Find(ctx context.Context, id string, kind string, labels map[string]string) ([]*domain.Runnable, error) {
+	result := []*domain.Runnable{}
+
+	// TODO: Replace with a badgerhold query.
+	runnables := []*domain.Runnable{}
+	err := rs.store.Find(&runnables, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range runnables {
+		if r.Matches(id, kind, labels) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+// Register adds a new runnable, based on the Runnable structure provided as argument
+func (rs *RunnableStore) Register(ctx context.Context, r *domain.Runnable) (*domain.Runnable, error) {
+	r.Created = time.Now()
+	r.Updated = r.Created
+	r.Version = 1
+	err := rs.store.Badger().Update(func(tx *badger.Txn) error {
+		if err := rs.store.TxInsert(tx, r.ID, r); err != nil {
+			return err
+		}
+		return rs.store.TxInsert(tx, versionKey(r.ID, r.Version), &runnableVersion{r.ID, r.Version, r})
+	})
+	if err != nil {
+		if err == badgerhold.ErrKeyExists {
+			return nil, domain.ErrRunnableExists
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// Get returns a runnable identified by id
+func (rs *RunnableStore) Get(ctx context.Context, id string) (*domain.Runnable, error) {
+	r := &domain.Runnable{}
+	err := rs.store.Get(id, r)
+	if err != nil {
+		if err == badgerhold.ErrNotFound {
+			return nil, domain.ErrRunnableNotFound
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetVersion returns a version of the runnable identified by id
+func (rs *RunnableStore) GetVersion(ctx context.Context, id string, version int) (*domain.Runnable, error) {
+	if _, err := rs.Get(ctx, id); err != nil {
+		return nil, err
+	}
+	rv := &runnableVersion{}
+	err := rs.store.Get(versionKey(id, version), rv)
+	if err != nil {
+		if err == badgerhold.ErrNotFound {
+			return nil, domain.ErrRunnableVersionNotFound
+		}
+		return nil, err
+	}
+	return rv.Runnable, nil
+}
+
+// Update replaces a runnable with a new version, based on the Runnable structure provided as argument
+func (rs *RunnableStore) Update(ctx context.Context, r *domain.Runnable) (*domain.Runnable, error) {
+	current, err := rs.Get(ctx, r.ID)
+	if err != nil {
+		return nil, err
+	}
+	r.Created = current.Created
+	r.Updated = time.Now()
+	r.Version = current.Version + 1
+	err = rs.store.Badger().Update(func(tx *badger.Txn) error {
+		if err := rs.store.TxUpdate(tx, r.ID, r); err != nil {
+			return err
+		}
+		return rs.store.TxInsert(tx, versionKey(r.ID, r.Version), &runnableVersion{r.ID, r.Version, r})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Delete removes a runnable identified by id, including all its versions
+func (rs *RunnableStore) Delete(ctx context.Context, id string) error {
+	err := rs.store.Badger().Update(func(tx *badger.Txn) error {
+		if err := rs.store.TxDelete(tx, id, domain.Runnable{}); err != nil {
+			return err
+		}
+		return rs.store.TxDeleteMatching(tx, runnableVersion{}, badgerhold.Where("ID").Eq(id))
+	})
+	if err == badgerhold.ErrNotFound {
+		return domain.ErrRunnableNotFound
+	}
+	return err
+}
+
+func versionKey(id string, version int) string {
+	return fmt.Sprintf("%s@%d", id, version)
+}
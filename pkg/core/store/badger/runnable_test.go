@@ -0,0 +1,362 @@
+package badger
+
+import (
+	"context"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/tektoncd/pipeline/test/diff"
+	"github.com/timshannon/badgerhold/v3"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+func TestRegisterRunnable(t *testing.T) {
+	t.Run("new", func(t *testing.T) {
+		store, done := newRunnableStore(t)
+		defer done()
+
+		r := newRunnable("mlflow-trainer", domain.RKTrainer, map[string]string{"framework": "mlflow"})
+		got, err := store.Register(context.TODO(), r)
+		assertNoError(t, err)
+		if got.Created.IsZero() {
+			t.Errorf("Expected runnable creation time to be set")
+		}
+	})
+
+	t.Run("existing", func(t *testing.T) {
+		store, done := newRunnableStore(t)
+		defer done()
+
+		_, err := store.Register(context.TODO(), newRunnable("mlflow-trainer", domain.RKTrainer, nil))
+		assertNoError(t, err)
+
+		_, got := store.Register(context.TODO(), newRunnable("mlflow-trainer", domain.RKPredictor, nil))
+		assertError(t, got, domain.ErrRunnableExists)
+	})
+}
+
+func TestGetRunnable(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		store, done := newRunnableStore(t)
+		defer done()
+
+		want, err := store.Register(context.TODO(), newRunnable("mlflow-trainer", domain.RKTrainer, nil))
+		assertNoError(t, err)
+
+		got, err := store.Get(context.TODO(), "mlflow-trainer")
+		assertNoError(t, err)
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected Runnable: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		store, done := newRunnableStore(t)
+		defer done()
+
+		_, got := store.Get(context.TODO(), "mlflow-trainer")
+		assertError(t, got, domain.ErrRunnableNotFound)
+	})
+
+	t.Run("after reopening the store", func(t *testing.T) {
+		dir := tmpDir(t)
+		defer os.RemoveAll(dir)
+
+		store := openRunnableStore(t, dir)
+		want, err := store.Register(context.TODO(), newRunnable("mlflow-trainer", domain.RKTrainer, nil))
+		assertNoError(t, err)
+		store.store.Close()
+
+		store = openRunnableStore(t, dir)
+		defer store.store.Close()
+
+		got, err := store.Get(context.TODO(), "mlflow-trainer")
+		assertNoError(t, err)
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected Runnable: %s", diff.PrintWantGot(d))
+		}
+	})
+}
+
+func TestUpdateRunnable(t *testing.T) {
+	t.Run("existing", func(t *testing.T) {
+		store, done := newRunnableStore(t)
+		defer done()
+
+		v1, err := store.Register(context.TODO(), newRunnable("mlflow-trainer", domain.RKTrainer, nil))
+		assertNoError(t, err)
+		v1 = copyRunnable(t, v1)
+
+		r := newRunnable("mlflow-trainer", domain.RKTrainer, map[string]string{"gpu": "true"})
+		r.Container.Image = "ghcr.io/fuseml/mlflow-trainer:v2"
+		v2, err := store.Update(context.TODO(), r)
+		assertNoError(t, err)
+		if v2.Version != 2 {
+			t.Errorf("Expected runnable version 2, got %d", v2.Version)
+		}
+		if !v2.Created.Equal(v1.Created) {
+			t.Errorf("Expected runnable creation time to be kept, got %s want %s", v2.Created, v1.Created)
+		}
+
+		got, err := store.Get(context.TODO(), "mlflow-trainer")
+		assertNoError(t, err)
+		if d := cmp.Diff(v2, got); d != "" {
+			t.Errorf("Unexpected Runnable: %s", diff.PrintWantGot(d))
+		}
+
+		for _, want := range []*domain.Runnable{v1, v2} {
+			got, err := store.GetVersion(context.TODO(), "mlflow-trainer", want.Version)
+			assertNoError(t, err)
+			if d := cmp.Diff(want, got); d != "" {
+				t.Errorf("Unexpected Runnable version %d: %s", want.Version, diff.PrintWantGot(d))
+			}
+		}
+
+		// updated runnables are matched by their latest version
+		res, err := store.Find(context.TODO(), "", "", map[string]string{"gpu": "true"})
+		assertNoError(t, err)
+		if len(res) != 1 || res[0].Version != 2 {
+			t.Errorf("Expected to find version 2 of the runnable, got %v", res)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		store, done := newRunnableStore(t)
+		defer done()
+
+		_, got := store.Update(context.TODO(), newRunnable("mlflow-trainer", domain.RKTrainer, nil))
+		assertError(t, got, domain.ErrRunnableNotFound)
+	})
+}
+
+func TestGetRunnableVersion(t *testing.T) {
+	store, done := newRunnableStore(t)
+	defer done()
+
+	_, err := store.Register(context.TODO(), newRunnable("mlflow-trainer", domain.RKTrainer, nil))
+	assertNoError(t, err)
+
+	t.Run("version not found", func(t *testing.T) {
+		_, got := store.GetVersion(context.TODO(), "mlflow-trainer", 2)
+		assertError(t, got, domain.ErrRunnableVersionNotFound)
+	})
+
+	t.Run("runnable not found", func(t *testing.T) {
+		_, got := store.GetVersion(context.TODO(), "mlflow-builder", 1)
+		assertError(t, got, domain.ErrRunnableNotFound)
+	})
+}
+
+func TestDeleteRunnable(t *testing.T) {
+	t.Run("existing", func(t *testing.T) {
+		store, done := newRunnableStore(t)
+		defer done()
+
+		_, err := store.Register(context.TODO(), newRunnable("mlflow-trainer", domain.RKTrainer, nil))
+		assertNoError(t, err)
+		_, err = store.Update(context.TODO(), newRunnable("mlflow-trainer", domain.RKTrainer, nil))
+		assertNoError(t, err)
+		_, err = store.Register(context.TODO(), newRunnable("mlflow-builder", domain.RKBuilder, nil))
+		assertNoError(t, err)
+
+		err = store.Delete(context.TODO(), "mlflow-trainer")
+		assertNoError(t, err)
+
+		_, got := store.Get(context.TODO(), "mlflow-trainer")
+		assertError(t, got, domain.ErrRunnableNotFound)
+		_, got = store.GetVersion(context.TODO(), "mlflow-trainer", 1)
+		assertError(t, got, domain.ErrRunnableNotFound)
+
+		// the other runnables are kept
+		_, err = store.GetVersion(context.TODO(), "mlflow-builder", 1)
+		assertNoError(t, err)
+
+		// the ID can be registered again, starting a new version history
+		r, err := store.Register(context.TODO(), newRunnable("mlflow-trainer", domain.RKTrainer, nil))
+		assertNoError(t, err)
+		if r.Version != 1 {
+			t.Errorf("Expected runnable version 1, got %d", r.Version)
+		}
+		_, got = store.GetVersion(context.TODO(), "mlflow-trainer", 2)
+		assertError(t, got, domain.ErrRunnableVersionNotFound)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		store, done := newRunnableStore(t)
+		defer done()
+
+		got := store.Delete(context.TODO(), "mlflow-trainer")
+		assertError(t, got, domain.ErrRunnableNotFound)
+	})
+}
+
+func TestFindRunnables(t *testing.T) {
+	store, done := newRunnableStore(t)
+	defer done()
+
+	runnables := []*domain.Runnable{
+		newRunnable("mlflow-builder", domain.RKBuilder, map[string]string{"framework": "mlflow"}),
+		newRunnable("mlflow-trainer", domain.RKTrainer, map[string]string{"framework": "mlflow", "gpu": "true"}),
+		newRunnable("kserve-predictor", domain.RKPredictor, map[string]string{"framework": "kserve", "gpu": "false"}),
+	}
+	for _, r := range runnables {
+		_, err := store.Register(context.TODO(), r)
+		assertNoError(t, err)
+	}
+
+	tests := []struct {
+		name   string
+		id     string
+		kind   string
+		labels map[string]string
+		want   []string
+	}{
+		{name: "all", want: []string{"kserve-predictor", "mlflow-builder", "mlflow-trainer"}},
+		{name: "by id", id: "mlflow-trainer", want: []string{"mlflow-trainer"}},
+		{name: "by id regexp", id: "^mlflow-", want: []string{"mlflow-builder", "mlflow-trainer"}},
+		{name: "by kind", kind: domain.RKPredictor, want: []string{"kserve-predictor"}},
+		{name: "by kind regexp", kind: "builder|trainer", want: []string{"mlflow-builder", "mlflow-trainer"}},
+		{name: "by label key", labels: map[string]string{"gpu": ""}, want: []string{"kserve-predictor", "mlflow-trainer"}},
+		{name: "by label value", labels: map[string]string{"gpu": "true"}, want: []string{"mlflow-trainer"}},
+		{name: "by label regexp", labels: map[string]string{"framework": "^ml"}, want: []string{"mlflow-builder", "mlflow-trainer"}},
+		{name: "all criteria", id: "mlflow", kind: domain.RKTrainer, labels: map[string]string{"gpu": "true"},
+			want: []string{"mlflow-trainer"}},
+		{name: "no match", id: "mlflow", kind: domain.RKPredictor, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := store.Find(context.TODO(), tt.id, tt.kind, tt.labels)
+			assertNoError(t, err)
+
+			got := []string{}
+			for _, r := range res {
+				got = append(got, r.ID)
+			}
+			sort.Strings(got)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("Unexpected Runnables: %s", diff.PrintWantGot(d))
+			}
+		})
+	}
+
+	t.Run("inputs and outputs", func(t *testing.T) {
+		res, err := store.Find(context.TODO(), "mlflow-trainer", "", nil)
+		assertNoError(t, err)
+		if len(res) != 1 {
+			t.Fatalf("Expected 1 Runnable, got %d", len(res))
+		}
+
+		want := newRunnable("mlflow-trainer", domain.RKTrainer, nil)
+		if d := cmp.Diff(want.Inputs, res[0].Inputs); d != "" {
+			t.Errorf("Unexpected Runnable inputs: %s", diff.PrintWantGot(d))
+		}
+		if d := cmp.Diff(want.Outputs, res[0].Outputs); d != "" {
+			t.Errorf("Unexpected Runnable outputs: %s", diff.PrintWantGot(d))
+		}
+	})
+}
+
+// newRunnable returns a runnable with an input and an output of every supported type
+func newRunnable(id string, kind string, labels map[string]string) *domain.Runnable {
+	argDesc := func(name string) domain.RunnableArtifactArgDesc {
+		return domain.RunnableArtifactArgDesc{
+			RunnableArgDesc: domain.RunnableArgDesc{Name: name, Labels: map[string]string{"type": name}},
+			Provider:        []domain.ArtifactProvider{domain.APFuseml, domain.APS3},
+			Dimension:       domain.RAADSingle,
+		}
+	}
+	return &domain.Runnable{
+		ID:          id,
+		Kind:        kind,
+		Description: "test runnable",
+		Container: domain.RunnableContainer{
+			Image:      "ghcr.io/fuseml/" + id,
+			Env:        map[string]string{"DEBUG": "true"},
+			Entrypoint: "/run",
+			Args:       []string{"--verbose"},
+		},
+		Inputs: map[string]interface{}{
+			"param": &domain.RunnableInputParameter{RunnableArgDesc: domain.RunnableArgDesc{Name: "param"},
+				Optional: true, DefaultValue: "1"},
+			"artifact": &domain.RunnableInputArtifact{RunnableArtifactArgDesc: argDesc("artifact"), Path: "/in"},
+			"codeset": &domain.RunnableInputCodeset{
+				RunnableInputArtifact:   domain.RunnableInputArtifact{RunnableArtifactArgDesc: argDesc("codeset")},
+				RunnableCodesetArtifact: domain.RunnableCodesetArtifact{Type: []string{"mlflow"}},
+			},
+			"model": &domain.RunnableInputModel{
+				RunnableInputArtifact: domain.RunnableInputArtifact{RunnableArtifactArgDesc: argDesc("model")},
+				RunnableModelArtifact: domain.RunnableModelArtifact{Format: []string{"onnx"}, Pretrained: true},
+			},
+			"dataset": &domain.RunnableInputDataset{
+				RunnableInputArtifact:   domain.RunnableInputArtifact{RunnableArtifactArgDesc: argDesc("dataset")},
+				RunnableDatasetArtifact: domain.RunnableDatasetArtifact{Format: []string{"csv"}},
+			},
+			"runnable": &domain.RunnableInputRunnable{
+				RunnableInputArtifact:    domain.RunnableInputArtifact{RunnableArtifactArgDesc: argDesc("runnable")},
+				RunnableRunnableArtifact: domain.RunnableRunnableArtifact{Kind: domain.RKBuilder},
+			},
+		},
+		Outputs: map[string]interface{}{
+			"param": &domain.RunnableOutputParameter{RunnableArgDesc: domain.RunnableArgDesc{Name: "param"},
+				Path: "/out/param"},
+			"artifact": &domain.RunnableOutputArtifact{RunnableArtifactArgDesc: argDesc("artifact"), Optional: true},
+			"codeset": &domain.RunnableOutputCodeset{
+				RunnableOutputArtifact:  domain.RunnableOutputArtifact{RunnableArtifactArgDesc: argDesc("codeset")},
+				RunnableCodesetArtifact: domain.RunnableCodesetArtifact{Requirements: map[string]string{"mlflow": "1.19"}},
+			},
+			"model": &domain.RunnableOutputModel{
+				RunnableOutputArtifact: domain.RunnableOutputArtifact{RunnableArtifactArgDesc: argDesc("model")},
+				RunnableModelArtifact:  domain.RunnableModelArtifact{Method: "supervised", Class: "regression"},
+			},
+			"dataset": &domain.RunnableOutputDataset{
+				RunnableOutputArtifact:  domain.RunnableOutputArtifact{RunnableArtifactArgDesc: argDesc("dataset")},
+				RunnableDatasetArtifact: domain.RunnableDatasetArtifact{Compression: []string{"gzip"}},
+			},
+			"runnable": &domain.RunnableOutputRunnable{
+				RunnableOutputArtifact:   domain.RunnableOutputArtifact{RunnableArtifactArgDesc: argDesc("runnable")},
+				RunnableRunnableArtifact: domain.RunnableRunnableArtifact{Kind: domain.RKPredictor},
+			},
+		},
+		DefaultInputPath:  "/inputs",
+		DefaultOutputPath: "/outputs",
+		Labels:            labels,
+	}
+}
+
+// copyRunnable returns a copy of a runnable, so that it is not changed when the runnable is updated
+func copyRunnable(t *testing.T, r *domain.Runnable) *domain.Runnable {
+	t.Helper()
+
+	c := *r
+	return &c
+}
+
+func newRunnableStore(t *testing.T) (*RunnableStore, func()) {
+	t.Helper()
+
+	dir := tmpDir(t)
+	runnableStore := openRunnableStore(t, dir)
+
+	return runnableStore, func() {
+		runnableStore.store.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func openRunnableStore(t *testing.T, dir string) *RunnableStore {
+	t.Helper()
+
+	opt := badgerhold.DefaultOptions
+	opt.Logger = nil
+	opt.Dir = dir
+	opt.ValueDir = dir
+
+	store, err := badgerhold.Open(opt)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	return NewRunnableStore(store)
+}
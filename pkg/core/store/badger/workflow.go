@@ -55,6 +55,18 @@ func (ws *WorkflowStore) AddWorkflow(ctx context.Context, w *domain.Workflow) (*
 	return w, nil
 }
 
+// UpdateWorkflow replaces an existing workflow with the Workflow structure provided as argument.
+func (ws *WorkflowStore) UpdateWorkflow(ctx context.Context, w *domain.Workflow) (*domain.Workflow, error) {
+	err := ws.store.Update(w.Name, w)
+	if err != nil {
+		if err == badgerhold.ErrNotFound {
+			return nil, domain.ErrWorkflowNotFound
+		}
+		return nil, err
+	}
+	return w, nil
+}
+
 // DeleteWorkflow deletes the workflow from the store.
 func (ws *WorkflowStore) DeleteWorkflow(ctx context.Context, name string) error {
 	wf := domain.Workflow{}
@@ -116,16 +128,17 @@ func (ws *WorkflowStore) GetAllCodesetAssignments(ctx context.Context, workflowN
 	return
 }
 
-// AddCodesetAssignment adds a codeset to the list of assigned codesets of a workflow if it does not already exists.
+// AddCodesetAssignment adds a codeset to the list of assigned codesets of a workflow if it does not already exists,
+// otherwise it updates the assignment filter.
 func (ws *WorkflowStore) AddCodesetAssignment(ctx context.Context, workflowName string, codeset *domain.Codeset,
-	webhookID *int64) ([]*domain.CodesetAssignment, error) {
+	webhookID *int64, filter *domain.CodesetAssignmentFilter) ([]*domain.CodesetAssignment, error) {
 	wf := domain.Workflow{}
 	err := ws.store.Get(workflowName, &wf)
 	if err != nil {
 		return nil, domain.ErrWorkflowNotFound
 	}
 
-	err = wf.AssignToCodeset(ctx, codeset, webhookID)
+	err = wf.AssignToCodeset(ctx, codeset, webhookID, filter)
 	if err != nil {
 		return nil, err
 	}
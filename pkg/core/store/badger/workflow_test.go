@@ -135,6 +135,36 @@ func TestAddWorkflow(t *testing.T) {
 	})
 }
 
+func TestUpdateWorkflow(t *testing.T) {
+	t.Run("existing", func(t *testing.T) {
+		store, done := newWorkflowStore(t)
+		defer done()
+
+		wf := domain.Workflow{Name: "test"}
+
+		_, err := store.AddWorkflow(context.TODO(), &wf)
+		assertNoError(t, err)
+
+		wf.Description = "updated"
+		_, err = store.UpdateWorkflow(context.TODO(), &wf)
+		assertNoError(t, err)
+
+		got, err := store.GetWorkflow(context.TODO(), "test")
+		assertNoError(t, err)
+		if d := cmp.Diff(&wf, got); d != "" {
+			t.Errorf("Unexpected Workflow: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		store, done := newWorkflowStore(t)
+		defer done()
+
+		_, err := store.UpdateWorkflow(context.TODO(), &domain.Workflow{Name: "test"})
+		assertError(t, err, domain.ErrWorkflowNotFound)
+	})
+}
+
 func TestDeleteWorkflow(t *testing.T) {
 	t.Run("existing", func(t *testing.T) {
 		store, done := newWorkflowStore(t)
@@ -175,7 +205,7 @@ func TestDeleteWorkflow(t *testing.T) {
 		}
 		webhookID := (int64)(10)
 
-		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID)
+		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
 
 		err = store.DeleteWorkflow(context.TODO(), wfName)
 		assertError(t, err, domain.ErrCannotDeleteAssignedWorkflow)
@@ -192,7 +222,7 @@ func TestAddCodesetAssignment(t *testing.T) {
 		}
 
 		webhookID := (int64)(10)
-		_, err := store.AddCodesetAssignment(context.TODO(), "", &cs, &webhookID)
+		_, err := store.AddCodesetAssignment(context.TODO(), "", &cs, &webhookID, nil)
 		assertError(t, err, domain.ErrWorkflowNotFound)
 	})
 
@@ -211,7 +241,7 @@ func TestAddCodesetAssignment(t *testing.T) {
 		}
 
 		webhookID := (int64)(10)
-		got, err := store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID)
+		got, err := store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
 		assertNoError(t, err)
 
 		want := []*domain.CodesetAssignment{{Codeset: &cs, WebhookID: &webhookID}}
@@ -236,7 +266,7 @@ func TestAddCodesetAssignment(t *testing.T) {
 
 		webhookID := (int64)(10)
 
-		got, err := store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID)
+		got, err := store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
 		assertNoError(t, err)
 
 		want := []*domain.CodesetAssignment{{Codeset: &cs, WebhookID: &webhookID}}
@@ -244,12 +274,43 @@ func TestAddCodesetAssignment(t *testing.T) {
 			t.Errorf("Unexpected Assignments: %s", diff.PrintWantGot(d))
 		}
 
-		got, err = store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID)
+		got, err = store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
 		assertNoError(t, err)
 		if d := cmp.Diff(want, got); d != "" {
 			t.Errorf("Unexpected Assignments: %s", diff.PrintWantGot(d))
 		}
 	})
+
+	t.Run("update filter", func(t *testing.T) {
+		store, done := newWorkflowStore(t)
+		defer done()
+
+		wfName := "test-wf"
+		wf := domain.Workflow{Name: wfName}
+
+		_, err := store.AddWorkflow(context.TODO(), &wf)
+		assertNoError(t, err)
+
+		cs := domain.Codeset{
+			Name: "test-cs",
+		}
+
+		webhookID := (int64)(10)
+		_, err = store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
+		assertNoError(t, err)
+
+		filter := &domain.CodesetAssignmentFilter{Branches: []string{"main", "release-*"}, Paths: []string{"src/**"}}
+		got, err := store.AddCodesetAssignment(context.TODO(), wfName, &cs, nil, filter)
+		assertNoError(t, err)
+
+		want := []*domain.CodesetAssignment{{Codeset: &cs, WebhookID: &webhookID, Filter: filter}}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected Assignments: %s", diff.PrintWantGot(d))
+		}
+		if d := cmp.Diff(want, store.GetCodesetAssignments(context.TODO(), wfName)); d != "" {
+			t.Errorf("Unexpected stored Assignments: %s", diff.PrintWantGot(d))
+		}
+	})
 }
 
 func TestGetCodesetAssignments(t *testing.T) {
@@ -296,7 +357,7 @@ func TestGetCodesetAssignments(t *testing.T) {
 		}
 
 		webhookID := (int64)(10)
-		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID)
+		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
 
 		got := store.GetCodesetAssignments(context.TODO(), wfName)
 		want := []*domain.CodesetAssignment{{Codeset: &cs, WebhookID: &webhookID}}
@@ -340,7 +401,7 @@ func TestGetAllCodesetAssignments(t *testing.T) {
 
 		webhookID := (int64)(10)
 
-		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID)
+		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
 
 		// with name
 		got := store.GetAllCodesetAssignments(context.TODO(), &wfName)
@@ -411,8 +472,8 @@ func TestDeleteCodesetAssignment(t *testing.T) {
 
 		webhookID := (int64)(10)
 
-		store.AddCodesetAssignment(context.TODO(), wfName, &cs1, &webhookID)
-		store.AddCodesetAssignment(context.TODO(), wfName, &cs2, &webhookID)
+		store.AddCodesetAssignment(context.TODO(), wfName, &cs1, &webhookID, nil)
+		store.AddCodesetAssignment(context.TODO(), wfName, &cs2, &webhookID, nil)
 
 		got, _ := store.DeleteCodesetAssignment(context.TODO(), wfName, &cs1)
 		want := []*domain.CodesetAssignment{{Codeset: &cs2, WebhookID: &webhookID}}
@@ -474,7 +535,7 @@ func TestGetCodesetAssignment(t *testing.T) {
 
 		webhookID := (int64)(10)
 
-		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID)
+		store.AddCodesetAssignment(context.TODO(), wfName, &cs, &webhookID, nil)
 
 		got, err := store.GetCodesetAssignment(context.TODO(), wfName, &cs)
 		assertNoError(t, err)
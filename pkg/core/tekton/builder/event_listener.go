@@ -1,7 +1,11 @@
 package builder
 
 import (
+	"encoding/json"
+	"sort"
+
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -42,3 +46,38 @@ func (b *EventListenerBuilder) TriggerBinding(templateName string, bindingsName
 		Bindings: bindings,
 	})
 }
+
+// FilteredTrigger adds a named EventListenerTrigger to the EventListener spec, with a CEL interceptor that only
+// lets through the events matching the filter expression, and with inline bindings for the params.
+func (b *EventListenerBuilder) FilteredTrigger(name, templateName, filter string, params map[string]string) {
+	paramNames := make([]string, 0, len(params))
+	for paramName := range params {
+		paramNames = append(paramNames, paramName)
+	}
+	sort.Strings(paramNames)
+	bindings := []*v1alpha1.TriggerSpecBinding{}
+	for _, paramName := range paramNames {
+		value := params[paramName]
+		bindings = append(bindings, &v1alpha1.TriggerSpecBinding{
+			Name:  paramName,
+			Value: &value,
+		})
+	}
+
+	// marshalling a string does not fail
+	rawFilter, _ := json.Marshal(filter)
+	b.EventListener.Spec.Triggers = append(b.EventListener.Spec.Triggers, v1alpha1.EventListenerTrigger{
+		Name: name,
+		Template: &v1alpha1.TriggerSpecTemplate{
+			Ref: &templateName,
+		},
+		Bindings: bindings,
+		Interceptors: []*v1alpha1.EventInterceptor{{
+			Ref: v1alpha1.InterceptorRef{Name: "cel"},
+			Params: []v1alpha1.InterceptorParams{{
+				Name:  "filter",
+				Value: apiextensionsv1.JSON{Raw: rawFilter},
+			}},
+		}},
+	})
+}
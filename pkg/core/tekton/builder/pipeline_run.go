@@ -102,3 +102,11 @@ func (b *PipelineRunBuilder) ResourceGit(name, url, revision string) {
 		},
 	})
 }
+
+// PriorityClass sets the PriorityClassName of the PodTemplate in the PipelineRun spec.
+func (b *PipelineRunBuilder) PriorityClass(name string) {
+	if b.PipelineRun.Spec.PodTemplate == nil {
+		b.PipelineRun.Spec.PodTemplate = &v1beta1.PodTemplate{}
+	}
+	b.PipelineRun.Spec.PodTemplate.PriorityClassName = &name
+}
@@ -24,6 +24,11 @@ func NewTaskSpecBuilder(name, image, command string) *TaskSpecBuilder {
 	return b
 }
 
+// Args sets the arguments of the TaskSpec step command.
+func (b *TaskSpecBuilder) Args(args []string) {
+	b.TaskSpec.Steps[0].Args = args
+}
+
 // Param adds a ParamSpec to the TaskSpec.
 func (b *TaskSpecBuilder) Param(name string) {
 	b.TaskSpec.Params = append(b.TaskSpec.Params, v1beta1.ParamSpec{
@@ -67,6 +72,20 @@ func (b *TaskSpecBuilder) Env(name, value string) {
 	})
 }
 
+// EnvFromSecret adds an environment variable to the TaskSpec step, taking its value from a secret key. The
+// variable is not set if the secret does not have the key.
+func (b *TaskSpecBuilder) EnvFromSecret(name, secretName, key string) {
+	optional := true
+	b.TaskSpec.Steps[0].Env = append(b.TaskSpec.Steps[0].Env, corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+			Key:                  key,
+			Optional:             &optional,
+		}},
+	})
+}
+
 // Image sets the image on the TaskSpec step.
 func (b *TaskSpecBuilder) Image(image string) {
 	b.TaskSpec.Steps[0].Image = image
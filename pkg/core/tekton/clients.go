@@ -7,6 +7,9 @@ import (
 	"github.com/tektoncd/pipeline/pkg/client/clientset/versioned/typed/pipeline/v1beta1"
 	triggersclient "github.com/tektoncd/triggers/pkg/client/clientset/versioned"
 	"github.com/tektoncd/triggers/pkg/client/clientset/versioned/typed/triggers/v1alpha1"
+	k8sclient "k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	schedulingv1 "k8s.io/client-go/kubernetes/typed/scheduling/v1"
 
 	"github.com/fuseml/fuseml-core/pkg/kubernetes"
 )
@@ -19,6 +22,11 @@ type clients struct {
 	TriggerTemplateClient v1alpha1.TriggerTemplateInterface
 	TriggerBindingClient  v1alpha1.TriggerBindingInterface
 	EventListenerClient   v1alpha1.EventListenerInterface
+	PodClient             corev1.PodInterface
+	SecretClient          corev1.SecretInterface
+	ResourceQuotaClient   corev1.ResourceQuotaInterface
+	LimitRangeClient      corev1.LimitRangeInterface
+	PriorityClassClient   schedulingv1.PriorityClassInterface
 }
 
 // NewClients instantiates and returns several clientsets required for making requests to
@@ -48,5 +56,15 @@ func newClients(namespace string) (*clients, error) {
 	c.TriggerBindingClient = cst.TriggersV1alpha1().TriggerBindings(namespace)
 	c.EventListenerClient = cst.TriggersV1alpha1().EventListeners(namespace)
 
+	kcs, err := k8sclient.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating kubernetes client set: %w", err)
+	}
+	c.PodClient = kcs.CoreV1().Pods(namespace)
+	c.SecretClient = kcs.CoreV1().Secrets(namespace)
+	c.ResourceQuotaClient = kcs.CoreV1().ResourceQuotas(namespace)
+	c.LimitRangeClient = kcs.CoreV1().LimitRanges(namespace)
+	c.PriorityClassClient = kcs.SchedulingV1().PriorityClasses()
+
 	return c, nil
 }
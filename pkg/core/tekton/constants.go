@@ -1,5 +1,7 @@
 package tekton
 
+import "time"
+
 const (
 	pipelineRunPrefix         = "fuseml-"
 	pipelineRunServiceAccount = "fuseml-workloads"
@@ -14,13 +16,21 @@ const (
 	codesetVersionParam       = "codeset-version"
 	codesetProjectParam       = "codeset-project"
 	codesetURLParam           = "codeset-url"
-	fuseMLRegistry            = "registry.fuseml-registry"
+	credentialsSecretParam    = "credentials-secret"
+	priorityClassParam        = "priority-class"
+	defaultCodesetVersion     = "main"
 	fuseMLRegistryLocal       = "127.0.0.1:30500"
 	imageParamName            = "IMAGE"
 	stepOutputVarName         = "TASK_RESULT"
 	inputsVarPrefix           = "FUSEML_"
 	envVarPrefix              = "FUSEML_ENV_"
 	stepDefaultCmd            = "run"
+	stepContainerPrefix       = "step-"
+	logsPollInterval          = 2 * time.Second
+	maxLogLineSize            = 1024 * 1024
+	defaultRequestsLimitRange = "fuseml-default-requests"
+	defaultCPURequest         = "100m"
+	defaultMemoryRequest      = "128Mi"
 
 	// LabelCodesetName is the label key for the codeset name
 	LabelCodesetName = "fuseml/codeset-name"
@@ -30,4 +40,11 @@ const (
 	LabelCodesetVersion = "fuseml/codeset-version"
 	// LabelWorkflowRef is the label key for the reference of the workflow
 	LabelWorkflowRef = "fuseml/workflow-ref"
+	// AnnotationUser is the annotation key for the user triggering a workflow run
+	AnnotationUser = "fuseml/user"
+	// AnnotationExtensions is the annotation key for the extensions the steps of a workflow run are bound to
+	AnnotationExtensions = "fuseml/extensions"
+	// AnnotationWorkflowExtensions is the annotation key for the extensions the steps of a workflow are bound to,
+	// which tekton copies to the pipeline runs created for the workflow
+	AnnotationWorkflowExtensions = "fuseml/workflow-extensions"
 )
@@ -0,0 +1,208 @@
+package tekton
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// StreamWorkflowRunLogs streams the logs from the step containers of the pods running the tasks of a tekton
+// pipeline run. When following the logs, the pipeline run is polled for new tasks until it completes.
+func (w *WorkflowBackend) StreamWorkflowRunLogs(ctx context.Context, runName string, options *domain.WorkflowRunLogOptions,
+	handler domain.WorkflowRunLogHandler) error {
+	if options == nil {
+		options = &domain.WorkflowRunLogOptions{}
+	}
+
+	pr, err := w.getPipelineRun(ctx, runName)
+	if err != nil {
+		return err
+	}
+
+	if options.Step != "" {
+		found, err := w.pipelineRunHasTask(ctx, pr, options.Step)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return domain.ErrWorkflowRunStepNotFound
+		}
+	}
+
+	// count the logged lines, so that it is possible to tell when there are no logs for the pipeline run
+	lines := 0
+	countingHandler := func(log *domain.WorkflowRunLog) error {
+		lines++
+		return handler(log)
+	}
+
+	// keep track of the pods that had their logs streamed, a task may run on multiple pods when retried
+	streamed := map[string]bool{}
+	for {
+		for _, tr := range sortedTaskRuns(pr) {
+			if options.Step != "" && tr.PipelineTaskName != options.Step {
+				continue
+			}
+			// the pod running the task may not have been created yet
+			if tr.Status == nil || tr.Status.PodName == "" || streamed[tr.Status.PodName] {
+				continue
+			}
+			err := w.streamPodLogs(ctx, tr.PipelineTaskName, tr.Status.PodName, options.Follow, countingHandler)
+			if err != nil {
+				return err
+			}
+			streamed[tr.Status.PodName] = true
+		}
+
+		if !options.Follow || pr.IsDone() {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(logsPollInterval):
+		}
+
+		pr, err = w.getPipelineRun(ctx, runName)
+		if err != nil {
+			return err
+		}
+	}
+
+	if lines == 0 {
+		return domain.ErrWorkflowRunLogsNotFound
+	}
+	return nil
+}
+
+// streamPodLogs streams the logs from the step containers of a pod, in the order they are executed
+func (w *WorkflowBackend) streamPodLogs(ctx context.Context, step, podName string, follow bool,
+	handler domain.WorkflowRunLogHandler) error {
+	pod, err := w.tektonClients.PodClient.Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		// the pod may have been deleted after the task completed
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error getting pod %q: %w", podName, err)
+	}
+
+	for _, c := range pod.Spec.Containers {
+		if !strings.HasPrefix(c.Name, stepContainerPrefix) {
+			continue
+		}
+		if follow {
+			pod, err = w.waitForContainer(ctx, podName, c.Name)
+			if err != nil {
+				return err
+			}
+		}
+		// there are no logs for containers that did not start, e.g. when a previous step failed
+		if !containerStarted(pod, c.Name) {
+			continue
+		}
+		err = w.streamContainerLogs(ctx, step, podName, c.Name, follow, handler)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *WorkflowBackend) streamContainerLogs(ctx context.Context, step, podName, container string, follow bool,
+	handler domain.WorkflowRunLogHandler) error {
+	req := w.tektonClients.PodClient.GetLogs(podName, &corev1.PodLogOptions{Container: container, Follow: follow})
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("error streaming logs from container %q of pod %q: %w", container, podName, err)
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLogLineSize)
+	for scanner.Scan() {
+		err = handler(&domain.WorkflowRunLog{Step: step, Container: container, Line: scanner.Text()})
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// waitForContainer waits until a pod container starts or the pod completes, returning the updated pod
+func (w *WorkflowBackend) waitForContainer(ctx context.Context, podName, container string) (pod *corev1.Pod, err error) {
+	err = wait.PollImmediateUntil(logsPollInterval, func() (bool, error) {
+		pod, err = w.tektonClients.PodClient.Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("error getting pod %q: %w", podName, err)
+		}
+		return containerStarted(pod, container) || pod.Status.Phase == corev1.PodSucceeded ||
+			pod.Status.Phase == corev1.PodFailed, nil
+	}, ctx.Done())
+	return
+}
+
+// pipelineRunHasTask checks whether the pipeline executed by a pipeline run has a task with the specified name
+func (w *WorkflowBackend) pipelineRunHasTask(ctx context.Context, pr *v1beta1.PipelineRun, name string) (bool, error) {
+	spec := pr.Status.PipelineSpec
+	if spec == nil && pr.Spec.PipelineRef != nil {
+		p, err := w.tektonClients.PipelineClient.Get(ctx, pr.Spec.PipelineRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("error getting tekton pipeline %q: %w", pr.Spec.PipelineRef.Name, err)
+		}
+		spec = &p.Spec
+	}
+	if spec == nil {
+		return false, nil
+	}
+	for _, task := range spec.Tasks {
+		if task.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// sortedTaskRuns returns the status of the task runs from a pipeline run sorted by their start time
+func sortedTaskRuns(pr *v1beta1.PipelineRun) []*v1beta1.PipelineRunTaskRunStatus {
+	taskRuns := make([]*v1beta1.PipelineRunTaskRunStatus, 0, len(pr.Status.TaskRuns))
+	for _, tr := range pr.Status.TaskRuns {
+		taskRuns = append(taskRuns, tr)
+	}
+	sort.SliceStable(taskRuns, func(i, j int) bool {
+		si, sj := taskRunStartTime(taskRuns[i]), taskRunStartTime(taskRuns[j])
+		if si.Equal(sj) {
+			return taskRuns[i].PipelineTaskName < taskRuns[j].PipelineTaskName
+		}
+		return si.Before(sj)
+	})
+	return taskRuns
+}
+
+func taskRunStartTime(tr *v1beta1.PipelineRunTaskRunStatus) time.Time {
+	if tr.Status == nil || tr.Status.StartTime == nil {
+		return time.Time{}
+	}
+	return tr.Status.StartTime.Time
+}
+
+func containerStarted(pod *corev1.Pod, container string) bool {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == container {
+			return cs.State.Running != nil || cs.State.Terminated != nil
+		}
+	}
+	return false
+}
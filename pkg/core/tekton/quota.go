@@ -0,0 +1,181 @@
+package tekton
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fuseml/fuseml-core/pkg/domain"
+)
+
+// ApplyProjectQuota limits the resources used by the pipeline runs of a project. As the pipeline runs of all
+// projects share the same namespace, the pods of each project are assigned a priority class of their own, which
+// scopes the resource quota of the project, including the pods of the pipeline runs triggered by the event
+// listeners. Besides the CPU and memory requests, the resource quota limits the number of pods, which caps the
+// pipeline runs executing at the same time as the steps of a pipeline run are executed one after the other.
+// A resource quota on requests is only accepted for pods that set them, so a limit range sets the default
+// requests of the containers in the namespace.
+func (w *WorkflowBackend) ApplyProjectQuota(ctx context.Context, project string, quota *domain.ProjectQuota) error {
+	name := projectQuotaName(w.namespace, project)
+	if err := w.ensureProjectPriorityClass(ctx, project); err != nil {
+		return err
+	}
+	if quota == nil || (!quota.HasResourceCaps() && quota.MaxConcurrentRuns <= 0) {
+		return w.deleteProjectQuota(ctx, name)
+	}
+
+	hard := corev1.ResourceList{}
+	for resourceName, value := range map[corev1.ResourceName]string{
+		corev1.ResourceRequestsCPU: quota.CPU, corev1.ResourceRequestsMemory: quota.Memory} {
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return fmt.Errorf("%w: %s %q is not a valid quantity", domain.ErrInvalidProjectQuota, resourceName, value)
+		}
+		hard[resourceName] = quantity
+	}
+	if quota.MaxConcurrentRuns > 0 {
+		hard[corev1.ResourcePods] = *resource.NewQuantity(int64(quota.MaxConcurrentRuns), resource.DecimalSI)
+	}
+
+	if quota.HasResourceCaps() {
+		if err := w.ensureDefaultRequests(ctx); err != nil {
+			return err
+		}
+	}
+
+	rq, err := w.tektonClients.ResourceQuotaClient.Get(ctx, name, metav1.GetOptions{})
+	if k8serr.IsNotFound(err) {
+		w.logger.Printf("Creating resource quota for project: %s...", project)
+		rq = &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: w.namespace},
+			Spec: corev1.ResourceQuotaSpec{
+				Hard: hard,
+				ScopeSelector: &corev1.ScopeSelector{MatchExpressions: []corev1.ScopedResourceSelectorRequirement{{
+					ScopeName: corev1.ResourceQuotaScopePriorityClass,
+					Operator:  corev1.ScopeSelectorOpIn,
+					Values:    []string{name},
+				}}},
+			},
+		}
+		if _, err = w.tektonClients.ResourceQuotaClient.Create(ctx, rq, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error creating resource quota %q: %w", name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error getting resource quota %q: %w", name, err)
+	}
+	if equalResourceLists(rq.Spec.Hard, hard) {
+		return nil
+	}
+	w.logger.Printf("Updating resource quota for project: %s...", project)
+	rq.Spec.Hard = hard
+	if _, err = w.tektonClients.ResourceQuotaClient.Update(ctx, rq, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating resource quota %q: %w", name, err)
+	}
+	return nil
+}
+
+// setProjectPriorityClass assigns the pods of a pipeline run to the priority class of its project
+func (w *WorkflowBackend) setProjectPriorityClass(ctx context.Context, pr *v1beta1.PipelineRun, project string) error {
+	if err := w.ensureProjectPriorityClass(ctx, project); err != nil {
+		return err
+	}
+	name := projectQuotaName(w.namespace, project)
+	if pr.Spec.PodTemplate == nil {
+		pr.Spec.PodTemplate = &v1beta1.PodTemplate{}
+	}
+	pr.Spec.PodTemplate.PriorityClassName = &name
+	return nil
+}
+
+// ensureProjectPriorityClass creates the priority class of a project, if it does not exist. The priority class
+// is kept when the project quota is removed, as the event listener triggers keep referencing it.
+func (w *WorkflowBackend) ensureProjectPriorityClass(ctx context.Context, project string) error {
+	name := projectQuotaName(w.namespace, project)
+	_, err := w.tektonClients.PriorityClassClient.Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !k8serr.IsNotFound(err) {
+		return fmt.Errorf("error getting priority class %q: %w", name, err)
+	}
+	preemptionPolicy := corev1.PreemptNever
+	priorityClass := &schedulingv1.PriorityClass{
+		ObjectMeta:       metav1.ObjectMeta{Name: name},
+		Description:      fmt.Sprintf("Workflow runs of the FuseML project %s", project),
+		PreemptionPolicy: &preemptionPolicy,
+	}
+	_, err = w.tektonClients.PriorityClassClient.Create(ctx, priorityClass, metav1.CreateOptions{})
+	if err != nil && !k8serr.IsAlreadyExists(err) {
+		return fmt.Errorf("error creating priority class %q: %w", name, err)
+	}
+	return nil
+}
+
+// deleteProjectQuota deletes the resource quota of a project, if it exists
+func (w *WorkflowBackend) deleteProjectQuota(ctx context.Context, name string) error {
+	err := w.tektonClients.ResourceQuotaClient.Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !k8serr.IsNotFound(err) {
+		return fmt.Errorf("error deleting resource quota %q: %w", name, err)
+	}
+	if err == nil {
+		w.logger.Printf("Deleted resource quota: %s", name)
+	}
+	return nil
+}
+
+// ensureDefaultRequests creates the limit range setting the default CPU and memory requests of the containers
+// in the namespace, if it does not exist
+func (w *WorkflowBackend) ensureDefaultRequests(ctx context.Context) error {
+	_, err := w.tektonClients.LimitRangeClient.Get(ctx, defaultRequestsLimitRange, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !k8serr.IsNotFound(err) {
+		return fmt.Errorf("error getting limit range %q: %w", defaultRequestsLimitRange, err)
+	}
+	lr := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: defaultRequestsLimitRange, Namespace: w.namespace},
+		Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+			Type: corev1.LimitTypeContainer,
+			DefaultRequest: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(defaultCPURequest),
+				corev1.ResourceMemory: resource.MustParse(defaultMemoryRequest),
+			},
+		}}},
+	}
+	_, err = w.tektonClients.LimitRangeClient.Create(ctx, lr, metav1.CreateOptions{})
+	if err != nil && !k8serr.IsAlreadyExists(err) {
+		return fmt.Errorf("error creating limit range %q: %w", defaultRequestsLimitRange, err)
+	}
+	return nil
+}
+
+// projectQuotaName returns the name of the resource quota and priority class of a project. Priority classes are
+// not namespaced, so the name includes the namespace of the pipeline runs.
+func projectQuotaName(namespace, project string) string {
+	return strings.ToLower(strings.ReplaceAll(fmt.Sprintf("%s-%s", namespace, project), "_", "-"))
+}
+
+func equalResourceLists(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, quantity := range a {
+		if other, ok := b[name]; !ok || quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
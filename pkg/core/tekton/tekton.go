@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ghodss/yaml"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -20,6 +23,7 @@ import (
 	"knative.dev/pkg/apis"
 
 	"github.com/fuseml/fuseml-core/pkg/core/tekton/builder"
+	"github.com/fuseml/fuseml-core/pkg/core/variables"
 	"github.com/fuseml/fuseml-core/pkg/domain"
 	"github.com/fuseml/fuseml-core/pkg/util"
 )
@@ -67,10 +71,65 @@ func (w *WorkflowBackend) CreateWorkflow(ctx context.Context, workflow *domain.W
 		}
 		return fmt.Errorf("error creating tekton pipeline for workflow %q: %w", workflow.Name, err)
 	}
+	return nil
+}
+
+// UpdateWorkflow updates the tekton pipeline generated for the workflow and, if the workflow has a listener,
+// the trigger template and binding generated from the pipeline
+func (w *WorkflowBackend) UpdateWorkflow(ctx context.Context, workflow *domain.Workflow) error {
+	pipeline := generatePipeline(*workflow, w.namespace)
+	current, err := w.tektonClients.PipelineClient.Get(ctx, workflow.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return domain.ErrWorkflowNotFound
+		}
+		return fmt.Errorf("error getting tekton pipeline %q: %w", workflow.Name, err)
+	}
+	pipeline.ResourceVersion = current.ResourceVersion
+	w.logger.Printf("Updating tekton pipeline for workflow: %s...", workflow.Name)
+	if _, err = w.tektonClients.PipelineClient.Update(ctx, pipeline, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating tekton pipeline for workflow %q: %w", workflow.Name, err)
+	}
+
+	triggerTemplate := generateTriggerTemplate(pipeline)
+	tt, err := w.tektonClients.TriggerTemplateClient.Get(ctx, workflow.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error getting tekton trigger template %q: %w", workflow.Name, err)
+	}
+	tt.Spec = triggerTemplate.Spec
+	w.logger.Printf("Updating tekton trigger template for workflow: %s...", workflow.Name)
+	if _, err = w.tektonClients.TriggerTemplateClient.Update(ctx, tt, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating tekton trigger template %q: %w", workflow.Name, err)
+	}
 
+	tb, err := w.tektonClients.TriggerBindingClient.Get(ctx, workflow.Name, metav1.GetOptions{})
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error getting tekton trigger binding %q: %w", workflow.Name, err)
+	}
+	tb.Spec = generateTriggerBinding(triggerTemplate).Spec
+	if _, err = w.tektonClients.TriggerBindingClient.Update(ctx, tb, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating tekton trigger binding %q: %w", workflow.Name, err)
+	}
 	return nil
 }
 
+// RenderWorkflow returns the tekton pipeline generated for the workflow as YAML, without creating it
+func (w *WorkflowBackend) RenderWorkflow(ctx context.Context, workflow *domain.Workflow) (string, error) {
+	pipeline := generatePipeline(*workflow, w.namespace)
+	pipeline.TypeMeta = metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: "Pipeline"}
+	out, err := yaml.Marshal(pipeline)
+	if err != nil {
+		return "", fmt.Errorf("error rendering tekton pipeline for workflow %q: %w", workflow.Name, err)
+	}
+	return string(out), nil
+}
+
 // DeleteWorkflow deletes a tekton pipeline with the specified name
 func (w *WorkflowBackend) DeleteWorkflow(ctx context.Context, name string) error {
 	w.logger.Printf("Deleting tekton pipeline: %s...", name)
@@ -81,27 +140,32 @@ func (w *WorkflowBackend) DeleteWorkflow(ctx context.Context, name string) error
 		}
 		w.logger.Printf("Tekton pipeline %q not found, skipping delete...", name)
 	}
-	return nil
+	return w.deleteCredentialsSecrets(ctx, name)
 }
 
-// CreateWorkflowRun creates a PipelineRun with its default values for the specified workflow and codeset
-func (w *WorkflowBackend) CreateWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset) error {
+// CreateWorkflowRun creates a PipelineRun for the specified workflow and codeset. The PipelineRun uses the
+// codeset revision and input values set on the run options, falling back to their default values.
+func (w *WorkflowBackend) CreateWorkflowRun(ctx context.Context, workflowName string, codeset *domain.Codeset,
+	options *domain.WorkflowRunOptions) (string, error) {
 	pipeline, err := w.tektonClients.PipelineClient.Get(ctx, workflowName, metav1.GetOptions{})
 	if err != nil {
-		return fmt.Errorf("error getting tekton pipeline %q: %w", workflowName, err)
+		return "", fmt.Errorf("error getting tekton pipeline %q: %w", workflowName, err)
 	}
 
-	pipelineRun, err := generatePipelineRun(pipeline, codeset)
+	pipelineRun, err := generatePipelineRun(pipeline, codeset, options)
 	if err != nil {
-		return fmt.Errorf("error generating tekton pipeline run for workflow %q: %w", workflowName, err)
+		return "", fmt.Errorf("error generating tekton pipeline run for workflow %q: %w", workflowName, err)
+	}
+	if err = w.setProjectPriorityClass(ctx, pipelineRun, codeset.Project); err != nil {
+		return "", err
 	}
 
 	w.logger.Printf("Creating tekton pipeline run for workflow: %s...", workflowName)
-	_, err = w.tektonClients.PipelineRunClient.Create(ctx, pipelineRun, metav1.CreateOptions{})
+	pr, err := w.tektonClients.PipelineRunClient.Create(ctx, pipelineRun, metav1.CreateOptions{})
 	if err != nil {
-		return fmt.Errorf("error creating tekton pipeline run %q: %w", pipelineRun.Name, err)
+		return "", fmt.Errorf("error creating tekton pipeline run %q: %w", pipelineRun.Name, err)
 	}
-	return nil
+	return pr.Name, nil
 }
 
 // GetWorkflowRuns returns a list of WorkflowRun for the given Workflow
@@ -113,6 +177,9 @@ func (w *WorkflowBackend) GetWorkflowRuns(ctx context.Context, wf *domain.Workfl
 	if filter.CodesetProject != "" {
 		labelSelector = fmt.Sprintf("%s,%s=%s", labelSelector, LabelCodesetProject, filter.CodesetProject)
 	}
+	if filter.CodesetVersion != "" {
+		labelSelector = fmt.Sprintf("%s,%s=%s", labelSelector, LabelCodesetVersion, filter.CodesetVersion)
+	}
 	runs, err := w.tektonClients.PipelineRunClient.List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
 	if err != nil {
 		return nil, fmt.Errorf("error getting tekton pipeline run %q: %w", wf.Name, err)
@@ -134,6 +201,118 @@ func (w *WorkflowBackend) GetWorkflowRuns(ctx context.Context, wf *domain.Workfl
 	return workflowRuns, nil
 }
 
+// GetWorkflowRun returns the WorkflowRun for the tekton pipeline run with the specified name, including the
+// status of the tasks executed by the pipeline run
+func (w *WorkflowBackend) GetWorkflowRun(ctx context.Context, runName string) (*domain.WorkflowRun, error) {
+	pr, err := w.getPipelineRun(ctx, runName)
+	if err != nil {
+		return nil, err
+	}
+
+	// build the workflow inputs and outputs from the pipeline run, the codeset input is represented
+	// by the git resource while the other inputs are pipeline run parameters
+	wf := &domain.Workflow{Name: pr.Labels[LabelWorkflowRef]}
+	if len(pr.Spec.Resources) > 0 {
+		wf.Inputs = append(wf.Inputs, &domain.WorkflowInput{Type: domain.WorkflowIOTypeCodeset})
+	}
+	for _, param := range pr.Spec.Params {
+		if !isCodesetParam(param.Name) && param.Name != credentialsSecretParam {
+			wf.Inputs = append(wf.Inputs, &domain.WorkflowInput{Name: param.Name, Type: domain.WorkflowIOTypeString})
+		}
+	}
+	for _, result := range pr.Status.PipelineResults {
+		wf.Outputs = append(wf.Outputs, &domain.WorkflowOutput{Name: result.Name})
+	}
+
+	run := w.toWorkflowRun(wf, *pr)
+	run.Steps = toWorkflowRunSteps(pr)
+	return run, nil
+}
+
+// CancelWorkflowRun cancels a running tekton pipeline run
+func (w *WorkflowBackend) CancelWorkflowRun(ctx context.Context, runName string) error {
+	pr, err := w.getPipelineRun(ctx, runName)
+	if err != nil {
+		return err
+	}
+	if pr.IsDone() || pr.IsCancelled() {
+		return domain.ErrWorkflowRunCompleted
+	}
+
+	w.logger.Printf("Cancelling tekton pipeline run: %s...", runName)
+	// use the status value supported by all tekton versions with the v1beta1 API
+	pr.Spec.Status = v1beta1.PipelineRunSpecStatusCancelledDeprecated
+	_, err = w.tektonClients.PipelineRunClient.Update(ctx, pr, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("error cancelling tekton pipeline run %q: %w", runName, err)
+	}
+	return nil
+}
+
+// RetryWorkflowRun creates a new tekton pipeline run with the same labels, parameters and resources as
+// a completed pipeline run. The new pipeline run references the current pipeline, so it records the extensions
+// the workflow steps are currently bound to instead of those of the completed pipeline run.
+func (w *WorkflowBackend) RetryWorkflowRun(ctx context.Context, runName string,
+	extensions []*domain.WorkflowRunExtension) (string, error) {
+	pr, err := w.getPipelineRun(ctx, runName)
+	if err != nil {
+		return "", err
+	}
+	if !pr.IsDone() {
+		return "", domain.ErrWorkflowRunNotCompleted
+	}
+
+	pipelineRun := &v1beta1.PipelineRun{
+		TypeMeta: pr.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: pr.GenerateName,
+			Namespace:    pr.Namespace,
+			Labels:       pr.Labels,
+		},
+		Spec: *pr.Spec.DeepCopy(),
+	}
+	annotations := map[string]string{}
+	if user, ok := pr.Annotations[AnnotationUser]; ok {
+		annotations[AnnotationUser] = user
+	}
+	if len(extensions) > 0 {
+		annotations[AnnotationExtensions] = extensionsAnnotation(extensions)
+	}
+	if len(annotations) > 0 {
+		pipelineRun.Annotations = annotations
+	}
+	pipelineRun.Spec.Status = ""
+	if project, ok := pr.Labels[LabelCodesetProject]; ok {
+		if err = w.setProjectPriorityClass(ctx, pipelineRun, project); err != nil {
+			return "", err
+		}
+	}
+
+	w.logger.Printf("Retrying tekton pipeline run: %s...", runName)
+	newPr, err := w.tektonClients.PipelineRunClient.Create(ctx, pipelineRun, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("error retrying tekton pipeline run %q: %w", runName, err)
+	}
+	return newPr.Name, nil
+}
+
+// DeleteWorkflowRun deletes a tekton pipeline run with the specified name
+func (w *WorkflowBackend) DeleteWorkflowRun(ctx context.Context, runName string) error {
+	if _, err := w.getPipelineRun(ctx, runName); err != nil {
+		return err
+	}
+
+	w.logger.Printf("Deleting tekton pipeline run: %s...", runName)
+	err := w.tektonClients.PipelineRunClient.Delete(ctx, runName, metav1.DeleteOptions{})
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return domain.ErrWorkflowRunNotFound
+		}
+		return fmt.Errorf("error deleting tekton pipeline run %q: %w", runName, err)
+	}
+	return nil
+}
+
 // CreateWorkflowListener creates tekton resources required to have a listener ready for triggering the pipeline
 func (w *WorkflowBackend) CreateWorkflowListener(ctx context.Context, workflowName string, timeout time.Duration) (*domain.WorkflowListener, error) {
 	pipeline, err := w.tektonClients.PipelineClient.Get(ctx, workflowName, metav1.GetOptions{})
@@ -186,7 +365,7 @@ func (w *WorkflowBackend) CreateWorkflowListener(ctx context.Context, workflowNa
 		defer w.tektonDeleteIfError(ctx, &err, el)
 	}
 
-	listenerURL := fmt.Sprintf("http://el-%s.%s.svc.cluster.local:8080", workflowName, w.namespace)
+	listenerURL := w.listenerServiceURL(workflowName)
 	if timeout > 0 {
 		interval := 1 * time.Second
 		if err = waitFor(w.eventListenerReady(ctx, el.Name), interval, timeout); err != nil {
@@ -253,6 +432,60 @@ func (w *WorkflowBackend) GetWorkflowListener(ctx context.Context, workflowName
 	return
 }
 
+// GetWorkflowListeners returns the listeners of all workflows. The URL of the listeners that are not available
+// yet is the address of the service created for them
+func (w *WorkflowBackend) GetWorkflowListeners(ctx context.Context) ([]*domain.WorkflowListener, error) {
+	els, err := w.tektonClients.EventListenerClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing tekton event listeners: %w", err)
+	}
+	listeners := make([]*domain.WorkflowListener, 0, len(els.Items))
+	for _, el := range els.Items {
+		wl := &domain.WorkflowListener{Name: el.Name, Available: listenerIsAvailable(el.Status),
+			URL:          w.listenerServiceURL(el.Name),
+			DashboardURL: fmt.Sprintf("%s/#/namespaces/%s/eventlisteners/%s", w.dashboardURL, w.namespace, el.Name)}
+		if el.Status.Address != nil && el.Status.Address.URL != nil {
+			wl.URL = el.Status.Address.URL.String()
+		}
+		listeners = append(listeners, wl)
+	}
+	return listeners, nil
+}
+
+// listenerServiceURL returns the address of the service created for the event listener of a workflow
+func (w *WorkflowBackend) listenerServiceURL(workflowName string) string {
+	return fmt.Sprintf("http://el-%s.%s.svc.cluster.local:8080", workflowName, w.namespace)
+}
+
+// UpdateWorkflowListener replaces the triggers of the tekton event listener with one trigger for each codeset
+// assignment, filtering the events with a CEL interceptor that only lets through the changes pushed to the
+// assigned codeset that pass the assignment filter. The codeset params are bound to the assigned codeset, so
+// that the push events sent by any git provider can trigger the workflow, and the pipeline runs are assigned
+// to the priority class of the codeset project, so that they are subject to the project quota.
+func (w *WorkflowBackend) UpdateWorkflowListener(ctx context.Context, workflowName string, assignments []*domain.CodesetAssignment) error {
+	tb, err := w.tektonClients.TriggerBindingClient.Get(ctx, workflowName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting tekton trigger binding %q: %w", workflowName, err)
+	}
+	el, err := w.tektonClients.EventListenerClient.Get(ctx, workflowName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting tekton event listener %q: %w", workflowName, err)
+	}
+
+	for _, a := range assignments {
+		if err = w.ensureProjectPriorityClass(ctx, a.Codeset.Project); err != nil {
+			return err
+		}
+	}
+	el.Spec.Triggers = generateEventListenerTriggers(el, tb, assignments)
+	w.logger.Printf("Updating tekton event listener triggers for workflow: %s...", workflowName)
+	_, err = w.tektonClients.EventListenerClient.Update(ctx, el, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("error updating tekton event listener %q: %w", workflowName, err)
+	}
+	return nil
+}
+
 func (e WorkflowBackendErr) Error() string {
 	return string(e)
 }
@@ -293,8 +526,17 @@ func generatePipeline(w domain.Workflow, namespace string) *v1beta1.Pipeline {
 	// label the pipeline with a reference to the workflow name
 	pb.Meta(builder.Label(LabelWorkflowRef, w.Name))
 	pb.Description(w.Description)
+	// record the extensions the steps are bound to, without the credentials as they depend on the codeset
+	// project of the pipeline runs
+	if extensions := w.ExtensionReferences(); len(extensions) > 0 {
+		for _, extension := range extensions {
+			extension.CredentialsID = ""
+		}
+		pb.Meta(builder.Annotation(AnnotationWorkflowExtensions, extensionsAnnotation(extensions)))
+	}
 
 	// process the FuseML workflow inputs
+	hasCodesetInput := false
 	for _, input := range w.Inputs {
 		// an input of the 'codeset' type means a git repository for tekton.
 		// adds a workspace, resource and the clone task to the pipeline, also
@@ -302,23 +544,25 @@ func generatePipeline(w domain.Workflow, namespace string) *v1beta1.Pipeline {
 		// pipeline which represents codeset.name and codeset.version in a FuseML
 		// workflow.
 		if input.Type == domain.WorkflowIOTypeCodeset {
+			hasCodesetInput = true
 			pb.Workspace(codesetWorkspaceName, false)
 			pb.Resource("source-repo", "git", false)
 			pb.Task("clone", cloneTaskName, nil, map[string]string{codesetWorkspaceName: codesetWorkspaceName},
 				map[string]string{"source-repo": "source-repo"})
 			pb.Param(codesetNameParam, "Reference to the codeset (git project)")
-			resolver.addReference(fmt.Sprintf("inputs.%s.name", input.Name), fmt.Sprintf("$(params.%s)", codesetNameParam))
-			pb.ParamWithDefaultValue(codesetVersionParam, "Codeset version (git revision)", "main")
-			resolver.addReference(fmt.Sprintf("inputs.%s.version", input.Name), fmt.Sprintf("$(params.%s)", codesetVersionParam))
+			resolver.AddReference(fmt.Sprintf("inputs.%s.name", input.Name), fmt.Sprintf("$(params.%s)", codesetNameParam))
+			pb.ParamWithDefaultValue(codesetVersionParam, "Codeset version (git revision)", defaultCodesetVersion)
+			resolver.AddReference(fmt.Sprintf("inputs.%s.version", input.Name), fmt.Sprintf("$(params.%s)", codesetVersionParam))
 			pb.Param(codesetProjectParam, "Reference to the codeset project (git organization)")
-			resolver.addReference(fmt.Sprintf("inputs.%s.project", input.Name), fmt.Sprintf("$(params.%s)", codesetProjectParam))
+			resolver.AddReference(fmt.Sprintf("inputs.%s.project", input.Name), fmt.Sprintf("$(params.%s)", codesetProjectParam))
 		} else {
 			pb.ParamWithDefaultValue(input.Name, input.Description, input.Default)
-			resolver.addReference(fmt.Sprintf("inputs.%s", input.Name), fmt.Sprintf("$(params.%s)", input.Name))
+			resolver.AddReference(fmt.Sprintf("inputs.%s", input.Name), fmt.Sprintf("$(params.%s)", input.Name))
 		}
 	}
 
 	// process the FuseML workflow steps
+	hasCredentialsParam := false
 STEPS:
 	for _, step := range w.Steps {
 		for _, output := range step.Outputs {
@@ -329,7 +573,7 @@ STEPS:
 			if output.Image != nil {
 				var dockerfile string
 				if output.Image.Dockerfile != "" {
-					dockerfile = resolver.resolve(output.Image.Dockerfile)
+					dockerfile = resolver.Resolve(output.Image.Dockerfile)
 					codesetPath := getInputCodesetPath(step.Inputs)
 					// in FuseML workflow the dockerfile path is a full path including the codeset.path, however
 					// the kaniko task mounts the codeset at workingDir and expects the dockerfile path to be referenced
@@ -339,12 +583,12 @@ STEPS:
 					}
 				}
 				prepTaskName := fmt.Sprintf("%s-prep", step.Name)
-				pb.Task(prepTaskName, builderPrepTaskName, map[string]string{"IMAGE": resolver.resolve(step.Image),
+				pb.Task(prepTaskName, builderPrepTaskName, map[string]string{"IMAGE": resolver.Resolve(step.Image),
 					"DOCKERFILE": dockerfile}, map[string]string{codesetWorkspaceName: codesetWorkspaceName}, nil)
-				pb.Task(step.Name, builderTaskName, map[string]string{"IMAGE": resolver.resolve(output.Image.Name),
+				pb.Task(step.Name, builderTaskName, map[string]string{"IMAGE": resolver.Resolve(output.Image.Name),
 					"DOCKERFILE": fmt.Sprintf("$(tasks.%s.results.DOCKERFILE-PATH)", prepTaskName)},
 					map[string]string{codesetWorkspaceName: codesetWorkspaceName}, nil)
-				resolver.addReference(fmt.Sprintf("steps.%s.outputs.%s", step.Name, output.Name), output.Image.Name)
+				resolver.AddReference(fmt.Sprintf("steps.%s.outputs.%s", step.Name, output.Name), output.Image.Name)
 				continue STEPS
 			}
 			// if the step output is the workflow output, map it to a PipelineResult in tekton
@@ -357,34 +601,44 @@ STEPS:
 			envVarPrefix + "WORKFLOW_NAMESPACE": namespace,
 			envVarPrefix + "WORKFLOW_NAME":      w.Name,
 		}
-		stepResolver := resolver.clone()
+		// let the steps know the codeset being processed, e.g. to record it when registering applications
+		if hasCodesetInput {
+			envVars[envVarPrefix+"CODESET_PROJECT"] = fmt.Sprintf("$(params.%s)", codesetProjectParam)
+			envVars[envVarPrefix+"CODESET_NAME"] = fmt.Sprintf("$(params.%s)", codesetNameParam)
+		}
+		// maps environment variables to the keys of the credentials secret holding their values
+		secretEnvVars := map[string]string{}
+		stepResolver := resolver.Clone()
 		for _, extension := range step.Extensions {
 			// add references to relevant extension fields
-			stepResolver.addReference(fmt.Sprintf("extensions.%s.product", extension.Name), extension.ExtensionAccess.Extension.Product)
-			stepResolver.addReference(fmt.Sprintf("extensions.%s.zone", extension.Name), extension.ExtensionAccess.Extension.Zone)
-			stepResolver.addReference(fmt.Sprintf("extensions.%s.version", extension.Name), extension.ExtensionAccess.Extension.Version)
-			stepResolver.addReference(fmt.Sprintf("extensions.%s.service_resource", extension.Name), extension.ExtensionAccess.Service.Resource)
-			stepResolver.addReference(fmt.Sprintf("extensions.%s.service_category", extension.Name), extension.ExtensionAccess.Service.Category)
-			stepResolver.addReference(fmt.Sprintf("extensions.%s.url", extension.Name), extension.ExtensionAccess.Endpoint.URL)
+			stepResolver.AddReference(fmt.Sprintf("extensions.%s.product", extension.Name), extension.ExtensionAccess.Extension.Product)
+			stepResolver.AddReference(fmt.Sprintf("extensions.%s.zone", extension.Name), extension.ExtensionAccess.Extension.Zone)
+			stepResolver.AddReference(fmt.Sprintf("extensions.%s.version", extension.Name), extension.ExtensionAccess.Extension.Version)
+			stepResolver.AddReference(fmt.Sprintf("extensions.%s.service_resource", extension.Name), extension.ExtensionAccess.Service.Resource)
+			stepResolver.AddReference(fmt.Sprintf("extensions.%s.service_category", extension.Name), extension.ExtensionAccess.Service.Category)
+			stepResolver.AddReference(fmt.Sprintf("extensions.%s.url", extension.Name), extension.ExtensionAccess.Endpoint.URL)
 			// add all configuration values as environment variables for the step as well as references
 			// that can be expanded in other fields
 			for k, v := range extension.ExtensionAccess.Extension.Configuration {
 				envVars[k] = v
-				stepResolver.addReference(fmt.Sprintf("extensions.%s.cfg.%s", extension.Name, k), v)
+				stepResolver.AddReference(fmt.Sprintf("extensions.%s.cfg.%s", extension.Name, k), v)
 			}
 			for k, v := range extension.ExtensionAccess.Service.Configuration {
 				envVars[k] = v
-				stepResolver.addReference(fmt.Sprintf("extensions.%s.cfg.%s", extension.Name, k), v)
+				stepResolver.AddReference(fmt.Sprintf("extensions.%s.cfg.%s", extension.Name, k), v)
 			}
 			for k, v := range extension.ExtensionAccess.Endpoint.Configuration {
 				envVars[k] = v
-				stepResolver.addReference(fmt.Sprintf("extensions.%s.cfg.%s", extension.Name, k), v)
+				stepResolver.AddReference(fmt.Sprintf("extensions.%s.cfg.%s", extension.Name, k), v)
 			}
-			// TODO: use secrets to store credentials env vars
+			// credentials are not set in the pipeline, the environment variables take their values from the
+			// credentials secret of the project and user the pipeline runs for and references are expanded
+			// from the environment variables
 			if extension.ExtensionAccess.Credentials != nil {
-				for k, v := range extension.ExtensionAccess.Credentials.Configuration {
-					envVars[k] = v
-					stepResolver.addReference(fmt.Sprintf("extensions.%s.cfg.%s", extension.Name, k), v)
+				for k := range extension.ExtensionAccess.Credentials.Configuration {
+					delete(envVars, k)
+					secretEnvVars[k] = credentialsSecretKey(step.Name, extension.Name, k)
+					stepResolver.AddReference(fmt.Sprintf("extensions.%s.cfg.%s", extension.Name, k), fmt.Sprintf("$(%s)", k))
 				}
 			}
 		}
@@ -392,16 +646,24 @@ STEPS:
 		// if the workflow step is not a pipeline task that references an existing TektonTask,
 		// build the task spec from the FuseML workflow step.
 		// generates a v1beta1.TaskSpec from a workflow.WorkflowStep
-		taskSpec := toTektonTaskSpec(step, stepResolver, envVars)
+		taskSpec := toTektonTaskSpec(step, stepResolver, envVars, secretEnvVars)
 		taskWs := make(map[string]string)
 		taskParams := make(map[string]string)
+		if len(secretEnvVars) > 0 {
+			if !hasCredentialsParam {
+				hasCredentialsParam = true
+				pb.ParamWithDefaultValue(credentialsSecretParam, "Name of the secret holding the extension credentials",
+					credentialsSecretName(w.Name, "", ""))
+			}
+			taskParams[credentialsSecretParam] = fmt.Sprintf("$(params.%s)", credentialsSecretParam)
+		}
 		for _, input := range step.Inputs {
 			// if the step has a codeset as input add the workspace
 			// TODO: for now it only supports 1 workspace
 			if input.Codeset != nil {
 				taskWs[taskSpec.Workspaces[0].Name] = codesetWorkspaceName
 			} else {
-				taskParams[input.Name] = resolver.resolve(input.Value)
+				taskParams[input.Name] = resolver.Resolve(input.Value)
 			}
 		}
 		// if image is parametrized add 'IMAGE' param, resolving it
@@ -410,9 +672,9 @@ STEPS:
 			// (registry.fuseml-registry), in that way, when the step uses an image
 			// from the local FuseML registry, replace registry.fuseml-registry with
 			// 127.0.0.1:30500
-			image := resolver.resolve(step.Image)
-			if strings.HasPrefix(image, fuseMLRegistry) {
-				image = strings.Replace(image, fuseMLRegistry, fuseMLRegistryLocal, 1)
+			image := resolver.Resolve(step.Image)
+			if strings.HasPrefix(image, domain.RegistryHostname) {
+				image = strings.Replace(image, domain.RegistryHostname, fuseMLRegistryLocal, 1)
 			}
 			taskParams[imageParamName] = image
 		}
@@ -421,24 +683,47 @@ STEPS:
 	return &pb.Pipeline
 }
 
-func generatePipelineRun(p *v1beta1.Pipeline, codeset *domain.Codeset) (*v1beta1.PipelineRun, error) {
-	codesetVersion := "main"
+func generatePipelineRun(p *v1beta1.Pipeline, codeset *domain.Codeset, options *domain.WorkflowRunOptions) (*v1beta1.PipelineRun, error) {
+	codesetVersion := defaultCodesetVersion
+	inputs := map[string]string{}
+	user := ""
+	var extensions []*domain.WorkflowRunExtension
+	if options != nil {
+		if options.CodesetRevision != "" {
+			codesetVersion = options.CodesetRevision
+		}
+		if options.Inputs != nil {
+			inputs = options.Inputs
+		}
+		user = options.User
+		extensions = options.Extensions
+	}
 	prb := builder.NewPipelineRunBuilder(fmt.Sprintf("%s%s-%s-", pipelineRunPrefix, codeset.Project, codeset.Name))
+	if len(extensions) > 0 {
+		prb.Meta(builder.Annotation(AnnotationExtensions, extensionsAnnotation(extensions)))
+	}
+	if user != "" {
+		prb.Meta(builder.Annotation(AnnotationUser, user))
+	}
 
 	for _, param := range p.Spec.Params {
-		if param.Default == nil {
-			switch param.Name {
-			case codesetNameParam:
-				prb.Param(param.Name, codeset.Name)
-			case codesetVersionParam:
-				prb.Param(param.Name, codesetVersion)
-			case codesetProjectParam:
-				prb.Param(param.Name, codeset.Project)
-			default:
+		switch param.Name {
+		case codesetNameParam:
+			prb.Param(param.Name, codeset.Name)
+		case codesetVersionParam:
+			prb.Param(param.Name, codesetVersion)
+		case codesetProjectParam:
+			prb.Param(param.Name, codeset.Project)
+		case credentialsSecretParam:
+			prb.Param(param.Name, credentialsSecretName(p.Name, codeset.Project, user))
+		default:
+			if value, ok := inputs[param.Name]; ok {
+				prb.Param(param.Name, value)
+			} else if param.Default != nil {
+				prb.Param(param.Name, param.Default.StringVal)
+			} else {
 				return nil, fmt.Errorf("pipeline run failed: could not set parameter value for %q", param.Name)
 			}
-		} else {
-			prb.Param(param.Name, param.Default.StringVal)
 		}
 	}
 
@@ -458,6 +743,10 @@ func generatePipelineRun(p *v1beta1.Pipeline, codeset *domain.Codeset) (*v1beta1
 	return &prb.PipelineRun, nil
 }
 
+func isCodesetParam(name string) bool {
+	return name == codesetNameParam || name == codesetVersionParam || name == codesetProjectParam
+}
+
 func generateTriggerTemplate(p *v1beta1.Pipeline) *v1alpha1.TriggerTemplate {
 	ttb := builder.NewTriggerTemplateBuilder(p.Name, p.Namespace)
 	prb := builder.NewPipelineRunBuilder(pipelineRunPrefix)
@@ -472,20 +761,20 @@ func generateTriggerTemplate(p *v1beta1.Pipeline) *v1alpha1.TriggerTemplate {
 		}
 		// if there is a codeset parameter we also need to add the codeset-url as parameter to
 		// the template
-		resolver.addReference(param.Name, fmt.Sprintf("$(tt.params.%s)", param.Name))
+		resolver.AddReference(param.Name, fmt.Sprintf("$(tt.params.%s)", param.Name))
 		switch param.Name {
 		case codesetNameParam:
 			ttb.Param(codesetURLParam, "The codeset URL (git repository URL)")
-			resolver.addReference(codesetURLParam, fmt.Sprintf("$(tt.params.%s)", codesetURLParam))
-			codesetName = resolver.resolve(param.Name)
+			resolver.AddReference(codesetURLParam, fmt.Sprintf("$(tt.params.%s)", codesetURLParam))
+			codesetName = resolver.Resolve(param.Name)
 			prb.Meta(builder.Label(LabelCodesetName, codesetName))
 		case codesetProjectParam:
-			codesetProject = resolver.resolve(param.Name)
+			codesetProject = resolver.Resolve(param.Name)
 			prb.Meta(builder.Label(LabelCodesetProject, codesetProject))
 		case codesetVersionParam:
-			prb.Meta(builder.Label(LabelCodesetVersion, resolver.resolve(param.Name)))
+			prb.Meta(builder.Label(LabelCodesetVersion, resolver.Resolve(param.Name)))
 		}
-		prb.Param(param.Name, resolver.resolve(param.Name))
+		prb.Param(param.Name, resolver.Resolve(param.Name))
 	}
 	prb.GenerateName(fmt.Sprintf("%s%s-%s-", pipelineRunPrefix, codesetProject, codesetName))
 
@@ -495,10 +784,14 @@ func generateTriggerTemplate(p *v1beta1.Pipeline) *v1alpha1.TriggerTemplate {
 
 	for _, res := range p.Spec.Resources {
 		if res.Type == "git" {
-			prb.ResourceGit(res.Name, resolver.resolve(codesetURLParam), resolver.resolve(codesetVersionParam))
+			prb.ResourceGit(res.Name, resolver.Resolve(codesetURLParam), resolver.Resolve(codesetVersionParam))
 		}
 	}
 
+	// the pods are assigned to the priority class of the codeset project, which scopes the project quota
+	ttb.ParamWithDefaultValue(priorityClassParam, "Name of the priority class of the codeset project", "")
+	prb.PriorityClass(fmt.Sprintf("$(tt.params.%s)", priorityClassParam))
+
 	prb.ServiceAccount(pipelineRunServiceAccount)
 	prb.PipelineRef(p.Name)
 
@@ -514,7 +807,7 @@ func generateTriggerTemplate(p *v1beta1.Pipeline) *v1alpha1.TriggerTemplate {
 func generateTriggerBinding(template *v1alpha1.TriggerTemplate) *v1alpha1.TriggerBinding {
 	webhookParamsMap := map[string]string{
 		codesetNameParam:    "$(body.repository.name)",
-		codesetVersionParam: "$(body.commits[0].id)",
+		codesetVersionParam: "$(body.after)",
 		codesetProjectParam: "$(body.repository.owner.username)",
 		codesetURLParam:     "$(body.repository.clone_url)",
 	}
@@ -524,6 +817,9 @@ func generateTriggerBinding(template *v1alpha1.TriggerTemplate) *v1alpha1.Trigge
 	for _, param := range template.Spec.Params {
 		if v, ok := webhookParamsMap[param.Name]; ok {
 			tbb.Param(param.Name, v)
+		} else if param.Name == credentialsSecretParam || param.Name == priorityClassParam {
+			// set to the secret and priority class of the codeset project by the event listener triggers
+			tbb.Param(param.Name, *param.Default)
 		}
 	}
 	return &tbb.TriggerBinding
@@ -536,8 +832,90 @@ func generateEventListener(template *v1alpha1.TriggerTemplate, binding *v1alpha1
 	return &elb.EventListener
 }
 
-func toTektonTaskSpec(step *domain.WorkflowStep, resolver *variablesResolver, envVars EnvVarMap) v1beta1.TaskSpec {
-	tb := builder.NewTaskSpecBuilder(step.Name, step.Image, stepDefaultCmd)
+// generateEventListenerTriggers generates one event listener trigger for each codeset assignment, binding the
+// params of the trigger binding with the assigned codeset values
+func generateEventListenerTriggers(el *v1alpha1.EventListener, tb *v1alpha1.TriggerBinding,
+	assignments []*domain.CodesetAssignment) []v1alpha1.EventListenerTrigger {
+	elb := builder.NewEventListenerBuilder(el.Name, el.Namespace)
+	for _, a := range assignments {
+		params := map[string]string{}
+		for _, p := range tb.Spec.Params {
+			switch p.Name {
+			case codesetNameParam:
+				params[p.Name] = a.Codeset.Name
+			case codesetProjectParam:
+				params[p.Name] = a.Codeset.Project
+			case codesetURLParam:
+				params[p.Name] = a.Codeset.URL
+			case credentialsSecretParam:
+				params[p.Name] = credentialsSecretName(el.Name, a.Codeset.Project, "")
+			case priorityClassParam:
+				params[p.Name] = projectQuotaName(el.Namespace, a.Codeset.Project)
+			default:
+				params[p.Name] = p.Value
+			}
+		}
+		elb.FilteredTrigger(fmt.Sprintf("%s-%s", a.Codeset.Project, a.Codeset.Name), el.Name,
+			assignmentFilterExpression(a), params)
+	}
+	return elb.EventListener.Spec.Triggers
+}
+
+// assignmentFilterExpression returns the CEL expression that matches the push events for the changes to the
+// assigned codeset that pass the assignment filter. The repository is identified by its full name, which GitLab
+// sends as the project path instead.
+func assignmentFilterExpression(a *domain.CodesetAssignment) string {
+	exprs := []string{fmt.Sprintf("(has(body.repository.full_name) ? body.repository.full_name : body.project.path_with_namespace) == %s",
+		celString(a.Codeset.Project+"/"+a.Codeset.Name))}
+	if a.Filter == nil {
+		return exprs[0]
+	}
+
+	refs := []string{}
+	for _, branch := range a.Filter.Branches {
+		refs = append(refs, fmt.Sprintf("body.ref.matches(%s)", celString(util.GlobToRegexp("refs/heads/"+branch))))
+	}
+	for _, tag := range a.Filter.Tags {
+		refs = append(refs, fmt.Sprintf("body.ref.matches(%s)", celString(util.GlobToRegexp("refs/tags/"+tag))))
+	}
+	if len(refs) > 0 {
+		exprs = append(exprs, fmt.Sprintf("(%s)", strings.Join(refs, " || ")))
+	}
+
+	paths := []string{}
+	for _, path := range a.Filter.Paths {
+		re := celString(util.GlobToRegexp(path))
+		paths = append(paths, fmt.Sprintf("c.added.exists(f, f.matches(%[1]s)) || c.modified.exists(f, f.matches(%[1]s)) || "+
+			"c.removed.exists(f, f.matches(%[1]s))", re))
+	}
+	if len(paths) > 0 {
+		exprs = append(exprs, fmt.Sprintf("body.commits.exists(c, %s)", strings.Join(paths, " || ")))
+	}
+	return strings.Join(exprs, " && ")
+}
+
+// celString returns a CEL string literal with the value
+func celString(value string) string {
+	return strconv.Quote(value)
+}
+
+// newVariablesResolver initializes a resolver that translates references to step outputs to the tekton way of
+// getting a task result: "$(tasks.TASK.results.VARIABLE)"
+func newVariablesResolver() *variables.Resolver {
+	return variables.NewResolver(func(reference string) string {
+		replacer := strings.NewReplacer("steps", "tasks", "outputs", "results")
+		return fmt.Sprintf("$(%s)", replacer.Replace(reference))
+	})
+}
+
+func toTektonTaskSpec(step *domain.WorkflowStep, resolver *variables.Resolver, envVars EnvVarMap,
+	secretEnvVars map[string]string) v1beta1.TaskSpec {
+	command := stepDefaultCmd
+	if step.Entrypoint != "" {
+		command = step.Entrypoint
+	}
+	tb := builder.NewTaskSpecBuilder(step.Name, step.Image, command)
+	tb.Args(step.Args)
 
 	for _, input := range step.Inputs {
 		// if there is a codeset as input, add workspace to the task and
@@ -575,9 +953,17 @@ func toTektonTaskSpec(step *domain.WorkflowStep, resolver *variablesResolver, en
 	// load environment variables
 	for _, stepEnv := range step.Env {
 		// step environment variables override those set elsewhere
-		envVars[stepEnv.Name] = resolver.resolve(stepEnv.Value)
+		envVars[stepEnv.Name] = resolver.Resolve(stepEnv.Value)
+		delete(secretEnvVars, stepEnv.Name)
+	}
+	// export env variables, the ones holding credentials are set first so that they can be referenced
+	// by the others. They are optional, as the credentials bound for a project or user may not set them all
+	if len(secretEnvVars) > 0 {
+		tb.ParamWithDescription(credentialsSecretParam, "Name of the secret holding the extension credentials")
+	}
+	for k, key := range secretEnvVars {
+		tb.EnvFromSecret(k, fmt.Sprintf("$(params.%s)", credentialsSecretParam), key)
 	}
-	// export env variables
 	for k, v := range envVars {
 		tb.Env(k, v)
 	}
@@ -610,8 +996,10 @@ func getInputCodesetPath(inputs []*domain.WorkflowStepInput) string {
 func (w *WorkflowBackend) toWorkflowRun(wf *domain.Workflow, p v1beta1.PipelineRun) *domain.WorkflowRun {
 
 	wfr := domain.WorkflowRun{
-		Name:        p.ObjectMeta.Name,
-		WorkflowRef: wf.Name,
+		Name:           p.ObjectMeta.Name,
+		WorkflowRef:    wf.Name,
+		CodesetProject: p.Labels[LabelCodesetProject],
+		User:           p.Annotations[AnnotationUser],
 	}
 
 	if p.Status.StartTime != nil {
@@ -648,15 +1036,101 @@ func (w *WorkflowBackend) toWorkflowRun(wf *domain.Workflow, p v1beta1.PipelineR
 	}
 	wfr.Status = status
 	wfr.URL = fmt.Sprintf("%s/#/namespaces/%s/pipelineruns/%s", w.dashboardURL, w.namespace, wfr.Name)
+	wfr.Extensions = runExtensions(p.Annotations)
 
 	return &wfr
 }
 
+// extensionsAnnotation returns the value of the annotation recording the extensions the workflow steps are bound to
+func extensionsAnnotation(extensions []*domain.WorkflowRunExtension) string {
+	value, err := json.Marshal(extensions)
+	if err != nil {
+		log.Fatalf("Error marshalling extensions: %s", err)
+	}
+	return string(value)
+}
+
+// runExtensions returns the extensions the steps of a pipeline run are bound to. The pipeline runs created by
+// the workflow listener only have the annotation copied from the pipeline, without the credentials.
+func runExtensions(annotations map[string]string) []*domain.WorkflowRunExtension {
+	value, ok := annotations[AnnotationExtensions]
+	if !ok {
+		value, ok = annotations[AnnotationWorkflowExtensions]
+	}
+	if !ok {
+		return nil
+	}
+	extensions := []*domain.WorkflowRunExtension{}
+	if err := json.Unmarshal([]byte(value), &extensions); err != nil {
+		return nil
+	}
+	return extensions
+}
+
+// toWorkflowRunSteps returns the status of the tasks executed by a pipeline run, sorted by their start time
+func toWorkflowRunSteps(p *v1beta1.PipelineRun) []*domain.WorkflowRunStep {
+	steps := []*domain.WorkflowRunStep{}
+	for _, tr := range p.Status.TaskRuns {
+		if tr.Status == nil {
+			continue
+		}
+
+		step := &domain.WorkflowRunStep{
+			Name:    tr.PipelineTaskName,
+			Status:  "Unknown",
+			Retries: len(tr.Status.RetriesStatus),
+			Results: map[string]string{},
+		}
+		if len(tr.Status.Conditions) > 0 {
+			step.Status = taskReasonToStepStatus(tr.Status.Conditions[0].Reason)
+		}
+		if tr.Status.StartTime != nil {
+			step.StartTime = tr.Status.StartTime.Time
+		}
+		if tr.Status.CompletionTime != nil {
+			step.CompletionTime = tr.Status.CompletionTime.Time
+		}
+
+		// prefer the image ID resolved when running the step container over the image set on the task
+		for _, s := range tr.Status.Steps {
+			if s.ImageID != "" {
+				step.Image = s.ImageID
+				break
+			}
+		}
+		if step.Image == "" && tr.Status.TaskSpec != nil && len(tr.Status.TaskSpec.Steps) > 0 {
+			step.Image = tr.Status.TaskSpec.Steps[0].Image
+		}
+
+		for _, result := range tr.Status.TaskRunResults {
+			step.Results[result.Name] = strings.TrimSpace(result.Value)
+		}
+		steps = append(steps, step)
+	}
+
+	sort.SliceStable(steps, func(i, j int) bool {
+		if steps[i].StartTime.Equal(steps[j].StartTime) {
+			return steps[i].Name < steps[j].Name
+		}
+		return steps[i].StartTime.Before(steps[j].StartTime)
+	})
+	return steps
+}
+
 // Some PipelineRun status starts with "PipelineRun" see:
 // https://github.com/tektoncd/pipeline/blob/main/docs/pipelineruns.md#monitoring-execution-status
 func pipelineReasonToWorkflowStatus(reason string) string {
+	return reasonToStatus(strings.TrimPrefix(reason, "PipelineRun"))
+}
+
+// Some TaskRun status starts with "TaskRun" see:
+// https://github.com/tektoncd/pipeline/blob/main/docs/taskruns.md#monitoring-execution-status
+func taskReasonToStepStatus(reason string) string {
+	return reasonToStatus(strings.TrimPrefix(reason, "TaskRun"))
+}
+
+func reasonToStatus(status string) string {
 	expectedStatus := []string{"Succeeded", "Running", "Cancelled", "Completed", "Pending", "Started", "Failed", "Unknown"}
-	status := strings.TrimPrefix(reason, "PipelineRun")
 	// If it is not an expected Status it means that the job failed and the status is the reason it failed
 	if !util.StringInSlice(status, expectedStatus) {
 		status = fmt.Sprintf("Failed (%s)", status)
@@ -691,6 +1165,21 @@ func getPipelineRunResultValue(resultName string, results []v1beta1.PipelineRunR
 	return ""
 }
 
+// getPipelineRun returns the pipeline run with the specified name, as long as it was created for a FuseML workflow
+func (w *WorkflowBackend) getPipelineRun(ctx context.Context, name string) (*v1beta1.PipelineRun, error) {
+	pr, err := w.tektonClients.PipelineRunClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			return nil, domain.ErrWorkflowRunNotFound
+		}
+		return nil, fmt.Errorf("error getting tekton pipeline run %q: %w", name, err)
+	}
+	if _, ok := pr.Labels[LabelWorkflowRef]; !ok {
+		return nil, domain.ErrWorkflowRunNotFound
+	}
+	return pr, nil
+}
+
 func (w *WorkflowBackend) tektonDeleteIfError(ctx context.Context, err *error, tektonWorkload interface{}) {
 	if *err != nil {
 		switch tw := tektonWorkload.(type) {
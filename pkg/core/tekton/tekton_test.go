@@ -20,12 +20,16 @@ import (
 	"github.com/tektoncd/triggers/pkg/apis/triggers/v1alpha1"
 	faketriggersclient "github.com/tektoncd/triggers/pkg/client/injection/client/fake"
 	corev1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 	v1 "knative.dev/pkg/apis/duck/v1"
 	knalpha1 "knative.dev/pkg/apis/duck/v1alpha1"
 	knbeta1 "knative.dev/pkg/apis/duck/v1beta1"
+	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	rtesting "knative.dev/pkg/reconciler/testing"
 
 	"github.com/fuseml/fuseml-core/pkg/domain"
@@ -67,6 +71,15 @@ func TestCreateWorkflow(t *testing.T) {
 		if d := cmp.Diff(want, *got, ignoreTypeMetaField, sortParamSlices, sortEnvVarSlices); d != "" {
 			t.Errorf("Unexpected Pipeline: %s", diff.PrintWantGot(d))
 		}
+
+		// the credentials are only set when the workflow runs for a project
+		secrets, err := b.tektonClients.SecretClient.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(secrets.Items) > 0 {
+			t.Errorf("Expected 0 Secret, got %d", len(secrets.Items))
+		}
 	})
 
 	t.Run("existing workflow", func(t *testing.T) {
@@ -84,6 +97,92 @@ func TestCreateWorkflow(t *testing.T) {
 	})
 }
 
+func TestUpdateWorkflow(t *testing.T) {
+	t.Run("existing workflow", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+
+		w := domain.Workflow{}
+		readYaml(t, fuseMLWorkflow, &w)
+
+		err := b.CreateWorkflow(ctx, &w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = b.CreateWorkflowListener(ctx, w.Name, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		access := *w.Steps[1].Extensions[0].ExtensionAccess
+		access.Endpoint.URL = "http://mlflow-tracking:5000"
+		w.Steps[1].Extensions[0].ExtensionAccess = &access
+		err = b.UpdateWorkflow(ctx, &w)
+		assertError(t, err, nil)
+
+		got, err := b.tektonClients.PipelineClient.Get(ctx, w.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get Pipeline %q: %s", w.Name, err)
+		}
+		want := generatePipeline(w, testNamespace)
+		sortParamSlices := cmpopts.SortSlices(func(x, y v1beta1.Param) bool { return x.Name < y.Name })
+		sortEnvVarSlices := cmpopts.SortSlices(func(x, y corev1.EnvVar) bool { return x.Name < y.Name })
+		if d := cmp.Diff(want.Spec, got.Spec, sortParamSlices, sortEnvVarSlices); d != "" {
+			t.Errorf("Unexpected Pipeline: %s", diff.PrintWantGot(d))
+		}
+		extensions := runExtensions(got.Annotations)
+		if len(extensions) == 0 || extensions[0].EndpointURL != access.Endpoint.URL {
+			t.Errorf("Expected the pipeline to record the updated extension endpoint, got %v", got.Annotations)
+		}
+
+		tt, err := b.tektonClients.TriggerTemplateClient.Get(ctx, w.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get TriggerTemplate %q: %s", w.Name, err)
+		}
+		if d := cmp.Diff(generateTriggerTemplate(want).Spec, tt.Spec); d != "" {
+			t.Errorf("Unexpected TriggerTemplate: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+
+		w := domain.Workflow{}
+		readYaml(t, fuseMLWorkflow, &w)
+
+		err := b.UpdateWorkflow(ctx, &w)
+		assertError(t, err, domain.ErrWorkflowNotFound)
+	})
+}
+
+func TestRenderWorkflow(t *testing.T) {
+	ctx, b, _ := initBackend(t)
+
+	w := domain.Workflow{}
+	readYaml(t, fuseMLWorkflow, &w)
+
+	rendered, err := b.RenderWorkflow(ctx, &w)
+	assertError(t, err, nil)
+
+	got := v1beta1.Pipeline{}
+	if err := yaml.Unmarshal([]byte(rendered), &got); err != nil {
+		t.Fatalf("Error unmarshiling rendered pipeline: %s", err)
+	}
+	want := v1beta1.Pipeline{}
+	readYaml(t, wantTektonPipeline, &want)
+
+	sortParamSlices := cmpopts.SortSlices(func(x, y v1beta1.Param) bool { return x.Name < y.Name })
+	sortEnvVarSlices := cmpopts.SortSlices(func(x, y corev1.EnvVar) bool { return x.Name < y.Name })
+	if d := cmp.Diff(want, got, sortParamSlices, sortEnvVarSlices); d != "" {
+		t.Errorf("Unexpected Pipeline: %s", diff.PrintWantGot(d))
+	}
+
+	// nothing is created
+	_, err = b.tektonClients.PipelineClient.Get(ctx, w.Name, metav1.GetOptions{})
+	if err == nil {
+		t.Errorf("Expected pipeline %q not to be created", w.Name)
+	}
+}
+
 func TestDeleteWorkflow(t *testing.T) {
 	t.Run("delete", func(t *testing.T) {
 		ctx, b, logsOutput := initBackend(t)
@@ -95,6 +194,11 @@ func TestDeleteWorkflow(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
+		for _, user := range []string{"", "alice"} {
+			if err = b.SetWorkflowCredentials(ctx, &w, "workspace", user); err != nil {
+				t.Fatal(err)
+			}
+		}
 		logsOutput.Reset()
 
 		err = b.DeleteWorkflow(ctx, w.Name)
@@ -108,6 +212,13 @@ func TestDeleteWorkflow(t *testing.T) {
 		if len(pipelines.Items) > 0 {
 			t.Errorf("Expected 0 Pipeline, got %d", len(pipelines.Items))
 		}
+		secrets, err := b.tektonClients.SecretClient.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(secrets.Items) > 0 {
+			t.Errorf("Expected 0 Secret, got %d", len(secrets.Items))
+		}
 
 		expectedLog := fmt.Sprintf("Deleting tekton pipeline: %s...\n", w.Name)
 		assertStrings(t, logsOutput.String(), expectedLog)
@@ -145,7 +256,7 @@ func TestCreateWorkflowRun(t *testing.T) {
 		Project: "workspace",
 		URL:     "http://gitea.10.160.5.140.nip.io/workspace/mlflow-app-01.git",
 	}
-	err = b.CreateWorkflowRun(ctx, w.Name, cs)
+	_, err = b.CreateWorkflowRun(ctx, w.Name, cs, nil)
 	if err != nil {
 		t.Fatalf("Failed to create workflow run %q: %s", w.Name, err)
 	}
@@ -172,6 +283,602 @@ func TestCreateWorkflowRun(t *testing.T) {
 	assertStrings(t, logsOutput.String(), expectedLog)
 }
 
+func TestCreateWorkflowRunWithOptions(t *testing.T) {
+	ctx, b, _ := initBackend(t)
+
+	w := domain.Workflow{}
+	readYaml(t, fuseMLWorkflow, &w)
+
+	err := b.CreateWorkflow(ctx, &w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := &domain.Codeset{
+		Name:    "mlflow-app-01",
+		Project: "workspace",
+		URL:     "http://gitea.10.160.5.140.nip.io/workspace/mlflow-app-01.git",
+	}
+	options := &domain.WorkflowRunOptions{
+		CodesetRevision: "v1.0",
+		Inputs:          map[string]string{"predictor": "kfserving", codesetVersionParam: "ignored"},
+		User:            "alice",
+		Extensions: []*domain.WorkflowRunExtension{{StepName: "trainer", Name: "mlflow-tracking", ExtensionID: "mlflow-0001",
+			ServiceID: "mlflow-tracking", EndpointURL: "http://mlflow", CredentialsID: "alice-token"}},
+	}
+	_, err = b.CreateWorkflowRun(ctx, w.Name, cs, options)
+	if err != nil {
+		t.Fatalf("Failed to create workflow run %q: %s", w.Name, err)
+	}
+
+	runs, err := b.tektonClients.PipelineRunClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list PipelineRuns: %s", err)
+	}
+
+	if len(runs.Items) != 1 {
+		t.Fatalf("Expected 1 PipelineRun, got %d", len(runs.Items))
+	}
+
+	got := runs.Items[0]
+	want := v1beta1.PipelineRun{}
+	readYaml(t, wantTektonPipelineRun, &want)
+	want.Labels[LabelCodesetVersion] = "v1.0"
+	for i, param := range want.Spec.Params {
+		switch param.Name {
+		case codesetVersionParam:
+			want.Spec.Params[i].Value.StringVal = "v1.0"
+		case "predictor":
+			want.Spec.Params[i].Value.StringVal = "kfserving"
+		case credentialsSecretParam:
+			want.Spec.Params[i].Value.StringVal = "fuseml-mlflow-sklearn-e2e-credentials.workspace.alice"
+		}
+	}
+	want.Spec.Resources[0].ResourceSpec.Params[1].Value = "v1.0"
+	want.Annotations = map[string]string{AnnotationExtensions: extensionsAnnotation(options.Extensions), AnnotationUser: "alice"}
+
+	ignoreStatusField := cmpopts.IgnoreFields(v1beta1.PipelineRunStatus{}, "Conditions", "PipelineRunStatusFields")
+	if d := cmp.Diff(want, got, ignoreStatusField); d != "" {
+		t.Errorf("Unexpected PipelineRun: %s", diff.PrintWantGot(d))
+	}
+	if d := cmp.Diff(options.Extensions, b.toWorkflowRun(&w, got).Extensions); d != "" {
+		t.Errorf("Unexpected WorkflowRun extensions: %s", diff.PrintWantGot(d))
+	}
+	if user := b.toWorkflowRun(&w, got).User; user != "alice" {
+		t.Errorf("Expected the WorkflowRun user to be alice, got %q", user)
+	}
+}
+
+func TestSetWorkflowCredentials(t *testing.T) {
+	ctx, b, logsOutput := initBackend(t)
+
+	w := domain.Workflow{}
+	readYaml(t, fuseMLWorkflow, &w)
+	if err := b.CreateWorkflow(ctx, &w); err != nil {
+		t.Fatal(err)
+	}
+	logsOutput.Reset()
+
+	wantData := map[string][]byte{}
+	for _, prefix := range []string{"trainer.mlflow-store.", "predictor.s3-storage."} {
+		wantData[prefix+"AWS_ACCESS_KEY_ID"] = []byte("gABTE5DmmLgjJypJzGFs")
+		wantData[prefix+"AWS_SECRET_ACCESS_KEY"] = []byte("uW1qiFS8DTFuACXCDrM7i5zLJXbbfXd6pReyntjn")
+	}
+
+	t.Run("project", func(t *testing.T) {
+		err := b.SetWorkflowCredentials(ctx, &w, "workspace", "")
+		assertError(t, err, nil)
+		assertStrings(t, logsOutput.String(), "Creating credentials secret for workflow: mlflow-sklearn-e2e, project: workspace...\n")
+
+		secret, err := b.tektonClients.SecretClient.Get(ctx, "fuseml-mlflow-sklearn-e2e-credentials.workspace", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get credentials Secret: %s", err)
+		}
+		if d := cmp.Diff(wantData, secret.Data); d != "" {
+			t.Errorf("Unexpected Secret data: %s", diff.PrintWantGot(d))
+		}
+		assertStrings(t, secret.Labels[LabelWorkflowRef], w.Name)
+		assertStrings(t, secret.Labels[LabelCodesetProject], "workspace")
+	})
+
+	t.Run("user", func(t *testing.T) {
+		userWorkflow := w.WithoutCredentials()
+		access := *userWorkflow.Steps[1].Extensions[0].ExtensionAccess
+		access.Credentials = &domain.ExtensionServiceCredentials{ID: "alice",
+			Configuration: map[string]string{"AWS_ACCESS_KEY_ID": "alice", "AWS_SECRET_ACCESS_KEY": "secret"}}
+		userWorkflow.Steps[1].Extensions[0].ExtensionAccess = &access
+
+		err := b.SetWorkflowCredentials(ctx, userWorkflow, "workspace", "alice")
+		assertError(t, err, nil)
+
+		secret, err := b.tektonClients.SecretClient.Get(ctx, "fuseml-mlflow-sklearn-e2e-credentials.workspace.alice", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get credentials Secret: %s", err)
+		}
+		want := map[string][]byte{
+			"trainer.mlflow-tracking.AWS_ACCESS_KEY_ID":     []byte("alice"),
+			"trainer.mlflow-tracking.AWS_SECRET_ACCESS_KEY": []byte("secret"),
+		}
+		if d := cmp.Diff(want, secret.Data); d != "" {
+			t.Errorf("Unexpected Secret data: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("names", func(t *testing.T) {
+		err := b.SetWorkflowCredentials(ctx, &w, "My_Project", "alice@example.com")
+		assertError(t, err, nil)
+
+		name := credentialsSecretName(w.Name, "My_Project", "alice@example.com")
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			t.Errorf("Invalid credentials Secret name %q: %v", name, errs)
+		}
+		if !strings.HasPrefix(name, "fuseml-mlflow-sklearn-e2e-credentials.my-project-") {
+			t.Errorf("Unexpected credentials Secret name %q", name)
+		}
+		if name == credentialsSecretName(w.Name, "my-project", "alice-example-com") {
+			t.Errorf("Expected different project and user names to use different secrets")
+		}
+		if _, err = b.tektonClients.SecretClient.Get(ctx, name, metav1.GetOptions{}); err != nil {
+			t.Errorf("Failed to get credentials Secret: %s", err)
+		}
+	})
+
+	t.Run("no credentials", func(t *testing.T) {
+		err := b.SetWorkflowCredentials(ctx, w.WithoutCredentials(), "workspace", "")
+		assertError(t, err, nil)
+
+		_, err = b.tektonClients.SecretClient.Get(ctx, "fuseml-mlflow-sklearn-e2e-credentials.workspace", metav1.GetOptions{})
+		if !k8serr.IsNotFound(err) {
+			t.Errorf("Expected the credentials Secret to be deleted, got %v", err)
+		}
+	})
+}
+
+func TestApplyProjectQuota(t *testing.T) {
+	ctx, b, _ := initBackend(t)
+
+	w := domain.Workflow{}
+	readYaml(t, fuseMLWorkflow, &w)
+	if err := b.CreateWorkflow(ctx, &w); err != nil {
+		t.Fatal(err)
+	}
+	cs := &domain.Codeset{
+		Name:    "mlflow-app-01",
+		Project: "Team_A",
+		URL:     "http://gitea.10.160.5.140.nip.io/Team_A/mlflow-app-01.git",
+	}
+	name := testNamespace + "-team-a"
+
+	// a quota without resource caps limits the number of pods
+	err := b.ApplyProjectQuota(ctx, cs.Project, &domain.ProjectQuota{MaxConcurrentRuns: 2})
+	assertError(t, err, nil)
+	rq, err := b.tektonClients.ResourceQuotaClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get ResourceQuota: %s", err)
+	}
+	if !equalResourceLists(corev1.ResourceList{corev1.ResourcePods: resource.MustParse("2")}, rq.Spec.Hard) {
+		t.Errorf("Unexpected ResourceQuota limits: %v", rq.Spec.Hard)
+	}
+	if _, err = b.tektonClients.LimitRangeClient.Get(ctx, defaultRequestsLimitRange, metav1.GetOptions{}); !k8serr.IsNotFound(err) {
+		t.Errorf("Expected no LimitRange, got error %v", err)
+	}
+
+	for _, quota := range []*domain.ProjectQuota{{CPU: "4", Memory: "8Gi"}, {Memory: "4Gi"}} {
+		err = b.ApplyProjectQuota(ctx, cs.Project, quota)
+		assertError(t, err, nil)
+
+		rq, err := b.tektonClients.ResourceQuotaClient.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get ResourceQuota: %s", err)
+		}
+		want := corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse(quota.Memory)}
+		if quota.CPU != "" {
+			want[corev1.ResourceRequestsCPU] = resource.MustParse(quota.CPU)
+		}
+		if !equalResourceLists(want, rq.Spec.Hard) {
+			t.Errorf("Unexpected ResourceQuota limits: %v", rq.Spec.Hard)
+		}
+		if rq.Spec.ScopeSelector == nil || rq.Spec.ScopeSelector.MatchExpressions[0].Values[0] != name {
+			t.Errorf("Expected the ResourceQuota to be scoped by the project priority class")
+		}
+	}
+	if _, err = b.tektonClients.PriorityClassClient.Get(ctx, name, metav1.GetOptions{}); err != nil {
+		t.Errorf("Failed to get PriorityClass: %s", err)
+	}
+	if _, err = b.tektonClients.LimitRangeClient.Get(ctx, defaultRequestsLimitRange, metav1.GetOptions{}); err != nil {
+		t.Errorf("Failed to get LimitRange: %s", err)
+	}
+
+	runName, err := b.CreateWorkflowRun(ctx, w.Name, cs, nil)
+	assertError(t, err, nil)
+	pr, err := b.tektonClients.PipelineRunClient.Get(ctx, runName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get PipelineRun: %s", err)
+	}
+	if pr.Spec.PodTemplate == nil || pr.Spec.PodTemplate.PriorityClassName == nil || *pr.Spec.PodTemplate.PriorityClassName != name {
+		t.Errorf("Expected the PipelineRun pods to use the %q priority class, got %v", name, pr.Spec.PodTemplate)
+	}
+
+	err = b.ApplyProjectQuota(ctx, cs.Project, nil)
+	assertError(t, err, nil)
+	if _, err = b.tektonClients.ResourceQuotaClient.Get(ctx, name, metav1.GetOptions{}); !k8serr.IsNotFound(err) {
+		t.Errorf("Expected the ResourceQuota to be deleted, got error %v", err)
+	}
+	// the priority class is kept, as the event listener triggers reference it
+	if _, err = b.tektonClients.PriorityClassClient.Get(ctx, name, metav1.GetOptions{}); err != nil {
+		t.Errorf("Failed to get PriorityClass: %s", err)
+	}
+}
+
+func TestGetWorkflowRun(t *testing.T) {
+	t.Run("existing run", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+
+		w := domain.Workflow{}
+		readYaml(t, fuseMLWorkflow, &w)
+
+		err := b.CreateWorkflow(ctx, &w)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cs := createCodeset(t, 1, 1)
+		runName := "run"
+		startTime := time.Now()
+		completionTime := startTime.Add(time.Minute)
+		b.createTestWorkflowRun(ctx, t, w.Name, cs, runName, "Failed", startTime, completionTime)
+
+		// the fake pipeline run client does not create task runs, set their status on the pipeline run
+		pr, err := b.tektonClients.PipelineRunClient.Get(ctx, runName, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get pipeline run: %s", err)
+		}
+		cloneStart := metav1.NewTime(startTime)
+		cloneEnd := metav1.NewTime(startTime.Add(10 * time.Second))
+		trainerStart := metav1.NewTime(startTime.Add(20 * time.Second))
+		trainerEnd := metav1.NewTime(completionTime)
+		pr.Status.TaskRuns = map[string]*v1beta1.PipelineRunTaskRunStatus{
+			"run-trainer": {
+				PipelineTaskName: "trainer",
+				Status: &v1beta1.TaskRunStatus{
+					Status: knbeta1.Status{Conditions: knbeta1.Conditions{{Type: apis.ConditionSucceeded,
+						Status: corev1.ConditionFalse, Reason: "TaskRunTimeout"}}},
+					TaskRunStatusFields: v1beta1.TaskRunStatusFields{
+						StartTime:      &trainerStart,
+						CompletionTime: &trainerEnd,
+						RetriesStatus:  []v1beta1.TaskRunStatus{{}},
+						TaskSpec:       &v1beta1.TaskSpec{Steps: []v1beta1.Step{{Container: corev1.Container{Image: "trainer:latest"}}}},
+					},
+				},
+			},
+			"run-clone": {
+				PipelineTaskName: "clone",
+				Status: &v1beta1.TaskRunStatus{
+					Status: knbeta1.Status{Conditions: knbeta1.Conditions{{Type: apis.ConditionSucceeded,
+						Status: corev1.ConditionTrue, Reason: "Succeeded"}}},
+					TaskRunStatusFields: v1beta1.TaskRunStatusFields{
+						StartTime:      &cloneStart,
+						CompletionTime: &cloneEnd,
+						Steps:          []v1beta1.StepState{{Name: "clone", ImageID: "docker-pullable://clone@sha256:1234"}},
+						TaskRunResults: []v1beta1.TaskRunResult{{Name: "commit", Value: "abcdef\n"}},
+					},
+				},
+			},
+		}
+		_, err = b.tektonClients.PipelineRunClient.Update(ctx, pr, metav1.UpdateOptions{})
+		if err != nil {
+			t.Fatalf("Failed to update pipeline run: %s", err)
+		}
+
+		got, err := b.GetWorkflowRun(ctx, runName)
+		if err != nil {
+			t.Fatalf("Failed to get workflow run: %s", err)
+		}
+
+		want := &domain.WorkflowRun{
+			Name:           runName,
+			WorkflowRef:    w.Name,
+			CodesetProject: cs.Project,
+			Inputs: []*domain.WorkflowRunInput{
+				{Input: &domain.WorkflowInput{Type: domain.WorkflowIOTypeCodeset}, Value: fmt.Sprintf("%s:main", cs.URL)},
+				{Input: &domain.WorkflowInput{Name: w.Inputs[1].Name, Type: domain.WorkflowIOTypeString}, Value: w.Inputs[1].Default},
+			},
+			StartTime:      startTime,
+			CompletionTime: completionTime,
+			Status:         "Failed",
+			URL:            "http://tekton.test/#/namespaces/test-namespace/pipelineruns/" + runName,
+			Steps: []*domain.WorkflowRunStep{
+				{
+					Name:           "clone",
+					Status:         "Succeeded",
+					StartTime:      cloneStart.Time,
+					CompletionTime: cloneEnd.Time,
+					Image:          "docker-pullable://clone@sha256:1234",
+					Results:        map[string]string{"commit": "abcdef"},
+				},
+				{
+					Name:           "trainer",
+					Status:         "Failed (Timeout)",
+					StartTime:      trainerStart.Time,
+					CompletionTime: trainerEnd.Time,
+					Retries:        1,
+					Image:          "trainer:latest",
+					Results:        map[string]string{},
+				},
+			},
+		}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected WorkflowRun: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+
+		_, err := b.GetWorkflowRun(ctx, "unknown")
+		assertError(t, err, domain.ErrWorkflowRunNotFound)
+	})
+}
+
+func TestStreamWorkflowRunLogs(t *testing.T) {
+	ctx, b, _ := initBackend(t)
+
+	w := domain.Workflow{}
+	readYaml(t, fuseMLWorkflow, &w)
+
+	err := b.CreateWorkflow(ctx, &w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := createCodeset(t, 1, 1)
+	startTime := time.Now()
+	b.createTestWorkflowRun(ctx, t, w.Name, cs, "run", "Failed", startTime, startTime.Add(time.Minute))
+	b.createTestWorkflowRun(ctx, t, w.Name, cs, "pending", "Running", startTime, startTime)
+
+	pr, err := b.tektonClients.PipelineRunClient.Get(ctx, "run", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get pipeline run: %s", err)
+	}
+	cloneStart := metav1.NewTime(startTime)
+	trainerStart := metav1.NewTime(startTime.Add(time.Second))
+	pr.Status.TaskRuns = map[string]*v1beta1.PipelineRunTaskRunStatus{
+		"run-trainer": {PipelineTaskName: "trainer", Status: &v1beta1.TaskRunStatus{
+			TaskRunStatusFields: v1beta1.TaskRunStatusFields{PodName: "run-trainer-pod", StartTime: &trainerStart}}},
+		"run-clone": {PipelineTaskName: "clone", Status: &v1beta1.TaskRunStatus{
+			TaskRunStatusFields: v1beta1.TaskRunStatusFields{PodName: "run-clone-pod", StartTime: &cloneStart}}},
+	}
+	_, err = b.tektonClients.PipelineRunClient.Update(ctx, pr, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("Failed to update pipeline run: %s", err)
+	}
+
+	terminated := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}
+	waiting := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}
+	pods := []*corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "run-clone-pod"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "step-clone"}}},
+			Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "step-clone", State: terminated}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "run-trainer-pod"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "step-train"}, {Name: "sidecar"}, {Name: "step-report"}}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "step-train", State: terminated}, {Name: "sidecar", State: terminated}, {Name: "step-report", State: waiting}}},
+		},
+	}
+	for _, pod := range pods {
+		_, err = b.tektonClients.PodClient.Create(ctx, pod, metav1.CreateOptions{})
+		if err != nil {
+			t.Fatalf("Failed to create pod: %s", err)
+		}
+	}
+
+	// the fake pod client always returns "fake logs" as the container logs
+	t.Run("all steps", func(t *testing.T) {
+		got := []*domain.WorkflowRunLog{}
+		err := b.StreamWorkflowRunLogs(ctx, "run", nil, func(log *domain.WorkflowRunLog) error {
+			got = append(got, log)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Failed to stream workflow run logs: %s", err)
+		}
+
+		want := []*domain.WorkflowRunLog{
+			{Step: "clone", Container: "step-clone", Line: "fake logs"},
+			{Step: "trainer", Container: "step-train", Line: "fake logs"},
+		}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected WorkflowRunLog: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("single step", func(t *testing.T) {
+		got := []*domain.WorkflowRunLog{}
+		err := b.StreamWorkflowRunLogs(ctx, "run", &domain.WorkflowRunLogOptions{Step: "trainer"}, func(log *domain.WorkflowRunLog) error {
+			got = append(got, log)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Failed to stream workflow run logs: %s", err)
+		}
+
+		want := []*domain.WorkflowRunLog{{Step: "trainer", Container: "step-train", Line: "fake logs"}}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("Unexpected WorkflowRunLog: %s", diff.PrintWantGot(d))
+		}
+	})
+
+	t.Run("handler error", func(t *testing.T) {
+		handlerErr := fmt.Errorf("connection closed")
+		err := b.StreamWorkflowRunLogs(ctx, "run", nil, func(log *domain.WorkflowRunLog) error {
+			return handlerErr
+		})
+		assertError(t, err, handlerErr)
+	})
+
+	t.Run("step not found", func(t *testing.T) {
+		err := b.StreamWorkflowRunLogs(ctx, "run", &domain.WorkflowRunLogOptions{Step: "unknown"}, nil)
+		assertError(t, err, domain.ErrWorkflowRunStepNotFound)
+	})
+
+	t.Run("no logs", func(t *testing.T) {
+		err := b.StreamWorkflowRunLogs(ctx, "pending", nil, nil)
+		assertError(t, err, domain.ErrWorkflowRunLogsNotFound)
+	})
+
+	t.Run("run not found", func(t *testing.T) {
+		err := b.StreamWorkflowRunLogs(ctx, "unknown", nil, nil)
+		assertError(t, err, domain.ErrWorkflowRunNotFound)
+	})
+}
+
+func TestCancelWorkflowRun(t *testing.T) {
+	ctx, b, _ := initBackend(t)
+
+	w := domain.Workflow{}
+	readYaml(t, fuseMLWorkflow, &w)
+
+	err := b.CreateWorkflow(ctx, &w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := createCodeset(t, 1, 1)
+	b.createTestWorkflowRun(ctx, t, w.Name, cs, "running", "Running", time.Now(), time.Now())
+	b.createTestWorkflowRun(ctx, t, w.Name, cs, "succeeded", "Succeeded", time.Now(), time.Now())
+
+	t.Run("running", func(t *testing.T) {
+		err := b.CancelWorkflowRun(ctx, "running")
+		if err != nil {
+			t.Fatalf("Failed to cancel workflow run: %s", err)
+		}
+
+		pr, err := b.tektonClients.PipelineRunClient.Get(ctx, "running", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get PipelineRun: %s", err)
+		}
+		if !pr.IsCancelled() {
+			t.Errorf("Expected PipelineRun to be cancelled, got status %q", pr.Spec.Status)
+		}
+
+		err = b.CancelWorkflowRun(ctx, "running")
+		assertError(t, err, domain.ErrWorkflowRunCompleted)
+	})
+
+	t.Run("completed", func(t *testing.T) {
+		err := b.CancelWorkflowRun(ctx, "succeeded")
+		assertError(t, err, domain.ErrWorkflowRunCompleted)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		err := b.CancelWorkflowRun(ctx, "unknown")
+		assertError(t, err, domain.ErrWorkflowRunNotFound)
+	})
+}
+
+func TestRetryWorkflowRun(t *testing.T) {
+	ctx, b, _ := initBackend(t)
+
+	w := domain.Workflow{}
+	readYaml(t, fuseMLWorkflow, &w)
+
+	err := b.CreateWorkflow(ctx, &w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := createCodeset(t, 1, 1)
+	b.createTestWorkflowRun(ctx, t, w.Name, cs, "running", "Running", time.Now(), time.Now())
+	b.createTestWorkflowRun(ctx, t, w.Name, cs, "failed", "Failed", time.Now(), time.Now())
+
+	t.Run("completed", func(t *testing.T) {
+		want, err := b.tektonClients.PipelineRunClient.Get(ctx, "failed", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get PipelineRun: %s", err)
+		}
+		want.Annotations = map[string]string{AnnotationUser: "alice", AnnotationExtensions: "[]"}
+		if want, err = b.tektonClients.PipelineRunClient.Update(ctx, want, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("Failed to update PipelineRun: %s", err)
+		}
+
+		// the retried pipeline run records the extensions the workflow steps are currently bound to
+		extensions := []*domain.WorkflowRunExtension{{StepName: "trainer", Name: "mlflow-tracking", ExtensionID: "mlflow",
+			ServiceID: "mlflow-tracking", EndpointURL: "http://mlflow", CredentialsID: "alice"}}
+		_, err = b.RetryWorkflowRun(ctx, "failed", extensions)
+		if err != nil {
+			t.Fatalf("Failed to retry workflow run: %s", err)
+		}
+
+		// the fake pipeline run client does not generate a name for the pipeline run
+		got, err := b.tektonClients.PipelineRunClient.Get(ctx, "", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get retried PipelineRun: %s", err)
+		}
+
+		assertStrings(t, got.GenerateName, want.GenerateName)
+		if d := cmp.Diff(want.Labels, got.Labels); d != "" {
+			t.Errorf("Unexpected PipelineRun labels: %s", diff.PrintWantGot(d))
+		}
+		wantAnnotations := map[string]string{AnnotationUser: "alice", AnnotationExtensions: extensionsAnnotation(extensions)}
+		if d := cmp.Diff(wantAnnotations, got.Annotations); d != "" {
+			t.Errorf("Unexpected PipelineRun annotations: %s", diff.PrintWantGot(d))
+		}
+		if d := cmp.Diff(want.Spec, got.Spec); d != "" {
+			t.Errorf("Unexpected PipelineRun spec: %s", diff.PrintWantGot(d))
+		}
+		if len(got.Status.Conditions) != 0 {
+			t.Errorf("Expected retried PipelineRun to have no status, got %v", got.Status.Conditions)
+		}
+	})
+
+	t.Run("running", func(t *testing.T) {
+		_, err := b.RetryWorkflowRun(ctx, "running", nil)
+		assertError(t, err, domain.ErrWorkflowRunNotCompleted)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := b.RetryWorkflowRun(ctx, "unknown", nil)
+		assertError(t, err, domain.ErrWorkflowRunNotFound)
+	})
+}
+
+func TestDeleteWorkflowRun(t *testing.T) {
+	ctx, b, logsOutput := initBackend(t)
+
+	w := domain.Workflow{}
+	readYaml(t, fuseMLWorkflow, &w)
+
+	err := b.CreateWorkflow(ctx, &w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cs := createCodeset(t, 1, 1)
+	b.createTestWorkflowRun(ctx, t, w.Name, cs, "succeeded", "Succeeded", time.Now(), time.Now())
+	logsOutput.Reset()
+
+	err = b.DeleteWorkflowRun(ctx, "succeeded")
+	if err != nil {
+		t.Fatalf("Failed to delete workflow run: %s", err)
+	}
+
+	runs, err := b.tektonClients.PipelineRunClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("Failed to list PipelineRuns: %s", err)
+	}
+	if len(runs.Items) != 0 {
+		t.Errorf("Expected 0 PipelineRun, got %d", len(runs.Items))
+	}
+	assertStrings(t, logsOutput.String(), "Deleting tekton pipeline run: succeeded...\n")
+
+	err = b.DeleteWorkflowRun(ctx, "succeeded")
+	assertError(t, err, domain.ErrWorkflowRunNotFound)
+}
+
 func TestGetWorkflowRuns(t *testing.T) {
 	t.Run("all", func(t *testing.T) {
 		ctx, b, _ := initBackend(t)
@@ -196,6 +903,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 			want = append(want, &domain.WorkflowRun{
 				Name:           runName,
 				WorkflowRef:    w.Name,
+				CodesetProject: cs.Project,
 				Inputs:         []*domain.WorkflowRunInput{{Input: w.Inputs[0], Value: fmt.Sprintf("%s:main", cs.URL)}, {Input: w.Inputs[1], Value: w.Inputs[1].Default}},
 				Outputs:        []*domain.WorkflowRunOutput{{Output: w.Outputs[0]}},
 				StartTime:      runStartTime,
@@ -238,6 +946,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 			wants = append(wants, &domain.WorkflowRun{
 				Name:           runName,
 				WorkflowRef:    w.Name,
+				CodesetProject: cs.Project,
 				Inputs:         []*domain.WorkflowRunInput{{Input: w.Inputs[0], Value: fmt.Sprintf("%s:main", cs.URL)}, {Input: w.Inputs[1], Value: w.Inputs[1].Default}},
 				Outputs:        []*domain.WorkflowRunOutput{{Output: w.Outputs[0]}},
 				StartTime:      runStartTime,
@@ -355,6 +1064,7 @@ func TestGetWorkflowRuns(t *testing.T) {
 			wants = append(wants, &domain.WorkflowRun{
 				Name:           runName,
 				WorkflowRef:    w.Name,
+				CodesetProject: cs.Project,
 				Inputs:         []*domain.WorkflowRunInput{{Input: w.Inputs[0], Value: fmt.Sprintf("%s:main", cs.URL)}, {Input: w.Inputs[1], Value: w.Inputs[1].Default}},
 				Outputs:        []*domain.WorkflowRunOutput{{Output: w.Outputs[0]}},
 				StartTime:      runStartTime,
@@ -661,6 +1371,98 @@ Tekton trigger template %q not found, skipping delete...
 	})
 }
 
+func TestUpdateWorkflowListener(t *testing.T) {
+	t.Run("update", func(t *testing.T) {
+		ctx, b, logsOutput := initBackend(t)
+
+		w := domain.Workflow{}
+		readYaml(t, fuseMLWorkflow, &w)
+
+		err := b.CreateWorkflow(ctx, &w)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		wfListener, err := b.CreateWorkflowListener(ctx, w.Name, 0)
+		if err != nil {
+			t.Fatalf("Failed to create listener for workflow %q: %s", w.Name, err)
+		}
+		logsOutput.Reset()
+
+		assignments := []*domain.CodesetAssignment{
+			{Codeset: &domain.Codeset{Project: "workspace", Name: "mlflow-app", URL: "https://github.com/workspace/mlflow-app.git"}},
+			{Codeset: &domain.Codeset{Project: "workspace", Name: "mlflow-lib", URL: "http://gitea.test/workspace/mlflow-lib.git"}, Filter: &domain.CodesetAssignmentFilter{
+				Branches: []string{"release/*"},
+				Tags:     []string{"v?.*"},
+				Paths:    []string{"src/**"},
+			}},
+		}
+		err = b.UpdateWorkflowListener(ctx, wfListener.Name, assignments)
+		assertError(t, err, nil)
+
+		el, err := b.tektonClients.EventListenerClient.Get(ctx, wfListener.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(el.Spec.Triggers) != 2 {
+			t.Fatalf("Expected 2 EventListener triggers, got %d", len(el.Spec.Triggers))
+		}
+
+		repoName := "(has(body.repository.full_name) ? body.repository.full_name : body.project.path_with_namespace)"
+		wantFilters := []string{
+			repoName + ` == "workspace/mlflow-app"`,
+			repoName + ` == "workspace/mlflow-lib" && (body.ref.matches("^refs/heads/release/[^/]*$") || ` +
+				`body.ref.matches("^refs/tags/v[^/]\\.[^/]*$")) && body.commits.exists(c, ` +
+				`c.added.exists(f, f.matches("^src/.*$")) || c.modified.exists(f, f.matches("^src/.*$")) || ` +
+				`c.removed.exists(f, f.matches("^src/.*$")))`,
+		}
+		for i, trigger := range el.Spec.Triggers {
+			assertStrings(t, trigger.Name, assignments[i].Codeset.Project+"-"+assignments[i].Codeset.Name)
+			if len(trigger.Interceptors) != 1 || len(trigger.Interceptors[0].Params) != 1 {
+				t.Fatalf("Expected a single CEL interceptor filter for trigger %q", trigger.Name)
+			}
+			var gotFilter string
+			if err := json.Unmarshal(trigger.Interceptors[0].Params[0].Value.Raw, &gotFilter); err != nil {
+				t.Fatal(err)
+			}
+			assertStrings(t, gotFilter, wantFilters[i])
+
+			gotBindings := map[string]string{}
+			for _, binding := range trigger.Bindings {
+				gotBindings[binding.Name] = *binding.Value
+			}
+			wantBindings := map[string]string{
+				"codeset-name":    assignments[i].Codeset.Name,
+				"codeset-project": assignments[i].Codeset.Project,
+				"codeset-url":     assignments[i].Codeset.URL,
+				"codeset-version": "$(body.after)",
+				// the runs triggered by the codeset use the credentials of its project
+				"credentials-secret": "fuseml-mlflow-sklearn-e2e-credentials." + assignments[i].Codeset.Project,
+				// and are subject to its quota
+				"priority-class": testNamespace + "-" + assignments[i].Codeset.Project,
+			}
+			if d := cmp.Diff(wantBindings, gotBindings); d != "" {
+				t.Errorf("Unexpected trigger bindings: %s", diff.PrintWantGot(d))
+			}
+		}
+
+		expectedLog := fmt.Sprintf("Updating tekton event listener triggers for workflow: %s...\n", wfListener.Name)
+		assertStrings(t, logsOutput.String(), expectedLog)
+		if _, err = b.tektonClients.PriorityClassClient.Get(ctx, testNamespace+"-workspace", metav1.GetOptions{}); err != nil {
+			t.Errorf("Failed to get PriorityClass: %s", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctx, b, _ := initBackend(t)
+
+		err := b.UpdateWorkflowListener(ctx, "TestListener", nil)
+		if err == nil {
+			t.Fatal("Expected an error updating a listener that does not exist")
+		}
+	})
+}
+
 func TestGetWorkflowListener(t *testing.T) {
 	ctx, b, _ := initBackend(t)
 
@@ -768,6 +1570,13 @@ func newFakeClients(context context.Context, t *testing.T, namespace string) *cl
 	fc.TriggerTemplateClient = tcs.TriggersV1alpha1().TriggerTemplates(namespace)
 	fc.TriggerBindingClient = tcs.TriggersV1alpha1().TriggerBindings(namespace)
 	fc.EventListenerClient = tcs.TriggersV1alpha1().EventListeners(namespace)
+
+	kcs := fakekubeclient.Get(context)
+	fc.PodClient = kcs.CoreV1().Pods(namespace)
+	fc.SecretClient = kcs.CoreV1().Secrets(namespace)
+	fc.ResourceQuotaClient = kcs.CoreV1().ResourceQuotas(namespace)
+	fc.LimitRangeClient = kcs.CoreV1().LimitRanges(namespace)
+	fc.PriorityClassClient = kcs.SchedulingV1().PriorityClasses()
 	return fc
 }
 
@@ -791,7 +1600,7 @@ func (b WorkflowBackend) createTestWorkflowRun(ctx context.Context, t *testing.T
 	cs *domain.Codeset, runName string, status string, startTime time.Time, completionTime time.Time) {
 	t.Helper()
 
-	err := b.CreateWorkflowRun(ctx, workflow, cs)
+	_, err := b.CreateWorkflowRun(ctx, workflow, cs, nil)
 	if err != nil {
 		t.Fatalf("Failed to create workflow run %q: %s", workflow, err)
 	}
@@ -805,7 +1614,14 @@ func (b WorkflowBackend) createTestWorkflowRun(ctx context.Context, t *testing.T
 		t.Fatalf("Failed to get pipeline run: %s", err)
 	}
 	prun.ObjectMeta.Name = runName
-	prun.Status.Conditions = knbeta1.Conditions{apis.Condition{Reason: status}}
+	conditionStatus := corev1.ConditionFalse
+	switch status {
+	case "Succeeded":
+		conditionStatus = corev1.ConditionTrue
+	case "Running", "Unknown":
+		conditionStatus = corev1.ConditionUnknown
+	}
+	prun.Status.Conditions = knbeta1.Conditions{apis.Condition{Type: apis.ConditionSucceeded, Status: conditionStatus, Reason: status}}
 	st := metav1.NewTime(startTime)
 	ct := metav1.NewTime(completionTime)
 	prun.Status.StartTime = &st
@@ -0,0 +1,74 @@
+package variables
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// StepOutputFormatter translates a reference to a step output (e.g. "steps.trainer.outputs.model") that is not
+// known by the Resolver into the syntax used by the workflow backend to get the output of a step.
+type StepOutputFormatter func(reference string) string
+
+// Resolver resolves the variables ("{{ inputs.mlflow-codeset.name }}") referenced by a FuseML workflow.
+type Resolver struct {
+	references       map[string]string
+	formatStepOutput StepOutputFormatter
+}
+
+// NewResolver initializes a Resolver. References to step outputs that are not known by the resolver are
+// translated using formatStepOutput, or left unresolved when formatStepOutput is nil.
+func NewResolver(formatStepOutput StepOutputFormatter) *Resolver {
+	r := &Resolver{formatStepOutput: formatStepOutput}
+	r.references = make(map[string]string)
+	return r
+}
+
+// Clone returns a copy of the resolver and its references.
+func (r *Resolver) Clone() (res *Resolver) {
+	res = NewResolver(r.formatStepOutput)
+	for key, value := range r.references {
+		res.references[key] = value
+	}
+	return res
+}
+
+// Resolve replaces the variables referenced by value with their values.
+func (r *Resolver) Resolve(value string) string {
+	regex := regexp.MustCompile(`{([^{{{}}}]*)}`)
+	matches := regex.FindAllStringSubmatch(value, -1)
+	if len(matches) == 0 {
+		if v, ok := r.references[value]; ok {
+			return v
+		}
+	}
+	for _, v := range matches {
+		toResolve := strings.TrimSpace(v[1])
+		resolvedTo, existsReference := r.references[toResolve]
+		// if value begins with 'steps.' it could mean that the value is derived from a step output, in that case
+		// translate it to the backend way of getting a step output (e.g. "$(tasks.TASK.results.VARIABLE)" for tekton),
+		// or that the value is referencing a step input/output that is resolvable here. For example, referencing an
+		// image step output "{{ steps.builder.outputs.mlflow-env }}", it starts with steps but a reference to it exists
+		// and resolving it returns:
+		// "registry.fuseml-registry/mlflow-builder/{{ inputs.mlflow-codeset.name }}:{{ inputs.mlflow-codeset.version }}"
+		// which also have variables to also be resolved.
+		if strings.HasPrefix(toResolve, "steps.") {
+			// if there is already a reference to 'value' on 'sources' use it,
+			// note that the reference might also be parametrized, so we also need to resolve it
+			if existsReference {
+				resolvedTo = r.Resolve(resolvedTo)
+			} else if r.formatStepOutput != nil {
+				resolvedTo = r.formatStepOutput(toResolve)
+			} else {
+				continue
+			}
+		}
+		value = strings.ReplaceAll(value, fmt.Sprintf("{{ %s }}", toResolve), resolvedTo)
+	}
+	return value
+}
+
+// AddReference adds a reference to a variable and the value it resolves to.
+func (r *Resolver) AddReference(ref, value string) {
+	r.references[ref] = value
+}
@@ -50,6 +50,15 @@ func (ws *WorkflowStore) AddWorkflow(ctx context.Context, w *domain.Workflow) (*
 	return w, nil
 }
 
+// UpdateWorkflow replaces an existing workflow with the Workflow structure provided as argument
+func (ws *WorkflowStore) UpdateWorkflow(ctx context.Context, w *domain.Workflow) (*domain.Workflow, error) {
+	if _, exists := ws.items[w.Name]; !exists {
+		return nil, domain.ErrWorkflowNotFound
+	}
+	ws.items[w.Name] = w
+	return w, nil
+}
+
 // DeleteWorkflow deletes the workflow from the store
 func (ws *WorkflowStore) DeleteWorkflow(ctx context.Context, name string) error {
 	wf, found := ws.items[name]
@@ -91,13 +100,13 @@ func (ws *WorkflowStore) GetAllCodesetAssignments(ctx context.Context, workflowN
 
 // AddCodesetAssignment adds a codeset assignment to the list of assigned codesets of a workflow
 func (ws *WorkflowStore) AddCodesetAssignment(ctx context.Context, workflowName string, codeset *domain.Codeset,
-	webhookID *int64) ([]*domain.CodesetAssignment, error) {
+	webhookID *int64, filter *domain.CodesetAssignmentFilter) ([]*domain.CodesetAssignment, error) {
 	wf, ok := ws.items[workflowName]
 	if !ok {
 		return nil, domain.ErrWorkflowNotFound
 	}
 
-	err := wf.AssignToCodeset(ctx, codeset, webhookID)
+	err := wf.AssignToCodeset(ctx, codeset, webhookID, filter)
 	if err != nil {
 		return nil, err
 	}
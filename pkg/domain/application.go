@@ -24,6 +24,10 @@ type Application struct {
 	URL string
 	// Name of the Workflow used to create Application
 	Workflow string
+	// Project of the codeset from which the Workflow created the Application, if known
+	CodesetProject string
+	// Name of the codeset from which the Workflow created the Application, if known
+	CodesetName string
 	// Kubernetes resources describing the Application
 	K8sResources []*KubernetesResource
 	// Kubernetes namespace where the resources are located
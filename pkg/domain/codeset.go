@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+	"strings"
+	"time"
 )
 
 // Codeset represents a codeset artifact
@@ -16,6 +18,140 @@ type Codeset struct {
 	Labels []string
 	// Full URL to the Codeset
 	URL string
+	// Metadata detected from the Codeset contents. Not set for empty Codesets
+	Metadata *CodesetMetadata
+}
+
+// CodesetMetadata describes the contents of a Codeset, as detected by inspecting the files found at the top
+// of its most recent commit. The fields match those of RunnableCodesetArtifact, so that Codesets can be matched
+// against the codeset inputs accepted by runnables
+type CodesetMetadata struct {
+	// The ID of the commit the metadata was detected from
+	Commit string
+	// The type of information contained in the Codeset (e.g. code, configuration, container)
+	Type []string
+	// The format(s) used for the Codeset contents (e.g. MLProject, conda, pip, Dockerfile, Jupyter)
+	Format []string
+	// Software packages found in the Codeset dependency files, with their optional version requirements
+	Requirements map[string]string
+	// The files the metadata was detected from
+	Files []string
+	// The labels associated with the detected formats, which are also added to the Codeset labels
+	Labels []string
+}
+
+// Matches returns true if the Codeset contents satisfy the description of a runnable codeset artifact: the
+// Codeset must contain all the artifact types and formats and must list all the required software packages.
+// Version requirements are not compared
+func (m *CodesetMetadata) Matches(a *RunnableCodesetArtifact) bool {
+	for _, t := range a.Type {
+		if !containsFold(m.Type, t) {
+			return false
+		}
+	}
+	for _, f := range a.Format {
+		if !containsFold(m.Format, f) {
+			return false
+		}
+	}
+	for pkg := range a.Requirements {
+		if _, ok := m.Requirements[strings.ToLower(pkg)]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// CodesetRevisions holds the revisions of a codeset: its branches, tags and most recent commits
+type CodesetRevisions struct {
+	// Branches is the list of branches of the Codeset
+	Branches []*CodesetRef
+	// Tags is the list of tags of the Codeset
+	Tags []*CodesetRef
+	// Commits is the list of the most recent commits of the Codeset, starting with the newest one
+	Commits []*CodesetCommit
+}
+
+// CodesetRef is a named reference (branch or tag) to a Codeset commit
+type CodesetRef struct {
+	// The name of the branch or tag
+	Name string
+	// The ID of the commit the reference points to
+	Commit string
+}
+
+// CodesetCommit describes a commit pushed to a Codeset
+type CodesetCommit struct {
+	// The commit ID
+	ID string
+	// The name of the commit author
+	Author string
+	// The commit message
+	Message string
+	// The time the commit was created
+	Time time.Time
+}
+
+// Types of the entries in a Codeset file tree
+const (
+	// CodesetFileTypeFile is a regular file
+	CodesetFileTypeFile = "file"
+	// CodesetFileTypeDir is a directory
+	CodesetFileTypeDir = "dir"
+	// CodesetFileTypeSymlink is a symbolic link
+	CodesetFileTypeSymlink = "symlink"
+	// CodesetFileTypeSubmodule is a git submodule
+	CodesetFileTypeSubmodule = "submodule"
+)
+
+// CodesetFile is an entry (file or directory) in the file tree of a Codeset
+type CodesetFile struct {
+	// The path of the entry, relative to the root of the Codeset
+	Path string
+	// The type of the entry: file, dir, symlink or submodule
+	Type string
+	// The size of the file, in bytes. Only set for regular files
+	Size int64
+}
+
+// Supported formats for the archives imported into a Codeset
+const (
+	// CodesetArchiveTarGz is a gzip compressed tar archive
+	CodesetArchiveTarGz = "tar.gz"
+	// CodesetArchiveZip is a zip archive
+	CodesetArchiveZip = "zip"
+)
+
+// CodesetImportSource describes the contents imported into a Codeset: either a remote git repository
+// or an uploaded archive
+type CodesetImportSource struct {
+	// URL of the remote git repository
+	URL string
+	// Branch, tag or commit of the remote git repository to import. The default branch is imported if empty
+	Ref string
+	// Configuration entries (username, password, token) used to access the remote git repository
+	Credentials map[string]string
+	// Archive holding the contents to import, used when URL is empty
+	Archive []byte
+	// Format of the archive (tar.gz or zip)
+	ArchiveFormat string
+}
+
+// CodesetWebhook is a webhook notifying a workflow listener about the changes pushed to a Codeset
+type CodesetWebhook struct {
+	// The ID of the webhook
+	ID int64
+	// The URL the webhook sends the events to
+	URL string
 }
 
 // CodesetSubscriber is an interface for objects interested in operations performed on
@@ -29,23 +165,47 @@ type CodesetStore interface {
 	Find(ctx context.Context, project, name string) (*Codeset, error)
 	GetAll(ctx context.Context, project, label *string) ([]*Codeset, error)
 	Add(ctx context.Context, c *Codeset) (*Codeset, *string, *string, error)
+	Import(ctx context.Context, c *Codeset, source *CodesetImportSource) (*Codeset, *string, *string, error)
+	Inspect(ctx context.Context, project, name string) (*Codeset, error)
 	CreateWebhook(context.Context, *Codeset, string) (*int64, error)
 	DeleteWebhook(context.Context, *Codeset, *int64) error
+	GetWebhooks(context.Context, *Codeset) ([]*CodesetWebhook, error)
 	Delete(ctx context.Context, project, name string) error
+	GetRevisions(ctx context.Context, project, name string, commits int) (*CodesetRevisions, error)
+	GetFiles(ctx context.Context, project, name, ref, path string) ([]*CodesetFile, error)
+	GetFileContents(ctx context.Context, project, name, ref, path string) ([]byte, error)
 	Subscribe(ctx context.Context, watcher CodesetSubscriber, codeset *Codeset) error
 	Unsubscribe(ctx context.Context, watcher CodesetSubscriber, codeset *Codeset) error
 }
 
+// CodesetMetadataStore is an interface to the stores keeping the metadata detected from the codeset contents
+type CodesetMetadataStore interface {
+	// Find returns the metadata of a codeset, or nil if the codeset has none
+	Find(ctx context.Context, project, name string) *CodesetMetadata
+	// Save adds or replaces the metadata of a codeset
+	Save(ctx context.Context, project, name string, metadata *CodesetMetadata) error
+	// Delete removes the metadata of a codeset
+	Delete(ctx context.Context, project, name string) error
+}
+
 // GitAdminClient describes the interface of a Git admin client
 type GitAdminClient interface {
 	PrepareRepository(*Codeset, *string) (*string, *string, error)
 	CreateRepoWebhook(string, string, *string) (*int64, error)
 	DeleteRepoWebhook(string, string, *int64) error
+	GetRepoWebhooks(org, name string) ([]*CodesetWebhook, error)
 	GetRepositories(org, label *string) ([]*Codeset, error)
 	GetRepository(org, name string) (*Codeset, error)
 	DeleteRepository(org, name string) error
+	AddRepositoryLabels(org, name string, labels []string) error
+	GetRepositoryRevisions(org, name string, commits int) (*CodesetRevisions, error)
+	GetRepositoryFiles(org, name, ref, path string) ([]*CodesetFile, error)
+	GetRepositoryFileContents(org, name, ref, path string) ([]byte, error)
 	GetProjects() ([]*Project, error)
 	GetProject(org string) (*Project, error)
 	DeleteProject(org string) error
 	CreateProject(string, string, bool) (*Project, error)
+	UpdateProject(org, desc string) error
+	AddProjectMember(org string, user *User) (*string, error)
+	RemoveProjectMember(org, name string) error
 }
@@ -3,6 +3,28 @@ package domain
 const (
 	// ErrProjectExists is the error message returned when trying to create a project (org) that already exists.
 	ErrProjectExists = projectErr("Project with that name already exists")
+	// ErrProjectMemberNotFound is the error message returned when a user is not a member of a project.
+	ErrProjectMemberNotFound = projectErr("User is not a member of the project")
+	// ErrProjectNotEmpty is the error message returned when deleting, without cascading, a project that still
+	// contains codesets.
+	ErrProjectNotEmpty = projectErr("Project still contains codesets. Delete them first or use a cascading delete")
+	// ErrInvalidProjectQuota is the error message returned when the resources of a project quota are not valid.
+	ErrInvalidProjectQuota = projectErr("Invalid project quota")
+	// ErrProjectQuotaExceeded is the error message returned when a workflow run would exceed the quota of its project.
+	ErrProjectQuotaExceeded = projectErr("Project quota exceeded")
+	// ErrProjectQuotaUnsupported is the error message returned when the workflow backend cannot limit the
+	// resources used by the workflow runs of a project.
+	ErrProjectQuotaUnsupported = projectErr("Project resource quotas are not supported by the workflow backend")
+	// ErrInvalidCodesetImport is the error message returned when the source of a codeset import is not valid.
+	ErrInvalidCodesetImport = codesetErr("Invalid codeset import source")
+	// ErrCodesetImportUnsupported is the error message returned when the git provider cannot push the imported
+	// contents to the codeset repositories.
+	ErrCodesetImportUnsupported = codesetErr("Codeset imports are not supported by the git provider")
+	// ErrCodesetFileNotFound is the error message returned when a codeset path or revision does not exist.
+	ErrCodesetFileNotFound = codesetErr("Codeset file not found")
+	// ErrCodesetNotAFile is the error message returned when trying to read the contents of a codeset path that
+	// is not a regular file.
+	ErrCodesetNotAFile = codesetErr("Codeset path is not a regular file")
 )
 
 type projectErr string
@@ -10,3 +32,9 @@ type projectErr string
 func (e projectErr) Error() string {
 	return string(e)
 }
+
+type codesetErr string
+
+func (e codesetErr) Error() string {
+	return string(e)
+}
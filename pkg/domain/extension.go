@@ -36,6 +36,28 @@ const (
 	ECSUser = "user"
 )
 
+// ExtensionServiceHealthCheckType is the type used for the ExtensionServiceHealthCheck Type field
+type ExtensionServiceHealthCheckType string
+
+// Valid values that can be used with ExtensionServiceHealthCheckType
+const (
+	// EHCHTTP is a health check sending an HTTP GET request to the endpoint and checking the response status
+	EHCHTTP ExtensionServiceHealthCheckType = "http"
+	// EHCTCP is a health check opening a TCP connection to the endpoint host and port
+	EHCTCP = "tcp"
+)
+
+// ExtensionServiceEndpointHealth is the type used for the ExtensionServiceEndpointStatus Health field
+type ExtensionServiceEndpointHealth string
+
+// Valid values that can be used with ExtensionServiceEndpointHealth
+const (
+	// EEHHealthy is the health of an endpoint that passed its last health check
+	EEHHealthy ExtensionServiceEndpointHealth = "healthy"
+	// EEHUnhealthy is the health of an endpoint that failed its last health check
+	EEHUnhealthy = "unhealthy"
+)
+
 // Extension is an entry in the extension registry that describes a particular installation of a
 // framework/platform/service/product developed and released or hosted under a unique product name
 type Extension struct {
@@ -90,6 +112,9 @@ type ExtensionService struct {
 	// Configuration entries (e.g. configuration values required to configure the client to access this
 	// service), expressed as set of key-value entries
 	Configuration map[string]string
+	// Optional health check run periodically against the service endpoints. The endpoints of services
+	// without a health check are not checked.
+	HealthCheck *ExtensionServiceHealthCheck
 	// The time when the service was registered
 	Created time.Time
 	// The time when the service was last updated
@@ -114,12 +139,45 @@ type ExtensionServiceEndpoint struct {
 	Type ExtensionServiceEndpointType
 	// Configuration entries (e.g. CA certificates), expressed as set of key-value entries
 	Configuration map[string]string
+	// The result of the last health check run against the endpoint, nil if it has not been checked
+	Status *ExtensionServiceEndpointStatus
 	// The time when the extension was registered
 	Created time.Time
 	// The time when the extension was last updated
 	Updated time.Time
 }
 
+// ExtensionServiceHealthCheck describes how the endpoints of an extension service are checked to determine
+// whether they are reachable
+type ExtensionServiceHealthCheck struct {
+	// Health check type - http/tcp
+	Type ExtensionServiceHealthCheckType
+	// Path appended to the endpoint URL for HTTP health checks
+	Path string
+	// HTTP status expected from HTTP health checks. If not set, any 2xx or 3xx status is accepted.
+	ExpectedStatus int
+	// Maximum duration of a health check. If not set, the default timeout of the prober is used.
+	Timeout time.Duration
+}
+
+// ExtensionServiceEndpointStatus is the result of the last health check run against an extension endpoint
+type ExtensionServiceEndpointStatus struct {
+	// Endpoint health - healthy/unhealthy
+	Health ExtensionServiceEndpointHealth
+	// The reason why the health check failed, for unhealthy endpoints
+	Message string
+	// The time it took to run the health check
+	Latency time.Duration
+	// The time when the health check was run
+	Checked time.Time
+}
+
+// IsUnhealthy returns true if the endpoint failed its last health check. Endpoints that have not been checked
+// are not considered unhealthy.
+func (ep *ExtensionServiceEndpoint) IsUnhealthy() bool {
+	return ep.Status != nil && ep.Status.Health == EEHUnhealthy
+}
+
 // ExtensionServiceCredentials is a group of configuration values that can be generally used to embed information
 // pertaining to the authentication and authorization features supported by a service. This descriptor allows
 // administrators and operators of 3rd party tools integrated with FuseML to configure different accounts
@@ -197,6 +255,9 @@ type ExtensionQuery struct {
 	// Match credentials allowed for a given project. CredentialsScope must be set to ECSUser
 	// or ECSProject for this to have effect
 	Project string
+	// Exclude the endpoints that failed their last health check. Endpoints that have not been checked
+	// are not excluded.
+	ExcludeUnhealthy bool
 }
 
 // Errors returned by the methods in the ExtensionRegistry and ExtensionStore interfaces
@@ -358,6 +419,12 @@ func (e *ErrExtensionServiceCredentialsNotFound) Error() string {
 		e.ExtensionID, e.ServiceID, e.CredentialsID)
 }
 
+// ExtensionSubscriber is an interface for objects interested in the changes made to the registered
+// extensions
+type ExtensionSubscriber interface {
+	OnExtensionChanged(ctx context.Context, extensionID string)
+}
+
 // ExtensionRegistry defines the public interface implemented by the extension registry
 type ExtensionRegistry interface {
 	// Register a new extension, with all participating services, endpoints and credentials
@@ -396,6 +463,8 @@ type ExtensionRegistry interface {
 	RemoveCredentials(ctx context.Context, extensionID, serviceID, credentialsID string) error
 	// Run a query on the extension registry to find one or more ways to access extensions matching given search parameters
 	GetExtensionAccessDescriptors(ctx context.Context, query *ExtensionQuery) ([]*ExtensionAccessDescriptor, error)
+	// Subscribe to the changes made to an extension, or to its services, endpoints and credentials
+	Subscribe(subscriber ExtensionSubscriber)
 }
 
 // ExtensionStore defines the interface required to store extensions.
@@ -428,6 +497,8 @@ type ExtensionStore interface {
 	ListExtensionServiceEndpoints(ctx context.Context, extensionID string, serviceID string) ([]*ExtensionServiceEndpoint, error)
 	// UpdateExtensionServiceEndpoint updates an endpoint belonging to an extension service.
 	UpdateExtensionServiceEndpoint(ctx context.Context, extensionID string, serviceID string, newEndpoint *ExtensionServiceEndpoint) error
+	// SetExtensionServiceEndpointStatus records the result of a health check run against an extension endpoint.
+	SetExtensionServiceEndpointStatus(ctx context.Context, extensionID string, serviceID string, endpointID string, status *ExtensionServiceEndpointStatus) error
 	// DeleteExtensionServiceEndpoint deletes an extension endpoint from an extension service.
 	DeleteExtensionServiceEndpoint(ctx context.Context, extensionID, serviceID, endpointID string) error
 	// AddExtensionServiceCredentials adds a new credential to an extension service.
@@ -605,6 +676,16 @@ func (e *Extension) UpdateServiceEndpoint(serviceID string, endpoint *ExtensionS
 	return service.UpdateEndpoint(endpoint)
 }
 
+// SetServiceEndpointStatus sets the health check status of an endpoint from a service.
+func (e *Extension) SetServiceEndpointStatus(serviceID string, endpointID string, status *ExtensionServiceEndpointStatus) error {
+	service, err := e.GetService(serviceID)
+	if err != nil {
+		return err
+	}
+
+	return service.SetEndpointStatus(endpointID, status)
+}
+
 // UpdateServiceCredentials updates a credential from a service.
 func (e *Extension) UpdateServiceCredentials(serviceID string, credentials *ExtensionServiceCredentials) error {
 	service, err := e.GetService(serviceID)
@@ -681,7 +762,7 @@ func (e *Extension) GetExtensionIfMatch(query *ExtensionQuery) *Extension {
 	extensionCopy := *e
 	if query.ServiceID != "" || query.ServiceResource != "" || query.ServiceCategory != "" ||
 		query.EndpointURL != "" || query.Type != nil || query.CredentialsID != "" || query.CredentialsScope != "" ||
-		query.User != "" || query.Project != "" {
+		query.User != "" || query.Project != "" || query.ExcludeUnhealthy {
 		services, err := e.FindServices(query)
 		if err != nil || len(services) == 0 {
 			return nil
@@ -901,11 +982,25 @@ func (es *ExtensionService) UpdateEndpoint(newEndpoint *ExtensionServiceEndpoint
 
 	newEndpoint.Created = endpoint.Created
 	newEndpoint.Updated = time.Now()
+	// the health check status is only set by SetEndpointStatus
+	newEndpoint.Status = endpoint.Status
 
 	es.Endpoints[endpoint.URL] = newEndpoint
 	return nil
 }
 
+// SetEndpointStatus sets the health check status of an endpoint in the service, without changing the time
+// when the endpoint was last updated.
+func (es *ExtensionService) SetEndpointStatus(endpointID string, status *ExtensionServiceEndpointStatus) error {
+	endpoint, err := es.GetEndpoint(endpointID)
+	if err != nil {
+		return err
+	}
+
+	endpoint.Status = status
+	return nil
+}
+
 // UpdateCredentials updates a credential in the service.
 func (es *ExtensionService) UpdateCredentials(newCredentials *ExtensionServiceCredentials) error {
 	credential, err := es.GetCredentials(newCredentials.ID)
@@ -950,6 +1045,9 @@ func (es *ExtensionService) FindEndpoints(extensionZone string, query *Extension
 		if query.EndpointURL != "" && query.EndpointURL != endpoint.URL {
 			return
 		}
+		if query.ExcludeUnhealthy && endpoint.IsUnhealthy() {
+			return
+		}
 		if query.Type != nil {
 			// match endpoint type, if supplied with the query
 			if *query.Type != endpoint.Type {
@@ -12,12 +12,47 @@ type Project struct {
 	Description string
 	// Users assigned to the project
 	Users []*User
+	// Labels associated with the project
+	Labels []string
+	// The workflow suggested for running the project codesets
+	DefaultWorkflow string
+	// Resources that the workflow runs of the project are allowed to use. Not limited if nil
+	Quota *ProjectQuota
 }
 
+// ProjectQuota describes the resources that the workflow runs of a project are allowed to use
+type ProjectQuota struct {
+	// Maximum number of workflow runs of the project executing at the same time. Not limited if zero
+	MaxConcurrentRuns int
+	// Maximum CPU requested by the workflow steps of the project running at the same time, as a Kubernetes
+	// quantity (e.g. "4" or "2500m"). Not limited if empty
+	CPU string
+	// Maximum memory requested by the workflow steps of the project running at the same time, as a Kubernetes
+	// quantity (e.g. "8Gi"). Not limited if empty
+	Memory string
+}
+
+// HasResourceCaps returns true if the quota limits the CPU or memory used by the project workflow runs
+func (q *ProjectQuota) HasResourceCaps() bool {
+	return q != nil && (q.CPU != "" || q.Memory != "")
+}
+
+// Roles of the users assigned to a project
+const (
+	// ProjectRoleOwner is the role of the users administering the project and its codesets
+	ProjectRoleOwner = "owner"
+	// ProjectRoleDeveloper is the role of the users pushing code to the project codesets
+	ProjectRoleDeveloper = "developer"
+	// ProjectRoleViewer is the role of the users with read-only access to the project codesets
+	ProjectRoleViewer = "viewer"
+)
+
 // User represents user assigned to the project
 type User struct {
 	Name  string
 	Email string
+	// The role of the user in the project: owner, developer or viewer
+	Role string
 }
 
 // ProjectStore is an interface to project stores
@@ -26,4 +61,65 @@ type ProjectStore interface {
 	GetAll(ctx context.Context) ([]*Project, error)
 	Delete(ctx context.Context, name string) error
 	Create(ctx context.Context, name, desc string) (*Project, error)
+	Update(ctx context.Context, project *Project) (*Project, error)
+	AddMember(ctx context.Context, project string, user *User) (*string, error)
+	RemoveMember(ctx context.Context, project, user string) error
+}
+
+// ProjectMetadata holds the project attributes that are kept by FuseML instead of the git provider
+type ProjectMetadata struct {
+	// The name of the Project
+	Project string
+	// Labels associated with the project
+	Labels []string
+	// The workflow suggested for running the project codesets
+	DefaultWorkflow string
+	// Resources that the workflow runs of the project are allowed to use
+	Quota *ProjectQuota
+}
+
+// ProjectMetadataStore is an interface to the stores keeping the project metadata
+type ProjectMetadataStore interface {
+	// Find returns the metadata of a project, or nil if the project has none
+	Find(ctx context.Context, project string) *ProjectMetadata
+	// Save adds or replaces the metadata of a project
+	Save(ctx context.Context, metadata *ProjectMetadata) error
+	// Delete removes the metadata of a project
+	Delete(ctx context.Context, project string) error
+}
+
+// ProjectManager performs the project operations that involve other FuseML resources
+type ProjectManager interface {
+	// DeleteProject deletes a project. When cascading, the codesets of the project and the resources depending on
+	// them are deleted first. In dry-run mode nothing is deleted and the returned result lists what would be removed
+	DeleteProject(ctx context.Context, name string, cascade, dryRun bool) (*ProjectDeletion, error)
+	// UpdateProject updates a project, applying its quota to the workflow runs of the project
+	UpdateProject(ctx context.Context, project *Project) (*Project, error)
+}
+
+// ProjectDeletion lists the resources removed, or that would be removed, when deleting a project
+type ProjectDeletion struct {
+	// The name of the deleted Project
+	Project string
+	// True if nothing was actually deleted
+	DryRun bool
+	// The workflow assignments removed from the project codesets
+	Assignments []*ProjectDeletionAssignment
+	// The names of the project codesets
+	Codesets []string
+	// The names of the applications created by workflow runs of the project codesets
+	Applications []string
+	// The names of the applications that do not record the codeset project they were created from, which are
+	// kept even if they were created from the project codesets
+	UnknownApplications []string
+	// The names of the project members that do not belong to any other project
+	Users []string
+}
+
+// ProjectDeletionAssignment is a workflow assignment removed when deleting a project
+type ProjectDeletionAssignment struct {
+	// The name of the assigned workflow
+	Workflow string
+	// The name of the codeset the workflow is assigned to
+	Codeset string
 }
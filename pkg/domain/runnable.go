@@ -2,13 +2,30 @@ package domain
 
 import (
 	"context"
+	"regexp"
 	"time"
 )
 
+const (
+	// ErrRunnableExists describes the error message returned when trying to register a runnable with an ID that
+	// is already in use.
+	ErrRunnableExists = RunnableErr("a runnable with that ID already exists")
+	// ErrRunnableNotFound describes the error message returned when trying to get a runnable that does not exist.
+	ErrRunnableNotFound = RunnableErr("could not find a runnable with the specified ID")
+	// ErrRunnableVersionNotFound describes the error message returned when trying to get a runnable version that
+	// does not exist.
+	ErrRunnableVersionNotFound = RunnableErr("could not find a runnable with the specified version")
+)
+
+// RunnableErr are expected errors returned from the RunnableStore
+type RunnableErr string
+
 const (
 	// LocalRegistryHostname - Container image location values may use this identifier as a hostname to indicate
 	// that they are stored internally in the local OCI registry managed by fuseml
 	LocalRegistryHostname = "fuseml.local"
+	// RegistryHostname is the hostname of the FuseML built-in OCI registry, as used by the workflow steps
+	RegistryHostname = "registry.fuseml-registry"
 )
 
 // RunnableKind encodes valid values that can be assigned to the Runnable.Kind field
@@ -58,6 +75,10 @@ type Runnable struct {
 	ID string
 	// The runnable's creation time
 	Created time.Time
+	// The runnable version, starting at 1 and incremented every time the runnable is updated
+	Version int
+	// The time when this version of the runnable was created
+	Updated time.Time
 	// Optional description
 	Description string
 	// The author
@@ -258,9 +279,57 @@ type RunnableOutputRunnable struct {
 	RunnableRunnableArtifact
 }
 
+// Matches returns whether the runnable matches the supplied id, kind and labels query. Runnables may be
+// matched by id, kind or labels, only runnables that match all the supplied criteria are a match. Query
+// values are matched exactly or, failing that, as regular expressions.
+func (r *Runnable) Matches(id string, kind string, labels map[string]string) bool {
+	// match ID query
+	if id != "" && r.ID != id {
+		// try matching as a regexp
+		if match, _ := regexp.MatchString(id, r.ID); !match {
+			return false
+		}
+	}
+	// match kind query
+	if kind != "" && r.Kind != kind {
+		// try matching as a regexp
+		if match, _ := regexp.MatchString(kind, r.Kind); !match {
+			return false
+		}
+	}
+	// match label query
+	for qLabelKey, qLabelValue := range labels {
+		rLabelValue, hasLabel := r.Labels[qLabelKey]
+		if !hasLabel {
+			// runnable label key doesn't match query
+			return false
+		}
+		if qLabelValue == "" || qLabelValue == rLabelValue {
+			// empty query label value or exact match
+			continue
+		}
+		// try matching as a regexp
+		if match, _ := regexp.MatchString(qLabelValue, rLabelValue); !match {
+			// runnable label value doesn't match query label regexp
+			return false
+		}
+	}
+	return true
+}
+
+func (e RunnableErr) Error() string {
+	return string(e)
+}
+
 // RunnableStore defines the public interface that needs to be implemented by all runnable stores
 type RunnableStore interface {
 	Find(ctx context.Context, id string, kind string, labels map[string]string) (res []*Runnable, err error)
 	Register(ctx context.Context, r *Runnable) (res *Runnable, err error)
 	Get(ctx context.Context, name string) (res *Runnable, err error)
+	// GetVersion returns a specific version of a runnable, previous versions are kept when a runnable is updated
+	GetVersion(ctx context.Context, name string, version int) (res *Runnable, err error)
+	// Update replaces a runnable with a new version
+	Update(ctx context.Context, r *Runnable) (res *Runnable, err error)
+	// Delete removes a runnable and all its versions
+	Delete(ctx context.Context, name string) error
 }
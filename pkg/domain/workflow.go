@@ -3,7 +3,11 @@ package domain
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
+
+	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
 const (
@@ -16,6 +20,31 @@ const (
 	ErrWorkflowNotAssignedToCodeset = WorkflowErr("workflow not assigned to codeset")
 	// ErrCannotDeleteAssignedWorkflow describes the error message returned when trying to delete a workflow that is assigned to a codeset.
 	ErrCannotDeleteAssignedWorkflow = WorkflowErr("cannot delete workflow, there are codesets assigned to it")
+	// ErrInvalidAssignmentFilter describes the error message returned when trying to assign a workflow to a codeset with an invalid filter.
+	ErrInvalidAssignmentFilter = WorkflowErr("invalid codeset assignment filter")
+	// ErrWorkflowInputNotFound describes the error message returned when trying to set a value for an input that
+	// the workflow does not have.
+	ErrWorkflowInputNotFound = WorkflowErr("workflow does not have an input with the specified name")
+	// ErrWorkflowRunNotFound describes the error message returned when trying to get a workflow run that does not exist.
+	ErrWorkflowRunNotFound = WorkflowErr("could not find a workflow run with the specified name")
+	// ErrWorkflowRunCompleted describes the error message returned when trying to cancel a workflow run that has
+	// already completed.
+	ErrWorkflowRunCompleted = WorkflowErr("workflow run has already completed")
+	// ErrWorkflowRunNotCompleted describes the error message returned when trying to retry a workflow run that has
+	// not completed yet.
+	ErrWorkflowRunNotCompleted = WorkflowErr("workflow run has not completed yet")
+	// ErrWorkflowRunStepNotFound describes the error message returned when trying to get the logs from a step
+	// that does not exist in the workflow run.
+	ErrWorkflowRunStepNotFound = WorkflowErr("workflow run does not have a step with the specified name")
+	// ErrWorkflowRunLogsNotFound describes the error message returned when trying to get the logs from a workflow
+	// run that has not logged anything yet.
+	ErrWorkflowRunLogsNotFound = WorkflowErr("no logs available for the workflow run")
+	// ErrInvalidWorkflow describes the error message returned when trying to create a workflow with a definition
+	// that does not pass validation.
+	ErrInvalidWorkflow = WorkflowErr("invalid workflow")
+	// ErrWorkflowCredentialsNotFound describes the error message returned when trying to run a workflow for a
+	// codeset project, or user, that is not allowed to use any of the credentials required by its extensions.
+	ErrWorkflowCredentialsNotFound = WorkflowErr("no extension credentials available for the codeset project and user")
 )
 
 const (
@@ -86,6 +115,25 @@ type WorkflowStep struct {
 	Env []*WorkflowStepEnv
 	// Resources specify the resources requests and limits for the step.
 	Resources WorkflowStepResources
+	// Runnable is the reference to the registered runnable executed by the step.
+	Runnable *WorkflowStepRunnable
+	// Entrypoint is the entrypoint of the container running the step.
+	Entrypoint string
+	// Args is the list of arguments passed to the entrypoint.
+	Args []string
+}
+
+// WorkflowStepRunnable represents the reference to the runnable executed by a FuseML workflow step. The
+// runnable is referenced either explicitly by ID or by a kind and labels query.
+type WorkflowStepRunnable struct {
+	// ID is the ID of the runnable.
+	ID string
+	// Version is the version of the runnable, the latest version is used when not set.
+	Version int
+	// Kind is the kind of the runnable.
+	Kind string
+	// Labels is the labels query used to find the runnable.
+	Labels map[string]string
 }
 
 // WorkflowStepInput represents a input for a FuseML workflow step.
@@ -167,6 +215,10 @@ type WorkflowRun struct {
 	Name string
 	// WorkflowRef is the reference to the workflow.
 	WorkflowRef string
+	// CodesetProject is the project of the codeset the workflow run was created for.
+	CodesetProject string
+	// User is the user triggering the workflow run, empty if the run was not triggered by a user.
+	User string
 	// Inputs is the list of workflow inputs used on a run.
 	Inputs []*WorkflowRunInput
 	// Outputs is the list of workflow outputs from a run.
@@ -179,6 +231,79 @@ type WorkflowRun struct {
 	Status string
 	// URL is the URL to the workflow run.
 	URL string
+	// Steps is the list of steps executed by the workflow run.
+	Steps []*WorkflowRunStep
+	// Extensions is the list of extensions the workflow steps were bound to when the run was created.
+	Extensions []*WorkflowRunExtension
+}
+
+// WorkflowRunExtension records the extension a workflow step was bound to for a workflow run.
+type WorkflowRunExtension struct {
+	// StepName is the name of the workflow step.
+	StepName string
+	// Name is the name of the step extension requirement.
+	Name string
+	// ExtensionID is the ID of the extension the requirement was resolved to.
+	ExtensionID string
+	// ServiceID is the ID of the extension service the requirement was resolved to.
+	ServiceID string
+	// EndpointURL is the URL of the extension endpoint used by the step.
+	EndpointURL string
+	// CredentialsID is the ID of the extension credentials used by the step, empty if the step does not use
+	// credentials or they are not known when the run is created.
+	CredentialsID string
+}
+
+// WorkflowRunStep represents the state of a step executed by a FuseML workflow run.
+type WorkflowRunStep struct {
+	// Name is the name of the step.
+	Name string
+	// Status is the status of the step.
+	Status string
+	// StartTime is the time the step started.
+	StartTime time.Time
+	// CompletionTime is the time the step completed.
+	CompletionTime time.Time
+	// Retries is the number of times the step has been retried.
+	Retries int
+	// Image is the image used to execute the step.
+	Image string
+	// Results is a map of the results produced by the step and their values.
+	Results map[string]string
+}
+
+// WorkflowRunLog represents a line logged by a step of a FuseML workflow run.
+type WorkflowRunLog struct {
+	// Step is the name of the step.
+	Step string
+	// Container is the name of the step container that logged the line.
+	Container string
+	// Line is the logged line.
+	Line string
+}
+
+// WorkflowRunLogOptions defines the options available when streaming the logs from a workflow run.
+type WorkflowRunLogOptions struct {
+	// Step is the name of the step to stream the logs from, when empty the logs from all steps are streamed.
+	Step string
+	// Follow is whether to keep streaming the logs until the workflow run completes.
+	Follow bool
+}
+
+// WorkflowRunLogHandler is called for every line logged by a workflow run step. Returning an error stops
+// streaming the logs.
+type WorkflowRunLogHandler func(log *WorkflowRunLog) error
+
+// WorkflowRunOptions defines the values that can be customized when creating a workflow run.
+type WorkflowRunOptions struct {
+	// CodesetRevision is the codeset revision (branch, tag or commit) used by the run.
+	CodesetRevision string
+	// Inputs is a map of workflow input names and the values overriding their defaults.
+	Inputs map[string]string
+	// User is the user triggering the run, whose user scoped extension credentials are used by the run.
+	User string
+	// Extensions is the list of extensions the workflow steps are bound to, recorded on the workflow run.
+	Extensions []*WorkflowRunExtension
 }
 
 // WorkflowRunInput represents a input from a FuseML workflow run.
@@ -211,6 +336,41 @@ type WorkflowAssignmentStatus struct {
 	URL string
 }
 
+// WorkflowReconciliation lists the inconsistencies found between the workflow assignments, the codeset webhooks
+// and the workflow listeners, e.g. because FuseML was restarted or stopped while they were being changed.
+type WorkflowReconciliation struct {
+	// Cleanup is whether the inconsistencies found were removed.
+	Cleanup bool
+	// Subscriptions is the number of codesets whose deletion is watched for the workflow assignments.
+	Subscriptions int
+	// StaleAssignments are the workflow assignments to codesets that no longer exist.
+	StaleAssignments []*StaleWorkflowAssignment
+	// OrphanedWebhooks are the codeset webhooks sending events to a workflow listener without a matching
+	// workflow assignment.
+	OrphanedWebhooks []*OrphanedCodesetWebhook
+	// OrphanedListeners are the names of the workflow listeners of workflows not assigned to any codeset.
+	OrphanedListeners []string
+}
+
+// StaleWorkflowAssignment is a workflow assignment to a codeset that no longer exists.
+type StaleWorkflowAssignment struct {
+	// Workflow is the name of the assigned workflow.
+	Workflow string
+	// Codeset is the codeset the workflow was assigned to.
+	Codeset *Codeset
+}
+
+// OrphanedCodesetWebhook is a codeset webhook sending events to a workflow listener without a matching workflow
+// assignment.
+type OrphanedCodesetWebhook struct {
+	// Codeset is the codeset the webhook belongs to.
+	Codeset *Codeset
+	// Webhook is the orphaned webhook.
+	Webhook *CodesetWebhook
+	// Listener is the name of the workflow listener the webhook sends events to.
+	Listener string
+}
+
 // WorkflowRunFilter defines the available filter when listing workflow runs.
 type WorkflowRunFilter struct {
 	// WorkflowName is the name of the workflow to filter by.
@@ -219,6 +379,8 @@ type WorkflowRunFilter struct {
 	CodesetName string
 	// CodesetProject is the name of the codeset project to filter by.
 	CodesetProject string
+	// CodesetVersion is the version (git revision) of the codeset to filter by.
+	CodesetVersion string
 	// Status is the status of the workflow run to filter by.
 	Status []string
 }
@@ -241,15 +403,64 @@ type CodesetAssignment struct {
 	Codeset *Codeset
 	// WebhookID is the ID of the webhook that is used by the workflow assignment.
 	WebhookID *int64
+	// Filter restricts the codeset changes that trigger the workflow, nil if every change triggers it.
+	Filter *CodesetAssignmentFilter
+}
+
+// CodesetAssignmentFilter restricts the codeset changes that trigger a workflow assigned to a codeset.
+// Patterns are globs where "*" matches any sequence of characters except "/" and "**" matches any sequence of
+// characters.
+type CodesetAssignmentFilter struct {
+	// Branches is the list of patterns matching the names of the branches that trigger the workflow.
+	Branches []string
+	// Tags is the list of patterns matching the names of the tags that trigger the workflow.
+	Tags []string
+	// Paths is the list of patterns matching the codeset files that trigger the workflow when changed.
+	Paths []string
+}
+
+// IsEmpty checks whether the filter does not restrict any codeset change.
+func (f *CodesetAssignmentFilter) IsEmpty() bool {
+	return f == nil || (len(f.Branches) == 0 && len(f.Tags) == 0 && len(f.Paths) == 0)
+}
+
+// MatchesBranch checks whether a change pushed to a branch, regardless of the files changed, passes the filter.
+// When the filter only has tag patterns, no branch passes it.
+func (f *CodesetAssignmentFilter) MatchesBranch(branch string) bool {
+	if f == nil || (len(f.Branches) == 0 && len(f.Tags) == 0) {
+		return true
+	}
+	for _, pattern := range f.Branches {
+		if regexp.MustCompile(util.GlobToRegexp(pattern)).MatchString(branch) {
+			return true
+		}
+	}
+	return false
 }
 
 // WorkflowErr are expected errors returned when performing operations on workflows,
 type WorkflowErr string
 
+// WorkflowValidationError describes a problem found when validating a workflow definition.
+type WorkflowValidationError struct {
+	// Field is the path to the workflow field with the problem (e.g. "steps[1].inputs[0].value").
+	Field string
+	// Message describes the problem.
+	Message string
+}
+
+// WorkflowValidationErrors is the list of problems found when validating a workflow definition, returned as an
+// error when trying to create an invalid workflow.
+type WorkflowValidationErrors []*WorkflowValidationError
+
 // WorkflowManager describes the interface for a Workflow Manager
 type WorkflowManager interface {
 	// CreateWorkflow creates a new workflow.
 	CreateWorkflow(ctx context.Context, workflow *Workflow) (*Workflow, error)
+	// ValidateWorkflow validates a workflow definition, returning the list of problems found.
+	ValidateWorkflow(ctx context.Context, workflow *Workflow) (WorkflowValidationErrors, error)
+	// RenderWorkflow returns the objects that the workflow backend creates for a workflow, without creating them.
+	RenderWorkflow(ctx context.Context, workflow *Workflow) (string, error)
 	// GetWorkflow retrieves a workflow.
 	GetWorkflow(ctx context.Context, name string) (*Workflow, error)
 	// GetWorkflows returns a list of workflows.
@@ -257,29 +468,49 @@ type WorkflowManager interface {
 	// DeleteWorkflow deletes a workflow.
 	DeleteWorkflow(ctx context.Context, name string) error
 	// AssignToCodeset assigns a workflow to a codeset.
-	AssignToCodeset(ctx context.Context, name, codesetProject, codesetName string) (*WorkflowListener, *int64, error)
+	AssignToCodeset(ctx context.Context, name, codesetProject, codesetName string, filter *CodesetAssignmentFilter) (*WorkflowListener, *int64, error)
 	// UnassignFromCodeset removes a workflow assignment from a codeset.
 	UnassignFromCodeset(ctx context.Context, name, codesetProject, codesetName string) error
 	// GetAllCodesetAssignments returns all the codeset assignments from all workflows, or a specific one.
 	GetAllCodesetAssignments(ctx context.Context, name *string) map[string][]*CodesetAssignment
 	// GetAssignmentStatus returns the status of a workflow assignment.
 	GetAssignmentStatus(ctx context.Context, name string) *WorkflowAssignmentStatus
+	// ReconcileAssignments restores the codeset subscriptions of the workflow assignments and finds the stale
+	// assignments, orphaned webhooks and orphaned listeners, removing them if cleanup is true.
+	ReconcileAssignments(ctx context.Context, cleanup bool) (*WorkflowReconciliation, error)
 	// GetWorkflowRuns returns all the workflow runs for a workflow.
 	GetWorkflowRuns(ctx context.Context, filter *WorkflowRunFilter) ([]*WorkflowRun, error)
+	// CreateWorkflowRun creates a new run of a workflow for a codeset, returning the workflow run name.
+	CreateWorkflowRun(ctx context.Context, name, codesetProject, codesetName string, options *WorkflowRunOptions) (string, error)
+	// GetWorkflowRun returns a workflow run, including the state of each of its steps.
+	GetWorkflowRun(ctx context.Context, runName string) (*WorkflowRun, error)
+	// StreamWorkflowRunLogs calls the handler for every line logged by the steps of a workflow run.
+	StreamWorkflowRunLogs(ctx context.Context, runName string, options *WorkflowRunLogOptions, handler WorkflowRunLogHandler) error
+	// CancelWorkflowRun cancels a running workflow run.
+	CancelWorkflowRun(ctx context.Context, runName string) error
+	// RetryWorkflowRun creates a new workflow run identical to a completed one, returning the new workflow run name.
+	RetryWorkflowRun(ctx context.Context, runName string) (string, error)
+	// DeleteWorkflowRun deletes a workflow run.
+	DeleteWorkflowRun(ctx context.Context, runName string) error
+	// ApplyProjectQuota limits the resources used by the workflow runs of a project, failing if the workflow
+	// backend cannot enforce the quota.
+	ApplyProjectQuota(ctx context.Context, project string, quota *ProjectQuota) error
 }
 
 // WorkflowStore is an interface for workflow stores.
 type WorkflowStore interface {
 	// AddWorkflow adds a workflow to the store.
 	AddWorkflow(ctx context.Context, w *Workflow) (*Workflow, error)
+	// UpdateWorkflow replaces a workflow in the store.
+	UpdateWorkflow(ctx context.Context, w *Workflow) (*Workflow, error)
 	// GetWorkflow returns a workflow.
 	GetWorkflow(ctx context.Context, name string) (*Workflow, error)
 	// ListWorkflows returns a list of workflows.
 	GetWorkflows(ctx context.Context, name *string) []*Workflow
 	// DeleteWorkflow deletes a workflow from the store.
 	DeleteWorkflow(ctx context.Context, name string) error
-	// AddCodesetAssignment adds a codeset assignment to the store.
-	AddCodesetAssignment(ctx context.Context, workflowName string, codeset *Codeset, webhook *int64) ([]*CodesetAssignment, error)
+	// AddCodesetAssignment adds a codeset assignment to the store, or updates the filter of an existing one.
+	AddCodesetAssignment(ctx context.Context, workflowName string, codeset *Codeset, webhook *int64, filter *CodesetAssignmentFilter) ([]*CodesetAssignment, error)
 	// GetCodesetAssignment returns the assignment for a workflow and a codeset.
 	GetCodesetAssignment(ctx context.Context, workflowName string, codeset *Codeset) (*CodesetAssignment, error)
 	// GetCodesetAssignments returns the codeset assignments for a workflow.
@@ -294,22 +525,53 @@ type WorkflowStore interface {
 type WorkflowBackend interface {
 	// CreateWorkflow creates a new workflow.
 	CreateWorkflow(ctx context.Context, workflow *Workflow) error
+	// UpdateWorkflow updates a workflow, e.g. after the extensions its steps are bound to have changed. The
+	// runs created before the update are not affected.
+	UpdateWorkflow(ctx context.Context, workflow *Workflow) error
+	// RenderWorkflow returns the objects created by CreateWorkflow for a workflow as YAML, without creating them.
+	RenderWorkflow(ctx context.Context, workflow *Workflow) (string, error)
 	// DeleteWorkflow deletes a workflow.
 	DeleteWorkflow(ctx context.Context, workflowName string) error
-	// CreateWorkflowRun creates a new workflow run.
-	CreateWorkflowRun(ctx context.Context, workflowName string, codeset *Codeset) error
+	// CreateWorkflowRun creates a new workflow run, returning its name.
+	CreateWorkflowRun(ctx context.Context, workflowName string, codeset *Codeset, options *WorkflowRunOptions) (string, error)
+	// SetWorkflowCredentials makes the extension credentials bound to the workflow steps available to the runs
+	// created for the codesets of a project and, if user is set, triggered by that user.
+	SetWorkflowCredentials(ctx context.Context, workflow *Workflow, project, user string) error
 	// GetWorkflowRuns returns a list of workflow runs.
 	GetWorkflowRuns(ctx context.Context, workflow *Workflow, filter *WorkflowRunFilter) ([]*WorkflowRun, error)
+	// GetWorkflowRun returns a workflow run, including the state of each of its steps. As the backend does not
+	// keep the workflow definition, the inputs and outputs of the returned workflow run are only identified by
+	// their name, or type in the case of codeset inputs.
+	GetWorkflowRun(ctx context.Context, runName string) (*WorkflowRun, error)
+	// StreamWorkflowRunLogs calls the handler for every line logged by the steps of a workflow run. When following
+	// the logs, it only returns after the workflow run completes or the context is done.
+	StreamWorkflowRunLogs(ctx context.Context, runName string, options *WorkflowRunLogOptions, handler WorkflowRunLogHandler) error
+	// CancelWorkflowRun cancels a running workflow run.
+	CancelWorkflowRun(ctx context.Context, runName string) error
+	// RetryWorkflowRun creates a new workflow run with the same parameters as a completed one, recording the
+	// extensions the workflow steps are bound to, returning its name.
+	RetryWorkflowRun(ctx context.Context, runName string, extensions []*WorkflowRunExtension) (string, error)
+	// DeleteWorkflowRun deletes a workflow run.
+	DeleteWorkflowRun(ctx context.Context, runName string) error
+	// ApplyProjectQuota limits the resources used by the workflow runs created for the codesets of a project,
+	// removing the limits if the quota is nil or has no resource caps.
+	ApplyProjectQuota(ctx context.Context, project string, quota *ProjectQuota) error
 	// CreateWorkflowListener creates a new workflow listener.
 	CreateWorkflowListener(ctx context.Context, workflowName string, timeout time.Duration) (*WorkflowListener, error)
 	// DeleteWorkflowListener deletes a workflow listener.
 	DeleteWorkflowListener(ctx context.Context, workflowName string) error
 	// GetWorkflowListener returns a workflow listener for a workflow.
 	GetWorkflowListener(ctx context.Context, workflowName string) (*WorkflowListener, error)
+	// GetWorkflowListeners returns the workflow listeners of all workflows.
+	GetWorkflowListeners(ctx context.Context) ([]*WorkflowListener, error)
+	// UpdateWorkflowListener configures the workflow listener to trigger the workflow only for the changes
+	// pushed to the assigned codesets that pass the assignment filters.
+	UpdateWorkflowListener(ctx context.Context, workflowName string, assignments []*CodesetAssignment) error
 }
 
-// AssignToCodeset assigns a workflow to a codeset.
-func (w *Workflow) AssignToCodeset(ctx context.Context, codeset *Codeset, webhookID *int64) error {
+// AssignToCodeset assigns a workflow to a codeset. If the workflow is already assigned to the codeset, only
+// the assignment filter is updated.
+func (w *Workflow) AssignToCodeset(ctx context.Context, codeset *Codeset, webhookID *int64, filter *CodesetAssignmentFilter) error {
 	if codeset == nil {
 		return fmt.Errorf("codeset is nil")
 	}
@@ -319,18 +581,19 @@ func (w *Workflow) AssignToCodeset(ctx context.Context, codeset *Codeset, webhoo
 	}
 
 	if w.AssignedTo.Codesets == nil {
-		w.AssignedTo.Codesets = []*CodesetAssignment{{Codeset: codeset, WebhookID: webhookID}}
+		w.AssignedTo.Codesets = []*CodesetAssignment{{Codeset: codeset, WebhookID: webhookID, Filter: filter}}
 		return nil
 	}
 
 	codesetAssignments := w.AssignedTo.Codesets
 	for _, assignment := range codesetAssignments {
 		if assignment.Codeset.Name == codeset.Name && assignment.Codeset.Project == codeset.Project {
+			assignment.Filter = filter
 			return nil
 		}
 	}
 
-	codesetAssignments = append(codesetAssignments, &CodesetAssignment{Codeset: codeset, WebhookID: webhookID})
+	codesetAssignments = append(codesetAssignments, &CodesetAssignment{Codeset: codeset, WebhookID: webhookID, Filter: filter})
 	w.AssignedTo.Codesets = codesetAssignments
 	return nil
 }
@@ -381,11 +644,89 @@ func (w *Workflow) GetCodesetAssignment(ctx context.Context, codeset *Codeset) (
 	return nil, ErrWorkflowNotAssignedToCodeset
 }
 
+// WithoutCredentials returns a copy of the workflow where the extension credentials the steps are resolved to
+// only keep their references, without their configuration (e.g. keys and passwords). The workflow itself is not
+// modified.
+func (w *Workflow) WithoutCredentials() *Workflow {
+	result := *w
+	if w.Steps == nil {
+		return &result
+	}
+	result.Steps = make([]*WorkflowStep, len(w.Steps))
+	for i, step := range w.Steps {
+		s := *step
+		if step.Extensions == nil {
+			result.Steps[i] = &s
+			continue
+		}
+		s.Extensions = make([]*WorkflowStepExtension, len(step.Extensions))
+		for j, extension := range step.Extensions {
+			e := *extension
+			if e.ExtensionAccess != nil && e.ExtensionAccess.Credentials != nil {
+				access := *e.ExtensionAccess
+				credentials := *access.Credentials
+				credentials.Configuration = nil
+				access.Credentials = &credentials
+				e.ExtensionAccess = &access
+			}
+			s.Extensions[j] = &e
+		}
+		result.Steps[i] = &s
+	}
+	return &result
+}
+
+// ExtensionReferences returns the extensions the workflow steps are bound to, or nil if no step references
+// an extension.
+func (w *Workflow) ExtensionReferences() []*WorkflowRunExtension {
+	var result []*WorkflowRunExtension
+	for _, step := range w.Steps {
+		for _, extension := range step.Extensions {
+			access := extension.ExtensionAccess
+			if access == nil {
+				continue
+			}
+			reference := &WorkflowRunExtension{
+				StepName:    step.Name,
+				Name:        extension.Name,
+				ExtensionID: access.Extension.ID,
+				ServiceID:   access.Service.ID,
+				EndpointURL: access.Endpoint.URL,
+			}
+			if access.Credentials != nil {
+				reference.CredentialsID = access.Credentials.ID
+			}
+			result = append(result, reference)
+		}
+	}
+	return result
+}
+
 // Error returns the error message
 func (e WorkflowErr) Error() string {
 	return string(e)
 }
 
+func (e *WorkflowValidationError) String() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+func (e WorkflowValidationErrors) Error() string {
+	problems := make([]string, len(e))
+	for i, err := range e {
+		problems[i] = err.String()
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidWorkflow, strings.Join(problems, "; "))
+}
+
+// Is reports whether the validation errors match the ErrInvalidWorkflow error.
+func (e WorkflowValidationErrors) Is(target error) bool {
+	return target == ErrInvalidWorkflow
+}
+
 // String returns the string representation of the Workflow Input/Output type
 func (t WorkflowIOType) String() string {
 	return string(t)
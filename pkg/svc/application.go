@@ -21,6 +21,12 @@ func appRestToDomain(ra *application.Application) (a *domain.Application, err er
 	if ra.Description != nil {
 		a.Description = *ra.Description
 	}
+	if ra.CodesetProject != nil {
+		a.CodesetProject = *ra.CodesetProject
+	}
+	if ra.CodesetName != nil {
+		a.CodesetName = *ra.CodesetName
+	}
 	for _, res := range ra.K8sResources {
 		a.K8sResources = append(a.K8sResources,
 			&domain.KubernetesResource{
@@ -43,6 +49,12 @@ func appDomainToRest(a *domain.Application) (ret *application.Application) {
 	if a.Description != "" {
 		ret.Description = &a.Description
 	}
+	if a.CodesetProject != "" {
+		ret.CodesetProject = &a.CodesetProject
+	}
+	if a.CodesetName != "" {
+		ret.CodesetName = &a.CodesetName
+	}
 	for _, res := range a.K8sResources {
 		ret.K8sResources = append(ret.K8sResources,
 			&application.KubernetesResource{
@@ -2,21 +2,28 @@ package svc
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/fuseml/fuseml-core/gen/codeset"
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
 // codeset service implementation.
 type codesetsrvc struct {
-	logger *log.Logger
-	store  domain.CodesetStore
+	logger   *log.Logger
+	store    domain.CodesetStore
+	registry domain.ExtensionRegistry
 }
 
 // NewCodesetService returns the codeset service implementation.
-func NewCodesetService(logger *log.Logger, store domain.CodesetStore) codeset.Service {
-	return &codesetsrvc{logger, store}
+func NewCodesetService(logger *log.Logger, store domain.CodesetStore, registry domain.ExtensionRegistry) codeset.Service {
+	return &codesetsrvc{logger, store, registry}
 }
 
 func codesetRestToDomain(restCodeset *codeset.Codeset) (res *domain.Codeset, err error) {
@@ -38,6 +45,16 @@ func codesetDomainToRest(c *domain.Codeset) (res *codeset.Codeset) {
 		Labels:      c.Labels,
 		URL:         &c.URL,
 	}
+	if c.Metadata != nil {
+		res.Metadata = &codeset.CodesetMetadata{
+			Commit:       c.Metadata.Commit,
+			Type:         c.Metadata.Type,
+			Format:       c.Metadata.Format,
+			Requirements: c.Metadata.Requirements,
+			Files:        c.Metadata.Files,
+			Labels:       c.Metadata.Labels,
+		}
+	}
 
 	return
 }
@@ -77,6 +94,91 @@ func (s *codesetsrvc) Register(ctx context.Context, p *codeset.RegisterPayload)
 	return &res, nil
 }
 
+// Register a Codeset and populate it with the contents of a remote git repository or of an uploaded archive.
+func (s *codesetsrvc) Import(ctx context.Context, p *codeset.ImportPayload) (*codeset.ImportResult, error) {
+	s.logger.Print("codeset.import")
+	c, err := codesetRestToDomain(&codeset.Codeset{
+		Name:        p.Name,
+		Project:     p.Project,
+		Description: p.Description,
+		Labels:      p.Labels,
+	})
+	if err != nil {
+		return nil, codeset.MakeBadRequest(err)
+	}
+	source := &domain.CodesetImportSource{
+		URL:           util.DerefString(p.URL),
+		Ref:           util.DerefString(p.Ref),
+		Archive:       p.Archive,
+		ArchiveFormat: p.ArchiveFormat,
+	}
+	if p.ExtensionID != nil || p.ServiceID != nil || p.CredentialsID != nil {
+		source.Credentials, err = s.importCredentials(ctx, p)
+		if err != nil {
+			s.logger.Print(err)
+			return nil, codeset.MakeBadRequest(err)
+		}
+	}
+	c, username, password, err := s.store.Import(ctx, c, source)
+	if err != nil {
+		s.logger.Print(err)
+		if errors.Is(err, domain.ErrInvalidCodesetImport) || errors.Is(err, domain.ErrCodesetImportUnsupported) {
+			return nil, codeset.MakeBadRequest(err)
+		}
+		return nil, err
+	}
+	res := codeset.ImportResult{
+		Codeset:  codesetDomainToRest(c),
+		Username: username,
+		Password: password,
+	}
+	return &res, nil
+}
+
+// importCredentials returns the configuration of the extension credentials referenced by an import,
+// if they are available to the codeset project
+func (s *codesetsrvc) importCredentials(ctx context.Context, p *codeset.ImportPayload) (map[string]string, error) {
+	if p.ExtensionID == nil || p.ServiceID == nil || p.CredentialsID == nil {
+		return nil, fmt.Errorf("the extension, service and credentials IDs are all required to reference credentials")
+	}
+	importURL, err := url.Parse(util.DerefString(p.URL))
+	if err != nil || importURL.Hostname() == "" {
+		return nil, fmt.Errorf("credentials can only be used to import a remote git repository")
+	}
+	service, err := s.registry.GetService(ctx, *p.ExtensionID, *p.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	// only hand the credentials to the host they were registered for
+	if !serviceHasEndpointHost(service, importURL.Hostname()) {
+		return nil, fmt.Errorf("the git repository host %q does not match any endpoint of extension service %q",
+			importURL.Hostname(), *p.ServiceID)
+	}
+	credentials, err := service.FindCredentials(&domain.ExtensionQuery{
+		CredentialsID:    *p.CredentialsID,
+		CredentialsScope: domain.ECSProject,
+		Project:          p.Project,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := credentials[*p.CredentialsID]; ok {
+		return c.Configuration, nil
+	}
+	return nil, fmt.Errorf("credentials %q not found or not available to project %q", *p.CredentialsID, p.Project)
+}
+
+// serviceHasEndpointHost returns true if one of the service endpoints is located on host
+func serviceHasEndpointHost(service *domain.ExtensionService, host string) bool {
+	for _, endpoint := range service.Endpoints {
+		u, err := url.Parse(endpoint.URL)
+		if err == nil && strings.EqualFold(u.Hostname(), host) {
+			return true
+		}
+	}
+	return false
+}
+
 // Retrieve an Codeset from FuseML.
 func (s *codesetsrvc) Get(ctx context.Context, p *codeset.GetPayload) (res *codeset.Codeset, err error) {
 	s.logger.Print("codeset.get")
@@ -87,7 +189,114 @@ func (s *codesetsrvc) Get(ctx context.Context, p *codeset.GetPayload) (res *code
 	return codesetDomainToRest(c), nil
 }
 
+// Detect the metadata of a Codeset from the contents of its most recent commit.
+func (s *codesetsrvc) Inspect(ctx context.Context, p *codeset.InspectPayload) (*codeset.Codeset, error) {
+	s.logger.Print("codeset.inspect")
+	if _, err := s.store.Find(ctx, p.Project, p.Name); err != nil {
+		s.logger.Print(err)
+		return nil, codeset.MakeNotFound(err)
+	}
+	c, err := s.store.Inspect(ctx, p.Project, p.Name)
+	if err != nil {
+		s.logger.Print(err)
+		return nil, codeset.MakeBadRequest(err)
+	}
+	return codesetDomainToRest(c), nil
+}
+
+// List the branches, tags and the most recent commits of a Codeset.
+func (s *codesetsrvc) Revisions(ctx context.Context, p *codeset.RevisionsPayload) (*codeset.CodesetRevisions, error) {
+	s.logger.Print("codeset.revisions")
+	if _, err := s.store.Find(ctx, p.Project, p.Name); err != nil {
+		s.logger.Print(err)
+		return nil, codeset.MakeNotFound(err)
+	}
+	revisions, err := s.store.GetRevisions(ctx, p.Project, p.Name, p.Commits)
+	if err != nil {
+		s.logger.Print(err)
+		return nil, err
+	}
+	return codesetRevisionsDomainToRest(revisions), nil
+}
+
+// List the files and directories found under a path of a Codeset, at a given revision.
+func (s *codesetsrvc) Files(ctx context.Context, p *codeset.FilesPayload) ([]*codeset.CodesetFile, error) {
+	s.logger.Print("codeset.files")
+	if _, err := s.store.Find(ctx, p.Project, p.Name); err != nil {
+		s.logger.Print(err)
+		return nil, codeset.MakeNotFound(err)
+	}
+	files, err := s.store.GetFiles(ctx, p.Project, p.Name, p.Ref, p.Path)
+	if err != nil {
+		s.logger.Print(err)
+		if errors.Is(err, domain.ErrCodesetFileNotFound) {
+			return nil, codeset.MakeNotFound(err)
+		}
+		return nil, err
+	}
+	res := make([]*codeset.CodesetFile, 0, len(files))
+	for _, f := range files {
+		file := &codeset.CodesetFile{Path: f.Path, Type: f.Type}
+		if f.Type == domain.CodesetFileTypeFile {
+			size := f.Size
+			file.Size = &size
+		}
+		res = append(res, file)
+	}
+	return res, nil
+}
+
+// Retrieve the contents of a Codeset file, at a given revision.
+func (s *codesetsrvc) Contents(ctx context.Context, p *codeset.ContentsPayload) (*codeset.CodesetFileContents, error) {
+	s.logger.Print("codeset.contents")
+	if _, err := s.store.Find(ctx, p.Project, p.Name); err != nil {
+		s.logger.Print(err)
+		return nil, codeset.MakeNotFound(err)
+	}
+	contents, err := s.store.GetFileContents(ctx, p.Project, p.Name, p.Ref, p.Path)
+	if err != nil {
+		s.logger.Print(err)
+		if errors.Is(err, domain.ErrCodesetFileNotFound) {
+			return nil, codeset.MakeNotFound(err)
+		}
+		if errors.Is(err, domain.ErrCodesetNotAFile) {
+			return nil, codeset.MakeBadRequest(err)
+		}
+		return nil, err
+	}
+	return &codeset.CodesetFileContents{Path: p.Path, Content: contents}, nil
+}
+
 func (s *codesetsrvc) Delete(ctx context.Context, p *codeset.DeletePayload) error {
 	s.logger.Print("codeset.delete")
 	return s.store.Delete(ctx, p.Project, p.Name)
 }
+
+func codesetRevisionsDomainToRest(r *domain.CodesetRevisions) *codeset.CodesetRevisions {
+	res := &codeset.CodesetRevisions{
+		Branches: codesetRefsDomainToRest(r.Branches),
+		Tags:     codesetRefsDomainToRest(r.Tags),
+		Commits:  make([]*codeset.CodesetCommit, 0, len(r.Commits)),
+	}
+	for _, c := range r.Commits {
+		commit := &codeset.CodesetCommit{
+			ID:      c.ID,
+			Author:  &c.Author,
+			Message: &c.Message,
+		}
+		if !c.Time.IsZero() {
+			t := c.Time.Format(time.RFC3339)
+			commit.Time = &t
+		}
+		res.Commits = append(res.Commits, commit)
+	}
+	return res
+}
+
+func codesetRefsDomainToRest(refs []*domain.CodesetRef) []*codeset.CodesetRef {
+	res := make([]*codeset.CodesetRef, 0, len(refs))
+	for _, r := range refs {
+		res = append(res, &codeset.CodesetRef{Name: r.Name, Commit: r.Commit})
+	}
+	return res
+}
@@ -48,6 +48,7 @@ func extensionServiceToDomain(service *extension.ExtensionService) (*domain.Exte
 		Description:   util.DerefString(service.Description),
 		AuthRequired:  util.DerefBool(service.AuthRequired),
 		Configuration: service.Configuration,
+		HealthCheck:   extensionHealthCheckToDomain(service.HealthCheck),
 	}
 	if service.Endpoints != nil {
 		err := setExtensionServiceEndpoints(svc, service.Endpoints)
@@ -64,6 +65,18 @@ func extensionServiceToDomain(service *extension.ExtensionService) (*domain.Exte
 	return svc, nil
 }
 
+func extensionHealthCheckToDomain(check *extension.ExtensionServiceHealthCheck) *domain.ExtensionServiceHealthCheck {
+	if check == nil {
+		return nil
+	}
+	return &domain.ExtensionServiceHealthCheck{
+		Type:           domain.ExtensionServiceHealthCheckType(check.Type),
+		Path:           util.DerefString(check.Path),
+		ExpectedStatus: util.DerefInt(check.ExpectedStatus),
+		Timeout:        time.Duration(util.DerefInt(check.TimeoutSeconds)) * time.Second,
+	}
+}
+
 func setExtensionServices(extension *domain.Extension, services []*extension.ExtensionService) error {
 	for _, service := range services {
 		svc, err := extensionServiceToDomain(service)
@@ -135,6 +148,8 @@ func extensionQueryToDomain(query *extension.ExtensionQuery) (result *domain.Ext
 		ServiceID:       query.ServiceID,
 		ServiceResource: query.ServiceResource,
 		ServiceCategory: query.ServiceCategory,
+		// unhealthy endpoints are only excluded on request, so that they can still be inspected
+		ExcludeUnhealthy: query.ExcludeUnhealthy,
 	}
 
 	return result
@@ -172,6 +187,7 @@ func extensionServiceToRest(extensionID string, service *domain.ExtensionService
 			Registered: service.Created.Format(time.RFC3339),
 			Updated:    service.Updated.Format(time.RFC3339),
 		},
+		HealthCheck: extensionHealthCheckToRest(service.HealthCheck),
 	}
 	if service.Endpoints != nil {
 		restSvc.Endpoints = extensionEndpointListToRest(extensionID, service.ID, service.Endpoints)
@@ -182,6 +198,18 @@ func extensionServiceToRest(extensionID string, service *domain.ExtensionService
 	return restSvc
 }
 
+func extensionHealthCheckToRest(check *domain.ExtensionServiceHealthCheck) *extension.ExtensionServiceHealthCheck {
+	if check == nil {
+		return nil
+	}
+	return &extension.ExtensionServiceHealthCheck{
+		Type:           string(check.Type),
+		Path:           util.RefString(check.Path),
+		ExpectedStatus: util.RefInt(check.ExpectedStatus),
+		TimeoutSeconds: util.RefInt(int(check.Timeout / time.Second)),
+	}
+}
+
 func extensionServiceListToRest(extensionID string, services map[string]*domain.ExtensionService) []*extension.ExtensionService {
 	restServices := []*extension.ExtensionService{}
 	for _, service := range services {
@@ -198,7 +226,20 @@ func extensionEndpointToRest(extensionID string, serviceID string, endpoint *dom
 		Type:          util.RefString(string(endpoint.Type)),
 		Configuration: endpoint.Configuration,
 		// TODO: Add registered and updated fields
-		Status: &extension.ExtensionEndpointStatus{},
+		Status: extensionEndpointStatusToRest(endpoint.Status),
+	}
+}
+
+func extensionEndpointStatusToRest(status *domain.ExtensionServiceEndpointStatus) *extension.ExtensionEndpointStatus {
+	if status == nil {
+		return &extension.ExtensionEndpointStatus{}
+	}
+	latency := status.Latency.Milliseconds()
+	return &extension.ExtensionEndpointStatus{
+		Health:    util.RefString(string(status.Health)),
+		Message:   util.RefString(status.Message),
+		LatencyMs: &latency,
+		Checked:   util.RefString(status.Checked.Format(time.RFC3339)),
 	}
 }
 
@@ -413,10 +454,14 @@ func (s *extensionRegistrySvc) UpdateService(ctx context.Context, req *extension
 		Configuration: svc.Configuration,
 		Endpoints:     svc.Endpoints,
 		Credentials:   svc.Credentials,
+		HealthCheck:   svc.HealthCheck,
 	}
 	if req.Configuration != nil {
 		svcUpdate.Configuration = req.Configuration
 	}
+	if req.HealthCheck != nil {
+		svcUpdate.HealthCheck = service.HealthCheck
+	}
 	if req.Endpoints != nil {
 		err = setExtensionServiceEndpoints(&svcUpdate, req.Endpoints)
 		if err != nil {
@@ -493,6 +538,7 @@ func (s *extensionRegistrySvc) UpdateEndpoint(ctx context.Context, req *extensio
 		URL:           extensionEndpointURLToDomain(&endpoint.URL),
 		Type:          domain.ExtensionServiceEndpointType(util.DerefString(req.Type, string(ep.Type))),
 		Configuration: ep.Configuration,
+		Status:        ep.Status,
 	}
 	if req.Configuration != nil {
 		epUpdate.Configuration = req.Configuration
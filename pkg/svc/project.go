@@ -2,36 +2,50 @@ package svc
 
 import (
 	"context"
+	"errors"
 	"log"
 
 	"github.com/fuseml/fuseml-core/gen/project"
 	"github.com/fuseml/fuseml-core/pkg/domain"
+	"github.com/fuseml/fuseml-core/pkg/util"
 )
 
 // project service implementation.
 type projectsrvc struct {
-	logger *log.Logger
-	store  domain.ProjectStore
+	logger  *log.Logger
+	store   domain.ProjectStore
+	manager domain.ProjectManager
 }
 
 // NewProjectService returns the project service implementation.
-func NewProjectService(logger *log.Logger, store domain.ProjectStore) project.Service {
-	return &projectsrvc{logger, store}
+func NewProjectService(logger *log.Logger, store domain.ProjectStore, manager domain.ProjectManager) project.Service {
+	return &projectsrvc{logger, store, manager}
 }
 
 func projectDomainToRest(p *domain.Project) (res *project.Project) {
 	res = &project.Project{
-		Name:        p.Name,
-		Description: p.Description,
+		Name:            p.Name,
+		Description:     p.Description,
+		Labels:          p.Labels,
+		DefaultWorkflow: util.RefString(p.DefaultWorkflow),
+	}
+	if p.Quota != nil {
+		res.Quota = &project.ProjectQuota{
+			MaxConcurrentRuns: util.RefInt(p.Quota.MaxConcurrentRuns),
+			CPU:               util.RefString(p.Quota.CPU),
+			Memory:            util.RefString(p.Quota.Memory),
+		}
 	}
 
 	for _, u := range p.Users {
-		res.Users = append(res.Users,
-			&project.User{
-				Name:  u.Name,
-				Email: u.Email,
-			},
-		)
+		user := &project.User{
+			Name:  u.Name,
+			Email: u.Email,
+		}
+		if u.Role != "" {
+			user.Role = &u.Role
+		}
+		res.Users = append(res.Users, user)
 	}
 	return
 }
@@ -69,7 +83,87 @@ func (s *projectsrvc) Create(ctx context.Context, p *project.CreatePayload) (res
 	return projectDomainToRest(c), nil
 }
 
-func (s *projectsrvc) Delete(ctx context.Context, p *project.DeletePayload) error {
+// Update the description, labels, default workflow or quota of a Project.
+func (s *projectsrvc) Update(ctx context.Context, p *project.UpdatePayload) (*project.Project, error) {
+	s.logger.Print("project.update")
+	c, err := s.store.Find(ctx, p.Name)
+	if err != nil {
+		s.logger.Print(err)
+		return nil, project.MakeBadRequest(err)
+	}
+	if p.Description != nil {
+		c.Description = *p.Description
+	}
+	if p.Labels != nil {
+		c.Labels = p.Labels
+	}
+	if p.DefaultWorkflow != nil {
+		c.DefaultWorkflow = *p.DefaultWorkflow
+	}
+	if p.Quota != nil {
+		c.Quota = &domain.ProjectQuota{
+			MaxConcurrentRuns: util.DerefInt(p.Quota.MaxConcurrentRuns),
+			CPU:               util.DerefString(p.Quota.CPU),
+			Memory:            util.DerefString(p.Quota.Memory),
+		}
+		if *c.Quota == (domain.ProjectQuota{}) {
+			c.Quota = nil
+		}
+	}
+	c, err = s.manager.UpdateProject(ctx, c)
+	if err != nil {
+		s.logger.Print(err)
+		return nil, project.MakeBadRequest(err)
+	}
+	return projectDomainToRest(c), nil
+}
+
+// Delete a FuseML Project, optionally with its codesets and the resources depending on them.
+func (s *projectsrvc) Delete(ctx context.Context, p *project.DeletePayload) (*project.ProjectDeletion, error) {
 	s.logger.Print("project.delete")
-	return s.store.Delete(ctx, p.Name)
+	d, err := s.manager.DeleteProject(ctx, p.Name, p.Cascade, p.DryRun)
+	if err != nil {
+		s.logger.Print(err)
+		if errors.Is(err, domain.ErrProjectNotEmpty) {
+			return nil, project.MakeConflict(err)
+		}
+		return nil, project.MakeBadRequest(err)
+	}
+	res := &project.ProjectDeletion{
+		Project:             d.Project,
+		DryRun:              d.DryRun,
+		Assignments:         make([]*project.ProjectDeletionAssignment, 0, len(d.Assignments)),
+		Codesets:            d.Codesets,
+		Applications:        d.Applications,
+		Users:               d.Users,
+		UnknownApplications: d.UnknownApplications,
+	}
+	for _, a := range d.Assignments {
+		res.Assignments = append(res.Assignments, &project.ProjectDeletionAssignment{Workflow: a.Workflow, Codeset: a.Codeset})
+	}
+	return res, nil
+}
+
+// Add a user to a Project, or change the role of an existing Project member.
+func (s *projectsrvc) AddMember(ctx context.Context, p *project.AddMemberPayload) (*project.ProjectMemberCredentials, error) {
+	s.logger.Print("project.addMember")
+	password, err := s.store.AddMember(ctx, p.Name, &domain.User{Name: p.User, Email: p.Email, Role: p.Role})
+	if err != nil {
+		s.logger.Print(err)
+		return nil, project.MakeBadRequest(err)
+	}
+	return &project.ProjectMemberCredentials{Username: p.User, Password: password}, nil
+}
+
+// Remove a user from a Project.
+func (s *projectsrvc) RemoveMember(ctx context.Context, p *project.RemoveMemberPayload) error {
+	s.logger.Print("project.removeMember")
+	if err := s.store.RemoveMember(ctx, p.Name, p.User); err != nil {
+		s.logger.Print(err)
+		if errors.Is(err, domain.ErrProjectMemberNotFound) {
+			return project.MakeNotFound(err)
+		}
+		return project.MakeBadRequest(err)
+	}
+	return nil
 }
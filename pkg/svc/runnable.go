@@ -2,7 +2,6 @@ package svc
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -350,9 +349,13 @@ func runnableDomainToRest(r *domain.Runnable) (res *runnable.Runnable) {
 	}
 
 	created := r.Created.Format(time.RFC3339)
+	updated := r.Updated.Format(time.RFC3339)
+	version := r.Version
 	res = &runnable.Runnable{
 		ID:          r.ID,
 		Created:     &created,
+		Version:     &version,
+		Updated:     &updated,
 		Description: r.Description,
 		Author:      r.Author,
 		Source:      r.Source,
@@ -575,9 +578,50 @@ func (s *runnablesrvc) Register(ctx context.Context, p *runnable.Runnable) (res
 // Retrieve a Runnable from FuseML.
 func (s *runnablesrvc) Get(ctx context.Context, p *runnable.GetPayload) (res *runnable.Runnable, err error) {
 	s.logger.Print("runnable.get")
-	r, err := s.store.Get(ctx, p.ID)
-	if r == nil {
-		return nil, runnable.MakeNotFound(errors.New(err.Error()))
+	var r *domain.Runnable
+	if p.Version != nil {
+		r, err = s.store.GetVersion(ctx, p.ID, *p.Version)
+	} else {
+		r, err = s.store.Get(ctx, p.ID)
+	}
+	if err != nil {
+		s.logger.Print(err)
+		if err == domain.ErrRunnableNotFound || err == domain.ErrRunnableVersionNotFound {
+			return nil, runnable.MakeNotFound(err)
+		}
+		return nil, err
+	}
+	return runnableDomainToRest(r), nil
+}
+
+// Update a runnable registered in FuseML, creating a new version of the runnable.
+func (s *runnablesrvc) Update(ctx context.Context, p *runnable.Runnable) (res *runnable.Runnable, err error) {
+	s.logger.Print("runnable.update")
+	r, err := runnableRestToDomain(p)
+	if err != nil {
+		return nil, runnable.MakeBadRequest(err)
+	}
+	r, err = s.store.Update(ctx, r)
+	if err != nil {
+		s.logger.Print(err)
+		if err == domain.ErrRunnableNotFound {
+			return nil, runnable.MakeNotFound(err)
+		}
+		return nil, err
 	}
 	return runnableDomainToRest(r), nil
 }
+
+// Delete a runnable and all its versions from FuseML.
+func (s *runnablesrvc) Delete(ctx context.Context, p *runnable.DeletePayload) (err error) {
+	s.logger.Print("runnable.delete")
+	err = s.store.Delete(ctx, p.ID)
+	if err != nil {
+		s.logger.Print(err)
+		if err == domain.ErrRunnableNotFound {
+			return runnable.MakeNotFound(err)
+		}
+		return err
+	}
+	return nil
+}
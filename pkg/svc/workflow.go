@@ -2,6 +2,7 @@ package svc
 
 import (
 	"context"
+	"errors"
 	"log"
 	"strings"
 	"time"
@@ -42,11 +43,42 @@ func (s *workflowsrvc) Create(ctx context.Context, w *workflow.Workflow) (res *w
 		if err == domain.ErrWorkflowExists {
 			return nil, workflow.MakeConflict(err)
 		}
+		if errors.Is(err, domain.ErrInvalidWorkflow) {
+			return nil, workflow.MakeBadRequest(err)
+		}
 		return nil, err
 	}
 	return workflowDomainToRest(wf), nil
 }
 
+// Validate a Workflow definition, optionally rendering the objects created by the workflow backend for it.
+func (s *workflowsrvc) Validate(ctx context.Context, p *workflow.ValidatePayload) (res *workflow.WorkflowValidation, err error) {
+	s.logger.Print("workflow.validate")
+	wf := workflowRestToDomain(&workflow.Workflow{
+		Name:        p.Name,
+		Description: p.Description,
+		Inputs:      p.Inputs,
+		Outputs:     p.Outputs,
+		Steps:       p.Steps,
+	})
+	problems, err := s.mgr.ValidateWorkflow(ctx, wf)
+	if err != nil {
+		s.logger.Print(err)
+		return nil, err
+	}
+
+	res = &workflow.WorkflowValidation{Valid: len(problems) == 0, Errors: workflowValidationErrorsDomainToRest(problems)}
+	if res.Valid && p.Render {
+		rendered, err := s.mgr.RenderWorkflow(ctx, wf)
+		if err != nil {
+			s.logger.Print(err)
+			return nil, err
+		}
+		res.Rendered = &rendered
+	}
+	return res, nil
+}
+
 // Get a Workflow.
 func (s *workflowsrvc) Get(ctx context.Context, w *workflow.GetPayload) (res *workflow.Workflow, err error) {
 	s.logger.Print("workflow.get")
@@ -75,9 +107,13 @@ func (s *workflowsrvc) Delete(ctx context.Context, d *workflow.DeletePayload) (e
 // Assign a Workflow to a Codeset.
 func (s *workflowsrvc) Assign(ctx context.Context, w *workflow.AssignPayload) (err error) {
 	s.logger.Print("workflow.assign")
-	_, _, err = s.mgr.AssignToCodeset(ctx, w.Name, w.CodesetProject, w.CodesetName)
+	filter := &domain.CodesetAssignmentFilter{Branches: w.Branches, Tags: w.Tags, Paths: w.Paths}
+	_, _, err = s.mgr.AssignToCodeset(ctx, w.Name, w.CodesetProject, w.CodesetName, filter)
 	if err != nil {
 		s.logger.Print(err)
+		if errors.Is(err, domain.ErrInvalidAssignmentFilter) || errors.Is(err, domain.ErrWorkflowCredentialsNotFound) {
+			return workflow.MakeBadRequest(err)
+		}
 		// FIXME: codeset needs to thrown a known error when trying to get a codeset that does not exist
 		// to properly compare the returned error.
 		if err == domain.ErrWorkflowNotFound || strings.Contains(err.Error(), "Fetching Codeset failed") {
@@ -116,6 +152,34 @@ func (s *workflowsrvc) ListAssignments(ctx context.Context, w *workflow.ListAssi
 	return
 }
 
+// Find the Workflow assignments to deleted Codesets, the Codeset webhooks and the Workflow listeners left behind
+// by removed assignments, and optionally remove them.
+func (s *workflowsrvc) ReconcileAssignments(ctx context.Context, w *workflow.ReconcileAssignmentsPayload) (*workflow.WorkflowReconciliation, error) {
+	s.logger.Print("workflow.reconcileAssignments")
+	r, err := s.mgr.ReconcileAssignments(ctx, w.Cleanup)
+	if err != nil {
+		s.logger.Print(err)
+		return nil, err
+	}
+	res := &workflow.WorkflowReconciliation{
+		Cleanup:           r.Cleanup,
+		Subscriptions:     r.Subscriptions,
+		StaleAssignments:  make([]*workflow.StaleWorkflowAssignment, 0, len(r.StaleAssignments)),
+		OrphanedWebhooks:  make([]*workflow.OrphanedCodesetWebhook, 0, len(r.OrphanedWebhooks)),
+		OrphanedListeners: r.OrphanedListeners,
+	}
+	for _, a := range r.StaleAssignments {
+		res.StaleAssignments = append(res.StaleAssignments, &workflow.StaleWorkflowAssignment{
+			Workflow: a.Workflow, CodesetProject: a.Codeset.Project, CodesetName: a.Codeset.Name})
+	}
+	for _, h := range r.OrphanedWebhooks {
+		res.OrphanedWebhooks = append(res.OrphanedWebhooks, &workflow.OrphanedCodesetWebhook{
+			CodesetProject: h.Codeset.Project, CodesetName: h.Codeset.Name, ID: h.Webhook.ID, URL: h.Webhook.URL,
+			Listener: h.Listener})
+	}
+	return res, nil
+}
+
 // List Workflow runs.
 func (s *workflowsrvc) ListRuns(ctx context.Context, w *workflow.ListRunsPayload) ([]*workflow.WorkflowRun, error) {
 	s.logger.Print("workflow.listRuns")
@@ -126,6 +190,9 @@ func (s *workflowsrvc) ListRuns(ctx context.Context, w *workflow.ListRunsPayload
 	if w.CodesetProject != nil {
 		filter.CodesetProject = *w.CodesetProject
 	}
+	if w.CodesetVersion != nil {
+		filter.CodesetVersion = *w.CodesetVersion
+	}
 	if w.Status != nil {
 		filter.Status = []string{*w.Status}
 	}
@@ -136,6 +203,115 @@ func (s *workflowsrvc) ListRuns(ctx context.Context, w *workflow.ListRunsPayload
 	return workflowRunsDomainToRest(domainRuns), nil
 }
 
+// GetRun gets a Workflow run, including the status of each of its steps.
+func (s *workflowsrvc) GetRun(ctx context.Context, g *workflow.GetRunPayload) (*workflow.WorkflowRun, error) {
+	s.logger.Print("workflow.getRun")
+	run, err := s.mgr.GetWorkflowRun(ctx, g.Name)
+	if err != nil {
+		s.logger.Print(err)
+		if err == domain.ErrWorkflowRunNotFound {
+			return nil, workflow.MakeNotFound(err)
+		}
+		return nil, err
+	}
+	return workflowRunDomainToRest(run), nil
+}
+
+// Logs streams the logs from the steps of a Workflow run.
+func (s *workflowsrvc) Logs(ctx context.Context, l *workflow.LogsPayload, stream workflow.LogsServerStream) error {
+	s.logger.Print("workflow.logs")
+	options := domain.WorkflowRunLogOptions{Step: util.DerefString(l.Step), Follow: l.Follow}
+	err := s.mgr.StreamWorkflowRunLogs(ctx, l.Name, &options, func(log *domain.WorkflowRunLog) error {
+		return stream.Send(&workflow.WorkflowRunLog{Step: log.Step, Container: log.Container, Line: log.Line})
+	})
+	if err != nil {
+		s.logger.Print(err)
+		if err == domain.ErrWorkflowRunNotFound || err == domain.ErrWorkflowRunStepNotFound ||
+			err == domain.ErrWorkflowRunLogsNotFound {
+			return workflow.MakeNotFound(err)
+		}
+		return err
+	}
+	return stream.Close()
+}
+
+// Run starts a new Workflow run for a Codeset.
+func (s *workflowsrvc) Run(ctx context.Context, r *workflow.RunPayload) (*workflow.RunResult, error) {
+	s.logger.Print("workflow.run")
+	options := domain.WorkflowRunOptions{CodesetRevision: util.DerefString(r.CodesetRevision), Inputs: r.Inputs,
+		User: util.DerefString(r.User)}
+	runName, err := s.mgr.CreateWorkflowRun(ctx, r.Name, r.CodesetProject, r.CodesetName, &options)
+	if err != nil {
+		s.logger.Print(err)
+		if err == domain.ErrWorkflowNotFound || strings.Contains(err.Error(), "Fetching Codeset failed") {
+			return nil, workflow.MakeNotFound(err)
+		}
+		if errors.Is(err, domain.ErrWorkflowInputNotFound) || errors.Is(err, domain.ErrWorkflowCredentialsNotFound) {
+			return nil, workflow.MakeBadRequest(err)
+		}
+		if errors.Is(err, domain.ErrProjectQuotaExceeded) {
+			return nil, workflow.MakeQuotaExceeded(err)
+		}
+		if errors.Is(err, domain.ErrProjectQuotaUnsupported) {
+			return nil, workflow.MakeBadRequest(err)
+		}
+		return nil, err
+	}
+	return &workflow.RunResult{Name: runName}, nil
+}
+
+// CancelRun cancels a running Workflow run.
+func (s *workflowsrvc) CancelRun(ctx context.Context, c *workflow.CancelRunPayload) (err error) {
+	s.logger.Print("workflow.cancelRun")
+	err = s.mgr.CancelWorkflowRun(ctx, c.Name)
+	if err != nil {
+		s.logger.Print(err)
+		if err == domain.ErrWorkflowRunNotFound {
+			return workflow.MakeNotFound(err)
+		}
+		if err == domain.ErrWorkflowRunCompleted {
+			return workflow.MakeConflict(err)
+		}
+	}
+	return
+}
+
+// RetryRun creates a new Workflow run with the same inputs and codeset revision as a completed one.
+func (s *workflowsrvc) RetryRun(ctx context.Context, r *workflow.RetryRunPayload) (*workflow.RetryRunResult, error) {
+	s.logger.Print("workflow.retryRun")
+	runName, err := s.mgr.RetryWorkflowRun(ctx, r.Name)
+	if err != nil {
+		s.logger.Print(err)
+		if err == domain.ErrWorkflowRunNotFound {
+			return nil, workflow.MakeNotFound(err)
+		}
+		if err == domain.ErrWorkflowRunNotCompleted {
+			return nil, workflow.MakeConflict(err)
+		}
+		if errors.Is(err, domain.ErrProjectQuotaExceeded) {
+			return nil, workflow.MakeQuotaExceeded(err)
+		}
+		if errors.Is(err, domain.ErrProjectQuotaUnsupported) || errors.Is(err, domain.ErrWorkflowCredentialsNotFound) {
+			return nil, workflow.MakeBadRequest(err)
+		}
+		return nil, err
+	}
+	return &workflow.RetryRunResult{Name: runName}, nil
+}
+
+// DeleteRun deletes a Workflow run.
+func (s *workflowsrvc) DeleteRun(ctx context.Context, d *workflow.DeleteRunPayload) (err error) {
+	s.logger.Print("workflow.deleteRun")
+	err = s.mgr.DeleteWorkflowRun(ctx, d.Name)
+	if err != nil {
+		s.logger.Print(err)
+		if err == domain.ErrWorkflowRunNotFound {
+			return workflow.MakeNotFound(err)
+		}
+	}
+	return
+}
+
 func workflowRestToDomain(restWf *workflow.Workflow) *domain.Workflow {
 	wf := &domain.Workflow{
 		Name:        restWf.Name,
@@ -147,6 +323,14 @@ func workflowRestToDomain(restWf *workflow.Workflow) *domain.Workflow {
 	return wf
 }
 
+func workflowValidationErrorsDomainToRest(problems domain.WorkflowValidationErrors) []*workflow.WorkflowValidationError {
+	restErrors := make([]*workflow.WorkflowValidationError, len(problems))
+	for i, problem := range problems {
+		restErrors[i] = &workflow.WorkflowValidationError{Field: problem.Field, Message: problem.Message}
+	}
+	return restErrors
+}
+
 func workflowInputsRestToDomain(restInputs []*workflow.WorkflowInput) []*domain.WorkflowInput {
 	inputs := make([]*domain.WorkflowInput, len(restInputs))
 	for i, restInput := range restInputs {
@@ -178,17 +362,35 @@ func workflowStepsRestToDomain(restSteps []*workflow.WorkflowStep) []*domain.Wor
 	for i, restStep := range restSteps {
 		steps[i] = &domain.WorkflowStep{
 			Name:       restStep.Name,
-			Image:      restStep.Image,
+			Image:      util.DerefString(restStep.Image),
 			Inputs:     workflowStepInputsRestToDomain(restStep.Inputs),
 			Outputs:    workflowStepOutputsRestToDomain(restStep.Outputs),
 			Extensions: workflowStepExtensionsRestToDomain(restStep.Extensions),
 			Env:        workflowStepEnvsRestToDomain(restStep.Env),
 			Resources:  workflowStepResourcesRestToDomain(restStep.Resources),
+			Runnable:   workflowStepRunnableRestToDomain(restStep.Runnable),
+			Entrypoint: util.DerefString(restStep.Entrypoint),
+			Args:       restStep.Args,
 		}
 	}
 	return steps
 }
 
+func workflowStepRunnableRestToDomain(restRunnable *workflow.WorkflowStepRunnable) *domain.WorkflowStepRunnable {
+	if restRunnable == nil {
+		return nil
+	}
+	runnable := &domain.WorkflowStepRunnable{
+		ID:     util.DerefString(restRunnable.ID),
+		Kind:   util.DerefString(restRunnable.Kind),
+		Labels: restRunnable.Labels,
+	}
+	if restRunnable.Version != nil {
+		runnable.Version = *restRunnable.Version
+	}
+	return runnable
+}
+
 func workflowStepInputsRestToDomain(restStepInputs []*workflow.WorkflowStepInput) []*domain.WorkflowStepInput {
 	inputs := make([]*domain.WorkflowStepInput, len(restStepInputs))
 	for i, restStepInput := range restStepInputs {
@@ -314,17 +516,36 @@ func workflowStepsDomainToRest(domainSteps []*domain.WorkflowStep) []*workflow.W
 	for i, domainStep := range domainSteps {
 		restSteps[i] = &workflow.WorkflowStep{
 			Name:       domainStep.Name,
-			Image:      domainStep.Image,
+			Image:      util.RefString(domainStep.Image),
 			Inputs:     workflowStepInputsDomainToRest(domainStep.Inputs),
 			Outputs:    workflowStepOutputsDomainToRest(domainStep.Outputs),
 			Extensions: workflowStepExtensionsDomainToRest(domainStep.Extensions),
 			Env:        workflowStepEnvsDomainToRest(domainStep.Env),
 			Resources:  workflowStepResourcesDomainToRest(domainStep.Resources),
+			Runnable:   workflowStepRunnableDomainToRest(domainStep.Runnable),
+			Entrypoint: util.RefString(domainStep.Entrypoint),
+			Args:       domainStep.Args,
 		}
 	}
 	return restSteps
 }
 
+func workflowStepRunnableDomainToRest(domainRunnable *domain.WorkflowStepRunnable) *workflow.WorkflowStepRunnable {
+	if domainRunnable == nil {
+		return nil
+	}
+	runnable := &workflow.WorkflowStepRunnable{
+		ID:     util.RefString(domainRunnable.ID),
+		Kind:   util.RefString(domainRunnable.Kind),
+		Labels: domainRunnable.Labels,
+	}
+	if domainRunnable.Version > 0 {
+		version := domainRunnable.Version
+		runnable.Version = &version
+	}
+	return runnable
+}
+
 func workflowStepInputsDomainToRest(domainStepInputs []*domain.WorkflowStepInput) []*workflow.WorkflowStepInput {
 	restStepInputs := make([]*workflow.WorkflowStepInput, len(domainStepInputs))
 	for i, domainStepInput := range domainStepInputs {
@@ -410,12 +631,36 @@ func workflowStepResourcesDomainToRest(domainStepResources domain.WorkflowStepRe
 func workflowAssignmentDomainToRest(domainAssignment []*domain.CodesetAssignment, wfName string, wfAsgStatus *domain.WorkflowAssignmentStatus) *workflow.WorkflowAssignment {
 	restCodesets := make([]*workflow.Codeset, len(domainAssignment))
 	for i, domainCodeset := range domainAssignment {
-		restCodesets[i] = (*workflow.Codeset)(codesetDomainToRest(domainCodeset.Codeset))
+		restCodeset := codesetDomainToRest(domainCodeset.Codeset)
+		restCodesets[i] = &workflow.Codeset{
+			Name:        restCodeset.Name,
+			Project:     restCodeset.Project,
+			Description: restCodeset.Description,
+			Labels:      restCodeset.Labels,
+			URL:         restCodeset.URL,
+			Metadata:    (*workflow.CodesetMetadata)(restCodeset.Metadata),
+		}
+	}
+
+	var restFilters map[string]*workflow.CodesetAssignmentFilter
+	for _, a := range domainAssignment {
+		if a.Filter.IsEmpty() {
+			continue
+		}
+		if restFilters == nil {
+			restFilters = map[string]*workflow.CodesetAssignmentFilter{}
+		}
+		restFilters[a.Codeset.Project+"/"+a.Codeset.Name] = &workflow.CodesetAssignmentFilter{
+			Branches: a.Filter.Branches,
+			Tags:     a.Filter.Tags,
+			Paths:    a.Filter.Paths,
+		}
 	}
 
 	restAssignment := workflow.WorkflowAssignment{
-		Workflow: wfName,
-		Codesets: restCodesets,
+		Workflow:       wfName,
+		Codesets:       restCodesets,
+		CodesetFilters: restFilters,
 		Status: &workflow.WorkflowAssignmentStatus{
 			Available: wfAsgStatus.Available,
 			URL:       util.RefString(wfAsgStatus.URL),
@@ -442,7 +687,51 @@ func workflowRunDomainToRest(domainRun *domain.WorkflowRun) *workflow.WorkflowRu
 		CompletionTime: domainRun.CompletionTime.Format(time.RFC3339),
 		Status:         domainRun.Status,
 		URL:            util.RefString(domainRun.URL),
+		Steps:          workflowRunStepsDomainToRest(domainRun.Steps),
+		Extensions:     workflowRunExtensionsDomainToRest(domainRun.Extensions),
+	}
+}
+
+func workflowRunExtensionsDomainToRest(domainRunExtensions []*domain.WorkflowRunExtension) []*workflow.WorkflowRunExtension {
+	if len(domainRunExtensions) == 0 {
+		return nil
+	}
+	restRunExtensions := make([]*workflow.WorkflowRunExtension, len(domainRunExtensions))
+	for i, domainRunExtension := range domainRunExtensions {
+		restRunExtensions[i] = &workflow.WorkflowRunExtension{
+			Step:          domainRunExtension.StepName,
+			Name:          domainRunExtension.Name,
+			ExtensionID:   domainRunExtension.ExtensionID,
+			ServiceID:     domainRunExtension.ServiceID,
+			EndpointURL:   domainRunExtension.EndpointURL,
+			CredentialsID: util.RefString(domainRunExtension.CredentialsID),
+		}
+	}
+	return restRunExtensions
+}
+
+func workflowRunStepsDomainToRest(domainRunSteps []*domain.WorkflowRunStep) []*workflow.WorkflowRunStep {
+	if len(domainRunSteps) == 0 {
+		return nil
+	}
+	restRunSteps := make([]*workflow.WorkflowRunStep, len(domainRunSteps))
+	for i, domainRunStep := range domainRunSteps {
+		restRunStep := &workflow.WorkflowRunStep{
+			Name:    domainRunStep.Name,
+			Status:  domainRunStep.Status,
+			Retries: domainRunStep.Retries,
+			Image:   util.RefString(domainRunStep.Image),
+			Results: domainRunStep.Results,
+		}
+		if !domainRunStep.StartTime.IsZero() {
+			restRunStep.StartTime = util.RefString(domainRunStep.StartTime.Format(time.RFC3339))
+		}
+		if !domainRunStep.CompletionTime.IsZero() {
+			restRunStep.CompletionTime = util.RefString(domainRunStep.CompletionTime.Format(time.RFC3339))
+		}
+		restRunSteps[i] = restRunStep
 	}
+	return restRunSteps
 }
 
 func workflowRunInputsDomainToRest(domainRunInputs []*domain.WorkflowRunInput) []*workflow.WorkflowRunInput {
@@ -1,5 +1,19 @@
 package util
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+const dnsLabelMaxLength = 63
+
+var (
+	dnsLabelRegexp       = regexp.MustCompile("^[a-z0-9]([-a-z0-9]*[a-z0-9])?$")
+	dnsLabelInvalidChars = regexp.MustCompile("[^a-z0-9]+")
+)
+
 // StringInSlice verifies if a string slice contains a string value
 func StringInSlice(s string, slice []string) bool {
 	for _, v := range slice {
@@ -61,3 +75,71 @@ func DerefBool(s *bool, defaultValue ...bool) bool {
 	}
 	return db
 }
+
+// RefInt converts an int value into an int reference. The reference can also take
+// a nil value to indicate a default value
+func RefInt(i int, defaultValue ...int) *int {
+	di := 0
+	if len(defaultValue) > 0 {
+		di = defaultValue[0]
+	}
+	if i == di {
+		return nil
+	}
+	return &i
+}
+
+// DerefInt converts an int reference into an int value. If the reference is nil,
+// the default value is returned instead
+func DerefInt(i *int, defaultValue ...int) int {
+	di := 0
+	if len(defaultValue) > 0 {
+		di = defaultValue[0]
+	}
+	if i != nil {
+		return *i
+	}
+	return di
+}
+
+// GlobToRegexp converts a glob pattern into an anchored regular expression. In the pattern, "**" matches any
+// sequence of characters, "*" matches any sequence of characters except "/" and "?" matches any single character
+// except "/". Any other character is matched literally.
+func GlobToRegexp(pattern string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			sb.WriteString("[^/]*")
+		case pattern[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
+
+// DNSLabel converts a value into a DNS-1123 label, which can be used as part of a Kubernetes resource name.
+// Valid labels are returned unchanged, other values are suffixed with a hash of the original value, so that
+// different values are not converted to the same label.
+func DNSLabel(value string) string {
+	if len(value) <= dnsLabelMaxLength && dnsLabelRegexp.MatchString(value) {
+		return value
+	}
+	sum := sha256.Sum256([]byte(value))
+	hash := hex.EncodeToString(sum[:4])
+	label := strings.Trim(dnsLabelInvalidChars.ReplaceAllString(strings.ToLower(value), "-"), "-")
+	if maxLength := dnsLabelMaxLength - len(hash) - 1; len(label) > maxLength {
+		label = strings.TrimRight(label[:maxLength], "-")
+	}
+	if label == "" {
+		return hash
+	}
+	return label + "-" + hash
+}